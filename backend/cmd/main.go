@@ -2,27 +2,42 @@
 package main
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"encoding/pem"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"lutexplorer/internal/api"
 	"lutexplorer/internal/bgloader"
+	"lutexplorer/internal/datastore"
+	_ "lutexplorer/internal/extensions/examples" // sample analyzer, check and optimizer
+	"lutexplorer/internal/grpcapi"
+	"lutexplorer/internal/i18n"
+	"lutexplorer/internal/lgs"
+	"lutexplorer/internal/logbuf"
 	"lutexplorer/internal/lut"
+	"lutexplorer/internal/recent"
+	"lutexplorer/internal/scenario"
+	"lutexplorer/internal/selftest"
 	"lutexplorer/internal/watcher"
 	"lutexplorer/internal/ws"
 )
@@ -32,6 +47,10 @@ const (
 	keyFile  = "lutexplorer.key"
 )
 
+// shutdownTimeout bounds how long in-flight requests may run after SIGINT or
+// SIGTERM. It stays below the launcher's default stop grace period.
+const shutdownTimeout = 5 * time.Second
+
 // loadOrGenerateCert loads cached certificate or generates a new one
 func loadOrGenerateCert() (tls.Certificate, error) {
 	// Try to load existing certificate
@@ -96,15 +115,62 @@ func generateAndSaveCert() (tls.Certificate, error) {
 	}, nil
 }
 
+// splitPatterns splits a comma-separated list of globs, dropping empty entries.
+func splitPatterns(s string) []string {
+	patterns := []string{}
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
 func main() {
-	libraryPath := flag.String("library", "", "Path to library folder (required)")
+	// "lutexplorer selftest -library <path>" checks a library and exits
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		os.Exit(runSelfTest(os.Args[2:]))
+	}
+
+	// Keep recent log output for GET /api/logs
+	logBuffer := logbuf.New(logbuf.DefaultCapacity)
+	log.SetOutput(io.MultiWriter(os.Stderr, logBuffer))
+	libraryPath := flag.String("library", "", "Path to library folder (optional: a library can also be opened via POST /api/library/open)")
 	port := flag.Int("port", 7754, "Server port (HTTP)")
 	httpsPort := flag.Int("https-port", 7755, "HTTPS port (0 to disable)")
+	grpcPort := flag.Int("grpc-port", 0, "gRPC port for the LUT analysis API (0 to disable)")
 	convexURL := flag.String("convex-url", "", "URL of the Convex Optimizer Python service (e.g., http://localhost:7756)")
 	watch := flag.Bool("watch", false, "Enable auto-reload when CSV lookup tables change")
+	watchDebounce := flag.Duration("watch-debounce", watcher.DefaultOptions().Debounce, "Quiet period after the last file change before reloading")
+	watchIgnore := flag.String("watch-ignore", strings.Join(watcher.DefaultIgnorePatterns(), ","), "Comma-separated filename globs the watcher never reloads on")
+	watchPoll := flag.Bool("watch-poll", false, "Detect changes by polling file mtime and size (for NFS/SMB-mounted libraries)")
+	watchPollInterval := flag.Duration("watch-poll-interval", watcher.DefaultPollingInterval, "Polling interval when -watch-poll is set")
+	watchStable := flag.Duration("watch-stable", watcher.DefaultOptions().StableFor, "How long a changed file's size must stay unchanged before it is reloaded")
+	watchRTPGuard := flag.Float64("watch-rtp-guard", lut.DefaultRTPDriftThreshold, "Warn when a watcher reload moves a mode's RTP by more than this (0.005 = 0.5 points, 0 = off)")
 	autoloadBooks := flag.Bool("autoload-books", false, "Enable automatic loading of event books at startup (uses more memory)")
+	eventsStore := flag.Bool("events-disk-store", false, "Keep fully loaded event books in an indexed file on disk instead of memory")
+	eventsStoreDir := flag.String("events-store-dir", "", "Folder for -events-disk-store files (system temp folder if empty)")
+	eventsStoreCache := flag.Int("events-store-cache", lut.DefaultStoreCacheSize, "Number of hot events -events-disk-store keeps in memory per mode")
+	loadWorkers := flag.Int("load-workers", bgloader.DefaultWorkers, "Number of event books decoded in parallel in high priority mode")
+	syntheticEvents := flag.Bool("synthetic-events", false, "Serve generated payout-only events for modes without an events file")
+	localesDir := flag.String("locales", "", "Folder of <lang>.json UI string catalogs served at /api/i18n")
+	dataDir := flag.String("data-dir", "", "Folder of the SQLite database keeping LGS sessions, simulation results, optimizer/compliance history and the audit log across restarts")
+	sessionDB := flag.String("session-db", "", "SQLite file to persist LGS sessions across restarts (in memory only if empty)")
+	lgsPresets := flag.String("lgs-presets", "", "JSON file of LGS bias presets (adds to or replaces the built-in ones)")
+	lgsReplay := flag.String("lgs-replay", "", "Cassette file of recorded LGS traffic to serve on /wallet and /bet instead of the maths (for front-end CI)")
+	logFile := flag.String("log-file", "", "Also append log output to this file (for running as a service)")
+	scenarioFile := flag.String("scenario", "", "Run a YAML scenario script against the library, print its report and exit (non-zero if it fails)")
 	flag.Parse()
 
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Fatalf("Failed to open log file: %v", err)
+		}
+		defer f.Close()
+		log.SetOutput(io.MultiWriter(os.Stderr, logBuffer, f))
+	}
+
 	// Check environment variable for convex URL if not provided via flag
 	if *convexURL == "" {
 		if envURL := os.Getenv("CONVEX_OPTIMIZER_URL"); envURL != "" {
@@ -112,30 +178,33 @@ func main() {
 		}
 	}
 
-	if *libraryPath == "" {
-		fmt.Fprintln(os.Stderr, "Error: -library flag is required")
-		fmt.Fprintln(os.Stderr, "Usage: lutexplorer -library <path/to/library> [-port 7754] [-https-port 7755]")
-		os.Exit(1)
-	}
-
 	addr := fmt.Sprintf(":%d", *port)
 	httpsAddr := fmt.Sprintf(":%d", *httpsPort)
 
-	// Load index from library folder
-	loader := lut.NewLoaderFromLibrary(*libraryPath)
-	if err := loader.Load(); err != nil {
-		log.Fatalf("Failed to load index: %v", err)
-	}
+	// Load index from library folder, or start empty and wait for POST /api/library/open
+	var loader *lut.Loader
+	if *libraryPath != "" {
+		loader = lut.NewLoaderFromLibrary(*libraryPath)
+		if err := loader.Load(); err != nil {
+			log.Fatalf("Failed to load index: %v", err)
+		}
 
-	index := loader.GetIndex()
-	log.Printf("Loaded index: %d modes", len(index.Modes))
+		index := loader.GetIndex()
+		log.Printf("Loaded index: %d modes", len(index.Modes))
 
-	// Print mode summaries
-	for _, summary := range loader.GetModeSummaries() {
-		log.Printf("  Mode %q: %d outcomes, Cost=%.2f, RTP=%.4f%%, HitRate=%.2f%%, MaxPayout=%.0fx",
-			summary.Mode, summary.Outcomes, summary.Cost, summary.RTP*100, summary.HitRate*100, summary.MaxPayout)
+		// Print mode summaries
+		for _, summary := range loader.GetModeSummaries() {
+			log.Printf("  Mode %q: %d outcomes, Cost=%.2f, RTP=%.4f%%, HitRate=%.2f%%, MaxPayout=%.0fx",
+				summary.Mode, summary.Outcomes, summary.Cost, summary.RTP*100, summary.HitRate*100, summary.MaxPayout)
+		}
+	} else {
+		loader = lut.NewEmptyLoader()
+		log.Println("No library given: open one with POST /api/library/open {\"path\": \"...\"}")
 	}
 
+	loader.SetSyntheticEvents(*syntheticEvents)
+	loader.EventsLoader().SetDiskStore(*eventsStore, *eventsStoreDir, *eventsStoreCache)
+
 	// Create WebSocket hub
 	hub := ws.NewHub()
 	go hub.Run()
@@ -143,6 +212,7 @@ func main() {
 
 	// Create background loader
 	bgLoader := bgloader.NewBackgroundLoader(loader, hub)
+	bgLoader.SetWorkers(*loadWorkers)
 	if *autoloadBooks {
 		bgLoader.Start()
 		log.Println("Background loader started (low priority mode)")
@@ -152,16 +222,31 @@ func main() {
 		log.Println("  - Use -autoload-books to preload all events (high memory)")
 	}
 
-	// Create CSV watcher for auto-reload on file changes (optional)
-	var csvWatcher *watcher.FileWatcher
-	if *watch {
+	// Create CSV watcher for auto-reload on file changes (optional).
+	// It watches one library folder, so it is recreated when the library changes.
+	newCSVWatcher := func() *watcher.FileWatcher {
+		if !loader.IsOpen() {
+			return nil
+		}
 		csvFiles := loader.GetCSVFiles()
-		var watcherErr error
-		csvWatcher, watcherErr = watcher.NewFileWatcher(loader.BaseDir(), csvFiles, func(mode string) error {
+		csvWatcher, watcherErr := watcher.NewFileWatcher(loader.BaseDir(), csvFiles, func(mode string) error {
 			log.Printf("CSV file changed, reloading LUT for mode: %s", mode)
+			previous, _ := loader.GetMode(mode)
 			if reloadErr := loader.ReloadModeTable(mode); reloadErr != nil {
 				return reloadErr
 			}
+			// Catch pipeline bugs that silently shift the maths under testers' feet
+			if current, getErr := loader.GetMode(mode); previous != nil && getErr == nil {
+				if drift := lut.CompareReload(previous, current, *watchRTPGuard); drift.Exceeded {
+					log.Printf("Warning: RTP of mode %s moved %.4f%% -> %.4f%% on reload (threshold %.2f points)",
+						mode, drift.Before.RTP*100, drift.After.RTP*100, *watchRTPGuard*100)
+					hub.Broadcast(ws.Message{
+						Type:    ws.MsgLUTDriftWarning,
+						Mode:    mode,
+						Payload: drift,
+					})
+				}
+			}
 			// Broadcast to WebSocket clients
 			hub.Broadcast(ws.Message{
 				Type: ws.MsgLUTReloaded,
@@ -174,21 +259,139 @@ func main() {
 		})
 		if watcherErr != nil {
 			log.Printf("Warning: Failed to create CSV watcher: %v", watcherErr)
-		} else {
-			if startErr := csvWatcher.Start(); startErr != nil {
-				log.Printf("Warning: Failed to start CSV watcher: %v", startErr)
-			} else {
-				log.Println("CSV watcher started (auto-reload on lookup table changes)")
+			return nil
+		}
+		csvWatcher.SetOptions(watcher.Options{
+			Debounce:  *watchDebounce,
+			StableFor: *watchStable,
+			Ignore:    splitPatterns(*watchIgnore),
+		})
+		csvWatcher.SetPolling(*watchPoll, *watchPollInterval)
+
+		// Watch index.json so modes added to or removed from the library are picked up
+		csvWatcher.SetIndexHandler(filepath.Base(loader.IndexPath()), func() error {
+			added, removed, reloadErr := loader.ReloadIndex()
+			if reloadErr != nil {
+				return reloadErr
 			}
+			csvWatcher.SetFiles(loader.GetCSVFiles())
+			for _, mode := range removed {
+				log.Printf("Mode removed from index: %s", mode)
+				bgLoader.RemoveMode(mode)
+			}
+			for _, mode := range added {
+				log.Printf("Mode added to index: %s", mode)
+				if addErr := bgLoader.AddMode(mode); addErr != nil {
+					log.Printf("Warning: Failed to register mode %s: %v", mode, addErr)
+				}
+			}
+			hub.Broadcast(ws.Message{
+				Type: ws.MsgIndexReloaded,
+				Payload: map[string]any{
+					"added":   added,
+					"removed": removed,
+					"message": "Index reloaded",
+				},
+			})
+			return nil
+		})
+
+		if startErr := csvWatcher.Start(); startErr != nil {
+			log.Printf("Warning: Failed to start CSV watcher: %v", startErr)
+		} else {
+			log.Println("CSV watcher started (auto-reload on lookup table changes)")
 		}
+		return csvWatcher
+	}
+
+	var csvWatcher *watcher.FileWatcher
+	if *watch {
+		csvWatcher = newCSVWatcher()
 	} else {
 		log.Println("CSV watcher disabled (use --watch to enable)")
 	}
 
+	// fsnotify does not see writes made by other hosts on network mounts
+	warnNetworkMount := func() {
+		if isNetwork, fsType := watcher.IsNetworkMount(loader.BaseDir()); isNetwork && !*watchPoll {
+			log.Printf("Library is on a network filesystem (%s): use -watch -watch-poll for reliable auto-reload", fsType)
+		}
+	}
+	if loader.IsOpen() {
+		warnNetworkMount()
+	}
+
 	// Create and configure server
 	server := api.NewServer(loader, addr, hub, *convexURL)
 	server.SetBackgroundLoader(bgLoader)
 	server.SetCSVWatcher(csvWatcher)
+	server.SetRecentLibraries(recent.New(recent.DefaultPath()))
+	server.RecordLibrary()
+	var watcherMu sync.Mutex
+	server.SetLibraryChangedHook(func() {
+		watcherMu.Lock()
+		defer watcherMu.Unlock()
+		if csvWatcher != nil {
+			csvWatcher.Stop()
+			csvWatcher = nil
+		}
+		if loader.IsOpen() {
+			warnNetworkMount()
+			if *watch {
+				csvWatcher = newCSVWatcher()
+			}
+		}
+		server.SetCSVWatcher(csvWatcher)
+	})
+	server.SetLogBuffer(logBuffer)
+	if *dataDir != "" && *sessionDB != "" {
+		log.Fatalf("-data-dir already persists LGS sessions; drop -session-db")
+	}
+	if *dataDir != "" {
+		store, err := datastore.Open(*dataDir)
+		if err != nil {
+			log.Fatalf("Failed to open data directory: %v", err)
+		}
+		defer store.Close()
+		restored, err := server.SetDataStore(store)
+		if err != nil {
+			log.Fatalf("Failed to restore LGS sessions: %v", err)
+		}
+		version, _ := store.SchemaVersion()
+		log.Printf("Data stored in %s (schema v%d, %d LGS sessions restored)", store.Path(), version, restored)
+	}
+	if *sessionDB != "" {
+		db, err := lgs.OpenSessionDB(*sessionDB)
+		if err != nil {
+			log.Fatalf("Failed to open session database: %v", err)
+		}
+		defer db.Close()
+		restored, err := server.SetSessionDB(db)
+		if err != nil {
+			log.Fatalf("Failed to restore LGS sessions: %v", err)
+		}
+		log.Printf("LGS sessions persisted to %s (%d restored)", *sessionDB, restored)
+	}
+	if *lgsPresets != "" {
+		presets, err := lgs.LoadBiasPresets(*lgsPresets)
+		if err != nil {
+			log.Fatalf("Failed to load LGS presets: %v", err)
+		}
+		server.SetLGSPresets(presets)
+		log.Printf("Loaded %d LGS bias presets from %s", len(presets), *lgsPresets)
+	}
+	if *lgsReplay != "" {
+		cassette, err := lgs.LoadCassette(*lgsReplay)
+		if err != nil {
+			log.Fatalf("Failed to load LGS cassette: %v", err)
+		}
+		server.SetLGSReplay(cassette)
+		log.Printf("Replaying %d recorded LGS interactions from %s", len(cassette.Interactions), *lgsReplay)
+	}
+	if *localesDir != "" {
+		server.SetLocales(i18n.NewCatalog(*localesDir))
+		log.Printf("Serving UI translations from %s", *localesDir)
+	}
 
 	// Log convex optimizer status
 	if *convexURL != "" {
@@ -197,23 +400,32 @@ func main() {
 		log.Println("Convex Optimizer proxy disabled (no -convex-url or CONVEX_OPTIMIZER_URL)")
 	}
 
-	// Handle graceful shutdown
-	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		<-sigCh
-		log.Println("Shutting down...")
-		if csvWatcher != nil {
-			csvWatcher.Stop()
-		}
-		bgLoader.Stop()
-		os.Exit(0)
-	}()
+	// Start gRPC server if enabled
+	var grpcServer *grpcapi.Server
+	if *grpcPort > 0 {
+		grpcServer = grpcapi.NewServer(loader)
+		go func() {
+			if err := grpcServer.Start(fmt.Sprintf(":%d", *grpcPort)); err != nil {
+				log.Printf("gRPC server error: %v", err)
+			}
+		}()
+	}
 
 	// Get the HTTP handler
 	handler := server.GetHandler()
 
+	if *scenarioFile != "" {
+		os.Exit(runScenario(handler, *scenarioFile))
+	}
+
+	// Keep the LGS request log, printed with fmt, as well. Not before
+	// -scenario: its report must reach stdout before the process exits.
+	if err := logbuf.CaptureStdout(logBuffer); err != nil {
+		log.Printf("Warning: could not capture stdout for GET /api/logs: %v", err)
+	}
+
 	// Start HTTPS server if enabled
+	var httpsServer *http.Server
 	if *httpsPort > 0 {
 		cert, err := loadOrGenerateCert()
 		if err != nil {
@@ -224,7 +436,7 @@ func main() {
 			Certificates: []tls.Certificate{cert},
 		}
 
-		httpsServer := &http.Server{
+		httpsServer = &http.Server{
 			Addr:      httpsAddr,
 			Handler:   handler,
 			TLSConfig: tlsConfig,
@@ -239,6 +451,39 @@ func main() {
 		}()
 	}
 
+	// Handle graceful shutdown
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+		log.Println("Shutting down...")
+
+		// Let in-flight requests finish, then stop the background work. main
+		// returns afterwards so the data stores are closed by their defers.
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if httpsServer != nil {
+			if err := httpsServer.Shutdown(ctx); err != nil {
+				log.Printf("HTTPS server shutdown: %v", err)
+			}
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("HTTP server shutdown: %v", err)
+		}
+		watcherMu.Lock()
+		if csvWatcher != nil {
+			csvWatcher.Stop()
+		}
+		watcherMu.Unlock()
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		bgLoader.Stop()
+		loader.EventsLoader().UnloadAll() // removes disk store files
+	}()
+
 	// Start HTTP server
 	log.Printf("HTTP server listening on http://localhost%s", addr)
 	log.Printf("WebSocket available at ws://localhost%s/ws", addr)
@@ -246,7 +491,65 @@ func main() {
 	log.Printf("  GET  /api/loader/status  - Get loading status")
 	log.Printf("  POST /api/loader/boost   - Enable turbo mode (full CPU)")
 	log.Printf("  DELETE /api/loader/boost - Disable turbo mode")
-	if err := server.Start(); err != nil {
+	if err := server.Start(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Server error: %v", err)
 	}
+	<-shutdownDone
+}
+
+// runScenario runs a scenario script in-process and returns the exit code.
+func runScenario(handler http.Handler, path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Failed to read scenario: %v", err)
+		return 2
+	}
+	script, err := scenario.Parse(data)
+	if err != nil {
+		log.Printf("%s: %v", path, err)
+		return 2
+	}
+	report := scenario.NewRunner(handler, "").Run(context.Background(), script)
+	fmt.Print(report.Text())
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
+
+// runSelfTest runs the selftest subcommand: loads a library, checks every
+// mode and prints the report. Returns the exit code: 0 if all modes pass,
+// 1 if one fails and 2 if the library cannot be loaded.
+func runSelfTest(args []string) int {
+	fs := flag.NewFlagSet("selftest", flag.ExitOnError)
+	libraryPath := fs.String("library", "", "Path to library folder (required)")
+	spins := fs.Int("spins", selftest.DefaultSpins, "Outcomes drawn per mode for the sampling check")
+	books := fs.Int("books", selftest.DefaultBooks, "Books read per mode for the events check")
+	seed := fs.Int64("seed", selftest.DefaultSeed, "Seed of the sampling RNG")
+	jsonOut := fs.Bool("json", false, "Print the report as JSON")
+	fs.Parse(args)
+
+	if *libraryPath == "" {
+		fmt.Fprintln(os.Stderr, "selftest: -library is required")
+		fs.Usage()
+		return 2
+	}
+	loader := lut.NewLoaderFromLibrary(*libraryPath)
+	if err := loader.Load(); err != nil {
+		log.Printf("Failed to load index: %v", err)
+		return 2
+	}
+	defer loader.Close()
+
+	report := selftest.Run(loader, selftest.Options{Spins: *spins, Books: *books, Seed: *seed})
+	if *jsonOut {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		fmt.Print(report.Text())
+	}
+	if !report.Passed {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/datastore"
+	"lutexplorer/internal/lgs"
+	"lutexplorer/internal/lut"
+)
+
+// SetDataStore keeps LGS sessions, simulation results, optimizer and
+// compliance runs and the audit log in st. Returns the number of sessions
+// restored from it.
+func (s *Server) SetDataStore(st *datastore.Store) (int, error) {
+	sessions, err := lgs.NewSessionDB(st.DB())
+	if err != nil {
+		return 0, err
+	}
+	restored, err := s.lgsSessions.SetDB(sessions)
+	if err != nil {
+		return 0, err
+	}
+	s.store = st
+	s.simulations.SetDB(st.DB())
+	s.optimizerHandlers.SetRunRecorder(func(mode, kind string, request, result interface{}) {
+		if err := st.RecordOptimizerRun(s.loader.BaseDir(), mode, kind, request, result); err != nil {
+			log.Printf("Failed to record optimizer run: %v", err)
+		}
+	})
+	return restored, nil
+}
+
+// recordCompliance stores compliance results when a data store is set.
+func (s *Server) recordCompliance(results ...*lut.ComplianceResult) {
+	if s.store == nil {
+		return
+	}
+	for _, result := range results {
+		if err := s.store.RecordComplianceRun(s.loader.BaseDir(), result.Mode, result.Profile, result.Passed, result); err != nil {
+			log.Printf("Failed to record compliance run: %v", err)
+		}
+	}
+}
+
+// audited reports whether a request changes server state and belongs in
+// the audit log. Game rounds on /wallet and /bet are left out.
+func audited(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	return strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, "/lgs/")
+}
+
+// auditWriter captures the status of a response.
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush keeps streaming responses working through the wrapper.
+func (w *auditWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// auditMiddleware appends state-changing requests to the data store's audit
+// log.
+func (s *Server) auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.store == nil || !audited(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		aw := &auditWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(aw, r)
+		err := s.store.RecordAudit(datastore.AuditEntry{
+			At:         start,
+			Client:     clientName(r),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Status:     aw.status,
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		if err != nil {
+			log.Printf("Failed to record audit entry: %v", err)
+		}
+	})
+}
+
+// requireStore writes an error and returns false when the server runs
+// without a data directory.
+func (s *Server) requireStore(w http.ResponseWriter) bool {
+	if s.store == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "no data directory configured (start the server with -data-dir)")
+		return false
+	}
+	return true
+}
+
+// listLimit parses the ?limit= query parameter; 0 means the default.
+func listLimit(r *http.Request) (int, bool) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(v)
+	return limit, err == nil && limit >= 0
+}
+
+// handleOptimizerHistory lists the optimizer runs of the open library.
+// Query params: mode (optional), limit
+func (s *Server) handleOptimizerHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.requireStore(w) {
+		return
+	}
+	limit, ok := listLimit(r)
+	if !ok {
+		common.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	runs, err := s.store.OptimizerRuns(s.loader.BaseDir(), r.URL.Query().Get("mode"), limit)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{"runs": runs})
+}
+
+// handleComplianceHistory lists the compliance runs of the open library.
+// Query params: mode (optional), limit
+func (s *Server) handleComplianceHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.requireStore(w) {
+		return
+	}
+	limit, ok := listLimit(r)
+	if !ok {
+		common.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	runs, err := s.store.ComplianceRuns(s.loader.BaseDir(), r.URL.Query().Get("mode"), limit)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{"runs": runs})
+}
+
+// handleAudit lists the audit log, newest first.
+// Query params: client (optional), limit
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if !s.requireStore(w) {
+		return
+	}
+	limit, ok := listLimit(r)
+	if !ok {
+		common.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	entries, err := s.store.Audit(r.URL.Query().Get("client"), limit)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{"entries": entries})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/extensions"
+	"lutexplorer/internal/lut"
+)
+
+// ExtensionChecksResult is the response of GET /api/extensions/checks/{mode}.
+type ExtensionChecksResult struct {
+	Mode      string                `json:"mode"`
+	Checks    []lut.ComplianceCheck `json:"checks"`
+	AllPassed bool                  `json:"all_passed"`
+}
+
+// ExtensionOptimizeResult is the response of
+// POST /api/extensions/optimizers/{name}/{mode}. The weights are a proposal;
+// save them with POST /api/optimizer/{mode}/apply.
+type ExtensionOptimizeResult struct {
+	Optimizer  string      `json:"optimizer"`
+	Mode       string      `json:"mode"`
+	Weights    []uint64    `json:"weights"`
+	RTP        float64     `json:"rtp"`
+	HitRate    float64     `json:"hit_rate"`
+	Volatility float64     `json:"volatility"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// extensionRequest builds the input of an extension call for the mode in
+// the request path. It writes the error response and returns nil if the
+// mode is not loaded.
+func (s *Server) extensionRequest(w http.ResponseWriter, r *http.Request) *extensions.Request {
+	mode := r.PathValue("mode")
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return nil
+	}
+	params := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			params[key] = values[0]
+		}
+	}
+	return &extensions.Request{
+		Mode:   mode,
+		Table:  table,
+		Stats:  s.loader.Statistics(table),
+		Params: params,
+	}
+}
+
+// runExtension calls fn, turning a panic in extension code into an error so
+// a faulty extension cannot take the server down.
+func runExtension(name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("extension %s panicked: %v", name, r)
+			err = fmt.Errorf("extension %s failed: %v", name, r)
+		}
+	}()
+	return fn()
+}
+
+// handleListExtensions lists the compiled-in analyzers, checks and
+// optimizers.
+func (s *Server) handleListExtensions(w http.ResponseWriter, r *http.Request) {
+	list := extensions.Registered()
+	if list == nil {
+		list = []extensions.Info{}
+	}
+	common.WriteSuccess(w, list)
+}
+
+// handleRunAnalyzer runs a custom analyzer on a mode. Query parameters are
+// passed to the analyzer.
+func (s *Server) handleRunAnalyzer(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	analyzer, ok := extensions.GetAnalyzer(name)
+	if !ok {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("analyzer not found: %s", name))
+		return
+	}
+	req := s.extensionRequest(w, r)
+	if req == nil {
+		return
+	}
+
+	var result interface{}
+	err := runExtension(name, func() error {
+		var err error
+		result, err = analyzer.Analyze(req)
+		return err
+	})
+	if err != nil {
+		common.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	common.WriteSuccess(w, result)
+}
+
+// handleRunExtensionChecks runs all custom compliance checks on a mode.
+func (s *Server) handleRunExtensionChecks(w http.ResponseWriter, r *http.Request) {
+	req := s.extensionRequest(w, r)
+	if req == nil {
+		return
+	}
+
+	result := ExtensionChecksResult{Mode: req.Mode, Checks: []lut.ComplianceCheck{}, AllPassed: true}
+	for _, check := range extensions.Checks() {
+		c := extensions.RunCheck(check, req)
+		if !c.Passed && c.Severity != lut.SeverityInfo {
+			result.AllPassed = false
+		}
+		result.Checks = append(result.Checks, c)
+	}
+	common.WriteSuccess(w, result)
+}
+
+// handleRunOptimizer runs a custom optimizer on a mode. The request body is
+// passed to the optimizer as is; the proposed weights are returned with the
+// statistics they produce but not applied.
+func (s *Server) handleRunOptimizer(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	optimizer, ok := extensions.GetOptimizer(name)
+	if !ok {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("optimizer not found: %s", name))
+		return
+	}
+	req := s.extensionRequest(w, r)
+	if req == nil {
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	if len(body) > 0 {
+		if !json.Valid(body) {
+			common.WriteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		req.Body = body
+	}
+
+	var result *extensions.OptimizeResult
+	err = runExtension(name, func() error {
+		var err error
+		result, err = optimizer.Optimize(req)
+		return err
+	})
+	if err == nil && result == nil {
+		err = fmt.Errorf("optimizer %s returned no result", name)
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// WithWeights checks the weight count against the table
+	optimized, err := lut.WithWeights(req.Table, result.Weights)
+	if err != nil {
+		common.WriteError(w, http.StatusUnprocessableEntity, fmt.Sprintf("optimizer %s: %v", name, err))
+		return
+	}
+	stats := lut.NewAnalyzer().Analyze(optimized)
+
+	common.WriteSuccess(w, ExtensionOptimizeResult{
+		Optimizer:  name,
+		Mode:       req.Mode,
+		Weights:    result.Weights,
+		RTP:        stats.RTP,
+		HitRate:    stats.HitRate,
+		Volatility: stats.Volatility,
+		Details:    result.Details,
+	})
+}
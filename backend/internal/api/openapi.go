@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"unicode"
+
+	"lutexplorer/internal/bookmarks"
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/crowdsim"
+	"lutexplorer/internal/datastore"
+	"lutexplorer/internal/extensions"
+	"lutexplorer/internal/lgs"
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/openapi"
+	"lutexplorer/internal/presence"
+	"lutexplorer/internal/scenario"
+	"lutexplorer/internal/selftest"
+	"lutexplorer/internal/simstore"
+
+	"stakergs"
+)
+
+// apiVersion is the version reported in the OpenAPI document.
+const apiVersion = "1.0"
+
+// routeMux is a ServeMux that remembers the routes registered on it, so the
+// OpenAPI document always matches what is served.
+type routeMux struct {
+	*http.ServeMux
+	routes []openapi.Route
+}
+
+func newRouteMux() *routeMux {
+	return &routeMux{ServeMux: http.NewServeMux()}
+}
+
+// HandleFunc registers handler and records the route. Subtree patterns
+// without a method (e.g. "/api/optimizer/") are not recorded; their
+// packages list the routes they dispatch themselves.
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.ServeMux.HandleFunc(pattern, handler)
+
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return
+	}
+	route := routeDocs[pattern]
+	route.Method, route.Path = method, path
+	if route.Summary == "" {
+		route.Summary = handlerSummary(handler)
+	}
+	if strings.HasPrefix(path, "/wallet/") || strings.HasPrefix(path, "/bet/") || strings.HasPrefix(path, "/lgs/") {
+		route.Raw = true // the LGS answers without the success envelope
+	}
+	m.routes = append(m.routes, route)
+}
+
+// handlerSummary derives a summary from a handler's name, e.g.
+// handleModeStats -> "Mode stats".
+func handlerSummary(handler func(http.ResponseWriter, *http.Request)) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "handle"), "Handle")
+	if name == "" || strings.HasPrefix(name, "func") {
+		return "" // wrapped or anonymous handler
+	}
+
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// routeDocs adds what cannot be read off the mux: body types, query
+// parameters and summaries of wrapped handlers. Keyed by route pattern.
+var routeDocs = map[string]openapi.Route{
+	"GET /api/modes":                                  {Response: []lut.ModeSummary{}},
+	"POST /api/modes":                                 {Request: stakergs.ModeConfig{}, Response: lut.ModeSummary{}},
+	"GET /api/mode/{mode}":                            {Response: lut.ModeSummary{}},
+	"GET /api/mode/{mode}/stats":                      {Response: lut.Statistics{}},
+	"GET /api/mode/{mode}/distribution":               {Response: []lut.DistributionItem{}},
+	"GET /api/compare":                                {Query: []string{"mode"}, Response: CompareResponse{}},
+	"POST /api/mode/{mode}/simulate":                  {Request: SimulateRequest{}, Response: lut.SimulationResult{}},
+	"POST /api/mode/{mode}/simulate/quick":            {Request: QuickSimulateRequest{}, Response: lut.SimulationResult{}},
+	"POST /api/mode/{mode}/simulate/composite":        {Request: CompositeSimulateRequest{}},
+	"POST /api/simulate/compare":                      {Request: CompareRequest{}},
+	"GET /api/simulations":                            {Query: []string{"mode"}},
+	"GET /api/simulations/{id}":                       {Response: simstore.Record{}},
+	"GET /api/mode/{mode}/sampling":                   {Query: []string{"top"}, Response: lut.SamplingReport{}},
+	"POST /api/mode/{mode}/variants":                  {Request: VariantRequest{}, Response: lut.VariantInfo{}},
+	"POST /api/mode/{mode}/whatif":                    {Request: WhatIfRequest{}, Response: lut.WhatIfResult{}},
+	"PATCH /api/mode/{mode}/outcome/{simID}":          {Summary: "Set outcome weight"},
+	"GET /api/bookmarks":                              {Query: []string{"mode", "tag"}},
+	"POST /api/bookmarks":                             {Request: BookmarkRequest{}, Response: bookmarks.Bookmark{}},
+	"PUT /api/bookmarks/{id}":                         {Request: BookmarkRequest{}, Response: bookmarks.Bookmark{}},
+	"GET /api/mode/{mode}/export":                     {Query: []string{"format"}, Response: ModeExport{}},
+	"GET /api/state/export":                           {Summary: "Export state archive (zip)"},
+	"POST /api/state/import":                          {Summary: "Import state archive (zip body)"},
+	"POST /api/library/open":                          {Request: LibraryRequest{}},
+	"POST /api/library/switch":                        {Request: LibraryRequest{}},
+	"POST /api/crowdsim/{mode}/simulate":              {Request: crowdsim.SimConfig{}, Response: crowdsim.SimResult{}},
+	"POST /api/crowdsim/compare":                      {Request: crowdsim.CompareRequest{}, Response: crowdsim.CompareResult{}},
+	"POST /wallet/authenticate":                       {Summary: "Authenticate", Request: lgs.AuthRequest{}, Response: lgs.AuthResponse{}},
+	"POST /wallet/play":                               {Summary: "Play", Request: lgs.PlayRequest{}, Response: lgs.PlayResponse{}},
+	"POST /wallet/end-round":                          {Summary: "End round", Request: lgs.EndRoundRequest{}, Response: lgs.EndRoundResponse{}},
+	"POST /bet/event":                                 {Summary: "Bet event", Request: lgs.EventRequest{}, Response: lgs.EventResponse{}},
+	"GET /bet/replay/{game}/{version}/{mode}/{event}": {Summary: "Replay", Query: []string{"transform"}, Response: lgs.ReplayResponse{}},
+	"GET /lgs/health":                                 {Response: lgs.HealthResponse{}},
+	"GET /lgs/sessions":                               {Response: lgs.SessionsResponse{}},
+	"GET /lgs/stats":                                  {Summary: "Session stats and RTP convergence", Query: []string{"sessionID", "confidence", "tolerance"}},
+	"POST /lgs/batchplay":                             {Request: lgs.BatchPlayRequest{}, Response: lgs.BatchPlayResponse{}},
+	"POST /lgs/batchplay/cancel":                      {Request: lgs.BatchCancelRequest{}},
+	"POST /lgs/scenario":                              {Summary: "Script the outcomes of the next plays", Request: lgs.ScenarioRequest{}, Response: lgs.ScenarioResponse{}},
+	"GET /lgs/scenario":                               {Summary: "Remaining scenario steps", Query: []string{"sessionID"}, Response: lgs.ScenarioResponse{}},
+	"DELETE /lgs/scenario":                            {Summary: "Clear scenario", Query: []string{"sessionID"}},
+	"POST /lgs/record/start":                          {Summary: "Start recording a session's plays"},
+	"POST /lgs/record/stop":                           {Summary: "Stop recording and download it", Response: lgs.SessionRecording{}},
+	"POST /lgs/record/replay":                         {Summary: "Replay a recording against the current LUT", Request: lgs.SessionRecording{}, Response: lgs.ReplayReport{}},
+	"POST /lgs/config/bet-levels":                     {Summary: "Configure per-mode bet levels", Request: lgs.BetLevelsRequest{}, Response: lgs.BetLevelsResponse{}},
+	"GET /lgs/config/bet-levels":                      {Summary: "Per-mode bet levels", Response: lgs.BetLevelsResponse{}},
+	"DELETE /lgs/config/bet-levels":                   {Summary: "Clear bet levels of a mode, or all", Query: []string{"mode"}},
+	"GET /lgs/config/currencies":                      {Summary: "Accepted currencies and exchange rates", Response: lgs.CurrenciesResponse{}},
+	"POST /lgs/config/currencies":                     {Summary: "Add or update currencies and exchange rates", Request: lgs.CurrenciesRequest{}, Response: lgs.CurrenciesResponse{}},
+	"DELETE /lgs/config/currencies":                   {Summary: "Restore the default currencies"},
+	"GET /lgs/config/jackpots":                        {Summary: "Jackpot pools and their values", Response: lgs.JackpotsResponse{}},
+	"POST /lgs/config/jackpots":                       {Summary: "Add or replace jackpot pools", Request: lgs.JackpotsRequest{}, Response: lgs.JackpotsResponse{}},
+	"DELETE /lgs/config/jackpots":                     {Summary: "Remove a jackpot pool, or all", Query: []string{"name"}},
+	"POST /lgs/jackpots/reset":                        {Summary: "Reset jackpot pools to their seed", Query: []string{"name"}, Response: lgs.JackpotsResponse{}},
+	"GET /lgs/faults":                                 {Summary: "Fault injection rules and counts", Response: lgs.FaultsResponse{}},
+	"POST /lgs/faults":                                {Summary: "Inject latency, errors, dropped requests and duplicate bet IDs into wallet and bet endpoints", Request: lgs.FaultsRequest{}, Response: lgs.FaultsResponse{}},
+	"DELETE /lgs/faults":                              {Summary: "Remove fault rules", Query: []string{"endpoint"}},
+	"POST /lgs/history":                               {Request: lgs.HistoryRequest{}, Response: lgs.HistoryResponse{}},
+	"POST /api/locks":                                 {Summary: "Acquire or refresh a soft lock", Request: LockRequest{}, Response: presence.Lock{}},
+	"DELETE /api/locks":                               {Summary: "Release a soft lock", Query: []string{"mode", "force"}},
+	"GET /api/extensions":                             {Response: []extensions.Info{}},
+	"GET /api/extensions/analyzers/{name}/{mode}":     {Summary: "Run a custom analyzer"},
+	"GET /api/extensions/checks/{mode}":               {Summary: "Run the custom compliance checks", Response: ExtensionChecksResult{}},
+	"POST /api/extensions/optimizers/{name}/{mode}":   {Summary: "Run a custom optimizer", Response: ExtensionOptimizeResult{}},
+	"POST /api/scenarios/run":                         {Summary: "Run a YAML scenario script (request body)", Response: scenario.Report{}},
+	"GET /api/selftest":                               {Summary: "Check the tables, sampler and events offsets of every mode", Query: []string{"spins", "books", "seed"}, Response: selftest.Report{}},
+	"GET /api/history/optimizer":                      {Summary: "Optimizer runs (needs -data-dir)", Query: []string{"mode", "limit"}, Response: []datastore.OptimizerRun{}},
+	"GET /api/history/compliance":                     {Summary: "Compliance runs (needs -data-dir)", Query: []string{"mode", "limit"}, Response: []datastore.ComplianceRun{}},
+	"GET /api/audit":                                  {Summary: "Audit log of state-changing requests (needs -data-dir)", Query: []string{"client", "limit"}, Response: []datastore.AuditEntry{}},
+	"GET /api/logs":                                   {Summary: "Recent backend log lines", Query: []string{"since", "limit", "level"}},
+	"GET /api/logs/stream":                            {Summary: "Backend log lines as server-sent events", Query: []string{"since", "level"}},
+	"GET /ws":                                         {Summary: "WebSocket for loading, LGS and optimizer messages"},
+	"GET /api/openapi.json":                           {Summary: "This OpenAPI document", Tag: "docs", Raw: true},
+	"GET /api/docs":                                   {Summary: "Interactive API docs (Swagger UI)", Tag: "docs", Raw: true},
+}
+
+// setRoutes builds the OpenAPI document from the routes registered on mux
+// plus those of the subtree handlers.
+func (s *Server) setRoutes(mux *routeMux) {
+	routes := append([]openapi.Route{}, mux.routes...)
+	routes = append(routes, s.optimizerHandlers.Routes()...)
+	if s.convexoptHandlers != nil {
+		routes = append(routes, s.convexoptHandlers.Routes()...)
+	}
+	doc := openapi.Build("LUT Explorer API", apiVersion, routes)
+	s.openAPI.Store(&doc)
+}
+
+// handleOpenAPI serves the OpenAPI document of the registered routes.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	doc := s.openAPI.Load()
+	if doc == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "routes not registered yet")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(doc)
+}
+
+// swaggerUI loads Swagger UI from a CDN and points it at the OpenAPI
+// document next to it.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>LUT Explorer API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: 'openapi.json', dom_id: '#swagger-ui', deepLinking: true });
+  </script>
+</body>
+</html>
+`
+
+// handleDocs serves interactive API docs.
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUI))
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/presence"
+	"lutexplorer/internal/ws"
+)
+
+// ClientHeader carries the display name a client identifies itself with.
+// Browsers that cannot set headers (e.g. WebSocket upgrades) may pass
+// ?client= instead.
+const ClientHeader = "X-Client-Name"
+
+// clientName returns the name a request identifies itself with, or "".
+func clientName(r *http.Request) string {
+	name := strings.TrimSpace(r.Header.Get(ClientHeader))
+	if name == "" {
+		name = strings.TrimSpace(r.URL.Query().Get("client"))
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// forced reports whether a request asks to override other clients' locks.
+func forced(r *http.Request) bool {
+	v := r.URL.Query().Get("force")
+	return v == "true" || v == "1"
+}
+
+// broadcastLock tells all clients about a lock change.
+func (s *Server) broadcastLock(e presence.Event) {
+	msgType := ws.MsgLockReleased
+	if e.Acquired {
+		msgType = ws.MsgLockAcquired
+	}
+	s.wsHub.Broadcast(ws.Message{Type: msgType, Mode: e.Lock.Mode, Payload: e.Lock})
+}
+
+// lockedTarget returns the mode a destructive request overwrites ("" for
+// the whole library), or ok=false if the request is not destructive.
+func lockedTarget(r *http.Request) (mode string, ok bool) {
+	path := r.URL.Path
+	switch r.Method {
+	case http.MethodPost:
+		switch path {
+		case "/api/reload", "/api/library/open", "/api/library/close", "/api/library/switch", "/api/state/import":
+			return "", true
+		}
+		if rest, found := strings.CutPrefix(path, "/api/optimizer/"); found {
+			mode, action, _ := strings.Cut(rest, "/")
+			if action == "apply" || action == "restore" {
+				return mode, true
+			}
+		}
+	case http.MethodPatch:
+		if rest, found := strings.CutPrefix(path, "/api/mode/"); found {
+			mode, action, _ := strings.Cut(rest, "/")
+			if strings.HasPrefix(action, "outcome/") {
+				return mode, true
+			}
+		}
+	}
+	return "", false
+}
+
+// presenceMiddleware records which clients are active and refuses
+// destructive requests that would override another client's soft lock,
+// unless they pass ?force=true.
+func (s *Server) presenceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := clientName(r)
+		if name != "" && s.presence.Seen(name) {
+			s.wsHub.Broadcast(ws.Message{Type: ws.MsgClientConnected, Payload: map[string]string{"name": name}})
+		}
+
+		if mode, ok := lockedTarget(r); ok && !forced(r) {
+			if err := s.presence.Check(mode, name); err != nil {
+				common.WriteError(w, http.StatusConflict, err.Error()+" (retry with ?force=true to override)")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handlePresence lists the active clients and the locks they hold.
+func (s *Server) handlePresence(w http.ResponseWriter, r *http.Request) {
+	common.WriteSuccess(w, map[string]interface{}{
+		"clients": s.presence.Clients(),
+		"locks":   s.presence.Locks(),
+	})
+}
+
+// LockRequest is the request body for POST /api/locks.
+type LockRequest struct {
+	Mode   string `json:"mode,omitempty"`   // empty locks the whole library
+	Action string `json:"action,omitempty"` // e.g. "optimizing"; default "editing"
+}
+
+// handleAcquireLock takes or refreshes a soft lock for the calling client.
+// Clients keep a lock by re-posting it before it expires.
+func (s *Server) handleAcquireLock(w http.ResponseWriter, r *http.Request) {
+	var req LockRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	name := clientName(r)
+	if name == "" {
+		common.WriteError(w, http.StatusBadRequest, ClientHeader+" header required")
+		return
+	}
+	if req.Mode != "" {
+		if _, err := s.loader.GetMode(req.Mode); err != nil {
+			common.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+	}
+
+	lock, err := s.presence.Acquire(req.Mode, name, req.Action, forced(r))
+	var conflict *presence.ConflictError
+	if errors.As(err, &conflict) {
+		common.WriteError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	common.WriteSuccess(w, lock)
+}
+
+// handleReleaseLock drops the calling client's lock on ?mode= (the library
+// lock if omitted). ?force=true releases another client's lock.
+func (s *Server) handleReleaseLock(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if err := s.presence.Release(mode, clientName(r), forced(r)); err != nil {
+		common.WriteError(w, http.StatusConflict, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]bool{"released": true})
+}
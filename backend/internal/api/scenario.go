@@ -0,0 +1,39 @@
+package api
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/scenario"
+)
+
+// maxScenarioSize limits the size of a posted scenario script.
+const maxScenarioSize = 1 << 20
+
+// handleRunScenario runs a YAML scenario script (the request body) against
+// this server and returns its report. Failed assertions are part of the
+// report, not an error response.
+func (s *Server) handleRunScenario(w http.ResponseWriter, r *http.Request) {
+	handler := s.handler.Load()
+	if handler == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "server is starting")
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxScenarioSize))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	script, err := scenario.Parse(data)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report := scenario.NewRunner(*handler, clientName(r)).Run(r.Context(), script)
+	log.Printf("Scenario %q: passed=%v, %d steps, %d spins, %dms",
+		report.Name, report.Passed, len(report.Steps), report.Spins, report.DurationMs)
+	common.WriteSuccess(w, report)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/selftest"
+)
+
+// handleSelfTest checks every mode of the open library: table, sampler and
+// events offsets. Failed checks are part of the report, not an error
+// response, so the launcher's pre-flight check reads one shape.
+// Query params: spins, books, seed
+func (s *Server) handleSelfTest(w http.ResponseWriter, r *http.Request) {
+	if !s.loader.IsOpen() {
+		common.WriteError(w, http.StatusConflict, "no library open")
+		return
+	}
+	var opts selftest.Options
+	var err error
+	query := r.URL.Query()
+	if v := query.Get("spins"); v != "" {
+		if opts.Spins, err = strconv.Atoi(v); err != nil || opts.Spins < 0 {
+			common.WriteError(w, http.StatusBadRequest, "spins must be a non-negative integer")
+			return
+		}
+	}
+	if v := query.Get("books"); v != "" {
+		if opts.Books, err = strconv.Atoi(v); err != nil || opts.Books < 0 {
+			common.WriteError(w, http.StatusBadRequest, "books must be a non-negative integer")
+			return
+		}
+	}
+	if v := query.Get("seed"); v != "" {
+		if opts.Seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "seed must be an integer")
+			return
+		}
+	}
+
+	report := selftest.Run(s.loader, opts)
+	log.Printf("Self-test: passed=%v, %d modes, %dms", report.Passed, len(report.Modes), report.DurationMs)
+	common.WriteSuccess(w, report)
+}
@@ -2,18 +2,38 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"lutexplorer/internal/bgloader"
+	"lutexplorer/internal/bookmarks"
 	"lutexplorer/internal/common"
 	"lutexplorer/internal/convexopt"
 	"lutexplorer/internal/crowdsim"
+	"lutexplorer/internal/datastore"
 	"lutexplorer/internal/lgs"
+	"lutexplorer/internal/i18n"
+	"lutexplorer/internal/logbuf"
+	"lutexplorer/internal/numfmt"
+	"lutexplorer/internal/simstore"
+	"lutexplorer/internal/statearchive"
 	"lutexplorer/internal/lut"
+	"lutexplorer/internal/openapi"
 	"lutexplorer/internal/optimizer"
+	"lutexplorer/internal/presence"
+	"lutexplorer/internal/recent"
 	"lutexplorer/internal/watcher"
 	"lutexplorer/internal/ws"
 
@@ -32,7 +52,22 @@ type Server struct {
 	convexoptHandlers  *convexopt.Handlers
 	wsHub              *ws.Hub
 	bgLoader           *bgloader.BackgroundLoader
-	csvWatcher         *watcher.FileWatcher
+	csvWatcher         atomic.Pointer[watcher.FileWatcher] // swapped when the library changes
+	logs               *logbuf.Buffer
+	locales            *i18n.Catalog
+	simulations        *simstore.Store
+	store              *datastore.Store // optional, set with -data-dir
+	bookmarks          *bookmarks.Store
+	libraryChanged     func()
+	recentLibraries    *recent.Store
+	startedAt          time.Time
+	openAPI            atomic.Pointer[openapi.Document]
+	presence           *presence.Tracker
+	// handler is the full handler chain, for requests the server sends to
+	// itself (scenario runs)
+	handler atomic.Pointer[http.HandlerFunc]
+	// httpServer serves the handler chain on addr once Start is called
+	httpServer *http.Server
 }
 
 // NewServer creates a new API server.
@@ -46,7 +81,12 @@ func NewServer(loader *lut.Loader, addr string, hub *ws.Hub, convexURL string) *
 		crowdsimHandlers:  crowdsim.NewHandlers(loader, hub),
 		optimizerHandlers: optimizer.NewHandlers(loader, hub),
 		wsHub:             hub,
+		simulations:       simstore.New(loader.BaseDir()),
+		bookmarks:         bookmarks.New(loader.BaseDir()),
+		startedAt:         time.Now(),
+		httpServer:        &http.Server{Addr: addr},
 	}
+	s.presence = presence.NewTracker(presence.DefaultTTL, s.broadcastLock)
 
 	// Initialize convex optimizer handlers if URL is provided
 	if convexURL != "" {
@@ -56,14 +96,51 @@ func NewServer(loader *lut.Loader, addr string, hub *ws.Hub, convexURL string) *
 	return s
 }
 
+// SetLogBuffer sets the buffer served by the log endpoints.
+func (s *Server) SetLogBuffer(b *logbuf.Buffer) {
+	s.logs = b
+}
+
+// SetLocales sets the catalog served by the i18n endpoints.
+func (s *Server) SetLocales(c *i18n.Catalog) {
+	s.locales = c
+}
+
 // SetBackgroundLoader sets the background loader for the server.
 func (s *Server) SetBackgroundLoader(bl *bgloader.BackgroundLoader) {
 	s.bgLoader = bl
 }
 
-// SetCSVWatcher sets the CSV watcher for the server.
+// SetCSVWatcher sets the CSV watcher for the server. It may be called while
+// serving, when the library changes.
 func (s *Server) SetCSVWatcher(w *watcher.FileWatcher) {
-	s.csvWatcher = w
+	s.csvWatcher.Store(w)
+}
+
+// SetSessionDB persists LGS sessions to db and restores the stored ones.
+func (s *Server) SetSessionDB(db *lgs.SessionDB) (int, error) {
+	return s.lgsSessions.SetDB(db)
+}
+
+// SetLGSPresets replaces the LGS bias presets.
+func (s *Server) SetLGSPresets(presets []lgs.BiasPreset) {
+	s.lgsHandlers.SetPresets(presets)
+}
+
+// SetLGSReplay serves a recorded cassette on the LGS wallet and bet endpoints.
+func (s *Server) SetLGSReplay(c *lgs.Cassette) {
+	s.lgsHandlers.StartReplay(c)
+}
+
+// SetRecentLibraries sets the store of recently opened libraries.
+func (s *Server) SetRecentLibraries(r *recent.Store) {
+	s.recentLibraries = r
+}
+
+// SetLibraryChangedHook sets a function called after a library is opened or
+// closed through the API, e.g. to point the CSV watcher at the new folder.
+func (s *Server) SetLibraryChangedHook(fn func()) {
+	s.libraryChanged = fn
 }
 
 // Hub returns the WebSocket hub.
@@ -74,34 +151,69 @@ func (s *Server) Hub() *ws.Hub {
 // IndexInfo contains basic information about the loaded index.
 type IndexInfo struct {
 	Modes []lut.ModeSummary `json:"modes"`
+	Size  *lut.LibrarySize  `json:"size"`
 }
 
 // Start starts the HTTP server.
 func (s *Server) Start() error {
-	mux := http.NewServeMux()
+	mux := newRouteMux()
 
 	// API routes
 	mux.HandleFunc("GET /api/health", s.handleHealth)
+	mux.HandleFunc("GET /api/system", s.handleSystem)
 	mux.HandleFunc("GET /api/index", s.handleIndex)
 	mux.HandleFunc("GET /api/modes", s.handleModes)
+	mux.HandleFunc("POST /api/modes", s.handleAddMode)
+	mux.HandleFunc("DELETE /api/mode/{mode}", s.handleRemoveMode)
 	mux.HandleFunc("GET /api/mode/{mode}", s.handleMode)
 	mux.HandleFunc("GET /api/mode/{mode}/stats", s.handleModeStats)
 	mux.HandleFunc("GET /api/mode/{mode}/distribution", s.handleModeDistribution)
 	mux.HandleFunc("GET /api/mode/{mode}/distribution/bucket", s.handleModeBucketDistribution)
 	mux.HandleFunc("GET /api/mode/{mode}/outcomes", s.handleModeOutcomes)
+	mux.HandleFunc("GET /api/mode/{mode}/outcomes/stream", s.handleModeOutcomesStream)
+	mux.HandleFunc("PATCH /api/mode/{mode}/outcome/{simID}", s.handleSetOutcomeWeight)
 	mux.HandleFunc("GET /api/compare", s.handleCompare)
 
 	// Events API (lazy loading - only loads what's needed)
 	mux.HandleFunc("POST /api/mode/{mode}/events/load", s.handleLoadEvents)
 	mux.HandleFunc("DELETE /api/mode/{mode}/events", s.handleUnloadEvents)
 	mux.HandleFunc("DELETE /api/events", s.handleUnloadAllEvents)
+	mux.HandleFunc("GET /api/events/synthetic", s.handleSyntheticEventsStatus)
+	mux.HandleFunc("POST /api/events/synthetic", s.handleSyntheticEventsEnable)
+	mux.HandleFunc("DELETE /api/events/synthetic", s.handleSyntheticEventsDisable)
 	mux.HandleFunc("GET /api/mode/{mode}/events/range", s.handleGetEventsRange)
 	mux.HandleFunc("GET /api/mode/{mode}/events/stats", s.handleEventsStats)
+	mux.HandleFunc("POST /api/mode/{mode}/events/search", s.handleSearchEvents)
+	mux.HandleFunc("GET /api/mode/{mode}/events/frequency", s.handleEventFrequency)
 	mux.HandleFunc("GET /api/mode/{mode}/event/{simID}", s.handleGetEvent)
 
 	// Simulator API
 	mux.HandleFunc("POST /api/mode/{mode}/simulate", s.handleSimulate)
 	mux.HandleFunc("POST /api/mode/{mode}/simulate/quick", s.handleQuickSimulate)
+	mux.HandleFunc("GET /api/mode/{mode}/sample-size", s.handleSampleSize)
+	mux.HandleFunc("GET /api/mode/{mode}/sampling", s.handleSampling)
+	mux.HandleFunc("DELETE /api/mode/{mode}/sampling", s.handleResetSampling)
+	mux.HandleFunc("GET /api/mode/{mode}/par-sheet", s.handleParSheet)
+	mux.HandleFunc("GET /api/mode/{mode}/max-win", s.handleMaxWin)
+	mux.HandleFunc("GET /api/mode/{mode}/variants", s.handleListVariants)
+	mux.HandleFunc("POST /api/mode/{mode}/variants", s.handleCreateVariant)
+	mux.HandleFunc("DELETE /api/mode/{mode}/variants/{name}", s.handleDeleteVariant)
+	mux.HandleFunc("POST /api/mode/{mode}/whatif", s.handleWhatIf)
+	mux.HandleFunc("GET /api/mode/{mode}/sample-outcomes", s.handleSampleOutcomes)
+	mux.HandleFunc("POST /api/mode/{mode}/simulate/composite", s.handleCompositeSimulate)
+	mux.HandleFunc("POST /api/simulate/compare", s.handleCompareSimulations)
+	mux.HandleFunc("GET /api/simulations", s.handleListSimulations)
+	mux.HandleFunc("GET /api/simulations/{id}", s.handleGetSimulation)
+	mux.HandleFunc("DELETE /api/simulations/{id}", s.handleDeleteSimulation)
+
+	// Bookmarks (per-library notes and tags on simIDs)
+	mux.HandleFunc("GET /api/bookmarks", s.handleListBookmarks)
+	mux.HandleFunc("POST /api/bookmarks", s.handleAddBookmark)
+	mux.HandleFunc("PUT /api/bookmarks/{id}", s.handleUpdateBookmark)
+	mux.HandleFunc("DELETE /api/bookmarks/{id}", s.handleDeleteBookmark)
+	mux.HandleFunc("GET /api/mode/{mode}/export", s.handleExportMode)
+	mux.HandleFunc("GET /api/state/export", s.handleExportState)
+	mux.HandleFunc("POST /api/state/import", s.handleImportState)
 
 	// CrowdSim API
 	mux.HandleFunc("POST /api/crowdsim/{mode}/simulate", s.crowdsimHandlers.HandleSimulate)
@@ -111,45 +223,91 @@ func (s *Server) Start() error {
 	mux.HandleFunc("POST /api/crowdsim/{mode}/volatility-check", s.crowdsimHandlers.HandleVolatilityCheck)
 
 	// Optimizer API
-	s.optimizerHandlers.RegisterRoutes(mux)
+	s.optimizerHandlers.RegisterRoutes(mux.ServeMux)
 
 	// Convex Optimizer API (if enabled)
 	if s.convexoptHandlers != nil {
-		s.convexoptHandlers.RegisterRoutes(mux)
+		s.convexoptHandlers.RegisterRoutes(mux.ServeMux)
 	}
 
 	// LGS (Local Game Server) - RGS-compatible endpoints
 	// Wallet endpoints
-	mux.HandleFunc("POST /wallet/authenticate", s.lgsHandlers.Authenticate)
-	mux.HandleFunc("POST /wallet/play", s.lgsHandlers.Play)
-	mux.HandleFunc("POST /wallet/end-round", s.lgsHandlers.EndRound)
+	// Wallet and bet traffic can be recorded to and replayed from cassettes,
+	// and disrupted by injected faults
+	tape := func(next http.HandlerFunc) http.HandlerFunc {
+		return s.lgsHandlers.Faulty(s.lgsHandlers.Taped(next))
+	}
+	mux.HandleFunc("POST /wallet/authenticate", tape(s.lgsHandlers.Authenticate))
+	mux.HandleFunc("POST /wallet/play", tape(s.lgsHandlers.Idempotent(s.lgsHandlers.Play)))
+	mux.HandleFunc("POST /wallet/end-round", tape(s.lgsHandlers.Idempotent(s.lgsHandlers.EndRound)))
 
 	// Bet endpoints
-	mux.HandleFunc("POST /bet/event", s.lgsHandlers.Event)
-	mux.HandleFunc("GET /bet/replay/{game}/{version}/{mode}/{event}", s.lgsHandlers.Replay)
+	mux.HandleFunc("POST /bet/event", tape(s.lgsHandlers.Event))
+	mux.HandleFunc("GET /bet/replay/{game}/{version}/{mode}/{event}", tape(s.lgsHandlers.Replay))
 
 	// Additional LGS utility endpoints
 	mux.HandleFunc("GET /lgs/health", s.lgsHandlers.Health)
 	mux.HandleFunc("GET /lgs/sessions", s.lgsHandlers.Sessions)
 	mux.HandleFunc("POST /lgs/batchplay", s.lgsHandlers.BatchPlay)
+	mux.HandleFunc("POST /lgs/batchplay/cancel", s.lgsHandlers.CancelBatchPlay)
 	mux.HandleFunc("POST /lgs/history", s.lgsHandlers.History)
 	mux.HandleFunc("DELETE /lgs/history", s.lgsHandlers.ClearHistory)
 	mux.HandleFunc("GET /lgs/stats", s.lgsHandlers.Stats)
+	mux.HandleFunc("GET /lgs/stats/timeseries", s.lgsHandlers.StatsTimeseries)
 	mux.HandleFunc("DELETE /lgs/stats", s.lgsHandlers.ClearStats)
 	mux.HandleFunc("POST /lgs/reset-balance", s.lgsHandlers.ResetBalance)
 	mux.HandleFunc("POST /lgs/set-balance", s.lgsHandlers.SetBalance)
 	mux.HandleFunc("POST /lgs/force-outcome", s.lgsHandlers.ForceOutcome)
+	mux.HandleFunc("POST /lgs/force-outcome/by-criteria", s.lgsHandlers.ForceOutcomeByCriteria)
 	mux.HandleFunc("GET /lgs/force-outcome", s.lgsHandlers.GetForcedOutcomes)
 	mux.HandleFunc("DELETE /lgs/force-outcome", s.lgsHandlers.ClearForcedOutcome)
+	mux.HandleFunc("POST /lgs/scenario", s.lgsHandlers.SetScenario)
+	mux.HandleFunc("GET /lgs/scenario", s.lgsHandlers.GetScenario)
+	mux.HandleFunc("DELETE /lgs/scenario", s.lgsHandlers.ClearScenario)
+	mux.HandleFunc("POST /lgs/record/start", s.lgsHandlers.StartRecording)
+	mux.HandleFunc("POST /lgs/record/stop", s.lgsHandlers.StopRecording)
+	mux.HandleFunc("POST /lgs/record/replay", s.lgsHandlers.ReplayRecording)
+	mux.HandleFunc("POST /lgs/config/bet-levels", s.lgsHandlers.SetBetLevels)
+	mux.HandleFunc("GET /lgs/config/bet-levels", s.lgsHandlers.GetBetLevels)
+	mux.HandleFunc("DELETE /lgs/config/bet-levels", s.lgsHandlers.ClearBetLevels)
+	mux.HandleFunc("GET /lgs/config/currencies", s.lgsHandlers.GetCurrencies)
+	mux.HandleFunc("POST /lgs/config/currencies", s.lgsHandlers.SetCurrencies)
+	mux.HandleFunc("DELETE /lgs/config/currencies", s.lgsHandlers.ResetCurrencies)
+	mux.HandleFunc("GET /lgs/config/jackpots", s.lgsHandlers.GetJackpots)
+	mux.HandleFunc("POST /lgs/config/jackpots", s.lgsHandlers.SetJackpots)
+	mux.HandleFunc("DELETE /lgs/config/jackpots", s.lgsHandlers.ClearJackpots)
+	mux.HandleFunc("POST /lgs/jackpots/reset", s.lgsHandlers.ResetJackpots)
+	mux.HandleFunc("GET /lgs/faults", s.lgsHandlers.GetFaults)
+	mux.HandleFunc("POST /lgs/faults", s.lgsHandlers.SetFaults)
+	mux.HandleFunc("DELETE /lgs/faults", s.lgsHandlers.ClearFaults)
 	mux.HandleFunc("POST /lgs/rtp-bias", s.lgsHandlers.SetRTPBias)
 	mux.HandleFunc("GET /lgs/rtp-bias", s.lgsHandlers.GetRTPBias)
+	mux.HandleFunc("POST /lgs/variant", s.lgsHandlers.SetVariant)
+	mux.HandleFunc("GET /lgs/variant", s.lgsHandlers.GetVariants)
+	mux.HandleFunc("GET /lgs/presets", s.lgsHandlers.Presets)
+	mux.HandleFunc("POST /lgs/presets/apply", s.lgsHandlers.ApplyPreset)
+	mux.HandleFunc("GET /lgs/transforms", s.lgsHandlers.Transforms)
+	mux.HandleFunc("PUT /lgs/transforms", s.lgsHandlers.SetTransforms)
+	mux.HandleFunc("GET /lgs/cassettes", s.lgsHandlers.Cassettes)
+	mux.HandleFunc("POST /lgs/cassettes/record", s.lgsHandlers.RecordCassette)
+	mux.HandleFunc("POST /lgs/cassettes/replay", s.lgsHandlers.ReplayCassette)
+	mux.HandleFunc("POST /lgs/cassettes/stop", s.lgsHandlers.StopCassette)
+	mux.HandleFunc("GET /lgs/cassettes/{name}", s.lgsHandlers.GetCassette)
+	mux.HandleFunc("DELETE /lgs/cassettes/{name}", s.lgsHandlers.DeleteCassette)
 
 	// WebSocket endpoint
 	mux.HandleFunc("GET /ws", s.wsHub.ServeWs)
 
 	// Compliance API
 	mux.HandleFunc("GET /api/mode/{mode}/compliance", s.handleModeCompliance)
+	mux.HandleFunc("GET /api/mode/{mode}/compliance/report", s.handleComplianceReport)
 	mux.HandleFunc("GET /api/compliance", s.handleAllCompliance)
+	mux.HandleFunc("GET /api/compliance/profiles", s.handleComplianceProfiles)
+
+	// Data directory history
+	mux.HandleFunc("GET /api/history/optimizer", s.handleOptimizerHistory)
+	mux.HandleFunc("GET /api/history/compliance", s.handleComplianceHistory)
+	mux.HandleFunc("GET /api/audit", s.handleAudit)
 
 	// Background loader API
 	mux.HandleFunc("GET /api/loader/status", s.handleLoaderStatus)
@@ -159,59 +317,141 @@ func (s *Server) Start() error {
 	mux.HandleFunc("GET /api/loader/priority", s.handleLoaderPriority)
 	mux.HandleFunc("POST /api/reload", s.handleReload)
 
+	// Library API (open/close a library without restarting)
+	mux.HandleFunc("GET /api/library", s.handleLibrary)
+	mux.HandleFunc("POST /api/library/open", s.handleLibraryOpen)
+	mux.HandleFunc("POST /api/library/close", s.handleLibraryClose)
+	mux.HandleFunc("POST /api/library/switch", s.handleLibrarySwitch)
+	mux.HandleFunc("GET /api/library/recent", s.handleRecentLibraries)
+	mux.HandleFunc("DELETE /api/library/recent", s.handleForgetLibrary)
+
 	// CSV Watcher API
 	mux.HandleFunc("GET /api/watcher/status", s.handleWatcherStatus)
 	mux.HandleFunc("POST /api/watcher/enable", s.handleWatcherEnable)
 	mux.HandleFunc("DELETE /api/watcher/enable", s.handleWatcherDisable)
+	mux.HandleFunc("GET /api/watcher/events", s.handleWatcherEvents)
+	mux.HandleFunc("POST /api/watcher/rescan", s.handleWatcherRescan)
+	mux.HandleFunc("POST /api/watcher/mode/{mode}/enable", s.handleWatcherModeEnable)
+	mux.HandleFunc("DELETE /api/watcher/mode/{mode}/enable", s.handleWatcherModeDisable)
+
+	// Log streaming API (used by the launcher's remote backend mode)
+	mux.HandleFunc("GET /api/logs", s.handleLogs)
+	mux.HandleFunc("GET /api/logs/stream", s.handleLogsStream)
+	mux.HandleFunc("GET /api/i18n/languages", s.handleLanguages)
+	mux.HandleFunc("GET /api/i18n/{lang}", s.handleTranslations)
+	mux.HandleFunc("GET /api/presence", s.handlePresence)
+	mux.HandleFunc("POST /api/locks", s.handleAcquireLock)
+	mux.HandleFunc("DELETE /api/locks", s.handleReleaseLock)
+
+	// Extensions (compiled-in analyzers, checks and optimizers)
+	mux.HandleFunc("GET /api/extensions", s.handleListExtensions)
+	mux.HandleFunc("GET /api/extensions/analyzers/{name}/{mode}", s.handleRunAnalyzer)
+	mux.HandleFunc("GET /api/extensions/checks/{mode}", s.handleRunExtensionChecks)
+	mux.HandleFunc("POST /api/extensions/optimizers/{name}/{mode}", s.handleRunOptimizer)
+
+	// Scenario runner (scripted LGS flows)
+	mux.HandleFunc("POST /api/scenarios/run", s.handleRunScenario)
+
+	// Library self-test (launcher pre-flight and CI)
+	mux.HandleFunc("GET /api/selftest", s.handleSelfTest)
+
+	mux.HandleFunc("GET /api/openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("GET /api/docs", s.handleDocs)
+
+	s.setRoutes(mux)
 
 	// CORS middleware
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"X-Total-Count", lgs.ReplayedHeader},
 		AllowCredentials: true,
 	})
 
 	// Logging middleware
 	loggingHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Log all requests except WebSocket upgrades and high-frequency endpoints
-		if r.URL.Path != "/ws" && r.URL.Path != "/api/loader/status" {
+		if r.URL.Path != "/ws" && r.URL.Path != "/api/loader/status" && !strings.HasPrefix(r.URL.Path, "/api/logs") {
 			log.Printf("[HTTP] %s %s", r.Method, r.URL.Path)
 		}
-		c.Handler(mux).ServeHTTP(w, r)
+		c.Handler(s.auditMiddleware(s.presenceMiddleware(mux))).ServeHTTP(w, r)
 	})
+	s.handler.Store(&loggingHandler)
 
 	log.Printf("Starting LUT Explorer API server on %s", s.addr)
 	log.Printf("LGS endpoints available at /wallet/authenticate, /wallet/play, /wallet/end-round")
-	return http.ListenAndServe(s.addr, loggingHandler)
+	s.httpServer.Handler = loggingHandler
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown stops the server started with Start: it stops accepting
+// connections and waits for in-flight requests until ctx is done. Start
+// then returns http.ErrServerClosed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
 }
 
 // GetHandler returns the HTTP handler for use with custom servers (e.g., HTTPS).
 func (s *Server) GetHandler() http.Handler {
-	mux := http.NewServeMux()
+	mux := newRouteMux()
 
 	// API routes
 	mux.HandleFunc("GET /api/health", s.handleHealth)
+	mux.HandleFunc("GET /api/system", s.handleSystem)
 	mux.HandleFunc("GET /api/index", s.handleIndex)
 	mux.HandleFunc("GET /api/modes", s.handleModes)
+	mux.HandleFunc("POST /api/modes", s.handleAddMode)
+	mux.HandleFunc("DELETE /api/mode/{mode}", s.handleRemoveMode)
 	mux.HandleFunc("GET /api/mode/{mode}", s.handleMode)
 	mux.HandleFunc("GET /api/mode/{mode}/stats", s.handleModeStats)
 	mux.HandleFunc("GET /api/mode/{mode}/distribution", s.handleModeDistribution)
 	mux.HandleFunc("GET /api/mode/{mode}/distribution/bucket", s.handleModeBucketDistribution)
 	mux.HandleFunc("GET /api/mode/{mode}/outcomes", s.handleModeOutcomes)
+	mux.HandleFunc("GET /api/mode/{mode}/outcomes/stream", s.handleModeOutcomesStream)
+	mux.HandleFunc("PATCH /api/mode/{mode}/outcome/{simID}", s.handleSetOutcomeWeight)
 	mux.HandleFunc("GET /api/compare", s.handleCompare)
 
 	// Events API (lazy loading - only loads what's needed)
 	mux.HandleFunc("POST /api/mode/{mode}/events/load", s.handleLoadEvents)
 	mux.HandleFunc("DELETE /api/mode/{mode}/events", s.handleUnloadEvents)
 	mux.HandleFunc("DELETE /api/events", s.handleUnloadAllEvents)
+	mux.HandleFunc("GET /api/events/synthetic", s.handleSyntheticEventsStatus)
+	mux.HandleFunc("POST /api/events/synthetic", s.handleSyntheticEventsEnable)
+	mux.HandleFunc("DELETE /api/events/synthetic", s.handleSyntheticEventsDisable)
 	mux.HandleFunc("GET /api/mode/{mode}/events/range", s.handleGetEventsRange)
 	mux.HandleFunc("GET /api/mode/{mode}/events/stats", s.handleEventsStats)
+	mux.HandleFunc("POST /api/mode/{mode}/events/search", s.handleSearchEvents)
+	mux.HandleFunc("GET /api/mode/{mode}/events/frequency", s.handleEventFrequency)
 	mux.HandleFunc("GET /api/mode/{mode}/event/{simID}", s.handleGetEvent)
 
 	// Simulator API
 	mux.HandleFunc("POST /api/mode/{mode}/simulate", s.handleSimulate)
 	mux.HandleFunc("POST /api/mode/{mode}/simulate/quick", s.handleQuickSimulate)
+	mux.HandleFunc("GET /api/mode/{mode}/sample-size", s.handleSampleSize)
+	mux.HandleFunc("GET /api/mode/{mode}/sampling", s.handleSampling)
+	mux.HandleFunc("DELETE /api/mode/{mode}/sampling", s.handleResetSampling)
+	mux.HandleFunc("GET /api/mode/{mode}/par-sheet", s.handleParSheet)
+	mux.HandleFunc("GET /api/mode/{mode}/max-win", s.handleMaxWin)
+	mux.HandleFunc("GET /api/mode/{mode}/variants", s.handleListVariants)
+	mux.HandleFunc("POST /api/mode/{mode}/variants", s.handleCreateVariant)
+	mux.HandleFunc("DELETE /api/mode/{mode}/variants/{name}", s.handleDeleteVariant)
+	mux.HandleFunc("POST /api/mode/{mode}/whatif", s.handleWhatIf)
+	mux.HandleFunc("GET /api/mode/{mode}/sample-outcomes", s.handleSampleOutcomes)
+	mux.HandleFunc("POST /api/mode/{mode}/simulate/composite", s.handleCompositeSimulate)
+	mux.HandleFunc("POST /api/simulate/compare", s.handleCompareSimulations)
+	mux.HandleFunc("GET /api/simulations", s.handleListSimulations)
+	mux.HandleFunc("GET /api/simulations/{id}", s.handleGetSimulation)
+	mux.HandleFunc("DELETE /api/simulations/{id}", s.handleDeleteSimulation)
+
+	// Bookmarks (per-library notes and tags on simIDs)
+	mux.HandleFunc("GET /api/bookmarks", s.handleListBookmarks)
+	mux.HandleFunc("POST /api/bookmarks", s.handleAddBookmark)
+	mux.HandleFunc("PUT /api/bookmarks/{id}", s.handleUpdateBookmark)
+	mux.HandleFunc("DELETE /api/bookmarks/{id}", s.handleDeleteBookmark)
+	mux.HandleFunc("GET /api/mode/{mode}/export", s.handleExportMode)
+	mux.HandleFunc("GET /api/state/export", s.handleExportState)
+	mux.HandleFunc("POST /api/state/import", s.handleImportState)
 
 	// CrowdSim API
 	mux.HandleFunc("POST /api/crowdsim/{mode}/simulate", s.crowdsimHandlers.HandleSimulate)
@@ -221,44 +461,90 @@ func (s *Server) GetHandler() http.Handler {
 	mux.HandleFunc("POST /api/crowdsim/{mode}/volatility-check", s.crowdsimHandlers.HandleVolatilityCheck)
 
 	// Optimizer API
-	s.optimizerHandlers.RegisterRoutes(mux)
+	s.optimizerHandlers.RegisterRoutes(mux.ServeMux)
 
 	// Convex Optimizer API (if enabled)
 	if s.convexoptHandlers != nil {
-		s.convexoptHandlers.RegisterRoutes(mux)
+		s.convexoptHandlers.RegisterRoutes(mux.ServeMux)
 	}
 
 	// LGS (Local Game Server) - RGS-compatible endpoints
-	mux.HandleFunc("POST /wallet/authenticate", s.lgsHandlers.Authenticate)
-	mux.HandleFunc("POST /wallet/play", s.lgsHandlers.Play)
-	mux.HandleFunc("POST /wallet/end-round", s.lgsHandlers.EndRound)
+	// Wallet and bet traffic can be recorded to and replayed from cassettes,
+	// and disrupted by injected faults
+	tape := func(next http.HandlerFunc) http.HandlerFunc {
+		return s.lgsHandlers.Faulty(s.lgsHandlers.Taped(next))
+	}
+	mux.HandleFunc("POST /wallet/authenticate", tape(s.lgsHandlers.Authenticate))
+	mux.HandleFunc("POST /wallet/play", tape(s.lgsHandlers.Idempotent(s.lgsHandlers.Play)))
+	mux.HandleFunc("POST /wallet/end-round", tape(s.lgsHandlers.Idempotent(s.lgsHandlers.EndRound)))
 
 	// Bet endpoints
-	mux.HandleFunc("POST /bet/event", s.lgsHandlers.Event)
-	mux.HandleFunc("GET /bet/replay/{game}/{version}/{mode}/{event}", s.lgsHandlers.Replay)
+	mux.HandleFunc("POST /bet/event", tape(s.lgsHandlers.Event))
+	mux.HandleFunc("GET /bet/replay/{game}/{version}/{mode}/{event}", tape(s.lgsHandlers.Replay))
 
 	// Additional LGS utility endpoints
 	mux.HandleFunc("GET /lgs/health", s.lgsHandlers.Health)
 	mux.HandleFunc("GET /lgs/sessions", s.lgsHandlers.Sessions)
 	mux.HandleFunc("POST /lgs/batchplay", s.lgsHandlers.BatchPlay)
+	mux.HandleFunc("POST /lgs/batchplay/cancel", s.lgsHandlers.CancelBatchPlay)
 	mux.HandleFunc("POST /lgs/history", s.lgsHandlers.History)
 	mux.HandleFunc("DELETE /lgs/history", s.lgsHandlers.ClearHistory)
 	mux.HandleFunc("GET /lgs/stats", s.lgsHandlers.Stats)
+	mux.HandleFunc("GET /lgs/stats/timeseries", s.lgsHandlers.StatsTimeseries)
 	mux.HandleFunc("DELETE /lgs/stats", s.lgsHandlers.ClearStats)
 	mux.HandleFunc("POST /lgs/reset-balance", s.lgsHandlers.ResetBalance)
 	mux.HandleFunc("POST /lgs/set-balance", s.lgsHandlers.SetBalance)
 	mux.HandleFunc("POST /lgs/force-outcome", s.lgsHandlers.ForceOutcome)
+	mux.HandleFunc("POST /lgs/force-outcome/by-criteria", s.lgsHandlers.ForceOutcomeByCriteria)
 	mux.HandleFunc("GET /lgs/force-outcome", s.lgsHandlers.GetForcedOutcomes)
 	mux.HandleFunc("DELETE /lgs/force-outcome", s.lgsHandlers.ClearForcedOutcome)
+	mux.HandleFunc("POST /lgs/scenario", s.lgsHandlers.SetScenario)
+	mux.HandleFunc("GET /lgs/scenario", s.lgsHandlers.GetScenario)
+	mux.HandleFunc("DELETE /lgs/scenario", s.lgsHandlers.ClearScenario)
+	mux.HandleFunc("POST /lgs/record/start", s.lgsHandlers.StartRecording)
+	mux.HandleFunc("POST /lgs/record/stop", s.lgsHandlers.StopRecording)
+	mux.HandleFunc("POST /lgs/record/replay", s.lgsHandlers.ReplayRecording)
+	mux.HandleFunc("POST /lgs/config/bet-levels", s.lgsHandlers.SetBetLevels)
+	mux.HandleFunc("GET /lgs/config/bet-levels", s.lgsHandlers.GetBetLevels)
+	mux.HandleFunc("DELETE /lgs/config/bet-levels", s.lgsHandlers.ClearBetLevels)
+	mux.HandleFunc("GET /lgs/config/currencies", s.lgsHandlers.GetCurrencies)
+	mux.HandleFunc("POST /lgs/config/currencies", s.lgsHandlers.SetCurrencies)
+	mux.HandleFunc("DELETE /lgs/config/currencies", s.lgsHandlers.ResetCurrencies)
+	mux.HandleFunc("GET /lgs/config/jackpots", s.lgsHandlers.GetJackpots)
+	mux.HandleFunc("POST /lgs/config/jackpots", s.lgsHandlers.SetJackpots)
+	mux.HandleFunc("DELETE /lgs/config/jackpots", s.lgsHandlers.ClearJackpots)
+	mux.HandleFunc("POST /lgs/jackpots/reset", s.lgsHandlers.ResetJackpots)
+	mux.HandleFunc("GET /lgs/faults", s.lgsHandlers.GetFaults)
+	mux.HandleFunc("POST /lgs/faults", s.lgsHandlers.SetFaults)
+	mux.HandleFunc("DELETE /lgs/faults", s.lgsHandlers.ClearFaults)
 	mux.HandleFunc("POST /lgs/rtp-bias", s.lgsHandlers.SetRTPBias)
 	mux.HandleFunc("GET /lgs/rtp-bias", s.lgsHandlers.GetRTPBias)
+	mux.HandleFunc("POST /lgs/variant", s.lgsHandlers.SetVariant)
+	mux.HandleFunc("GET /lgs/variant", s.lgsHandlers.GetVariants)
+	mux.HandleFunc("GET /lgs/presets", s.lgsHandlers.Presets)
+	mux.HandleFunc("POST /lgs/presets/apply", s.lgsHandlers.ApplyPreset)
+	mux.HandleFunc("GET /lgs/transforms", s.lgsHandlers.Transforms)
+	mux.HandleFunc("PUT /lgs/transforms", s.lgsHandlers.SetTransforms)
+	mux.HandleFunc("GET /lgs/cassettes", s.lgsHandlers.Cassettes)
+	mux.HandleFunc("POST /lgs/cassettes/record", s.lgsHandlers.RecordCassette)
+	mux.HandleFunc("POST /lgs/cassettes/replay", s.lgsHandlers.ReplayCassette)
+	mux.HandleFunc("POST /lgs/cassettes/stop", s.lgsHandlers.StopCassette)
+	mux.HandleFunc("GET /lgs/cassettes/{name}", s.lgsHandlers.GetCassette)
+	mux.HandleFunc("DELETE /lgs/cassettes/{name}", s.lgsHandlers.DeleteCassette)
 
 	// WebSocket endpoint
 	mux.HandleFunc("GET /ws", s.wsHub.ServeWs)
 
 	// Compliance API
 	mux.HandleFunc("GET /api/mode/{mode}/compliance", s.handleModeCompliance)
+	mux.HandleFunc("GET /api/mode/{mode}/compliance/report", s.handleComplianceReport)
 	mux.HandleFunc("GET /api/compliance", s.handleAllCompliance)
+	mux.HandleFunc("GET /api/compliance/profiles", s.handleComplianceProfiles)
+
+	// Data directory history
+	mux.HandleFunc("GET /api/history/optimizer", s.handleOptimizerHistory)
+	mux.HandleFunc("GET /api/history/compliance", s.handleComplianceHistory)
+	mux.HandleFunc("GET /api/audit", s.handleAudit)
 
 	// Background loader API
 	mux.HandleFunc("GET /api/loader/status", s.handleLoaderStatus)
@@ -268,26 +554,66 @@ func (s *Server) GetHandler() http.Handler {
 	mux.HandleFunc("GET /api/loader/priority", s.handleLoaderPriority)
 	mux.HandleFunc("POST /api/reload", s.handleReload)
 
+	// Library API (open/close a library without restarting)
+	mux.HandleFunc("GET /api/library", s.handleLibrary)
+	mux.HandleFunc("POST /api/library/open", s.handleLibraryOpen)
+	mux.HandleFunc("POST /api/library/close", s.handleLibraryClose)
+	mux.HandleFunc("POST /api/library/switch", s.handleLibrarySwitch)
+	mux.HandleFunc("GET /api/library/recent", s.handleRecentLibraries)
+	mux.HandleFunc("DELETE /api/library/recent", s.handleForgetLibrary)
+
 	// CSV Watcher API
 	mux.HandleFunc("GET /api/watcher/status", s.handleWatcherStatus)
 	mux.HandleFunc("POST /api/watcher/enable", s.handleWatcherEnable)
 	mux.HandleFunc("DELETE /api/watcher/enable", s.handleWatcherDisable)
+	mux.HandleFunc("GET /api/watcher/events", s.handleWatcherEvents)
+	mux.HandleFunc("POST /api/watcher/rescan", s.handleWatcherRescan)
+	mux.HandleFunc("POST /api/watcher/mode/{mode}/enable", s.handleWatcherModeEnable)
+	mux.HandleFunc("DELETE /api/watcher/mode/{mode}/enable", s.handleWatcherModeDisable)
+
+	// Log streaming API (used by the launcher's remote backend mode)
+	mux.HandleFunc("GET /api/logs", s.handleLogs)
+	mux.HandleFunc("GET /api/logs/stream", s.handleLogsStream)
+	mux.HandleFunc("GET /api/i18n/languages", s.handleLanguages)
+	mux.HandleFunc("GET /api/i18n/{lang}", s.handleTranslations)
+	mux.HandleFunc("GET /api/presence", s.handlePresence)
+	mux.HandleFunc("POST /api/locks", s.handleAcquireLock)
+	mux.HandleFunc("DELETE /api/locks", s.handleReleaseLock)
+
+	// Extensions (compiled-in analyzers, checks and optimizers)
+	mux.HandleFunc("GET /api/extensions", s.handleListExtensions)
+	mux.HandleFunc("GET /api/extensions/analyzers/{name}/{mode}", s.handleRunAnalyzer)
+	mux.HandleFunc("GET /api/extensions/checks/{mode}", s.handleRunExtensionChecks)
+	mux.HandleFunc("POST /api/extensions/optimizers/{name}/{mode}", s.handleRunOptimizer)
+
+	// Scenario runner (scripted LGS flows)
+	mux.HandleFunc("POST /api/scenarios/run", s.handleRunScenario)
+
+	// Library self-test (launcher pre-flight and CI)
+	mux.HandleFunc("GET /api/selftest", s.handleSelfTest)
+
+	mux.HandleFunc("GET /api/openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("GET /api/docs", s.handleDocs)
+
+	s.setRoutes(mux)
 
 	// CORS middleware
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"X-Total-Count", lgs.ReplayedHeader},
 		AllowCredentials: true,
 	})
 
 	// Logging middleware
 	loggingHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/ws" && r.URL.Path != "/api/loader/status" {
+		if r.URL.Path != "/ws" && r.URL.Path != "/api/loader/status" && !strings.HasPrefix(r.URL.Path, "/api/logs") {
 			log.Printf("[HTTP] %s %s", r.Method, r.URL.Path)
 		}
-		c.Handler(mux).ServeHTTP(w, r)
+		c.Handler(s.auditMiddleware(s.presenceMiddleware(mux))).ServeHTTP(w, r)
 	})
+	s.handler.Store(&loggingHandler)
 
 	return loggingHandler
 }
@@ -297,15 +623,67 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, map[string]string{"status": "ok"})
 }
 
+// handleSystem reports runtime and process information for diagnostics.
+func (s *Server) handleSystem(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	modes := []string{}
+	if index := s.loader.GetIndex(); index != nil {
+		for _, m := range index.Modes {
+			modes = append(modes, m.Name)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	common.WriteSuccess(w, map[string]any{
+		"go_version":     runtime.Version(),
+		"os":             runtime.GOOS,
+		"arch":           runtime.GOARCH,
+		"num_cpu":        runtime.NumCPU(),
+		"num_goroutine":  runtime.NumGoroutine(),
+		"pid":            os.Getpid(),
+		"hostname":       hostname,
+		"args":           os.Args[1:],
+		"started_at":     s.startedAt.Format(time.RFC3339),
+		"uptime_seconds": int64(time.Since(s.startedAt).Seconds()),
+		"library":        s.loader.LibraryDir(),
+		"modes":          modes,
+		"watcher":        s.csvWatcher.Load() != nil,
+		"convex":         s.convexoptHandlers != nil,
+		"ws_clients":     s.wsHub.ClientCount(),
+		"stats_cache":    s.loader.StatsCache().Metrics(),
+		"memory": map[string]any{
+			"alloc_bytes":       mem.Alloc,
+			"heap_inuse_bytes":  mem.HeapInuse,
+			"sys_bytes":         mem.Sys,
+			"num_gc":            mem.NumGC,
+			"gc_pause_total_ns": mem.PauseTotalNs,
+		},
+	})
+}
+
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	index := s.loader.GetIndex()
 	if index == nil {
-		common.WriteError(w, http.StatusInternalServerError, "index not loaded")
+		common.WriteError(w, http.StatusNotFound, "no library open")
 		return
 	}
 
 	info := IndexInfo{
 		Modes: s.loader.GetModeSummaries(),
+		Size:  s.loader.LibrarySize(),
+	}
+
+	// Replace load time estimates with measured ones where books were loaded
+	if s.bgLoader != nil {
+		status := s.bgLoader.GetStatus()
+		for i := range info.Size.Modes {
+			ms, ok := status[info.Size.Modes[i].Mode]
+			if ok && ms.CompletedAt > 0 && ms.StartedAt > 0 {
+				info.Size.Modes[i].LoadMs = ms.CompletedAt - ms.StartedAt
+			}
+		}
 	}
 
 	common.WriteSuccess(w, info)
@@ -316,6 +694,78 @@ func (s *Server) handleModes(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, summaries)
 }
 
+// handleAddMode registers a mode from a ModeConfig body without a full
+// reload. Only that mode's events are queued in the background loader.
+func (s *Server) handleAddMode(w http.ResponseWriter, r *http.Request) {
+	var config stakergs.ModeConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	if err := s.loader.AddMode(config); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	table, err := s.loader.GetMode(config.Name)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if s.bgLoader != nil {
+		if err := s.bgLoader.AddMode(table.Mode); err != nil {
+			log.Printf("Warning: Failed to register mode %s: %v", table.Mode, err)
+		}
+	}
+	s.modesChanged([]string{table.Mode}, nil)
+
+	common.WriteSuccess(w, lut.ModeSummary{
+		Mode:      table.Mode,
+		Cost:      table.Cost,
+		Outcomes:  len(table.Outcomes),
+		RTP:       table.RTP(),
+		HitRate:   table.HitRate(),
+		MaxPayout: float64(table.MaxPayout()) / 100.0,
+	})
+}
+
+// handleRemoveMode unregisters a mode without a full reload.
+func (s *Server) handleRemoveMode(w http.ResponseWriter, r *http.Request) {
+	name, err := s.loader.RemoveMode(r.PathValue("mode"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if s.bgLoader != nil {
+		s.bgLoader.RemoveMode(name)
+	}
+	s.modesChanged(nil, []string{name})
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"removed": name,
+	})
+}
+
+// modesChanged keeps the CSV watcher in step with runtime mode changes and
+// tells clients, with the same message the watcher sends for index.json.
+func (s *Server) modesChanged(added, removed []string) {
+	if fw := s.csvWatcher.Load(); fw != nil {
+		fw.SetFiles(s.loader.GetCSVFiles())
+	}
+	if s.wsHub != nil {
+		s.wsHub.Broadcast(ws.Message{
+			Type: ws.MsgIndexReloaded,
+			Payload: map[string]any{
+				"added":   added,
+				"removed": removed,
+				"message": "Modes changed",
+			},
+		})
+	}
+}
+
 func (s *Server) handleMode(w http.ResponseWriter, r *http.Request) {
 	mode := r.PathValue("mode")
 	if mode == "" {
@@ -353,7 +803,7 @@ func (s *Server) handleModeStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stats := s.loader.Analyzer().Analyze(table)
+	stats := s.loader.Statistics(table)
 
 	// Start background generation of distribution cache for faster bucket queries
 	cache := s.loader.DistributionCache()
@@ -364,6 +814,78 @@ func (s *Server) handleModeStats(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, stats)
 }
 
+// handleSetOutcomeWeight changes the weight of one outcome, recomputes the
+// mode's statistics and broadcasts them, so open views follow live edits.
+// Body: {"weight": 12, "save": false}; save also writes the table to disk.
+func (s *Server) handleSetOutcomeWeight(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	simID, err := strconv.Atoi(r.PathValue("simID"))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid simID")
+		return
+	}
+
+	var req struct {
+		Weight *uint64 `json:"weight"`
+		Save   bool    `json:"save"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Weight == nil {
+		common.WriteError(w, http.StatusBadRequest, "weight required")
+		return
+	}
+
+	if _, err := s.loader.GetMode(mode); err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	table, oldWeight, err := s.loader.SetOutcomeWeight(mode, simID, *req.Weight)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Save {
+		weights := make([]uint64, len(table.Outcomes))
+		for i, o := range table.Outcomes {
+			weights[i] = o.Weight
+		}
+		if err := s.loader.SaveWeights(mode, weights); err != nil {
+			common.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	stats := s.loader.Statistics(table)
+	update := map[string]interface{}{
+		"sim_id":       simID,
+		"weight":       *req.Weight,
+		"old_weight":   oldWeight,
+		"saved":        req.Save,
+		"total_weight": stats.TotalWeight,
+		"rtp":          stats.RTP,
+		"hit_rate":     stats.HitRate,
+		"volatility":   stats.Volatility,
+	}
+	s.wsHub.Broadcast(ws.Message{
+		Type:    ws.MsgTableUpdated,
+		Mode:    table.Mode,
+		Payload: update,
+	})
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"sim_id":     simID,
+		"weight":     *req.Weight,
+		"old_weight": oldWeight,
+		"saved":      req.Save,
+		"stats":      stats,
+	})
+}
+
 func (s *Server) handleModeDistribution(w http.ResponseWriter, r *http.Request) {
 	mode := r.PathValue("mode")
 	if mode == "" {
@@ -377,10 +899,7 @@ func (s *Server) handleModeDistribution(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	totalWeight := table.TotalWeight()
-	distribution := s.loader.Analyzer().BuildDistribution(table, totalWeight)
-
-	common.WriteSuccess(w, distribution)
+	common.WriteSuccess(w, s.loader.Statistics(table).Distribution)
 }
 
 func (s *Server) handleModeBucketDistribution(w http.ResponseWriter, r *http.Request) {
@@ -428,8 +947,7 @@ func (s *Server) handleModeBucketDistribution(w http.ResponseWriter, r *http.Req
 
 	// Not in cache - generate synchronously for first request, then cache
 	// Get buckets to build the cache
-	totalWeight := table.TotalWeight()
-	buckets := s.loader.Analyzer().BuildPayoutBuckets(table, totalWeight)
+	buckets := s.loader.Statistics(table).PayoutBuckets
 
 	// Generate cache (synchronously for first request)
 	cache.Generate(mode, table, buckets)
@@ -447,7 +965,7 @@ func (s *Server) handleModeBucketDistribution(w http.ResponseWriter, r *http.Req
 		Offset:     offset,
 		Limit:      limit,
 	}
-	result := s.loader.Analyzer().GetBucketDistribution(table, totalWeight, req)
+	result := s.loader.Analyzer().GetBucketDistribution(table, table.TotalWeight(), req)
 	common.WriteSuccess(w, result)
 }
 
@@ -486,6 +1004,100 @@ func (s *Server) handleModeOutcomes(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, outcomes)
 }
 
+// outcomesStreamChunk is how many rows are written between flushes.
+const outcomesStreamChunk = 5000
+
+// handleModeOutcomesStream streams outcomes as NDJSON, one outcome per line,
+// sorted server-side (?sort=sim_id|payout|weight|probability&order=asc|desc).
+// ?offset and ?limit select a window. Rows are flushed in chunks so clients
+// can render the first rows before the whole table has been sent; the total
+// row count is in the X-Total-Count header.
+func (s *Server) handleModeOutcomesStream(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	order := q.Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		common.WriteError(w, http.StatusBadRequest, "order must be asc or desc")
+		return
+	}
+	offset, limit := 0, 0
+	if v := q.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			common.WriteError(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			common.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	outcomes, err := lut.SortOutcomes(table, q.Get("sort"), order == "desc")
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	total := len(outcomes)
+	if offset > total {
+		offset = total
+	}
+	outcomes = outcomes[offset:]
+	if limit > 0 && limit < len(outcomes) {
+		outcomes = outcomes[:limit]
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	flusher, _ := w.(http.Flusher)
+
+	totalWeight := float64(table.TotalWeight())
+	buf := make([]byte, 0, 128)
+	for i, o := range outcomes {
+		probability := 0.0
+		if totalWeight > 0 {
+			probability = float64(o.Weight) / totalWeight
+		}
+		buf = append(buf[:0], `{"sim_id":`...)
+		buf = strconv.AppendInt(buf, int64(o.SimID), 10)
+		buf = append(buf, `,"weight":`...)
+		buf = strconv.AppendUint(buf, o.Weight, 10)
+		buf = append(buf, `,"payout":`...)
+		buf = strconv.AppendFloat(buf, float64(o.Payout)/100.0, 'f', -1, 64)
+		buf = append(buf, `,"probability":`...)
+		buf = strconv.AppendFloat(buf, probability, 'g', -1, 64)
+		buf = append(buf, "}\n"...)
+		if _, err := w.Write(buf); err != nil {
+			return
+		}
+
+		if (i+1)%outcomesStreamChunk == 0 {
+			if r.Context().Err() != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
 // CompareResponse contains comparison data for multiple modes.
 // FailedMode contains information about a mode that failed to load.
 type FailedMode struct {
@@ -532,7 +1144,7 @@ func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		stats := s.loader.Analyzer().Analyze(table)
+		stats := s.loader.Statistics(table)
 		items = append(items, CompareItem{
 			Mode:              modeName,
 			Cost:              stats.Cost,
@@ -631,10 +1243,84 @@ func (s *Server) handleEventsStats(w http.ResponseWriter, r *http.Request) {
 	if chunkStats != nil {
 		stats["chunk_cache"] = chunkStats
 	}
+	if storeStats := eventsLoader.GetStoreStats(mode); storeStats != nil {
+		stats["disk_store"] = storeStats
+	}
 
 	common.WriteSuccess(w, stats)
 }
 
+// handleSearchEvents finds books matching JSON-path predicates and an
+// optional payout band. Uses loaded events when available, otherwise
+// streams the events file.
+func (s *Server) handleSearchEvents(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	config, err := s.loader.GetModeConfig(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if config.Events == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode has no events file")
+		return
+	}
+
+	var query lut.EventQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := query.Validate(); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.loader.EventsLoader().SearchEvents(mode, table, config.Events, &query)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, result)
+}
+
+// handleEventFrequency reports counts and weighted probabilities of each
+// event type across all books of a mode.
+func (s *Server) handleEventFrequency(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	config, err := s.loader.GetModeConfig(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if config.Events == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode has no events file")
+		return
+	}
+
+	report, err := s.loader.EventsLoader().EventFrequency(mode, table, config.Events)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, report)
+}
+
 // handleGetEventsRange returns events for a specific line range.
 // Query params: start (required), end (required)
 // Example: /api/mode/base/events/range?start=100&end=200
@@ -734,9 +1420,27 @@ func (s *Server) handleGetEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Modes without events get a generated payout-only book when enabled
+	writeSynthetic := func() {
+		common.WriteSuccess(w, map[string]any{
+			"sim_id":        outcome.SimID,
+			"weight":        outcome.Weight,
+			"payout":        outcome.Payout,
+			"probability":   outcome.Probability,
+			"odds":          outcome.Odds,
+			"event":         lut.SyntheticBook(outcome.SimID, uint(math.Round(outcome.Payout*100))),
+			"events_loaded": false,
+			"synthetic":     true,
+		})
+	}
+
 	// Get mode config to find events file
 	config, err := s.loader.GetModeConfig(mode)
 	if err != nil || config.Events == "" {
+		if s.loader.SyntheticEvents() {
+			writeSynthetic()
+			return
+		}
 		// No events file configured, return outcome stats only
 		common.WriteSuccess(w, map[string]any{
 			"sim_id":        outcome.SimID,
@@ -764,6 +1468,10 @@ func (s *Server) handleGetEvent(w http.ResponseWriter, r *http.Request) {
 	} else {
 		// Use lazy loading - only loads a small chunk around the requested event
 		event, err = eventsLoader.GetEventLazy(mode, config.Events, simID, table.SimIDOffset)
+		if err != nil && s.loader.SyntheticEvents() {
+			writeSynthetic()
+			return
+		}
 		if err != nil {
 			// Lazy loading failed, return outcome stats without event
 			common.WriteSuccess(w, map[string]any{
@@ -806,6 +1514,17 @@ type SimulateRequest struct {
 	TargetRTP   float64   `json:"target_rtp"`
 	TestSpins   []int     `json:"test_spins"`
 	TestWeights []float64 `json:"test_weights"`
+	Seed        *int64    `json:"seed,omitempty"` // omit for a random seed; echoed in the result
+	Workers     int       `json:"workers"`        // parallel trial workers (0 = one per CPU)
+	// RTPTolerance and Confidence drive the sample-size estimate and CIs (0 = defaults)
+	RTPTolerance  float64 `json:"rtp_tolerance"`
+	Confidence    float64 `json:"confidence"`
+	HistogramBins int     `json:"histogram_bins"` // bins for per-trial RTP distributions (default 20)
+	// BetSchedule optionally varies the bet per spin (cycled levels or a win progression)
+	BetSchedule *lut.BetSchedule `json:"bet_schedule,omitempty"`
+	// Weights optionally replaces the table's weights for this run only
+	// (one per outcome, in table order), e.g. to verify optimizer output
+	Weights []uint64 `json:"weights,omitempty"`
 }
 
 // handleSimulate runs a full simulation with multiple trials.
@@ -828,6 +1547,20 @@ func (s *Server) handleSimulate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(req.Weights) > 0 {
+		table, err = lut.WithWeights(table, req.Weights)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if req.BetSchedule != nil {
+		if err := req.BetSchedule.Validate(); err != nil {
+			common.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	// Validate and set defaults
 	if req.Spins <= 0 {
 		req.Spins = common.DefaultSpins
@@ -854,26 +1587,46 @@ func (s *Server) handleSimulate(w http.ResponseWriter, r *http.Request) {
 		bet = 1.0
 	}
 
-	config := lut.SimulationConfig{
-		Spins:       req.Spins,
-		Trials:      req.Trials,
-		Bet:         bet,
-		TargetRTP:   req.TargetRTP,
-		TestSpins:   req.TestSpins,
-		TestWeights: req.TestWeights,
+	simulator := s.loader.Simulator()
+	seed := simulator.NewSeed()
+	if req.Seed != nil {
+		seed = *req.Seed
 	}
 
-	result := s.loader.Simulator().RunSimulation(table, config)
+	config := lut.SimulationConfig{
+		Spins:         req.Spins,
+		Trials:        req.Trials,
+		Bet:           bet,
+		TargetRTP:     req.TargetRTP,
+		TestSpins:     req.TestSpins,
+		TestWeights:   req.TestWeights,
+		Seed:          seed,
+		Workers:       req.Workers,
+		RTPTolerance:  req.RTPTolerance,
+		Confidence:    req.Confidence,
+		HistogramBins: req.HistogramBins,
+		BetSchedule:   req.BetSchedule,
+	}
+
+	result := simulator.RunSimulation(table, config)
+	result.CustomWeights = len(req.Weights) > 0
+	s.storeSimulation("full", req, result)
 	common.WriteSuccess(w, result)
 }
 
-// QuickSimulateRequest holds the request body for quick simulation.
-type QuickSimulateRequest struct {
-	Spins int `json:"spins"`
+// CompositeSimulateRequest holds the request body for base+feature simulation.
+type CompositeSimulateRequest struct {
+	Spins   int    `json:"spins"`
+	Trials  int    `json:"trials"`
+	Seed    *int64 `json:"seed,omitempty"` // omit for a random seed; echoed in the result
+	Workers int    `json:"workers"`
+	// Triggers overrides the "triggers" listed for the mode in index.json
+	Triggers []stakergs.FeatureTrigger `json:"triggers,omitempty"`
 }
 
-// handleQuickSimulate runs a quick single-trial simulation with spin-by-spin results.
-func (s *Server) handleQuickSimulate(w http.ResponseWriter, r *http.Request) {
+// handleCompositeSimulate simulates the whole game: the base mode plus the
+// feature modes its trigger outcomes start, with RTP split by base vs feature.
+func (s *Server) handleCompositeSimulate(w http.ResponseWriter, r *http.Request) {
 	mode := r.PathValue("mode")
 	if mode == "" {
 		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
@@ -886,28 +1639,987 @@ func (s *Server) handleQuickSimulate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req QuickSimulateRequest
+	var req CompositeSimulateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
 		return
 	}
 
-	// Validate and set defaults
-	if req.Spins <= 0 {
-		req.Spins = 100
+	if len(req.Triggers) == 0 {
+		if modeConfig, err := s.loader.GetModeConfig(mode); err == nil {
+			req.Triggers = modeConfig.Triggers
+		}
 	}
-	if req.Spins > 10000 {
-		req.Spins = 10000
+	if len(req.Triggers) == 0 {
+		common.WriteError(w, http.StatusBadRequest, "no triggers given and none listed for this mode in index.json")
+		return
 	}
 
-	// Use mode cost as bet
-	bet := table.Cost
-	if bet <= 0 {
-		bet = 1.0
-	}
+	features := make(map[string]*stakergs.LookupTable)
+	for _, t := range req.Triggers {
+		if _, ok := features[t.Mode]; ok {
+			continue
+		}
+		feature, err := s.loader.GetMode(t.Mode)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("feature mode %q: %v", t.Mode, err))
+			return
+		}
+		features[t.Mode] = feature
+	}
+
+	// Validate and set defaults
+	if req.Spins <= 0 {
+		req.Spins = common.DefaultSpins
+	}
+	if req.Spins > common.MaxSpins {
+		req.Spins = common.MaxSpins
+	}
+	if req.Trials <= 0 {
+		req.Trials = common.DefaultTrials
+	}
+	if req.Trials > common.MaxTrials {
+		req.Trials = common.MaxTrials
+	}
+
+	// Use mode cost as bet
+	bet := table.Cost
+	if bet <= 0 {
+		bet = 1.0
+	}
+
+	simulator := s.loader.Simulator()
+	seed := simulator.NewSeed()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	result, err := simulator.RunCompositeSimulation(table, features, lut.CompositeConfig{
+		Spins:    req.Spins,
+		Trials:   req.Trials,
+		Bet:      bet,
+		Seed:     seed,
+		Workers:  req.Workers,
+		Triggers: req.Triggers,
+	})
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	common.WriteSuccess(w, result)
+}
+
+// CompareVariant is one side of a simulation comparison: a mode, optionally
+// with candidate weights.
+type CompareVariant struct {
+	Label   string   `json:"label,omitempty"` // defaults to the mode name (plus index for duplicates)
+	Mode    string   `json:"mode"`
+	Weights []uint64 `json:"weights,omitempty"`
+}
+
+// CompareRequest holds the request body for comparing simulations.
+type CompareRequest struct {
+	Variants []CompareVariant `json:"variants"`
+	Spins    int              `json:"spins"`
+	Trials   int              `json:"trials"`
+	Seed     *int64           `json:"seed,omitempty"` // shared by all variants
+	Workers  int              `json:"workers"`
+}
+
+// CompareVariantResult holds one variant's per-trial values, aligned by trial index.
+type CompareVariantResult struct {
+	Label         string    `json:"label"`
+	Mode          string    `json:"mode"`
+	CustomWeights bool      `json:"custom_weights,omitempty"`
+	ActualRTP     float64   `json:"actual_rtp"`
+	HitRate       float64   `json:"hit_rate"`
+	TrialRTPs     []float64 `json:"trial_rtps"`
+	TrialHitRates []float64 `json:"trial_hit_rates"`
+}
+
+// CompareTest holds the statistical tests between two variants.
+type CompareTest struct {
+	A       string               `json:"a"`
+	B       string               `json:"b"`
+	RTP     lut.SampleComparison `json:"rtp"`
+	HitRate lut.SampleComparison `json:"hit_rate"`
+}
+
+// maxCompareVariants bounds the work a single compare request can ask for.
+const maxCompareVariants = 8
+
+// handleCompareSimulations simulates several modes (or weight sets) with the
+// same seed and spin counts and tests every pair for differences in RTP and
+// hit rate. The shared seed correlates the variants' trials, which makes the
+// independent-sample tests conservative.
+func (s *Server) handleCompareSimulations(w http.ResponseWriter, r *http.Request) {
+	var req CompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if len(req.Variants) < 2 || len(req.Variants) > maxCompareVariants {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("between 2 and %d variants required", maxCompareVariants))
+		return
+	}
+
+	// Validate and set defaults
+	if req.Spins <= 0 {
+		req.Spins = common.DefaultSpins
+	}
+	if req.Spins > common.MaxSpins {
+		req.Spins = common.MaxSpins
+	}
+	if req.Trials <= 0 {
+		req.Trials = common.DefaultTrials
+	}
+	if req.Trials > common.MaxTrials {
+		req.Trials = common.MaxTrials
+	}
+
+	simulator := s.loader.Simulator()
+	seed := simulator.NewSeed()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	seen := make(map[string]bool)
+	variants := make([]CompareVariantResult, len(req.Variants))
+	for i, v := range req.Variants {
+		table, err := s.loader.GetMode(v.Mode)
+		if err != nil {
+			common.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if len(v.Weights) > 0 {
+			if table, err = lut.WithWeights(table, v.Weights); err != nil {
+				common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("variant %d: %v", i+1, err))
+				return
+			}
+		}
+
+		label := v.Label
+		if label == "" {
+			label = v.Mode
+		}
+		if seen[label] {
+			label = fmt.Sprintf("%s #%d", label, i+1)
+		}
+		seen[label] = true
+
+		bet := table.Cost
+		if bet <= 0 {
+			bet = 1.0
+		}
+		result := simulator.RunSimulation(table, lut.SimulationConfig{
+			Spins:   req.Spins,
+			Trials:  req.Trials,
+			Bet:     bet,
+			Seed:    seed,
+			Workers: req.Workers,
+		})
+
+		variants[i] = CompareVariantResult{
+			Label:         label,
+			Mode:          table.Mode,
+			CustomWeights: len(v.Weights) > 0,
+			ActualRTP:     result.ActualRTP,
+			HitRate:       result.HitRate,
+			TrialRTPs:     result.TrialRTPs,
+			TrialHitRates: result.TrialHitRates,
+		}
+	}
+
+	var tests []CompareTest
+	for i := 0; i < len(variants); i++ {
+		for j := i + 1; j < len(variants); j++ {
+			tests = append(tests, CompareTest{
+				A:       variants[i].Label,
+				B:       variants[j].Label,
+				RTP:     lut.CompareSamples("rtp", variants[i].TrialRTPs, variants[j].TrialRTPs),
+				HitRate: lut.CompareSamples("hit_rate", variants[i].TrialHitRates, variants[j].TrialHitRates),
+			})
+		}
+	}
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"seed":     seed,
+		"spins":    req.Spins,
+		"trials":   req.Trials,
+		"variants": variants,
+		"tests":    tests,
+	})
+}
+
+// handleSampleSize returns how many spins are needed to verify a mode's RTP
+// to within ?tolerance (e.g. 0.001 = 0.1pp) at ?confidence (e.g. 0.95).
+func (s *Server) handleSampleSize(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var tolerance, confidence float64
+	if v := r.URL.Query().Get("tolerance"); v != "" {
+		if tolerance, err = strconv.ParseFloat(v, 64); err != nil || tolerance <= 0 {
+			common.WriteError(w, http.StatusBadRequest, "tolerance must be a positive number")
+			return
+		}
+	}
+	if v := r.URL.Query().Get("confidence"); v != "" {
+		if confidence, err = strconv.ParseFloat(v, 64); err != nil || confidence <= 0 || confidence >= 1 {
+			common.WriteError(w, http.StatusBadRequest, "confidence must be between 0 and 1")
+			return
+		}
+	}
+
+	common.WriteSuccess(w, lut.EstimateSampleSize(table, tolerance, confidence))
+}
+
+// handleSampling reports how the LGS sampler has covered a mode's book so
+// far and whether its draws fit the table weights.
+// Query params: top (most drawn simIDs to list, default 20)
+func (s *Server) handleSampling(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	top := 20
+	if v := r.URL.Query().Get("top"); v != "" {
+		if top, err = strconv.Atoi(v); err != nil || top < 0 {
+			common.WriteError(w, http.StatusBadRequest, "top must be a non-negative integer")
+			return
+		}
+	}
+
+	counts, skipped := s.lgsHandlers.Sampling().Counts(table.Mode)
+	report := lut.AnalyzeSampling(table, counts, top)
+	report.SkippedDraws = skipped
+
+	common.WriteSuccess(w, report)
+}
+
+// handleResetSampling clears the LGS draw counts of a mode.
+func (s *Server) handleResetSampling(w http.ResponseWriter, r *http.Request) {
+	table, err := s.loader.GetMode(r.PathValue("mode"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	s.lgsHandlers.Sampling().Reset(table.Mode)
+	common.WriteSuccess(w, map[string]string{"status": "reset", "mode": table.Mode})
+}
+
+// handleParSheet returns the mode's theoretical PAR sheet.
+// Query params: format (json|html, default json)
+func (s *Server) handleParSheet(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	sheet := lut.BuildParSheet(table)
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		common.WriteSuccess(w, sheet)
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := sheet.WriteHTML(w); err != nil {
+			log.Printf("Failed to render PAR sheet for %s: %v", mode, err)
+		}
+	default:
+		common.WriteError(w, http.StatusBadRequest, "format must be json or html")
+	}
+}
+
+// WhatIfRequest holds the request body for a weight what-if.
+type WhatIfRequest struct {
+	Edits []lut.WeightEdit `json:"edits"`
+}
+
+// handleMaxWin estimates spins and turnover to hit the max win
+// (?bet=base bet, default 1; ?threshold=multiplier, default the max win).
+func (s *Server) handleMaxWin(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	bet, threshold := 1.0, 0.0
+	if v := r.URL.Query().Get("bet"); v != "" {
+		if bet, err = strconv.ParseFloat(v, 64); err != nil || bet <= 0 {
+			common.WriteError(w, http.StatusBadRequest, "bet must be a positive number")
+			return
+		}
+	}
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		if threshold, err = strconv.ParseFloat(v, 64); err != nil || threshold < 0 {
+			common.WriteError(w, http.StatusBadRequest, "threshold must be a non-negative number")
+			return
+		}
+	}
+
+	result, err := lut.EstimateMaxWin(table, bet, threshold)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result.Summary = result.Describe(requestLocale(r))
+	common.WriteSuccess(w, result)
+}
+
+// VariantRequest creates an in-memory variant of a mode. Without weights the
+// current weights are snapshotted.
+type VariantRequest struct {
+	Name    string   `json:"name"`
+	Weights []uint64 `json:"weights,omitempty"`
+}
+
+// handleListVariants lists the in-memory variants of a mode.
+func (s *Server) handleListVariants(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if _, err := s.loader.GetMode(mode); err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{
+		"mode":     mode,
+		"variants": s.loader.ListVariants(mode),
+	})
+}
+
+// handleCreateVariant creates (or replaces) a named in-memory variant of a
+// mode that LGS sessions can play instead of the table on disk.
+func (s *Server) handleCreateVariant(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if _, err := s.loader.GetMode(mode); err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var req VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	variant, err := s.loader.CreateVariant(mode, req.Name, req.Weights)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	common.WriteSuccess(w, variant.Info())
+}
+
+// handleDeleteVariant removes an in-memory variant.
+func (s *Server) handleDeleteVariant(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	name := r.PathValue("name")
+	if err := s.loader.DeleteVariant(mode, name); err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{
+		"deleted": name,
+	})
+}
+
+// handleWhatIf recomputes RTP, hit rate and odds for single-outcome weight
+// edits without saving them.
+func (s *Server) handleWhatIf(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var req WhatIfRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := lut.WhatIf(table, req.Edits)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, result)
+}
+
+// handleSampleOutcomes returns random simIDs from a payout band.
+// Query params: bucket (zero, sub_bet, win, big_win, mega_win) or min/max in
+// bet multiples, count (default 10), weighting (weighted|uniform), seed.
+// Example: /api/mode/base/sample-outcomes?bucket=big_win&count=10
+func (s *Server) handleSampleOutcomes(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	var band lut.PayoutBand
+	if name := q.Get("bucket"); name != "" {
+		if band, err = lut.LookupPayoutBand(name); err != nil {
+			common.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if v := q.Get("min"); v != "" {
+		if band.Min, err = strconv.ParseFloat(v, 64); err != nil || band.Min < 0 {
+			common.WriteError(w, http.StatusBadRequest, "min must be a non-negative number")
+			return
+		}
+		band.Name = ""
+	}
+	if v := q.Get("max"); v != "" {
+		if band.Max, err = strconv.ParseFloat(v, 64); err != nil || band.Max < 0 {
+			common.WriteError(w, http.StatusBadRequest, "max must be a non-negative number")
+			return
+		}
+		band.Name = ""
+	}
+	if band.Max > 0 && band.Min >= band.Max {
+		common.WriteError(w, http.StatusBadRequest, "min must be below max")
+		return
+	}
+
+	count := lut.DefaultSampleCount
+	if v := q.Get("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil || count <= 0 {
+			common.WriteError(w, http.StatusBadRequest, "count must be a positive integer")
+			return
+		}
+	}
+	if count > lut.MaxSampleCount {
+		count = lut.MaxSampleCount
+	}
+
+	weighted := true
+	switch q.Get("weighting") {
+	case "", "weighted":
+	case "uniform":
+		weighted = false
+	default:
+		common.WriteError(w, http.StatusBadRequest, "weighting must be weighted or uniform")
+		return
+	}
+
+	seed := s.loader.Simulator().NewSeed()
+	if v := q.Get("seed"); v != "" {
+		if seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "invalid seed")
+			return
+		}
+	}
+
+	common.WriteSuccess(w, lut.SampleOutcomes(table, band, count, weighted, seed))
+}
+
+// QuickSimulateRequest holds the request body for quick simulation.
+type QuickSimulateRequest struct {
+	Spins int    `json:"spins"`
+	Seed  *int64 `json:"seed,omitempty"` // omit for a random seed; echoed in the result
+	// Stream sends spins over the WebSocket hub as simulation_spins messages
+	// instead of in the response, ending with simulation_complete
+	Stream     bool `json:"stream,omitempty"`
+	BatchSize  int  `json:"batch_size,omitempty"`  // spins per message (default 100)
+	IntervalMs int  `json:"interval_ms,omitempty"` // pause between messages (default 20)
+}
+
+// handleQuickSimulate runs a quick single-trial simulation with spin-by-spin results.
+func (s *Server) handleQuickSimulate(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var req QuickSimulateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	// Validate and set defaults
+	if req.Spins <= 0 {
+		req.Spins = 100
+	}
+	if req.Spins > 10000 {
+		req.Spins = 10000
+	}
+
+	// Use mode cost as bet
+	bet := table.Cost
+	if bet <= 0 {
+		bet = 1.0
+	}
+
+	simulator := s.loader.Simulator()
+	seed := simulator.NewSeed()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	if req.Stream {
+		s.streamQuickSimulation(w, table, req, bet, seed)
+		return
+	}
+
+	result := simulator.RunQuickSimulation(table, req.Spins, bet, seed)
+	s.storeSimulation("quick", req, result)
+	common.WriteSuccess(w, result)
+}
+
+// streamQuickSimulation starts a quick simulation in the background and
+// replies with its stream ID. Spins are broadcast in batches as they are
+// generated; the run stops early once no WebSocket clients are connected.
+func (s *Server) streamQuickSimulation(w http.ResponseWriter, table *stakergs.LookupTable, req QuickSimulateRequest, bet float64, seed int64) {
+	if s.wsHub == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "WebSocket hub not available")
+		return
+	}
+
+	if req.BatchSize <= 0 {
+		req.BatchSize = 100
+	}
+	if req.BatchSize > 1000 {
+		req.BatchSize = 1000
+	}
+	if req.IntervalMs <= 0 {
+		req.IntervalMs = 20 // keeps the hub's broadcast buffer from overflowing
+	}
+	if req.IntervalMs > 1000 {
+		req.IntervalMs = 1000
+	}
+
+	streamID := fmt.Sprintf("quick-%d", time.Now().UnixNano())
+	interval := time.Duration(req.IntervalMs) * time.Millisecond
+
+	go func() {
+		result := s.loader.Simulator().RunQuickSimulationStream(table, req.Spins, bet, seed, req.BatchSize, func(batch []lut.SpinResult) bool {
+			if s.wsHub.ClientCount() == 0 {
+				return false
+			}
+			s.wsHub.Broadcast(ws.Message{
+				Type: ws.MsgSimulationSpins,
+				Mode: table.Mode,
+				Payload: map[string]interface{}{
+					"stream_id": streamID,
+					"spins":     batch,
+				},
+			})
+			time.Sleep(interval)
+			return true
+		})
+		s.storeSimulation("quick", req, result)
+
+		summary := *result
+		summary.SpinResults = nil
+		s.wsHub.Broadcast(ws.Message{
+			Type: ws.MsgSimulationComplete,
+			Mode: table.Mode,
+			Payload: map[string]interface{}{
+				"stream_id": streamID,
+				"result":    summary,
+				"completed": result.TotalSpins == req.Spins,
+			},
+		})
+	}()
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"stream_id":   streamID,
+		"seed":        seed,
+		"spins":       req.Spins,
+		"batch_size":  req.BatchSize,
+		"interval_ms": req.IntervalMs,
+	})
+}
+
+// storeSimulation persists a result so it can be reloaded by ID. The result
+// is still returned (without an ID) if storing fails.
+func (s *Server) storeSimulation(kind string, req interface{}, result *lut.SimulationResult) {
+	if _, err := s.simulations.Save(kind, req, result); err != nil {
+		log.Printf("Failed to store simulation result: %v", err)
+	}
+}
+
+// handleListSimulations lists stored simulation results, optionally filtered by ?mode.
+func (s *Server) handleListSimulations(w http.ResponseWriter, r *http.Request) {
+	summaries, err := s.simulations.List(r.URL.Query().Get("mode"))
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{
+		"simulations": summaries,
+	})
+}
+
+// handleGetSimulation returns a stored simulation with its request and full result.
+func (s *Server) handleGetSimulation(w http.ResponseWriter, r *http.Request) {
+	record, err := s.simulations.Get(r.PathValue("id"))
+	if errors.Is(err, simstore.ErrNotFound) {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, record)
+}
+
+// handleDeleteSimulation removes a stored simulation.
+func (s *Server) handleDeleteSimulation(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	err := s.simulations.Delete(id)
+	if errors.Is(err, simstore.ErrNotFound) {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{
+		"deleted": id,
+	})
+}
+
+// BookmarkRequest holds the request body for creating or updating a bookmark.
+type BookmarkRequest struct {
+	Mode  string   `json:"mode"`
+	SimID *int     `json:"sim_id"`
+	Note  *string  `json:"note"`
+	Tags  []string `json:"tags"`
+}
+
+// handleListBookmarks lists bookmarks, optionally filtered by ?mode and ?tag.
+func (s *Server) handleListBookmarks(w http.ResponseWriter, r *http.Request) {
+	list, err := s.bookmarks.List(r.URL.Query().Get("mode"), r.URL.Query().Get("tag"))
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{
+		"bookmarks": list,
+	})
+}
+
+// handleAddBookmark bookmarks a simID of a mode with a note and tags.
+func (s *Server) handleAddBookmark(w http.ResponseWriter, r *http.Request) {
+	var req BookmarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Mode == "" || req.SimID == nil {
+		common.WriteError(w, http.StatusBadRequest, "mode and sim_id are required")
+		return
+	}
+
+	outcome, err := s.loader.GetOutcome(req.Mode, *req.SimID)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	table, _ := s.loader.GetMode(req.Mode)
+	note := ""
+	if req.Note != nil {
+		note = *req.Note
+	}
+	bookmark, err := s.bookmarks.Add(table.Mode, outcome.SimID, note, req.Tags)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, bookmark)
+}
+
+// handleUpdateBookmark changes the note and/or tags of a bookmark.
+func (s *Server) handleUpdateBookmark(w http.ResponseWriter, r *http.Request) {
+	var req BookmarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	bookmark, err := s.bookmarks.Update(r.PathValue("id"), req.Note, req.Tags)
+	if errors.Is(err, bookmarks.ErrNotFound) {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, bookmark)
+}
+
+// handleDeleteBookmark removes a bookmark.
+func (s *Server) handleDeleteBookmark(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	err := s.bookmarks.Delete(id)
+	if errors.Is(err, bookmarks.ErrNotFound) {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{
+		"deleted": id,
+	})
+}
+
+// maxStateArchiveSize caps an uploaded state archive.
+const maxStateArchiveSize = 2 << 30
+
+// handleExportState downloads the runtime state as a zip archive: LGS
+// sessions, table variants, and the library's bookmarks, stored
+// simulations, transforms, cassettes and optimizer weight backups.
+func (s *Server) handleExportState(w http.ResponseWriter, r *http.Request) {
+	if !s.loader.IsOpen() {
+		common.WriteError(w, http.StatusConflict, "no library open")
+		return
+	}
+
+	var weightFiles []string
+	for weights := range s.loader.GetCSVFiles() {
+		weightFiles = append(weightFiles, weights)
+	}
+	files, err := statearchive.LibraryFiles(s.loader.BaseDir(), weightFiles)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	state := &statearchive.State{
+		Sessions: s.lgsSessions.Snapshot(),
+		Variants: []statearchive.Variant{},
+	}
+	for _, mode := range s.loader.ListModes() {
+		for _, info := range s.loader.ListVariants(mode) {
+			table, err := s.loader.GetVariant(mode, info.Name)
+			if err != nil {
+				continue
+			}
+			weights := make([]uint64, len(table.Outcomes))
+			for i, o := range table.Outcomes {
+				weights[i] = o.Weight
+			}
+			state.Variants = append(state.Variants, statearchive.Variant{Mode: info.Mode, Name: info.Name, Weights: weights})
+		}
+	}
+
+	filename := fmt.Sprintf("lutexplorer-state-%s.zip", time.Now().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	manifest, err := statearchive.Export(w, s.loader.BaseDir(), files, state)
+	if err != nil {
+		// Headers are sent; all that is left is to cut the download short
+		log.Printf("State export failed: %v", err)
+		return
+	}
+	log.Printf("Exported state: %d sessions, %d variants, %d files", manifest.Sessions, manifest.Variants, len(manifest.Files))
+}
+
+// handleImportState restores a state archive (the zip body) into the open
+// library. Files and sessions with the same name or ID are replaced;
+// variants of modes the library lacks are skipped and reported.
+func (s *Server) handleImportState(w http.ResponseWriter, r *http.Request) {
+	if !s.loader.IsOpen() {
+		common.WriteError(w, http.StatusConflict, "no library open")
+		return
+	}
+
+	// zip needs random access, so spool the upload to disk first
+	tmp, err := os.CreateTemp("", "lutexplorer-state-*.zip")
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, http.MaxBytesReader(w, r.Body, maxStateArchiveSize))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "failed to read archive: "+err.Error())
+		return
+	}
+
+	manifest, state, err := statearchive.Import(tmp, size, s.loader.BaseDir())
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, session := range state.Sessions {
+		if session == nil || session.SessionID == "" {
+			continue
+		}
+		s.lgsSessions.Update(session)
+	}
+
+	skipped := []string{}
+	variants := 0
+	for _, v := range state.Variants {
+		if _, err := s.loader.CreateVariant(v.Mode, v.Name, v.Weights); err != nil {
+			skipped = append(skipped, fmt.Sprintf("variant %s/%s: %v", v.Mode, v.Name, err))
+			continue
+		}
+		variants++
+	}
+
+	log.Printf("Imported state from %s: %d sessions, %d variants, %d files", manifest.Library, len(state.Sessions), variants, len(manifest.Files))
+	common.WriteSuccess(w, map[string]interface{}{
+		"library":     manifest.Library,
+		"exported_at": manifest.CreatedAt,
+		"sessions":    len(state.Sessions),
+		"variants":    variants,
+		"files":       manifest.Files,
+		"skipped":     skipped,
+	})
+}
+
+// ModeExport is a self-contained snapshot of a mode for sharing or archiving.
+type ModeExport struct {
+	ExportedAt time.Time            `json:"exported_at"`
+	GameID     string               `json:"game_id,omitempty"`
+	Config     *stakergs.ModeConfig `json:"config"`
+	Summary    lut.ModeSummary      `json:"summary"`
+	Statistics *lut.Statistics      `json:"statistics"`
+	Bookmarks  []bookmarks.Bookmark `json:"bookmarks"`
+}
+
+// handleExportMode returns a mode export bundle as a JSON download. With
+// ?format=csv|jsonl|parquet it instead streams the full outcome table with
+// probabilities and bucket assignments, for loading into pandas or DuckDB.
+func (s *Server) handleExportMode(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if format := r.URL.Query().Get("format"); format != "" && format != "json" {
+		s.exportOutcomes(w, r, table, format)
+		return
+	}
+
+	config, err := s.loader.GetModeConfig(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
 
-	result := s.loader.Simulator().RunQuickSimulation(table, req.Spins, bet)
-	common.WriteSuccess(w, result)
+	marks, err := s.bookmarks.List(table.Mode, "")
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	bundle := ModeExport{
+		ExportedAt: time.Now().UTC(),
+		GameID:     table.GameID,
+		Config:     config,
+		Summary: lut.ModeSummary{
+			Mode:      table.Mode,
+			Outcomes:  len(table.Outcomes),
+			RTP:       table.RTP(),
+			HitRate:   table.HitRate(),
+			MaxPayout: float64(table.MaxPayout()) / 100.0,
+		},
+		Statistics: s.loader.Statistics(table),
+		Bookmarks:  marks,
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Mode+"-export.json"))
+	common.WriteSuccess(w, bundle)
+}
+
+// exportOutcomes streams a mode's outcome table as a file download.
+func (s *Server) exportOutcomes(w http.ResponseWriter, r *http.Request, table *stakergs.LookupTable, format string) {
+	contentType, ext, err := lut.ExportContentType(format)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Mode+"-outcomes."+ext))
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(table.Outcomes)))
+	flusher, _ := w.(http.Flusher)
+
+	err = lut.WriteOutcomes(w, table, format, func() error {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent; all we can do is stop and log
+		log.Printf("Export of %s as %s aborted: %v", table.Mode, format, err)
+	}
 }
 
 // handleLoaderStatus returns the current status of background loading.
@@ -931,11 +2643,8 @@ func (s *Server) handleLoaderStatus(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Estimate decompressed size (zstd ratio ~10-15x for JSON, use 12x average)
-	// Plus overhead for map storage (~20% extra)
-	const decompressionRatio = 12.0
-	const storageOverhead = 1.2
-	estimatedMemoryBytes := int64(float64(totalCompressedBytes) * decompressionRatio * storageOverhead)
+	// Estimate decompressed size plus overhead for map storage
+	estimatedMemoryBytes := int64(float64(totalCompressedBytes) * lut.DecompressionRatio * lut.StorageOverhead)
 
 	common.WriteSuccess(w, map[string]any{
 		"priority":   priority,
@@ -947,7 +2656,7 @@ func (s *Server) handleLoaderStatus(w http.ResponseWriter, r *http.Request) {
 			"estimated_bytes":    estimatedMemoryBytes,
 			"estimated_mb":       estimatedMemoryBytes / (1024 * 1024),
 			"mode_count":         modeCount,
-			"decompression_ratio": decompressionRatio,
+			"decompression_ratio": lut.DecompressionRatio,
 		},
 	})
 }
@@ -1015,6 +2724,7 @@ func (s *Server) handleLoaderPriority(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, map[string]interface{}{
 		"priority":    priority.String(),
 		"description": getPriorityDescription(priority),
+		"workers":     s.bgLoader.Workers(),
 	})
 }
 
@@ -1056,12 +2766,63 @@ func (s *Server) handleModeCompliance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	checker := lut.NewComplianceChecker()
+	checker, err := complianceChecker(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	checker.SetLocale(requestLocale(r))
 	result := checker.CheckMode(table)
+	s.recordCompliance(result)
 
 	common.WriteSuccess(w, result)
 }
 
+// handleComplianceReport renders a mode's compliance results as a document
+// for certification labs.
+// Query params: format (pdf|html, default pdf), profile (see complianceChecker)
+func (s *Server) handleComplianceReport(w http.ResponseWriter, r *http.Request) {
+	mode := r.PathValue("mode")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode parameter required")
+		return
+	}
+
+	table, err := s.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	checker, err := complianceChecker(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "pdf"
+	}
+	var contentType string
+	switch format {
+	case "pdf":
+		contentType = "application/pdf"
+	case "html":
+		contentType = "text/html; charset=utf-8"
+	default:
+		common.WriteError(w, http.StatusBadRequest, "format must be pdf or html")
+		return
+	}
+
+	report := lut.BuildComplianceReport(table, checker)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Mode+"-compliance."+format))
+	if err := report.WriteReport(w, format); err != nil {
+		log.Printf("Failed to render compliance report for %s: %v", mode, err)
+	}
+}
+
 // handleAllCompliance returns compliance check results for all modes.
 func (s *Server) handleAllCompliance(w http.ResponseWriter, r *http.Request) {
 	tables := make(map[string]*stakergs.LookupTable)
@@ -1079,43 +2840,119 @@ func (s *Server) handleAllCompliance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	checker := lut.NewComplianceChecker()
+	checker, err := complianceChecker(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	checker.SetLocale(requestLocale(r))
 	result := checker.CheckAllModes(tables)
+	for _, modeResult := range result.ModeResults {
+		s.recordCompliance(modeResult)
+	}
 
 	common.WriteSuccess(w, result)
 }
 
+// requestLocale returns the number formatting locale the client asked for
+// with Accept-Language.
+func requestLocale(r *http.Request) *numfmt.Locale {
+	return numfmt.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// complianceChecker builds a checker for the ?profile= query parameter: a
+// preset name or a custom profile as JSON.
+func complianceChecker(r *http.Request) (*lut.ComplianceChecker, error) {
+	profile, err := lut.ParseComplianceProfile(r.URL.Query().Get("profile"))
+	if err != nil {
+		return nil, err
+	}
+	return lut.NewComplianceCheckerWithProfile(profile), nil
+}
+
+// handleComplianceProfiles lists the preset compliance profiles.
+func (s *Server) handleComplianceProfiles(w http.ResponseWriter, r *http.Request) {
+	common.WriteSuccess(w, map[string]interface{}{
+		"profiles": lut.ComplianceProfiles(),
+		"default":  lut.DefaultComplianceProfile,
+	})
+}
+
 // WatcherStatus represents the status of the CSV watcher.
 type WatcherStatus struct {
 	Available bool              `json:"available"`
 	Enabled   bool              `json:"enabled"`
+	Method    string            `json:"method,omitempty"` // "fsnotify" or "poll"
 	Files     map[string]string `json:"files,omitempty"`
+	// DisabledModes lists modes whose auto-reload was turned off individually.
+	DisabledModes []string `json:"disabled_modes,omitempty"`
+	// NetworkMount is set when the library is on a network filesystem,
+	// where polling is recommended over fsnotify.
+	NetworkMount   bool   `json:"network_mount"`
+	FilesystemType string `json:"filesystem_type,omitempty"`
+	SuggestPolling bool   `json:"suggest_polling"`
 }
 
 // handleWatcherStatus returns the current status of the CSV watcher.
 func (s *Server) handleWatcherStatus(w http.ResponseWriter, r *http.Request) {
+	fw := s.csvWatcher.Load()
 	status := WatcherStatus{
-		Available: s.csvWatcher != nil,
+		Available: fw != nil,
 		Enabled:   false,
 		Files:     nil,
 	}
 
-	if s.csvWatcher != nil {
-		status.Enabled = s.csvWatcher.Enabled()
-		status.Files = s.csvWatcher.GetFiles()
+	status.NetworkMount, status.FilesystemType = watcher.IsNetworkMount(s.loader.BaseDir())
+	status.SuggestPolling = status.NetworkMount
+
+	if fw != nil {
+		status.Enabled = fw.Enabled()
+		status.Method = fw.Method()
+		status.Files = fw.GetFiles()
+		status.DisabledModes = fw.DisabledModes()
+		status.SuggestPolling = status.NetworkMount && status.Method != watcher.MethodPoll
 	}
 
 	common.WriteSuccess(w, status)
 }
 
+// handleSyntheticEventsStatus reports whether synthetic books are served for
+// modes without events.
+func (s *Server) handleSyntheticEventsStatus(w http.ResponseWriter, r *http.Request) {
+	common.WriteSuccess(w, map[string]interface{}{
+		"enabled": s.loader.SyntheticEvents(),
+	})
+}
+
+// handleSyntheticEventsEnable serves generated payout-only books for modes
+// without an events file, so game clients can be driven against LUT-only
+// libraries.
+func (s *Server) handleSyntheticEventsEnable(w http.ResponseWriter, r *http.Request) {
+	s.loader.SetSyntheticEvents(true)
+	common.WriteSuccess(w, map[string]interface{}{
+		"message": "Synthetic events enabled",
+		"enabled": true,
+	})
+}
+
+// handleSyntheticEventsDisable stops serving synthetic books.
+func (s *Server) handleSyntheticEventsDisable(w http.ResponseWriter, r *http.Request) {
+	s.loader.SetSyntheticEvents(false)
+	common.WriteSuccess(w, map[string]interface{}{
+		"message": "Synthetic events disabled",
+		"enabled": false,
+	})
+}
+
 // handleWatcherEnable enables the CSV watcher.
 func (s *Server) handleWatcherEnable(w http.ResponseWriter, r *http.Request) {
-	if s.csvWatcher == nil {
+	fw := s.csvWatcher.Load()
+	if fw == nil {
 		common.WriteError(w, http.StatusServiceUnavailable, "watcher not available (start server with --watch flag)")
 		return
 	}
 
-	s.csvWatcher.SetEnabled(true)
+	fw.SetEnabled(true)
 
 	// Broadcast to WebSocket clients
 	s.wsHub.Broadcast(ws.Message{
@@ -1133,12 +2970,13 @@ func (s *Server) handleWatcherEnable(w http.ResponseWriter, r *http.Request) {
 
 // handleWatcherDisable disables the CSV watcher.
 func (s *Server) handleWatcherDisable(w http.ResponseWriter, r *http.Request) {
-	if s.csvWatcher == nil {
+	fw := s.csvWatcher.Load()
+	if fw == nil {
 		common.WriteError(w, http.StatusServiceUnavailable, "watcher not available (start server with --watch flag)")
 		return
 	}
 
-	s.csvWatcher.SetEnabled(false)
+	fw.SetEnabled(false)
 
 	// Broadcast to WebSocket clients
 	s.wsHub.Broadcast(ws.Message{
@@ -1153,3 +2991,390 @@ func (s *Server) handleWatcherDisable(w http.ResponseWriter, r *http.Request) {
 		"enabled": false,
 	})
 }
+
+// handleWatcherEvents returns recently detected file changes and their outcome, newest first.
+func (s *Server) handleWatcherEvents(w http.ResponseWriter, r *http.Request) {
+	fw := s.csvWatcher.Load()
+	if fw == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "watcher not available (start server with --watch flag)")
+		return
+	}
+
+	events := fw.History()
+	common.WriteSuccess(w, map[string]interface{}{
+		"count":  len(events),
+		"events": events,
+	})
+}
+
+// handleWatcherRescan forces a reload of the index and all watched lookup tables.
+func (s *Server) handleWatcherRescan(w http.ResponseWriter, r *http.Request) {
+	fw := s.csvWatcher.Load()
+	if fw == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "watcher not available (start server with --watch flag)")
+		return
+	}
+
+	results := fw.Rescan()
+
+	failed := 0
+	for _, ev := range results {
+		if !ev.Success {
+			failed++
+		}
+	}
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"message": fmt.Sprintf("Rescanned %d files, %d failed", len(results), failed),
+		"results": results,
+	})
+}
+
+// handleWatcherModeEnable re-enables auto-reload for a single mode.
+func (s *Server) handleWatcherModeEnable(w http.ResponseWriter, r *http.Request) {
+	s.setWatcherModeEnabled(w, r, true)
+}
+
+// handleWatcherModeDisable disables auto-reload for a single mode.
+func (s *Server) handleWatcherModeDisable(w http.ResponseWriter, r *http.Request) {
+	s.setWatcherModeEnabled(w, r, false)
+}
+
+func (s *Server) setWatcherModeEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	fw := s.csvWatcher.Load()
+	if fw == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "watcher not available (start server with --watch flag)")
+		return
+	}
+
+	config, err := s.loader.GetModeConfig(r.PathValue("mode"))
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	mode := config.Name
+
+	fw.SetModeEnabled(mode, enabled)
+
+	msgType := ws.MsgWatcherDisabled
+	message := "Auto-reload disabled for mode " + mode
+	if enabled {
+		msgType = ws.MsgWatcherEnabled
+		message = "Auto-reload enabled for mode " + mode
+	}
+
+	// Broadcast to WebSocket clients
+	s.wsHub.Broadcast(ws.Message{
+		Type: msgType,
+		Mode: mode,
+		Payload: map[string]interface{}{
+			"mode":    mode,
+			"enabled": enabled,
+		},
+	})
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"message": message,
+		"mode":    mode,
+		"enabled": enabled,
+	})
+}
+
+// handleLogs returns buffered backend log lines newer than ?since (a sequence number)
+// and at least as severe as ?level (debug, info, warn, error).
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if s.logs == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "log buffer not available")
+		return
+	}
+
+	level, err := logbuf.ParseLevel(r.URL.Query().Get("level"))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	since, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	lines, last := s.logs.Since(since, limit, level)
+	common.WriteSuccess(w, map[string]interface{}{
+		"lines": lines,
+		"last":  last,
+	})
+}
+
+// handleLogsStream streams backend log lines as server-sent events, starting
+// with buffered lines newer than ?since. ?level filters as for handleLogs.
+func (s *Server) handleLogsStream(w http.ResponseWriter, r *http.Request) {
+	if s.logs == nil {
+		common.WriteError(w, http.StatusServiceUnavailable, "log buffer not available")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		common.WriteError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	level, err := logbuf.ParseLevel(r.URL.Query().Get("level"))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Subscribe before reading the backlog so no line falls in between
+	sub, unsubscribe := s.logs.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	since, _ := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+	// A reconnecting EventSource resumes after the last line it received
+	if id, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64); err == nil && id > since {
+		since = id
+	}
+	backlog, last := s.logs.Since(since, 0, level)
+	for _, line := range backlog {
+		writeLogEvent(w, line)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line, ok := <-sub:
+			if !ok {
+				return
+			}
+			if line.Seq <= last || !line.Level.AtLeast(level) {
+				continue // already sent with the backlog, or filtered out
+			}
+			writeLogEvent(w, line)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeLogEvent(w http.ResponseWriter, line logbuf.Line) {
+	data, _ := json.Marshal(line)
+	fmt.Fprintf(w, "id: %d\ndata: %s\n\n", line.Seq, data)
+}
+
+// handleLanguages lists the languages available from the -locales folder.
+func (s *Server) handleLanguages(w http.ResponseWriter, r *http.Request) {
+	if s.locales == nil {
+		common.WriteSuccess(w, map[string]interface{}{"languages": []i18n.Language{}})
+		return
+	}
+	languages, err := s.locales.Languages()
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, map[string]interface{}{"languages": languages})
+}
+
+// handleTranslations returns the string catalog for a language.
+func (s *Server) handleTranslations(w http.ResponseWriter, r *http.Request) {
+	lang := r.PathValue("lang")
+	if s.locales == nil {
+		common.WriteError(w, http.StatusNotFound, "no locales folder configured")
+		return
+	}
+	messages, err := s.locales.Messages(lang)
+	if errors.Is(err, i18n.ErrUnknownLanguage) {
+		common.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, messages)
+}
+
+// LibraryRequest is the request body for POST /api/library/open.
+type LibraryRequest struct {
+	Path string `json:"path"`
+}
+
+// LibraryInfo describes the open library, if any.
+type LibraryInfo struct {
+	Open      bool              `json:"open"`
+	Library   string            `json:"library,omitempty"`
+	IndexPath string            `json:"index_path,omitempty"`
+	Modes     []lut.ModeSummary `json:"modes"`
+}
+
+func (s *Server) libraryInfo() LibraryInfo {
+	info := LibraryInfo{
+		Open:  s.loader.IsOpen(),
+		Modes: []lut.ModeSummary{},
+	}
+	if info.Open {
+		info.Library = s.loader.LibraryDir()
+		info.IndexPath = s.loader.IndexPath()
+		info.Modes = s.loader.GetModeSummaries()
+	}
+	return info
+}
+
+// handleLibrary returns the open library.
+func (s *Server) handleLibrary(w http.ResponseWriter, r *http.Request) {
+	common.WriteSuccess(w, s.libraryInfo())
+}
+
+// handleLibraryOpen opens a library folder, replacing the current one.
+// On failure the current library stays open.
+func (s *Server) handleLibraryOpen(w http.ResponseWriter, r *http.Request) {
+	var req LibraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Path) == "" {
+		common.WriteError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+
+	if err := s.openLibrary(req.Path); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, s.libraryInfo())
+}
+
+// handleLibrarySwitch switches to another library, typically one from the
+// recent list. Clients get library_switching before loading starts so they
+// can drop views of the old library, then library_changed once it is open.
+func (s *Server) handleLibrarySwitch(w http.ResponseWriter, r *http.Request) {
+	var req LibraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Path) == "" {
+		common.WriteError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+
+	s.wsHub.Broadcast(ws.Message{
+		Type: ws.MsgLibrarySwitching,
+		Payload: map[string]string{
+			"from": s.loader.LibraryDir(),
+			"to":   req.Path,
+		},
+	})
+
+	if err := s.openLibrary(req.Path); err != nil {
+		// The old library stays open; tell clients so they can restore it
+		s.wsHub.Broadcast(ws.Message{
+			Type:    ws.MsgLibraryChanged,
+			Payload: s.libraryInfo(),
+		})
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, s.libraryInfo())
+}
+
+// handleRecentLibraries lists recently opened libraries, most recent first.
+func (s *Server) handleRecentLibraries(w http.ResponseWriter, r *http.Request) {
+	if s.recentLibraries == nil {
+		common.WriteSuccess(w, []recent.Library{})
+		return
+	}
+	libraries, err := s.recentLibraries.List()
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	common.WriteSuccess(w, libraries)
+}
+
+// handleForgetLibrary removes a library from the recent list (?path=...).
+func (s *Server) handleForgetLibrary(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		common.WriteError(w, http.StatusBadRequest, "path query parameter required")
+		return
+	}
+	if s.recentLibraries == nil {
+		common.WriteError(w, http.StatusNotFound, "recent libraries not available")
+		return
+	}
+	if err := s.recentLibraries.Remove(path); err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.handleRecentLibraries(w, r)
+}
+
+// openLibrary opens a library, remembers it as recently used and notifies
+// everything that depends on it.
+func (s *Server) openLibrary(path string) error {
+	if err := s.loader.OpenLibrary(path); err != nil {
+		return err
+	}
+	log.Printf("Opened library %s", s.loader.LibraryDir())
+	s.RecordLibrary()
+	s.onLibraryChanged()
+	return nil
+}
+
+// RecordLibrary adds the open library to the recent list.
+func (s *Server) RecordLibrary() {
+	if s.recentLibraries == nil || !s.loader.IsOpen() {
+		return
+	}
+	lib := recent.Library{
+		Path:      s.loader.LibraryDir(),
+		IndexPath: s.loader.IndexPath(),
+		Modes:     s.loader.ListModes(),
+	}
+	if len(lib.Modes) > 0 {
+		if table, err := s.loader.GetMode(lib.Modes[0]); err == nil {
+			lib.GameID = table.GameID
+		}
+	}
+	if err := s.recentLibraries.Add(lib); err != nil {
+		log.Printf("Warning: failed to update recent libraries: %v", err)
+	}
+}
+
+// handleLibraryClose closes the open library.
+func (s *Server) handleLibraryClose(w http.ResponseWriter, r *http.Request) {
+	if s.loader.IsOpen() {
+		log.Printf("Closed library %s", s.loader.LibraryDir())
+		s.loader.Close()
+		s.onLibraryChanged()
+	}
+
+	common.WriteSuccess(w, s.libraryInfo())
+}
+
+// onLibraryChanged points everything that depends on the library folder at
+// the loader's current library and notifies WebSocket clients.
+func (s *Server) onLibraryChanged() {
+	if s.bgLoader != nil {
+		s.bgLoader.LibraryChanged()
+	}
+	s.simulations.SetLibrary(s.loader.BaseDir())
+	s.bookmarks.SetLibrary(s.loader.BaseDir())
+	if s.libraryChanged != nil {
+		s.libraryChanged()
+	}
+
+	s.wsHub.Broadcast(ws.Message{
+		Type:    ws.MsgLibraryChanged,
+		Payload: s.libraryInfo(),
+	})
+}
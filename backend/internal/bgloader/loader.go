@@ -28,6 +28,9 @@ const (
 	PriorityHigh                 // Fast loading (full CPU)
 )
 
+// DefaultWorkers is the number of modes decoded in parallel in high priority mode.
+const DefaultWorkers = 4
+
 func (p Priority) String() string {
 	if p == PriorityHigh {
 		return "high"
@@ -48,6 +51,9 @@ type ModeStatus struct {
 	Error        string  `json:"error,omitempty"`
 	StartedAt    int64   `json:"started_at,omitempty"`
 	CompletedAt  int64   `json:"completed_at,omitempty"`
+	// Reloading is set while previously loaded events keep serving until
+	// the new file finishes loading
+	Reloading bool `json:"reloading,omitempty"`
 }
 
 // BackgroundLoader handles background loading of event books.
@@ -72,6 +78,8 @@ type BackgroundLoader struct {
 	lowPriorityBatchDelay time.Duration
 	// How often to send progress updates
 	progressInterval int // Every N lines
+	// Modes decoded in parallel in high priority mode (low priority stays sequential)
+	workers atomic.Int32
 }
 
 // NewBackgroundLoader creates a new background loader.
@@ -88,29 +96,39 @@ func NewBackgroundLoader(loader *lut.Loader, hub *ws.Hub) *BackgroundLoader {
 		progressInterval:      1000,                 // Update every 1000 lines
 	}
 	bl.priority.Store(int32(PriorityLow))
+	bl.workers.Store(DefaultWorkers)
 
 	// Pre-initialize mode statuses with file sizes for memory estimation
 	// This allows memory estimate to be available before Start() is called
-	index := loader.GetIndex()
-	if index != nil {
-		for _, mode := range index.Modes {
-			if mode.Events != "" {
-				status := &ModeStatus{
-					Mode:       mode.Name,
-					EventsFile: mode.Events,
-					Status:     "pending",
-				}
-				// Get file size for memory estimation
-				filePath := filepath.Join(bl.baseDir, mode.Events)
-				if info, err := os.Stat(filePath); err == nil {
-					status.TotalBytes = info.Size()
-				}
-				bl.modeStatuses[mode.Name] = status
+	bl.modeStatuses = bl.pendingStatuses()
+
+	return bl
+}
+
+// pendingStatuses returns a pending status, with file size, for every mode
+// of the current index that has an events file.
+func (bl *BackgroundLoader) pendingStatuses() map[string]*ModeStatus {
+	statuses := make(map[string]*ModeStatus)
+	index := bl.loader.GetIndex()
+	if index == nil {
+		return statuses
+	}
+	for _, mode := range index.Modes {
+		if mode.Events != "" {
+			status := &ModeStatus{
+				Mode:       mode.Name,
+				EventsFile: mode.Events,
+				Status:     "pending",
+			}
+			// Get file size for memory estimation
+			filePath := filepath.Join(bl.baseDir, mode.Events)
+			if info, err := os.Stat(filePath); err == nil {
+				status.TotalBytes = info.Size()
 			}
+			statuses[mode.Name] = status
 		}
 	}
-
-	return bl
+	return statuses
 }
 
 // Start begins background loading of all modes.
@@ -130,7 +148,7 @@ func (bl *BackgroundLoader) Start() {
 	// Mode statuses are already initialized in NewBackgroundLoader()
 	// Just start the loading goroutine
 	bl.wg.Add(1)
-	go bl.loadAllModes(index.Modes)
+	go bl.loadAllModes(index.Modes, false)
 }
 
 // IsStarted returns whether loading has been started.
@@ -145,6 +163,8 @@ func (bl *BackgroundLoader) Stop() {
 }
 
 // Restart stops current loading, reloads the index, and starts loading again.
+// Tables switch over as soon as they are read; each mode's previously loaded
+// events keep serving until its new events file has loaded completely.
 func (bl *BackgroundLoader) Restart() error {
 	// Stop any current loading
 	select {
@@ -163,9 +183,13 @@ func (bl *BackgroundLoader) Restart() error {
 	// Update base dir in case it changed
 	bl.baseDir = bl.loader.BaseDir()
 
-	// Reset state
+	// Reset state, flagging modes whose old events are still serving
+	statuses := bl.pendingStatuses()
+	for name, status := range statuses {
+		status.Reloading = bl.loader.EventsLoader().IsLoaded(name)
+	}
 	bl.mu.Lock()
-	bl.modeStatuses = make(map[string]*ModeStatus)
+	bl.modeStatuses = statuses
 	bl.stopCh = make(chan struct{})
 	bl.mu.Unlock()
 
@@ -177,12 +201,48 @@ func (bl *BackgroundLoader) Restart() error {
 		},
 	})
 
-	// Start loading again
-	bl.Start()
+	// Start loading again. Loaded modes are reloaded too, since their
+	// events may be stale.
+	if index := bl.loader.GetIndex(); index != nil && bl.started.Load() {
+		bl.wg.Add(1)
+		go bl.loadAllModes(index.Modes, true)
+	} else {
+		bl.Start()
+	}
 
 	return nil
 }
 
+// LibraryChanged resets loading after the loader opened or closed a library.
+// In-flight loads are stopped and statuses rebuilt from the new index; if
+// background loading was started, it continues with the new library.
+func (bl *BackgroundLoader) LibraryChanged() {
+	select {
+	case <-bl.stopCh:
+	default:
+		close(bl.stopCh)
+	}
+	bl.modeCancelMu.Lock()
+	for name, cancelCh := range bl.modeCancelCh {
+		close(cancelCh)
+		delete(bl.modeCancelCh, name)
+	}
+	bl.modeCancelMu.Unlock()
+	bl.wg.Wait()
+
+	bl.baseDir = bl.loader.BaseDir()
+
+	bl.mu.Lock()
+	bl.modeStatuses = bl.pendingStatuses()
+	bl.stopCh = make(chan struct{})
+	bl.mu.Unlock()
+
+	if index := bl.loader.GetIndex(); index != nil && bl.started.Load() {
+		bl.wg.Add(1)
+		go bl.loadAllModes(index.Modes, false)
+	}
+}
+
 // ReloadMode reloads events for a specific mode (used by file watcher).
 func (bl *BackgroundLoader) ReloadMode(modeName string) error {
 	index := bl.loader.GetIndex()
@@ -256,6 +316,54 @@ func (bl *BackgroundLoader) ReloadMode(modeName string) error {
 	return nil
 }
 
+// AddMode registers a mode that was added to the index after startup.
+// If background loading has been started, its events are loaded immediately;
+// otherwise the mode is tracked as pending.
+func (bl *BackgroundLoader) AddMode(modeName string) error {
+	if bl.IsStarted() {
+		return bl.ReloadMode(modeName)
+	}
+
+	config, err := bl.loader.GetModeConfig(modeName)
+	if err != nil {
+		return err
+	}
+	if config.Events == "" {
+		return nil
+	}
+
+	status := &ModeStatus{
+		Mode:       config.Name,
+		EventsFile: config.Events,
+		Status:     "pending",
+	}
+	if info, err := os.Stat(filepath.Join(bl.baseDir, config.Events)); err == nil {
+		status.TotalBytes = info.Size()
+	}
+
+	bl.mu.Lock()
+	bl.modeStatuses[config.Name] = status
+	bl.mu.Unlock()
+	return nil
+}
+
+// RemoveMode cancels any in-flight load for a mode and forgets its status.
+// Used when a mode is removed from the index.
+func (bl *BackgroundLoader) RemoveMode(modeName string) {
+	bl.modeCancelMu.Lock()
+	if cancelCh, exists := bl.modeCancelCh[modeName]; exists {
+		close(cancelCh)
+		delete(bl.modeCancelCh, modeName)
+	}
+	bl.modeCancelMu.Unlock()
+
+	bl.mu.Lock()
+	delete(bl.modeStatuses, modeName)
+	bl.mu.Unlock()
+
+	bl.loader.EventsLoader().ClearMode(modeName)
+}
+
 // loadModeWithRetry attempts to load a mode with retries on failure.
 // This handles cases where the file might still be incomplete.
 func (bl *BackgroundLoader) loadModeWithRetry(mode stakergs.ModeConfig, maxRetries int) {
@@ -360,6 +468,20 @@ func (bl *BackgroundLoader) SetPriority(p Priority) {
 	}
 }
 
+// SetWorkers sets how many modes are decoded in parallel in high priority
+// mode. Values below 1 are treated as 1. Takes effect on the next load run.
+func (bl *BackgroundLoader) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	bl.workers.Store(int32(n))
+}
+
+// Workers returns the number of parallel loading workers.
+func (bl *BackgroundLoader) Workers() int {
+	return int(bl.workers.Load())
+}
+
 // GetPriority returns the current loading priority.
 func (bl *BackgroundLoader) GetPriority() Priority {
 	return Priority(bl.priority.Load())
@@ -391,23 +513,22 @@ func (bl *BackgroundLoader) GetModeStatus(mode string) *ModeStatus {
 	return nil
 }
 
-// loadAllModes loads events for all modes sequentially.
-func (bl *BackgroundLoader) loadAllModes(modes []stakergs.ModeConfig) {
+// loadAllModes loads events for all modes using a pool of workers. Modes that
+// already have events loaded are skipped unless reloadLoaded is set.
+// Only the first worker runs in low priority mode, so loading stays
+// sequential there; the others join in while priority is high.
+func (bl *BackgroundLoader) loadAllModes(modes []stakergs.ModeConfig, reloadLoaded bool) {
 	defer bl.wg.Done()
 
+	stopCh := bl.stopCh
+	queue := make(chan stakergs.ModeConfig, len(modes))
 	for _, mode := range modes {
 		if mode.Events == "" {
 			continue
 		}
 
-		select {
-		case <-bl.stopCh:
-			return
-		default:
-		}
-
 		// Skip if already loaded
-		if bl.loader.EventsLoader().IsLoaded(mode.Name) {
+		if !reloadLoaded && bl.loader.EventsLoader().IsLoaded(mode.Name) {
 			bl.mu.Lock()
 			if status, ok := bl.modeStatuses[mode.Name]; ok {
 				status.Status = "complete"
@@ -417,10 +538,54 @@ func (bl *BackgroundLoader) loadAllModes(modes []stakergs.ModeConfig) {
 			continue
 		}
 
-		bl.loadMode(mode)
+		queue <- mode
+	}
+	close(queue)
+
+	workers := min(bl.Workers(), len(queue))
+	var wg sync.WaitGroup
+	for id := 0; id < workers; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for {
+				if id > 0 && !bl.waitHighPriority(stopCh, queue) {
+					return
+				}
+				select {
+				case <-stopCh:
+					return
+				case mode, ok := <-queue:
+					if !ok {
+						return
+					}
+					bl.loadMode(mode)
+				}
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	select {
+	case <-stopCh:
+	default:
+		log.Println("BackgroundLoader: All modes loaded")
 	}
+}
 
-	log.Println("BackgroundLoader: All modes loaded")
+// waitHighPriority blocks until loading is in high priority mode or the
+// queue has drained. Returns false if loading was stopped first.
+func (bl *BackgroundLoader) waitHighPriority(stopCh <-chan struct{}, queue <-chan stakergs.ModeConfig) bool {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for bl.GetPriority() != PriorityHigh && len(queue) > 0 {
+		select {
+		case <-stopCh:
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
 }
 
 // loadMode loads events for a single mode (wrapper for backwards compatibility).
@@ -452,8 +617,13 @@ func (bl *BackgroundLoader) loadModeInternalCancel(mode stakergs.ModeConfig, can
 	// Wrap file in a counting reader
 	countingReader := &countingReader{reader: file}
 
-	// Create zstd decoder
-	decoder, err := zstd.NewReader(countingReader)
+	// Create zstd decoder. With several workers each stream decodes on a
+	// single goroutine, since the parallelism comes from the pool.
+	var decoderOpts []zstd.DOption
+	if bl.Workers() > 1 {
+		decoderOpts = append(decoderOpts, zstd.WithDecoderConcurrency(1))
+	}
+	decoder, err := zstd.NewReader(countingReader, decoderOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create zstd decoder: %w", err)
 	}
@@ -480,8 +650,17 @@ func (bl *BackgroundLoader) loadModeInternalCancel(mode stakergs.ModeConfig, can
 		},
 	})
 
-	// Read events line by line
-	events := make(map[int]json.RawMessage)
+	// Read events line by line, into memory or an indexed store on disk
+	var events map[int]json.RawMessage
+	var store *lut.DiskEventStoreBuilder
+	if bl.loader.EventsLoader().DiskStoreEnabled() {
+		if store, err = bl.loader.EventsLoader().NewStoreBuilder(mode.Name, filePath); err != nil {
+			return err
+		}
+		defer store.Abort()
+	} else {
+		events = make(map[int]json.RawMessage)
+	}
 	scanner := bufio.NewScanner(decoder)
 	const maxCapacity = 10 * 1024 * 1024 // 10MB buffer
 	buf := make([]byte, maxCapacity)
@@ -512,9 +691,15 @@ func (bl *BackgroundLoader) loadModeInternalCancel(mode stakergs.ModeConfig, can
 		}
 
 		// Copy event data (0-indexed to match CSV sim_id offset handling)
-		eventCopy := make(json.RawMessage, len(line))
-		copy(eventCopy, line)
-		events[lineNum] = eventCopy
+		if store != nil {
+			if err := store.Add(lineNum, line); err != nil {
+				return err
+			}
+		} else {
+			eventCopy := make(json.RawMessage, len(line))
+			copy(eventCopy, line)
+			events[lineNum] = eventCopy
+		}
 		lineNum++
 
 		// Send progress update
@@ -572,7 +757,15 @@ func (bl *BackgroundLoader) loadModeInternalCancel(mode stakergs.ModeConfig, can
 	}
 
 	// Store events in the loader
-	bl.loader.EventsLoader().SetEvents(mode.Name, events, filePath)
+	if store != nil {
+		diskStore, err := store.Finish()
+		if err != nil {
+			return err
+		}
+		bl.loader.EventsLoader().SetStore(mode.Name, diskStore)
+	} else {
+		bl.loader.EventsLoader().SetEvents(mode.Name, events, filePath)
+	}
 
 	// Update status to complete
 	completedAt := time.Now()
@@ -584,6 +777,7 @@ func (bl *BackgroundLoader) loadModeInternalCancel(mode stakergs.ModeConfig, can
 		status.BytesRead = countingReader.BytesRead()
 		status.PercentBytes = 100
 		status.CompletedAt = completedAt.UnixMilli()
+		status.Reloading = false
 	}
 	bl.mu.Unlock()
 
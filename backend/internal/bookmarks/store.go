@@ -0,0 +1,242 @@
+// Package bookmarks stores per-library bookmarks on simIDs with free-text
+// notes and tags, e.g. "visual glitch on this book" or "great demo spin".
+package bookmarks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the file inside the library that holds bookmarks.
+const FileName = ".lutexplorer/bookmarks.json"
+
+// ErrNotFound is returned for an unknown bookmark ID.
+var ErrNotFound = errors.New("bookmark not found")
+
+// Bookmark marks a single outcome of a mode.
+type Bookmark struct {
+	ID        string    `json:"id"`
+	Mode      string    `json:"mode"`
+	SimID     int       `json:"sim_id"`
+	Note      string    `json:"note"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store keeps all bookmarks of a library in one JSON file.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates a store under the given library directory.
+func New(libraryDir string) *Store {
+	return &Store{path: filepath.Join(libraryDir, filepath.FromSlash(FileName))}
+}
+
+// SetLibrary moves the store to another library directory.
+func (s *Store) SetLibrary(libraryDir string) {
+	s.mu.Lock()
+	s.path = filepath.Join(libraryDir, filepath.FromSlash(FileName))
+	s.mu.Unlock()
+}
+
+// List returns bookmarks ordered by mode and simID. Empty mode or tag match all.
+func (s *Store) List(mode, tag string) ([]Bookmark, error) {
+	s.mu.Lock()
+	all, err := s.readLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Bookmark, 0, len(all))
+	for _, b := range all {
+		if mode != "" && !strings.EqualFold(b.Mode, mode) {
+			continue
+		}
+		if tag != "" && !hasTag(b.Tags, tag) {
+			continue
+		}
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Mode != result[j].Mode {
+			return result[i].Mode < result[j].Mode
+		}
+		if result[i].SimID != result[j].SimID {
+			return result[i].SimID < result[j].SimID
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Get returns a bookmark by ID.
+func (s *Store) Get(id string) (*Bookmark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLocked()
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].ID == id {
+			return &all[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Add stores a new bookmark and returns it with its ID and timestamps set.
+func (s *Store) Add(mode string, simID int, note string, tags []string) (*Bookmark, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	b := Bookmark{
+		ID:        id,
+		Mode:      mode,
+		SimID:     simID,
+		Note:      strings.TrimSpace(note),
+		Tags:      normalizeTags(tags),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLocked()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeLocked(append(all, b)); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Update replaces the note and/or tags of a bookmark. Nil leaves a field unchanged.
+func (s *Store) Update(id string, note *string, tags []string) (*Bookmark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLocked()
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].ID != id {
+			continue
+		}
+		if note != nil {
+			all[i].Note = strings.TrimSpace(*note)
+		}
+		if tags != nil {
+			all[i].Tags = normalizeTags(tags)
+		}
+		all[i].UpdatedAt = time.Now().UTC()
+		if err := s.writeLocked(all); err != nil {
+			return nil, err
+		}
+		b := all[i]
+		return &b, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Delete removes a bookmark.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	for i := range all {
+		if all[i].ID == id {
+			return s.writeLocked(append(all[:i], all[i+1:]...))
+		}
+	}
+	return ErrNotFound
+}
+
+// readLocked loads all bookmarks; a missing file means none. Caller must hold s.mu.
+func (s *Store) readLocked() ([]Bookmark, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var all []Bookmark
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("corrupt bookmarks file: %w", err)
+	}
+	return all, nil
+}
+
+// writeLocked replaces the bookmarks file atomically. Caller must hold s.mu.
+func (s *Store) writeLocked(all []Bookmark) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create bookmarks folder: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	return nil
+}
+
+// normalizeTags trims, drops empty and de-duplicates tags (case-insensitive).
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || hasTag(result, t) {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func newID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
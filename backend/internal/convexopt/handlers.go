@@ -11,6 +11,7 @@ import (
 
 	"lutexplorer/internal/common"
 	"lutexplorer/internal/lut"
+	"lutexplorer/internal/openapi"
 	"lutexplorer/internal/ws"
 )
 
@@ -243,6 +244,17 @@ func (h *Handlers) RegisterRoutes(mux *http.ServeMux) {
 	})
 }
 
+// Routes documents the endpoints RegisterRoutes dispatches, for the OpenAPI
+// document (the subtree handler hides them from the mux).
+func (h *Handlers) Routes() []openapi.Route {
+	return []openapi.Route{
+		{Method: "GET", Path: "/api/convexopt/health", Summary: "Check the Python optimizer service", Response: HealthResponse{}},
+		{Method: "POST", Path: "/api/convexopt/optimize", Summary: "Run a convex optimization", Request: ConvexOptimizeRequest{}, Response: ConvexOptimizeResponse{}},
+		{Method: "POST", Path: "/api/convexopt/validate", Summary: "Validate an optimization request", Request: ConvexOptimizeRequest{}},
+		{Method: "GET", Path: "/api/convexopt/{mode}/info", Summary: "Mode information for the optimizer", Response: ModeInfoResponse{}},
+	}
+}
+
 // extractModeFromPath extracts the mode name from a URL path.
 func extractModeFromPath(path, action string) string {
 	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
@@ -3,6 +3,7 @@ package crowdsim
 
 import (
 	"fmt"
+	"math/rand"
 	"runtime"
 )
 
@@ -17,6 +18,12 @@ type SimConfig struct {
 	UseCryptoRNG    bool    `json:"use_crypto_rng"`    // Use crypto/rand for secure randomness
 	StreamingMode   bool    `json:"streaming_mode"`    // Memory-efficient mode (no full history)
 	ParallelWorkers int     `json:"parallel_workers"`  // Number of goroutines for simulation
+	Workers         int     `json:"workers,omitempty"` // Alias of parallel_workers; takes precedence when set
+	// Seed makes runs reproducible: each player draws from its own RNG
+	// stream derived from it, independent of the worker count. Omit for a
+	// random seed; the seed used is echoed in the result's config. Ignored
+	// with use_crypto_rng.
+	Seed *int64 `json:"seed,omitempty"`
 }
 
 // DefaultConfig returns a reasonable default configuration.
@@ -112,12 +119,21 @@ func (c *SimConfig) Validate() error {
 		c.DangerThreshold = 0.1
 	}
 
+	if c.Workers > 0 {
+		c.ParallelWorkers = c.Workers
+	}
 	if c.ParallelWorkers <= 0 {
 		c.ParallelWorkers = runtime.NumCPU()
 	}
 	if c.ParallelWorkers > 64 {
 		c.ParallelWorkers = 64
 	}
+	c.Workers = c.ParallelWorkers
+
+	if c.Seed == nil {
+		seed := rand.Int63()
+		c.Seed = &seed
+	}
 
 	return nil
 }
@@ -57,7 +57,7 @@ func (h *Handlers) HandleSimulate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create simulator
-	simulator := NewCrowdSimulator(table, config)
+	simulator := NewCrowdSimulator(table, h.loader.Sampler(table), config)
 
 	// Run simulation with progress reporting via WebSocket
 	var result *SimResult
@@ -135,7 +135,7 @@ func (h *Handlers) HandleCompare(w http.ResponseWriter, r *http.Request) {
 			continue // Skip invalid modes
 		}
 
-		simulator := NewCrowdSimulator(table, req.Config)
+		simulator := NewCrowdSimulator(table, h.loader.Sampler(table), req.Config)
 
 		var result *SimResult
 		if req.Config.ParallelWorkers > 1 {
@@ -195,7 +195,7 @@ func (h *Handlers) HandleValidate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Run simulation
-	simulator := NewCrowdSimulator(table, config)
+	simulator := NewCrowdSimulator(table, h.loader.Sampler(table), config)
 	var result *SimResult
 	if config.ParallelWorkers > 1 {
 		result = simulator.RunParallel(nil)
@@ -243,7 +243,7 @@ func (h *Handlers) HandleVolatilityCheck(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Run simulation
-	simulator := NewCrowdSimulator(table, req.Config)
+	simulator := NewCrowdSimulator(table, h.loader.Sampler(table), req.Config)
 	var result *SimResult
 	if req.Config.ParallelWorkers > 1 {
 		result = simulator.RunParallel(nil)
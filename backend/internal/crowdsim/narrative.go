@@ -0,0 +1,145 @@
+package crowdsim
+
+import (
+	"fmt"
+	"math"
+)
+
+// Narrative severities.
+const (
+	NarrativeInfo    = "info"
+	NarrativeWarning = "warning"
+)
+
+// NarrativeLine is one plain-language statement about how the simulated
+// players experienced the game, e.g. "1 in 4 players never see a 10x win in
+// 200 spins".
+type NarrativeLine struct {
+	Key      string  `json:"key"`      // stable identifier of the template
+	Text     string  `json:"text"`     // rendered sentence
+	Value    float64 `json:"value"`    // metric the sentence is based on
+	Severity string  `json:"severity"` // "info" or "warning"
+}
+
+// NarrativeThresholds decide when a statement is flagged as a warning.
+type NarrativeThresholds struct {
+	MaxPercentNeverBigWin float64 `json:"max_percent_never_big_win"` // % of players never hitting a big win
+	MaxMedianLoss         float64 `json:"max_median_loss"`           // fraction of bankroll lost by the median player
+	MinPoP                float64 `json:"min_pop"`                   // fraction of players ending ahead
+	MaxPercentHalfGone    float64 `json:"max_percent_half_gone"`     // % of players losing over half the bankroll at some point
+	MaxAvgLoseStreak      float64 `json:"max_avg_lose_streak"`       // spins
+}
+
+// DefaultNarrativeThresholds are tuned for a typical medium volatility slot.
+func DefaultNarrativeThresholds() NarrativeThresholds {
+	return NarrativeThresholds{
+		MaxPercentNeverBigWin: 50,
+		MaxMedianLoss:         0.40,
+		MinPoP:                0.20,
+		MaxPercentHalfGone:    40,
+		MaxAvgLoseStreak:      15,
+	}
+}
+
+// BuildNarrative turns the metrics of a result into plain-language statements.
+func BuildNarrative(result *SimResult, t NarrativeThresholds) []NarrativeLine {
+	cfg := result.Config
+	if cfg.InitialBalance <= 0 || cfg.PlayerCount == 0 {
+		return nil
+	}
+	spins := cfg.SpinsPerSession
+	lines := make([]NarrativeLine, 0, 7)
+	add := func(key string, value float64, warn bool, format string, args ...any) {
+		severity := NarrativeInfo
+		if warn {
+			severity = NarrativeWarning
+		}
+		lines = append(lines, NarrativeLine{
+			Key:      key,
+			Text:     fmt.Sprintf(format, args...),
+			Value:    value,
+			Severity: severity,
+		})
+	}
+
+	// Big win exposure
+	never := result.BigWinStats.PercentNeverHit
+	switch {
+	case never <= 0:
+		add("big_win_never", never, false,
+			"Every player sees a %gx win within %d spins", cfg.BigWinThreshold, spins)
+	case never >= 100:
+		add("big_win_never", never, true,
+			"No player sees a %gx win within %d spins", cfg.BigWinThreshold, spins)
+	default:
+		add("big_win_never", never, never > t.MaxPercentNeverBigWin,
+			"%s players never see a %gx win in %d spins", sharePhrase(never), cfg.BigWinThreshold, spins)
+	}
+	if result.BigWinStats.PlayersHit > 0 {
+		add("big_win_wait", result.BigWinStats.MedianSpinsToFirst, false,
+			"Players who do hit a %gx win wait a median of %d spins for it",
+			cfg.BigWinThreshold, int(math.Round(result.BigWinStats.MedianSpinsToFirst)))
+	}
+
+	// Bankroll outcome of the median player
+	change := (result.BalanceStats.Median - cfg.InitialBalance) / cfg.InitialBalance
+	switch {
+	case change < -0.005:
+		add("median_bankroll", round4(change), -change > t.MaxMedianLoss,
+			"The median player loses %.0f%% of their bankroll over %d spins", -change*100, spins)
+	case change > 0.005:
+		add("median_bankroll", round4(change), false,
+			"The median player ends %.0f%% ahead after %d spins", change*100, spins)
+	default:
+		add("median_bankroll", round4(change), false,
+			"The median player roughly breaks even after %d spins", spins)
+	}
+
+	// Players ending ahead
+	add("players_ahead", result.FinalPoP, result.FinalPoP < t.MinPoP,
+		"%s players walk away with more than they started with", sharePhrase(result.FinalPoP*100))
+
+	// Worst moment of the session
+	halfGone := result.DrawdownStats.PercentBelow50
+	if halfGone > 0 {
+		add("half_bankroll_gone", halfGone, halfGone > t.MaxPercentHalfGone,
+			"%s players are down more than half their bankroll at some point", sharePhrase(halfGone))
+	}
+	if result.DangerStats.PercentWithDanger > 0 {
+		add("danger_zone", result.DangerStats.PercentWithDanger, false,
+			"%s players drop below %.0f%% of their bankroll", sharePhrase(result.DangerStats.PercentWithDanger), cfg.DangerThreshold*100)
+	}
+
+	// Dry spells
+	streak := result.StreakStats.AvgLoseStreak
+	add("lose_streak", streak, streak > t.MaxAvgLoseStreak,
+		"A typical player's longest losing streak is %d spins in a row (worst seen: %d)",
+		int(math.Round(streak)), result.StreakStats.MaxLoseStreak)
+
+	return lines
+}
+
+// sharePhrase renders a percentage of players the way people say it:
+// "1 in 4" for small shares, "7 in 10" for large ones.
+func sharePhrase(pct float64) string {
+	switch {
+	case pct <= 0:
+		return "No"
+	case pct >= 99.5:
+		return "Virtually all"
+	case pct < 1:
+		return "Fewer than 1 in 100"
+	case pct <= 50:
+		n := int(math.Round(100 / pct))
+		if n == 2 {
+			return "Half of"
+		}
+		return fmt.Sprintf("1 in %d", n)
+	default:
+		n := int(math.Round(pct / 10))
+		if n >= 10 {
+			return "Virtually all"
+		}
+		return fmt.Sprintf("%d in 10", n)
+	}
+}
@@ -41,6 +41,9 @@ type SimResult struct {
 	VolatilityProfile VolatilityProfile `json:"volatility_profile"`
 	CompositeScore    float64           `json:"composite_score"`
 
+	// Plain-language summary of the player experience
+	Narrative []NarrativeLine `json:"narrative,omitempty"`
+
 	// Detailed Data (when not in streaming mode and player count <= 1000)
 	PlayerSummaries []PlayerSummary `json:"player_summaries,omitempty"`
 }
@@ -2,63 +2,21 @@ package crowdsim
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"fmt"
-	"math/big"
 	mrand "math/rand"
-	"sort"
 	"sync"
 	"time"
 
+	"lutexplorer/internal/lut"
+
 	"stakergs"
 )
 
-// WeightedSampler provides efficient weighted random sampling.
-type WeightedSampler struct {
-	outcomes    []stakergs.Outcome
-	cumWeights  []uint64
-	totalWeight uint64
-}
-
-// NewWeightedSampler creates a sampler from a lookup table.
-func NewWeightedSampler(lut *stakergs.LookupTable) *WeightedSampler {
-	ws := &WeightedSampler{
-		outcomes:   lut.Outcomes,
-		cumWeights: make([]uint64, len(lut.Outcomes)),
-	}
-
-	var cumulative uint64
-	for i, o := range lut.Outcomes {
-		cumulative += o.Weight
-		ws.cumWeights[i] = cumulative
-	}
-	ws.totalWeight = cumulative
-
-	return ws
-}
-
-// Sample returns a random outcome based on weights using math/rand.
-func (ws *WeightedSampler) Sample(rng *mrand.Rand) stakergs.Outcome {
-	r := rng.Uint64() % ws.totalWeight
-	idx := sort.Search(len(ws.cumWeights), func(i int) bool {
-		return ws.cumWeights[i] > r
-	})
-	return ws.outcomes[idx]
-}
-
-// SampleCrypto returns a random outcome using crypto/rand.
-func (ws *WeightedSampler) SampleCrypto() stakergs.Outcome {
-	n, _ := rand.Int(rand.Reader, big.NewInt(int64(ws.totalWeight)))
-	r := uint64(n.Int64())
-
-	idx := sort.Search(len(ws.cumWeights), func(i int) bool {
-		return ws.cumWeights[i] > r
-	})
-	return ws.outcomes[idx]
-}
-
 // CrowdSimulator handles multi-player simulation.
 type CrowdSimulator struct {
-	sampler        *WeightedSampler
+	sampler        *lut.AliasSampler
+	table          *stakergs.LookupTable
 	config         SimConfig
 	theoreticalRTP float64
 	mode           string
@@ -67,25 +25,26 @@ type CrowdSimulator struct {
 	maxPayout      float64 // Maximum payout (normalized by cost)
 }
 
-// NewCrowdSimulator creates a new simulator for the given lookup table.
-func NewCrowdSimulator(lut *stakergs.LookupTable, config SimConfig) *CrowdSimulator {
-	modeCost := lut.Cost
+// NewCrowdSimulator creates a new simulator for the given lookup table,
+// drawing outcomes with sampler (built from the same table).
+func NewCrowdSimulator(table *stakergs.LookupTable, sampler *lut.AliasSampler, config SimConfig) *CrowdSimulator {
+	modeCost := table.Cost
 	if modeCost <= 0 {
 		modeCost = 1.0
 	}
 
 	// Use the canonical RTP calculation from stakergs:
 	// RTP = rawRTP / cost, where rawRTP = avg(payout/100)
-	theoreticalRTP := lut.RTP()
+	theoreticalRTP := table.RTP()
 
 	// Calculate breakeven rate: P(payout >= cost) and find max payout
 	breakevenRate := 0.0
 	var maxPayoutCents uint
-	totalWeight := lut.TotalWeight()
+	totalWeight := table.TotalWeight()
 	if totalWeight > 0 {
 		costCents := uint(modeCost * 100)
 		var breakevenWeight uint64
-		for _, o := range lut.Outcomes {
+		for _, o := range table.Outcomes {
 			if o.Payout >= costCents {
 				breakevenWeight += o.Weight
 			}
@@ -105,18 +64,30 @@ func NewCrowdSimulator(lut *stakergs.LookupTable, config SimConfig) *CrowdSimula
 	// - InitialBalance stays as user input (number of unit bets)
 	// This way RTP = avg(payout) / 1 = avg((outcome.Payout/100)/cost) = theoreticalRTP
 	config.BetAmount = 1.0
+	if config.Seed == nil { // Validate normally picks one
+		seed := mrand.Int63()
+		config.Seed = &seed
+	}
 
 	return &CrowdSimulator{
-		sampler:        NewWeightedSampler(lut),
+		sampler:        sampler,
+		table:          table,
 		config:         config,
 		theoreticalRTP: theoreticalRTP,
-		mode:           lut.Mode,
+		mode:           table.Mode,
 		modeCost:       modeCost,
 		breakevenRate:  breakevenRate,
 		maxPayout:      maxPayout,
 	}
 }
 
+// sampleCrypto returns a random outcome using crypto/rand.
+func (s *CrowdSimulator) sampleCrypto() stakergs.Outcome {
+	var buf [8]byte
+	rand.Read(buf[:])
+	return s.table.Outcomes[s.sampler.Index(binary.LittleEndian.Uint64(buf[:]))]
+}
+
 // Progress reports simulation progress.
 type Progress struct {
 	PlayersComplete int   `json:"players_complete"`
@@ -125,6 +96,35 @@ type Progress struct {
 	ElapsedMs       int64 `json:"elapsed_ms"`
 }
 
+// runPlayer plays one player's session. Without crypto RNG each player
+// draws from its own stream seeded from the run seed and the player's
+// index, so a seed reproduces the same players however the work is split
+// between workers.
+func (s *CrowdSimulator) runPlayer(id int, trackHistory bool) *Player {
+	player := NewPlayer(id, s.config.InitialBalance, trackHistory, s.config.SpinsPerSession)
+
+	var rng *mrand.Rand
+	if !s.config.UseCryptoRNG {
+		rng = mrand.New(mrand.NewSource(lut.TrialSeed(*s.config.Seed, id)))
+	}
+
+	for spin := 0; spin < s.config.SpinsPerSession; spin++ {
+		var outcome stakergs.Outcome
+		if s.config.UseCryptoRNG {
+			outcome = s.sampleCrypto()
+		} else {
+			outcome = s.sampler.Sample(rng)
+		}
+		// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
+		// Normalize by cost to get multiplier relative to mode cost
+		// Example: bonus cost=350, payout=34055 -> 340.55 / 350 = 0.973x
+		payout := float64(outcome.Payout) / 100.0 / s.modeCost
+
+		player.ProcessSpin(spin, payout, s.config.BetAmount, s.config.BigWinThreshold, s.config.DangerThreshold)
+	}
+	return player
+}
+
 // Run executes the full simulation sequentially.
 func (s *CrowdSimulator) Run(progressCallback func(Progress)) *SimResult {
 	start := time.Now()
@@ -132,32 +132,8 @@ func (s *CrowdSimulator) Run(progressCallback func(Progress)) *SimResult {
 	trackHistory := !s.config.StreamingMode
 	players := make([]*Player, s.config.PlayerCount)
 
-	// Create a single RNG for sequential mode
-	rng := mrand.New(mrand.NewSource(time.Now().UnixNano()))
-
 	for i := 0; i < s.config.PlayerCount; i++ {
-		player := NewPlayer(i, s.config.InitialBalance, trackHistory, s.config.SpinsPerSession)
-
-		// Run session
-		for spin := 0; spin < s.config.SpinsPerSession; spin++ {
-			var payout float64
-			if s.config.UseCryptoRNG {
-				outcome := s.sampler.SampleCrypto()
-				// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-				// Normalize by cost to get multiplier relative to mode cost
-				// Example: bonus cost=350, payout=34055 -> 340.55 / 350 = 0.973x
-				payout = float64(outcome.Payout) / 100.0 / s.modeCost
-			} else {
-				outcome := s.sampler.Sample(rng)
-				// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-				// Normalize by cost to get multiplier relative to mode cost
-				payout = float64(outcome.Payout) / 100.0 / s.modeCost
-			}
-
-			player.ProcessSpin(spin, payout, s.config.BetAmount, s.config.BigWinThreshold, s.config.DangerThreshold)
-		}
-
-		players[i] = player
+		players[i] = s.runPlayer(i, trackHistory)
 
 		// Report progress every 100 players
 		if progressCallback != nil && (i+1)%100 == 0 {
@@ -173,7 +149,8 @@ func (s *CrowdSimulator) Run(progressCallback func(Progress)) *SimResult {
 	return s.calculateResults(players, time.Since(start))
 }
 
-// RunParallel executes simulation with parallel workers.
+// RunParallel executes simulation with a pool of ParallelWorkers
+// goroutines. Results are identical to Run for the same seed.
 func (s *CrowdSimulator) RunParallel(progressCallback func(Progress)) *SimResult {
 	start := time.Now()
 
@@ -194,31 +171,8 @@ func (s *CrowdSimulator) RunParallel(progressCallback func(Progress)) *SimResult
 		go func() {
 			defer wg.Done()
 
-			// Each worker has its own RNG
-			rng := mrand.New(mrand.NewSource(time.Now().UnixNano() + int64(mrand.Intn(1000000))))
-
 			for playerID := range playerChan {
-				player := NewPlayer(playerID, s.config.InitialBalance, trackHistory, s.config.SpinsPerSession)
-
-				// Run session
-				for spin := 0; spin < s.config.SpinsPerSession; spin++ {
-					var payout float64
-					if s.config.UseCryptoRNG {
-						outcome := s.sampler.SampleCrypto()
-						// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-						// Normalize by cost to get multiplier relative to mode cost
-						payout = float64(outcome.Payout) / 100.0 / s.modeCost
-					} else {
-						outcome := s.sampler.Sample(rng)
-						// Payout from LUT is multiplier * 100 (e.g., 150 = 1.5x of base bet)
-						// Normalize by cost to get multiplier relative to mode cost
-						payout = float64(outcome.Payout) / 100.0 / s.modeCost
-					}
-
-					player.ProcessSpin(spin, payout, s.config.BetAmount, s.config.BigWinThreshold, s.config.DangerThreshold)
-				}
-
-				players[playerID] = player
+				players[playerID] = s.runPlayer(playerID, trackHistory)
 
 				// Update progress
 				if progressCallback != nil {
@@ -303,6 +257,7 @@ func (s *CrowdSimulator) calculateResults(players []*Player, duration time.Durat
 	result.BigWinStats = CalcBigWinStats(players)
 	result.VolatilityProfile = ClassifyVolatility(result.FinalPoP, result.BalanceStats, result.PeakStats, s.config.InitialBalance)
 	result.CompositeScore = CalcCompositeScore(result, DefaultRankingWeights(), s.config.InitialBalance)
+	result.Narrative = BuildNarrative(result, DefaultNarrativeThresholds())
 
 	// Player summaries (limit to avoid huge responses)
 	if !s.config.StreamingMode && len(players) <= 1000 {
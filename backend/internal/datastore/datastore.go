@@ -0,0 +1,157 @@
+// Package datastore is the optional embedded SQLite database of a server
+// started with -data-dir. It keeps LGS sessions, stored simulation results,
+// optimizer and compliance run history and the audit log in one file, so a
+// long-lived team server retains its state across restarts and the data can
+// be inspected with any SQLite client.
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite" // pure Go driver, keeps CGO_ENABLED=0 builds working
+)
+
+// FileName is the database file inside the data directory.
+const FileName = "lutexplorer.db"
+
+// migrations upgrade the schema one version at a time; the database's
+// user_version is the number applied. Append only: never edit a released
+// migration. The sessions table is created by lgs.NewSessionDB.
+var migrations = []string{
+	// 1: stored simulation results (see simstore)
+	`CREATE TABLE simulations (
+		id         TEXT PRIMARY KEY,
+		library    TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		mode       TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		request    TEXT NOT NULL, -- JSON
+		result     TEXT NOT NULL  -- JSON lut.SimulationResult
+	);
+	CREATE INDEX simulations_library_mode ON simulations (library, mode);`,
+
+	// 2: optimizer and compliance run history
+	`CREATE TABLE optimizer_runs (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		library    TEXT NOT NULL,
+		mode       TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		created_at TEXT NOT NULL,
+		request    TEXT NOT NULL, -- JSON
+		result     TEXT NOT NULL  -- JSON
+	);
+	CREATE INDEX optimizer_runs_library_mode ON optimizer_runs (library, mode);
+	CREATE TABLE compliance_runs (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		library    TEXT NOT NULL,
+		mode       TEXT NOT NULL,
+		profile    TEXT NOT NULL,
+		passed     INTEGER NOT NULL,
+		created_at TEXT NOT NULL,
+		result     TEXT NOT NULL  -- JSON lut.ComplianceResult
+	);
+	CREATE INDEX compliance_runs_library_mode ON compliance_runs (library, mode);`,
+
+	// 3: audit log of state-changing requests
+	`CREATE TABLE audit_log (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		at          TEXT NOT NULL,
+		client      TEXT NOT NULL,
+		method      TEXT NOT NULL,
+		path        TEXT NOT NULL,
+		status      INTEGER NOT NULL,
+		duration_ms INTEGER NOT NULL
+	);`,
+}
+
+// Store is an open data directory database.
+type Store struct {
+	db   *sql.DB
+	path string
+}
+
+// Open opens the database in dir, creating the directory and file if
+// needed, and migrates it to the current schema.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	path := filepath.Join(dir, FileName)
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	// One writer at a time; SQLite serializes writes anyway
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000"} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize %s: %w", path, err)
+		}
+	}
+	s := &Store{db: db, path: path}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate applies the migrations the database has not seen yet, each in a
+// transaction with its version bump.
+func (s *Store) migrate() error {
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("%s has schema version %d, newer than this build supports (%d)", s.path, version, len(migrations))
+	}
+	for v := version; v < len(migrations); v++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[v]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d failed: %w", v+1, err)
+		}
+		// PRAGMA takes no parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", v+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d failed: %w", v+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d failed: %w", v+1, err)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the number of migrations applied.
+func (s *Store) SchemaVersion() (int, error) {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// DB returns the database, for the packages that keep their own tables in
+// it (sessions, simulations).
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Path returns the database file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,182 @@
+package datastore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultListLimit and MaxListLimit bound the rows a list returns.
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 1000
+)
+
+// OptimizerRun is a finished optimizer run.
+type OptimizerRun struct {
+	ID        int64           `json:"id"`
+	Library   string          `json:"library"`
+	Mode      string          `json:"mode"`
+	Kind      string          `json:"kind"` // e.g. "bucket", "fit-distribution"
+	CreatedAt time.Time       `json:"created_at"`
+	Request   json.RawMessage `json:"request"`
+	Result    json.RawMessage `json:"result"`
+}
+
+// ComplianceRun is the result of a compliance check of one mode.
+type ComplianceRun struct {
+	ID        int64           `json:"id"`
+	Library   string          `json:"library"`
+	Mode      string          `json:"mode"`
+	Profile   string          `json:"profile"`
+	Passed    bool            `json:"passed"`
+	CreatedAt time.Time       `json:"created_at"`
+	Result    json.RawMessage `json:"result"`
+}
+
+// AuditEntry is a state-changing request the server handled.
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	At         time.Time `json:"at"`
+	Client     string    `json:"client"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// ClampLimit applies the default and maximum to a requested list size.
+func ClampLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultListLimit
+	}
+	return min(limit, MaxListLimit)
+}
+
+// RecordOptimizerRun stores an optimizer run; request and result are
+// encoded as JSON.
+func (s *Store) RecordOptimizerRun(library, mode, kind string, request, result interface{}) error {
+	req, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	res, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO optimizer_runs (library, mode, kind, created_at, request, result)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		library, mode, kind, formatTime(time.Now()), string(req), string(res))
+	if err != nil {
+		return fmt.Errorf("failed to record optimizer run: %w", err)
+	}
+	return nil
+}
+
+// OptimizerRuns lists the runs of a library, newest first. An empty mode
+// lists all modes.
+func (s *Store) OptimizerRuns(library, mode string, limit int) ([]OptimizerRun, error) {
+	rows, err := s.db.Query(`SELECT id, library, mode, kind, created_at, request, result
+		FROM optimizer_runs WHERE library = ? AND (? = '' OR mode = ?)
+		ORDER BY id DESC LIMIT ?`, library, mode, mode, ClampLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list optimizer runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]OptimizerRun, 0)
+	for rows.Next() {
+		var run OptimizerRun
+		var createdAt, req, res string
+		if err := rows.Scan(&run.ID, &run.Library, &run.Mode, &run.Kind, &createdAt, &req, &res); err != nil {
+			return nil, fmt.Errorf("failed to list optimizer runs: %w", err)
+		}
+		run.CreatedAt = parseTime(createdAt)
+		run.Request, run.Result = json.RawMessage(req), json.RawMessage(res)
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// RecordComplianceRun stores the compliance result of a mode.
+func (s *Store) RecordComplianceRun(library, mode, profile string, passed bool, result interface{}) error {
+	res, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO compliance_runs (library, mode, profile, passed, created_at, result)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		library, mode, profile, passed, formatTime(time.Now()), string(res))
+	if err != nil {
+		return fmt.Errorf("failed to record compliance run: %w", err)
+	}
+	return nil
+}
+
+// ComplianceRuns lists the compliance runs of a library, newest first. An
+// empty mode lists all modes.
+func (s *Store) ComplianceRuns(library, mode string, limit int) ([]ComplianceRun, error) {
+	rows, err := s.db.Query(`SELECT id, library, mode, profile, passed, created_at, result
+		FROM compliance_runs WHERE library = ? AND (? = '' OR mode = ?)
+		ORDER BY id DESC LIMIT ?`, library, mode, mode, ClampLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]ComplianceRun, 0)
+	for rows.Next() {
+		var run ComplianceRun
+		var createdAt, res string
+		if err := rows.Scan(&run.ID, &run.Library, &run.Mode, &run.Profile, &run.Passed, &createdAt, &res); err != nil {
+			return nil, fmt.Errorf("failed to list compliance runs: %w", err)
+		}
+		run.CreatedAt = parseTime(createdAt)
+		run.Result = json.RawMessage(res)
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// RecordAudit appends an entry to the audit log.
+func (s *Store) RecordAudit(e AuditEntry) error {
+	_, err := s.db.Exec(`INSERT INTO audit_log (at, client, method, path, status, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		formatTime(e.At), e.Client, e.Method, e.Path, e.Status, e.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// Audit lists audit log entries, newest first, optionally of one client.
+func (s *Store) Audit(client string, limit int) ([]AuditEntry, error) {
+	rows, err := s.db.Query(`SELECT id, at, client, method, path, status, duration_ms
+		FROM audit_log WHERE (? = '' OR client = ?)
+		ORDER BY id DESC LIMIT ?`, client, client, ClampLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]AuditEntry, 0)
+	for rows.Next() {
+		var e AuditEntry
+		var at string
+		if err := rows.Scan(&e.ID, &at, &e.Client, &e.Method, &e.Path, &e.Status, &e.DurationMs); err != nil {
+			return nil, fmt.Errorf("failed to list audit log: %w", err)
+		}
+		e.At = parseTime(at)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
@@ -0,0 +1,184 @@
+// Package examples holds sample extensions: one analyzer, one compliance
+// check and one optimizer. They are imported by cmd/main.go and double as a
+// template for studio-specific extensions.
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"lutexplorer/internal/extensions"
+	"lutexplorer/internal/lut"
+)
+
+func init() {
+	extensions.RegisterAnalyzer(rtpConcentration{})
+	extensions.RegisterCheck(maxWinShare{limit: 0.05})
+	extensions.RegisterOptimizer(scaleLosses{})
+}
+
+// rtpConcentration reports how much of the RTP the largest payouts carry.
+type rtpConcentration struct{}
+
+func (rtpConcentration) Name() string { return "rtp-concentration" }
+
+func (rtpConcentration) Description() string {
+	return "Share of RTP contributed by the top N payout levels (?top=10)"
+}
+
+// PayoutShare is one payout level's contribution to RTP.
+type PayoutShare struct {
+	Payout   float64 `json:"payout"`
+	RTP      float64 `json:"rtp"`
+	RTPShare float64 `json:"rtp_share"`
+}
+
+// ConcentrationResult is the output of the rtp-concentration analyzer.
+type ConcentrationResult struct {
+	Top      []PayoutShare `json:"top"`
+	TopShare float64       `json:"top_share"` // RTP share of all levels in Top
+}
+
+func (rtpConcentration) Analyze(req *extensions.Request) (interface{}, error) {
+	top, err := strconv.Atoi(req.Param("top", "10"))
+	if err != nil || top < 1 {
+		return nil, fmt.Errorf("top must be a positive integer")
+	}
+
+	total := float64(req.Table.TotalWeight())
+	cost := req.Table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+	byPayout := make(map[uint]float64)
+	for _, o := range req.Table.Outcomes {
+		if o.Payout > 0 {
+			byPayout[o.Payout] += float64(o.Weight) * float64(o.Payout) / 100 / total / cost
+		}
+	}
+	levels := make([]PayoutShare, 0, len(byPayout))
+	for payout, rtp := range byPayout {
+		levels = append(levels, PayoutShare{Payout: float64(payout) / 100, RTP: rtp})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Payout > levels[j].Payout })
+	if len(levels) > top {
+		levels = levels[:top]
+	}
+
+	result := ConcentrationResult{Top: levels}
+	for i := range levels {
+		if req.Stats.RTP > 0 {
+			levels[i].RTPShare = levels[i].RTP / req.Stats.RTP
+		}
+		result.TopShare += levels[i].RTPShare
+	}
+	return result, nil
+}
+
+// maxWinShare fails modes where the max-win outcomes carry more than limit
+// of the RTP, which makes the RTP hinge on a result players rarely see.
+type maxWinShare struct {
+	limit float64
+}
+
+func (maxWinShare) Name() string { return "max-win-rtp-share" }
+
+func (c maxWinShare) Description() string {
+	return fmt.Sprintf("Max-win outcomes carry at most %.0f%% of RTP", c.limit*100)
+}
+
+func (c maxWinShare) Check(req *extensions.Request) lut.ComplianceCheck {
+	maxPayout := req.Table.MaxPayout()
+	total := float64(req.Table.TotalWeight())
+	var rtp float64
+	for _, o := range req.Table.Outcomes {
+		if o.Payout == maxPayout {
+			rtp += float64(o.Weight) * float64(o.Payout) / 100 / total
+		}
+	}
+	if req.Stats.Cost > 0 {
+		rtp /= req.Stats.Cost
+	}
+	share := 0.0
+	if req.Stats.RTP > 0 {
+		share = rtp / req.Stats.RTP
+	}
+
+	check := lut.ComplianceCheck{
+		Passed:   share <= c.limit,
+		Value:    fmt.Sprintf("%.2f%%", share*100),
+		Expected: fmt.Sprintf("≤ %.0f%%", c.limit*100),
+		Severity: lut.SeverityWarning,
+	}
+	if maxPayout == 0 {
+		check.Passed = true
+		check.Value = "no wins"
+	}
+	return check
+}
+
+// scaleLosses hits a target RTP by scaling the weights of the zero-payout
+// outcomes, leaving the paying outcomes' relative odds untouched.
+type scaleLosses struct{}
+
+func (scaleLosses) Name() string { return "scale-losses" }
+
+func (scaleLosses) Description() string {
+	return `Reach a target RTP by scaling only the losing outcomes' weights (body: {"target_rtp": 0.96})`
+}
+
+// ScaleLossesParams is the request body of the scale-losses optimizer.
+type ScaleLossesParams struct {
+	TargetRTP float64 `json:"target_rtp"`
+}
+
+func (scaleLosses) Optimize(req *extensions.Request) (*extensions.OptimizeResult, error) {
+	var params ScaleLossesParams
+	if len(req.Body) == 0 {
+		return nil, fmt.Errorf("target_rtp is required")
+	}
+	if err := json.Unmarshal(req.Body, &params); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	if params.TargetRTP <= 0 || params.TargetRTP >= 10 {
+		return nil, fmt.Errorf("target_rtp must be a fraction such as 0.96")
+	}
+
+	cost := req.Table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+	// RTP = S / (c * (Wp + k*W0)) with S the weighted payout sum, so the
+	// losses must be scaled by k = (S/(c*target) - Wp) / W0.
+	var sum, paying, losing float64
+	for _, o := range req.Table.Outcomes {
+		if o.Payout > 0 {
+			sum += float64(o.Weight) * float64(o.Payout) / 100
+			paying += float64(o.Weight)
+		} else {
+			losing += float64(o.Weight)
+		}
+	}
+	if losing == 0 {
+		return nil, fmt.Errorf("mode has no losing outcomes to scale")
+	}
+	k := (sum/(cost*params.TargetRTP) - paying) / losing
+	if k <= 0 || math.IsInf(k, 0) || math.IsNaN(k) {
+		return nil, fmt.Errorf("target_rtp %.4f is out of reach by scaling losses alone", params.TargetRTP)
+	}
+
+	weights := make([]uint64, len(req.Table.Outcomes))
+	for i, o := range req.Table.Outcomes {
+		weights[i] = o.Weight
+		if o.Payout == 0 && o.Weight > 0 {
+			weights[i] = max(1, uint64(math.Round(float64(o.Weight)*k)))
+		}
+	}
+	return &extensions.OptimizeResult{
+		Weights: weights,
+		Details: map[string]float64{"loss_scale": k},
+	}, nil
+}
@@ -0,0 +1,201 @@
+// Package extensions is the registration point for studio-specific
+// analyzers, compliance checks and optimizers. Extensions are compiled in:
+// a package implements one of the interfaces below, registers it from an
+// init function and is imported for its side effects from cmd/main.go,
+// the way database/sql drivers are:
+//
+//	import _ "lutexplorer/internal/extensions/examples"
+//
+// Registered extensions are listed and run under /api/extensions without
+// touching the route setup.
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"lutexplorer/internal/lut"
+
+	"stakergs"
+)
+
+// Request is the input of one extension call.
+type Request struct {
+	Mode  string
+	Table *stakergs.LookupTable // shared with the loader; must not be modified
+	Stats *lut.Statistics       // statistics of Table
+	// Params holds the query parameters of the call
+	Params map[string]string
+	// Body is the JSON request body of optimizer calls (nil if empty)
+	Body json.RawMessage
+}
+
+// Param returns a query parameter or def if it is not set.
+func (r *Request) Param(name, def string) string {
+	if v, ok := r.Params[name]; ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// Extension is what every kind of extension provides.
+type Extension interface {
+	// Name identifies the extension in URLs: lowercase letters, digits, '-'
+	// and '_'.
+	Name() string
+	Description() string
+}
+
+// Analyzer computes a custom report for a mode. The result is sent as the
+// response data.
+type Analyzer interface {
+	Extension
+	Analyze(req *Request) (interface{}, error)
+}
+
+// Check is a custom compliance check. Checks report like the built-in
+// ones; ID defaults to the check's name and NameKey to its description.
+type Check interface {
+	Extension
+	Check(req *Request) lut.ComplianceCheck
+}
+
+// OptimizeResult is the output of an optimizer: new weights for the
+// table's outcomes, in table order, and optional details.
+type OptimizeResult struct {
+	Weights []uint64
+	Details interface{}
+}
+
+// Optimizer proposes new weights for a mode. Weights are not applied; the
+// client saves them with POST /api/optimizer/{mode}/apply like any other
+// optimizer result.
+type Optimizer interface {
+	Extension
+	Optimize(req *Request) (*OptimizeResult, error)
+}
+
+// Kinds of extensions, as listed by Registered.
+const (
+	KindAnalyzer  = "analyzer"
+	KindCheck     = "check"
+	KindOptimizer = "optimizer"
+)
+
+// Info describes a registered extension.
+type Info struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var (
+	mu         sync.RWMutex
+	analyzers  = make(map[string]Analyzer)
+	checks     = make(map[string]Check)
+	optimizers = make(map[string]Optimizer)
+)
+
+// register adds an extension to m. Like sql.Register it panics on an
+// invalid or duplicate name, since both are programming errors found at
+// startup.
+func register[T Extension](m map[string]T, kind string, ext T) {
+	name := ext.Name()
+	if !nameRe.MatchString(name) {
+		panic(fmt.Sprintf("extensions: invalid %s name %q", kind, name))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := m[name]; dup {
+		panic(fmt.Sprintf("extensions: %s %q registered twice", kind, name))
+	}
+	m[name] = ext
+}
+
+// RegisterAnalyzer makes an analyzer available. Call it from an init
+// function.
+func RegisterAnalyzer(a Analyzer) { register(analyzers, KindAnalyzer, a) }
+
+// RegisterCheck makes a compliance check available. Call it from an init
+// function.
+func RegisterCheck(c Check) { register(checks, KindCheck, c) }
+
+// RegisterOptimizer makes an optimizer available. Call it from an init
+// function.
+func RegisterOptimizer(o Optimizer) { register(optimizers, KindOptimizer, o) }
+
+// GetAnalyzer returns a registered analyzer by name.
+func GetAnalyzer(name string) (Analyzer, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := analyzers[name]
+	return a, ok
+}
+
+// GetOptimizer returns a registered optimizer by name.
+func GetOptimizer(name string) (Optimizer, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	o, ok := optimizers[name]
+	return o, ok
+}
+
+// Checks returns the registered checks sorted by name.
+func Checks() []Check {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Check, 0, len(checks))
+	for _, c := range checks {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// Registered lists all extensions, sorted by kind and name.
+func Registered() []Info {
+	mu.RLock()
+	defer mu.RUnlock()
+	var list []Info
+	for _, a := range analyzers {
+		list = append(list, Info{KindAnalyzer, a.Name(), a.Description()})
+	}
+	for _, c := range checks {
+		list = append(list, Info{KindCheck, c.Name(), c.Description()})
+	}
+	for _, o := range optimizers {
+		list = append(list, Info{KindOptimizer, o.Name(), o.Description()})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// RunCheck runs a check, recovering from panics so a faulty extension
+// fails its own check instead of the request, and fills in the defaults.
+func RunCheck(c Check, req *Request) (check lut.ComplianceCheck) {
+	defer func() {
+		if r := recover(); r != nil {
+			check = lut.ComplianceCheck{
+				Value:    fmt.Sprintf("check panicked: %v", r),
+				Severity: lut.SeverityError,
+			}
+		}
+		if check.ID == "" {
+			check.ID = lut.ComplianceCheckID(c.Name())
+		}
+		if check.NameKey == "" {
+			check.NameKey = c.Description()
+		}
+	}()
+	return c.Check(req)
+}
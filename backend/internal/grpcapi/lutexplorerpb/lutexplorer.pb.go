@@ -0,0 +1,1343 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        (unknown)
+// source: lutexplorer/v1/lutexplorer.proto
+
+// gRPC mirror of the LUT analysis REST endpoints, for math tooling that
+// would rather use generated clients than hand-rolled HTTP calls.
+
+package lutexplorerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListModesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModesRequest) Reset() {
+	*x = ListModesRequest{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModesRequest) ProtoMessage() {}
+
+func (x *ListModesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModesRequest.ProtoReflect.Descriptor instead.
+func (*ListModesRequest) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{0}
+}
+
+type ModeSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Cost          float64                `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	Outcomes      int64                  `protobuf:"varint,3,opt,name=outcomes,proto3" json:"outcomes,omitempty"`
+	Rtp           float64                `protobuf:"fixed64,4,opt,name=rtp,proto3" json:"rtp,omitempty"`
+	HitRate       float64                `protobuf:"fixed64,5,opt,name=hit_rate,json=hitRate,proto3" json:"hit_rate,omitempty"`
+	MaxPayout     float64                `protobuf:"fixed64,6,opt,name=max_payout,json=maxPayout,proto3" json:"max_payout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModeSummary) Reset() {
+	*x = ModeSummary{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModeSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModeSummary) ProtoMessage() {}
+
+func (x *ModeSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModeSummary.ProtoReflect.Descriptor instead.
+func (*ModeSummary) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ModeSummary) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ModeSummary) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *ModeSummary) GetOutcomes() int64 {
+	if x != nil {
+		return x.Outcomes
+	}
+	return 0
+}
+
+func (x *ModeSummary) GetRtp() float64 {
+	if x != nil {
+		return x.Rtp
+	}
+	return 0
+}
+
+func (x *ModeSummary) GetHitRate() float64 {
+	if x != nil {
+		return x.HitRate
+	}
+	return 0
+}
+
+func (x *ModeSummary) GetMaxPayout() float64 {
+	if x != nil {
+		return x.MaxPayout
+	}
+	return 0
+}
+
+type ListModesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Modes         []*ModeSummary         `protobuf:"bytes,1,rep,name=modes,proto3" json:"modes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModesResponse) Reset() {
+	*x = ListModesResponse{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModesResponse) ProtoMessage() {}
+
+func (x *ListModesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModesResponse.ProtoReflect.Descriptor instead.
+func (*ListModesResponse) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListModesResponse) GetModes() []*ModeSummary {
+	if x != nil {
+		return x.Modes
+	}
+	return nil
+}
+
+type GetModeStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModeStatsRequest) Reset() {
+	*x = GetModeStatsRequest{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModeStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModeStatsRequest) ProtoMessage() {}
+
+func (x *GetModeStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModeStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetModeStatsRequest) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetModeStatsRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type PayoutBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RangeStart    float64                `protobuf:"fixed64,1,opt,name=range_start,json=rangeStart,proto3" json:"range_start,omitempty"`
+	RangeEnd      float64                `protobuf:"fixed64,2,opt,name=range_end,json=rangeEnd,proto3" json:"range_end,omitempty"`
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Weight        uint64                 `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	Probability   float64                `protobuf:"fixed64,5,opt,name=probability,proto3" json:"probability,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayoutBucket) Reset() {
+	*x = PayoutBucket{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayoutBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayoutBucket) ProtoMessage() {}
+
+func (x *PayoutBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayoutBucket.ProtoReflect.Descriptor instead.
+func (*PayoutBucket) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PayoutBucket) GetRangeStart() float64 {
+	if x != nil {
+		return x.RangeStart
+	}
+	return 0
+}
+
+func (x *PayoutBucket) GetRangeEnd() float64 {
+	if x != nil {
+		return x.RangeEnd
+	}
+	return 0
+}
+
+func (x *PayoutBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PayoutBucket) GetWeight() uint64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *PayoutBucket) GetProbability() float64 {
+	if x != nil {
+		return x.Probability
+	}
+	return 0
+}
+
+type PayoutInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SimId         int64                  `protobuf:"varint,1,opt,name=sim_id,json=simId,proto3" json:"sim_id,omitempty"`
+	Payout        float64                `protobuf:"fixed64,2,opt,name=payout,proto3" json:"payout,omitempty"`
+	Weight        uint64                 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Odds          string                 `protobuf:"bytes,4,opt,name=odds,proto3" json:"odds,omitempty"`
+	Count         int64                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayoutInfo) Reset() {
+	*x = PayoutInfo{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayoutInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayoutInfo) ProtoMessage() {}
+
+func (x *PayoutInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayoutInfo.ProtoReflect.Descriptor instead.
+func (*PayoutInfo) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PayoutInfo) GetSimId() int64 {
+	if x != nil {
+		return x.SimId
+	}
+	return 0
+}
+
+func (x *PayoutInfo) GetPayout() float64 {
+	if x != nil {
+		return x.Payout
+	}
+	return 0
+}
+
+func (x *PayoutInfo) GetWeight() uint64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *PayoutInfo) GetOdds() string {
+	if x != nil {
+		return x.Odds
+	}
+	return ""
+}
+
+func (x *PayoutInfo) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ModeStats struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Mode              string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Cost              float64                `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	TotalOutcomes     int64                  `protobuf:"varint,3,opt,name=total_outcomes,json=totalOutcomes,proto3" json:"total_outcomes,omitempty"`
+	TotalWeight       uint64                 `protobuf:"varint,4,opt,name=total_weight,json=totalWeight,proto3" json:"total_weight,omitempty"`
+	Rtp               float64                `protobuf:"fixed64,5,opt,name=rtp,proto3" json:"rtp,omitempty"`
+	HitRate           float64                `protobuf:"fixed64,6,opt,name=hit_rate,json=hitRate,proto3" json:"hit_rate,omitempty"`
+	MaxPayout         float64                `protobuf:"fixed64,7,opt,name=max_payout,json=maxPayout,proto3" json:"max_payout,omitempty"`
+	MinPayout         float64                `protobuf:"fixed64,8,opt,name=min_payout,json=minPayout,proto3" json:"min_payout,omitempty"`
+	MeanPayout        float64                `protobuf:"fixed64,9,opt,name=mean_payout,json=meanPayout,proto3" json:"mean_payout,omitempty"`
+	MedianPayout      float64                `protobuf:"fixed64,10,opt,name=median_payout,json=medianPayout,proto3" json:"median_payout,omitempty"`
+	Variance          float64                `protobuf:"fixed64,11,opt,name=variance,proto3" json:"variance,omitempty"`
+	StdDev            float64                `protobuf:"fixed64,12,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	Volatility        float64                `protobuf:"fixed64,13,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	MeanMedianRatio   float64                `protobuf:"fixed64,14,opt,name=mean_median_ratio,json=meanMedianRatio,proto3" json:"mean_median_ratio,omitempty"`
+	ZeroPayoutRate    float64                `protobuf:"fixed64,15,opt,name=zero_payout_rate,json=zeroPayoutRate,proto3" json:"zero_payout_rate,omitempty"`
+	BreakevenRate     float64                `protobuf:"fixed64,16,opt,name=breakeven_rate,json=breakevenRate,proto3" json:"breakeven_rate,omitempty"`
+	CostAdjVolatility float64                `protobuf:"fixed64,17,opt,name=cost_adj_volatility,json=costAdjVolatility,proto3" json:"cost_adj_volatility,omitempty"`
+	PayoutBuckets     []*PayoutBucket        `protobuf:"bytes,18,rep,name=payout_buckets,json=payoutBuckets,proto3" json:"payout_buckets,omitempty"`
+	TopPayouts        []*PayoutInfo          `protobuf:"bytes,19,rep,name=top_payouts,json=topPayouts,proto3" json:"top_payouts,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ModeStats) Reset() {
+	*x = ModeStats{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModeStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModeStats) ProtoMessage() {}
+
+func (x *ModeStats) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModeStats.ProtoReflect.Descriptor instead.
+func (*ModeStats) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ModeStats) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ModeStats) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *ModeStats) GetTotalOutcomes() int64 {
+	if x != nil {
+		return x.TotalOutcomes
+	}
+	return 0
+}
+
+func (x *ModeStats) GetTotalWeight() uint64 {
+	if x != nil {
+		return x.TotalWeight
+	}
+	return 0
+}
+
+func (x *ModeStats) GetRtp() float64 {
+	if x != nil {
+		return x.Rtp
+	}
+	return 0
+}
+
+func (x *ModeStats) GetHitRate() float64 {
+	if x != nil {
+		return x.HitRate
+	}
+	return 0
+}
+
+func (x *ModeStats) GetMaxPayout() float64 {
+	if x != nil {
+		return x.MaxPayout
+	}
+	return 0
+}
+
+func (x *ModeStats) GetMinPayout() float64 {
+	if x != nil {
+		return x.MinPayout
+	}
+	return 0
+}
+
+func (x *ModeStats) GetMeanPayout() float64 {
+	if x != nil {
+		return x.MeanPayout
+	}
+	return 0
+}
+
+func (x *ModeStats) GetMedianPayout() float64 {
+	if x != nil {
+		return x.MedianPayout
+	}
+	return 0
+}
+
+func (x *ModeStats) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+func (x *ModeStats) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *ModeStats) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *ModeStats) GetMeanMedianRatio() float64 {
+	if x != nil {
+		return x.MeanMedianRatio
+	}
+	return 0
+}
+
+func (x *ModeStats) GetZeroPayoutRate() float64 {
+	if x != nil {
+		return x.ZeroPayoutRate
+	}
+	return 0
+}
+
+func (x *ModeStats) GetBreakevenRate() float64 {
+	if x != nil {
+		return x.BreakevenRate
+	}
+	return 0
+}
+
+func (x *ModeStats) GetCostAdjVolatility() float64 {
+	if x != nil {
+		return x.CostAdjVolatility
+	}
+	return 0
+}
+
+func (x *ModeStats) GetPayoutBuckets() []*PayoutBucket {
+	if x != nil {
+		return x.PayoutBuckets
+	}
+	return nil
+}
+
+func (x *ModeStats) GetTopPayouts() []*PayoutInfo {
+	if x != nil {
+		return x.TopPayouts
+	}
+	return nil
+}
+
+type SimulateRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Mode        string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Spins       int64                  `protobuf:"varint,2,opt,name=spins,proto3" json:"spins,omitempty"`
+	Trials      int64                  `protobuf:"varint,3,opt,name=trials,proto3" json:"trials,omitempty"`
+	TargetRtp   float64                `protobuf:"fixed64,4,opt,name=target_rtp,json=targetRtp,proto3" json:"target_rtp,omitempty"`
+	TestSpins   []int64                `protobuf:"varint,5,rep,packed,name=test_spins,json=testSpins,proto3" json:"test_spins,omitempty"`
+	TestWeights []float64              `protobuf:"fixed64,6,rep,packed,name=test_weights,json=testWeights,proto3" json:"test_weights,omitempty"`
+	// Unset for a random seed; the seed used is echoed in the response
+	Seed         *int64  `protobuf:"varint,7,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	Workers      int64   `protobuf:"varint,8,opt,name=workers,proto3" json:"workers,omitempty"`
+	RtpTolerance float64 `protobuf:"fixed64,9,opt,name=rtp_tolerance,json=rtpTolerance,proto3" json:"rtp_tolerance,omitempty"`
+	Confidence   float64 `protobuf:"fixed64,10,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	// Optionally replaces the table's weights for this run only (one per
+	// outcome, in table order)
+	Weights       []uint64 `protobuf:"varint,11,rep,packed,name=weights,proto3" json:"weights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimulateRequest) Reset() {
+	*x = SimulateRequest{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateRequest) ProtoMessage() {}
+
+func (x *SimulateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateRequest.ProtoReflect.Descriptor instead.
+func (*SimulateRequest) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SimulateRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *SimulateRequest) GetSpins() int64 {
+	if x != nil {
+		return x.Spins
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetTrials() int64 {
+	if x != nil {
+		return x.Trials
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetTargetRtp() float64 {
+	if x != nil {
+		return x.TargetRtp
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetTestSpins() []int64 {
+	if x != nil {
+		return x.TestSpins
+	}
+	return nil
+}
+
+func (x *SimulateRequest) GetTestWeights() []float64 {
+	if x != nil {
+		return x.TestWeights
+	}
+	return nil
+}
+
+func (x *SimulateRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetWorkers() int64 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetRtpTolerance() float64 {
+	if x != nil {
+		return x.RtpTolerance
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *SimulateRequest) GetWeights() []uint64 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+type RTPAtSpin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpinCount     int64                  `protobuf:"varint,1,opt,name=spin_count,json=spinCount,proto3" json:"spin_count,omitempty"`
+	SuccessRate   float64                `protobuf:"fixed64,2,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	MeanRtp       float64                `protobuf:"fixed64,3,opt,name=mean_rtp,json=meanRtp,proto3" json:"mean_rtp,omitempty"`
+	StdError      float64                `protobuf:"fixed64,4,opt,name=std_error,json=stdError,proto3" json:"std_error,omitempty"`
+	CiLow         float64                `protobuf:"fixed64,5,opt,name=ci_low,json=ciLow,proto3" json:"ci_low,omitempty"`
+	CiHigh        float64                `protobuf:"fixed64,6,opt,name=ci_high,json=ciHigh,proto3" json:"ci_high,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RTPAtSpin) Reset() {
+	*x = RTPAtSpin{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RTPAtSpin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RTPAtSpin) ProtoMessage() {}
+
+func (x *RTPAtSpin) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RTPAtSpin.ProtoReflect.Descriptor instead.
+func (*RTPAtSpin) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RTPAtSpin) GetSpinCount() int64 {
+	if x != nil {
+		return x.SpinCount
+	}
+	return 0
+}
+
+func (x *RTPAtSpin) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *RTPAtSpin) GetMeanRtp() float64 {
+	if x != nil {
+		return x.MeanRtp
+	}
+	return 0
+}
+
+func (x *RTPAtSpin) GetStdError() float64 {
+	if x != nil {
+		return x.StdError
+	}
+	return 0
+}
+
+func (x *RTPAtSpin) GetCiLow() float64 {
+	if x != nil {
+		return x.CiLow
+	}
+	return 0
+}
+
+func (x *RTPAtSpin) GetCiHigh() float64 {
+	if x != nil {
+		return x.CiHigh
+	}
+	return 0
+}
+
+type SimulateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Seed          int64                  `protobuf:"varint,2,opt,name=seed,proto3" json:"seed,omitempty"`
+	CustomWeights bool                   `protobuf:"varint,3,opt,name=custom_weights,json=customWeights,proto3" json:"custom_weights,omitempty"`
+	TotalSpins    int64                  `protobuf:"varint,4,opt,name=total_spins,json=totalSpins,proto3" json:"total_spins,omitempty"`
+	TotalWagered  float64                `protobuf:"fixed64,5,opt,name=total_wagered,json=totalWagered,proto3" json:"total_wagered,omitempty"`
+	TotalWon      float64                `protobuf:"fixed64,6,opt,name=total_won,json=totalWon,proto3" json:"total_won,omitempty"`
+	ActualRtp     float64                `protobuf:"fixed64,7,opt,name=actual_rtp,json=actualRtp,proto3" json:"actual_rtp,omitempty"`
+	HitCount      int64                  `protobuf:"varint,8,opt,name=hit_count,json=hitCount,proto3" json:"hit_count,omitempty"`
+	HitRate       float64                `protobuf:"fixed64,9,opt,name=hit_rate,json=hitRate,proto3" json:"hit_rate,omitempty"`
+	BigWins       int64                  `protobuf:"varint,10,opt,name=big_wins,json=bigWins,proto3" json:"big_wins,omitempty"`
+	MegaWins      int64                  `protobuf:"varint,11,opt,name=mega_wins,json=megaWins,proto3" json:"mega_wins,omitempty"`
+	MaxWin        float64                `protobuf:"fixed64,12,opt,name=max_win,json=maxWin,proto3" json:"max_win,omitempty"`
+	FinalScore    float64                `protobuf:"fixed64,13,opt,name=final_score,json=finalScore,proto3" json:"final_score,omitempty"`
+	RtpStdError   float64                `protobuf:"fixed64,14,opt,name=rtp_std_error,json=rtpStdError,proto3" json:"rtp_std_error,omitempty"`
+	RtpCiLow      float64                `protobuf:"fixed64,15,opt,name=rtp_ci_low,json=rtpCiLow,proto3" json:"rtp_ci_low,omitempty"`
+	RtpCiHigh     float64                `protobuf:"fixed64,16,opt,name=rtp_ci_high,json=rtpCiHigh,proto3" json:"rtp_ci_high,omitempty"`
+	RtpAtSpins    []*RTPAtSpin           `protobuf:"bytes,17,rep,name=rtp_at_spins,json=rtpAtSpins,proto3" json:"rtp_at_spins,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,18,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimulateResponse) Reset() {
+	*x = SimulateResponse{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateResponse) ProtoMessage() {}
+
+func (x *SimulateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateResponse.ProtoReflect.Descriptor instead.
+func (*SimulateResponse) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SimulateResponse) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *SimulateResponse) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetCustomWeights() bool {
+	if x != nil {
+		return x.CustomWeights
+	}
+	return false
+}
+
+func (x *SimulateResponse) GetTotalSpins() int64 {
+	if x != nil {
+		return x.TotalSpins
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetTotalWagered() float64 {
+	if x != nil {
+		return x.TotalWagered
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetTotalWon() float64 {
+	if x != nil {
+		return x.TotalWon
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetActualRtp() float64 {
+	if x != nil {
+		return x.ActualRtp
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetHitCount() int64 {
+	if x != nil {
+		return x.HitCount
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetHitRate() float64 {
+	if x != nil {
+		return x.HitRate
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetBigWins() int64 {
+	if x != nil {
+		return x.BigWins
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetMegaWins() int64 {
+	if x != nil {
+		return x.MegaWins
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetMaxWin() float64 {
+	if x != nil {
+		return x.MaxWin
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetFinalScore() float64 {
+	if x != nil {
+		return x.FinalScore
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetRtpStdError() float64 {
+	if x != nil {
+		return x.RtpStdError
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetRtpCiLow() float64 {
+	if x != nil {
+		return x.RtpCiLow
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetRtpCiHigh() float64 {
+	if x != nil {
+		return x.RtpCiHigh
+	}
+	return 0
+}
+
+func (x *SimulateResponse) GetRtpAtSpins() []*RTPAtSpin {
+	if x != nil {
+		return x.RtpAtSpins
+	}
+	return nil
+}
+
+func (x *SimulateResponse) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+type ApplyWeightsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Weights       []uint64               `protobuf:"varint,2,rep,packed,name=weights,proto3" json:"weights,omitempty"`
+	CreateBackup  bool                   `protobuf:"varint,3,opt,name=create_backup,json=createBackup,proto3" json:"create_backup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyWeightsRequest) Reset() {
+	*x = ApplyWeightsRequest{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyWeightsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyWeightsRequest) ProtoMessage() {}
+
+func (x *ApplyWeightsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyWeightsRequest.ProtoReflect.Descriptor instead.
+func (*ApplyWeightsRequest) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ApplyWeightsRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ApplyWeightsRequest) GetWeights() []uint64 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+func (x *ApplyWeightsRequest) GetCreateBackup() bool {
+	if x != nil {
+		return x.CreateBackup
+	}
+	return false
+}
+
+type ApplyWeightsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Saved         bool                   `protobuf:"varint,1,opt,name=saved,proto3" json:"saved,omitempty"`
+	BackupPath    string                 `protobuf:"bytes,2,opt,name=backup_path,json=backupPath,proto3" json:"backup_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyWeightsResponse) Reset() {
+	*x = ApplyWeightsResponse{}
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyWeightsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyWeightsResponse) ProtoMessage() {}
+
+func (x *ApplyWeightsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lutexplorer_v1_lutexplorer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyWeightsResponse.ProtoReflect.Descriptor instead.
+func (*ApplyWeightsResponse) Descriptor() ([]byte, []int) {
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ApplyWeightsResponse) GetSaved() bool {
+	if x != nil {
+		return x.Saved
+	}
+	return false
+}
+
+func (x *ApplyWeightsResponse) GetBackupPath() string {
+	if x != nil {
+		return x.BackupPath
+	}
+	return ""
+}
+
+var File_lutexplorer_v1_lutexplorer_proto protoreflect.FileDescriptor
+
+var file_lutexplorer_v1_lutexplorer_proto_rawDesc = []byte{
+	0x0a, 0x20, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2f, 0x76, 0x31,
+	0x2f, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x9d, 0x01, 0x0a, 0x0b, 0x4d, 0x6f, 0x64, 0x65, 0x53,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x74,
+	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x72, 0x74, 0x70, 0x12, 0x19, 0x0a, 0x08,
+	0x68, 0x69, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07,
+	0x68, 0x69, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x70,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6d, 0x61, 0x78,
+	0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x22, 0x46, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f,
+	0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x75, 0x74,
+	0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x64, 0x65,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x29,
+	0x0a, 0x13, 0x47, 0x65, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x0c, 0x50, 0x61,
+	0x79, 0x6f, 0x75, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0a, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x7d, 0x0a, 0x0a, 0x50, 0x61, 0x79, 0x6f,
+	0x75, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x69, 0x6d, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6d, 0x49, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x70,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6f, 0x64, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6f, 0x64, 0x64,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb2, 0x05, 0x0a, 0x09, 0x4d, 0x6f, 0x64, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x12, 0x25, 0x0a,
+	0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f, 0x75, 0x74, 0x63,
+	0x6f, 0x6d, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x74, 0x70, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x72, 0x74, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x69, 0x74,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x68, 0x69, 0x74,
+	0x52, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x79, 0x6f,
+	0x75, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x79,
+	0x6f, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x50, 0x61, 0x79, 0x6f,
+	0x75, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x61, 0x6e, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x65, 0x61, 0x6e, 0x50, 0x61, 0x79,
+	0x6f, 0x75, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x5f, 0x70, 0x61,
+	0x79, 0x6f, 0x75, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x6e, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69,
+	0x61, 0x6e, 0x63, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x74, 0x64, 0x5f, 0x64, 0x65, 0x76, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x73, 0x74, 0x64, 0x44, 0x65, 0x76, 0x12, 0x1e, 0x0a,
+	0x0a, 0x76, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x76, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2a, 0x0a,
+	0x11, 0x6d, 0x65, 0x61, 0x6e, 0x5f, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x5f, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x65, 0x61, 0x6e, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x6e, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12, 0x28, 0x0a, 0x10, 0x7a, 0x65, 0x72,
+	0x6f, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0e, 0x7a, 0x65, 0x72, 0x6f, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x65, 0x76, 0x65, 0x6e,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x65, 0x76, 0x65, 0x6e, 0x52, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x6f,
+	0x73, 0x74, 0x5f, 0x61, 0x64, 0x6a, 0x5f, 0x76, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x63, 0x6f, 0x73, 0x74, 0x41, 0x64, 0x6a,
+	0x56, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x43, 0x0a, 0x0e, 0x70, 0x61,
+	0x79, 0x6f, 0x75, 0x74, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x12, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x52, 0x0d, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12,
+	0x3b, 0x0a, 0x0b, 0x74, 0x6f, 0x70, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x13,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x0a, 0x74, 0x6f, 0x70, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x73, 0x22, 0xcf, 0x02, 0x0a,
+	0x0f, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x70, 0x69, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x72,
+	0x69, 0x61, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x74, 0x72, 0x69, 0x61,
+	0x6c, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x74, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x74,
+	0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x73, 0x70, 0x69, 0x6e, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x03, 0x52, 0x09, 0x74, 0x65, 0x73, 0x74, 0x53, 0x70, 0x69, 0x6e, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x65, 0x73, 0x74, 0x57, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x73, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x00, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07,
+	0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x77,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x74, 0x70, 0x5f, 0x74, 0x6f,
+	0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x72,
+	0x74, 0x70, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x65, 0x65, 0x64, 0x22, 0xb5,
+	0x01, 0x0a, 0x09, 0x52, 0x54, 0x50, 0x41, 0x74, 0x53, 0x70, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x70, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x73, 0x70, 0x69, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x61, 0x74, 0x65, 0x12, 0x19,
+	0x0a, 0x08, 0x6d, 0x65, 0x61, 0x6e, 0x5f, 0x72, 0x74, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x07, 0x6d, 0x65, 0x61, 0x6e, 0x52, 0x74, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x64,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x73, 0x74,
+	0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x15, 0x0a, 0x06, 0x63, 0x69, 0x5f, 0x6c, 0x6f, 0x77,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x63, 0x69, 0x4c, 0x6f, 0x77, 0x12, 0x17, 0x0a,
+	0x07, 0x63, 0x69, 0x5f, 0x68, 0x69, 0x67, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x63, 0x69, 0x48, 0x69, 0x67, 0x68, 0x22, 0xcd, 0x04, 0x0a, 0x10, 0x53, 0x69, 0x6d, 0x75, 0x6c,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73,
+	0x65, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x63, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x73, 0x70, 0x69, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x70, 0x69, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x77, 0x61, 0x67, 0x65, 0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x57, 0x61, 0x67, 0x65, 0x72, 0x65, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x77, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x57, 0x6f, 0x6e, 0x12, 0x1d, 0x0a,
+	0x0a, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x72, 0x74, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x52, 0x74, 0x70, 0x12, 0x1b, 0x0a, 0x09,
+	0x68, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x68, 0x69, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x69, 0x74,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x68, 0x69, 0x74,
+	0x52, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x69, 0x67, 0x5f, 0x77, 0x69, 0x6e, 0x73,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x69, 0x67, 0x57, 0x69, 0x6e, 0x73, 0x12,
+	0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x67, 0x61, 0x5f, 0x77, 0x69, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x6d, 0x65, 0x67, 0x61, 0x57, 0x69, 0x6e, 0x73, 0x12, 0x17, 0x0a, 0x07,
+	0x6d, 0x61, 0x78, 0x5f, 0x77, 0x69, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6d,
+	0x61, 0x78, 0x57, 0x69, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x61,
+	0x6c, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x74, 0x70, 0x5f, 0x73, 0x74,
+	0x64, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x72,
+	0x74, 0x70, 0x53, 0x74, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x0a, 0x72, 0x74,
+	0x70, 0x5f, 0x63, 0x69, 0x5f, 0x6c, 0x6f, 0x77, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x72, 0x74, 0x70, 0x43, 0x69, 0x4c, 0x6f, 0x77, 0x12, 0x1e, 0x0a, 0x0b, 0x72, 0x74, 0x70, 0x5f,
+	0x63, 0x69, 0x5f, 0x68, 0x69, 0x67, 0x68, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x72,
+	0x74, 0x70, 0x43, 0x69, 0x48, 0x69, 0x67, 0x68, 0x12, 0x3b, 0x0a, 0x0c, 0x72, 0x74, 0x70, 0x5f,
+	0x61, 0x74, 0x5f, 0x73, 0x70, 0x69, 0x6e, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x54, 0x50, 0x41, 0x74, 0x53, 0x70, 0x69, 0x6e, 0x52, 0x0a, 0x72, 0x74, 0x70, 0x41, 0x74,
+	0x53, 0x70, 0x69, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x22, 0x68, 0x0a, 0x13, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x57,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x04, 0x52, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x22, 0x4d, 0x0a, 0x14, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x61, 0x76, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x61, 0x76, 0x65, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x50, 0x61, 0x74, 0x68, 0x32,
+	0xd9, 0x02, 0x0a, 0x0b, 0x4c, 0x55, 0x54, 0x45, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x12,
+	0x50, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x6c,
+	0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
+	0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4e, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x23, 0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c,
+	0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x4d, 0x0a, 0x08, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e,
+	0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
+	0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x59, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73,
+	0x12, 0x23, 0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6c, 0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f,
+	0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x57, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2c, 0x5a, 0x2a, 0x6c,
+	0x75, 0x74, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69, 0x2f, 0x6c, 0x75, 0x74, 0x65,
+	0x78, 0x70, 0x6c, 0x6f, 0x72, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_lutexplorer_v1_lutexplorer_proto_rawDescOnce sync.Once
+	file_lutexplorer_v1_lutexplorer_proto_rawDescData = file_lutexplorer_v1_lutexplorer_proto_rawDesc
+)
+
+func file_lutexplorer_v1_lutexplorer_proto_rawDescGZIP() []byte {
+	file_lutexplorer_v1_lutexplorer_proto_rawDescOnce.Do(func() {
+		file_lutexplorer_v1_lutexplorer_proto_rawDescData = protoimpl.X.CompressGZIP(file_lutexplorer_v1_lutexplorer_proto_rawDescData)
+	})
+	return file_lutexplorer_v1_lutexplorer_proto_rawDescData
+}
+
+var file_lutexplorer_v1_lutexplorer_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_lutexplorer_v1_lutexplorer_proto_goTypes = []any{
+	(*ListModesRequest)(nil),     // 0: lutexplorer.v1.ListModesRequest
+	(*ModeSummary)(nil),          // 1: lutexplorer.v1.ModeSummary
+	(*ListModesResponse)(nil),    // 2: lutexplorer.v1.ListModesResponse
+	(*GetModeStatsRequest)(nil),  // 3: lutexplorer.v1.GetModeStatsRequest
+	(*PayoutBucket)(nil),         // 4: lutexplorer.v1.PayoutBucket
+	(*PayoutInfo)(nil),           // 5: lutexplorer.v1.PayoutInfo
+	(*ModeStats)(nil),            // 6: lutexplorer.v1.ModeStats
+	(*SimulateRequest)(nil),      // 7: lutexplorer.v1.SimulateRequest
+	(*RTPAtSpin)(nil),            // 8: lutexplorer.v1.RTPAtSpin
+	(*SimulateResponse)(nil),     // 9: lutexplorer.v1.SimulateResponse
+	(*ApplyWeightsRequest)(nil),  // 10: lutexplorer.v1.ApplyWeightsRequest
+	(*ApplyWeightsResponse)(nil), // 11: lutexplorer.v1.ApplyWeightsResponse
+}
+var file_lutexplorer_v1_lutexplorer_proto_depIdxs = []int32{
+	1,  // 0: lutexplorer.v1.ListModesResponse.modes:type_name -> lutexplorer.v1.ModeSummary
+	4,  // 1: lutexplorer.v1.ModeStats.payout_buckets:type_name -> lutexplorer.v1.PayoutBucket
+	5,  // 2: lutexplorer.v1.ModeStats.top_payouts:type_name -> lutexplorer.v1.PayoutInfo
+	8,  // 3: lutexplorer.v1.SimulateResponse.rtp_at_spins:type_name -> lutexplorer.v1.RTPAtSpin
+	0,  // 4: lutexplorer.v1.LUTExplorer.ListModes:input_type -> lutexplorer.v1.ListModesRequest
+	3,  // 5: lutexplorer.v1.LUTExplorer.GetModeStats:input_type -> lutexplorer.v1.GetModeStatsRequest
+	7,  // 6: lutexplorer.v1.LUTExplorer.Simulate:input_type -> lutexplorer.v1.SimulateRequest
+	10, // 7: lutexplorer.v1.LUTExplorer.ApplyWeights:input_type -> lutexplorer.v1.ApplyWeightsRequest
+	2,  // 8: lutexplorer.v1.LUTExplorer.ListModes:output_type -> lutexplorer.v1.ListModesResponse
+	6,  // 9: lutexplorer.v1.LUTExplorer.GetModeStats:output_type -> lutexplorer.v1.ModeStats
+	9,  // 10: lutexplorer.v1.LUTExplorer.Simulate:output_type -> lutexplorer.v1.SimulateResponse
+	11, // 11: lutexplorer.v1.LUTExplorer.ApplyWeights:output_type -> lutexplorer.v1.ApplyWeightsResponse
+	8,  // [8:12] is the sub-list for method output_type
+	4,  // [4:8] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_lutexplorer_v1_lutexplorer_proto_init() }
+func file_lutexplorer_v1_lutexplorer_proto_init() {
+	if File_lutexplorer_v1_lutexplorer_proto != nil {
+		return
+	}
+	file_lutexplorer_v1_lutexplorer_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_lutexplorer_v1_lutexplorer_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lutexplorer_v1_lutexplorer_proto_goTypes,
+		DependencyIndexes: file_lutexplorer_v1_lutexplorer_proto_depIdxs,
+		MessageInfos:      file_lutexplorer_v1_lutexplorer_proto_msgTypes,
+	}.Build()
+	File_lutexplorer_v1_lutexplorer_proto = out.File
+	file_lutexplorer_v1_lutexplorer_proto_rawDesc = nil
+	file_lutexplorer_v1_lutexplorer_proto_goTypes = nil
+	file_lutexplorer_v1_lutexplorer_proto_depIdxs = nil
+}
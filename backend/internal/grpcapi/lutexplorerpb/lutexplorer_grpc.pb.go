@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: lutexplorer/v1/lutexplorer.proto
+
+// gRPC mirror of the LUT analysis REST endpoints, for math tooling that
+// would rather use generated clients than hand-rolled HTTP calls.
+
+package lutexplorerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LUTExplorer_ListModes_FullMethodName    = "/lutexplorer.v1.LUTExplorer/ListModes"
+	LUTExplorer_GetModeStats_FullMethodName = "/lutexplorer.v1.LUTExplorer/GetModeStats"
+	LUTExplorer_Simulate_FullMethodName     = "/lutexplorer.v1.LUTExplorer/Simulate"
+	LUTExplorer_ApplyWeights_FullMethodName = "/lutexplorer.v1.LUTExplorer/ApplyWeights"
+)
+
+// LUTExplorerClient is the client API for LUTExplorer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LUTExplorerClient interface {
+	// ListModes mirrors GET /api/modes.
+	ListModes(ctx context.Context, in *ListModesRequest, opts ...grpc.CallOption) (*ListModesResponse, error)
+	// GetModeStats mirrors GET /api/mode/{mode}/stats.
+	GetModeStats(ctx context.Context, in *GetModeStatsRequest, opts ...grpc.CallOption) (*ModeStats, error)
+	// Simulate mirrors POST /api/mode/{mode}/simulate.
+	Simulate(ctx context.Context, in *SimulateRequest, opts ...grpc.CallOption) (*SimulateResponse, error)
+	// ApplyWeights mirrors POST /api/optimizer/{mode}/apply.
+	ApplyWeights(ctx context.Context, in *ApplyWeightsRequest, opts ...grpc.CallOption) (*ApplyWeightsResponse, error)
+}
+
+type lUTExplorerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLUTExplorerClient(cc grpc.ClientConnInterface) LUTExplorerClient {
+	return &lUTExplorerClient{cc}
+}
+
+func (c *lUTExplorerClient) ListModes(ctx context.Context, in *ListModesRequest, opts ...grpc.CallOption) (*ListModesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModesResponse)
+	err := c.cc.Invoke(ctx, LUTExplorer_ListModes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lUTExplorerClient) GetModeStats(ctx context.Context, in *GetModeStatsRequest, opts ...grpc.CallOption) (*ModeStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModeStats)
+	err := c.cc.Invoke(ctx, LUTExplorer_GetModeStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lUTExplorerClient) Simulate(ctx context.Context, in *SimulateRequest, opts ...grpc.CallOption) (*SimulateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimulateResponse)
+	err := c.cc.Invoke(ctx, LUTExplorer_Simulate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lUTExplorerClient) ApplyWeights(ctx context.Context, in *ApplyWeightsRequest, opts ...grpc.CallOption) (*ApplyWeightsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyWeightsResponse)
+	err := c.cc.Invoke(ctx, LUTExplorer_ApplyWeights_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LUTExplorerServer is the server API for LUTExplorer service.
+// All implementations must embed UnimplementedLUTExplorerServer
+// for forward compatibility.
+type LUTExplorerServer interface {
+	// ListModes mirrors GET /api/modes.
+	ListModes(context.Context, *ListModesRequest) (*ListModesResponse, error)
+	// GetModeStats mirrors GET /api/mode/{mode}/stats.
+	GetModeStats(context.Context, *GetModeStatsRequest) (*ModeStats, error)
+	// Simulate mirrors POST /api/mode/{mode}/simulate.
+	Simulate(context.Context, *SimulateRequest) (*SimulateResponse, error)
+	// ApplyWeights mirrors POST /api/optimizer/{mode}/apply.
+	ApplyWeights(context.Context, *ApplyWeightsRequest) (*ApplyWeightsResponse, error)
+	mustEmbedUnimplementedLUTExplorerServer()
+}
+
+// UnimplementedLUTExplorerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLUTExplorerServer struct{}
+
+func (UnimplementedLUTExplorerServer) ListModes(context.Context, *ListModesRequest) (*ListModesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModes not implemented")
+}
+func (UnimplementedLUTExplorerServer) GetModeStats(context.Context, *GetModeStatsRequest) (*ModeStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModeStats not implemented")
+}
+func (UnimplementedLUTExplorerServer) Simulate(context.Context, *SimulateRequest) (*SimulateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Simulate not implemented")
+}
+func (UnimplementedLUTExplorerServer) ApplyWeights(context.Context, *ApplyWeightsRequest) (*ApplyWeightsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyWeights not implemented")
+}
+func (UnimplementedLUTExplorerServer) mustEmbedUnimplementedLUTExplorerServer() {}
+func (UnimplementedLUTExplorerServer) testEmbeddedByValue()                     {}
+
+// UnsafeLUTExplorerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LUTExplorerServer will
+// result in compilation errors.
+type UnsafeLUTExplorerServer interface {
+	mustEmbedUnimplementedLUTExplorerServer()
+}
+
+func RegisterLUTExplorerServer(s grpc.ServiceRegistrar, srv LUTExplorerServer) {
+	// If the following call pancis, it indicates UnimplementedLUTExplorerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LUTExplorer_ServiceDesc, srv)
+}
+
+func _LUTExplorer_ListModes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LUTExplorerServer).ListModes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LUTExplorer_ListModes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LUTExplorerServer).ListModes(ctx, req.(*ListModesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LUTExplorer_GetModeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModeStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LUTExplorerServer).GetModeStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LUTExplorer_GetModeStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LUTExplorerServer).GetModeStats(ctx, req.(*GetModeStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LUTExplorer_Simulate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LUTExplorerServer).Simulate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LUTExplorer_Simulate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LUTExplorerServer).Simulate(ctx, req.(*SimulateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LUTExplorer_ApplyWeights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyWeightsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LUTExplorerServer).ApplyWeights(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LUTExplorer_ApplyWeights_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LUTExplorerServer).ApplyWeights(ctx, req.(*ApplyWeightsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LUTExplorer_ServiceDesc is the grpc.ServiceDesc for LUTExplorer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LUTExplorer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lutexplorer.v1.LUTExplorer",
+	HandlerType: (*LUTExplorerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListModes",
+			Handler:    _LUTExplorer_ListModes_Handler,
+		},
+		{
+			MethodName: "GetModeStats",
+			Handler:    _LUTExplorer_GetModeStats_Handler,
+		},
+		{
+			MethodName: "Simulate",
+			Handler:    _LUTExplorer_Simulate_Handler,
+		},
+		{
+			MethodName: "ApplyWeights",
+			Handler:    _LUTExplorer_ApplyWeights_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lutexplorer/v1/lutexplorer.proto",
+}
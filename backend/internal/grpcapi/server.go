@@ -0,0 +1,237 @@
+// Package grpcapi serves the LUT analysis endpoints over gRPC, mirroring
+// their REST counterparts in internal/api for pipelines that prefer
+// generated clients.
+package grpcapi
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=lutexplorer/internal/grpcapi --go-grpc_out=. --go-grpc_opt=module=lutexplorer/internal/grpcapi lutexplorer/v1/lutexplorer.proto
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"lutexplorer/internal/common"
+	"lutexplorer/internal/grpcapi/lutexplorerpb"
+	"lutexplorer/internal/lut"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the LUTExplorer gRPC service on top of a loader.
+type Server struct {
+	lutexplorerpb.UnimplementedLUTExplorerServer
+
+	loader *lut.Loader
+	grpc   *grpc.Server
+}
+
+// NewServer creates a gRPC server for the loader's library.
+func NewServer(loader *lut.Loader) *Server {
+	s := &Server{
+		loader: loader,
+		grpc:   grpc.NewServer(),
+	}
+	lutexplorerpb.RegisterLUTExplorerServer(s.grpc, s)
+	return s
+}
+
+// Start listens on addr and serves until Stop is called.
+func (s *Server) Start(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("gRPC server listening on %s", lis.Addr())
+	return s.grpc.Serve(lis)
+}
+
+// Stop finishes in-flight calls and closes the listener.
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// ListModes mirrors GET /api/modes.
+func (s *Server) ListModes(ctx context.Context, req *lutexplorerpb.ListModesRequest) (*lutexplorerpb.ListModesResponse, error) {
+	summaries := s.loader.GetModeSummaries()
+	resp := &lutexplorerpb.ListModesResponse{Modes: make([]*lutexplorerpb.ModeSummary, len(summaries))}
+	for i, m := range summaries {
+		resp.Modes[i] = &lutexplorerpb.ModeSummary{
+			Mode:      m.Mode,
+			Cost:      m.Cost,
+			Outcomes:  int64(m.Outcomes),
+			Rtp:       m.RTP,
+			HitRate:   m.HitRate,
+			MaxPayout: m.MaxPayout,
+		}
+	}
+	return resp, nil
+}
+
+// GetModeStats mirrors GET /api/mode/{mode}/stats.
+func (s *Server) GetModeStats(ctx context.Context, req *lutexplorerpb.GetModeStatsRequest) (*lutexplorerpb.ModeStats, error) {
+	table, err := s.loader.GetMode(req.GetMode())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	stats := s.loader.Statistics(table)
+
+	resp := &lutexplorerpb.ModeStats{
+		Mode:              stats.Mode,
+		Cost:              stats.Cost,
+		TotalOutcomes:     int64(stats.TotalOutcomes),
+		TotalWeight:       stats.TotalWeight,
+		Rtp:               stats.RTP,
+		HitRate:           stats.HitRate,
+		MaxPayout:         stats.MaxPayout,
+		MinPayout:         stats.MinPayout,
+		MeanPayout:        stats.MeanPayout,
+		MedianPayout:      stats.MedianPayout,
+		Variance:          stats.Variance,
+		StdDev:            stats.StdDev,
+		Volatility:        stats.Volatility,
+		MeanMedianRatio:   stats.MeanMedian,
+		ZeroPayoutRate:    stats.ZeroPayoutRate,
+		BreakevenRate:     stats.BreakevenRate,
+		CostAdjVolatility: stats.CostAdjVolatility,
+	}
+	for _, b := range stats.PayoutBuckets {
+		resp.PayoutBuckets = append(resp.PayoutBuckets, &lutexplorerpb.PayoutBucket{
+			RangeStart:  b.RangeStart,
+			RangeEnd:    b.RangeEnd,
+			Count:       int64(b.Count),
+			Weight:      b.Weight,
+			Probability: b.Probability,
+		})
+	}
+	for _, p := range stats.TopPayouts {
+		resp.TopPayouts = append(resp.TopPayouts, &lutexplorerpb.PayoutInfo{
+			SimId:  int64(p.SimID),
+			Payout: p.Payout,
+			Weight: p.Weight,
+			Odds:   p.Odds,
+			Count:  int64(p.Count),
+		})
+	}
+	return resp, nil
+}
+
+// Simulate mirrors POST /api/mode/{mode}/simulate. Results are not added
+// to the REST simulation history.
+func (s *Server) Simulate(ctx context.Context, req *lutexplorerpb.SimulateRequest) (*lutexplorerpb.SimulateResponse, error) {
+	table, err := s.loader.GetMode(req.GetMode())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if len(req.GetWeights()) > 0 {
+		if table, err = lut.WithWeights(table, req.GetWeights()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// Same defaults and limits as the REST endpoint
+	spins := int(req.GetSpins())
+	if spins <= 0 {
+		spins = common.DefaultSpins
+	}
+	if spins > common.MaxSpins {
+		spins = common.MaxSpins
+	}
+	trials := int(req.GetTrials())
+	if trials <= 0 {
+		trials = common.DefaultTrials
+	}
+	if trials > common.MaxTrials {
+		trials = common.MaxTrials
+	}
+	targetRTP := req.GetTargetRtp()
+	if targetRTP <= 0 {
+		targetRTP = 0.97
+	}
+	testSpins := []int{100, 500, 1000}
+	if len(req.GetTestSpins()) > 0 {
+		testSpins = make([]int, len(req.GetTestSpins()))
+		for i, n := range req.GetTestSpins() {
+			testSpins[i] = int(n)
+		}
+	}
+
+	bet := table.Cost
+	if bet <= 0 {
+		bet = 1.0
+	}
+
+	simulator := s.loader.Simulator()
+	seed := simulator.NewSeed()
+	if req.Seed != nil {
+		seed = req.GetSeed()
+	}
+
+	result := simulator.RunSimulation(table, lut.SimulationConfig{
+		Spins:        spins,
+		Trials:       trials,
+		Bet:          bet,
+		TargetRTP:    targetRTP,
+		TestSpins:    testSpins,
+		TestWeights:  req.GetTestWeights(),
+		Seed:         seed,
+		Workers:      int(req.GetWorkers()),
+		RTPTolerance: req.GetRtpTolerance(),
+		Confidence:   req.GetConfidence(),
+	})
+
+	resp := &lutexplorerpb.SimulateResponse{
+		Mode:          result.Mode,
+		Seed:          result.Seed,
+		CustomWeights: len(req.GetWeights()) > 0,
+		TotalSpins:    int64(result.TotalSpins),
+		TotalWagered:  result.TotalWagered,
+		TotalWon:      result.TotalWon,
+		ActualRtp:     result.ActualRTP,
+		HitCount:      int64(result.HitCount),
+		HitRate:       result.HitRate,
+		BigWins:       int64(result.BigWins),
+		MegaWins:      int64(result.MegaWins),
+		MaxWin:        result.MaxWin,
+		FinalScore:    result.FinalScore,
+		RtpStdError:   result.RTPStdError,
+		RtpCiLow:      result.RTPCILow,
+		RtpCiHigh:     result.RTPCIHigh,
+		DurationMs:    result.DurationMs,
+	}
+	for _, r := range result.RTPatSpins {
+		resp.RtpAtSpins = append(resp.RtpAtSpins, &lutexplorerpb.RTPAtSpin{
+			SpinCount:   int64(r.SpinCount),
+			SuccessRate: r.SuccessRate,
+			MeanRtp:     r.MeanRTP,
+			StdError:    r.StdError,
+			CiLow:       r.CILow,
+			CiHigh:      r.CIHigh,
+		})
+	}
+	return resp, nil
+}
+
+// ApplyWeights mirrors POST /api/optimizer/{mode}/apply.
+func (s *Server) ApplyWeights(ctx context.Context, req *lutexplorerpb.ApplyWeightsRequest) (*lutexplorerpb.ApplyWeightsResponse, error) {
+	if req.GetMode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "mode required")
+	}
+	if len(req.GetWeights()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "weights required")
+	}
+
+	var backupPath string
+	var err error
+	if req.GetCreateBackup() {
+		backupPath, err = s.loader.SaveWeightsWithBackup(req.GetMode(), req.GetWeights())
+	} else {
+		err = s.loader.SaveWeights(req.GetMode(), req.GetWeights())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &lutexplorerpb.ApplyWeightsResponse{Saved: true, BackupPath: backupPath}, nil
+}
@@ -0,0 +1,131 @@
+// Package i18n serves UI string catalogs from a folder of <lang>.json files,
+// so a language can be added by dropping in a file instead of rebuilding the frontend.
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the catalog other languages fall back to for missing keys.
+const DefaultLanguage = "en"
+
+// ErrUnknownLanguage is returned for a language with no catalog file.
+var ErrUnknownLanguage = errors.New("unknown language")
+
+var codeRe = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+// Language describes one available catalog.
+type Language struct {
+	Code string `json:"code"`
+	Name string `json:"name"` // the language's name in itself, from its "languages" section
+}
+
+// Catalog reads catalogs from a directory. Files are read on every call, so
+// new or edited files take effect without a restart.
+type Catalog struct {
+	dir string
+}
+
+// NewCatalog creates a catalog backed by dir.
+func NewCatalog(dir string) *Catalog {
+	return &Catalog{dir: dir}
+}
+
+// Dir returns the directory catalogs are read from.
+func (c *Catalog) Dir() string {
+	return c.dir
+}
+
+// Languages lists the available catalogs, default language first.
+func (c *Catalog) Languages() ([]Language, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]Language, 0, len(files))
+	for _, file := range files {
+		code := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !codeRe.MatchString(code) {
+			continue
+		}
+		messages, err := c.load(code)
+		if err != nil {
+			continue
+		}
+		name := code
+		if names, ok := messages["languages"].(map[string]interface{}); ok {
+			if n, ok := names[code].(string); ok && n != "" {
+				name = n
+			}
+		}
+		languages = append(languages, Language{Code: code, Name: name})
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		if (languages[i].Code == DefaultLanguage) != (languages[j].Code == DefaultLanguage) {
+			return languages[i].Code == DefaultLanguage
+		}
+		return languages[i].Code < languages[j].Code
+	})
+	return languages, nil
+}
+
+// Messages returns the strings for lang with missing keys filled in from the default language.
+func (c *Catalog) Messages(lang string) (map[string]interface{}, error) {
+	if !codeRe.MatchString(lang) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLanguage, lang)
+	}
+	messages, err := c.load(lang)
+	if err != nil {
+		return nil, err
+	}
+	if lang == DefaultLanguage {
+		return messages, nil
+	}
+	base, err := c.load(DefaultLanguage)
+	if err != nil {
+		return messages, nil // no fallback catalog, serve as-is
+	}
+	return merge(base, messages), nil
+}
+
+func (c *Catalog) load(code string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, code+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownLanguage, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var messages map[string]interface{}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s.json: %w", code, err)
+	}
+	return messages, nil
+}
+
+// merge returns base with override's keys layered on top, recursively.
+func merge(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if baseSub, ok := merged[k].(map[string]interface{}); ok {
+				merged[k] = merge(baseSub, sub)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,218 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"lutexplorer/internal/ws"
+	"stakergs"
+)
+
+// DefaultBatchProgressEvery is how many spins a streamed batch play runs
+// between progress broadcasts.
+const DefaultBatchProgressEvery = 1000
+
+// batchJob is a streamed batch play running in the background.
+type batchJob struct {
+	sessionID string
+	cancel    chan struct{}
+	once      sync.Once
+}
+
+func (j *batchJob) stop() {
+	j.once.Do(func() { close(j.cancel) })
+}
+
+func (j *batchJob) cancelled() bool {
+	select {
+	case <-j.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// add merges the statistics of another chunk of spins.
+func (s *batchPlayStats) add(o batchPlayStats) {
+	s.spins += o.spins
+	s.totalWagered += o.totalWagered
+	s.totalWon += o.totalWon
+	s.hitCount += o.hitCount
+	s.bigWins += o.bigWins
+	s.megaWins += o.megaWins
+	if o.maxWin > s.maxWin {
+		s.maxWin = o.maxWin
+	}
+	s.jackpotWins = append(s.jackpotWins, o.jackpotWins...)
+}
+
+// streamBatchPlay starts a batch play in the background and replies with its
+// batch ID. Progress is broadcast as lgs_batch_progress every ProgressEvery
+// spins and the final result as lgs_batch_complete. Requests changing the
+// session get 409 while it runs, and it stops early when the balance can no
+// longer cover a spin.
+func (h *Handlers) streamBatchPlay(w http.ResponseWriter, session *SessionData, req BatchPlayRequest, sampleOutcome func() stakergs.Outcome, betPerSpin int64, jackpot jackpotHook, onSpin func(stakergs.Outcome, int64)) {
+	if h.wsHub == nil {
+		h.sendError(w, "WebSocket hub not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	if req.ProgressEvery <= 0 {
+		req.ProgressEvery = DefaultBatchProgressEvery
+	}
+	if req.ProgressEvery < 100 {
+		req.ProgressEvery = 100 // keeps the hub's broadcast buffer from overflowing
+	}
+
+	batchID := fmt.Sprintf("batch-%d", time.Now().UnixNano())
+	job := &batchJob{sessionID: req.SessionID, cancel: make(chan struct{})}
+
+	h.batchesMu.Lock()
+	for _, running := range h.batches {
+		if running.sessionID == req.SessionID {
+			h.batchesMu.Unlock()
+			h.sendError(w, fmt.Sprintf("batch play already running for session %s", req.SessionID), http.StatusConflict)
+			return
+		}
+	}
+	h.batches[batchID] = job
+	h.batchesMu.Unlock()
+
+	fmt.Printf("[LGS] BatchPlay stream started: batch=%s, session=%s, mode=%s, spins=%d\n",
+		batchID, req.SessionID, req.Mode, req.Spins)
+
+	go h.runBatchStream(batchID, job, session, req, sampleOutcome, betPerSpin, jackpot, onSpin)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success":       true,
+		"batchID":       batchID,
+		"sessionID":     req.SessionID,
+		"mode":          req.Mode,
+		"spins":         req.Spins,
+		"progressEvery": req.ProgressEvery,
+	}, http.StatusAccepted)
+}
+
+func (h *Handlers) runBatchStream(batchID string, job *batchJob, session *SessionData, req BatchPlayRequest, sampleOutcome func() stakergs.Outcome, betPerSpin int64, jackpot jackpotHook, onSpin func(stakergs.Outcome, int64)) {
+	start := time.Now()
+	defer func() {
+		h.batchesMu.Lock()
+		delete(h.batches, batchID)
+		h.batchesMu.Unlock()
+	}()
+
+	// Own the session for the whole run: this waits for the requests
+	// changing it to finish, and refuses new ones until the stream ends
+	session.busy.Lock()
+	defer session.busy.Unlock()
+
+	var stats batchPlayStats
+	done := 0
+	outOfBalance := false
+	for done < req.Spins && !job.cancelled() && !outOfBalance {
+		n := min(req.ProgressEvery, req.Spins-done)
+		session.mu.Lock()
+		chunk, _ := processBatchSpins(session, sampleOutcome, n, betPerSpin, req.Amount, false, jackpot, onSpin)
+		balance := session.Balance
+		session.mu.Unlock()
+		stats.add(chunk)
+		h.broadcastJackpotWins(chunk.jackpotWins)
+		done += chunk.spins
+		outOfBalance = chunk.spins < n
+
+		progress := BatchProgress{
+			BatchID:      batchID,
+			SessionID:    req.SessionID,
+			Mode:         req.Mode,
+			SpinsDone:    done,
+			Spins:        req.Spins,
+			TotalWagered: stats.totalWagered,
+			TotalWon:     stats.totalWon,
+			HitCount:     stats.hitCount,
+			MaxWin:       stats.maxWin,
+			Balance:      balance,
+			ElapsedMs:    time.Since(start).Milliseconds(),
+		}
+		if done > 0 {
+			progress.HitRate = float64(stats.hitCount) / float64(done)
+		}
+		if stats.totalWagered > 0 {
+			progress.RTP = float64(stats.totalWon) / float64(stats.totalWagered)
+		}
+		h.wsHub.Broadcast(ws.Message{
+			Type:    ws.MsgLGSBatchProgress,
+			Mode:    req.Mode,
+			Payload: progress,
+		})
+	}
+
+	h.sessions.Update(session)
+	h.series.Record(time.Now(), int64(done), int64(stats.hitCount), stats.totalWagered, stats.totalWon)
+
+	result := BatchPlayResponse{
+		SessionID:    req.SessionID,
+		Mode:         req.Mode,
+		Spins:        done,
+		TotalWagered: stats.totalWagered,
+		TotalWon:     stats.totalWon,
+		HitCount:     stats.hitCount,
+		MaxWin:       stats.maxWin,
+		BigWins:      stats.bigWins,
+		MegaWins:     stats.megaWins,
+		Balance:      h.balanceInfo(nil, session),
+		DurationMs:   time.Since(start).Milliseconds(),
+		Jackpots:     h.jackpots.values(),
+		JackpotWins:  stats.jackpotWins,
+	}
+	if stats.totalWagered > 0 {
+		result.RTP = float64(stats.totalWon) / float64(stats.totalWagered)
+	}
+	if done > 0 {
+		result.HitRate = float64(stats.hitCount) / float64(done)
+	}
+
+	cancelled := done < req.Spins && !outOfBalance
+	fmt.Printf("[LGS] BatchPlay stream finished: batch=%s, spins=%d/%d, rtp=%.4f, cancelled=%v, outOfBalance=%v, duration=%dms\n",
+		batchID, done, req.Spins, result.RTP, cancelled, outOfBalance, result.DurationMs)
+
+	h.broadcastSessionsUpdate()
+	h.wsHub.Broadcast(ws.Message{
+		Type: ws.MsgLGSBatchComplete,
+		Mode: req.Mode,
+		Payload: map[string]interface{}{
+			"batchID":      batchID,
+			"cancelled":    cancelled,
+			"outOfBalance": outOfBalance,
+			"result":       result,
+		},
+	})
+}
+
+// CancelBatchPlay handles POST /lgs/batchplay/cancel - stops a streamed batch
+// play after the chunk in progress. Spins already played are kept.
+func (h *Handlers) CancelBatchPlay(w http.ResponseWriter, r *http.Request) {
+	var req BatchCancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	h.batchesMu.Lock()
+	job := h.batches[req.BatchID]
+	h.batchesMu.Unlock()
+	if job == nil {
+		h.sendError(w, fmt.Sprintf("batch play not running: %s", req.BatchID), http.StatusNotFound)
+		return
+	}
+	job.stop()
+
+	fmt.Printf("[LGS] BatchPlay stream cancel requested: batch=%s\n", req.BatchID)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"batchID": req.BatchID,
+	}, http.StatusOK)
+}
@@ -0,0 +1,126 @@
+package lgs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lutexplorer/internal/lut"
+	"lutexplorer/internal/ws"
+	"stakergs"
+)
+
+// ============================================================================
+// Streamed Batch Play Tests
+// ============================================================================
+
+func newTestHandlers(t *testing.T) *Handlers {
+	t.Helper()
+	dir := t.TempDir()
+	publish := filepath.Join(dir, "publish_files")
+	if err := os.Mkdir(publish, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"index.json":   `{"modes":[{"name":"base","cost":1,"events":"books_base.jsonl.zst","weights":"lut_base.csv"}]}`,
+		"lut_base.csv": "1,10,0\n2,5,200\n3,1,1000\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(publish, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loader := lut.NewLoaderFromLibrary(dir)
+	if err := loader.Load(); err != nil {
+		t.Fatalf("load library: %v", err)
+	}
+	return NewHandlers(loader, NewSessionManager(), ws.NewHub())
+}
+
+func post(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	return rec
+}
+
+// Run with -race: requests on the session of a running stream do not race
+// with it.
+func TestStreamBatchPlay_ConcurrentPlays(t *testing.T) {
+	h := newTestHandlers(t)
+
+	rec := post(h.BatchPlay, `{"sessionID":"s1","mode":"base","amount":1000000,"spins":100000,"stream":true,"progressEvery":100}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("stream not started: %d %s", rec.Code, rec.Body.String())
+	}
+
+	running := func() bool {
+		h.batchesMu.Lock()
+		defer h.batchesMu.Unlock()
+		return len(h.batches) > 0
+	}
+	refused := 0
+	deadline := time.Now().Add(30 * time.Second)
+	for running() {
+		if time.Now().After(deadline) {
+			t.Fatal("stream did not finish")
+		}
+		for _, req := range []struct {
+			handler http.HandlerFunc
+			body    string
+		}{
+			{h.Play, `{"sessionID":"s1","mode":"base","amount":1000000}`},
+			{h.SetBalance, `{"sessionID":"s1","balance":1000000000000}`},
+		} {
+			switch rec := post(req.handler, req.body); rec.Code {
+			case http.StatusOK, http.StatusBadRequest: // played, or the stream spent the balance
+			case http.StatusConflict:
+				refused++
+			default:
+				t.Fatalf("unexpected status %d %s", rec.Code, rec.Body.String())
+			}
+		}
+		h.Sessions(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lgs/sessions", nil))
+		h.Stats(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lgs/stats?sessionID=s1", nil))
+	}
+
+	t.Logf("%d requests refused while the stream ran", refused)
+	if session := h.sessions.Get("s1"); session.Balance < 0 {
+		t.Errorf("balance went negative: %d", session.Balance)
+	}
+}
+
+func TestClaimSession_RefusedWhileStreaming(t *testing.T) {
+	h := newTestHandlers(t)
+	session := h.sessions.GetOrCreate("s1")
+
+	// Hold the session the way runBatchStream does.
+	session.busy.Lock()
+	for _, rec := range []*httptest.ResponseRecorder{
+		post(h.Play, `{"sessionID":"s1","mode":"base","amount":1000000}`),
+		post(h.SetBalance, `{"sessionID":"s1","balance":5000000}`),
+		post(h.ForceOutcome, `{"sessionID":"s1","mode":"base","simID":2}`),
+	} {
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected 409 while streaming, got %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	session.busy.Unlock()
+
+	if rec := post(h.Play, `{"sessionID":"s1","mode":"base","amount":1000000}`); rec.Code != http.StatusOK {
+		t.Errorf("expected play after the stream, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProcessBatchSpins_StopsWithoutBalance(t *testing.T) {
+	session := &SessionData{Balance: 2500000}
+	sample := func() stakergs.Outcome { return stakergs.Outcome{SimID: 1} }
+
+	stats, _ := processBatchSpins(session, sample, 10, 1000000, 1000000, false, nil, nil)
+	if stats.spins != 2 || session.Balance != 500000 {
+		t.Errorf("expected 2 spins leaving 500000, got %d spins leaving %d", stats.spins, session.Balance)
+	}
+}
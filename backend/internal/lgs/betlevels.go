@@ -0,0 +1,246 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// unitsPerCurrency is the number of API amount units in one currency unit
+const unitsPerCurrency = 1000000
+
+// BetLevelConfig is the bet configuration of a mode, as an RGS would serve
+// it. Amounts are per play before the mode cost, in API units.
+type BetLevelConfig struct {
+	MinBet  int64 `json:"minBet"`
+	MaxBet  int64 `json:"maxBet"`
+	StepBet int64 `json:"stepBet"`
+	// DefaultBetLevel is played when a request has no amount; defaults to
+	// the first level, or minBet
+	DefaultBetLevel int64 `json:"defaultBetLevel"`
+	// Levels restricts bets to a fixed list; empty allows every step
+	// between minBet and maxBet
+	Levels []int64 `json:"betLevels,omitempty"`
+}
+
+// Validate checks the config and fills in its default bet level.
+func (c *BetLevelConfig) Validate() error {
+	if c.MinBet <= 0 || c.StepBet <= 0 {
+		return fmt.Errorf("minBet and stepBet must be positive")
+	}
+	if c.MinBet > c.MaxBet {
+		return fmt.Errorf("minBet must not exceed maxBet")
+	}
+	if c.MinBet%c.StepBet != 0 || c.MaxBet%c.StepBet != 0 {
+		return fmt.Errorf("minBet and maxBet must be multiples of stepBet")
+	}
+	for i, level := range c.Levels {
+		if err := c.check(level); err != nil {
+			return fmt.Errorf("bet level %d: %v", level, err)
+		}
+		if i > 0 && level <= c.Levels[i-1] {
+			return fmt.Errorf("bet levels must be ascending and unique")
+		}
+	}
+	if c.DefaultBetLevel == 0 {
+		c.DefaultBetLevel = c.MinBet
+		if len(c.Levels) > 0 {
+			c.DefaultBetLevel = c.Levels[0]
+		}
+	}
+	if err := c.Allows(c.DefaultBetLevel); err != nil {
+		return fmt.Errorf("defaultBetLevel: %v", err)
+	}
+	return nil
+}
+
+// check tests an amount against the range and step only
+func (c *BetLevelConfig) check(amount int64) error {
+	if amount < c.MinBet || amount > c.MaxBet {
+		return fmt.Errorf("outside %d-%d", c.MinBet, c.MaxBet)
+	}
+	if amount%c.StepBet != 0 {
+		return fmt.Errorf("not a multiple of %d", c.StepBet)
+	}
+	return nil
+}
+
+// Allows reports why an amount is not a valid bet, or nil if it is.
+func (c *BetLevelConfig) Allows(amount int64) error {
+	if err := c.check(amount); err != nil {
+		return err
+	}
+	if len(c.Levels) > 0 {
+		i := sort.Search(len(c.Levels), func(i int) bool { return c.Levels[i] >= amount })
+		if i == len(c.Levels) || c.Levels[i] != amount {
+			return fmt.Errorf("not a configured bet level")
+		}
+	}
+	return nil
+}
+
+// roundToCurrency rounds an amount to the minor unit of a currency, e.g.
+// whole yen or cents, the way an RGS settles bets.
+func roundToCurrency(amount int64, currency Currency) int64 {
+	unit := currency.unit()
+	return (amount + unit/2) / unit * unit
+}
+
+// betLevelTable holds the bet configuration of modes by lower-cased name.
+// Modes without a config accept any amount.
+type betLevelTable struct {
+	mu    sync.RWMutex
+	modes map[string]BetLevelConfig
+}
+
+func (t *betLevelTable) get(mode string) (BetLevelConfig, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	c, ok := t.modes[strings.ToLower(mode)]
+	return c, ok
+}
+
+func (t *betLevelTable) all() map[string]BetLevelConfig {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make(map[string]BetLevelConfig, len(t.modes))
+	for mode, c := range t.modes {
+		out[mode] = c
+	}
+	return out
+}
+
+// set merges configs into the table; replace drops the modes not listed.
+func (t *betLevelTable) set(configs map[string]BetLevelConfig, replace bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if replace || t.modes == nil {
+		t.modes = make(map[string]BetLevelConfig, len(configs))
+	}
+	for mode, c := range configs {
+		t.modes[strings.ToLower(mode)] = c
+	}
+}
+
+// clear removes the config of a mode, or of every mode if mode is empty.
+func (t *betLevelTable) clear(mode string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if mode == "" {
+		t.modes = nil
+		return true
+	}
+	if _, ok := t.modes[strings.ToLower(mode)]; !ok {
+		return false
+	}
+	delete(t.modes, strings.ToLower(mode))
+	return true
+}
+
+// resolve returns the amount to play in a mode: a zero amount becomes the
+// default bet level, others are rounded to the currency and checked against
+// the mode's config. Without a config the amount is returned unchanged.
+func (t *betLevelTable) resolve(mode string, amount int64, currency Currency) (int64, error) {
+	c, ok := t.get(mode)
+	if !ok {
+		return amount, nil
+	}
+	if amount == 0 {
+		return c.DefaultBetLevel, nil
+	}
+	rounded := roundToCurrency(amount, currency)
+	if err := c.Allows(rounded); err != nil {
+		return 0, fmt.Errorf("invalid bet %d for mode %s: %v", amount, mode, err)
+	}
+	return rounded, nil
+}
+
+// betModes describes the configured modes for the authenticate config
+func (t *betLevelTable) betModes() map[string]interface{} {
+	modes := make(map[string]interface{})
+	for mode, c := range t.all() {
+		modes[mode] = c
+	}
+	return modes
+}
+
+// configInfo returns the game configuration sent to clients, with the
+// configured modes under betModes.
+func (h *Handlers) configInfo() ConfigInfo {
+	config := DefaultConfigInfo()
+	config.BetModes = h.betLevels.betModes()
+	return config
+}
+
+// BetLevelsRequest for POST /lgs/config/bet-levels
+type BetLevelsRequest struct {
+	// Modes maps mode names to their bet configuration
+	Modes map[string]BetLevelConfig `json:"modes"`
+	// Replace drops the configs of modes not listed instead of keeping them
+	Replace bool `json:"replace"`
+}
+
+// BetLevelsResponse for /lgs/config/bet-levels
+type BetLevelsResponse struct {
+	Modes   map[string]BetLevelConfig `json:"modes"`
+	Message string                    `json:"message,omitempty"`
+}
+
+// SetBetLevels handles POST /lgs/config/bet-levels - configures the bet
+// levels of modes. Plays in a configured mode reject bets outside them.
+func (h *Handlers) SetBetLevels(w http.ResponseWriter, r *http.Request) {
+	var req BetLevelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Modes) == 0 {
+		h.sendError(w, "modes are required", http.StatusBadRequest)
+		return
+	}
+	// Validate every mode before applying any
+	for mode, c := range req.Modes {
+		if _, err := h.loader.GetMode(mode); err != nil {
+			h.sendError(w, fmt.Sprintf("mode not found: %s", mode), http.StatusBadRequest)
+			return
+		}
+		if err := c.Validate(); err != nil {
+			h.sendError(w, fmt.Sprintf("mode %s: %v", mode, err), http.StatusBadRequest)
+			return
+		}
+		req.Modes[mode] = c
+	}
+	h.betLevels.set(req.Modes, req.Replace)
+
+	fmt.Printf("[LGS] Bet levels: %d modes configured, replace=%v\n", len(req.Modes), req.Replace)
+
+	h.sendJSON(w, BetLevelsResponse{
+		Modes:   h.betLevels.all(),
+		Message: fmt.Sprintf("bet levels set for %d modes", len(req.Modes)),
+	}, http.StatusOK)
+}
+
+// GetBetLevels handles GET /lgs/config/bet-levels - the configured modes
+func (h *Handlers) GetBetLevels(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, BetLevelsResponse{Modes: h.betLevels.all()}, http.StatusOK)
+}
+
+// ClearBetLevels handles DELETE /lgs/config/bet-levels - removes the config
+// of a mode, or of all modes without one
+func (h *Handlers) ClearBetLevels(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if !h.betLevels.clear(mode) {
+		h.sendError(w, fmt.Sprintf("no bet levels configured for mode %s", mode), http.StatusNotFound)
+		return
+	}
+
+	fmt.Printf("[LGS] Clear Bet levels: mode=%q\n", mode)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"message": "bet levels cleared",
+	}, http.StatusOK)
+}
@@ -0,0 +1,443 @@
+package lgs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CassettesDir is the folder inside the library that holds recorded LGS
+// traffic.
+const CassettesDir = ".lutexplorer/cassettes"
+
+// Tape modes of the wallet and bet endpoints.
+const (
+	TapeOff    = "off"
+	TapeRecord = "record"
+	TapeReplay = "replay"
+)
+
+var cassetteNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Method string `json:"method"`
+	// Path includes the query string
+	Path string `json:"path"`
+	// Request and Response hold JSON bodies as is; other bodies are stored
+	// as JSON strings
+	Request     json.RawMessage `json:"request,omitempty"`
+	Status      int             `json:"status"`
+	ContentType string          `json:"contentType,omitempty"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// Cassette is a recording of /wallet and /bet traffic that can be served
+// back verbatim, so front-end CI runs are deterministic without the maths.
+type Cassette struct {
+	Name         string        `json:"name"`
+	RecordedAt   time.Time     `json:"recordedAt"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// CassetteInfo describes a stored cassette.
+type CassetteInfo struct {
+	Name         string    `json:"name"`
+	RecordedAt   time.Time `json:"recordedAt"`
+	Interactions int       `json:"interactions"`
+	Size         int64     `json:"size"`
+}
+
+// TapeStatus is the current recording or replay state.
+type TapeStatus struct {
+	Mode     string `json:"mode"`
+	Cassette string `json:"cassette,omitempty"`
+	// Interactions recorded so far, or available for replay
+	Interactions int `json:"interactions"`
+	// Played counts interactions already served in replay mode
+	Played int `json:"played"`
+	// Misses counts replay requests without a recorded response
+	Misses int `json:"misses"`
+}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette file: %w", err)
+	}
+	if c.Name == "" {
+		c.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return &c, nil
+}
+
+// tapeDeck records or replays the wallet and bet endpoints.
+type tapeDeck struct {
+	mu       sync.Mutex
+	mode     string
+	cassette *Cassette
+	played   []bool
+	misses   int
+}
+
+func (d *tapeDeck) status() TapeStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := TapeStatus{Mode: d.mode, Misses: d.misses}
+	if status.Mode == "" {
+		status.Mode = TapeOff
+	}
+	if d.cassette != nil {
+		status.Cassette = d.cassette.Name
+		status.Interactions = len(d.cassette.Interactions)
+	}
+	for _, p := range d.played {
+		if p {
+			status.Played++
+		}
+	}
+	return status
+}
+
+// next finds the first unplayed interaction for a request: one with the
+// same body if there is one, else the next one on the same path.
+func (d *tapeDeck) next(method, path string, body []byte) (*Interaction, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fallback := -1
+	for i := range d.cassette.Interactions {
+		in := &d.cassette.Interactions[i]
+		if d.played[i] || in.Method != method || in.Path != path {
+			continue
+		}
+		if sameBody(in.Request, body) {
+			d.played[i] = true
+			return in, true
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback >= 0 {
+		d.played[fallback] = true
+		return &d.cassette.Interactions[fallback], true
+	}
+	d.misses++
+	return nil, false
+}
+
+// Taped wraps a wallet or bet handler so its traffic is recorded to, or
+// served from, the active cassette.
+func (h *Handlers) Taped(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.tape.mu.Lock()
+		mode := h.tape.mode
+		h.tape.mu.Unlock()
+		if mode != TapeRecord && mode != TapeReplay {
+			next(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.sendError(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		path := r.URL.RequestURI()
+
+		if mode == TapeReplay {
+			in, ok := h.tape.next(r.Method, path, body)
+			if !ok {
+				fmt.Printf("[LGS] Tape: no recorded response for %s %s\n", r.Method, path)
+				h.sendError(w, fmt.Sprintf("no recorded response for %s %s", r.Method, path), http.StatusNotFound)
+				return
+			}
+			contentType := in.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.WriteHeader(in.Status)
+			w.Write(bodyBytes(in.Response, contentType))
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		in := Interaction{
+			Method:      r.Method,
+			Path:        path,
+			Status:      rec.status,
+			ContentType: rec.Header().Get("Content-Type"),
+			Response:    rawBody(rec.body.Bytes()),
+		}
+		if len(body) > 0 {
+			in.Request = rawBody(body)
+		}
+		h.tape.mu.Lock()
+		if h.tape.mode == TapeRecord {
+			h.tape.cassette.Interactions = append(h.tape.cassette.Interactions, in)
+		}
+		h.tape.mu.Unlock()
+	}
+}
+
+// recordingWriter keeps a copy of the response while passing it through.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// rawBody stores a JSON body compacted, and anything else as a JSON string.
+func rawBody(body []byte) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, body); err == nil {
+		return json.RawMessage(buf.Bytes())
+	}
+	quoted, _ := json.Marshal(string(body))
+	return json.RawMessage(quoted)
+}
+
+// bodyBytes restores a body stored by rawBody. JSON is compacted again, as
+// cassette files are saved indented.
+func bodyBytes(raw json.RawMessage, contentType string) []byte {
+	if !strings.Contains(contentType, "json") {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return []byte(s)
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func sameBody(recorded json.RawMessage, body []byte) bool {
+	if len(recorded) == 0 || len(body) == 0 {
+		return len(recorded) == 0 && len(body) == 0
+	}
+	return bytes.Equal(rawBody(recorded), rawBody(body))
+}
+
+// cassettePath returns the file of a named cassette in the current library.
+func (h *Handlers) cassettePath(name string) (string, error) {
+	if !cassetteNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid cassette name %q (letters, digits, '.', '_' and '-')", name)
+	}
+	if !h.loader.IsOpen() {
+		return "", errors.New("no library open")
+	}
+	return filepath.Join(h.loader.BaseDir(), filepath.FromSlash(CassettesDir), name+".json"), nil
+}
+
+// StartReplay serves a cassette on the wallet and bet endpoints until the
+// tape is stopped.
+func (h *Handlers) StartReplay(c *Cassette) {
+	h.tape.mu.Lock()
+	h.tape.mode = TapeReplay
+	h.tape.cassette = c
+	h.tape.played = make([]bool, len(c.Interactions))
+	h.tape.misses = 0
+	h.tape.mu.Unlock()
+	fmt.Printf("[LGS] Tape: replaying cassette %q (%d interactions)\n", c.Name, len(c.Interactions))
+}
+
+// Cassettes handles GET /lgs/cassettes - stored cassettes and tape state
+func (h *Handlers) Cassettes(w http.ResponseWriter, r *http.Request) {
+	infos := []CassetteInfo{}
+	if h.loader.IsOpen() {
+		dir := filepath.Join(h.loader.BaseDir(), filepath.FromSlash(CassettesDir))
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, file := range files {
+			c, err := LoadCassette(file)
+			if err != nil {
+				continue
+			}
+			info := CassetteInfo{
+				Name:         strings.TrimSuffix(filepath.Base(file), ".json"),
+				RecordedAt:   c.RecordedAt,
+				Interactions: len(c.Interactions),
+			}
+			if stat, err := os.Stat(file); err == nil {
+				info.Size = stat.Size()
+			}
+			infos = append(infos, info)
+		}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	}
+
+	h.sendJSON(w, map[string]interface{}{
+		"cassettes": infos,
+		"tape":      h.tape.status(),
+	}, http.StatusOK)
+}
+
+// RecordCassette handles POST /lgs/cassettes/record - starts recording
+// wallet and bet traffic into a new cassette
+func (h *Handlers) RecordCassette(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.cassettePath(req.Name); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.tape.mu.Lock()
+	h.tape.mode = TapeRecord
+	h.tape.cassette = &Cassette{Name: req.Name, RecordedAt: time.Now().UTC(), Interactions: []Interaction{}}
+	h.tape.played = nil
+	h.tape.misses = 0
+	h.tape.mu.Unlock()
+
+	fmt.Printf("[LGS] Tape: recording cassette %q\n", req.Name)
+	h.sendJSON(w, h.tape.status(), http.StatusOK)
+}
+
+// ReplayCassette handles POST /lgs/cassettes/replay - serves a stored
+// cassette on the wallet and bet endpoints
+func (h *Handlers) ReplayCassette(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	path, err := h.cassettePath(req.Name)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c, err := LoadCassette(path)
+	if errors.Is(err, os.ErrNotExist) {
+		h.sendError(w, fmt.Sprintf("cassette %q not found", req.Name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.StartReplay(c)
+	h.sendJSON(w, h.tape.status(), http.StatusOK)
+}
+
+// StopCassette handles POST /lgs/cassettes/stop - ends recording or replay.
+// A recording is saved to the library.
+func (h *Handlers) StopCassette(w http.ResponseWriter, r *http.Request) {
+	status := h.tape.status()
+
+	h.tape.mu.Lock()
+	mode, cassette := h.tape.mode, h.tape.cassette
+	h.tape.mode = TapeOff
+	h.tape.cassette = nil
+	h.tape.played = nil
+	h.tape.mu.Unlock()
+
+	if mode == TapeRecord && cassette != nil {
+		if err := h.saveCassette(cassette); err != nil {
+			h.sendError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Printf("[LGS] Tape: saved cassette %q (%d interactions)\n", cassette.Name, len(cassette.Interactions))
+	}
+
+	h.sendJSON(w, status, http.StatusOK)
+}
+
+func (h *Handlers) saveCassette(c *Cassette) error {
+	path, err := h.cassettePath(c.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// GetCassette handles GET /lgs/cassettes/{name} - downloads a cassette
+func (h *Handlers) GetCassette(w http.ResponseWriter, r *http.Request) {
+	path, err := h.cassettePath(r.PathValue("name"))
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c, err := LoadCassette(path)
+	if errors.Is(err, os.ErrNotExist) {
+		h.sendError(w, fmt.Sprintf("cassette %q not found", r.PathValue("name")), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.sendJSON(w, c, http.StatusOK)
+}
+
+// DeleteCassette handles DELETE /lgs/cassettes/{name}
+func (h *Handlers) DeleteCassette(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	path, err := h.cassettePath(name)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		h.sendError(w, fmt.Sprintf("cassette %q not found", name), http.StatusNotFound)
+		return
+	} else if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"deleted": name,
+	}, http.StatusOK)
+}
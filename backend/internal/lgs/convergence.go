@@ -0,0 +1,191 @@
+package lgs
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lutexplorer/internal/lut"
+	"stakergs"
+)
+
+// modeReturns accumulates the per-spin returns (payout / bet) of a session
+// in one mode. Only random draws are tracked: forced outcomes and plays
+// with an RTP bias do not follow the table and are left out.
+type modeReturns struct {
+	Spins int64
+	Hits  int64
+	Sum   float64
+	SumSq float64
+}
+
+// trackReturn adds a spin to the session's returns in a mode.
+func (s *SessionData) trackReturn(mode string, bet, payout int64) {
+	if bet <= 0 {
+		return
+	}
+	if s.Returns == nil {
+		s.Returns = make(map[string]*modeReturns)
+	}
+	key := strings.ToLower(mode)
+	m := s.Returns[key]
+	if m == nil {
+		m = &modeReturns{}
+		s.Returns[key] = m
+	}
+	ret := float64(payout) / float64(bet)
+	m.Spins++
+	m.Sum += ret
+	m.SumSq += ret * ret
+	if payout > 0 {
+		m.Hits++
+	}
+}
+
+// returnTracker wraps a processBatchSpins callback so batch spins are
+// tracked too.
+func (s *SessionData) returnTracker(mode string, bet int64, next func(stakergs.Outcome, int64)) func(stakergs.Outcome, int64) {
+	return func(outcome stakergs.Outcome, payout int64) {
+		s.trackReturn(mode, bet, payout)
+		if next != nil {
+			next(outcome, payout)
+		}
+	}
+}
+
+// Convergence tells how far a session's observed RTP in a mode is from the
+// table's, and whether the gap is more than chance. RTPs are relative to
+// the bet including the mode cost.
+type Convergence struct {
+	Mode  string `json:"mode"`
+	Spins int64  `json:"spins"`
+
+	ObservedRTP    float64 `json:"observedRTP"`
+	TheoreticalRTP float64 `json:"theoreticalRTP"`
+	// StdDev is the per-spin standard deviation of the table's returns, and
+	// StandardError the expected spread of ObservedRTP after Spins
+	StdDev        float64 `json:"stdDev"`
+	StandardError float64 `json:"standardError"`
+	Confidence    float64 `json:"confidence"`
+	// RTPLow and RTPHigh bound the true RTP at Confidence (normal
+	// approximation around ObservedRTP)
+	RTPLow  float64 `json:"rtpLow"`
+	RTPHigh float64 `json:"rtpHigh"`
+	// ZScore is the deviation from TheoreticalRTP in standard errors;
+	// Significant is set when it exceeds the critical value at Confidence,
+	// i.e. the deviation is unlikely to be chance
+	ZScore      float64 `json:"zScore"`
+	Significant bool    `json:"significant"`
+
+	ObservedHitRate    float64 `json:"observedHitRate"`
+	TheoreticalHitRate float64 `json:"theoreticalHitRate"`
+	// HitRateLow and HitRateHigh are the Wilson score interval
+	HitRateLow  float64 `json:"hitRateLow"`
+	HitRateHigh float64 `json:"hitRateHigh"`
+
+	// SpinsToConverge are the spins after which ObservedRTP is within
+	// Tolerance of TheoreticalRTP at Confidence; RemainingSpins are those
+	// still to play
+	Tolerance       float64 `json:"tolerance"`
+	SpinsToConverge int64   `json:"spinsToConverge"`
+	RemainingSpins  int64   `json:"remainingSpins"`
+	Converged       bool    `json:"converged"`
+}
+
+// convergence computes the convergence of each mode the session played,
+// sorted by mode. Modes whose table is gone report the observed figures
+// with intervals from the sample variance.
+func (h *Handlers) convergence(session *SessionData, confidence, tolerance float64) []Convergence {
+	if len(session.Returns) == 0 {
+		return nil
+	}
+	if confidence <= 0 || confidence >= 1 {
+		confidence = lut.DefaultConfidence
+	}
+	if tolerance <= 0 {
+		tolerance = lut.DefaultRTPTolerance
+	}
+	z := lut.ZScore(confidence)
+
+	out := make([]Convergence, 0, len(session.Returns))
+	for mode, m := range session.Returns {
+		if m.Spins == 0 {
+			continue
+		}
+		n := float64(m.Spins)
+		c := Convergence{
+			Mode:            mode,
+			Spins:           m.Spins,
+			ObservedRTP:     m.Sum / n,
+			ObservedHitRate: float64(m.Hits) / n,
+			Confidence:      confidence,
+			Tolerance:       tolerance,
+		}
+
+		table, err := h.tableFor(session, mode)
+		if err == nil {
+			c.TheoreticalRTP, c.StdDev = lut.SpinRTPStats(table)
+			c.TheoreticalHitRate = hitRate(table)
+		} else if m.Spins > 1 {
+			variance := (m.SumSq - m.Sum*m.Sum/n) / (n - 1)
+			c.StdDev = math.Sqrt(math.Max(variance, 0))
+		}
+
+		c.StandardError = c.StdDev / math.Sqrt(n)
+		c.RTPLow = math.Max(0, c.ObservedRTP-z*c.StandardError)
+		c.RTPHigh = c.ObservedRTP + z*c.StandardError
+		c.HitRateLow, c.HitRateHigh = wilsonInterval(m.Hits, m.Spins, z)
+
+		if err == nil {
+			if c.StandardError > 0 {
+				c.ZScore = (c.ObservedRTP - c.TheoreticalRTP) / c.StandardError
+				c.Significant = math.Abs(c.ZScore) > z
+			}
+			c.SpinsToConverge = lut.RequiredSpins(c.StdDev, tolerance, confidence)
+			c.RemainingSpins = max(0, c.SpinsToConverge-m.Spins)
+			c.Converged = c.RemainingSpins == 0
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Mode < out[j].Mode })
+	return out
+}
+
+// hitRate is the probability of a non-zero payout in a table.
+func hitRate(table *stakergs.LookupTable) float64 {
+	total := table.TotalWeight()
+	if total == 0 {
+		return 0
+	}
+	var hits uint64
+	for _, o := range table.Outcomes {
+		if o.Payout > 0 {
+			hits += o.Weight
+		}
+	}
+	return float64(hits) / float64(total)
+}
+
+// wilsonInterval is the Wilson score interval of a proportion, which stays
+// sensible for small samples and rates close to 0 or 1.
+func wilsonInterval(successes, trials int64, z float64) (low, high float64) {
+	if trials == 0 {
+		return 0, 0
+	}
+	n := float64(trials)
+	p := float64(successes) / n
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	half := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return math.Max(0, center-half), math.Min(1, center+half)
+}
+
+// convergenceParams reads ?confidence and ?tolerance, zero when absent or
+// invalid so the defaults apply.
+func convergenceParams(r *http.Request) (confidence, tolerance float64) {
+	confidence, _ = strconv.ParseFloat(r.URL.Query().Get("confidence"), 64)
+	tolerance, _ = strconv.ParseFloat(r.URL.Query().Get("tolerance"), 64)
+	return confidence, tolerance
+}
@@ -0,0 +1,320 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"lutexplorer/internal/numfmt"
+)
+
+// BaseCurrency is the currency aggregate stats are normalized to.
+const BaseCurrency = "USD"
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Currency is a currency sessions can play in.
+type Currency struct {
+	Code string `json:"code"`
+	// Decimals of the minor unit: 2 for cents, 0 for yen. Amounts must be
+	// whole minor units.
+	Decimals int    `json:"decimals"`
+	Symbol   string `json:"symbol,omitempty"`
+	// Rate is the value of one unit in BaseCurrency, e.g. 1.08 for EUR
+	Rate float64 `json:"rate"`
+}
+
+// unit returns the size of the currency's minor unit in API units.
+func (c Currency) unit() int64 {
+	unit := int64(unitsPerCurrency)
+	for d := c.Decimals; d > 0 && unit > 1; d-- {
+		unit /= 10
+	}
+	return unit
+}
+
+// toBase converts an amount in API units to BaseCurrency units.
+func (c Currency) toBase(amount int64) float64 {
+	return float64(amount) / unitsPerCurrency * c.Rate
+}
+
+// Validate normalizes the code and checks the fields.
+func (c *Currency) Validate() error {
+	c.Code = strings.ToUpper(c.Code)
+	if !currencyCodePattern.MatchString(c.Code) {
+		return fmt.Errorf("currency code %q must be three letters", c.Code)
+	}
+	if c.Decimals < 0 || c.Decimals > 6 {
+		return fmt.Errorf("%s: decimals must be between 0 and 6", c.Code)
+	}
+	if c.Rate <= 0 || math.IsInf(c.Rate, 0) || math.IsNaN(c.Rate) {
+		return fmt.Errorf("%s: rate must be positive", c.Code)
+	}
+	return nil
+}
+
+// DefaultCurrencies are accepted until others are configured. Rates are
+// indicative only; post current ones to /lgs/config/currencies when they
+// matter.
+var DefaultCurrencies = []Currency{
+	{Code: "USD", Rate: 1},
+	{Code: "EUR", Rate: 1.08},
+	{Code: "GBP", Rate: 1.27},
+	{Code: "CAD", Rate: 0.73},
+	{Code: "AUD", Rate: 0.66},
+	{Code: "JPY", Rate: 0.0067},
+	{Code: "KRW", Rate: 0.00074},
+	{Code: "INR", Rate: 0.012},
+	{Code: "BRL", Rate: 0.18},
+	{Code: "TRY", Rate: 0.029},
+	{Code: "PLN", Rate: 0.25},
+	{Code: "RUB", Rate: 0.011},
+	{Code: "KWD", Rate: 3.25},
+}
+
+func init() {
+	for i := range DefaultCurrencies {
+		c := &DefaultCurrencies[i]
+		c.Decimals = numfmt.MinorUnits(c.Code)
+		c.Symbol = numfmt.Symbol(c.Code)
+	}
+}
+
+// currencyRegistry holds the accepted currencies by code; nil means the
+// defaults.
+type currencyRegistry struct {
+	mu         sync.RWMutex
+	currencies map[string]Currency
+}
+
+func (r *currencyRegistry) get(code string) (Currency, bool) {
+	code = strings.ToUpper(code)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.currencies == nil {
+		for _, c := range DefaultCurrencies {
+			if c.Code == code {
+				return c, true
+			}
+		}
+		return Currency{}, false
+	}
+	c, ok := r.currencies[code]
+	return c, ok
+}
+
+// all returns the accepted currencies sorted by code.
+func (r *currencyRegistry) all() []Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.currencies == nil {
+		out := append([]Currency(nil), DefaultCurrencies...)
+		sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+		return out
+	}
+	out := make([]Currency, 0, len(r.currencies))
+	for _, c := range r.currencies {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// set adds or updates currencies; replace drops the others.
+func (r *currencyRegistry) set(currencies []Currency, replace bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if replace {
+		r.currencies = make(map[string]Currency, len(currencies))
+	} else if r.currencies == nil {
+		r.currencies = make(map[string]Currency, len(DefaultCurrencies)+len(currencies))
+		for _, c := range DefaultCurrencies {
+			r.currencies[c.Code] = c
+		}
+	}
+	for _, c := range currencies {
+		r.currencies[c.Code] = c
+	}
+}
+
+// reset restores the defaults.
+func (r *currencyRegistry) reset() {
+	r.mu.Lock()
+	r.currencies = nil
+	r.mu.Unlock()
+}
+
+// currency looks up a currency for a request.
+func (h *Handlers) currency(code string) (Currency, error) {
+	c, ok := h.currencies.get(code)
+	if !ok {
+		return Currency{}, fmt.Errorf("unsupported currency %q", code)
+	}
+	return c, nil
+}
+
+// checkAmount rejects amounts that are not whole minor units of c.
+func (c Currency) checkAmount(amount int64, what string) error {
+	if unit := c.unit(); amount%unit != 0 {
+		return fmt.Errorf("%s %d is not a whole number of %s minor units (multiples of %d)", what, amount, c.Code, unit)
+	}
+	return nil
+}
+
+// convert converts an amount in API units between currencies, rounded down
+// to whole minor units of the target.
+func convert(amount int64, from, to Currency) int64 {
+	value := float64(amount) * from.Rate / to.Rate
+	unit := to.unit()
+	return int64(value/float64(unit)) * unit
+}
+
+// switchCurrency moves a session to another currency. A session that has
+// not played yet has its balance converted; one that has is refused, since
+// its history and totals are in the old currency.
+func (h *Handlers) switchCurrency(session *SessionData, to Currency) error {
+	if strings.EqualFold(session.Currency, to.Code) {
+		return nil
+	}
+	if session.TotalBets > 0 {
+		return fmt.Errorf("session %s plays in %s; set a new balance to switch to %s", session.SessionID, session.Currency, to.Code)
+	}
+	if from, ok := h.currencies.get(session.Currency); ok {
+		session.Balance = convert(session.Balance, from, to)
+	}
+	session.Currency = to.Code
+	return nil
+}
+
+// balanceInfo returns a session's balance, formatted for the request.
+func (h *Handlers) balanceInfo(r *http.Request, session *SessionData) BalanceInfo {
+	return BalanceInfo{
+		Amount:    session.Balance,
+		Currency:  session.Currency,
+		Formatted: h.formatBalance(r, session.Balance, session.Currency),
+	}
+}
+
+// formatBalance formats a balance in API units (1000000 = 1 unit of the
+// currency) for the request's Accept-Language, or in English if r is nil.
+func (h *Handlers) formatBalance(r *http.Request, units int64, currency string) string {
+	var language string
+	if r != nil {
+		language = r.Header.Get("Accept-Language")
+	}
+	locale := numfmt.FromAcceptLanguage(language)
+	c, ok := h.currencies.get(currency)
+	if !ok {
+		return locale.Currency(float64(units)/unitsPerCurrency, currency)
+	}
+	return locale.Money(float64(units)/unitsPerCurrency, c.Symbol, c.Code, c.Decimals)
+}
+
+// baseTotals accumulates session amounts in BaseCurrency.
+type baseTotals struct {
+	wagered, won float64
+	// unconverted counts sessions in currencies without a rate
+	unconverted int
+}
+
+func (t *baseTotals) add(h *Handlers, s *SessionData) {
+	c, ok := h.currencies.get(s.Currency)
+	if !ok {
+		t.unconverted++
+		return
+	}
+	t.wagered += c.toBase(s.TotalWagered)
+	t.won += c.toBase(s.TotalWon)
+}
+
+// apply fills the normalized fields of stats. The overall RTP is taken
+// from the normalized totals so sessions in different currencies weigh by
+// value.
+func (t *baseTotals) apply(stats *AggregateStats) {
+	stats.BaseCurrency = BaseCurrency
+	stats.TotalWageredBase = t.wagered
+	stats.TotalWonBase = t.won
+	stats.TotalProfitBase = t.wagered - t.won
+	stats.UnconvertedSessions = t.unconverted
+	if t.wagered > 0 {
+		stats.OverallRTP = t.won / t.wagered
+	}
+}
+
+// CurrenciesRequest for POST /lgs/config/currencies
+type CurrenciesRequest struct {
+	Currencies []Currency `json:"currencies"`
+	// Replace drops the currencies not listed instead of keeping them
+	Replace bool `json:"replace"`
+}
+
+// CurrenciesResponse for /lgs/config/currencies
+type CurrenciesResponse struct {
+	BaseCurrency string     `json:"baseCurrency"`
+	Currencies   []Currency `json:"currencies"`
+	Message      string     `json:"message,omitempty"`
+}
+
+// GetCurrencies handles GET /lgs/config/currencies - the accepted currencies
+func (h *Handlers) GetCurrencies(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, CurrenciesResponse{BaseCurrency: BaseCurrency, Currencies: h.currencies.all()}, http.StatusOK)
+}
+
+// SetCurrencies handles POST /lgs/config/currencies - adds or updates
+// currencies and their exchange rates
+func (h *Handlers) SetCurrencies(w http.ResponseWriter, r *http.Request) {
+	var req CurrenciesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Currencies) == 0 {
+		h.sendError(w, "currencies are required", http.StatusBadRequest)
+		return
+	}
+	hasBase := !req.Replace
+	for i := range req.Currencies {
+		if err := req.Currencies[i].Validate(); err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Currencies[i].Code == BaseCurrency {
+			if req.Currencies[i].Rate != 1 {
+				h.sendError(w, fmt.Sprintf("%s is the base currency; its rate must be 1", BaseCurrency), http.StatusBadRequest)
+				return
+			}
+			hasBase = true
+		}
+	}
+	if !hasBase {
+		h.sendError(w, fmt.Sprintf("replacing the currencies must keep %s, the base currency", BaseCurrency), http.StatusBadRequest)
+		return
+	}
+	h.currencies.set(req.Currencies, req.Replace)
+
+	fmt.Printf("[LGS] Currencies: %d configured, replace=%v\n", len(req.Currencies), req.Replace)
+
+	h.sendJSON(w, CurrenciesResponse{
+		BaseCurrency: BaseCurrency,
+		Currencies:   h.currencies.all(),
+		Message:      fmt.Sprintf("%d currencies configured", len(req.Currencies)),
+	}, http.StatusOK)
+}
+
+// ResetCurrencies handles DELETE /lgs/config/currencies - restores the
+// default currencies
+func (h *Handlers) ResetCurrencies(w http.ResponseWriter, r *http.Request) {
+	h.currencies.reset()
+
+	fmt.Printf("[LGS] Currencies reset to defaults\n")
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"message": "currencies reset to defaults",
+	}, http.StatusOK)
+}
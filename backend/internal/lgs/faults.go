@@ -0,0 +1,283 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FaultAnyEndpoint is the endpoint of rules that apply to every wallet and
+// bet endpoint without a rule of its own.
+const FaultAnyEndpoint = "*"
+
+// maxFaultDelayMs bounds injected latency.
+const maxFaultDelayMs = 60000
+
+// FaultRule injects faults into a wallet or bet endpoint. Probabilities are
+// between 0 and 1 and drawn independently per request.
+type FaultRule struct {
+	// Endpoint is a path such as "/wallet/play", or "*"
+	Endpoint string `json:"endpoint"`
+	// DelayMs, plus up to DelayJitterMs, is added before the request is
+	// handled with DelayProbability
+	DelayMs          int     `json:"delayMs,omitempty"`
+	DelayJitterMs    int     `json:"delayJitterMs,omitempty"`
+	DelayProbability float64 `json:"delayProbability,omitempty"`
+	// ErrorProbability answers with ErrorStatus (500 by default) without
+	// handling the request
+	ErrorProbability float64 `json:"errorProbability,omitempty"`
+	ErrorStatus      int     `json:"errorStatus,omitempty"`
+	// DropProbability closes the connection without handling the request,
+	// as if it was lost on the way
+	DropProbability float64 `json:"dropProbability,omitempty"`
+	// DuplicateRoundProbability makes /wallet/play answer with the bet ID of
+	// the previous round
+	DuplicateRoundProbability float64 `json:"duplicateRoundProbability,omitempty"`
+}
+
+// Validate fills the defaults and checks the fields.
+func (f *FaultRule) Validate() error {
+	if f.Endpoint == "" {
+		return fmt.Errorf("endpoint is required (a path, or %q for all)", FaultAnyEndpoint)
+	}
+	if f.DelayMs < 0 || f.DelayJitterMs < 0 || f.DelayMs+f.DelayJitterMs > maxFaultDelayMs {
+		return fmt.Errorf("%s: delays must be between 0 and %dms", f.Endpoint, maxFaultDelayMs)
+	}
+	if f.DelayMs+f.DelayJitterMs > 0 && f.DelayProbability == 0 {
+		f.DelayProbability = 1
+	}
+	if f.ErrorStatus == 0 {
+		f.ErrorStatus = http.StatusInternalServerError
+	}
+	if f.ErrorStatus < 400 || f.ErrorStatus > 599 {
+		return fmt.Errorf("%s: errorStatus must be a 4xx or 5xx status", f.Endpoint)
+	}
+	probabilities := []struct {
+		name  string
+		value float64
+	}{
+		{"delayProbability", f.DelayProbability},
+		{"errorProbability", f.ErrorProbability},
+		{"dropProbability", f.DropProbability},
+		{"duplicateRoundProbability", f.DuplicateRoundProbability},
+	}
+	for _, p := range probabilities {
+		if p.value < 0 || p.value > 1 || math.IsNaN(p.value) {
+			return fmt.Errorf("%s: %s must be between 0 and 1", f.Endpoint, p.name)
+		}
+	}
+	return nil
+}
+
+// FaultCounts counts the faults injected by a rule.
+type FaultCounts struct {
+	Delayed    int64 `json:"delayed"`
+	Errors     int64 `json:"errors"`
+	Dropped    int64 `json:"dropped"`
+	Duplicated int64 `json:"duplicated"`
+}
+
+// FaultStatus is a rule and what it injected so far.
+type FaultStatus struct {
+	FaultRule
+	Injected FaultCounts `json:"injected"`
+}
+
+type faultEntry struct {
+	rule   FaultRule
+	counts FaultCounts
+}
+
+// faultTable holds the fault rules by endpoint.
+type faultTable struct {
+	mu    sync.Mutex
+	rules map[string]*faultEntry
+}
+
+// set adds or replaces rules; replace drops the others.
+func (t *faultTable) set(rules []FaultRule, replace bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if replace || t.rules == nil {
+		t.rules = make(map[string]*faultEntry, len(rules))
+	}
+	for _, rule := range rules {
+		t.rules[rule.Endpoint] = &faultEntry{rule: rule}
+	}
+}
+
+// clear removes the rule of an endpoint, or all rules when endpoint is
+// empty. Returns false if the endpoint has no rule.
+func (t *faultTable) clear(endpoint string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if endpoint == "" {
+		t.rules = nil
+		return true
+	}
+	if _, ok := t.rules[endpoint]; !ok {
+		return false
+	}
+	delete(t.rules, endpoint)
+	return true
+}
+
+// all returns the rules sorted by endpoint.
+func (t *faultTable) all() []FaultStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]FaultStatus, 0, len(t.rules))
+	for _, e := range t.rules {
+		out = append(out, FaultStatus{FaultRule: e.rule, Injected: e.counts})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
+
+// entryLocked returns the rule of a path, falling back to FaultAnyEndpoint.
+func (t *faultTable) entryLocked(path string) *faultEntry {
+	if e, ok := t.rules[path]; ok {
+		return e
+	}
+	return t.rules[FaultAnyEndpoint]
+}
+
+// faultPlan is what happens to one request.
+type faultPlan struct {
+	delay       time.Duration
+	errorStatus int // 0: no error
+	drop        bool
+}
+
+// plan draws the faults of a request and counts them.
+func (t *faultTable) plan(path string) faultPlan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var p faultPlan
+	e := t.entryLocked(path)
+	if e == nil {
+		return p
+	}
+	if e.rule.DelayProbability > 0 && rand.Float64() < e.rule.DelayProbability {
+		ms := e.rule.DelayMs
+		if e.rule.DelayJitterMs > 0 {
+			ms += rand.Intn(e.rule.DelayJitterMs + 1)
+		}
+		p.delay = time.Duration(ms) * time.Millisecond
+		e.counts.Delayed++
+	}
+	switch {
+	case e.rule.DropProbability > 0 && rand.Float64() < e.rule.DropProbability:
+		p.drop = true
+		e.counts.Dropped++
+	case e.rule.ErrorProbability > 0 && rand.Float64() < e.rule.ErrorProbability:
+		p.errorStatus = e.rule.ErrorStatus
+		e.counts.Errors++
+	}
+	return p
+}
+
+// duplicateRound draws whether a play reuses the previous bet ID.
+func (t *faultTable) duplicateRound(path string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entryLocked(path)
+	if e == nil || e.rule.DuplicateRoundProbability == 0 || rand.Float64() >= e.rule.DuplicateRoundProbability {
+		return false
+	}
+	e.counts.Duplicated++
+	return true
+}
+
+// Faulty wraps a wallet or bet endpoint with the configured fault rules:
+// delays, error responses and dropped requests.
+func (h *Handlers) Faulty(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plan := h.faults.plan(r.URL.Path)
+		if plan.delay > 0 {
+			select {
+			case <-time.After(plan.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		switch {
+		case plan.drop:
+			fmt.Printf("[LGS] Fault: dropped %s %s\n", r.Method, r.URL.Path)
+			// Closes the connection without a response
+			panic(http.ErrAbortHandler)
+		case plan.errorStatus != 0:
+			fmt.Printf("[LGS] Fault: %d on %s %s\n", plan.errorStatus, r.Method, r.URL.Path)
+			h.sendError(w, "injected fault", plan.errorStatus)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// FaultsRequest for POST /lgs/faults
+type FaultsRequest struct {
+	Rules []FaultRule `json:"rules"`
+	// Replace drops the rules not listed instead of keeping them
+	Replace bool `json:"replace"`
+}
+
+// FaultsResponse for /lgs/faults
+type FaultsResponse struct {
+	Rules   []FaultStatus `json:"rules"`
+	Message string        `json:"message,omitempty"`
+}
+
+// GetFaults handles GET /lgs/faults - the fault rules and their counts
+func (h *Handlers) GetFaults(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, FaultsResponse{Rules: h.faults.all()}, http.StatusOK)
+}
+
+// SetFaults handles POST /lgs/faults - adds or replaces fault rules
+func (h *Handlers) SetFaults(w http.ResponseWriter, r *http.Request) {
+	var req FaultsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Rules) == 0 {
+		h.sendError(w, "rules are required", http.StatusBadRequest)
+		return
+	}
+	for i := range req.Rules {
+		if err := req.Rules[i].Validate(); err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	h.faults.set(req.Rules, req.Replace)
+
+	fmt.Printf("[LGS] Faults: %d rules configured, replace=%v\n", len(req.Rules), req.Replace)
+
+	h.sendJSON(w, FaultsResponse{
+		Rules:   h.faults.all(),
+		Message: fmt.Sprintf("%d fault rules configured", len(req.Rules)),
+	}, http.StatusOK)
+}
+
+// ClearFaults handles DELETE /lgs/faults - removes the rule of an endpoint,
+// or all rules. Query params: endpoint (optional)
+func (h *Handlers) ClearFaults(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.Query().Get("endpoint")
+	if !h.faults.clear(endpoint) {
+		h.sendError(w, fmt.Sprintf("no fault rule for endpoint %s", endpoint), http.StatusNotFound)
+		return
+	}
+
+	fmt.Printf("[LGS] Clear Faults: endpoint=%q\n", endpoint)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"message": "fault rules cleared",
+	}, http.StatusOK)
+}
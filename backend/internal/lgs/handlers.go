@@ -3,7 +3,10 @@ package lgs
 import (
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"lutexplorer/internal/common"
@@ -14,15 +17,18 @@ import (
 
 // batchPlayStats holds statistics for batch play operations.
 type batchPlayStats struct {
+	spins        int
 	totalWagered int64
 	totalWon     int64
 	hitCount     int
 	bigWins      int
 	megaWins     int
 	maxWin       float64
+	jackpotWins  []JackpotWin
 }
 
-// processBatchSpins executes multiple spins and returns statistics.
+// processBatchSpins executes multiple spins and returns statistics. It stops
+// early when the balance can no longer cover a spin.
 func processBatchSpins(
 	session *SessionData,
 	sampleOutcome func() stakergs.Outcome,
@@ -30,6 +36,8 @@ func processBatchSpins(
 	betPerSpin int64,
 	baseAmount int64,
 	keepRounds bool,
+	jackpot jackpotHook, // optional, feeds and pays the jackpot pools
+	onSpin func(outcome stakergs.Outcome, payout int64), // optional, after each spin with the book payout
 ) (batchPlayStats, []BatchPlayRound) {
 	var stats batchPlayStats
 	var rounds []BatchPlayRound
@@ -38,6 +46,11 @@ func processBatchSpins(
 	}
 
 	for i := 0; i < spins; i++ {
+		if session.Balance < betPerSpin {
+			break
+		}
+		stats.spins++
+
 		// Deduct bet
 		session.Balance -= betPerSpin
 		stats.totalWagered += betPerSpin
@@ -45,7 +58,13 @@ func processBatchSpins(
 		// Sample outcome
 		outcome := sampleOutcome()
 		payoutMultiplier := float64(outcome.Payout) / 100.0
-		payout := int64(float64(baseAmount) * payoutMultiplier)
+		bookPayout := int64(float64(baseAmount) * payoutMultiplier)
+		payout := bookPayout
+		if jackpot != nil {
+			wins, jackpotPayout := jackpot(outcome, payoutMultiplier)
+			stats.jackpotWins = append(stats.jackpotWins, wins...)
+			payout += jackpotPayout
+		}
 
 		// Add payout
 		session.Balance += payout
@@ -82,6 +101,10 @@ func processBatchSpins(
 		if payout > 0 {
 			session.TotalWins++
 		}
+
+		if onSpin != nil {
+			onSpin(outcome, bookPayout)
+		}
 	}
 
 	return stats, rounds
@@ -115,6 +138,21 @@ type Handlers struct {
 	loader   *lut.Loader
 	sessions *SessionManager
 	wsHub    *ws.Hub
+	presets  []BiasPreset
+	series   *StatsSeries
+
+	batchesMu sync.Mutex
+	batches   map[string]*batchJob // streamed batch plays by batch ID
+
+	transforms  transformStore  // per-library event transforms
+	tape        tapeDeck        // record/replay of wallet and bet traffic
+	recorder    sessionRecorder // per-session play recordings
+	betLevels   betLevelTable   // per-mode bet configuration
+	currencies  currencyRegistry
+	jackpots    jackpotPools
+	faults      faultTable
+	idempotency idempotencyCache // responses by X-Request-ID
+	sampling    *SamplingCounter
 }
 
 // NewHandlers creates new LGS handlers
@@ -123,9 +161,23 @@ func NewHandlers(loader *lut.Loader, sessions *SessionManager, hub *ws.Hub) *Han
 		loader:   loader,
 		sessions: sessions,
 		wsHub:    hub,
+		presets:  DefaultBiasPresets,
+		series:   NewStatsSeries(DefaultSeriesInterval, DefaultSeriesCapacity),
+		batches:  make(map[string]*batchJob),
+		sampling: NewSamplingCounter(),
 	}
 }
 
+// Sampling returns the per-simID draw counts of the LGS sampler
+func (h *Handlers) Sampling() *SamplingCounter {
+	return h.sampling
+}
+
+// SetPresets replaces the bias presets offered by /lgs/presets
+func (h *Handlers) SetPresets(presets []BiasPreset) {
+	h.presets = presets
+}
+
 // broadcastSessionsUpdate sends current sessions state to all WebSocket clients
 func (h *Handlers) broadcastSessionsUpdate() {
 	if h.wsHub == nil {
@@ -136,8 +188,10 @@ func (h *Handlers) broadcastSessionsUpdate() {
 	summaries := make([]SessionSummary, 0, len(allSessions))
 
 	var aggBets, aggWins, aggWagered, aggWon int64
+	var base baseTotals
 
 	for _, s := range allSessions {
+		s.mu.Lock()
 		rtp := 0.0
 		if s.TotalWagered > 0 {
 			rtp = float64(s.TotalWon) / float64(s.TotalWagered)
@@ -164,12 +218,16 @@ func (h *Handlers) broadcastSessionsUpdate() {
 			LastActivity:   s.LastActivity.Format("2006-01-02 15:04:05"),
 			ForcedOutcomes: s.GetAllForcedSimIDs(),
 			RTPBias:        s.RTPBias,
+			Variants:       s.GetVariants(),
+			Convergence:    h.convergence(s, 0, 0),
 		})
 
 		aggBets += s.TotalBets
 		aggWins += s.TotalWins
 		aggWagered += s.TotalWagered
 		aggWon += s.TotalWon
+		base.add(h, s)
+		s.mu.Unlock()
 	}
 
 	overallRTP := 0.0
@@ -180,26 +238,40 @@ func (h *Handlers) broadcastSessionsUpdate() {
 	if aggBets > 0 {
 		overallHitRate = float64(aggWins) / float64(aggBets)
 	}
+	agg := AggregateStats{
+		TotalBets:      aggBets,
+		TotalWins:      aggWins,
+		TotalWagered:   aggWagered,
+		TotalWon:       aggWon,
+		OverallRTP:     overallRTP,
+		OverallHitRate: overallHitRate,
+		TotalProfit:    aggWagered - aggWon,
+	}
+	base.apply(&agg)
 
 	h.wsHub.Broadcast(ws.Message{
 		Type: ws.MsgLGSSessionsUpdate,
 		Payload: SessionsResponse{
-			Sessions:      summaries,
-			TotalSessions: len(allSessions),
-			TotalCreated:  h.sessions.TotalCreated(),
-			AggregateStats: AggregateStats{
-				TotalBets:      aggBets,
-				TotalWins:      aggWins,
-				TotalWagered:   aggWagered,
-				TotalWon:       aggWon,
-				OverallRTP:     overallRTP,
-				OverallHitRate: overallHitRate,
-				TotalProfit:    aggWagered - aggWon,
-			},
+			Sessions:       summaries,
+			TotalSessions:  len(allSessions),
+			TotalCreated:   h.sessions.TotalCreated(),
+			AggregateStats: agg,
+			Jackpots:       h.jackpots.values(),
 		},
 	})
 }
 
+// claimSession marks a request that changes the session. It fails with 409
+// while a streamed batch play runs on the session; otherwise the caller must
+// release the claim with session.busy.RUnlock.
+func (h *Handlers) claimSession(w http.ResponseWriter, session *SessionData) bool {
+	if session.busy.TryRLock() {
+		return true
+	}
+	h.sendError(w, fmt.Sprintf("batch play running for session %s", session.SessionID), http.StatusConflict)
+	return false
+}
+
 // sendJSON sends a JSON response
 func (h *Handlers) sendJSON(w http.ResponseWriter, data interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -244,21 +316,28 @@ func (h *Handlers) Authenticate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 	session.Language = req.Language
 
+	// A multi-stage round left open is returned so the client can resume it
+	var round interface{}
+	if open := session.OpenRound(); open != nil {
+		round = open
+	}
+
 	fmt.Printf("[LGS] Authenticate: session=%s, balance=%d\n", req.SessionID, session.Balance)
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
 
 	h.sendJSON(w, AuthResponse{
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Round:  nil,
-		Config: DefaultConfigInfo(),
-		Meta:   nil,
+		Balance: h.balanceInfo(r, session),
+		Round:   round,
+		Config:  h.configInfo(),
+		Meta:    nil,
 	}, http.StatusOK)
 }
 
@@ -278,20 +357,49 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if req.Currency == "" {
-		req.Currency = "USD"
+		req.Currency = BaseCurrency
 	}
+	currency, err := h.currency(req.Currency)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Configured modes round and check the bet, or supply the default level
+	amount, err := h.betLevels.resolve(req.Mode, req.Amount, currency)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Amount = amount
 	if req.Amount == 0 {
 		req.Amount = APIMultiplier
 	}
 
-	// Get session
+	// Pick the event transform before touching the balance
+	transform, err := h.eventTransform(r, "")
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Get session; a new play ends a multi-stage round left open
 	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Currency = req.Currency
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	if credited := session.SettleRound(); credited > 0 {
+		fmt.Printf("[LGS] Settled open round: session=%s, payout=%d\n", req.SessionID, credited)
+	}
+	if err := h.switchCurrency(session, currency); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Get LUT for mode
-	table, err := h.loader.GetMode(req.Mode)
+	// Get LUT for mode, or the variant the session plays
+	table, err := h.tableFor(session, req.Mode)
 	if err != nil {
-		h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusBadRequest)
+		h.sendError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -304,7 +412,8 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 
 	// Check balance
 	if session.Balance < totalBet {
-		h.sendError(w, "insufficient balance", http.StatusBadRequest)
+		h.sendError(w, fmt.Sprintf("insufficient balance: need %s, have %s",
+			h.formatBalance(r, totalBet, session.Currency), h.formatBalance(r, session.Balance, session.Currency)), http.StatusBadRequest)
 		return
 	}
 
@@ -313,7 +422,7 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 
 	// Check for forced outcome first
 	var outcome stakergs.Outcome
-	var forced bool
+	var forced, scripted bool
 	if forcedSimID, ok := session.ConsumeForcedSimID(req.Mode); ok {
 		// Find the outcome with this simID
 		for _, o := range table.Outcomes {
@@ -329,41 +438,60 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 			session.Balance += totalBet
 			return
 		}
+	} else if step, ok := session.PeekScenarioStep(req.Mode); ok {
+		// Scripted step; resolved against the table played, so variants apply
+		outcome, err = pickScenarioOutcome(table, step)
+		if err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest)
+			session.Balance += totalBet
+			return
+		}
+		session.AdvanceScenario()
+		forced, scripted = true, true
 	} else {
 		// Use weighted random selection, with bias if set
 		if session.RTPBias != 0 {
 			sampler := lut.NewBiasedWeightedSampler(table, session.RTPBias)
 			outcome = sampler.SampleWithNewRNG()
 		} else {
-			sampler := lut.NewWeightedSampler(table)
-			outcome = sampler.SampleWithNewRNG()
+			outcome = h.loader.Sampler(table).SampleWithSharedRNG()
 		}
 	}
 
+	// Only unbiased draws from the table on disk are checked against its weights
+	if forced || session.RTPBias != 0 || session.Variant(req.Mode) != "" {
+		h.sampling.Skip(req.Mode, 1)
+	} else {
+		h.sampling.Record(req.Mode, outcome.SimID)
+	}
+
 	// Calculate payout
 	payoutMultiplier := float64(outcome.Payout) / 100.0
-	payout := int64(float64(req.Amount) * payoutMultiplier)
-
-	// Add payout to balance
-	session.Balance += payout
-
-	// Get event data (state) using lazy loading - only loads what's needed
-	var stateData json.RawMessage
-	eventsLoader := h.loader.EventsLoader()
-	modeConfig, _ := h.loader.GetModeConfig(req.Mode)
-	if modeConfig != nil && modeConfig.Events != "" {
-		// Use lazy loading - only loads a small chunk around the requested event
-		if bookJSON, err := eventsLoader.GetEventLazy(req.Mode, modeConfig.Events, outcome.SimID, table.SimIDOffset); err == nil {
-			stateData = extractEvents(bookJSON)
+	bookPayout := int64(float64(req.Amount) * payoutMultiplier)
+
+	// Contribute to the jackpots; hits are paid with the round
+	jackpotWins, jackpotPayout := h.awardJackpots(session, currency, req.Mode, outcome.SimID, payoutMultiplier, totalBet)
+	payout := bookPayout + jackpotPayout
+
+	// Get event data (state) using lazy loading - only loads what's needed.
+	// Modes without events get a synthetic book when that is enabled.
+	stateData := json.RawMessage(`[]`)
+	var progress *RoundProgress
+	if bookJSON, _, err := h.loader.GetBook(req.Mode, table, outcome); err == nil {
+		progress = newRoundProgress(extractEvents(bookJSON))
+		if state, err := transform.Apply(bookJSON); err == nil {
+			stateData = state
 		} else {
-			stateData = json.RawMessage(`[]`)
+			fmt.Printf("[LGS] Play: transform %q failed for simID=%d: %v\n", transform.Name, outcome.SimID, err)
 		}
-	} else {
-		stateData = json.RawMessage(`[]`)
 	}
 
 	// Create round info
 	betID := session.NextBetID(outcome.SimID)
+	if session.LastRound != nil && h.faults.duplicateRound(r.URL.Path) {
+		fmt.Printf("[LGS] Fault: duplicated bet ID %d on %s\n", session.LastRound.BetID, r.URL.Path)
+		betID = session.LastRound.BetID
+	}
 	roundInfo := RoundInfo{
 		BetID:            betID,
 		Amount:           totalBet,
@@ -373,14 +501,31 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 		State:            stateData,
 		Mode:             req.Mode,
 		Event:            nil,
+		Progress:         progress,
+	}
+
+	// Rounds with a feature are paid when they end, after their stages
+	if progress == nil {
+		session.Balance += payout
 	}
 
 	// Add to history
 	session.AddRound(roundInfo)
+	h.recordPlay(session, table, req.Mode, outcome, req.Amount, totalBet, bookPayout, forced, false)
+	if !forced && session.RTPBias == 0 {
+		session.trackReturn(req.Mode, totalBet, bookPayout)
+	}
 	h.sessions.Update(session)
+	var win int64
+	if payout > 0 {
+		win = 1
+	}
+	h.series.Record(time.Now(), 1, win, totalBet, payout)
 
 	tag := ""
-	if forced {
+	if scripted {
+		tag = " [SCENARIO]"
+	} else if forced {
 		tag = " [FORCED]"
 	} else if session.RTPBias != 0 {
 		tag = fmt.Sprintf(" [BIAS=%.2f]", session.RTPBias)
@@ -390,13 +535,13 @@ func (h *Handlers) Play(w http.ResponseWriter, r *http.Request) {
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
+	h.broadcastJackpotWins(jackpotWins)
 
 	h.sendJSON(w, PlayResponse{
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Round: roundInfo,
+		Balance:     h.balanceInfo(r, session),
+		Round:       roundInfo,
+		Jackpots:    h.jackpots.values(),
+		JackpotWins: jackpotWins,
 	}, http.StatusOK)
 }
 
@@ -412,22 +557,28 @@ func (h *Handlers) EndRound(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 
-	// Mark round as inactive
+	// Pay out a multi-stage round, then mark the round as inactive
+	credited := session.SettleRound()
 	if session.LastRound != nil {
 		session.LastRound.Active = false
 	}
+	h.sessions.Update(session)
 
-	fmt.Printf("[LGS] End Round: session=%s, balance=%d\n", req.SessionID, session.Balance)
+	fmt.Printf("[LGS] End Round: session=%s, balance=%d, credited=%d\n", req.SessionID, session.Balance, credited)
+	if credited > 0 {
+		h.broadcastSessionsUpdate()
+	}
 
 	h.sendJSON(w, EndRoundResponse{
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Round:  nil,
-		Config: DefaultConfigInfo(),
-		Meta:   nil,
+		Balance: h.balanceInfo(r, session),
+		Round:   nil,
+		Config:  h.configInfo(),
+		Meta:    nil,
 	}, http.StatusOK)
 }
 
@@ -449,21 +600,22 @@ func (h *Handlers) History(w http.ResponseWriter, r *http.Request) {
 	session := h.sessions.GetOrCreate(req.SessionID)
 
 	// Get last N rounds
+	session.mu.Lock()
 	rounds := session.History
 	if len(rounds) > req.Limit {
 		rounds = rounds[len(rounds)-req.Limit:]
 	}
+	balance := h.balanceInfo(r, session)
+	session.mu.Unlock()
 
 	h.sendJSON(w, HistoryResponse{
-		Rounds: rounds,
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
+		Rounds:  rounds,
+		Balance: balance,
 	}, http.StatusOK)
 }
 
-// Stats handles /lgs/stats - returns session statistics
+// Stats handles /lgs/stats - returns session statistics and the RTP
+// convergence per mode. Query params: sessionID, confidence, tolerance
 func (h *Handlers) Stats(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.URL.Query().Get("sessionID")
 	if sessionID == "" {
@@ -476,13 +628,36 @@ func (h *Handlers) Stats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	confidence, tolerance := convergenceParams(r)
+	session.mu.Lock()
 	stats := session.GetStats()
 	stats["balance"] = session.Balance
 	stats["currency"] = session.Currency
+	stats["convergence"] = h.convergence(session, confidence, tolerance)
+	session.mu.Unlock()
 
 	h.sendJSON(w, stats, http.StatusOK)
 }
 
+// StatsTimeseries handles GET /lgs/stats/timeseries - returns bets, wins and
+// RTP across all sessions per interval, for points at or after ?since (unix ms)
+func (h *Handlers) StatsTimeseries(w http.ResponseWriter, r *http.Request) {
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			h.sendError(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	h.sendJSON(w, map[string]interface{}{
+		"intervalMs": h.series.Interval().Milliseconds(),
+		"points":     h.series.Since(since),
+	}, http.StatusOK)
+}
+
 // ResetBalance handles reset-balance
 func (h *Handlers) ResetBalance(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -496,10 +671,13 @@ func (h *Handlers) ResetBalance(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 
-	session := h.sessions.ResetBalance(req.SessionID)
-	if session == nil {
-		session = h.sessions.GetOrCreate(req.SessionID)
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
 	}
+	defer session.busy.RUnlock()
+	session.Balance = DefaultBalance
+	h.sessions.Update(session)
 
 	fmt.Printf("[LGS] Reset Balance: session=%s, balance=%d\n", req.SessionID, session.Balance)
 
@@ -508,10 +686,7 @@ func (h *Handlers) ResetBalance(w http.ResponseWriter, r *http.Request) {
 
 	h.sendJSON(w, map[string]interface{}{
 		"success": true,
-		"balance": BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
+		"balance": h.balanceInfo(r, session),
 	}, http.StatusOK)
 }
 
@@ -537,10 +712,23 @@ func (h *Handlers) SetBalance(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Balance = req.Balance
-	if req.Currency != "" {
-		session.Currency = req.Currency
+	if !h.claimSession(w, session) {
+		return
 	}
+	defer session.busy.RUnlock()
+	if req.Currency == "" {
+		req.Currency = session.Currency
+	}
+	currency, err := h.currency(req.Currency)
+	if err == nil {
+		err = currency.checkAmount(req.Balance, "balance")
+	}
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	session.Balance = req.Balance
+	session.Currency = currency.Code
 	h.sessions.Update(session)
 
 	fmt.Printf("[LGS] Set Balance: session=%s, balance=%d, currency=%s\n", req.SessionID, session.Balance, session.Currency)
@@ -550,10 +738,7 @@ func (h *Handlers) SetBalance(w http.ResponseWriter, r *http.Request) {
 
 	h.sendJSON(w, map[string]interface{}{
 		"success": true,
-		"balance": BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
+		"balance": h.balanceInfo(r, session),
 	}, http.StatusOK)
 }
 
@@ -569,13 +754,39 @@ func (h *Handlers) Event(w http.ResponseWriter, r *http.Request) {
 		req.SessionID = "default-session"
 	}
 
-	// For simple slot games, event just acknowledges the event was processed
-	// In more complex games, this would advance game state
-	fmt.Printf("[LGS] Event: session=%s, event=%s\n", req.SessionID, req.Event)
+	// Multi-stage rounds advance to the event; for simple slot games the
+	// event is just acknowledged
+	var progress *RoundProgress
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	if session.OpenRound() != nil {
+		index, err := strconv.Atoi(req.Event)
+		if err == nil {
+			progress, err = session.AdvanceRound(index)
+		} else {
+			err = fmt.Errorf("event must be an event index, got %q", req.Event)
+		}
+		if err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.sessions.Update(session)
+	}
+
+	if progress != nil {
+		fmt.Printf("[LGS] Event: session=%s, event=%s, phase=%s, featureSpins=%d/%d\n",
+			req.SessionID, req.Event, progress.Phase, progress.FeatureSpinsPlayed, progress.FeatureSpins)
+	} else {
+		fmt.Printf("[LGS] Event: session=%s, event=%s\n", req.SessionID, req.Event)
+	}
 
 	// Return simple event response
 	h.sendJSON(w, EventResponse{
 		Event: req.Event,
+		Round: progress,
 	}, http.StatusOK)
 }
 
@@ -597,8 +808,20 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if req.Currency == "" {
-		req.Currency = "USD"
+		req.Currency = BaseCurrency
+	}
+	currency, err := h.currency(req.Currency)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Configured modes round and check the bet, or supply the default level
+	amount, err := h.betLevels.resolve(req.Mode, req.Amount, currency)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	req.Amount = amount
 	if req.Amount == 0 {
 		req.Amount = APIMultiplier
 	}
@@ -609,14 +832,24 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		req.Spins = 100000
 	}
 
-	// Get session
+	// Get session; a new play ends a multi-stage round left open
 	session := h.sessions.GetOrCreate(req.SessionID)
-	session.Currency = req.Currency
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	if credited := session.SettleRound(); credited > 0 {
+		fmt.Printf("[LGS] Settled open round: session=%s, payout=%d\n", req.SessionID, credited)
+	}
+	if err := h.switchCurrency(session, currency); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Get LUT for mode
-	table, err := h.loader.GetMode(req.Mode)
+	// Get LUT for mode, or the variant the session plays
+	table, err := h.tableFor(session, req.Mode)
 	if err != nil {
-		h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusBadRequest)
+		h.sendError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -630,7 +863,8 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 
 	// Check balance
 	if session.Balance < totalBetRequired {
-		h.sendError(w, fmt.Sprintf("insufficient balance: need %d, have %d", totalBetRequired, session.Balance), http.StatusBadRequest)
+		h.sendError(w, fmt.Sprintf("insufficient balance: need %s, have %s",
+			h.formatBalance(r, totalBetRequired, session.Currency), h.formatBalance(r, session.Balance, session.Currency)), http.StatusBadRequest)
 		return
 	}
 
@@ -640,15 +874,32 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		biasedSampler := lut.NewBiasedWeightedSampler(table, session.RTPBias)
 		sampleOutcome = biasedSampler.SampleWithNewRNG
 	} else {
-		regularSampler := lut.NewWeightedSampler(table)
-		sampleOutcome = regularSampler.SampleWithNewRNG
+		sampleOutcome = h.loader.Sampler(table).SampleWithSharedRNG
+	}
+
+	// Only unbiased draws from the table on disk are checked against its weights
+	if session.RTPBias == 0 && session.Variant(req.Mode) == "" {
+		sampleOutcome = h.sampling.Counted(req.Mode, sampleOutcome)
+	} else {
+		sampleOutcome = h.sampling.Uncounted(req.Mode, sampleOutcome)
+	}
+
+	onSpin := h.batchRecorder(session, table, req.Mode, req.Amount, betPerSpin)
+	if session.RTPBias == 0 {
+		onSpin = session.returnTracker(req.Mode, betPerSpin, onSpin)
+	}
+	jackpot := h.batchJackpots(session, currency, req.Mode, betPerSpin)
+	if req.Stream {
+		h.streamBatchPlay(w, session, req, sampleOutcome, betPerSpin, jackpot, onSpin)
+		return
 	}
 
 	// Play all spins
 	keepRounds := req.Spins <= 1000
-	stats, rounds := processBatchSpins(session, sampleOutcome, req.Spins, betPerSpin, req.Amount, keepRounds)
+	stats, rounds := processBatchSpins(session, sampleOutcome, req.Spins, betPerSpin, req.Amount, keepRounds, jackpot, onSpin)
 
 	h.sessions.Update(session)
+	h.series.Record(time.Now(), int64(stats.spins), int64(stats.hitCount), stats.totalWagered, stats.totalWon)
 
 	// Calculate rates
 	rtp := 0.0
@@ -656,8 +907,8 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		rtp = float64(stats.totalWon) / float64(stats.totalWagered)
 	}
 	hitRate := 0.0
-	if req.Spins > 0 {
-		hitRate = float64(stats.hitCount) / float64(req.Spins)
+	if stats.spins > 0 {
+		hitRate = float64(stats.hitCount) / float64(stats.spins)
 	}
 
 	durationMs := time.Since(start).Milliseconds()
@@ -667,15 +918,16 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		biasTag = fmt.Sprintf(" [BIAS=%.2f]", session.RTPBias)
 	}
 	fmt.Printf("[LGS] BatchPlay: session=%s, mode=%s, spins=%d, rtp=%.4f, duration=%dms%s\n",
-		req.SessionID, req.Mode, req.Spins, rtp, durationMs, biasTag)
+		req.SessionID, req.Mode, stats.spins, rtp, durationMs, biasTag)
 
 	// Broadcast session update
 	h.broadcastSessionsUpdate()
+	h.broadcastJackpotWins(stats.jackpotWins)
 
 	h.sendJSON(w, BatchPlayResponse{
 		SessionID:    req.SessionID,
 		Mode:         req.Mode,
-		Spins:        req.Spins,
+		Spins:        stats.spins,
 		TotalWagered: stats.totalWagered,
 		TotalWon:     stats.totalWon,
 		HitCount:     stats.hitCount,
@@ -684,12 +936,11 @@ func (h *Handlers) BatchPlay(w http.ResponseWriter, r *http.Request) {
 		MaxWin:       stats.maxWin,
 		BigWins:      stats.bigWins,
 		MegaWins:     stats.megaWins,
-		Balance: BalanceInfo{
-			Amount:   session.Balance,
-			Currency: session.Currency,
-		},
-		Rounds:     rounds,
-		DurationMs: durationMs,
+		Balance:      h.balanceInfo(r, session),
+		Rounds:       rounds,
+		DurationMs:   durationMs,
+		Jackpots:     h.jackpots.values(),
+		JackpotWins:  stats.jackpotWins,
 	}, http.StatusOK)
 }
 
@@ -701,8 +952,10 @@ func (h *Handlers) Sessions(w http.ResponseWriter, r *http.Request) {
 
 	// Aggregate stats
 	var aggBets, aggWins, aggWagered, aggWon int64
+	var base baseTotals
 
 	for _, s := range allSessions {
+		s.mu.Lock()
 		rtp := 0.0
 		if s.TotalWagered > 0 {
 			rtp = float64(s.TotalWon) / float64(s.TotalWagered)
@@ -729,6 +982,8 @@ func (h *Handlers) Sessions(w http.ResponseWriter, r *http.Request) {
 			LastActivity:   s.LastActivity.Format("2006-01-02 15:04:05"),
 			ForcedOutcomes: s.GetAllForcedSimIDs(),
 			RTPBias:        s.RTPBias,
+			Variants:       s.GetVariants(),
+			Convergence:    h.convergence(s, 0, 0),
 		})
 
 		// Accumulate for aggregate
@@ -736,6 +991,8 @@ func (h *Handlers) Sessions(w http.ResponseWriter, r *http.Request) {
 		aggWins += s.TotalWins
 		aggWagered += s.TotalWagered
 		aggWon += s.TotalWon
+		base.add(h, s)
+		s.mu.Unlock()
 	}
 
 	// Calculate aggregate stats
@@ -747,23 +1004,26 @@ func (h *Handlers) Sessions(w http.ResponseWriter, r *http.Request) {
 	if aggBets > 0 {
 		overallHitRate = float64(aggWins) / float64(aggBets)
 	}
+	agg := AggregateStats{
+		TotalBets:      aggBets,
+		TotalWins:      aggWins,
+		TotalWagered:   aggWagered,
+		TotalWon:       aggWon,
+		OverallRTP:     overallRTP,
+		OverallHitRate: overallHitRate,
+		TotalProfit:    aggWagered - aggWon,
+	}
+	base.apply(&agg)
 
 	fmt.Printf("[LGS] Sessions: count=%d, totalBets=%d, overallRTP=%.4f\n",
-		len(allSessions), aggBets, overallRTP)
+		len(allSessions), aggBets, agg.OverallRTP)
 
 	h.sendJSON(w, SessionsResponse{
-		Sessions:      summaries,
-		TotalSessions: len(allSessions),
-		TotalCreated:  h.sessions.TotalCreated(),
-		AggregateStats: AggregateStats{
-			TotalBets:      aggBets,
-			TotalWins:      aggWins,
-			TotalWagered:   aggWagered,
-			TotalWon:       aggWon,
-			OverallRTP:     overallRTP,
-			OverallHitRate: overallHitRate,
-			TotalProfit:    aggWagered - aggWon,
-		},
+		Sessions:       summaries,
+		TotalSessions:  len(allSessions),
+		TotalCreated:   h.sessions.TotalCreated(),
+		AggregateStats: agg,
+		Jackpots:       h.jackpots.values(),
 	}, http.StatusOK)
 }
 
@@ -779,6 +1039,10 @@ func (h *Handlers) ClearHistory(w http.ResponseWriter, r *http.Request) {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 
 	session.ClearHistory()
 	h.sessions.Update(session)
@@ -803,6 +1067,10 @@ func (h *Handlers) ClearStats(w http.ResponseWriter, r *http.Request) {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 
 	session.ClearStats()
 	h.sessions.Update(session)
@@ -860,6 +1128,10 @@ func (h *Handlers) ForceOutcome(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 	session.SetForcedSimID(req.Mode, req.SimID)
 	h.sessions.Update(session)
 
@@ -875,6 +1147,112 @@ func (h *Handlers) ForceOutcome(w http.ResponseWriter, r *http.Request) {
 	}, http.StatusOK)
 }
 
+// ForceOutcomeByCriteria handles POST /lgs/force-outcome/by-criteria - forces
+// the next spin to a book whose events match the given predicates
+func (h *Handlers) ForceOutcomeByCriteria(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionID"`
+		Mode      string `json:"mode"`
+		// Pick chooses among matches: "random" (uniform, default), "weighted" (by LUT weight) or "first"
+		Pick string `json:"pick"`
+		lut.EventQuery
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+	if req.Mode == "" {
+		h.sendError(w, "mode is required", http.StatusBadRequest)
+		return
+	}
+	switch req.Pick {
+	case "":
+		req.Pick = "random"
+	case "random", "weighted", "first":
+	default:
+		h.sendError(w, fmt.Sprintf("invalid pick %q: want random, weighted or first", req.Pick), http.StatusBadRequest)
+		return
+	}
+
+	table, err := h.loader.GetMode(req.Mode)
+	if err != nil {
+		h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusBadRequest)
+		return
+	}
+	config, err := h.loader.GetModeConfig(req.Mode)
+	if err != nil || config.Events == "" {
+		h.sendError(w, fmt.Sprintf("mode %s has no events file", req.Mode), http.StatusBadRequest)
+		return
+	}
+
+	req.Limit = lut.MaxSearchLimit
+	if err := req.EventQuery.Validate(); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.loader.EventsLoader().SearchEvents(req.Mode, table, config.Events, &req.EventQuery)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(result.Matches) == 0 {
+		h.sendError(w, fmt.Sprintf("no outcome in mode %s matches the criteria", req.Mode), http.StatusNotFound)
+		return
+	}
+
+	match := pickEventMatch(result.Matches, req.Pick)
+
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	session.SetForcedSimID(req.Mode, match.SimID)
+	h.sessions.Update(session)
+
+	fmt.Printf("[LGS] Force Outcome by criteria: session=%s, mode=%s, simID=%d, payout=%.2fx (%d matches)\n",
+		req.SessionID, req.Mode, match.SimID, match.Payout, result.TotalMatches)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success":      true,
+		"message":      fmt.Sprintf("next spin in %s will use simID %d (%.2fx)", req.Mode, match.SimID, match.Payout),
+		"mode":         req.Mode,
+		"simID":        match.SimID,
+		"payout":       match.Payout,
+		"totalMatches": result.TotalMatches,
+		"probability":  result.Probability,
+	}, http.StatusOK)
+}
+
+// pickEventMatch selects one search match using the given pick strategy.
+func pickEventMatch(matches []lut.EventMatch, pick string) lut.EventMatch {
+	switch pick {
+	case "first":
+		return matches[0]
+	case "weighted":
+		var total float64
+		for _, m := range matches {
+			total += float64(m.Weight)
+		}
+		if total > 0 {
+			target := rand.Float64() * total
+			for _, m := range matches {
+				target -= float64(m.Weight)
+				if target < 0 {
+					return m
+				}
+			}
+			return matches[len(matches)-1]
+		}
+	}
+	return matches[rand.Intn(len(matches))]
+}
+
 // ClearForcedOutcome handles DELETE /lgs/force-outcome - clears forced outcome for a session/mode
 func (h *Handlers) ClearForcedOutcome(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.URL.Query().Get("sessionID")
@@ -889,12 +1267,16 @@ func (h *Handlers) ClearForcedOutcome(w http.ResponseWriter, r *http.Request) {
 		h.sendError(w, "session not found", http.StatusNotFound)
 		return
 	}
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 
 	if mode != "" {
 		session.ClearForcedSimID(mode)
 	} else {
 		// Clear all forced outcomes
-		session.ForcedSimID = nil
+		session.ClearAllForced()
 	}
 	h.sessions.Update(session)
 
@@ -952,6 +1334,10 @@ func (h *Handlers) SetRTPBias(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
 	session.RTPBias = req.Bias
 	h.sessions.Update(session)
 
@@ -990,6 +1376,183 @@ func (h *Handlers) GetRTPBias(w http.ResponseWriter, r *http.Request) {
 	}, http.StatusOK)
 }
 
+// tableFor returns the table a session plays in a mode: its selected variant
+// if any, otherwise the table on disk
+func (h *Handlers) tableFor(session *SessionData, mode string) (*stakergs.LookupTable, error) {
+	if name := session.Variant(mode); name != "" {
+		table, err := h.loader.GetVariant(mode, name)
+		if err != nil {
+			return nil, fmt.Errorf("session %s plays a missing variant: %w", session.SessionID, err)
+		}
+		return table, nil
+	}
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		return nil, fmt.Errorf("mode not found: %s", mode)
+	}
+	return table, nil
+}
+
+// SetVariant handles POST /lgs/variant - selects the in-memory table variant
+// a session plays in a mode ("" = the table on disk)
+func (h *Handlers) SetVariant(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionID"`
+		Mode      string `json:"mode"`
+		Variant   string `json:"variant"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+	if req.Mode == "" {
+		h.sendError(w, "mode is required", http.StatusBadRequest)
+		return
+	}
+	if req.Variant != "" {
+		if _, err := h.loader.GetVariant(req.Mode, req.Variant); err != nil {
+			h.sendError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	} else if _, err := h.loader.GetMode(req.Mode); err != nil {
+		h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusNotFound)
+		return
+	}
+
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	session.SetVariant(req.Mode, req.Variant)
+	h.sessions.Update(session)
+
+	fmt.Printf("[LGS] Set Variant: session=%s, mode=%s, variant=%q\n", req.SessionID, req.Mode, req.Variant)
+
+	h.broadcastSessionsUpdate()
+
+	h.sendJSON(w, map[string]interface{}{
+		"success":   true,
+		"sessionID": req.SessionID,
+		"mode":      req.Mode,
+		"variant":   req.Variant,
+	}, http.StatusOK)
+}
+
+// GetVariants handles GET /lgs/variant - returns the variants a session plays
+func (h *Handlers) GetVariants(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("sessionID")
+	if sessionID == "" {
+		sessionID = "default-session"
+	}
+
+	variants := map[string]string{}
+	if session := h.sessions.Get(sessionID); session != nil {
+		variants = session.GetVariants()
+	}
+
+	h.sendJSON(w, map[string]interface{}{
+		"sessionID": sessionID,
+		"variants":  variants,
+	}, http.StatusOK)
+}
+
+// Presets handles GET /lgs/presets - lists the bias presets
+func (h *Handlers) Presets(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, map[string]interface{}{
+		"presets": h.presets,
+	}, http.StatusOK)
+}
+
+// ApplyPreset handles POST /lgs/presets/apply - sets bias, balance and forced
+// outcomes of a session from a named preset in one call
+func (h *Handlers) ApplyPreset(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionID"`
+		Preset    string `json:"preset"`
+		// Mode receives the preset's forced outcomes; required if the preset forces any
+		Mode string `json:"mode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+	preset, ok := findPreset(h.presets, req.Preset)
+	if !ok {
+		h.sendError(w, fmt.Sprintf("unknown preset %q", req.Preset), http.StatusNotFound)
+		return
+	}
+
+	// Pick forced outcomes before touching the session so a bad preset changes nothing
+	var forced []int
+	if len(preset.Force) > 0 {
+		if req.Mode == "" {
+			h.sendError(w, fmt.Sprintf("mode is required: preset %s forces outcomes", preset.Name), http.StatusBadRequest)
+			return
+		}
+		table, err := h.loader.GetMode(req.Mode)
+		if err != nil {
+			h.sendError(w, fmt.Sprintf("mode not found: %s", req.Mode), http.StatusBadRequest)
+			return
+		}
+		for _, name := range preset.Force {
+			band, err := lut.LookupPayoutBand(name)
+			if err != nil {
+				h.sendError(w, fmt.Sprintf("preset %s: %v", preset.Name, err), http.StatusBadRequest)
+				return
+			}
+			sample := lut.SampleOutcomes(table, band, 1, true, rand.Int63())
+			if len(sample.Outcomes) == 0 {
+				h.sendError(w, fmt.Sprintf("mode %s has no %s outcome for preset %s", req.Mode, band.Name, preset.Name), http.StatusBadRequest)
+				return
+			}
+			forced = append(forced, sample.Outcomes[0].SimID)
+		}
+	}
+
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	session.RTPBias = preset.Bias
+	if preset.Balance != nil {
+		session.Balance = *preset.Balance
+	}
+	if preset.ResetStats {
+		session.ClearHistory()
+		session.ClearStats()
+	}
+	session.ClearAllForced()
+	if len(forced) > 0 {
+		session.SetForcedSequence(req.Mode, forced)
+	}
+	h.sessions.Update(session)
+
+	fmt.Printf("[LGS] Apply Preset: session=%s, preset=%s, bias=%.2f, balance=%d, forced=%v\n",
+		req.SessionID, preset.Name, session.RTPBias, session.Balance, forced)
+
+	h.broadcastSessionsUpdate()
+
+	h.sendJSON(w, map[string]interface{}{
+		"success":      true,
+		"sessionID":    req.SessionID,
+		"preset":       preset.Name,
+		"bias":         session.RTPBias,
+		"balance":      h.balanceInfo(r, session),
+		"forcedSimIDs": forced,
+		"message":      fmt.Sprintf("preset %s applied", preset.Name),
+	}, http.StatusOK)
+}
+
 // Replay handles /bet/replay/{game}/{version}/{mode}/{event} - returns event data for replay
 func (h *Handlers) Replay(w http.ResponseWriter, r *http.Request) {
 	game := r.PathValue("game")
@@ -1016,10 +1579,20 @@ func (h *Handlers) Replay(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	transform, err := h.eventTransform(r, version)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Find payout multiplier for this simID
 	var payoutMultiplier float64
+	outcome := stakergs.Outcome{SimID: simID}
+	found := false
 	for _, o := range table.Outcomes {
 		if o.SimID == simID {
+			outcome = o
+			found = true
 			payoutMultiplier = float64(o.Payout) / 100.0
 			break
 		}
@@ -1031,21 +1604,21 @@ func (h *Handlers) Replay(w http.ResponseWriter, r *http.Request) {
 		costMultiplier = 1.0
 	}
 
-	// Get event data using lazy loading - only loads the needed chunk
-	eventsLoader := h.loader.EventsLoader()
-	modeConfig, err := h.loader.GetModeConfig(mode)
-	if err != nil || modeConfig.Events == "" {
-		h.sendError(w, fmt.Sprintf("no events file configured for mode: %s", mode), http.StatusNotFound)
-		return
+	// Get event data using lazy loading - only loads the needed chunk,
+	// or a synthetic book for modes without events when that is enabled
+	bookJSON, synthetic, err := h.loader.GetBook(mode, table, outcome)
+	if err == nil && synthetic && !found {
+		err = fmt.Errorf("simID %d not in mode %s", simID, mode)
 	}
-
-	// Use lazy loading - only loads a small chunk around the requested event
-	bookJSON, err := eventsLoader.GetEventLazy(mode, modeConfig.Events, simID, table.SimIDOffset)
 	if err != nil {
 		h.sendError(w, fmt.Sprintf("event not found: %v", err), http.StatusNotFound)
 		return
 	}
-	stateData := extractEvents(bookJSON)
+	stateData, err := transform.Apply(bookJSON)
+	if err != nil {
+		h.sendError(w, fmt.Sprintf("transform %q failed: %v", transform.Name, err), http.StatusInternalServerError)
+		return
+	}
 
 	fmt.Printf("[LGS] Replay: game=%s, version=%s, mode=%s, simID=%d, payout=%.2fx\n", game, version, mode, simID, payoutMultiplier)
 
@@ -1055,3 +1628,53 @@ func (h *Handlers) Replay(w http.ResponseWriter, r *http.Request) {
 		State:            stateData,
 	}, http.StatusOK)
 }
+
+// eventTransform selects the library's event transform for a request: the
+// one named by ?transform=, else one matching the client version, else the
+// library default. Returns nil for untransformed events.
+func (h *Handlers) eventTransform(r *http.Request, version string) (*EventTransform, error) {
+	config, err := h.transforms.load(h.loader.BaseDir())
+	if err != nil {
+		return nil, err
+	}
+	return config.Select(r.URL.Query().Get("transform"), version)
+}
+
+// Transforms handles GET /lgs/transforms - the event transforms of the
+// current library
+func (h *Handlers) Transforms(w http.ResponseWriter, r *http.Request) {
+	config, err := h.transforms.load(h.loader.BaseDir())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.sendJSON(w, config, http.StatusOK)
+}
+
+// SetTransforms handles PUT /lgs/transforms - replaces the event transforms
+// of the current library and saves them in the library folder
+func (h *Handlers) SetTransforms(w http.ResponseWriter, r *http.Request) {
+	if !h.loader.IsOpen() {
+		h.sendError(w, "no library open", http.StatusConflict)
+		return
+	}
+	var config EventTransforms
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if config.Transforms == nil {
+		config.Transforms = []EventTransform{}
+	}
+	if err := config.Validate(); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.transforms.save(h.loader.BaseDir(), &config); err != nil {
+		h.sendError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Printf("[LGS] Event transforms updated: %d transforms, default=%q\n", len(config.Transforms), config.Default)
+	h.sendJSON(w, config, http.StatusOK)
+}
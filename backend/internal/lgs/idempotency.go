@@ -0,0 +1,134 @@
+package lgs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// RequestIDHeader carries the idempotency key of a wallet request.
+const RequestIDHeader = "X-Request-ID"
+
+// ReplayedHeader is set on responses served from the idempotency cache.
+const ReplayedHeader = "X-Idempotent-Replay"
+
+// maxIdempotentResponses bounds the responses kept for repeated keys; the
+// oldest are forgotten first.
+const maxIdempotentResponses = 10000
+
+// idempotentResponse is the response to the first request with a key.
+// done is closed once it is stored, so repeats that arrive while the first
+// request is still handled wait for it.
+type idempotentResponse struct {
+	fingerprint [sha256.Size]byte
+	done        chan struct{}
+	status      int
+	contentType string
+	body        []byte
+}
+
+// idempotencyCache holds responses by endpoint and request ID.
+type idempotencyCache struct {
+	mu        sync.Mutex
+	responses map[string]*idempotentResponse
+	order     []string
+}
+
+// claim returns the response stored for a key, or registers the caller as
+// the first request with it (first is true).
+func (c *idempotencyCache) claim(key string, fingerprint [sha256.Size]byte) (resp *idempotentResponse, first bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resp, ok := c.responses[key]; ok {
+		return resp, false
+	}
+	if c.responses == nil {
+		c.responses = make(map[string]*idempotentResponse)
+	}
+	resp = &idempotentResponse{fingerprint: fingerprint, done: make(chan struct{})}
+	c.responses[key] = resp
+	c.order = append(c.order, key)
+	for len(c.order) > maxIdempotentResponses {
+		delete(c.responses, c.order[0])
+		c.order = c.order[1:]
+	}
+	return resp, true
+}
+
+// forget drops a key so the request can be retried.
+func (c *idempotencyCache) forget(key string, resp *idempotentResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.responses[key] == resp {
+		delete(c.responses, key)
+	}
+}
+
+// Idempotent wraps /wallet/play and /wallet/end-round so a request repeated
+// with the same X-Request-ID gets the original response instead of being
+// handled again, like the production RGS. Reusing a key with another body
+// is refused. Server errors and conflicts with a streamed batch play (409)
+// are not kept, so those requests can be retried.
+// Requests without the header are handled as usual.
+func (h *Handlers) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			next(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.sendError(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := r.URL.Path + " " + requestID
+		fingerprint := sha256.Sum256(body)
+		resp, first := h.idempotency.claim(key, fingerprint)
+		if !first {
+			select {
+			case <-resp.done:
+			case <-r.Context().Done():
+				return
+			}
+			if resp.status == 0 {
+				// The first request failed and was forgotten: handle this one
+				h.Idempotent(next)(w, r)
+				return
+			}
+			if resp.fingerprint != fingerprint {
+				h.sendError(w, fmt.Sprintf("%s %q was already used with another request body", RequestIDHeader, requestID), http.StatusConflict)
+				return
+			}
+			fmt.Printf("[LGS] Idempotency: replayed %s %s=%s\n", r.URL.Path, RequestIDHeader, requestID)
+			w.Header().Set("Content-Type", resp.contentType)
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set(ReplayedHeader, "true")
+			w.WriteHeader(resp.status)
+			w.Write(resp.body)
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		defer close(resp.done)
+		defer func() {
+			if err := recover(); err != nil {
+				h.idempotency.forget(key, resp)
+				panic(err)
+			}
+			if rec.status >= http.StatusInternalServerError || rec.status == http.StatusConflict {
+				h.idempotency.forget(key, resp)
+				return
+			}
+			resp.status = rec.status
+			resp.contentType = rec.Header().Get("Content-Type")
+			resp.body = rec.body.Bytes()
+		}()
+		next(rec, r)
+	}
+}
@@ -0,0 +1,343 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"lutexplorer/internal/ws"
+	"stakergs"
+)
+
+// JackpotConfig configures a progressive jackpot pool. Amounts are in API
+// units of BaseCurrency; plays in other currencies are converted at the
+// configured rates. Every spin of a batch play feeds and may hit the pools
+// like a single play.
+type JackpotConfig struct {
+	Name string `json:"name"`
+	// Mode limits the pool to plays in one mode; empty means every mode
+	Mode string `json:"mode,omitempty"`
+	// ContributionPercent of each bet goes into the pool, e.g. 1.5
+	ContributionPercent float64 `json:"contributionPercent"`
+	// Seed is the pool value after a reset or a hit
+	Seed int64 `json:"seed"`
+	// A play hits the jackpot when its simID is listed or its payout
+	// multiplier reaches TriggerMultiplier (0 disables the threshold)
+	TriggerSimIDs     []int   `json:"triggerSimIDs,omitempty"`
+	TriggerMultiplier float64 `json:"triggerMultiplier,omitempty"`
+}
+
+// Validate normalizes the name and mode and checks the fields.
+func (c *JackpotConfig) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Mode = strings.ToLower(c.Mode)
+	if c.Name == "" {
+		return fmt.Errorf("jackpot name is required")
+	}
+	if c.ContributionPercent < 0 || c.ContributionPercent > 100 || math.IsNaN(c.ContributionPercent) {
+		return fmt.Errorf("%s: contributionPercent must be between 0 and 100", c.Name)
+	}
+	if c.Seed < 0 {
+		return fmt.Errorf("%s: seed must be non-negative", c.Name)
+	}
+	if c.TriggerMultiplier < 0 || math.IsNaN(c.TriggerMultiplier) {
+		return fmt.Errorf("%s: triggerMultiplier must be non-negative", c.Name)
+	}
+	if len(c.TriggerSimIDs) == 0 && c.TriggerMultiplier == 0 {
+		return fmt.Errorf("%s: set triggerSimIDs or triggerMultiplier", c.Name)
+	}
+	return nil
+}
+
+// triggered reports whether a play hits the pool.
+func (c JackpotConfig) triggered(simID int, multiplier float64) bool {
+	if c.TriggerMultiplier > 0 && multiplier >= c.TriggerMultiplier {
+		return true
+	}
+	for _, id := range c.TriggerSimIDs {
+		if id == simID {
+			return true
+		}
+	}
+	return false
+}
+
+// JackpotPool is a pool's configuration and current state.
+type JackpotPool struct {
+	JackpotConfig
+	Value   int64       `json:"value"`
+	Hits    int         `json:"hits"`
+	LastHit *JackpotWin `json:"lastHit,omitempty"`
+}
+
+// JackpotValue is the current value of a pool, as sent with plays.
+type JackpotValue struct {
+	Name     string `json:"name"`
+	Value    int64  `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// JackpotWin is a pool paid out to a play. Amount is in the session's
+// currency and is included in the round payout.
+type JackpotWin struct {
+	Name      string    `json:"name"`
+	SessionID string    `json:"sessionID"`
+	Mode      string    `json:"mode"`
+	SimID     int       `json:"simID"`
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	At        time.Time `json:"at"`
+}
+
+// jackpotPools holds the configured pools by name.
+type jackpotPools struct {
+	mu    sync.Mutex
+	pools map[string]*JackpotPool
+}
+
+// set adds or replaces pools, seeding them; replace drops the others.
+func (p *jackpotPools) set(configs []JackpotConfig, replace bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if replace || p.pools == nil {
+		p.pools = make(map[string]*JackpotPool, len(configs))
+	}
+	for _, c := range configs {
+		p.pools[c.Name] = &JackpotPool{JackpotConfig: c, Value: c.Seed}
+	}
+}
+
+// remove drops a pool, or all of them when name is empty. Returns false if
+// the pool does not exist.
+func (p *jackpotPools) remove(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if name == "" {
+		p.pools = nil
+		return true
+	}
+	if _, ok := p.pools[name]; !ok {
+		return false
+	}
+	delete(p.pools, name)
+	return true
+}
+
+// reset puts pools back to their seed, or all of them when name is empty.
+func (p *jackpotPools) reset(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	found := false
+	for _, pool := range p.pools {
+		if name == "" || pool.Name == name {
+			pool.Value = pool.Seed
+			found = true
+		}
+	}
+	return found || name == ""
+}
+
+// all returns copies of the pools sorted by name.
+func (p *jackpotPools) all() []JackpotPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]JackpotPool, 0, len(p.pools))
+	for _, pool := range p.pools {
+		out = append(out, *pool)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// values returns the current pool values, or nil without pools.
+func (p *jackpotPools) values() []JackpotValue {
+	pools := p.all()
+	if len(pools) == 0 {
+		return nil
+	}
+	values := make([]JackpotValue, len(pools))
+	for i, pool := range pools {
+		values[i] = JackpotValue{Name: pool.Name, Value: pool.Value, Currency: BaseCurrency}
+	}
+	return values
+}
+
+// play adds a bet's contribution to the pools of its mode and pays out the
+// pools the outcome triggers; the winner's own contribution is included.
+// Amounts are converted between the play's currency and BaseCurrency.
+func (p *jackpotPools) play(session *SessionData, currency, base Currency, mode string, simID int, multiplier float64, bet int64) []JackpotWin {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var wins []JackpotWin
+	for _, pool := range p.pools {
+		if pool.Mode != "" && !strings.EqualFold(pool.Mode, mode) {
+			continue
+		}
+		contribution := float64(bet) * pool.ContributionPercent / 100
+		pool.Value += convert(int64(contribution), currency, base)
+		if !pool.triggered(simID, multiplier) {
+			continue
+		}
+		win := JackpotWin{
+			Name:      pool.Name,
+			SessionID: session.SessionID,
+			Mode:      mode,
+			SimID:     simID,
+			Amount:    convert(pool.Value, base, currency),
+			Currency:  currency.Code,
+			At:        time.Now(),
+		}
+		pool.Value = pool.Seed
+		pool.Hits++
+		pool.LastHit = &win
+		wins = append(wins, win)
+	}
+	sort.Slice(wins, func(i, j int) bool { return wins[i].Name < wins[j].Name })
+	return wins
+}
+
+// awardJackpots runs a play through the jackpot pools and returns the wins
+// and the total to add to the payout.
+func (h *Handlers) awardJackpots(session *SessionData, currency Currency, mode string, simID int, multiplier float64, bet int64) ([]JackpotWin, int64) {
+	base, err := h.currency(BaseCurrency)
+	if err != nil {
+		return nil, 0
+	}
+	wins := h.jackpots.play(session, currency, base, mode, simID, multiplier, bet)
+	var total int64
+	for _, win := range wins {
+		total += win.Amount
+		fmt.Printf("[LGS] Jackpot: %s hit by session=%s, mode=%s, simID=%d, amount=%d %s\n",
+			win.Name, win.SessionID, win.Mode, win.SimID, win.Amount, win.Currency)
+	}
+	return wins, total
+}
+
+// jackpotHook runs one spin of a batch play through the jackpot pools and
+// returns the wins and the total to add to the spin's payout.
+type jackpotHook func(outcome stakergs.Outcome, multiplier float64) ([]JackpotWin, int64)
+
+// batchJackpots returns the jackpot hook for the spins of a batch play, or
+// nil when no pools are configured.
+func (h *Handlers) batchJackpots(session *SessionData, currency Currency, mode string, betPerSpin int64) jackpotHook {
+	if h.jackpots.values() == nil {
+		return nil
+	}
+	return func(outcome stakergs.Outcome, multiplier float64) ([]JackpotWin, int64) {
+		return h.awardJackpots(session, currency, mode, outcome.SimID, multiplier, betPerSpin)
+	}
+}
+
+// broadcastJackpotWins announces jackpot hits to WebSocket clients.
+func (h *Handlers) broadcastJackpotWins(wins []JackpotWin) {
+	if h.wsHub == nil {
+		return
+	}
+	for _, win := range wins {
+		h.wsHub.Broadcast(ws.Message{Type: ws.MsgLGSJackpotHit, Payload: win})
+	}
+}
+
+// JackpotsRequest for POST /lgs/config/jackpots
+type JackpotsRequest struct {
+	Pools []JackpotConfig `json:"pools"`
+	// Replace drops the pools not listed instead of keeping them
+	Replace bool `json:"replace"`
+}
+
+// JackpotsResponse for /lgs/config/jackpots
+type JackpotsResponse struct {
+	BaseCurrency string        `json:"baseCurrency"`
+	Pools        []JackpotPool `json:"pools"`
+	Message      string        `json:"message,omitempty"`
+}
+
+// GetJackpots handles GET /lgs/config/jackpots - pools and their values
+func (h *Handlers) GetJackpots(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, JackpotsResponse{BaseCurrency: BaseCurrency, Pools: h.jackpots.all()}, http.StatusOK)
+}
+
+// SetJackpots handles POST /lgs/config/jackpots - adds or replaces pools,
+// seeding their values
+func (h *Handlers) SetJackpots(w http.ResponseWriter, r *http.Request) {
+	var req JackpotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Pools) == 0 {
+		h.sendError(w, "pools are required", http.StatusBadRequest)
+		return
+	}
+	seen := make(map[string]bool, len(req.Pools))
+	for i := range req.Pools {
+		if err := req.Pools[i].Validate(); err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if seen[req.Pools[i].Name] {
+			h.sendError(w, fmt.Sprintf("duplicate jackpot %q", req.Pools[i].Name), http.StatusBadRequest)
+			return
+		}
+		seen[req.Pools[i].Name] = true
+	}
+	h.jackpots.set(req.Pools, req.Replace)
+
+	fmt.Printf("[LGS] Jackpots: %d configured, replace=%v\n", len(req.Pools), req.Replace)
+
+	h.broadcastSessionsUpdate()
+
+	h.sendJSON(w, JackpotsResponse{
+		BaseCurrency: BaseCurrency,
+		Pools:        h.jackpots.all(),
+		Message:      fmt.Sprintf("%d jackpots configured", len(req.Pools)),
+	}, http.StatusOK)
+}
+
+// ResetJackpots handles POST /lgs/jackpots/reset - puts pools back to their
+// seed. Query params: name (optional, all pools if empty)
+func (h *Handlers) ResetJackpots(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if !h.jackpots.reset(name) {
+		h.sendError(w, fmt.Sprintf("jackpot %q not found", name), http.StatusNotFound)
+		return
+	}
+
+	fmt.Printf("[LGS] Jackpots reset: %s\n", describeTarget(name))
+
+	h.broadcastSessionsUpdate()
+
+	h.sendJSON(w, JackpotsResponse{BaseCurrency: BaseCurrency, Pools: h.jackpots.all()}, http.StatusOK)
+}
+
+// ClearJackpots handles DELETE /lgs/config/jackpots - removes a pool, or all
+// Query params: name (optional)
+func (h *Handlers) ClearJackpots(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if !h.jackpots.remove(name) {
+		h.sendError(w, fmt.Sprintf("jackpot %q not found", name), http.StatusNotFound)
+		return
+	}
+
+	fmt.Printf("[LGS] Jackpots cleared: %s\n", describeTarget(name))
+
+	h.broadcastSessionsUpdate()
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("cleared %s", describeTarget(name)),
+	}, http.StatusOK)
+}
+
+// describeTarget names the pool a request applies to for messages.
+func describeTarget(name string) string {
+	if name == "" {
+		return "all jackpots"
+	}
+	return "jackpot " + name
+}
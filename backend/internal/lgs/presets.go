@@ -0,0 +1,104 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// BiasPreset sets up a session for a demo or test in one call: RTP bias,
+// balance and the payout bands of the next spins.
+type BiasPreset struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Bias        float64 `json:"bias"`
+	// Balance in API units (1000000 = $1); nil keeps the current balance
+	Balance *int64 `json:"balance,omitempty"`
+	// ResetStats clears history and statistics of the session
+	ResetStats bool `json:"resetStats"`
+	// Force lists payout bands (see lut.PayoutBands) for the next spins, in
+	// order; one weighted-random outcome is forced per band
+	Force []string `json:"force,omitempty"`
+}
+
+// dollars converts a dollar amount to a balance in API units.
+func dollars(amount int64) *int64 {
+	units := amount * 1000000
+	return &units
+}
+
+// DefaultBiasPresets are available unless replaced by a presets file.
+var DefaultBiasPresets = []BiasPreset{
+	{
+		Name:        "neutral",
+		Description: "Normal RTP, default balance, nothing forced",
+		Bias:        0,
+		Balance:     dollars(DefaultBalance / 1000000),
+		ResetStats:  true,
+	},
+	{
+		Name:        "demo_lucky",
+		Description: "Boosted payouts opening with a win and a big win, for showing off the game",
+		Bias:        0.8,
+		Balance:     dollars(1000),
+		ResetStats:  true,
+		Force:       []string{"win", "big_win"},
+	},
+	{
+		Name:        "stress_unlucky",
+		Description: "Suppressed payouts on a small balance opening with losses, for testing low balance and losing streak flows",
+		Bias:        -0.8,
+		Balance:     dollars(100),
+		ResetStats:  true,
+		Force:       []string{"zero", "zero", "zero"},
+	},
+}
+
+// LoadBiasPresets reads presets from a JSON file (an array of presets).
+// Presets with the name of a default preset replace it.
+func LoadBiasPresets(path string) ([]BiasPreset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets: %w", err)
+	}
+	var loaded []BiasPreset
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("invalid presets file: %w", err)
+	}
+
+	byName := make(map[string]BiasPreset, len(DefaultBiasPresets)+len(loaded))
+	for _, p := range DefaultBiasPresets {
+		byName[p.Name] = p
+	}
+	for _, p := range loaded {
+		if p.Name == "" {
+			return nil, fmt.Errorf("invalid presets file: preset without name")
+		}
+		if p.Bias < -2 || p.Bias > 2 {
+			return nil, fmt.Errorf("preset %q: bias must be within [-2, 2]", p.Name)
+		}
+		if p.Balance != nil && *p.Balance < 0 {
+			return nil, fmt.Errorf("preset %q: balance must be non-negative", p.Name)
+		}
+		byName[p.Name] = p
+	}
+
+	presets := make([]BiasPreset, 0, len(byName))
+	for _, p := range byName {
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets, nil
+}
+
+// findPreset looks up a preset by name (case-insensitive).
+func findPreset(presets []BiasPreset, name string) (BiasPreset, bool) {
+	for _, p := range presets {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return BiasPreset{}, false
+}
@@ -0,0 +1,415 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"stakergs"
+)
+
+// RecordingVersion is the format version of session recordings
+const RecordingVersion = 1
+
+// MaxRecordedPlays limits the plays kept by one recording; later plays are
+// dropped and the recording is marked truncated
+const MaxRecordedPlays = 100000
+
+// maxReportedDivergences limits the divergences listed in a replay report
+const maxReportedDivergences = 1000
+
+// RecordedPlay is one play captured by a session recording. Amounts are in
+// API units.
+type RecordedPlay struct {
+	Seq  int    `json:"seq"` // 1-based
+	Mode string `json:"mode"`
+	// Variant is the table variant the session played ("" = table on disk)
+	Variant          string  `json:"variant,omitempty"`
+	SimID            int     `json:"simID"`
+	Amount           int64   `json:"amount"` // base bet, before the mode cost
+	Bet              int64   `json:"bet"`    // amount * mode cost
+	Payout           int64   `json:"payout"`
+	PayoutMultiplier float64 `json:"payoutMultiplier"`
+	// Probability of the outcome in the table played
+	Probability float64 `json:"probability"`
+	Balance     int64   `json:"balance"` // after the play
+	Forced      bool    `json:"forced,omitempty"`
+	Batch       bool    `json:"batch,omitempty"` // played by /lgs/batchplay
+}
+
+// SessionRecording captures the plays of a session in a portable file that
+// can be replayed against a changed LUT (see replayRecording).
+type SessionRecording struct {
+	Version      int            `json:"version"`
+	SessionID    string         `json:"sessionID"`
+	Currency     string         `json:"currency"`
+	StartBalance int64          `json:"startBalance"`
+	StartedAt    time.Time      `json:"startedAt"`
+	StoppedAt    time.Time      `json:"stoppedAt,omitempty"`
+	Truncated    bool           `json:"truncated,omitempty"`
+	Plays        []RecordedPlay `json:"plays"`
+}
+
+// sessionRecorder holds the active recordings by session ID.
+type sessionRecorder struct {
+	mu     sync.Mutex
+	active map[string]*SessionRecording
+}
+
+func (r *sessionRecorder) start(session *SessionData) *SessionRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.active == nil {
+		r.active = make(map[string]*SessionRecording)
+	}
+	rec := &SessionRecording{
+		Version:      RecordingVersion,
+		SessionID:    session.SessionID,
+		Currency:     session.Currency,
+		StartBalance: session.Balance,
+		StartedAt:    time.Now().UTC(),
+		Plays:        []RecordedPlay{},
+	}
+	r.active[session.SessionID] = rec
+	return rec
+}
+
+func (r *sessionRecorder) stop(sessionID string) *SessionRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := r.active[sessionID]
+	if rec != nil {
+		delete(r.active, sessionID)
+		rec.StoppedAt = time.Now().UTC()
+	}
+	return rec
+}
+
+// recording reports whether a session is being recorded
+func (r *sessionRecorder) recording(sessionID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active[sessionID] != nil
+}
+
+// add appends a play to the session's recording, if any
+func (r *sessionRecorder) add(sessionID string, play RecordedPlay) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := r.active[sessionID]
+	if rec == nil {
+		return
+	}
+	if len(rec.Plays) >= MaxRecordedPlays {
+		rec.Truncated = true
+		return
+	}
+	play.Seq = len(rec.Plays) + 1
+	rec.Plays = append(rec.Plays, play)
+}
+
+// recordPlay captures a play of session if it is being recorded
+func (h *Handlers) recordPlay(session *SessionData, table *stakergs.LookupTable, mode string, outcome stakergs.Outcome, amount, bet, payout int64, forced, batch bool) {
+	var probability float64
+	if total := table.TotalWeight(); total > 0 {
+		probability = float64(outcome.Weight) / float64(total)
+	}
+	h.recorder.add(session.SessionID, RecordedPlay{
+		Mode:             mode,
+		Variant:          session.Variant(mode),
+		SimID:            outcome.SimID,
+		Amount:           amount,
+		Bet:              bet,
+		Payout:           payout,
+		PayoutMultiplier: float64(outcome.Payout) / 100.0,
+		Probability:      probability,
+		Balance:          session.Balance,
+		Forced:           forced,
+		Batch:            batch,
+	})
+}
+
+// batchRecorder returns a processBatchSpins callback recording each spin,
+// or nil if the session is not being recorded
+func (h *Handlers) batchRecorder(session *SessionData, table *stakergs.LookupTable, mode string, amount, bet int64) func(stakergs.Outcome, int64) {
+	if !h.recorder.recording(session.SessionID) {
+		return nil
+	}
+	return func(outcome stakergs.Outcome, payout int64) {
+		h.recordPlay(session, table, mode, outcome, amount, bet, payout, false, true)
+	}
+}
+
+// Divergence kinds reported by a replay
+const (
+	DivergenceModeMissing    = "mode_missing"    // mode (or variant) no longer loaded
+	DivergenceSimMissing     = "sim_missing"     // simID no longer in the table
+	DivergencePayoutChanged  = "payout_changed"  // the simID pays a different multiplier
+	DivergenceCostChanged    = "cost_changed"    // the mode's bet cost changed
+	DivergenceUnreachable    = "unreachable"     // the outcome's weight is now zero
+	DivergenceWeightChanged  = "weight_changed"  // the outcome's probability changed
+	DivergenceBalanceChanged = "balance_changed" // play from which the balance differs
+)
+
+// PlayDivergence is a recorded play that the current LUT would not repeat
+// exactly.
+type PlayDivergence struct {
+	Seq      int     `json:"seq"`
+	Mode     string  `json:"mode"`
+	SimID    int     `json:"simID"`
+	Kind     string  `json:"kind"`
+	Message  string  `json:"message"`
+	Recorded float64 `json:"recorded"`
+	Current  float64 `json:"current"`
+}
+
+// ReplayReport compares a recording with the current LUT.
+type ReplayReport struct {
+	SessionID string `json:"sessionID"`
+	Plays     int    `json:"plays"`
+	Matched   int    `json:"matched"` // plays without any divergence
+	Diverged  int    `json:"diverged"`
+	// Kinds counts the divergences by kind
+	Kinds       map[string]int   `json:"kinds"`
+	Divergences []PlayDivergence `json:"divergences"` // first 1000
+	// Balances after the last play, in API units
+	RecordedBalance int64   `json:"recordedBalance"`
+	ReplayedBalance int64   `json:"replayedBalance"`
+	RecordedRTP     float64 `json:"recordedRTP"`
+	ReplayedRTP     float64 `json:"replayedRTP"`
+	// LikelihoodRatio is how much more (> 1) or less likely the recorded
+	// sequence of unforced outcomes is under the current weights
+	LikelihoodRatio float64 `json:"likelihoodRatio"`
+}
+
+// replayRecording re-executes a recording against the current tables: each
+// play's simID is looked up again and paid at today's multiplier and cost,
+// and every difference is reported. Sessions are not touched.
+func (h *Handlers) replayRecording(rec *SessionRecording) *ReplayReport {
+	report := &ReplayReport{
+		SessionID:   rec.SessionID,
+		Plays:       len(rec.Plays),
+		Kinds:       map[string]int{},
+		Divergences: []PlayDivergence{},
+	}
+	// delta is how much more the replayed plays won (net) than the recorded
+	// ones; balance changes between plays (e.g. /lgs/set-balance) cancel out
+	var delta int64
+	var recWagered, recWon, wagered, won int64
+	var logRatio float64
+
+	type tableKey struct{ mode, variant string }
+	tables := make(map[tableKey]*stakergs.LookupTable)
+	index := make(map[*stakergs.LookupTable]map[int]stakergs.Outcome)
+
+	for _, play := range rec.Plays {
+		var diverged bool
+		diverge := func(kind, message string, recorded, current float64) {
+			diverged = true
+			report.Kinds[kind]++
+			if len(report.Divergences) < maxReportedDivergences {
+				report.Divergences = append(report.Divergences, PlayDivergence{
+					Seq: play.Seq, Mode: play.Mode, SimID: play.SimID,
+					Kind: kind, Message: message, Recorded: recorded, Current: current,
+				})
+			}
+		}
+		recWagered += play.Bet
+		recWon += play.Payout
+
+		key := tableKey{strings.ToLower(play.Mode), play.Variant}
+		table, seen := tables[key]
+		if !seen {
+			var err error
+			if play.Variant != "" {
+				table, err = h.loader.GetVariant(play.Mode, play.Variant)
+			} else {
+				table, err = h.loader.GetMode(play.Mode)
+			}
+			if err != nil {
+				table = nil
+			}
+			tables[key] = table
+			if table != nil && index[table] == nil {
+				byID := make(map[int]stakergs.Outcome, len(table.Outcomes))
+				for _, o := range table.Outcomes {
+					byID[o.SimID] = o
+				}
+				index[table] = byID
+			}
+		}
+
+		// Without the outcome the play is replayed as recorded, so one
+		// missing simID does not shift every later balance
+		bet, payout := play.Bet, play.Payout
+		if table == nil {
+			diverge(DivergenceModeMissing, fmt.Sprintf("mode %s is not loaded", describeTable(play.Mode, play.Variant)), 0, 0)
+		} else if outcome, ok := index[table][play.SimID]; !ok {
+			diverge(DivergenceSimMissing, fmt.Sprintf("simID %d is not in mode %s", play.SimID, play.Mode), 0, 0)
+		} else {
+			cost := table.Cost
+			if cost == 0 {
+				cost = 1.0
+			}
+			bet = int64(float64(play.Amount) * cost)
+			if bet != play.Bet {
+				diverge(DivergenceCostChanged, fmt.Sprintf("bet is %d instead of %d", bet, play.Bet), float64(play.Bet), float64(bet))
+			}
+			multiplier := float64(outcome.Payout) / 100.0
+			payout = int64(float64(play.Amount) * multiplier)
+			if multiplier != play.PayoutMultiplier {
+				diverge(DivergencePayoutChanged, fmt.Sprintf("pays %gx instead of %gx", multiplier, play.PayoutMultiplier), play.PayoutMultiplier, multiplier)
+			}
+
+			var probability float64
+			if total := table.TotalWeight(); total > 0 {
+				probability = float64(outcome.Weight) / float64(total)
+			}
+			switch {
+			case outcome.Weight == 0 && play.Probability > 0:
+				diverge(DivergenceUnreachable, "outcome now has zero weight", play.Probability, 0)
+			case !sameProbability(probability, play.Probability):
+				diverge(DivergenceWeightChanged, fmt.Sprintf("probability %.3g instead of %.3g", probability, play.Probability), play.Probability, probability)
+			}
+			if !play.Forced && probability > 0 && play.Probability > 0 {
+				logRatio += math.Log(probability / play.Probability)
+			}
+		}
+
+		wagered += bet
+		won += payout
+		firstDrift := delta == 0
+		delta += (payout - bet) - (play.Payout - play.Bet)
+		if firstDrift && delta != 0 {
+			balance := play.Balance + delta
+			diverge(DivergenceBalanceChanged, fmt.Sprintf("balance %d instead of %d", balance, play.Balance), float64(play.Balance), float64(balance))
+		}
+
+		if diverged {
+			report.Diverged++
+		} else {
+			report.Matched++
+		}
+	}
+
+	if n := len(rec.Plays); n > 0 {
+		report.RecordedBalance = rec.Plays[n-1].Balance
+	} else {
+		report.RecordedBalance = rec.StartBalance
+	}
+	report.ReplayedBalance = report.RecordedBalance + delta
+	if recWagered > 0 {
+		report.RecordedRTP = float64(recWon) / float64(recWagered)
+	}
+	if wagered > 0 {
+		report.ReplayedRTP = float64(won) / float64(wagered)
+	}
+	report.LikelihoodRatio = math.Exp(logRatio)
+	return report
+}
+
+// sameProbability compares probabilities up to float rounding
+func sameProbability(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-12*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func describeTable(mode, variant string) string {
+	if variant == "" {
+		return mode
+	}
+	return mode + " (variant " + variant + ")"
+}
+
+// StartRecording handles POST /lgs/record/start - starts capturing every
+// play of a session. A running recording of the session is discarded.
+func (h *Handlers) StartRecording(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionID"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	rec := h.recorder.start(session)
+
+	fmt.Printf("[LGS] Record: started session=%s, balance=%d\n", req.SessionID, rec.StartBalance)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success":      true,
+		"sessionID":    req.SessionID,
+		"startBalance": rec.StartBalance,
+		"startedAt":    rec.StartedAt,
+	}, http.StatusOK)
+}
+
+// StopRecording handles POST /lgs/record/stop - ends a session's recording
+// and returns it as a downloadable JSON file
+func (h *Handlers) StopRecording(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"sessionID"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+
+	rec := h.recorder.stop(req.SessionID)
+	if rec == nil {
+		h.sendError(w, fmt.Sprintf("session %s is not being recorded", req.SessionID), http.StatusNotFound)
+		return
+	}
+
+	fmt.Printf("[LGS] Record: stopped session=%s, plays=%d\n", req.SessionID, len(rec.Plays))
+
+	filename := fmt.Sprintf("recording-%s-%s.json", fileSafe(req.SessionID), rec.StartedAt.Format("20060102-150405"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	h.sendJSON(w, rec, http.StatusOK)
+}
+
+// fileSafe reduces a session ID to file name characters
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// ReplayRecording handles POST /lgs/record/replay - re-executes a
+// recording (the request body) against the current LUT and reports every
+// divergence
+func (h *Handlers) ReplayRecording(w http.ResponseWriter, r *http.Request) {
+	var rec SessionRecording
+	if err := json.NewDecoder(r.Body).Decode(&rec); err != nil {
+		h.sendError(w, "invalid recording", http.StatusBadRequest)
+		return
+	}
+	if rec.Version > RecordingVersion {
+		h.sendError(w, fmt.Sprintf("recording version %d is newer than supported (%d)", rec.Version, RecordingVersion), http.StatusBadRequest)
+		return
+	}
+
+	report := h.replayRecording(&rec)
+
+	fmt.Printf("[LGS] Record: replayed session=%s, plays=%d, diverged=%d\n",
+		rec.SessionID, report.Plays, report.Diverged)
+
+	h.sendJSON(w, report, http.StatusOK)
+}
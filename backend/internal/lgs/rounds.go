@@ -0,0 +1,154 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Phases of a multi-stage round. A round whose book triggers a feature
+// starts in PhaseBase; /bet/event calls move it through the pending feature
+// and the feature spins, and /wallet/end-round settles it.
+const (
+	PhaseBase           = "base"            // base spin shown, feature not reached yet
+	PhasePendingFeature = "pending_feature" // feature triggered, no feature spin shown yet
+	PhaseFeature        = "feature"         // feature spins in progress
+	PhaseSettled        = "settled"         // payout credited
+)
+
+// RoundProgress tracks a multi-stage round through the events of its book.
+// Event indexes are positions in the book's events array.
+type RoundProgress struct {
+	Phase string `json:"phase"`
+	// EventIndex is the last event reported with /bet/event, -1 before any
+	EventIndex int `json:"eventIndex"`
+	Events     int `json:"events"`
+	// TriggerIndex is the event that triggers the feature
+	TriggerIndex int `json:"triggerIndex"`
+	// FeatureSpinIndexes are the events that reveal a feature spin
+	FeatureSpinIndexes []int `json:"featureSpinIndexes"`
+	FeatureSpins       int   `json:"featureSpins"`
+	FeatureSpinsPlayed int   `json:"featureSpinsPlayed"`
+}
+
+// bookEvent is the part of a book event the round state machine reads.
+type bookEvent struct {
+	Type     string `json:"type"`
+	GameType string `json:"gameType"`
+}
+
+// newRoundProgress reads the events of a book and returns the progress of a
+// new round, or nil if the book has no feature and settles in one play.
+//
+// The feature starts at the first event whose type mentions a trigger
+// (e.g. "freeSpinTrigger"), or else at the first reveal in another game
+// type than the base spin's. Its spins are the reveals after the trigger
+// in another game type than the base spin's.
+func newRoundProgress(events json.RawMessage) *RoundProgress {
+	var book []bookEvent
+	if err := json.Unmarshal(events, &book); err != nil || len(book) == 0 {
+		return nil
+	}
+
+	baseGameType := ""
+	trigger := -1
+	for i, e := range book {
+		isReveal := strings.EqualFold(e.Type, "reveal")
+		if isReveal && baseGameType == "" {
+			baseGameType = e.GameType
+			continue
+		}
+		if strings.Contains(strings.ToLower(e.Type), "trigger") ||
+			(isReveal && e.GameType != "" && e.GameType != baseGameType) {
+			trigger = i
+			break
+		}
+	}
+	if trigger < 0 {
+		return nil
+	}
+
+	spins := make([]int, 0)
+	for i := trigger; i < len(book); i++ {
+		e := book[i]
+		if strings.EqualFold(e.Type, "reveal") && e.GameType != baseGameType {
+			spins = append(spins, i)
+		}
+	}
+	return &RoundProgress{
+		Phase:              PhaseBase,
+		EventIndex:         -1,
+		Events:             len(book),
+		TriggerIndex:       trigger,
+		FeatureSpinIndexes: spins,
+		FeatureSpins:       len(spins),
+	}
+}
+
+// advance moves the round to an event. Events are shown in order, so the
+// index cannot go back.
+func (p *RoundProgress) advance(index int) error {
+	if p.Phase == PhaseSettled {
+		return fmt.Errorf("round is settled")
+	}
+	if index < 0 || index >= p.Events {
+		return fmt.Errorf("event %d out of range: the round has %d events", index, p.Events)
+	}
+	if index < p.EventIndex {
+		return fmt.Errorf("event %d is before the last reported event %d", index, p.EventIndex)
+	}
+	p.EventIndex = index
+	p.FeatureSpinsPlayed = 0
+	for _, i := range p.FeatureSpinIndexes {
+		if i <= index {
+			p.FeatureSpinsPlayed++
+		}
+	}
+	switch {
+	case index < p.TriggerIndex:
+		p.Phase = PhaseBase
+	case p.FeatureSpinsPlayed == 0:
+		p.Phase = PhasePendingFeature
+	default:
+		p.Phase = PhaseFeature
+	}
+	return nil
+}
+
+// OpenRound returns the last round if it is a multi-stage round that has
+// not been settled, or nil.
+func (s *SessionData) OpenRound() *RoundInfo {
+	if s.LastRound == nil || !s.LastRound.Active || s.LastRound.Progress == nil {
+		return nil
+	}
+	if s.LastRound.Progress.Phase == PhaseSettled {
+		return nil
+	}
+	return s.LastRound
+}
+
+// AdvanceRound records the event the client reached in the open round.
+// Returns nil without an open round.
+func (s *SessionData) AdvanceRound(index int) (*RoundProgress, error) {
+	round := s.OpenRound()
+	if round == nil {
+		return nil, nil
+	}
+	if err := round.Progress.advance(index); err != nil {
+		return nil, err
+	}
+	return round.Progress, nil
+}
+
+// SettleRound ends the open round and credits its payout, which multi-stage
+// rounds hold back until then. Returns the amount credited.
+func (s *SessionData) SettleRound() int64 {
+	round := s.OpenRound()
+	if round == nil {
+		return 0
+	}
+	s.Balance += round.Payout
+	round.Progress.Phase = PhaseSettled
+	round.Active = false
+	return round.Payout
+}
@@ -0,0 +1,95 @@
+package lgs
+
+import (
+	"strings"
+	"sync"
+
+	"stakergs"
+)
+
+// SamplingCounter counts how often the LGS drew each simID of a mode,
+// across all sessions. Only draws from the unbiased sampler on the table on
+// disk are counted; forced, biased and variant draws are tallied as skipped
+// so they do not distort the fit against the table weights.
+type SamplingCounter struct {
+	mu    sync.Mutex
+	modes map[string]*modeDraws // lower-case mode -> draws
+}
+
+type modeDraws struct {
+	counts  map[int]int64
+	skipped int64
+}
+
+// NewSamplingCounter creates an empty counter.
+func NewSamplingCounter() *SamplingCounter {
+	return &SamplingCounter{modes: make(map[string]*modeDraws)}
+}
+
+func (c *SamplingCounter) modeLocked(mode string) *modeDraws {
+	key := strings.ToLower(mode)
+	m, ok := c.modes[key]
+	if !ok {
+		m = &modeDraws{counts: make(map[int]int64)}
+		c.modes[key] = m
+	}
+	return m
+}
+
+// Record counts a draw of simID in a mode.
+func (c *SamplingCounter) Record(mode string, simID int) {
+	c.mu.Lock()
+	c.modeLocked(mode).counts[simID]++
+	c.mu.Unlock()
+}
+
+// Skip counts n draws that are not from the unbiased sampler.
+func (c *SamplingCounter) Skip(mode string, n int64) {
+	c.mu.Lock()
+	c.modeLocked(mode).skipped += n
+	c.mu.Unlock()
+}
+
+// Counted wraps a sampler so every outcome it draws is recorded.
+func (c *SamplingCounter) Counted(mode string, sample func() stakergs.Outcome) func() stakergs.Outcome {
+	return func() stakergs.Outcome {
+		outcome := sample()
+		c.Record(mode, outcome.SimID)
+		return outcome
+	}
+}
+
+// Uncounted wraps a sampler so every outcome it draws is tallied as skipped.
+func (c *SamplingCounter) Uncounted(mode string, sample func() stakergs.Outcome) func() stakergs.Outcome {
+	return func() stakergs.Outcome {
+		outcome := sample()
+		c.Skip(mode, 1)
+		return outcome
+	}
+}
+
+// Counts returns a copy of a mode's draw counts and its skipped draws.
+func (c *SamplingCounter) Counts(mode string) (map[int]int64, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m, ok := c.modes[strings.ToLower(mode)]
+	if !ok {
+		return map[int]int64{}, 0
+	}
+	counts := make(map[int]int64, len(m.counts))
+	for simID, n := range m.counts {
+		counts[simID] = n
+	}
+	return counts, m.skipped
+}
+
+// Reset forgets the draws of a mode, or of all modes if mode is empty.
+func (c *SamplingCounter) Reset(mode string) {
+	c.mu.Lock()
+	if mode == "" {
+		c.modes = make(map[string]*modeDraws)
+	} else {
+		delete(c.modes, strings.ToLower(mode))
+	}
+	c.mu.Unlock()
+}
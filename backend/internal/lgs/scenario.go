@@ -0,0 +1,276 @@
+package lgs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"stakergs"
+)
+
+// MaxScenarioSteps limits the steps of one scenario
+const MaxScenarioSteps = 1000
+
+// ScenarioStep forces the outcome of the next plays in a mode, either to a
+// fixed simID or to a weighted-random outcome whose payout multiplier lies
+// in [MinPayout, MaxPayout]. A scenario is a list of steps consumed in order
+// across plays, e.g. three losses, a bonus trigger, then a max win.
+type ScenarioStep struct {
+	Mode  string `json:"mode"`
+	SimID *int   `json:"simID,omitempty"`
+	// Payout multiplier bounds, inclusive; nil is unbounded. maxPayout 0
+	// forces a loss.
+	MinPayout *float64 `json:"minPayout,omitempty"`
+	MaxPayout *float64 `json:"maxPayout,omitempty"`
+	// Repeat is how many plays the step covers (default 1); counts down as
+	// plays consume it
+	Repeat int `json:"repeat"`
+}
+
+// describe returns a short description of what the step forces
+func (s ScenarioStep) describe() string {
+	if s.SimID != nil {
+		return fmt.Sprintf("simID %d", *s.SimID)
+	}
+	switch {
+	case s.MinPayout != nil && s.MaxPayout != nil:
+		return fmt.Sprintf("payout %gx-%gx", *s.MinPayout, *s.MaxPayout)
+	case s.MinPayout != nil:
+		return fmt.Sprintf("payout >= %gx", *s.MinPayout)
+	default:
+		return fmt.Sprintf("payout <= %gx", *s.MaxPayout)
+	}
+}
+
+// matches reports whether an outcome satisfies the step
+func (s ScenarioStep) matches(o stakergs.Outcome) bool {
+	if s.SimID != nil {
+		return o.SimID == *s.SimID
+	}
+	payout := float64(o.Payout) / 100.0
+	if s.MinPayout != nil && payout < *s.MinPayout {
+		return false
+	}
+	if s.MaxPayout != nil && payout > *s.MaxPayout {
+		return false
+	}
+	return true
+}
+
+// pickScenarioOutcome returns the outcome a step forces from table. Payout
+// ranges pick among the matching outcomes by LUT weight, or uniformly if
+// all of them have zero weight.
+func pickScenarioOutcome(table *stakergs.LookupTable, step ScenarioStep) (stakergs.Outcome, error) {
+	var matches []stakergs.Outcome
+	var total float64
+	for _, o := range table.Outcomes {
+		if step.matches(o) {
+			if step.SimID != nil {
+				return o, nil
+			}
+			matches = append(matches, o)
+			total += float64(o.Weight)
+		}
+	}
+	if len(matches) == 0 {
+		return stakergs.Outcome{}, fmt.Errorf("no outcome in mode %s matches scenario step %s", step.Mode, step.describe())
+	}
+	if total > 0 {
+		target := rand.Float64() * total
+		for _, o := range matches {
+			target -= float64(o.Weight)
+			if target < 0 {
+				return o, nil
+			}
+		}
+	}
+	return matches[rand.Intn(len(matches))], nil
+}
+
+// SetScenario replaces the session's scenario, or appends to it
+func (s *SessionData) SetScenario(steps []ScenarioStep, appendSteps bool) {
+	if !appendSteps {
+		s.Scenario = nil
+	}
+	s.Scenario = append(s.Scenario, steps...)
+}
+
+// PeekScenarioStep returns the next scenario step if it applies to mode.
+// Steps are strictly ordered: a play in another mode does not skip ahead.
+func (s *SessionData) PeekScenarioStep(mode string) (ScenarioStep, bool) {
+	if len(s.Scenario) == 0 || !strings.EqualFold(s.Scenario[0].Mode, mode) {
+		return ScenarioStep{}, false
+	}
+	return s.Scenario[0], true
+}
+
+// AdvanceScenario consumes one play of the next scenario step
+func (s *SessionData) AdvanceScenario() {
+	if len(s.Scenario) == 0 {
+		return
+	}
+	s.Scenario[0].Repeat--
+	if s.Scenario[0].Repeat <= 0 {
+		s.Scenario = s.Scenario[1:]
+	}
+	if len(s.Scenario) == 0 {
+		s.Scenario = nil
+	}
+}
+
+// ScenarioSpins returns the number of plays the remaining scenario covers
+func (s *SessionData) ScenarioSpins() int {
+	var spins int
+	for _, step := range s.Scenario {
+		spins += step.Repeat
+	}
+	return spins
+}
+
+// ScenarioRequest for POST /lgs/scenario
+type ScenarioRequest struct {
+	SessionID string         `json:"sessionID"`
+	Steps     []ScenarioStep `json:"steps"`
+	// Append adds the steps after the remaining ones instead of replacing them
+	Append bool `json:"append"`
+}
+
+// ScenarioResponse for /lgs/scenario
+type ScenarioResponse struct {
+	SessionID string         `json:"sessionID"`
+	Steps     []ScenarioStep `json:"steps"` // remaining, next first
+	Spins     int            `json:"spins"` // plays the remaining steps cover
+	Message   string         `json:"message,omitempty"`
+}
+
+func scenarioResponse(session *SessionData, message string) ScenarioResponse {
+	steps := session.Scenario
+	if steps == nil {
+		steps = []ScenarioStep{}
+	}
+	return ScenarioResponse{
+		SessionID: session.SessionID,
+		Steps:     steps,
+		Spins:     session.ScenarioSpins(),
+		Message:   message,
+	}
+}
+
+// validateScenarioStep normalizes a step and checks it can be satisfied by
+// the mode's table
+func (h *Handlers) validateScenarioStep(step *ScenarioStep) error {
+	if step.Mode == "" {
+		return fmt.Errorf("mode is required")
+	}
+	if step.Repeat == 0 {
+		step.Repeat = 1
+	}
+	if step.Repeat < 0 || step.Repeat > 100000 {
+		return fmt.Errorf("repeat must be between 1 and 100000")
+	}
+	hasRange := step.MinPayout != nil || step.MaxPayout != nil
+	if (step.SimID != nil) == hasRange {
+		return fmt.Errorf("set either simID or a payout range (minPayout/maxPayout)")
+	}
+	if step.MinPayout != nil && step.MaxPayout != nil && *step.MinPayout > *step.MaxPayout {
+		return fmt.Errorf("minPayout must not exceed maxPayout")
+	}
+	table, err := h.loader.GetMode(step.Mode)
+	if err != nil {
+		return fmt.Errorf("mode not found: %s", step.Mode)
+	}
+	_, err = pickScenarioOutcome(table, *step)
+	return err
+}
+
+// SetScenario handles POST /lgs/scenario - scripts the outcomes of the next
+// plays of a session as an ordered list of steps
+func (h *Handlers) SetScenario(w http.ResponseWriter, r *http.Request) {
+	var req ScenarioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = "default-session"
+	}
+	if len(req.Steps) == 0 {
+		h.sendError(w, "steps are required", http.StatusBadRequest)
+		return
+	}
+	// Validate every step before touching the session so a bad script changes nothing
+	for i := range req.Steps {
+		if err := h.validateScenarioStep(&req.Steps[i]); err != nil {
+			h.sendError(w, fmt.Sprintf("step %d: %v", i+1, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	session := h.sessions.GetOrCreate(req.SessionID)
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	total := len(req.Steps)
+	if req.Append {
+		total += len(session.Scenario)
+	}
+	if total > MaxScenarioSteps {
+		h.sendError(w, fmt.Sprintf("a scenario has at most %d steps", MaxScenarioSteps), http.StatusBadRequest)
+		return
+	}
+	session.SetScenario(req.Steps, req.Append)
+	h.sessions.Update(session)
+
+	fmt.Printf("[LGS] Scenario: session=%s, steps=%d, spins=%d, append=%v\n",
+		req.SessionID, len(session.Scenario), session.ScenarioSpins(), req.Append)
+
+	h.sendJSON(w, scenarioResponse(session,
+		fmt.Sprintf("next %d plays are scripted", session.ScenarioSpins())), http.StatusOK)
+}
+
+// GetScenario handles GET /lgs/scenario - returns the remaining steps of a
+// session's scenario
+func (h *Handlers) GetScenario(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("sessionID")
+	if sessionID == "" {
+		sessionID = "default-session"
+	}
+
+	session := h.sessions.Get(sessionID)
+	if session == nil {
+		h.sendJSON(w, ScenarioResponse{SessionID: sessionID, Steps: []ScenarioStep{}}, http.StatusOK)
+		return
+	}
+	h.sendJSON(w, scenarioResponse(session, ""), http.StatusOK)
+}
+
+// ClearScenario handles DELETE /lgs/scenario - drops a session's scenario
+func (h *Handlers) ClearScenario(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("sessionID")
+	if sessionID == "" {
+		sessionID = "default-session"
+	}
+
+	session := h.sessions.Get(sessionID)
+	if session == nil {
+		h.sendError(w, "session not found", http.StatusNotFound)
+		return
+	}
+	if !h.claimSession(w, session) {
+		return
+	}
+	defer session.busy.RUnlock()
+	session.SetScenario(nil, false)
+	h.sessions.Update(session)
+
+	fmt.Printf("[LGS] Clear Scenario: session=%s\n", sessionID)
+
+	h.sendJSON(w, map[string]interface{}{
+		"success": true,
+		"message": "scenario cleared",
+	}, http.StatusOK)
+}
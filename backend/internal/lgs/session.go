@@ -1,6 +1,8 @@
 package lgs
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,10 +32,37 @@ type SessionData struct {
 	TotalWon     int64
 	// ForcedSimID maps mode -> simID for forcing specific outcomes
 	ForcedSimID map[string]int
+	// ForcedQueue maps mode -> simIDs forced for the spins after ForcedSimID, in order
+	ForcedQueue map[string][]int
 	// RTPBias is an exponent that biases sampling toward higher payouts.
 	// 0.0 = normal RTP, positive values boost high payouts (e.g., 0.5 = moderate boost, 1.0 = strong boost)
 	// The weight for each outcome is multiplied by payout^RTPBias
 	RTPBias float64
+	// Variants maps mode -> name of the in-memory table variant played
+	// instead of the table on disk (see lut.Loader.CreateVariant)
+	Variants map[string]string
+	// Scenario holds the remaining scripted steps, consumed by plays after
+	// any forced simID (see ScenarioStep)
+	Scenario []ScenarioStep
+	// Returns maps mode -> the per-spin returns behind the RTP convergence
+	// stats (see Convergence)
+	Returns map[string]*modeReturns
+
+	// mu is held while a streamed batch play changes the session, and by
+	// readers outside the request that owns it (summaries, persistence)
+	mu sync.Mutex
+	// busy is write-locked by a streamed batch play for its whole run.
+	// Requests that change the session read-lock it (see
+	// Handlers.claimSession), so the stream waits for them and they are
+	// refused while it runs.
+	busy sync.RWMutex
+}
+
+// touch records activity on the session
+func (s *SessionData) touch() {
+	s.mu.Lock()
+	s.LastActivity = time.Now()
+	s.mu.Unlock()
 }
 
 // NextBetID returns the simID as the bet ID
@@ -89,6 +118,7 @@ func (s *SessionData) ClearStats() {
 	s.TotalWins = 0
 	s.TotalWagered = 0
 	s.TotalWon = 0
+	s.Returns = nil
 }
 
 // SetForcedSimID sets a specific simID to be used for the next play in a mode
@@ -100,18 +130,65 @@ func (s *SessionData) SetForcedSimID(mode string, simID int) {
 	s.ForcedSimID[strings.ToLower(mode)] = simID
 }
 
-// ConsumeForcedSimID returns and clears the forced simID for a mode
+// SetVariant selects the table variant played in a mode; an empty name
+// switches back to the table on disk
+func (s *SessionData) SetVariant(mode, name string) {
+	if s.Variants == nil {
+		s.Variants = make(map[string]string)
+	}
+	modeLower := strings.ToLower(mode)
+	if name == "" {
+		delete(s.Variants, modeLower)
+		return
+	}
+	s.Variants[modeLower] = name
+}
+
+// Variant returns the table variant selected for a mode, or ""
+func (s *SessionData) Variant(mode string) string {
+	return s.Variants[strings.ToLower(mode)]
+}
+
+// GetVariants returns a copy of the selected variants
+func (s *SessionData) GetVariants() map[string]string {
+	result := make(map[string]string, len(s.Variants))
+	for mode, name := range s.Variants {
+		result[mode] = name
+	}
+	return result
+}
+
+// SetForcedSequence queues simIDs to be used, in order, for the next plays in a mode
+func (s *SessionData) SetForcedSequence(mode string, simIDs []int) {
+	if s.ForcedQueue == nil {
+		s.ForcedQueue = make(map[string][]int)
+	}
+	modeLower := strings.ToLower(mode)
+	if len(simIDs) == 0 {
+		delete(s.ForcedQueue, modeLower)
+		return
+	}
+	s.ForcedQueue[modeLower] = append([]int(nil), simIDs...)
+}
+
+// ConsumeForcedSimID returns and clears the forced simID for a mode, falling
+// back to the head of the forced queue
 // Returns simID and true if set, 0 and false otherwise
 func (s *SessionData) ConsumeForcedSimID(mode string) (int, bool) {
-	if s.ForcedSimID == nil {
-		return 0, false
-	}
 	modeLower := strings.ToLower(mode)
-	simID, ok := s.ForcedSimID[modeLower]
-	if ok {
+	if simID, ok := s.ForcedSimID[modeLower]; ok {
 		delete(s.ForcedSimID, modeLower)
+		return simID, true
 	}
-	return simID, ok
+	if queue := s.ForcedQueue[modeLower]; len(queue) > 0 {
+		if len(queue) == 1 {
+			delete(s.ForcedQueue, modeLower)
+		} else {
+			s.ForcedQueue[modeLower] = queue[1:]
+		}
+		return queue[0], true
+	}
+	return 0, false
 }
 
 // GetForcedSimID returns the forced simID for a mode without consuming it
@@ -123,19 +200,26 @@ func (s *SessionData) GetForcedSimID(mode string) (int, bool) {
 	return simID, ok
 }
 
-// ClearForcedSimID clears the forced simID for a mode
+// ClearForcedSimID clears the forced simID and queue for a mode
 func (s *SessionData) ClearForcedSimID(mode string) {
-	if s.ForcedSimID != nil {
-		delete(s.ForcedSimID, strings.ToLower(mode))
-	}
+	delete(s.ForcedSimID, strings.ToLower(mode))
+	delete(s.ForcedQueue, strings.ToLower(mode))
+}
+
+// ClearAllForced clears forced simIDs and queues of every mode
+func (s *SessionData) ClearAllForced() {
+	s.ForcedSimID = nil
+	s.ForcedQueue = nil
 }
 
-// GetAllForcedSimIDs returns all forced simIDs
+// GetAllForcedSimIDs returns the next forced simID of every mode
 func (s *SessionData) GetAllForcedSimIDs() map[string]int {
-	if s.ForcedSimID == nil {
-		return make(map[string]int)
+	result := make(map[string]int, len(s.ForcedSimID)+len(s.ForcedQueue))
+	for k, queue := range s.ForcedQueue {
+		if len(queue) > 0 {
+			result[k] = queue[0]
+		}
 	}
-	result := make(map[string]int, len(s.ForcedSimID))
 	for k, v := range s.ForcedSimID {
 		result[k] = v
 	}
@@ -147,6 +231,7 @@ type SessionManager struct {
 	sessions map[string]*SessionData
 	mu       sync.RWMutex
 	counter  atomic.Int64
+	db       *SessionDB // optional persistence
 }
 
 // NewSessionManager creates a new session manager
@@ -156,13 +241,54 @@ func NewSessionManager() *SessionManager {
 	}
 }
 
+// SetDB persists sessions to db from now on and loads the sessions stored
+// in it, replacing in-memory sessions with the same ID
+func (sm *SessionManager) SetDB(db *SessionDB) (int, error) {
+	stored, err := db.LoadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	for _, session := range stored {
+		sm.sessions[session.SessionID] = session
+	}
+	sm.db = db
+	return len(stored), nil
+}
+
+// persistLocked saves a session if persistence is enabled. Caller must hold sm.mu.
+func (sm *SessionManager) persistLocked(session *SessionData) {
+	if sm.db == nil {
+		return
+	}
+	session.mu.Lock()
+	err := sm.db.Save(session)
+	session.mu.Unlock()
+	if err != nil {
+		fmt.Printf("[LGS] Warning: %v\n", err)
+	}
+}
+
+// forgetLocked deletes a stored session if persistence is enabled. Caller must hold sm.mu.
+func (sm *SessionManager) forgetLocked(sessionID string) {
+	if sm.db == nil {
+		return
+	}
+	if err := sm.db.Delete(sessionID); err != nil {
+		fmt.Printf("[LGS] Warning: %v\n", err)
+	}
+}
+
 // GetOrCreate gets existing session or creates a new one
 func (sm *SessionManager) GetOrCreate(sessionID string) *SessionData {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	if session, ok := sm.sessions[sessionID]; ok {
-		session.LastActivity = time.Now()
+		session.touch()
 		return session
 	}
 
@@ -178,6 +304,7 @@ func (sm *SessionManager) GetOrCreate(sessionID string) *SessionData {
 
 	sm.sessions[sessionID] = session
 	sm.counter.Add(1)
+	sm.persistLocked(session)
 
 	return session
 }
@@ -195,8 +322,9 @@ func (sm *SessionManager) Update(session *SessionData) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	session.LastActivity = time.Now()
+	session.touch()
 	sm.sessions[session.SessionID] = session
+	sm.persistLocked(session)
 }
 
 // Delete removes a session
@@ -205,6 +333,7 @@ func (sm *SessionManager) Delete(sessionID string) {
 	defer sm.mu.Unlock()
 
 	delete(sm.sessions, sessionID)
+	sm.forgetLocked(sessionID)
 }
 
 // Count returns the number of active sessions
@@ -227,25 +356,34 @@ func (sm *SessionManager) GetAll() []*SessionData {
 	return sessions
 }
 
+// Snapshot returns copies of all sessions, which can be read while the
+// sessions keep changing
+func (sm *SessionManager) Snapshot() []*SessionData {
+	sessions := sm.GetAll()
+	copies := make([]*SessionData, 0, len(sessions))
+	for _, session := range sessions {
+		session.mu.Lock()
+		data, err := json.Marshal(session)
+		session.mu.Unlock()
+		if err != nil {
+			fmt.Printf("[LGS] Warning: snapshot of session %s: %v\n", session.SessionID, err)
+			continue
+		}
+		var copied SessionData
+		if err := json.Unmarshal(data, &copied); err != nil {
+			fmt.Printf("[LGS] Warning: snapshot of session %s: %v\n", session.SessionID, err)
+			continue
+		}
+		copies = append(copies, &copied)
+	}
+	return copies
+}
+
 // TotalCreated returns total number of sessions created
 func (sm *SessionManager) TotalCreated() int64 {
 	return sm.counter.Load()
 }
 
-// ResetBalance resets session balance to default
-func (sm *SessionManager) ResetBalance(sessionID string) *SessionData {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
-	if session, ok := sm.sessions[sessionID]; ok {
-		session.Balance = DefaultBalance
-		session.LastActivity = time.Now()
-		return session
-	}
-
-	return nil
-}
-
 // CleanupInactive removes sessions inactive for more than the given duration
 func (sm *SessionManager) CleanupInactive(maxAge time.Duration) int {
 	sm.mu.Lock()
@@ -255,8 +393,12 @@ func (sm *SessionManager) CleanupInactive(maxAge time.Duration) int {
 	cleaned := 0
 
 	for id, session := range sm.sessions {
-		if session.LastActivity.Before(cutoff) {
+		session.mu.Lock()
+		inactive := session.LastActivity.Before(cutoff)
+		session.mu.Unlock()
+		if inactive {
 			delete(sm.sessions, id)
+			sm.forgetLocked(id)
 			cleaned++
 		}
 	}
@@ -0,0 +1,186 @@
+package lgs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // pure Go driver, keeps CGO_ENABLED=0 builds working
+)
+
+const sessionSchema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	session_id     TEXT PRIMARY KEY,
+	balance        INTEGER NOT NULL,
+	currency       TEXT NOT NULL,
+	language       TEXT NOT NULL,
+	bet_id_counter INTEGER NOT NULL,
+	created_at     TEXT NOT NULL,
+	last_activity  TEXT NOT NULL,
+	total_bets     INTEGER NOT NULL,
+	total_wins     INTEGER NOT NULL,
+	total_wagered  INTEGER NOT NULL,
+	total_won      INTEGER NOT NULL,
+	rtp_bias       REAL NOT NULL,
+	forced_sim_ids TEXT NOT NULL, -- JSON object mode -> simID
+	forced_queue   TEXT NOT NULL, -- JSON object mode -> [simID]
+	last_round     TEXT,          -- JSON RoundInfo
+	history        TEXT NOT NULL  -- JSON array of RoundInfo, oldest first
+);`
+
+// SessionDB persists sessions to a SQLite file so they survive restarts
+// and can be inspected offline with any SQLite client.
+type SessionDB struct {
+	db *sql.DB
+	// shared databases belong to their opener and are not closed here
+	shared bool
+}
+
+// OpenSessionDB opens (creating if needed) a session database.
+func OpenSessionDB(path string) (*SessionDB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session db: %w", err)
+	}
+	// One writer at a time; SQLite serializes writes anyway
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000", sessionSchema} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize session db: %w", err)
+		}
+	}
+	return &SessionDB{db: db}, nil
+}
+
+// NewSessionDB keeps sessions in an already open database, e.g. the data
+// directory store, creating the sessions table if needed. Close leaves the
+// database open.
+func NewSessionDB(db *sql.DB) (*SessionDB, error) {
+	if _, err := db.Exec(sessionSchema); err != nil {
+		return nil, fmt.Errorf("failed to initialize session db: %w", err)
+	}
+	return &SessionDB{db: db, shared: true}, nil
+}
+
+// Close closes the database.
+func (d *SessionDB) Close() error {
+	if d.shared {
+		return nil
+	}
+	return d.db.Close()
+}
+
+// Save inserts or replaces a session.
+func (d *SessionDB) Save(s *SessionData) error {
+	forced, err := json.Marshal(nonNilMap(s.ForcedSimID))
+	if err != nil {
+		return err
+	}
+	queue, err := json.Marshal(nonNilQueue(s.ForcedQueue))
+	if err != nil {
+		return err
+	}
+	history, err := json.Marshal(s.History)
+	if err != nil {
+		return err
+	}
+	var lastRound []byte
+	if s.LastRound != nil {
+		if lastRound, err = json.Marshal(s.LastRound); err != nil {
+			return err
+		}
+	}
+
+	_, err = d.db.Exec(`INSERT OR REPLACE INTO sessions (
+		session_id, balance, currency, language, bet_id_counter, created_at, last_activity,
+		total_bets, total_wins, total_wagered, total_won, rtp_bias,
+		forced_sim_ids, forced_queue, last_round, history
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.SessionID, s.Balance, s.Currency, s.Language, s.BetIDCounter,
+		s.CreatedAt.UTC().Format(time.RFC3339Nano), s.LastActivity.UTC().Format(time.RFC3339Nano),
+		s.TotalBets, s.TotalWins, s.TotalWagered, s.TotalWon, s.RTPBias,
+		string(forced), string(queue), nullableString(lastRound), string(history))
+	if err != nil {
+		return fmt.Errorf("failed to save session %s: %w", s.SessionID, err)
+	}
+	return nil
+}
+
+// Delete removes a session.
+func (d *SessionDB) Delete(sessionID string) error {
+	if _, err := d.db.Exec(`DELETE FROM sessions WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// LoadAll reads every stored session.
+func (d *SessionDB) LoadAll() ([]*SessionData, error) {
+	rows, err := d.db.Query(`SELECT
+		session_id, balance, currency, language, bet_id_counter, created_at, last_activity,
+		total_bets, total_wins, total_wagered, total_won, rtp_bias,
+		forced_sim_ids, forced_queue, last_round, history
+	FROM sessions`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*SessionData
+	for rows.Next() {
+		var s SessionData
+		var createdAt, lastActivity, forced, queue, history string
+		var lastRound sql.NullString
+		if err := rows.Scan(&s.SessionID, &s.Balance, &s.Currency, &s.Language, &s.BetIDCounter,
+			&createdAt, &lastActivity, &s.TotalBets, &s.TotalWins, &s.TotalWagered, &s.TotalWon,
+			&s.RTPBias, &forced, &queue, &lastRound, &history); err != nil {
+			return nil, fmt.Errorf("failed to load sessions: %w", err)
+		}
+		s.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		s.LastActivity, _ = time.Parse(time.RFC3339Nano, lastActivity)
+		if err := json.Unmarshal([]byte(forced), &s.ForcedSimID); err != nil {
+			return nil, fmt.Errorf("session %s: corrupt forced outcomes: %w", s.SessionID, err)
+		}
+		if err := json.Unmarshal([]byte(queue), &s.ForcedQueue); err != nil {
+			return nil, fmt.Errorf("session %s: corrupt forced queue: %w", s.SessionID, err)
+		}
+		if err := json.Unmarshal([]byte(history), &s.History); err != nil {
+			return nil, fmt.Errorf("session %s: corrupt history: %w", s.SessionID, err)
+		}
+		if s.History == nil {
+			s.History = make([]RoundInfo, 0)
+		}
+		if lastRound.Valid {
+			var round RoundInfo
+			if err := json.Unmarshal([]byte(lastRound.String), &round); err != nil {
+				return nil, fmt.Errorf("session %s: corrupt last round: %w", s.SessionID, err)
+			}
+			s.LastRound = &round
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, rows.Err()
+}
+
+func nonNilMap(m map[string]int) map[string]int {
+	if m == nil {
+		return map[string]int{}
+	}
+	return m
+}
+
+func nonNilQueue(m map[string][]int) map[string][]int {
+	if m == nil {
+		return map[string][]int{}
+	}
+	return m
+}
+
+func nullableString(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
@@ -0,0 +1,128 @@
+package lgs
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSeriesInterval is the width of one time series bucket
+	DefaultSeriesInterval = 10 * time.Second
+	// DefaultSeriesCapacity is how many buckets are kept (24h at 10s)
+	DefaultSeriesCapacity = 8640
+)
+
+// StatsPoint aggregates all plays across sessions within one interval,
+// plus running totals since the backend started.
+type StatsPoint struct {
+	Time    int64   `json:"time"` // bucket start, unix ms
+	Bets    int64   `json:"bets"`
+	Wins    int64   `json:"wins"`
+	Wagered int64   `json:"wagered"`
+	Won     int64   `json:"won"`
+	RTP     float64 `json:"rtp"`
+	HitRate float64 `json:"hitRate"`
+
+	CumulativeBets    int64   `json:"cumulativeBets"`
+	CumulativeWagered int64   `json:"cumulativeWagered"`
+	CumulativeWon     int64   `json:"cumulativeWon"`
+	CumulativeRTP     float64 `json:"cumulativeRTP"`
+}
+
+// StatsSeries is a ring buffer of StatsPoints. Intervals without plays
+// have no point.
+type StatsSeries struct {
+	mu       sync.Mutex
+	interval time.Duration
+	points   []StatsPoint
+	next     int  // ring position of the next new point
+	full     bool // ring has wrapped
+
+	bets, wagered, won int64
+}
+
+// NewStatsSeries creates a series keeping capacity buckets of interval each.
+func NewStatsSeries(interval time.Duration, capacity int) *StatsSeries {
+	return &StatsSeries{
+		interval: interval,
+		points:   make([]StatsPoint, capacity),
+	}
+}
+
+// Record adds plays to the bucket containing now.
+func (s *StatsSeries) Record(now time.Time, bets, wins, wagered, won int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bets += bets
+	s.wagered += wagered
+	s.won += won
+
+	start := now.Truncate(s.interval).UnixMilli()
+	p := s.lastLocked()
+	if p == nil || p.Time != start {
+		s.points[s.next] = StatsPoint{Time: start}
+		p = &s.points[s.next]
+		s.next++
+		if s.next == len(s.points) {
+			s.next = 0
+			s.full = true
+		}
+	}
+
+	p.Bets += bets
+	p.Wins += wins
+	p.Wagered += wagered
+	p.Won += won
+	if p.Wagered > 0 {
+		p.RTP = float64(p.Won) / float64(p.Wagered)
+	}
+	if p.Bets > 0 {
+		p.HitRate = float64(p.Wins) / float64(p.Bets)
+	}
+	p.CumulativeBets = s.bets
+	p.CumulativeWagered = s.wagered
+	p.CumulativeWon = s.won
+	if s.wagered > 0 {
+		p.CumulativeRTP = float64(s.won) / float64(s.wagered)
+	}
+}
+
+// Since returns the points with a bucket start at or after since (unix ms),
+// oldest first.
+func (s *StatsSeries) Since(since int64) []StatsPoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]StatsPoint, 0)
+	n := s.next
+	start := 0
+	if s.full {
+		n = len(s.points)
+		start = s.next
+	}
+	for i := 0; i < n; i++ {
+		p := s.points[(start+i)%len(s.points)]
+		if p.Time >= since {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Interval returns the bucket width.
+func (s *StatsSeries) Interval() time.Duration {
+	return s.interval
+}
+
+// lastLocked returns the most recent point, or nil. Caller must hold s.mu.
+func (s *StatsSeries) lastLocked() *StatsPoint {
+	if s.next == 0 && !s.full {
+		return nil
+	}
+	i := s.next - 1
+	if i < 0 {
+		i = len(s.points) - 1
+	}
+	return &s.points[i]
+}
@@ -0,0 +1,336 @@
+package lgs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// TransformsFile is the file inside the library that configures how books
+// are reshaped for the game clients it serves.
+const TransformsFile = ".lutexplorer/event_transforms.json"
+
+// Key cases an EventTransform can convert object keys to.
+const (
+	KeyCaseCamel = "camel"
+	KeyCaseSnake = "snake"
+)
+
+// NoTransform selects the untransformed events array, overriding the
+// library default.
+const NoTransform = "none"
+
+// EventTransform reshapes the round state sent to a game client, so one
+// backend can serve client generations that expect different book shapes.
+type EventTransform struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Versions lists client version globs (e.g. "1.*") whose replays use
+	// this transform when none is requested explicitly
+	Versions []string `json:"versions,omitempty"`
+	// KeyCase converts every object key to "camel" or "snake" case; empty
+	// keeps keys as they are in the book
+	KeyCase string `json:"keyCase,omitempty"`
+	// Rename maps book field names, at any depth, to the names the client
+	// expects. Renamed keys are not case converted.
+	Rename map[string]string `json:"rename,omitempty"`
+	// Wrap is a JSON template for the state: the string "$events" is
+	// replaced by the events array and "$book" by the whole book, e.g.
+	// {"round": {"events": "$events"}}. Empty sends the events array.
+	Wrap json.RawMessage `json:"wrap,omitempty"`
+}
+
+// EventTransforms is the transform configuration of a library.
+type EventTransforms struct {
+	// Default is used when a request names no transform and no version
+	// matches; empty sends books untransformed
+	Default    string           `json:"default,omitempty"`
+	Transforms []EventTransform `json:"transforms"`
+}
+
+// Validate checks names, key cases, version globs and wrap templates.
+func (c *EventTransforms) Validate() error {
+	seen := make(map[string]bool, len(c.Transforms))
+	for _, t := range c.Transforms {
+		name := strings.ToLower(t.Name)
+		if name == "" {
+			return fmt.Errorf("transform without name")
+		}
+		if name == NoTransform {
+			return fmt.Errorf("transform name %q is reserved", NoTransform)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate transform %q", t.Name)
+		}
+		seen[name] = true
+		if t.KeyCase != "" && t.KeyCase != KeyCaseCamel && t.KeyCase != KeyCaseSnake {
+			return fmt.Errorf("transform %q: keyCase must be %q or %q", t.Name, KeyCaseCamel, KeyCaseSnake)
+		}
+		for _, pattern := range t.Versions {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("transform %q: invalid version pattern %q", t.Name, pattern)
+			}
+		}
+		if len(t.Wrap) > 0 && !json.Valid(t.Wrap) {
+			return fmt.Errorf("transform %q: wrap is not valid JSON", t.Name)
+		}
+	}
+	if c.Default != "" && !seen[strings.ToLower(c.Default)] {
+		return fmt.Errorf("default transform %q not found", c.Default)
+	}
+	return nil
+}
+
+func (c *EventTransforms) find(name string) *EventTransform {
+	for i := range c.Transforms {
+		if strings.EqualFold(c.Transforms[i].Name, name) {
+			return &c.Transforms[i]
+		}
+	}
+	return nil
+}
+
+// Select picks the transform for a request: the named one, else the first
+// whose versions match the client version, else the default. Returns nil
+// when books are sent untransformed.
+func (c *EventTransforms) Select(name, version string) (*EventTransform, error) {
+	if strings.EqualFold(name, NoTransform) {
+		return nil, nil
+	}
+	if name != "" {
+		if t := c.find(name); t != nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown transform %q", name)
+	}
+	if version != "" {
+		for i, t := range c.Transforms {
+			for _, pattern := range t.Versions {
+				if ok, _ := path.Match(pattern, version); ok {
+					return &c.Transforms[i], nil
+				}
+			}
+		}
+	}
+	if c.Default != "" {
+		return c.find(c.Default), nil
+	}
+	return nil, nil
+}
+
+// Apply builds the client state of a book. A nil transform returns the
+// book's events array as is.
+func (t *EventTransform) Apply(bookJSON json.RawMessage) (json.RawMessage, error) {
+	events := extractEvents(bookJSON)
+	if t == nil || (t.KeyCase == "" && len(t.Rename) == 0 && len(t.Wrap) == 0) {
+		return events, nil
+	}
+
+	eventsValue, err := decodeJSON(events)
+	if err != nil {
+		return nil, fmt.Errorf("invalid events: %w", err)
+	}
+	state := t.rekey(eventsValue)
+
+	if len(t.Wrap) > 0 {
+		template, err := decodeJSON(t.Wrap)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wrap template: %w", err)
+		}
+		var book interface{}
+		if len(bookJSON) > 0 {
+			if book, err = decodeJSON(bookJSON); err != nil {
+				return nil, fmt.Errorf("invalid book: %w", err)
+			}
+			book = t.rekey(book)
+		}
+		state = fillTemplate(template, map[string]interface{}{"$events": state, "$book": book})
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(state); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+// rekey renames and case converts the object keys of a decoded value.
+func (t *EventTransform) rekey(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			out[t.key(key)] = t.rekey(child)
+		}
+		return out
+	case []interface{}:
+		for i, child := range v {
+			v[i] = t.rekey(child)
+		}
+		return v
+	}
+	return value
+}
+
+func (t *EventTransform) key(key string) string {
+	if renamed, ok := t.Rename[key]; ok {
+		return renamed
+	}
+	switch t.KeyCase {
+	case KeyCaseCamel:
+		return camelCase(key)
+	case KeyCaseSnake:
+		return snakeCase(key)
+	}
+	return key
+}
+
+// fillTemplate replaces placeholder strings of a decoded template.
+func fillTemplate(template interface{}, values map[string]interface{}) interface{} {
+	switch v := template.(type) {
+	case string:
+		if value, ok := values[v]; ok {
+			return value
+		}
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = fillTemplate(child, values)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = fillTemplate(child, values)
+		}
+	}
+	return template
+}
+
+// decodeJSON decodes keeping numbers as written, so amounts and IDs survive
+// the round trip unchanged.
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// snakeCase converts "payoutMultiplier" or "simID" to "payout_multiplier"
+// and "sim_id".
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Break before an upper case letter that starts a word: after a
+			// lower case letter or digit, or before a lower case letter in a run
+			if i > 0 && runes[i-1] != '_' && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// camelCase converts "payout_multiplier" to "payoutMultiplier".
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	var b strings.Builder
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i > 0 && b.Len() > 0 {
+			runes := []rune(part)
+			runes[0] = unicode.ToUpper(runes[0])
+			part = string(runes)
+		}
+		b.WriteString(part)
+	}
+	if b.Len() == 0 {
+		return s
+	}
+	return b.String()
+}
+
+// transformStore reads a library's transforms file, re-reading it when it
+// changes on disk.
+type transformStore struct {
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	size    int64
+	config  *EventTransforms
+}
+
+// load returns the transforms of the library in baseDir. No library or a
+// missing file means no transforms.
+func (s *transformStore) load(baseDir string) (*EventTransforms, error) {
+	filePath := filepath.Join(baseDir, filepath.FromSlash(TransformsFile))
+	info, err := os.Stat(filePath)
+	if baseDir == "" || errors.Is(err, os.ErrNotExist) {
+		return &EventTransforms{Transforms: []EventTransform{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config != nil && s.path == filePath && s.modTime.Equal(info.ModTime()) && s.size == info.Size() {
+		return s.config, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transforms: %w", err)
+	}
+	var config EventTransforms
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid transforms file: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid transforms file: %w", err)
+	}
+	if config.Transforms == nil {
+		config.Transforms = []EventTransform{}
+	}
+	s.path, s.modTime, s.size, s.config = filePath, info.ModTime(), info.Size(), &config
+	return &config, nil
+}
+
+// save validates and writes the transforms of the library in baseDir.
+func (s *transformStore) save(baseDir string, config *EventTransforms) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(baseDir, filepath.FromSlash(TransformsFile))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(filePath), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write transforms: %w", err)
+	}
+	s.config = nil
+	return nil
+}
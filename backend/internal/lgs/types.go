@@ -30,6 +30,8 @@ type AuthResponse struct {
 type BalanceInfo struct {
 	Amount   int64  `json:"amount"`
 	Currency string `json:"currency"`
+	// Formatted is the amount for display, e.g. "$1,234.56"
+	Formatted string `json:"formatted,omitempty"`
 }
 
 // JurisdictionInfo represents jurisdiction settings
@@ -86,6 +88,10 @@ type PlayRequest struct {
 type PlayResponse struct {
 	Balance BalanceInfo `json:"balance"`
 	Round   RoundInfo   `json:"round"`
+	// Jackpots are the pool values after the play; JackpotWins the pools it
+	// hit, already included in the round payout
+	Jackpots    []JackpotValue `json:"jackpots,omitempty"`
+	JackpotWins []JackpotWin   `json:"jackpotWins,omitempty"`
 }
 
 // RoundInfo represents a game round
@@ -98,6 +104,9 @@ type RoundInfo struct {
 	State            json.RawMessage `json:"state"`
 	Mode             string          `json:"mode"`
 	Event            interface{}     `json:"event"`
+	// Progress is set for rounds whose book triggers a feature; their
+	// payout is credited on /wallet/end-round
+	Progress *RoundProgress `json:"progress,omitempty"`
 }
 
 // EndRoundRequest for /wallet/end-round
@@ -121,7 +130,8 @@ type EventRequest struct {
 
 // EventResponse for /bet/event - simple format
 type EventResponse struct {
-	Event string `json:"event"`
+	Event string         `json:"event"`
+	Round *RoundProgress `json:"round,omitempty"` // progress of a multi-stage round
 }
 
 // ReplayResponse for /bet/replay/{game}/{version}/{mode}/{event}
@@ -164,6 +174,10 @@ type BatchPlayRequest struct {
 	Amount    int64  `json:"amount"`
 	Currency  string `json:"currency"`
 	Spins     int    `json:"spins"` // Number of spins to play
+	// Stream runs the spins in the background and reports progress over
+	// WebSocket every ProgressEvery spins instead of blocking
+	Stream        bool `json:"stream"`
+	ProgressEvery int  `json:"progressEvery"`
 }
 
 // BatchPlayRound contains result of a single round in batch
@@ -190,25 +204,54 @@ type BatchPlayResponse struct {
 	Balance      BalanceInfo      `json:"balance"`
 	Rounds       []BatchPlayRound `json:"rounds,omitempty"` // Only if spins <= 1000
 	DurationMs   int64            `json:"durationMs"`
+	// Jackpots are the pool values after the batch; JackpotWins the pools
+	// its spins hit, already included in TotalWon
+	Jackpots    []JackpotValue `json:"jackpots,omitempty"`
+	JackpotWins []JackpotWin   `json:"jackpotWins,omitempty"`
+}
+
+// BatchProgress is broadcast while a streamed batch play is running
+type BatchProgress struct {
+	BatchID      string  `json:"batchID"`
+	SessionID    string  `json:"sessionID"`
+	Mode         string  `json:"mode"`
+	SpinsDone    int     `json:"spinsDone"`
+	Spins        int     `json:"spins"`
+	TotalWagered int64   `json:"totalWagered"`
+	TotalWon     int64   `json:"totalWon"`
+	HitCount     int     `json:"hitCount"`
+	HitRate      float64 `json:"hitRate"`
+	RTP          float64 `json:"rtp"`
+	MaxWin       float64 `json:"maxWin"`
+	Balance      int64   `json:"balance"`
+	ElapsedMs    int64   `json:"elapsedMs"`
+}
+
+// BatchCancelRequest for /lgs/batchplay/cancel
+type BatchCancelRequest struct {
+	BatchID string `json:"batchID"`
 }
 
 // SessionSummary contains summary info for a single session
 type SessionSummary struct {
-	SessionID      string         `json:"sessionID"`
-	Balance        int64          `json:"balance"`
-	Currency       string         `json:"currency"`
-	TotalBets      int64          `json:"totalBets"`
-	TotalWins      int64          `json:"totalWins"`
-	TotalWagered   int64          `json:"totalWagered"`
-	TotalWon       int64          `json:"totalWon"`
-	RTP            float64        `json:"rtp"`
-	HitRate        float64        `json:"hitRate"`
-	Profit         int64          `json:"profit"` // totalWagered - totalWon (house profit)
-	HistorySize    int            `json:"historySize"`
-	CreatedAt      string         `json:"createdAt"`
-	LastActivity   string         `json:"lastActivity"`
-	ForcedOutcomes map[string]int `json:"forcedOutcomes"`
-	RTPBias        float64        `json:"rtpBias"`
+	SessionID      string            `json:"sessionID"`
+	Balance        int64             `json:"balance"`
+	Currency       string            `json:"currency"`
+	TotalBets      int64             `json:"totalBets"`
+	TotalWins      int64             `json:"totalWins"`
+	TotalWagered   int64             `json:"totalWagered"`
+	TotalWon       int64             `json:"totalWon"`
+	RTP            float64           `json:"rtp"`
+	HitRate        float64           `json:"hitRate"`
+	Profit         int64             `json:"profit"` // totalWagered - totalWon (house profit)
+	HistorySize    int               `json:"historySize"`
+	CreatedAt      string            `json:"createdAt"`
+	LastActivity   string            `json:"lastActivity"`
+	ForcedOutcomes map[string]int    `json:"forcedOutcomes"`
+	RTPBias        float64           `json:"rtpBias"`
+	Variants       map[string]string `json:"variants,omitempty"` // mode -> table variant
+	// Convergence of the observed RTP per mode, at the default confidence
+	Convergence []Convergence `json:"convergence,omitempty"`
 }
 
 // SessionsResponse for GET /lgs/sessions
@@ -217,6 +260,7 @@ type SessionsResponse struct {
 	TotalSessions  int              `json:"totalSessions"`
 	TotalCreated   int64            `json:"totalCreated"`
 	AggregateStats AggregateStats   `json:"aggregate"`
+	Jackpots       []JackpotValue   `json:"jackpots,omitempty"`
 }
 
 // AggregateStats contains aggregate statistics across all sessions
@@ -228,4 +272,13 @@ type AggregateStats struct {
 	OverallRTP     float64 `json:"overallRTP"`
 	OverallHitRate float64 `json:"overallHitRate"`
 	TotalProfit    int64   `json:"totalProfit"`
+	// Totals converted to BaseCurrency at the configured rates, in
+	// currency units; OverallRTP is computed from them
+	BaseCurrency     string  `json:"baseCurrency"`
+	TotalWageredBase float64 `json:"totalWageredBase"`
+	TotalWonBase     float64 `json:"totalWonBase"`
+	TotalProfitBase  float64 `json:"totalProfitBase"`
+	// UnconvertedSessions play in currencies no longer configured and are
+	// left out of the converted totals
+	UnconvertedSessions int `json:"unconvertedSessions,omitempty"`
 }
@@ -0,0 +1,60 @@
+package logbuf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a log line. The backend logs plain text, so the
+// level is inferred from the message (see Classify).
+type Level string
+
+// Levels from least to most severe.
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
+var levelRank = map[Level]int{LevelDebug: 0, LevelInfo: 1, LevelWarn: 2, LevelError: 3}
+
+// ParseLevel parses a level name; empty means LevelDebug, i.e. every line.
+func ParseLevel(s string) (Level, error) {
+	if s == "" {
+		return LevelDebug, nil
+	}
+	level := Level(strings.ToLower(s))
+	if level == "warning" {
+		level = LevelWarn
+	}
+	if _, ok := levelRank[level]; !ok {
+		return "", fmt.Errorf("unknown log level %q (debug, info, warn, error)", s)
+	}
+	return level, nil
+}
+
+// AtLeast reports whether l is as severe as min or more.
+func (l Level) AtLeast(min Level) bool {
+	return levelRank[l] >= levelRank[min]
+}
+
+// Classify infers the level of a message: request logs are debug,
+// messages mentioning errors, failures or panics are errors, warnings are
+// warn and everything else is info.
+func Classify(msg string) Level {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "[HTTP] "):
+		return LevelDebug
+	case strings.Contains(lower, "warning"):
+		// "Warning: Failed to ..." is a warning, not an error
+		return LevelWarn
+	case strings.Contains(lower, "error") || strings.Contains(lower, "failed") ||
+		strings.Contains(lower, "panic") || strings.Contains(lower, "fatal"):
+		return LevelError
+	case strings.Contains(lower, "warn"):
+		return LevelWarn
+	}
+	return LevelInfo
+}
@@ -0,0 +1,132 @@
+// Package logbuf keeps recent log output in memory so it can be served over HTTP.
+package logbuf
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the number of lines kept when no capacity is given.
+const DefaultCapacity = 2000
+
+// Line is a single captured log line.
+type Line struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	Level   Level     `json:"level"`
+	Message string    `json:"message"`
+}
+
+// Buffer is a fixed-size ring of log lines. It implements io.Writer so it can
+// be combined with the standard logger via io.MultiWriter.
+type Buffer struct {
+	mu      sync.Mutex
+	lines   []Line
+	start   int // index of the oldest line
+	count   int
+	nextSeq uint64
+	partial string // text after the last newline, waiting for the rest
+
+	subs map[chan Line]struct{}
+}
+
+// New creates a buffer holding up to capacity lines.
+func New(capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Buffer{
+		lines:   make([]Line, capacity),
+		nextSeq: 1,
+		subs:    make(map[chan Line]struct{}),
+	}
+}
+
+// Write splits p into lines and appends them. It never fails.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	text := b.partial + string(p)
+	parts := strings.Split(text, "\n")
+	b.partial = parts[len(parts)-1]
+	now := time.Now()
+	for _, msg := range parts[:len(parts)-1] {
+		b.appendLocked(Line{Seq: b.nextSeq, Time: now, Level: Classify(msg), Message: msg})
+		b.nextSeq++
+	}
+	return len(p), nil
+}
+
+func (b *Buffer) appendLocked(line Line) {
+	idx := (b.start + b.count) % len(b.lines)
+	b.lines[idx] = line
+	if b.count < len(b.lines) {
+		b.count++
+	} else {
+		b.start = (b.start + 1) % len(b.lines)
+	}
+
+	for ch := range b.subs {
+		select {
+		case ch <- line:
+		default: // slow subscriber, drop rather than block logging
+		}
+	}
+}
+
+// Since returns up to limit lines with Seq greater than since and a level
+// of at least min, oldest first, plus the sequence number of the newest
+// line. A limit <= 0 returns all.
+func (b *Buffer) Since(since uint64, limit int, min Level) ([]Line, uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := make([]Line, 0)
+	for i := 0; i < b.count; i++ {
+		line := b.lines[(b.start+i)%len(b.lines)]
+		if line.Seq > since && line.Level.AtLeast(min) {
+			result = append(result, line)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, b.nextSeq - 1
+}
+
+// Subscribe returns a channel receiving new lines and a function that
+// unsubscribes and closes it.
+func (b *Buffer) Subscribe() (<-chan Line, func()) {
+	ch := make(chan Line, 256)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// CaptureStdout redirects os.Stdout through a pipe copying everything to
+// the original stdout and to w, so output printed with fmt (e.g. the LGS
+// request log) reaches the buffer like log output does.
+func CaptureStdout(w io.Writer) error {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	out := io.MultiWriter(os.Stdout, w)
+	os.Stdout = pw
+	go io.Copy(out, r)
+	return nil
+}
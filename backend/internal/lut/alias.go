@@ -0,0 +1,182 @@
+package lut
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"stakergs"
+)
+
+// AliasSampler draws weighted outcomes in O(1) per sample using Vose's
+// alias method: each of the n columns holds an outcome and an alias, and a
+// draw picks a column uniformly, then keeps the outcome with the column's
+// probability or takes the alias otherwise. Building the table is O(n).
+//
+// A sampler is immutable and safe for concurrent use with separate RNGs.
+type AliasSampler struct {
+	outcomes []stakergs.Outcome
+	// threshold[i] is the probability of keeping column i, scaled to
+	// [0, 2^64). Full columns alias themselves, so their threshold is moot.
+	threshold []uint64
+	alias     []uint32
+}
+
+// NewAliasSampler builds an alias table from a lookup table's weights.
+// The table must have at least one outcome with a non-zero weight.
+func NewAliasSampler(table *stakergs.LookupTable) *AliasSampler {
+	n := len(table.Outcomes)
+	s := &AliasSampler{
+		outcomes:  table.Outcomes,
+		threshold: make([]uint64, n),
+		alias:     make([]uint32, n),
+	}
+	if n == 0 {
+		return s
+	}
+
+	var total float64
+	for _, o := range table.Outcomes {
+		total += float64(o.Weight)
+	}
+
+	// Scaled probabilities average 1; columns below 1 are topped up by
+	// aliasing into columns above 1.
+	prob := make([]float64, n)
+	small := make([]uint32, 0, n)
+	large := make([]uint32, 0, n)
+	for i, o := range table.Outcomes {
+		prob[i] = float64(o.Weight) * float64(n) / total
+		if prob[i] < 1 {
+			small = append(small, uint32(i))
+		} else {
+			large = append(large, uint32(i))
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+
+		s.threshold[l] = scaleProbability(prob[l])
+		s.alias[l] = g
+		prob[g] -= 1 - prob[l]
+		if prob[g] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, g)
+		}
+	}
+	// What is left is full up to rounding error
+	for _, i := range append(small, large...) {
+		s.threshold[i] = math.MaxUint64
+		s.alias[i] = i
+	}
+	return s
+}
+
+// scaleProbability maps p in [0, 1] to [0, 2^64).
+func scaleProbability(p float64) uint64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return math.MaxUint64
+	}
+	return uint64(p * (1 << 64))
+}
+
+// Len returns the number of outcomes.
+func (s *AliasSampler) Len() int {
+	return len(s.outcomes)
+}
+
+// Index returns the index of the outcome selected by a uniformly random
+// 64-bit value. One value picks both the column (high part of r*n) and the
+// coin flip (low part), so a draw costs a single RNG call.
+func (s *AliasSampler) Index(r uint64) int {
+	col, frac := bits.Mul64(r, uint64(len(s.outcomes)))
+	if frac < s.threshold[col] {
+		return int(col)
+	}
+	return int(s.alias[col])
+}
+
+// Sample returns a random outcome based on weights.
+func (s *AliasSampler) Sample(rng *rand.Rand) stakergs.Outcome {
+	return s.outcomes[s.Index(rng.Uint64())]
+}
+
+// SampleWithSharedRNG returns a random outcome using math/rand's shared,
+// randomly seeded source, which is safe for concurrent use.
+func (s *AliasSampler) SampleWithSharedRNG() stakergs.Outcome {
+	return s.outcomes[s.Index(rand.Uint64())]
+}
+
+// samplerCache keeps one alias sampler per loaded mode. Entries remember
+// the table they were built from, so a replaced table is never served a
+// stale sampler; in-place weight edits must call invalidate.
+type samplerCache struct {
+	mu      sync.Mutex
+	entries map[string]*samplerEntry
+}
+
+type samplerEntry struct {
+	table   *stakergs.LookupTable
+	sampler *AliasSampler
+}
+
+func newSamplerCache() *samplerCache {
+	return &samplerCache{entries: make(map[string]*samplerEntry)}
+}
+
+func (c *samplerCache) get(table *stakergs.LookupTable) *AliasSampler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e := c.entries[table.Mode]; e != nil && e.table == table {
+		return e.sampler
+	}
+	return nil
+}
+
+func (c *samplerCache) put(table *stakergs.LookupTable, sampler *AliasSampler) {
+	c.mu.Lock()
+	c.entries[table.Mode] = &samplerEntry{table: table, sampler: sampler}
+	c.mu.Unlock()
+}
+
+func (c *samplerCache) invalidate(mode string) {
+	c.mu.Lock()
+	for name := range c.entries {
+		if strings.EqualFold(name, mode) {
+			delete(c.entries, name)
+		}
+	}
+	c.mu.Unlock()
+}
+
+func (c *samplerCache) invalidateAll() {
+	c.mu.Lock()
+	c.entries = make(map[string]*samplerEntry)
+	c.mu.Unlock()
+}
+
+// Sampler returns an alias sampler for table. Samplers of the loaded mode
+// tables are cached until the table's weights change (SaveWeights,
+// SetOutcomeWeight) or it is reloaded; other tables, e.g. variants, get a
+// fresh sampler.
+func (l *Loader) Sampler(table *stakergs.LookupTable) *AliasSampler {
+	if s := l.samplers.get(table); s != nil {
+		return s
+	}
+	s := NewAliasSampler(table)
+
+	l.mu.RLock()
+	loaded := l.tables[table.Mode] == table
+	l.mu.RUnlock()
+	if loaded {
+		l.samplers.put(table, s)
+	}
+	return s
+}
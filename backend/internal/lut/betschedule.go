@@ -0,0 +1,97 @@
+package lut
+
+import "fmt"
+
+// Bet schedule types.
+const (
+	BetScheduleCycle       = "cycle"       // levels are used in turn, one per spin
+	BetScheduleProgression = "progression" // move up a level after consecutive wins
+)
+
+// BetSchedule varies the bet per spin to model promotional mechanics.
+// Levels are multiples of the base bet (the mode cost), e.g. [1, 2, 4, 8].
+type BetSchedule struct {
+	Type   string    `json:"type"`
+	Levels []float64 `json:"levels"`
+	// Progression only: consecutive wins needed to move up a level
+	// (staying on the top level), and whether a loss drops back to the first
+	AdvanceAfter int  `json:"advance_after,omitempty"`
+	ResetOnLoss  bool `json:"reset_on_loss,omitempty"`
+}
+
+// BetLevelStats holds wagered/returned accounting for one bet level.
+type BetLevelStats struct {
+	Level    int     `json:"level"`    // index into BetSchedule.Levels
+	Multiple float64 `json:"multiple"` // bet as a multiple of the base bet
+	Spins    int     `json:"spins"`
+	Wagered  float64 `json:"wagered"`
+	Won      float64 `json:"won"`
+	RTP      float64 `json:"rtp"`
+}
+
+// Validate checks the schedule is usable.
+func (b *BetSchedule) Validate() error {
+	if b.Type != BetScheduleCycle && b.Type != BetScheduleProgression {
+		return fmt.Errorf("bet schedule type must be %q or %q", BetScheduleCycle, BetScheduleProgression)
+	}
+	if len(b.Levels) == 0 {
+		return fmt.Errorf("bet schedule needs at least one level")
+	}
+	for i, l := range b.Levels {
+		if l <= 0 {
+			return fmt.Errorf("bet level %d must be positive", i+1)
+		}
+	}
+	if b.Type == BetScheduleProgression && b.AdvanceAfter < 1 {
+		return fmt.Errorf("progression bet schedule needs advance_after >= 1")
+	}
+	return nil
+}
+
+// betState tracks one trial's position in a schedule. A nil schedule always
+// bets level 0 at multiple 1.
+type betState struct {
+	schedule *BetSchedule
+	level    int
+	streak   int
+}
+
+// next returns the level and bet multiple for the given spin.
+func (b *betState) next(spin int) (int, float64) {
+	if b.schedule == nil {
+		return 0, 1
+	}
+	if b.schedule.Type == BetScheduleCycle {
+		b.level = spin % len(b.schedule.Levels)
+	}
+	return b.level, b.schedule.Levels[b.level]
+}
+
+// record advances a progression after a spin's result.
+func (b *betState) record(won bool) {
+	if b.schedule == nil || b.schedule.Type != BetScheduleProgression {
+		return
+	}
+	if !won {
+		b.streak = 0
+		if b.schedule.ResetOnLoss {
+			b.level = 0
+		}
+		return
+	}
+	b.streak++
+	if b.streak >= b.schedule.AdvanceAfter {
+		b.streak = 0
+		if b.level < len(b.schedule.Levels)-1 {
+			b.level++
+		}
+	}
+}
+
+// levelCount returns how many bet levels a schedule has (1 without one).
+func (b *BetSchedule) levelCount() int {
+	if b == nil {
+		return 1
+	}
+	return len(b.Levels)
+}
@@ -0,0 +1,113 @@
+package lut
+
+import (
+	"math"
+	"sort"
+)
+
+// SampleComparison compares one metric between two sets of per-trial values.
+type SampleComparison struct {
+	Metric   string  `json:"metric"`
+	MeanA    float64 `json:"mean_a"`
+	MeanB    float64 `json:"mean_b"`
+	MeanDiff float64 `json:"mean_diff"` // MeanB - MeanA
+	StdError float64 `json:"std_error"` // of the difference (Welch)
+	TStat    float64 `json:"t_stat"`
+	PValue   float64 `json:"p_value"` // two-sided, normal approximation
+	// Two-sample Kolmogorov-Smirnov test on the whole distribution
+	KSStatistic float64 `json:"ks_statistic"`
+	KSPValue    float64 `json:"ks_p_value"`
+}
+
+// CompareSamples runs a difference-in-means (Welch) test and a two-sample
+// KS test on a and b. P-values use large-sample approximations, which hold
+// for the trial counts simulations use (tens of trials and up).
+func CompareSamples(metric string, a, b []float64) SampleComparison {
+	c := SampleComparison{Metric: metric}
+	if len(a) < 2 || len(b) < 2 {
+		return c
+	}
+
+	meanA, varA := meanVariance(a)
+	meanB, varB := meanVariance(b)
+	se := math.Sqrt(varA/float64(len(a)) + varB/float64(len(b)))
+
+	c.MeanA = round6(meanA)
+	c.MeanB = round6(meanB)
+	c.MeanDiff = round6(meanB - meanA)
+	c.StdError = round6(se)
+	c.PValue = 1
+	if se > 0 {
+		t := (meanB - meanA) / se
+		c.TStat = round4(t)
+		c.PValue = round6(math.Erfc(math.Abs(t) / math.Sqrt2))
+	} else if meanA != meanB {
+		c.PValue = 0
+	}
+
+	d := ksStatistic(a, b)
+	c.KSStatistic = round6(d)
+	c.KSPValue = round6(ksPValue(d, len(a), len(b)))
+	return c
+}
+
+// meanVariance returns the mean and sample variance of values.
+func meanVariance(values []float64) (mean, variance float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		d := v - mean
+		variance += d * d
+	}
+	return mean, variance / float64(len(values)-1)
+}
+
+// ksStatistic returns the largest gap between the empirical CDFs of a and b.
+func ksStatistic(a, b []float64) float64 {
+	x := append([]float64(nil), a...)
+	y := append([]float64(nil), b...)
+	sort.Float64s(x)
+	sort.Float64s(y)
+
+	var d float64
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		v := math.Min(x[i], y[j])
+		for i < len(x) && x[i] <= v {
+			i++
+		}
+		for j < len(y) && y[j] <= v {
+			j++
+		}
+		gap := math.Abs(float64(i)/float64(len(x)) - float64(j)/float64(len(y)))
+		if gap > d {
+			d = gap
+		}
+	}
+	return d
+}
+
+// ksPValue is the asymptotic Kolmogorov distribution tail for statistic d
+// with sample sizes n and m (Numerical Recipes' effective-n correction).
+func ksPValue(d float64, n, m int) float64 {
+	ne := float64(n) * float64(m) / float64(n+m)
+	sqrtNe := math.Sqrt(ne)
+	lambda := (sqrtNe + 0.12 + 0.11/sqrtNe) * d
+	if lambda < 1e-3 {
+		return 1
+	}
+
+	var sum float64
+	sign := 1.0
+	for k := 1; k <= 100; k++ {
+		term := sign * 2 * math.Exp(-2*float64(k*k)*lambda*lambda)
+		sum += term
+		if math.Abs(term) < 1e-10 {
+			return math.Max(0, math.Min(1, sum))
+		}
+		sign = -sign
+	}
+	return 1 // series didn't converge: lambda is tiny, distributions indistinguishable
+}
@@ -4,6 +4,8 @@ package lut
 import (
 	"fmt"
 
+	"lutexplorer/internal/numfmt"
+
 	"stakergs"
 )
 
@@ -38,6 +40,7 @@ type ComplianceCheck struct {
 // ComplianceResult contains all compliance check results for a mode.
 type ComplianceResult struct {
 	Mode         string             `json:"mode"`
+	Profile      string             `json:"profile"`
 	Passed       bool               `json:"passed"`
 	PassedCount  int                `json:"passed_count"`
 	FailedCount  int                `json:"failed_count"`
@@ -62,6 +65,7 @@ type ComplianceSummary struct {
 // AllModesComplianceResult contains compliance results for all modes.
 type AllModesComplianceResult struct {
 	AllPassed   bool                        `json:"all_passed"`
+	Profile     string                      `json:"profile"`
 	ModeResults map[string]*ComplianceResult `json:"mode_results"`
 	GlobalChecks []ComplianceCheck           `json:"global_checks"`
 }
@@ -69,23 +73,46 @@ type AllModesComplianceResult struct {
 // ComplianceChecker performs compliance checks on LUT tables.
 type ComplianceChecker struct {
 	analyzer *Analyzer
+	profile  ComplianceProfile
+	locale   *numfmt.Locale // formats the Value and Expected strings
 }
 
-// NewComplianceChecker creates a new compliance checker.
+// NewComplianceChecker creates a new compliance checker using the default
+// profile.
 func NewComplianceChecker() *ComplianceChecker {
+	profile, _ := GetComplianceProfile(DefaultComplianceProfile)
+	return NewComplianceCheckerWithProfile(profile)
+}
+
+// NewComplianceCheckerWithProfile creates a compliance checker that applies
+// the given profile's thresholds and severities.
+func NewComplianceCheckerWithProfile(profile ComplianceProfile) *ComplianceChecker {
 	return &ComplianceChecker{
 		analyzer: NewAnalyzer(),
+		profile:  profile,
+		locale:   numfmt.English,
 	}
 }
 
+// SetLocale sets the locale numbers in check values are formatted for.
+func (c *ComplianceChecker) SetLocale(locale *numfmt.Locale) {
+	c.locale = locale
+}
+
+// Profile returns the profile the checker applies.
+func (c *ComplianceChecker) Profile() ComplianceProfile {
+	return c.profile
+}
+
 // CheckMode performs all compliance checks on a single mode.
 func (c *ComplianceChecker) CheckMode(lut *stakergs.LookupTable) *ComplianceResult {
 	stats := c.analyzer.Analyze(lut)
 	totalWeight := lut.TotalWeight()
 
 	result := &ComplianceResult{
-		Mode:   lut.Mode,
-		Checks: make([]ComplianceCheck, 0),
+		Mode:    lut.Mode,
+		Profile: c.profile.Name,
+		Checks:  make([]ComplianceCheck, 0),
 		Summary: ComplianceSummary{
 			RTP:           stats.RTP,
 			HitRate:       stats.HitRate,
@@ -101,21 +128,28 @@ func (c *ComplianceChecker) CheckMode(lut *stakergs.LookupTable) *ComplianceResu
 	result.Summary.MaxPayoutHitRate = c.calculateMaxPayoutHitRate(lut, totalWeight)
 	result.Summary.MostFrequentProb, _ = c.calculateMostFrequentProbability(lut, totalWeight)
 
-	// Run all checks
-	result.Checks = append(result.Checks, c.checkRTPRange(stats))
-	result.Checks = append(result.Checks, c.checkMaxWinAchievable(lut, totalWeight, stats))
-	result.Checks = append(result.Checks, c.checkHitRateReasonable(lut, stats))
-	result.Checks = append(result.Checks, c.checkPayoutGaps(lut, stats))
-	result.Checks = append(result.Checks, c.checkUniquePayouts(lut))
-	result.Checks = append(result.Checks, c.checkSimulationDiversity(lut, totalWeight))
-	result.Checks = append(result.Checks, c.checkZeroPayoutRate(stats))
-	result.Checks = append(result.Checks, c.checkVolatility(stats))
-
-	// Calculate totals
+	// Run all checks, with severities mapped by the profile
+	checks := []ComplianceCheck{
+		c.checkRTPRange(stats),
+		c.checkMaxWinAchievable(lut, totalWeight, stats),
+		c.checkHitRateReasonable(lut, stats),
+		c.checkPayoutGaps(lut, stats),
+		c.checkUniquePayouts(lut),
+		c.checkSimulationDiversity(lut, totalWeight),
+		c.checkZeroPayoutRate(stats),
+		c.checkVolatility(stats),
+	}
+	for _, check := range checks {
+		if c.profile.applySeverity(&check) {
+			result.Checks = append(result.Checks, check)
+		}
+	}
+
+	// Calculate totals; only failed error-severity checks fail the mode
 	for _, check := range result.Checks {
 		if check.Passed {
 			result.PassedCount++
-		} else if check.Severity == "warning" {
+		} else if check.Severity != SeverityError {
 			result.WarningCount++
 		} else {
 			result.FailedCount++
@@ -130,6 +164,7 @@ func (c *ComplianceChecker) CheckMode(lut *stakergs.LookupTable) *ComplianceResu
 // CheckAllModes performs compliance checks on all modes and cross-mode checks.
 func (c *ComplianceChecker) CheckAllModes(tables map[string]*stakergs.LookupTable) *AllModesComplianceResult {
 	result := &AllModesComplianceResult{
+		Profile:      c.profile.Name,
 		ModeResults:  make(map[string]*ComplianceResult),
 		GlobalChecks: make([]ComplianceCheck, 0),
 		AllPassed:    true,
@@ -138,19 +173,23 @@ func (c *ComplianceChecker) CheckAllModes(tables map[string]*stakergs.LookupTabl
 	// Compute base RTP first (needed for per-mode checks)
 	baseRTP, baseModeName := c.findBaseRTP(tables)
 
+	// RTP variation only applies with several modes, unless the profile turns it off
+	checkVariation := len(tables) > 1 && c.profile.Severities[CheckRTPVariation] != SeverityOff
+
 	// Check each mode individually
 	for mode, lut := range tables {
 		modeResult := c.CheckMode(lut)
 
 		// Add per-mode RTP variation check if we have multiple modes
-		if len(tables) > 1 {
+		if checkVariation {
 			rtpCheck := c.checkModeRTPVariation(lut, baseRTP, baseModeName)
+			c.profile.applySeverity(&rtpCheck)
 			modeResult.Checks = append(modeResult.Checks, rtpCheck)
 
 			// Update counts
 			if rtpCheck.Passed {
 				modeResult.PassedCount++
-			} else if rtpCheck.Severity == "warning" {
+			} else if rtpCheck.Severity != SeverityError {
 				modeResult.WarningCount++
 			} else {
 				modeResult.FailedCount++
@@ -165,8 +204,9 @@ func (c *ComplianceChecker) CheckAllModes(tables map[string]*stakergs.LookupTabl
 	}
 
 	// Global cross-mode RTP variation check (summary)
-	if len(tables) > 1 {
+	if checkVariation {
 		rtpCheck := c.checkRTPVariationGlobal(tables, baseRTP, baseModeName)
+		c.profile.applySeverity(&rtpCheck)
 		result.GlobalChecks = append(result.GlobalChecks, rtpCheck)
 		if !rtpCheck.Passed && rtpCheck.Severity == "error" {
 			result.AllPassed = false
@@ -200,7 +240,7 @@ func (c *ComplianceChecker) findBaseRTP(tables map[string]*stakergs.LookupTable)
 
 // checkModeRTPVariation checks if a single mode's RTP is within allowed range of base RTP.
 func (c *ComplianceChecker) checkModeRTPVariation(lut *stakergs.LookupTable, baseRTP float64, baseModeName string) ComplianceCheck {
-	maxVariation := 0.005 // 0.5%
+	maxVariation := c.profile.MaxRTPVariation
 	minAllowed := baseRTP - maxVariation
 	maxAllowed := baseRTP + maxVariation
 
@@ -216,8 +256,8 @@ func (c *ComplianceChecker) checkModeRTPVariation(lut *stakergs.LookupTable, bas
 		ID:             CheckRTPVariation,
 		NameKey:        "compliance.checks.rtpVariation.name",
 		DescriptionKey: "compliance.checks.rtpVariation.description",
-		Expected:       fmt.Sprintf("%.2f%% - %.2f%%", minAllowed*100, maxAllowed*100),
-		Value:          fmt.Sprintf("%.2f%% (deviation: %.2f%%)", modeRTP*100, deviation*100),
+		Expected:       c.locale.Percent(minAllowed, 2) + " - " + c.locale.Percent(maxAllowed, 2),
+		Value:          fmt.Sprintf("%s (deviation: %s)", c.locale.Percent(modeRTP, 2), c.locale.Percent(deviation, 2)),
 		Severity:       "error",
 		Details: map[string]interface{}{
 			"base_mode":   baseModeName,
@@ -244,15 +284,15 @@ func (c *ComplianceChecker) checkModeRTPVariation(lut *stakergs.LookupTable, bas
 }
 
 func (c *ComplianceChecker) checkRTPRange(stats *Statistics) ComplianceCheck {
-	minRTP := 0.90
-	maxRTP := 0.98
+	minRTP := c.profile.MinRTP
+	maxRTP := c.profile.MaxRTP
 
 	check := ComplianceCheck{
 		ID:             CheckRTPRange,
 		NameKey:        "compliance.checks.rtpRange.name",
 		DescriptionKey: "compliance.checks.rtpRange.description",
-		Expected:       fmt.Sprintf("%.1f%% - %.1f%%", minRTP*100, maxRTP*100),
-		Value:          fmt.Sprintf("%.2f%%", stats.RTP*100),
+		Expected:       c.locale.Percent(minRTP, 1) + " - " + c.locale.Percent(maxRTP, 1),
+		Value:          c.locale.Percent(stats.RTP, 2),
 		Severity:       "error",
 	}
 
@@ -272,7 +312,7 @@ func (c *ComplianceChecker) checkRTPRange(stats *Statistics) ComplianceCheck {
 
 // checkRTPVariationGlobal creates a global summary of RTP variation across all modes.
 func (c *ComplianceChecker) checkRTPVariationGlobal(tables map[string]*stakergs.LookupTable, baseRTP float64, baseModeName string) ComplianceCheck {
-	maxVariation := 0.005 // 0.5%
+	maxVariation := c.profile.MaxRTPVariation
 	minAllowed := baseRTP - maxVariation
 	maxAllowed := baseRTP + maxVariation
 
@@ -303,7 +343,7 @@ func (c *ComplianceChecker) checkRTPVariationGlobal(tables map[string]*stakergs.
 		ID:             CheckRTPVariation,
 		NameKey:        "compliance.checks.rtpVariationGlobal.name",
 		DescriptionKey: "compliance.checks.rtpVariationGlobal.description",
-		Expected:       fmt.Sprintf("%.2f%% - %.2f%%", minAllowed*100, maxAllowed*100),
+		Expected:       c.locale.Percent(minAllowed, 2) + " - " + c.locale.Percent(maxAllowed, 2),
 		Value:          fmt.Sprintf("%d/%d modes passed", passedModes, totalModes),
 		Severity:       "error",
 		Details: map[string]interface{}{
@@ -330,10 +370,10 @@ func (c *ComplianceChecker) checkRTPVariationGlobal(tables map[string]*stakergs.
 }
 
 func (c *ComplianceChecker) checkMaxWinAchievable(lut *stakergs.LookupTable, totalWeight uint64, stats *Statistics) ComplianceCheck {
-	// Max win should be achievable with hit-rate of at least 1 in 20,000,000 for base mode (cost=1)
-	// For bonus modes with higher cost, the threshold is adjusted: 20,000,000 / cost
+	// Max win should be achievable with hit-rate of at least 1 in MaxWinOdds (20,000,000 by default) for base mode (cost=1)
+	// For bonus modes with higher cost, the threshold is adjusted: MaxWinOdds / cost
 	// Example: bonus with cost=200x -> maxOdds = 20,000,000 / 200 = 100,000
-	baseMaxOdds := 20_000_000.0
+	baseMaxOdds := c.profile.MaxWinOdds
 	cost := lut.Cost
 	if cost <= 0 {
 		cost = 1.0
@@ -354,8 +394,8 @@ func (c *ComplianceChecker) checkMaxWinAchievable(lut *stakergs.LookupTable, tot
 		ID:             CheckMaxWinAchievable,
 		NameKey:        "compliance.checks.maxWinAchievable.name",
 		DescriptionKey: "compliance.checks.maxWinAchievable.description",
-		Expected:       fmt.Sprintf("Odds ≤ 1 in %s", formatLargeNumber(maxOdds)),
-		Value:          fmt.Sprintf("1 in %s", formatLargeNumber(actualOdds)),
+		Expected:       "Odds ≤ 1 in " + c.locale.Compact(maxOdds),
+		Value:          "1 in " + c.locale.Compact(actualOdds),
 		Severity:       "error",
 		Details: map[string]interface{}{
 			"max_payout":        stats.MaxPayout,
@@ -393,15 +433,15 @@ func (c *ComplianceChecker) checkHitRateReasonable(lut *stakergs.LookupTable, st
 			NameKey:        "compliance.checks.hitRate.name",
 			DescriptionKey: "compliance.checks.hitRate.descriptionSkipped",
 			Expected:       "N/A (bonus mode)",
-			Value:          fmt.Sprintf("%.2f%% (1 in %.2f)", stats.HitRate*100, 1.0/stats.HitRate),
+			Value:          fmt.Sprintf("%s (1 in %s)", c.locale.Percent(stats.HitRate, 2), c.locale.Number(1.0/stats.HitRate, 2)),
 			Severity:       "info",
 			Passed:         true,
 		}
 	}
 
-	// For base modes: hit rate should be between 1 in 3 and 1 in 20
-	minHitRate := 0.05 // 1 in 20
-	maxHitRate := 0.33 // 1 in 3
+	// For base modes: hit rate should be within the profile bounds (1 in 20 - 1 in 3 by default)
+	minHitRate := c.profile.MinHitRate
+	maxHitRate := c.profile.MaxHitRate
 
 	odds := 1.0 / stats.HitRate
 
@@ -409,8 +449,8 @@ func (c *ComplianceChecker) checkHitRateReasonable(lut *stakergs.LookupTable, st
 		ID:             CheckHitRateReasonable,
 		NameKey:        "compliance.checks.hitRate.name",
 		DescriptionKey: "compliance.checks.hitRate.description",
-		Expected:       fmt.Sprintf("%.0f%% - %.0f%% (1 in %.0f - 1 in %.0f)", minHitRate*100, maxHitRate*100, 1/maxHitRate, 1/minHitRate),
-		Value:          fmt.Sprintf("%.2f%% (1 in %.2f)", stats.HitRate*100, odds),
+		Expected:       fmt.Sprintf("%s - %s (1 in %s - 1 in %s)", c.locale.Percent(minHitRate, 0), c.locale.Percent(maxHitRate, 0), c.locale.Number(1/maxHitRate, 0), c.locale.Number(1/minHitRate, 0)),
+		Value:          fmt.Sprintf("%s (1 in %s)", c.locale.Percent(stats.HitRate, 2), c.locale.Number(odds, 2)),
 		Severity:       "warning",
 	}
 
@@ -502,7 +542,7 @@ func (c *ComplianceChecker) checkPayoutGaps(lut *stakergs.LookupTable, stats *St
 
 func (c *ComplianceChecker) checkUniquePayouts(lut *stakergs.LookupTable) ComplianceCheck {
 	// For slot-type games, should have reasonable number of unique payout values
-	minUnique := 10
+	minUnique := c.profile.MinUniquePayouts
 
 	uniquePayouts := c.countUniquePayouts(lut)
 
@@ -510,8 +550,8 @@ func (c *ComplianceChecker) checkUniquePayouts(lut *stakergs.LookupTable) Compli
 		ID:             CheckUniquePayouts,
 		NameKey:        "compliance.checks.uniquePayouts.name",
 		DescriptionKey: "compliance.checks.uniquePayouts.description",
-		Expected:       fmt.Sprintf("≥ %d unique values", minUnique),
-		Value:          fmt.Sprintf("%d unique values", uniquePayouts),
+		Expected:       fmt.Sprintf("≥ %s unique values", c.locale.Int(int64(minUnique))),
+		Value:          fmt.Sprintf("%s unique values", c.locale.Int(int64(uniquePayouts))),
 		Severity:       "warning",
 	}
 
@@ -528,7 +568,7 @@ func (c *ComplianceChecker) checkUniquePayouts(lut *stakergs.LookupTable) Compli
 func (c *ComplianceChecker) checkSimulationDiversity(lut *stakergs.LookupTable, totalWeight uint64) ComplianceCheck {
 	// No single result should be so frequent that it appears multiple times in a typical session
 	// With 100,000 simulations, a single result shouldn't exceed ~1% probability
-	maxSingleProb := 0.01 // 1%
+	maxSingleProb := c.profile.MaxSingleOutcomeProb
 
 	mostFreqProb, _ := c.calculateMostFrequentProbability(lut, totalWeight)
 
@@ -536,8 +576,8 @@ func (c *ComplianceChecker) checkSimulationDiversity(lut *stakergs.LookupTable,
 		ID:             CheckSimulationDiversity,
 		NameKey:        "compliance.checks.simulationDiversity.name",
 		DescriptionKey: "compliance.checks.simulationDiversity.description",
-		Expected:       fmt.Sprintf("Most frequent outcome < %.1f%%", maxSingleProb*100),
-		Value:          fmt.Sprintf("%.2f%%", mostFreqProb*100),
+		Expected:       "Most frequent outcome < " + c.locale.Percent(maxSingleProb, 1),
+		Value:          c.locale.Percent(mostFreqProb, 2),
 		Severity:       "warning",
 	}
 
@@ -552,15 +592,15 @@ func (c *ComplianceChecker) checkSimulationDiversity(lut *stakergs.LookupTable,
 }
 
 func (c *ComplianceChecker) checkZeroPayoutRate(stats *Statistics) ComplianceCheck {
-	// Non-paying results shouldn't exceed 90%
-	maxZeroRate := 0.90
+	// Non-paying results shouldn't exceed the profile limit (90% by default)
+	maxZeroRate := c.profile.MaxZeroPayoutRate
 
 	check := ComplianceCheck{
 		ID:             CheckZeroPayoutRate,
 		NameKey:        "compliance.checks.zeroPayoutRate.name",
 		DescriptionKey: "compliance.checks.zeroPayoutRate.description",
-		Expected:       fmt.Sprintf("Non-paying ≤ %.0f%%", maxZeroRate*100),
-		Value:          fmt.Sprintf("%.2f%% non-paying", stats.ZeroPayoutRate*100),
+		Expected:       "Non-paying ≤ " + c.locale.Percent(maxZeroRate, 0),
+		Value:          c.locale.Percent(stats.ZeroPayoutRate, 2) + " non-paying",
 		Severity:       "error",
 	}
 
@@ -577,14 +617,14 @@ func (c *ComplianceChecker) checkZeroPayoutRate(stats *Statistics) ComplianceChe
 func (c *ComplianceChecker) checkVolatility(stats *Statistics) ComplianceCheck {
 	// Volatility check - standard deviation should be within industry norms
 	// This is more informational
-	maxVolatility := 50.0 // Very high volatility threshold
+	maxVolatility := c.profile.MaxVolatility
 
 	check := ComplianceCheck{
 		ID:             CheckVolatility,
 		NameKey:        "compliance.checks.volatility.name",
 		DescriptionKey: "compliance.checks.volatility.description",
-		Expected:       fmt.Sprintf("Volatility < %.0f", maxVolatility),
-		Value:          fmt.Sprintf("%.2f", stats.Volatility),
+		Expected:       "Volatility < " + c.locale.Number(maxVolatility, 0),
+		Value:          c.locale.Number(stats.Volatility, 2),
 		Severity:       "info",
 	}
 
@@ -671,19 +711,6 @@ func (c *ComplianceChecker) calculateMostFrequentProbability(lut *stakergs.Looku
 	return mostFreqProb, zeroProb
 }
 
-func formatLargeNumber(n float64) string {
-	if n >= 1_000_000_000 {
-		return fmt.Sprintf("%.2fB", n/1_000_000_000)
-	}
-	if n >= 1_000_000 {
-		return fmt.Sprintf("%.2fM", n/1_000_000)
-	}
-	if n >= 1_000 {
-		return fmt.Sprintf("%.2fK", n/1_000)
-	}
-	return fmt.Sprintf("%.0f", n)
-}
-
 // PayoutGapDetail contains details about payout distribution gaps.
 type PayoutGapDetail struct {
 	Range       string  `json:"range"`
@@ -0,0 +1,270 @@
+package lut
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Compliance check severities. SeverityOff drops a check from the result.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+// DefaultComplianceProfile is the profile used when none is selected.
+const DefaultComplianceProfile = "default"
+
+// ComplianceProfile holds the thresholds the compliance checks run against.
+// Presets are starting points modelled on common lab practice for each
+// jurisdiction; confirm them against the current technical standard before
+// a submission.
+type ComplianceProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Extends names the preset a custom profile starts from; unset fields
+	// keep the preset's values.
+	Extends string `json:"extends,omitempty"`
+
+	MinRTP          float64 `json:"min_rtp"`
+	MaxRTP          float64 `json:"max_rtp"`
+	MaxRTPVariation float64 `json:"max_rtp_variation"` // allowed distance from the base mode RTP
+	// MaxWinOdds is the worst acceptable "1 in N" for the max win in a
+	// cost-1 mode; it is divided by the cost for bonus modes.
+	MaxWinOdds           float64 `json:"max_win_odds"`
+	MinHitRate           float64 `json:"min_hit_rate"` // base modes (cost <= 2) only
+	MaxHitRate           float64 `json:"max_hit_rate"`
+	MinUniquePayouts     int     `json:"min_unique_payouts"`
+	MaxSingleOutcomeProb float64 `json:"max_single_outcome_probability"`
+	MaxZeroPayoutRate    float64 `json:"max_zero_payout_rate"`
+	MaxVolatility        float64 `json:"max_volatility"`
+
+	// Severities overrides the severity of individual checks.
+	Severities map[ComplianceCheckID]string `json:"severities,omitempty"`
+}
+
+var complianceProfiles = map[string]ComplianceProfile{
+	"default": {
+		Name:                 "default",
+		Description:          "Generic thresholds for Stake Engine submissions",
+		MinRTP:               0.90,
+		MaxRTP:               0.98,
+		MaxRTPVariation:      0.005,
+		MaxWinOdds:           20_000_000,
+		MinHitRate:           0.05,
+		MaxHitRate:           0.33,
+		MinUniquePayouts:     10,
+		MaxSingleOutcomeProb: 0.01,
+		MaxZeroPayoutRate:    0.90,
+		MaxVolatility:        50,
+	},
+	"ukgc": {
+		Name:                 "ukgc",
+		Description:          "United Kingdom Gambling Commission (RTS)",
+		MinRTP:               0.85,
+		MaxRTP:               0.99,
+		MaxRTPVariation:      0.005,
+		MaxWinOdds:           50_000_000,
+		MinHitRate:           0.05,
+		MaxHitRate:           0.50,
+		MinUniquePayouts:     10,
+		MaxSingleOutcomeProb: 0.01,
+		MaxZeroPayoutRate:    0.90,
+		MaxVolatility:        50,
+		Severities: map[ComplianceCheckID]string{
+			CheckHitRateReasonable: SeverityInfo,
+			CheckZeroPayoutRate:    SeverityError,
+		},
+	},
+	"mga": {
+		Name:                 "mga",
+		Description:          "Malta Gaming Authority",
+		MinRTP:               0.92,
+		MaxRTP:               0.99,
+		MaxRTPVariation:      0.005,
+		MaxWinOdds:           50_000_000,
+		MinHitRate:           0.05,
+		MaxHitRate:           0.50,
+		MinUniquePayouts:     10,
+		MaxSingleOutcomeProb: 0.01,
+		MaxZeroPayoutRate:    0.90,
+		MaxVolatility:        50,
+		Severities: map[ComplianceCheckID]string{
+			CheckHitRateReasonable: SeverityInfo,
+		},
+	},
+	"ontario": {
+		Name:                 "ontario",
+		Description:          "Alcohol and Gaming Commission of Ontario (iGaming Ontario)",
+		MinRTP:               0.85,
+		MaxRTP:               0.99,
+		MaxRTPVariation:      0.005,
+		MaxWinOdds:           50_000_000,
+		MinHitRate:           0.05,
+		MaxHitRate:           0.50,
+		MinUniquePayouts:     10,
+		MaxSingleOutcomeProb: 0.01,
+		MaxZeroPayoutRate:    0.90,
+		MaxVolatility:        50,
+		Severities: map[ComplianceCheckID]string{
+			CheckHitRateReasonable: SeverityInfo,
+			CheckMaxWinAchievable:  SeverityError,
+		},
+	},
+	"curacao": {
+		Name:                 "curacao",
+		Description:          "Curacao Gaming Authority",
+		MinRTP:               0.85,
+		MaxRTP:               0.99,
+		MaxRTPVariation:      0.01,
+		MaxWinOdds:           100_000_000,
+		MinHitRate:           0.03,
+		MaxHitRate:           0.50,
+		MinUniquePayouts:     5,
+		MaxSingleOutcomeProb: 0.02,
+		MaxZeroPayoutRate:    0.95,
+		MaxVolatility:        100,
+		Severities: map[ComplianceCheckID]string{
+			CheckHitRateReasonable: SeverityInfo,
+			CheckPayoutGaps:        SeverityInfo,
+			CheckUniquePayouts:     SeverityInfo,
+			CheckZeroPayoutRate:    SeverityWarning,
+		},
+	},
+}
+
+// ComplianceProfiles returns the preset profiles, default first.
+func ComplianceProfiles() []ComplianceProfile {
+	profiles := make([]ComplianceProfile, 0, len(complianceProfiles))
+	for _, p := range complianceProfiles {
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Name == DefaultComplianceProfile || profiles[j].Name == DefaultComplianceProfile {
+			return profiles[i].Name == DefaultComplianceProfile
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// GetComplianceProfile returns a preset by name (case-insensitive); the empty
+// name selects the default profile.
+func GetComplianceProfile(name string) (ComplianceProfile, error) {
+	if name == "" {
+		name = DefaultComplianceProfile
+	}
+	p, ok := complianceProfiles[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(complianceProfiles))
+		for _, preset := range ComplianceProfiles() {
+			names = append(names, preset.Name)
+		}
+		return ComplianceProfile{}, fmt.Errorf("unknown compliance profile %q: want one of %s, or a JSON profile", name, strings.Join(names, ", "))
+	}
+	return p.clone(), nil
+}
+
+// ParseComplianceProfile resolves a ?profile= value: a preset name, or a
+// custom profile as JSON whose unset fields fall back to the preset named by
+// "extends" (default if omitted), e.g.
+//
+//	{"name":"studio","extends":"mga","min_rtp":0.94,"severities":{"volatility":"off"}}
+func ParseComplianceProfile(value string) (ComplianceProfile, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{") {
+		return GetComplianceProfile(value)
+	}
+
+	var head struct {
+		Extends string `json:"extends"`
+	}
+	if err := json.Unmarshal([]byte(value), &head); err != nil {
+		return ComplianceProfile{}, fmt.Errorf("invalid profile JSON: %w", err)
+	}
+	base, err := GetComplianceProfile(head.Extends)
+	if err != nil {
+		return ComplianceProfile{}, err
+	}
+
+	custom := base
+	custom.Name = "custom"
+	custom.Description = ""
+	custom.Severities = nil
+	if err := json.Unmarshal([]byte(value), &custom); err != nil {
+		return ComplianceProfile{}, fmt.Errorf("invalid profile JSON: %w", err)
+	}
+	custom.Extends = base.Name
+	// Severity overrides add to the preset's instead of replacing them
+	merged := base.clone().Severities
+	if merged == nil {
+		merged = make(map[ComplianceCheckID]string)
+	}
+	for id, severity := range custom.Severities {
+		merged[id] = severity
+	}
+	custom.Severities = merged
+
+	if err := custom.Validate(); err != nil {
+		return ComplianceProfile{}, err
+	}
+	return custom, nil
+}
+
+// Validate checks that thresholds are consistent.
+func (p ComplianceProfile) Validate() error {
+	if p.MinRTP < 0 || p.MaxRTP <= 0 || p.MinRTP > p.MaxRTP {
+		return fmt.Errorf("profile %s: need 0 <= min_rtp <= max_rtp and max_rtp > 0", p.Name)
+	}
+	if p.MaxRTPVariation < 0 {
+		return fmt.Errorf("profile %s: max_rtp_variation must be non-negative", p.Name)
+	}
+	if p.MaxWinOdds <= 0 {
+		return fmt.Errorf("profile %s: max_win_odds must be positive", p.Name)
+	}
+	if p.MinHitRate < 0 || p.MaxHitRate > 1 || p.MinHitRate > p.MaxHitRate {
+		return fmt.Errorf("profile %s: need 0 <= min_hit_rate <= max_hit_rate <= 1", p.Name)
+	}
+	if p.MaxSingleOutcomeProb <= 0 || p.MaxSingleOutcomeProb > 1 {
+		return fmt.Errorf("profile %s: max_single_outcome_probability must be within (0, 1]", p.Name)
+	}
+	if p.MaxZeroPayoutRate < 0 || p.MaxZeroPayoutRate > 1 {
+		return fmt.Errorf("profile %s: max_zero_payout_rate must be within [0, 1]", p.Name)
+	}
+	for id, severity := range p.Severities {
+		switch severity {
+		case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		default:
+			return fmt.Errorf("profile %s: check %s: unknown severity %q", p.Name, id, severity)
+		}
+	}
+	return nil
+}
+
+func (p ComplianceProfile) clone() ComplianceProfile {
+	if p.Severities != nil {
+		severities := make(map[ComplianceCheckID]string, len(p.Severities))
+		for id, severity := range p.Severities {
+			severities[id] = severity
+		}
+		p.Severities = severities
+	}
+	return p
+}
+
+// applySeverity sets a check's severity from the profile. It reports false
+// if the profile turns the check off.
+func (p ComplianceProfile) applySeverity(check *ComplianceCheck) bool {
+	severity, ok := p.Severities[check.ID]
+	if !ok {
+		return true
+	}
+	if severity == SeverityOff {
+		return false
+	}
+	check.Severity = severity
+	return true
+}
@@ -0,0 +1,156 @@
+package lut
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"stakergs"
+)
+
+// complianceText holds the English strings for the i18n keys of compliance
+// checks, so reports read the same as the UI without the frontend.
+var complianceText = map[string]string{
+	"compliance.checks.rtpRange.name":              "RTP Range",
+	"compliance.checks.rtpRange.reasonLow":         "RTP is below minimum threshold",
+	"compliance.checks.rtpRange.reasonHigh":        "RTP exceeds maximum threshold",
+	"compliance.checks.rtpVariation.name":          "RTP vs Base Mode",
+	"compliance.checks.rtpVariation.reasonLow":     "RTP is below minimum allowed range",
+	"compliance.checks.rtpVariation.reasonHigh":    "RTP exceeds maximum allowed range",
+	"compliance.checks.maxWinAchievable.name":      "Maximum Win Achievability",
+	"compliance.checks.maxWinAchievable.reason":    "Maximum win is too rare to be achievable within limits",
+	"compliance.checks.hitRate.name":               "Hit Rate",
+	"compliance.checks.hitRate.reasonLow":          "Hit rate is too low, players may perceive too many losing spins",
+	"compliance.checks.hitRate.reasonHigh":         "Hit rate is unusually high, this may affect game balance",
+	"compliance.checks.payoutGaps.name":            "Payout Distribution Gaps",
+	"compliance.checks.payoutGaps.reason":          "Missing payouts in some ranges detected",
+	"compliance.checks.uniquePayouts.name":         "Unique Payout Amounts",
+	"compliance.checks.uniquePayouts.reason":       "Insufficient unique payout values for game variety",
+	"compliance.checks.simulationDiversity.name":   "Simulation Diversity",
+	"compliance.checks.simulationDiversity.reason": "Most frequent outcome has too high probability, may cause repetitive results",
+	"compliance.checks.zeroPayoutRate.name":        "Zero Payout Rate",
+	"compliance.checks.zeroPayoutRate.reason":      "Too many outcomes result in zero payout",
+	"compliance.checks.volatility.name":            "Volatility",
+	"compliance.checks.volatility.reason":          "Very high volatility may result in extreme variance in player outcomes",
+	"compliance.checks.rtpVariationGlobal.name":    "RTP Variation Between Modes",
+	"compliance.checks.rtpVariationGlobal.reason":  "Some modes are outside the allowed RTP range",
+}
+
+func complianceString(key string) string {
+	if text, ok := complianceText[key]; ok {
+		return text
+	}
+	return key
+}
+
+// ComplianceReportCheck is a check as printed in a report.
+type ComplianceReportCheck struct {
+	Name     string
+	Status   string // PASS, WARN or FAIL
+	Severity string
+	Expected string
+	Value    string
+	Reason   string
+}
+
+// ComplianceReportBar is one payout range of the report's distribution chart.
+type ComplianceReportBar struct {
+	PayoutGapDetail
+	Width float64 // bar length in [0, 1], log scale of probability
+}
+
+// ComplianceReport is a self-contained compliance document for a mode, for
+// submission to certification labs.
+type ComplianceReport struct {
+	GeneratedAt time.Time
+	GameID      string
+	Mode        string
+	Cost        float64
+	Profile     ComplianceProfile
+	Result      *ComplianceResult
+	Checks      []ComplianceReportCheck
+	Ranges      []ComplianceReportBar
+}
+
+// BuildComplianceReport runs the checker on a table and collects everything
+// the report renders.
+func BuildComplianceReport(table *stakergs.LookupTable, checker *ComplianceChecker) *ComplianceReport {
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+	result := checker.CheckMode(table)
+
+	report := &ComplianceReport{
+		GeneratedAt: time.Now().UTC(),
+		GameID:      table.GameID,
+		Mode:        table.Mode,
+		Cost:        cost,
+		Profile:     checker.Profile(),
+		Result:      result,
+	}
+
+	for _, check := range result.Checks {
+		row := ComplianceReportCheck{
+			Name:     complianceString(check.NameKey),
+			Severity: check.Severity,
+			Expected: check.Expected,
+			Value:    check.Value,
+		}
+		switch {
+		case check.Passed:
+			row.Status = "PASS"
+		case check.Severity == SeverityError:
+			row.Status = "FAIL"
+		default:
+			row.Status = "WARN"
+		}
+		if check.ReasonKey != "" {
+			row.Reason = complianceString(check.ReasonKey)
+		}
+		if gaps, ok := check.Details.([]string); ok && len(gaps) > 0 {
+			row.Reason += ": " + strings.Join(gaps, ", ")
+		}
+		report.Checks = append(report.Checks, row)
+	}
+
+	// Bars span from a decade below the rarest populated range to 100%
+	ranges := checker.GetPayoutRangeAnalysis(table)
+	minProb := 1.0
+	for _, r := range ranges {
+		if r.Probability > 0 && r.Probability < minProb {
+			minProb = r.Probability
+		}
+	}
+	floor := math.Log10(minProb) - 1
+	for _, r := range ranges {
+		bar := ComplianceReportBar{PayoutGapDetail: r}
+		if r.Probability > 0 {
+			bar.Width = (math.Log10(r.Probability) - floor) / -floor
+		}
+		report.Ranges = append(report.Ranges, bar)
+	}
+
+	return report
+}
+
+// Status returns the overall verdict line.
+func (r *ComplianceReport) Status() string {
+	if r.Result.Passed {
+		return "PASSED"
+	}
+	return "FAILED"
+}
+
+// WriteReport renders the report as "html" or "pdf".
+func (r *ComplianceReport) WriteReport(w io.Writer, format string) error {
+	switch format {
+	case "html":
+		return r.WriteHTML(w)
+	case "pdf":
+		return r.WritePDF(w)
+	}
+	return fmt.Errorf("format must be html or pdf")
+}
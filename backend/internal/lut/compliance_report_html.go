@@ -0,0 +1,99 @@
+package lut
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"lutexplorer/internal/numfmt"
+)
+
+// complianceChartWidth is the bar area of the payout range chart, in px.
+const complianceChartWidth = 420
+
+var complianceReportFuncs = template.FuncMap{
+	"pct":  func(v float64) string { return fmt.Sprintf("%.4f%%", v*100) },
+	"x":    func(v float64) string { return fmt.Sprintf("%.2fx", v) },
+	"num":  func(v float64) string { return fmt.Sprintf("%.4f", v) },
+	"odds": func(v float64) string { return "1 in " + numfmt.English.Compact(v) },
+	"barw": func(v float64) string { return fmt.Sprintf("%.1f", v*complianceChartWidth) },
+	"bary": func(i int) int { return i * 20 },
+	"barh": func(bars []ComplianceReportBar) int { return len(bars) * 20 },
+}
+
+var complianceReportTemplate = template.Must(template.New("compliance").Funcs(complianceReportFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Compliance report – {{.GameID}} {{.Mode}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 12px; margin: 24px; color: #111; }
+h1 { font-size: 20px; margin-bottom: 4px; }
+h2 { font-size: 14px; margin-top: 24px; border-bottom: 1px solid #999; }
+table { border-collapse: collapse; margin-top: 8px; }
+th, td { border: 1px solid #ccc; padding: 3px 8px; text-align: right; }
+th { background: #f0f0f0; }
+td.label, th.label { text-align: left; }
+.meta { color: #555; }
+.verdict { font-size: 16px; font-weight: bold; margin-top: 12px; }
+.PASS, .PASSED { color: #1a7f37; }
+.WARN { color: #9a6700; }
+.FAIL, .FAILED { color: #cf222e; }
+svg text { font-size: 11px; }
+@media print { body { margin: 0; } h2 { page-break-after: avoid; } table, svg { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>Compliance report: {{.GameID}} / {{.Mode}}</h1>
+<div class="meta">Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 UTC"}} · profile {{.Profile.Name}}{{with .Profile.Description}} ({{.}}){{end}}</div>
+<div class="verdict {{.Status}}">{{.Status}}: {{.Result.PassedCount}} passed, {{.Result.WarningCount}} warnings, {{.Result.FailedCount}} failed</div>
+
+<h2>Summary</h2>
+<table>
+<tr><td class="label">Cost</td><td>{{x .Cost}}</td></tr>
+<tr><td class="label">RTP</td><td>{{pct .Result.Summary.RTP}}</td></tr>
+<tr><td class="label">Hit rate</td><td>{{pct .Result.Summary.HitRate}}</td></tr>
+<tr><td class="label">Zero payout rate</td><td>{{pct .Result.Summary.ZeroPayoutRate}}</td></tr>
+<tr><td class="label">Max payout</td><td>{{x .Result.Summary.MaxPayout}}</td></tr>
+<tr><td class="label">Max payout probability</td><td>{{pct .Result.Summary.MaxPayoutHitRate}}</td></tr>
+<tr><td class="label">Outcomes</td><td>{{.Result.Summary.TotalOutcomes}}</td></tr>
+<tr><td class="label">Unique payouts</td><td>{{.Result.Summary.UniquePayouts}}</td></tr>
+<tr><td class="label">Most frequent outcome</td><td>{{pct .Result.Summary.MostFrequentProb}}</td></tr>
+<tr><td class="label">Volatility</td><td>{{num .Result.Summary.Volatility}}</td></tr>
+</table>
+
+<h2>Checks</h2>
+<table>
+<tr><th class="label">Check</th><th class="label">Result</th><th class="label">Severity</th><th class="label">Expected</th><th class="label">Actual</th><th class="label">Reason</th></tr>
+{{range .Checks}}<tr><td class="label">{{.Name}}</td><td class="label {{.Status}}">{{.Status}}</td><td class="label">{{.Severity}}</td><td class="label">{{.Expected}}</td><td class="label">{{.Value}}</td><td class="label">{{.Reason}}</td></tr>
+{{end}}</table>
+
+<h2>Payout range analysis</h2>
+<svg width="{{barw 1.0}}" height="{{barh .Ranges}}" viewBox="0 0 {{barw 1.0}} {{barh .Ranges}}" role="img" aria-label="Probability by payout range, log scale" style="margin-left: 110px; overflow: visible">
+{{range $i, $b := .Ranges}}<text x="-6" y="{{bary $i}}" dy="14" text-anchor="end">{{$b.Range}}</text><rect x="0" y="{{bary $i}}" width="{{barw $b.Width}}" height="16" fill="#4f6bed"/>
+{{end}}</svg>
+<div class="meta">Probability per range on a log scale; ranges without payouts are empty.</div>
+<table>
+<tr><th class="label">Range</th><th>Weight</th><th>Probability</th></tr>
+{{range .Ranges}}<tr><td class="label">{{.Range}}</td><td>{{.TotalWeight}}</td><td>{{pct .Probability}}</td></tr>
+{{end}}</table>
+
+<h2>Profile thresholds</h2>
+<table>
+<tr><td class="label">RTP range</td><td>{{pct .Profile.MinRTP}} – {{pct .Profile.MaxRTP}}</td></tr>
+<tr><td class="label">RTP variation vs base mode</td><td>± {{pct .Profile.MaxRTPVariation}}</td></tr>
+<tr><td class="label">Max win odds (cost 1)</td><td>{{odds .Profile.MaxWinOdds}}</td></tr>
+<tr><td class="label">Hit rate (base modes)</td><td>{{pct .Profile.MinHitRate}} – {{pct .Profile.MaxHitRate}}</td></tr>
+<tr><td class="label">Unique payouts</td><td>≥ {{.Profile.MinUniquePayouts}}</td></tr>
+<tr><td class="label">Most frequent outcome</td><td>≤ {{pct .Profile.MaxSingleOutcomeProb}}</td></tr>
+<tr><td class="label">Zero payout rate</td><td>≤ {{pct .Profile.MaxZeroPayoutRate}}</td></tr>
+<tr><td class="label">Volatility</td><td>&lt; {{num .Profile.MaxVolatility}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML renders the compliance report as a printable HTML document.
+func (r *ComplianceReport) WriteHTML(w io.Writer) error {
+	return complianceReportTemplate.Execute(w, r)
+}
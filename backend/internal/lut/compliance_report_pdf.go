@@ -0,0 +1,147 @@
+package lut
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"lutexplorer/internal/numfmt"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// pdfSymbols replaces characters the core PDF fonts (cp1252) cannot show.
+var pdfSymbols = strings.NewReplacer("≤", "<=", "≥", ">=", "∞", "inf")
+
+// WritePDF renders the compliance report as an A4 PDF document.
+func (r *ComplianceReport) WritePDF(w io.Writer) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Compliance report - %s %s", r.GameID, r.Mode), true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%s / %s - page %d of {nb}", r.GameID, r.Mode, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	text := func(s string) string { return tr(pdfSymbols.Replace(s)) }
+
+	pdf.AddPage()
+
+	// Title and verdict
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, text(fmt.Sprintf("Compliance report: %s / %s", r.GameID, r.Mode)), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	meta := fmt.Sprintf("Generated %s - profile %s", r.GeneratedAt.Format("2006-01-02 15:04:05 UTC"), r.Profile.Name)
+	if r.Profile.Description != "" {
+		meta += " (" + r.Profile.Description + ")"
+	}
+	pdf.CellFormat(0, 5, text(meta), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 12)
+	setStatusColor(pdf, r.Status())
+	pdf.CellFormat(0, 7, fmt.Sprintf("%s: %d passed, %d warnings, %d failed",
+		r.Status(), r.Result.PassedCount, r.Result.WarningCount, r.Result.FailedCount), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	heading := func(title string) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 7, title, "B", 1, "L", false, 0, "")
+		pdf.Ln(1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+	pair := func(label, value string) {
+		pdf.CellFormat(70, 5, text(label), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 5, text(value), "1", 1, "R", false, 0, "")
+	}
+
+	s := r.Result.Summary
+	heading("Summary")
+	pair("Cost", fmt.Sprintf("%.2fx", r.Cost))
+	pair("RTP", fmt.Sprintf("%.4f%%", s.RTP*100))
+	pair("Hit rate", fmt.Sprintf("%.4f%%", s.HitRate*100))
+	pair("Zero payout rate", fmt.Sprintf("%.4f%%", s.ZeroPayoutRate*100))
+	pair("Max payout", fmt.Sprintf("%.2fx", s.MaxPayout))
+	pair("Max payout probability", fmt.Sprintf("%.4f%%", s.MaxPayoutHitRate*100))
+	pair("Outcomes", fmt.Sprintf("%d", s.TotalOutcomes))
+	pair("Unique payouts", fmt.Sprintf("%d", s.UniquePayouts))
+	pair("Most frequent outcome", fmt.Sprintf("%.4f%%", s.MostFrequentProb*100))
+	pair("Volatility", fmt.Sprintf("%.4f", s.Volatility))
+
+	// Checks: one row per check, reason on its own line when it failed
+	heading("Checks")
+	widths := []float64{48, 14, 18, 50, 50}
+	pdf.SetFillColor(240, 240, 240)
+	pdf.SetFont("Helvetica", "B", 9)
+	for i, h := range []string{"Check", "Result", "Severity", "Expected", "Actual"} {
+		pdf.CellFormat(widths[i], 6, h, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 8)
+	for _, c := range r.Checks {
+		pdf.CellFormat(widths[0], 5, text(c.Name), "1", 0, "L", false, 0, "")
+		setStatusColor(pdf, c.Status)
+		pdf.CellFormat(widths[1], 5, c.Status, "1", 0, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(widths[2], 5, c.Severity, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 5, text(c.Expected), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[4], 5, text(c.Value), "1", 1, "L", false, 0, "")
+		if c.Reason != "" {
+			pdf.SetTextColor(90, 90, 90)
+			pdf.MultiCell(0, 4, text("  "+c.Reason), "LRB", "L", false)
+			pdf.SetTextColor(0, 0, 0)
+		}
+	}
+
+	// Payout ranges: log-scale bar chart followed by the numbers
+	heading("Payout range analysis")
+	const labelWidth, chartWidth, barHeight = 32.0, 110.0, 4.0
+	for _, b := range r.Ranges {
+		if pdf.GetY()+barHeight+1 > 280 {
+			pdf.AddPage()
+		}
+		y := pdf.GetY()
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(labelWidth, barHeight, text(b.Range), "", 0, "R", false, 0, "")
+		if b.Width > 0 {
+			pdf.SetFillColor(79, 107, 237)
+			pdf.Rect(15+labelWidth+2, y, b.Width*chartWidth, barHeight, "F")
+		}
+		pdf.SetXY(15+labelWidth+chartWidth+4, y)
+		pdf.CellFormat(0, barHeight, fmt.Sprintf("%.4f%%", b.Probability*100), "", 1, "L", false, 0, "")
+		pdf.SetY(y + barHeight + 1)
+	}
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.CellFormat(0, 5, "Probability per range on a log scale; ranges without payouts are empty.", "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	heading("Profile thresholds")
+	p := r.Profile
+	pair("RTP range", fmt.Sprintf("%.2f%% - %.2f%%", p.MinRTP*100, p.MaxRTP*100))
+	pair("RTP variation vs base mode", fmt.Sprintf("± %.2f%%", p.MaxRTPVariation*100))
+	pair("Max win odds (cost 1)", "1 in "+numfmt.English.Compact(p.MaxWinOdds))
+	pair("Hit rate (base modes)", fmt.Sprintf("%.2f%% - %.2f%%", p.MinHitRate*100, p.MaxHitRate*100))
+	pair("Unique payouts", fmt.Sprintf("≥ %d", p.MinUniquePayouts))
+	pair("Most frequent outcome", fmt.Sprintf("≤ %.2f%%", p.MaxSingleOutcomeProb*100))
+	pair("Zero payout rate", fmt.Sprintf("≤ %.2f%%", p.MaxZeroPayoutRate*100))
+	pair("Volatility", fmt.Sprintf("< %.2f", p.MaxVolatility))
+
+	return pdf.Output(w)
+}
+
+func setStatusColor(pdf *fpdf.Fpdf, status string) {
+	switch status {
+	case "PASS", "PASSED":
+		pdf.SetTextColor(26, 127, 55)
+	case "WARN":
+		pdf.SetTextColor(154, 103, 0)
+	default:
+		pdf.SetTextColor(207, 34, 46)
+	}
+}
@@ -0,0 +1,238 @@
+package lut
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"stakergs"
+)
+
+// CompositeConfig holds parameters for a base game simulation that plays
+// feature modes when trigger outcomes hit.
+type CompositeConfig struct {
+	Spins    int                       `json:"spins"`    // Base spins per trial
+	Trials   int                       `json:"trials"`   // Number of trials to run
+	Bet      float64                   `json:"bet"`      // Base bet per spin (base mode cost)
+	Seed     int64                     `json:"seed"`     // RNG seed; the same seed and tables give identical results
+	Workers  int                       `json:"workers"`  // Goroutines running trials (0 = one per CPU)
+	Triggers []stakergs.FeatureTrigger `json:"triggers"` // Base outcomes that start each feature
+}
+
+// CompositeResult holds total-game results split into base game and features.
+type CompositeResult struct {
+	BaseMode     string          `json:"base_mode"`
+	Config       CompositeConfig `json:"config"`
+	Seed         int64           `json:"seed"`
+	TotalSpins   int             `json:"total_spins"`
+	TotalWagered float64         `json:"total_wagered"`
+	TotalWon     float64         `json:"total_won"`
+	ActualRTP    float64         `json:"actual_rtp"`
+	BaseWon      float64         `json:"base_won"`
+	BaseRTP      float64         `json:"base_rtp"` // RTP contributed by base outcomes
+	FeatureWon   float64         `json:"feature_won"`
+	FeatureRTP   float64         `json:"feature_rtp"` // RTP contributed by triggered features
+	Features     []FeatureStats  `json:"features"`
+	// Exact expectations from the tables, for comparison with the measured split
+	TheoreticalRTP        float64 `json:"theoretical_rtp"`
+	TheoreticalBaseRTP    float64 `json:"theoretical_base_rtp"`
+	TheoreticalFeatureRTP float64 `json:"theoretical_feature_rtp"`
+	DurationMs            int64   `json:"duration_ms"`
+}
+
+// FeatureStats holds results for one feature mode.
+type FeatureStats struct {
+	Mode              string  `json:"mode"`
+	Triggers          int     `json:"triggers"`
+	TriggerRate       float64 `json:"trigger_rate"`     // triggers per base spin
+	TheoreticalRate   float64 `json:"theoretical_rate"` // from base table weights
+	TotalWon          float64 `json:"total_won"`
+	RTP               float64 `json:"rtp"`     // contribution to total RTP
+	AvgWin            float64 `json:"avg_win"` // per trigger, in base bets
+	MaxWin            float64 `json:"max_win"`
+	TheoreticalAvgWin float64 `json:"theoretical_avg_win"`
+}
+
+// compositeTrial holds the raw totals of one composite trial.
+type compositeTrial struct {
+	baseWon    float64
+	featureWon []float64
+	triggers   []int
+	featureMax []float64
+}
+
+// RunCompositeSimulation plays the base table and, whenever a trigger outcome
+// hits, one draw from the triggered feature's table. Feature payouts are
+// multiples of the base bet, as in the feature's own table, and are added to
+// the base outcome's payout. Trials run in parallel with per-trial seeds.
+func (s *Simulator) RunCompositeSimulation(base *stakergs.LookupTable, features map[string]*stakergs.LookupTable, config CompositeConfig) (*CompositeResult, error) {
+	start := time.Now()
+
+	// Resolve triggers to feature indexes
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	featureIndex := make(map[string]int, len(names))
+	samplers := make([]*WeightedSampler, len(names))
+	for i, name := range names {
+		if features[name].TotalWeight() == 0 {
+			return nil, fmt.Errorf("feature mode %q has no weight", name)
+		}
+		featureIndex[name] = i
+		samplers[i] = NewWeightedSampler(features[name])
+	}
+
+	triggerOf := make(map[int]int) // base sim ID -> feature index
+	for _, t := range config.Triggers {
+		idx, ok := featureIndex[t.Mode]
+		if !ok {
+			return nil, fmt.Errorf("trigger references unknown feature mode %q", t.Mode)
+		}
+		for _, simID := range t.SimIDs {
+			if prev, dup := triggerOf[simID]; dup && prev != idx {
+				return nil, fmt.Errorf("sim_id %d triggers more than one feature", simID)
+			}
+			triggerOf[simID] = idx
+		}
+	}
+	if len(triggerOf) == 0 {
+		return nil, fmt.Errorf("no trigger outcomes configured")
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > config.Trials {
+		workers = config.Trials
+	}
+	config.Workers = workers
+
+	baseSampler := NewWeightedSampler(base)
+
+	outcomes := make([]compositeTrial, config.Trials)
+	trials := make(chan int, config.Trials)
+	for trial := 0; trial < config.Trials; trial++ {
+		trials <- trial
+	}
+	close(trials)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for trial := range trials {
+				rng := rand.New(rand.NewSource(TrialSeed(config.Seed, trial)))
+				out := compositeTrial{
+					featureWon: make([]float64, len(names)),
+					triggers:   make([]int, len(names)),
+					featureMax: make([]float64, len(names)),
+				}
+				for spin := 0; spin < config.Spins; spin++ {
+					outcome := baseSampler.Sample(rng)
+					out.baseWon += float64(outcome.Payout) / 100.0
+
+					idx, ok := triggerOf[outcome.SimID]
+					if !ok {
+						continue
+					}
+					win := float64(samplers[idx].Sample(rng).Payout) / 100.0
+					out.triggers[idx]++
+					out.featureWon[idx] += win
+					if win > out.featureMax[idx] {
+						out.featureMax[idx] = win
+					}
+				}
+				outcomes[trial] = out
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Merge in trial order
+	var baseWon float64
+	featureWon := make([]float64, len(names))
+	triggers := make([]int, len(names))
+	featureMax := make([]float64, len(names))
+	for _, out := range outcomes {
+		baseWon += out.baseWon
+		for i := range names {
+			featureWon[i] += out.featureWon[i]
+			triggers[i] += out.triggers[i]
+			if out.featureMax[i] > featureMax[i] {
+				featureMax[i] = out.featureMax[i]
+			}
+		}
+	}
+
+	totalSpins := config.Spins * config.Trials
+	totalWagered := float64(totalSpins) * config.Bet
+
+	// Exact expectations: base mean payout plus, per feature, the trigger
+	// probability times the feature's mean payout
+	baseTotal := float64(base.TotalWeight())
+	var theoBase float64
+	triggerWeight := make([]float64, len(names))
+	for _, o := range base.Outcomes {
+		theoBase += float64(o.Weight) * float64(o.Payout) / 100.0
+		if idx, ok := triggerOf[o.SimID]; ok {
+			triggerWeight[idx] += float64(o.Weight)
+		}
+	}
+	theoBase /= baseTotal * config.Bet
+
+	result := &CompositeResult{
+		BaseMode:           base.Mode,
+		Config:             config,
+		Seed:               config.Seed,
+		TotalSpins:         totalSpins,
+		TotalWagered:       round2(totalWagered),
+		BaseWon:            round2(baseWon),
+		BaseRTP:            round4(baseWon / totalWagered),
+		TheoreticalBaseRTP: round4(theoBase),
+	}
+
+	var totalFeatureWon, theoFeature float64
+	for i, name := range names {
+		totalFeatureWon += featureWon[i]
+		// Mean feature payout in base bets; the table's own RTP is relative to its cost
+		featureCost := features[name].Cost
+		if featureCost <= 0 {
+			featureCost = 1.0
+		}
+		meanWin := features[name].RTP() * featureCost
+		rate := triggerWeight[i] / baseTotal
+		theoFeature += rate * meanWin / config.Bet
+
+		stats := FeatureStats{
+			Mode:              name,
+			Triggers:          triggers[i],
+			TriggerRate:       round6(float64(triggers[i]) / float64(totalSpins)),
+			TheoreticalRate:   round6(rate),
+			TotalWon:          round2(featureWon[i]),
+			RTP:               round4(featureWon[i] / totalWagered),
+			MaxWin:            round2(featureMax[i]),
+			TheoreticalAvgWin: round2(meanWin),
+		}
+		if triggers[i] > 0 {
+			stats.AvgWin = round2(featureWon[i] / float64(triggers[i]))
+		}
+		result.Features = append(result.Features, stats)
+	}
+
+	result.FeatureWon = round2(totalFeatureWon)
+	result.FeatureRTP = round4(totalFeatureWon / totalWagered)
+	result.TotalWon = round2(baseWon + totalFeatureWon)
+	result.ActualRTP = round4((baseWon + totalFeatureWon) / totalWagered)
+	result.TheoreticalFeatureRTP = round4(theoFeature)
+	result.TheoreticalRTP = round4(theoBase + theoFeature)
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	return result, nil
+}
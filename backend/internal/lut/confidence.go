@@ -0,0 +1,89 @@
+package lut
+
+import (
+	"math"
+
+	"stakergs"
+)
+
+// Defaults for sample-size estimates.
+const (
+	DefaultConfidence   = 0.95
+	DefaultRTPTolerance = 0.001 // ±0.1 percentage points
+)
+
+// SampleSizeEstimate answers "how many spins do we need to verify this RTP?":
+// the spins after which measured RTP is within Tolerance of the theoretical
+// RTP with the given confidence (normal approximation).
+type SampleSizeEstimate struct {
+	TheoreticalRTP float64 `json:"theoretical_rtp"`
+	StdDev         float64 `json:"std_dev"` // per-spin standard deviation of RTP
+	Tolerance      float64 `json:"tolerance"`
+	Confidence     float64 `json:"confidence"`
+	RequiredSpins  int64   `json:"required_spins"`
+}
+
+// SpinRTPStats returns the mean and standard deviation of a single spin's
+// return relative to the mode cost, computed exactly from the table weights.
+func SpinRTPStats(lut *stakergs.LookupTable) (mean, stdDev float64) {
+	totalWeight := lut.TotalWeight()
+	if totalWeight == 0 {
+		return 0, 0
+	}
+	cost := lut.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+
+	var sum, sumSq float64
+	for _, o := range lut.Outcomes {
+		prob := float64(o.Weight) / float64(totalWeight)
+		r := float64(o.Payout) / 100.0 / cost
+		sum += r * prob
+		sumSq += r * r * prob
+	}
+	variance := sumSq - sum*sum
+	if variance < 0 {
+		variance = 0 // rounding on near-constant tables
+	}
+	return sum, math.Sqrt(variance)
+}
+
+// ZScore returns the two-sided standard normal critical value for a
+// confidence level, e.g. 1.96 for 0.95.
+func ZScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidence)
+}
+
+// RequiredSpins returns the spins needed for the mean of per-spin returns
+// with the given standard deviation to land within tolerance at confidence.
+func RequiredSpins(stdDev, tolerance, confidence float64) int64 {
+	if tolerance <= 0 || stdDev == 0 {
+		return 0
+	}
+	n := ZScore(confidence) * stdDev / tolerance
+	return int64(math.Ceil(n * n))
+}
+
+// EstimateSampleSize computes the spins required to verify the table's RTP
+// to within tolerance. Zero or invalid arguments use the defaults.
+func EstimateSampleSize(lut *stakergs.LookupTable, tolerance, confidence float64) SampleSizeEstimate {
+	if tolerance <= 0 {
+		tolerance = DefaultRTPTolerance
+	}
+	if confidence <= 0 || confidence >= 1 {
+		confidence = DefaultConfidence
+	}
+	mean, stdDev := SpinRTPStats(lut)
+	return SampleSizeEstimate{
+		TheoreticalRTP: round6(mean),
+		StdDev:         round6(stdDev),
+		Tolerance:      tolerance,
+		Confidence:     confidence,
+		RequiredSpins:  RequiredSpins(stdDev, tolerance, confidence),
+	}
+}
+
+func round6(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
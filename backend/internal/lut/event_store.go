@@ -0,0 +1,296 @@
+package lut
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// DefaultStoreCacheSize is how many decoded events a disk store keeps
+	// in memory for hot simIDs.
+	DefaultStoreCacheSize = 4096
+
+	// storeFrameSize is the decompressed size a store frame is cut at. Each
+	// lookup decompresses one frame, so smaller frames mean faster misses
+	// and a slightly worse compression ratio.
+	storeFrameSize = 256 * 1024
+)
+
+// storeEntry locates an event inside a store frame. A zero length marks a
+// line without an event.
+type storeEntry struct {
+	frame  uint32
+	offset uint32
+	length uint32
+}
+
+// storeFrame is an independently compressed block of the store file.
+type storeFrame struct {
+	offset int64
+	length int64
+}
+
+// DiskEventStore serves a mode's events from disk instead of memory. The
+// events file is re-compressed into small independent zstd frames with a
+// line -> (frame, offset, length) index, so a lookup seeks to one frame and
+// decompresses only that. Recently read events are kept in an LRU cache.
+type DiskEventStore struct {
+	Mode     string
+	FilePath string // source events file
+
+	storePath string
+	file      *os.File
+	frames    []storeFrame
+	entries   []storeEntry // by line index
+	count     int
+	decoder   *zstd.Decoder
+
+	mu     sync.RWMutex // held for reading by lookups, for writing by Close
+	closed bool
+
+	cacheMu   sync.Mutex
+	cache     map[int]*list.Element
+	lru       *list.List // front = most recently used
+	cacheSize int
+	hits      int64
+	misses    int64
+}
+
+type storeCacheItem struct {
+	line  int
+	event json.RawMessage
+}
+
+// DiskEventStoreBuilder writes a DiskEventStore one line at a time.
+type DiskEventStoreBuilder struct {
+	store   *DiskEventStore
+	encoder *zstd.Encoder
+	buf     []byte
+	offset  int64
+	done    bool
+}
+
+// NewDiskEventStoreBuilder starts a store for a mode in dir (the system temp
+// directory if empty). The store file is removed when the store is closed.
+func NewDiskEventStoreBuilder(mode, sourcePath, dir string, cacheSize int) (*DiskEventStoreBuilder, error) {
+	if cacheSize <= 0 {
+		cacheSize = DefaultStoreCacheSize
+	}
+	file, err := os.CreateTemp(dir, "lutexplorer-events-*.store")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event store: %w", err)
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+	return &DiskEventStoreBuilder{
+		store: &DiskEventStore{
+			Mode:      mode,
+			FilePath:  sourcePath,
+			storePath: file.Name(),
+			file:      file,
+			cache:     make(map[int]*list.Element),
+			lru:       list.New(),
+			cacheSize: cacheSize,
+		},
+		encoder: encoder,
+		buf:     make([]byte, 0, storeFrameSize*2),
+	}, nil
+}
+
+// Add stores the event at a line index. Lines must be added in increasing
+// order; skipped lines have no event.
+func (b *DiskEventStoreBuilder) Add(lineIndex int, event []byte) error {
+	s := b.store
+	if lineIndex < len(s.entries) {
+		return fmt.Errorf("line %d added out of order", lineIndex)
+	}
+	for len(s.entries) < lineIndex {
+		s.entries = append(s.entries, storeEntry{})
+	}
+	s.entries = append(s.entries, storeEntry{
+		frame:  uint32(len(s.frames)),
+		offset: uint32(len(b.buf)),
+		length: uint32(len(event)),
+	})
+	if len(event) > 0 {
+		s.count++
+	}
+	b.buf = append(b.buf, event...)
+	if len(b.buf) >= storeFrameSize {
+		return b.flush()
+	}
+	return nil
+}
+
+// flush compresses the buffered events into a frame.
+func (b *DiskEventStoreBuilder) flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	compressed := b.encoder.EncodeAll(b.buf, nil)
+	if _, err := b.store.file.Write(compressed); err != nil {
+		return fmt.Errorf("failed to write event store: %w", err)
+	}
+	b.store.frames = append(b.store.frames, storeFrame{offset: b.offset, length: int64(len(compressed))})
+	b.offset += int64(len(compressed))
+	b.buf = b.buf[:0]
+	return nil
+}
+
+// Finish writes the last frame and returns the store, ready for lookups.
+func (b *DiskEventStoreBuilder) Finish() (*DiskEventStore, error) {
+	if err := b.flush(); err != nil {
+		b.Abort()
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	if err != nil {
+		b.Abort()
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	b.encoder.Close()
+	b.done = true
+	b.store.decoder = decoder
+	return b.store, nil
+}
+
+// Abort discards a store that was not finished. It is a no-op after Finish.
+func (b *DiskEventStoreBuilder) Abort() {
+	if b.done {
+		return
+	}
+	b.done = true
+	b.encoder.Close()
+	b.store.file.Close()
+	os.Remove(b.store.storePath)
+}
+
+// Count returns the number of events in the store.
+func (s *DiskEventStore) Count() int {
+	return s.count
+}
+
+// Get returns the event at a line index.
+func (s *DiskEventStore) Get(lineIndex int) (json.RawMessage, error) {
+	if lineIndex < 0 || lineIndex >= len(s.entries) || s.entries[lineIndex].length == 0 {
+		return nil, fmt.Errorf("event at line %d not found in mode %q", lineIndex, s.Mode)
+	}
+
+	s.cacheMu.Lock()
+	if elem, ok := s.cache[lineIndex]; ok {
+		s.lru.MoveToFront(elem)
+		s.hits++
+		event := elem.Value.(*storeCacheItem).event
+		s.cacheMu.Unlock()
+		return event, nil
+	}
+	s.misses++
+	s.cacheMu.Unlock()
+
+	entry := s.entries[lineIndex]
+	frame, err := s.readFrame(int(entry.frame))
+	if err != nil {
+		return nil, err
+	}
+	event := make(json.RawMessage, entry.length)
+	copy(event, frame[entry.offset:entry.offset+entry.length])
+
+	s.cacheMu.Lock()
+	if _, ok := s.cache[lineIndex]; !ok {
+		s.cache[lineIndex] = s.lru.PushFront(&storeCacheItem{line: lineIndex, event: event})
+		for s.lru.Len() > s.cacheSize {
+			oldest := s.lru.Back()
+			s.lru.Remove(oldest)
+			delete(s.cache, oldest.Value.(*storeCacheItem).line)
+		}
+	}
+	s.cacheMu.Unlock()
+	return event, nil
+}
+
+// readFrame reads and decompresses one frame of the store file.
+func (s *DiskEventStore) readFrame(id int) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, errors.New("event store closed")
+	}
+
+	frame := s.frames[id]
+	compressed := make([]byte, frame.length)
+	if _, err := s.file.ReadAt(compressed, frame.offset); err != nil {
+		return nil, fmt.Errorf("failed to read event store: %w", err)
+	}
+	data, err := s.decoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt event store frame %d: %w", id, err)
+	}
+	return data, nil
+}
+
+// ForEach calls fn for every event in line order, decompressing each frame
+// once. Cached events are not touched.
+func (s *DiskEventStore) ForEach(fn func(lineIndex int, event json.RawMessage) error) error {
+	var data []byte
+	current := -1
+	for line, entry := range s.entries {
+		if entry.length == 0 {
+			continue
+		}
+		if int(entry.frame) != current {
+			var err error
+			if data, err = s.readFrame(int(entry.frame)); err != nil {
+				return err
+			}
+			current = int(entry.frame)
+		}
+		if err := fn(line, data[entry.offset:entry.offset+entry.length]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats returns sizes and cache counters for diagnostics.
+func (s *DiskEventStore) Stats() map[string]interface{} {
+	var size int64
+	if n := len(s.frames); n > 0 {
+		size = s.frames[n-1].offset + s.frames[n-1].length
+	}
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return map[string]interface{}{
+		"events":       s.count,
+		"frames":       len(s.frames),
+		"store_bytes":  size,
+		"index_bytes":  len(s.entries) * 12,
+		"cached":       s.lru.Len(),
+		"cache_size":   s.cacheSize,
+		"cache_hits":   s.hits,
+		"cache_misses": s.misses,
+	}
+}
+
+// Close releases the store and removes its file. Lookups in flight finish
+// first; later ones fail.
+func (s *DiskEventStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.decoder.Close()
+	s.file.Close()
+	return os.Remove(s.storePath)
+}
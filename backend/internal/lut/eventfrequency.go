@@ -0,0 +1,159 @@
+package lut
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"stakergs"
+)
+
+// EventFrequencyReport aggregates event types across every book of a mode.
+type EventFrequencyReport struct {
+	Mode        string               `json:"mode"`
+	Source      string               `json:"source"` // "memory" or "disk" when events were loaded, "stream" otherwise
+	Books       int                  `json:"books"`
+	TotalWeight uint64               `json:"total_weight"`
+	Types       []EventTypeFrequency `json:"types"` // sorted by weighted probability, highest first
+}
+
+// EventTypeFrequency holds counts and weighted probabilities for one event type.
+type EventTypeFrequency struct {
+	Type        string  `json:"type"`
+	Occurrences int     `json:"occurrences"` // across all books, unweighted
+	Books       int     `json:"books"`       // books containing the type at least once
+	BookRate    float64 `json:"book_rate"`   // Books / total books, unweighted
+	Probability float64 `json:"probability"` // chance a spin contains the type (weighted)
+	PerSpin     float64 `json:"per_spin"`    // expected occurrences per spin (weighted)
+	MaxPerBook  int     `json:"max_per_book"`
+	// Counts breaks down how many times the type appears within a book
+	Counts []EventCountFrequency `json:"counts"`
+}
+
+// EventCountFrequency is the share of books containing a type exactly Count times.
+type EventCountFrequency struct {
+	Count       int     `json:"count"`
+	Books       int     `json:"books"`
+	Probability float64 `json:"probability"` // weighted
+}
+
+// bookEventTypes decodes only the event types of a book.
+type bookEventTypes struct {
+	Events []struct {
+		Type string `json:"type"`
+	} `json:"events"`
+}
+
+// eventTypeTotals accumulates one type during a scan.
+type eventTypeTotals struct {
+	occurrences   int
+	books         int
+	weight        uint64
+	weightedCount float64
+	countBooks    map[int]int
+	countWeights  map[int]uint64
+}
+
+// EventFrequency scans all books of a mode and reports how often each event
+// type occurs, weighted by the lookup table so rates match real play.
+func (e *EventsLoader) EventFrequency(mode string, table *stakergs.LookupTable, eventsFile string) (*EventFrequencyReport, error) {
+	weights := make(map[int]uint64, len(table.Outcomes))
+	for _, o := range table.Outcomes {
+		weights[o.SimID] = o.Weight
+	}
+	totalWeight := table.TotalWeight()
+
+	totals := make(map[string]*eventTypeTotals)
+	books := 0
+
+	source, err := e.ForEachEvent(mode, eventsFile, func(lineIndex int, event json.RawMessage) error {
+		if len(bytes.TrimSpace(event)) == 0 {
+			return nil
+		}
+		weight, ok := weights[lineIndex+table.SimIDOffset]
+		if !ok {
+			return nil
+		}
+
+		var book bookEventTypes
+		if err := json.Unmarshal(event, &book); err != nil {
+			return fmt.Errorf("invalid event at line %d: %w", lineIndex, err)
+		}
+		books++
+
+		counts := make(map[string]int)
+		for _, ev := range book.Events {
+			if ev.Type != "" {
+				counts[ev.Type]++
+			}
+		}
+		for typ, count := range counts {
+			t, ok := totals[typ]
+			if !ok {
+				t = &eventTypeTotals{
+					countBooks:   make(map[int]int),
+					countWeights: make(map[int]uint64),
+				}
+				totals[typ] = t
+			}
+			t.occurrences += count
+			t.books++
+			t.weight += weight
+			t.weightedCount += float64(weight) * float64(count)
+			t.countBooks[count]++
+			t.countWeights[count] += weight
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EventFrequencyReport{
+		Mode:        mode,
+		Source:      source,
+		Books:       books,
+		TotalWeight: totalWeight,
+		Types:       make([]EventTypeFrequency, 0, len(totals)),
+	}
+
+	for typ, t := range totals {
+		freq := EventTypeFrequency{
+			Type:        typ,
+			Occurrences: t.occurrences,
+			Books:       t.books,
+			Counts:      make([]EventCountFrequency, 0, len(t.countBooks)),
+		}
+		if books > 0 {
+			freq.BookRate = round6(float64(t.books) / float64(books))
+		}
+		if totalWeight > 0 {
+			freq.Probability = round6(float64(t.weight) / float64(totalWeight))
+			freq.PerSpin = round6(t.weightedCount / float64(totalWeight))
+		}
+		for count, n := range t.countBooks {
+			if count > freq.MaxPerBook {
+				freq.MaxPerBook = count
+			}
+			c := EventCountFrequency{Count: count, Books: n}
+			if totalWeight > 0 {
+				c.Probability = round6(float64(t.countWeights[count]) / float64(totalWeight))
+			}
+			freq.Counts = append(freq.Counts, c)
+		}
+		sort.Slice(freq.Counts, func(i, j int) bool {
+			return freq.Counts[i].Count < freq.Counts[j].Count
+		})
+		report.Types = append(report.Types, freq)
+	}
+
+	sort.Slice(report.Types, func(i, j int) bool {
+		if report.Types[i].Probability != report.Types[j].Probability {
+			return report.Types[i].Probability > report.Types[j].Probability
+		}
+		return report.Types[i].Type < report.Types[j].Type
+	})
+
+	return report, nil
+}
@@ -16,9 +16,15 @@ import (
 // EventsLoader handles loading and decompressing event files (.jsonl.zst).
 type EventsLoader struct {
 	baseDir string
-	cache   map[string]*EventsIndex // mode -> events index (full load, legacy)
-	chunks  map[string]*ChunkCache  // mode -> chunk cache (lazy loading)
-	mu      sync.RWMutex            // protects cache from concurrent access
+	cache   map[string]*EventsIndex    // mode -> events index (full load, legacy)
+	chunks  map[string]*ChunkCache     // mode -> chunk cache (lazy loading)
+	stores  map[string]*DiskEventStore // mode -> indexed on-disk events (full load, disk mode)
+	mu      sync.RWMutex               // protects cache from concurrent access
+
+	// Disk mode: full loads build a DiskEventStore instead of a map
+	diskStore      bool
+	storeDir       string
+	storeCacheSize int
 }
 
 // ChunkCache holds cached event chunks for lazy loading.
@@ -61,9 +67,110 @@ func NewEventsLoader(baseDir string) *EventsLoader {
 		baseDir: baseDir,
 		cache:   make(map[string]*EventsIndex),
 		chunks:  make(map[string]*ChunkCache),
+		stores:  make(map[string]*DiskEventStore),
 	}
 }
 
+// SetDiskStore turns disk mode on or off. In disk mode fully loaded events
+// are kept in an indexed store file in dir (the system temp directory if
+// empty) instead of memory, with cacheSize hot events cached. Applies to
+// modes loaded afterwards.
+func (e *EventsLoader) SetDiskStore(enabled bool, dir string, cacheSize int) {
+	e.mu.Lock()
+	e.diskStore = enabled
+	e.storeDir = dir
+	e.storeCacheSize = cacheSize
+	e.mu.Unlock()
+}
+
+// DiskStoreEnabled reports whether full loads go to disk stores.
+func (e *EventsLoader) DiskStoreEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.diskStore
+}
+
+// NewStoreBuilder starts a disk store for a mode's events file, using the
+// loader's store settings.
+func (e *EventsLoader) NewStoreBuilder(mode, filePath string) (*DiskEventStoreBuilder, error) {
+	e.mu.RLock()
+	dir, cacheSize := e.storeDir, e.storeCacheSize
+	e.mu.RUnlock()
+	return NewDiskEventStoreBuilder(mode, filePath, dir, cacheSize)
+}
+
+// SetStore installs a finished disk store for a mode, replacing any events
+// loaded before.
+func (e *EventsLoader) SetStore(mode string, store *DiskEventStore) {
+	e.mu.Lock()
+	old := e.removeModeLocked(mode)
+	e.stores[mode] = store
+	e.mu.Unlock()
+	closeStores(old)
+}
+
+// findStoreLocked does case-insensitive lookup for mode in the disk stores.
+// IMPORTANT: caller must hold at least e.mu.RLock()
+func (e *EventsLoader) findStoreLocked(mode string) (*DiskEventStore, bool) {
+	for name, store := range e.stores {
+		if strings.EqualFold(name, mode) {
+			return store, true
+		}
+	}
+	return nil, false
+}
+
+// removeModeLocked drops a mode's full load, in memory or on disk, and
+// returns its disk stores for the caller to close outside the lock.
+// IMPORTANT: caller must hold e.mu.Lock()
+func (e *EventsLoader) removeModeLocked(mode string) []*DiskEventStore {
+	var stores []*DiskEventStore
+	for name, store := range e.stores {
+		if strings.EqualFold(name, mode) {
+			stores = append(stores, store)
+			delete(e.stores, name)
+		}
+	}
+	for name := range e.cache {
+		if strings.EqualFold(name, mode) {
+			delete(e.cache, name)
+		}
+	}
+	return stores
+}
+
+// clearStoresLocked drops all disk stores and returns them for closing.
+// IMPORTANT: caller must hold e.mu.Lock()
+func (e *EventsLoader) clearStoresLocked() []*DiskEventStore {
+	stores := make([]*DiskEventStore, 0, len(e.stores))
+	for _, store := range e.stores {
+		stores = append(stores, store)
+	}
+	e.stores = make(map[string]*DiskEventStore)
+	return stores
+}
+
+func closeStores(stores []*DiskEventStore) {
+	for _, store := range stores {
+		store.Close()
+	}
+}
+
+// SetBaseDir points the loader at another library's publish folder.
+// Callers should unload cached events first.
+func (e *EventsLoader) SetBaseDir(baseDir string) {
+	e.mu.Lock()
+	e.baseDir = baseDir
+	e.mu.Unlock()
+}
+
+// filePath resolves an events file against the base directory.
+func (e *EventsLoader) filePath(eventsFile string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return filepath.Join(e.baseDir, eventsFile)
+}
+
 // findMode does case-insensitive lookup for mode in cache.
 // IMPORTANT: caller must hold at least e.mu.RLock()
 func (e *EventsLoader) findModeLocked(mode string) (*EventsIndex, bool) {
@@ -78,7 +185,7 @@ func (e *EventsLoader) findModeLocked(mode string) (*EventsIndex, bool) {
 
 // LoadEvents loads and indexes events from a .jsonl.zst file.
 func (e *EventsLoader) LoadEvents(mode, eventsFile string) error {
-	filePath := filepath.Join(e.baseDir, eventsFile)
+	filePath := e.filePath(eventsFile)
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -93,6 +200,10 @@ func (e *EventsLoader) LoadEvents(mode, eventsFile string) error {
 	}
 	defer decoder.Close()
 
+	if e.DiskStoreEnabled() {
+		return e.loadStore(mode, filePath, decoder)
+	}
+
 	// Read and index events
 	index := &EventsIndex{
 		Mode:     mode,
@@ -128,26 +239,64 @@ func (e *EventsLoader) LoadEvents(mode, eventsFile string) error {
 	index.Count = len(index.Events)
 
 	e.mu.Lock()
+	old := e.removeModeLocked(mode)
 	e.cache[mode] = index
 	e.mu.Unlock()
+	closeStores(old)
+
+	return nil
+}
+
+// loadStore reads a decompressed events stream into a new disk store.
+func (e *EventsLoader) loadStore(mode, filePath string, r io.Reader) error {
+	builder, err := e.NewStoreBuilder(mode, filePath)
+	if err != nil {
+		return err
+	}
+	defer builder.Abort()
+
+	scanner := bufio.NewScanner(r)
+	const maxCapacity = 10 * 1024 * 1024 // 10MB
+	scanner.Buffer(make([]byte, maxCapacity), maxCapacity)
+
+	lineNum := 0
+	for scanner.Scan() {
+		if err := builder.Add(lineNum, scanner.Bytes()); err != nil {
+			return err
+		}
+		lineNum++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading events: %w", err)
+	}
 
+	store, err := builder.Finish()
+	if err != nil {
+		return err
+	}
+	e.SetStore(mode, store)
 	return nil
 }
 
 // GetEvent retrieves a single event by sim_id (case-insensitive mode lookup).
 // simIDOffset is the minimum sim_id from the LUT (0 or 1) for backwards compatibility.
 func (e *EventsLoader) GetEvent(mode string, simID int, simIDOffset int) (json.RawMessage, error) {
+	// Convert sim_id to 0-indexed event position
+	// Old format: sim_id starts from 1, so eventIndex = simID - 1
+	// New format: sim_id starts from 0, so eventIndex = simID - 0
+	eventIndex := simID - simIDOffset
+
 	e.mu.RLock()
+	if store, ok := e.findStoreLocked(mode); ok {
+		e.mu.RUnlock()
+		return store.Get(eventIndex)
+	}
 	index, ok := e.findModeLocked(mode)
 	if !ok {
 		e.mu.RUnlock()
 		return nil, fmt.Errorf("events for mode %q not loaded", mode)
 	}
 
-	// Convert sim_id to 0-indexed event position
-	// Old format: sim_id starts from 1, so eventIndex = simID - 1
-	// New format: sim_id starts from 0, so eventIndex = simID - 0
-	eventIndex := simID - simIDOffset
 	event, ok := index.Events[eventIndex]
 	e.mu.RUnlock()
 
@@ -185,6 +334,9 @@ func (e *EventsLoader) GetEventInfo(mode string, simID int, simIDOffset int, out
 func (e *EventsLoader) IsLoaded(mode string) bool {
 	e.mu.RLock()
 	_, ok := e.findModeLocked(mode)
+	if !ok {
+		_, ok = e.findStoreLocked(mode)
+	}
 	e.mu.RUnlock()
 	return ok
 }
@@ -192,10 +344,13 @@ func (e *EventsLoader) IsLoaded(mode string) bool {
 // GetLoadedModes returns list of modes with loaded events.
 func (e *EventsLoader) GetLoadedModes() []string {
 	e.mu.RLock()
-	modes := make([]string, 0, len(e.cache))
+	modes := make([]string, 0, len(e.cache)+len(e.stores))
 	for mode := range e.cache {
 		modes = append(modes, mode)
 	}
+	for mode := range e.stores {
+		modes = append(modes, mode)
+	}
 	e.mu.RUnlock()
 	return modes
 }
@@ -203,17 +358,20 @@ func (e *EventsLoader) GetLoadedModes() []string {
 // GetEventCount returns number of events for a mode (case-insensitive).
 func (e *EventsLoader) GetEventCount(mode string) int {
 	e.mu.RLock()
-	index, ok := e.findModeLocked(mode)
-	e.mu.RUnlock()
-	if ok {
+	defer e.mu.RUnlock()
+	if index, ok := e.findModeLocked(mode); ok {
 		return index.Count
 	}
+	if store, ok := e.findStoreLocked(mode); ok {
+		return store.Count()
+	}
 	return 0
 }
 
 // SetEvents stores events loaded by the background loader.
 func (e *EventsLoader) SetEvents(mode string, events map[int]json.RawMessage, filePath string) {
 	e.mu.Lock()
+	old := e.removeModeLocked(mode)
 	e.cache[mode] = &EventsIndex{
 		Mode:     mode,
 		FilePath: filePath,
@@ -221,26 +379,34 @@ func (e *EventsLoader) SetEvents(mode string, events map[int]json.RawMessage, fi
 		Count:    len(events),
 	}
 	e.mu.Unlock()
+	closeStores(old)
 }
 
 // ClearAll removes all cached events.
 func (e *EventsLoader) ClearAll() {
 	e.mu.Lock()
 	e.cache = make(map[string]*EventsIndex)
+	old := e.clearStoresLocked()
 	e.mu.Unlock()
+	closeStores(old)
 }
 
 // ClearMode removes cached events for a specific mode.
 func (e *EventsLoader) ClearMode(mode string) {
 	e.mu.Lock()
 	delete(e.cache, mode)
+	old := e.stores[mode]
+	delete(e.stores, mode)
 	e.mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
 }
 
 // StreamEvents streams events through a callback (for large files).
 // lineIndex passed to callback is 0-indexed to match CSV sim_id format.
 func (e *EventsLoader) StreamEvents(eventsFile string, callback func(lineIndex int, event json.RawMessage) error) error {
-	filePath := filepath.Join(e.baseDir, eventsFile)
+	filePath := e.filePath(eventsFile)
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -314,7 +480,7 @@ func FormatOdds(probability float64) string {
 // This streams through the file and only keeps the requested range in memory.
 // Returns a map of lineIndex -> event.
 func (e *EventsLoader) GetEventsRange(eventsFile string, startLine, endLine int) (map[int]json.RawMessage, error) {
-	filePath := filepath.Join(e.baseDir, eventsFile)
+	filePath := e.filePath(eventsFile)
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -367,8 +533,12 @@ func (e *EventsLoader) GetEventsRange(eventsFile string, startLine, endLine int)
 // GetEventLazy gets a single event using chunk-based lazy loading.
 // It loads a chunk around the requested line and caches it.
 func (e *EventsLoader) GetEventLazy(mode, eventsFile string, lineIndex int, simIDOffset int) (json.RawMessage, error) {
-	// First check if we have it in full cache (legacy)
+	// First check if we have it in full cache (legacy) or a disk store
 	e.mu.RLock()
+	if store, ok := e.findStoreLocked(mode); ok {
+		e.mu.RUnlock()
+		return store.Get(lineIndex - simIDOffset)
+	}
 	if index, ok := e.findModeLocked(mode); ok {
 		eventIdx := lineIndex - simIDOffset
 		if event, ok := index.Events[eventIdx]; ok {
@@ -469,32 +639,34 @@ func (c *ChunkCache) evictIfNeededLocked() {
 	}
 }
 
-// UnloadMode removes all cached events for a mode (both full and chunks).
+// UnloadMode removes all cached events for a mode (full, disk and chunks).
 func (e *EventsLoader) UnloadMode(mode string) {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	modeLower := strings.ToLower(mode)
-
-	// Clear full cache
-	for name := range e.cache {
-		if strings.ToLower(name) == modeLower {
-			delete(e.cache, name)
-			break
-		}
-	}
+	// Clear full cache and disk store
+	old := e.removeModeLocked(mode)
 
 	// Clear chunk cache
-	delete(e.chunks, modeLower)
+	delete(e.chunks, strings.ToLower(mode))
+	e.mu.Unlock()
+	closeStores(old)
+}
+
+// ClearChunks drops all lazily loaded chunks, keeping fully loaded events.
+// Used after a reload so chunks are re-read from the new files.
+func (e *EventsLoader) ClearChunks() {
+	e.mu.Lock()
+	e.chunks = make(map[string]*ChunkCache)
+	e.mu.Unlock()
 }
 
 // UnloadAll removes all cached events.
 func (e *EventsLoader) UnloadAll() {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-
 	e.cache = make(map[string]*EventsIndex)
 	e.chunks = make(map[string]*ChunkCache)
+	old := e.clearStoresLocked()
+	e.mu.Unlock()
+	closeStores(old)
 }
 
 // GetChunkCacheStats returns stats about chunk cache for debugging.
@@ -525,3 +697,15 @@ func (e *EventsLoader) GetChunkCacheStats(mode string) map[string]interface{} {
 		"lru_order":    cache.LRU,
 	}
 }
+
+// GetStoreStats returns stats about a mode's disk store, or nil if the mode
+// is not loaded to disk.
+func (e *EventsLoader) GetStoreStats(mode string) map[string]interface{} {
+	e.mu.RLock()
+	store, ok := e.findStoreLocked(mode)
+	e.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return store.Stats()
+}
@@ -0,0 +1,403 @@
+package lut
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"stakergs"
+)
+
+const (
+	DefaultSearchLimit = 100   // matches returned when no limit is given
+	MaxSearchLimit     = 10000 // upper bound on returned matches
+)
+
+// errStopScan stops ForEachEvent early without reporting an error.
+var errStopScan = errors.New("stop scan")
+
+// EventPredicate is a single condition on a book's JSON.
+//
+// Path uses a small JSON-path subset: dot-separated keys with optional
+// [n] or [*] array steps, e.g. "events[*].type" or "$.payoutMultiplier".
+// A path can resolve to several values; comparison ops match when any
+// resolved value satisfies them (ne and not_exists when none does).
+// Count ops compare the number of resolved values, counting only those
+// equal to Where when it is set:
+//
+//	{"path": "events[*].type", "op": "count_eq", "where": "freeSpinRetrigger", "value": 3}
+type EventPredicate struct {
+	Path  string `json:"path"`
+	Op    string `json:"op"` // eq, ne, gt, gte, lt, lte, contains, exists, not_exists, count_eq, count_gt, count_gte, count_lt, count_lte
+	Value any    `json:"value,omitempty"`
+	Where any    `json:"where,omitempty"` // filter for count ops
+	steps []pathStep
+}
+
+// EventQuery selects books by payout band and event predicates.
+type EventQuery struct {
+	Predicates []EventPredicate `json:"predicates"`
+	Match      string           `json:"match"`      // "all" (default) or "any"
+	MinPayout  float64          `json:"min_payout"` // inclusive, in bet multiples (0 = no bound)
+	MaxPayout  float64          `json:"max_payout"` // inclusive, in bet multiples (0 = no bound)
+	Limit      int              `json:"limit"`      // max matches returned (default 100)
+}
+
+// EventMatch is a book that satisfied the query.
+type EventMatch struct {
+	SimID       int     `json:"sim_id"`
+	Payout      float64 `json:"payout"`
+	Weight      uint64  `json:"weight"`
+	Probability float64 `json:"probability"`
+}
+
+// EventSearchResult holds the matches of an event search.
+type EventSearchResult struct {
+	Mode         string       `json:"mode"`
+	Source       string       `json:"source"`        // "memory" or "disk" when events were loaded, "stream" otherwise
+	Scanned      int          `json:"scanned"`       // books whose JSON was evaluated
+	TotalMatches int          `json:"total_matches"` // all matches, including those past the limit
+	MatchWeight  uint64       `json:"match_weight"`
+	Probability  float64      `json:"probability"` // chance a spin lands on a matching book
+	Matches      []EventMatch `json:"matches"`
+	Truncated    bool         `json:"truncated"`
+}
+
+// pathStep is one compiled step of a predicate path.
+type pathStep struct {
+	key      string // object key ("" for a bare array step)
+	index    int    // array index, or -1 for none
+	wildcard bool   // [*]: expand every array element
+}
+
+// Validate compiles the predicate paths and normalizes defaults.
+func (q *EventQuery) Validate() error {
+	switch q.Match {
+	case "":
+		q.Match = "all"
+	case "all", "any":
+	default:
+		return fmt.Errorf("invalid match %q: want all or any", q.Match)
+	}
+	if q.MinPayout < 0 || q.MaxPayout < 0 {
+		return fmt.Errorf("payout bounds must be non-negative")
+	}
+	if q.MaxPayout > 0 && q.MinPayout > q.MaxPayout {
+		return fmt.Errorf("min_payout must not exceed max_payout")
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	if q.Limit > MaxSearchLimit {
+		q.Limit = MaxSearchLimit
+	}
+	for i := range q.Predicates {
+		p := &q.Predicates[i]
+		steps, err := compilePath(p.Path)
+		if err != nil {
+			return fmt.Errorf("predicate %d: %w", i, err)
+		}
+		p.steps = steps
+		switch p.Op {
+		case "exists", "not_exists":
+		case "eq", "ne", "contains":
+			if p.Value == nil {
+				return fmt.Errorf("predicate %d: op %s requires a value", i, p.Op)
+			}
+		case "gt", "gte", "lt", "lte", "count_eq", "count_gt", "count_gte", "count_lt", "count_lte":
+			if _, ok := p.Value.(float64); !ok {
+				return fmt.Errorf("predicate %d: op %s requires a numeric value", i, p.Op)
+			}
+		default:
+			return fmt.Errorf("predicate %d: unknown op %q", i, p.Op)
+		}
+	}
+	return nil
+}
+
+// inPayoutBand reports whether a payout (x100) lies within the query bounds.
+func (q *EventQuery) inPayoutBand(payout uint) bool {
+	x := float64(payout) / 100.0
+	if q.MinPayout > 0 && x < q.MinPayout {
+		return false
+	}
+	if q.MaxPayout > 0 && x > q.MaxPayout {
+		return false
+	}
+	return true
+}
+
+// Matches evaluates the predicates against a decoded book.
+func (q *EventQuery) Matches(book any) bool {
+	if len(q.Predicates) == 0 {
+		return true
+	}
+	for i := range q.Predicates {
+		ok := q.Predicates[i].eval(book)
+		if q.Match == "any" && ok {
+			return true
+		}
+		if q.Match == "all" && !ok {
+			return false
+		}
+	}
+	return q.Match == "all"
+}
+
+func compilePath(path string) ([]pathStep, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return nil, nil
+	}
+	var steps []pathStep
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		brackets := ""
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			key, brackets = part[:i], part[i:]
+		}
+		if key == "" && brackets == "" {
+			return nil, fmt.Errorf("invalid path %q: empty segment", path)
+		}
+		if key != "" {
+			steps = append(steps, pathStep{key: key, index: -1})
+		}
+		for brackets != "" {
+			end := strings.IndexByte(brackets, ']')
+			if brackets[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unbalanced brackets", path)
+			}
+			inner := brackets[1:end]
+			brackets = brackets[end+1:]
+			if inner == "*" {
+				steps = append(steps, pathStep{index: -1, wildcard: true})
+				continue
+			}
+			n, err := strconv.Atoi(inner)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, inner)
+			}
+			steps = append(steps, pathStep{index: n})
+		}
+	}
+	return steps, nil
+}
+
+// resolve returns every value the compiled path reaches in v.
+func resolve(v any, steps []pathStep) []any {
+	values := []any{v}
+	for _, step := range steps {
+		var next []any
+		for _, cur := range values {
+			switch {
+			case step.key != "":
+				if obj, ok := cur.(map[string]any); ok {
+					if child, ok := obj[step.key]; ok {
+						next = append(next, child)
+					}
+				}
+			case step.wildcard:
+				if arr, ok := cur.([]any); ok {
+					next = append(next, arr...)
+				}
+			default:
+				if arr, ok := cur.([]any); ok && step.index < len(arr) {
+					next = append(next, arr[step.index])
+				}
+			}
+		}
+		values = next
+		if len(values) == 0 {
+			break
+		}
+	}
+	return values
+}
+
+func (p *EventPredicate) eval(book any) bool {
+	values := resolve(book, p.steps)
+
+	switch p.Op {
+	case "exists":
+		return len(values) > 0
+	case "not_exists":
+		return len(values) == 0
+	case "ne":
+		for _, v := range values {
+			if jsonEqual(v, p.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if strings.HasPrefix(p.Op, "count_") {
+		count := 0
+		for _, v := range values {
+			if p.Where == nil || jsonEqual(v, p.Where) {
+				count++
+			}
+		}
+		return compareNumber(strings.TrimPrefix(p.Op, "count_"), float64(count), p.Value.(float64))
+	}
+
+	for _, v := range values {
+		switch p.Op {
+		case "eq":
+			if jsonEqual(v, p.Value) {
+				return true
+			}
+		case "contains":
+			if containsValue(v, p.Value) {
+				return true
+			}
+		default:
+			if n, ok := v.(float64); ok && compareNumber(p.Op, n, p.Value.(float64)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func compareNumber(op string, a, b float64) bool {
+	switch op {
+	case "eq":
+		return a == b
+	case "gt":
+		return a > b
+	case "gte":
+		return a >= b
+	case "lt":
+		return a < b
+	case "lte":
+		return a <= b
+	}
+	return false
+}
+
+func jsonEqual(a, b any) bool {
+	switch av := a.(type) {
+	case float64, string, bool, nil:
+		return a == b
+	default:
+		return reflect.DeepEqual(av, b)
+	}
+}
+
+// containsValue matches substrings of strings and elements of arrays.
+func containsValue(v, want any) bool {
+	switch vv := v.(type) {
+	case string:
+		s, ok := want.(string)
+		return ok && strings.Contains(vv, s)
+	case []any:
+		for _, elem := range vv {
+			if jsonEqual(elem, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ForEachEvent calls fn for every book of a mode, in line order. Events
+// already loaded into memory are used directly; otherwise the file is
+// streamed. It returns "memory", "disk" or "stream" to tell which source
+// was read.
+// Returning errStopScan from fn ends the scan early without error.
+func (e *EventsLoader) ForEachEvent(mode, eventsFile string, fn func(lineIndex int, event json.RawMessage) error) (string, error) {
+	e.mu.RLock()
+	index, ok := e.findModeLocked(mode)
+	store, onDisk := e.findStoreLocked(mode)
+	e.mu.RUnlock()
+
+	if onDisk {
+		err := store.ForEach(fn)
+		if errors.Is(err, errStopScan) {
+			err = nil
+		}
+		return "disk", err
+	}
+	if !ok {
+		err := e.StreamEvents(eventsFile, fn)
+		if errors.Is(err, errStopScan) {
+			err = nil
+		}
+		return "stream", err
+	}
+
+	lines := make([]int, 0, len(index.Events))
+	for line := range index.Events {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	for _, line := range lines {
+		if err := fn(line, index.Events[line]); err != nil {
+			if errors.Is(err, errStopScan) {
+				break
+			}
+			return "memory", err
+		}
+	}
+	return "memory", nil
+}
+
+// SearchEvents finds books of a mode that satisfy the query. Books outside
+// the payout band are skipped before their JSON is decoded. The query must
+// have been validated.
+func (e *EventsLoader) SearchEvents(mode string, table *stakergs.LookupTable, eventsFile string, query *EventQuery) (*EventSearchResult, error) {
+	bySimID := make(map[int]*stakergs.Outcome, len(table.Outcomes))
+	for i := range table.Outcomes {
+		bySimID[table.Outcomes[i].SimID] = &table.Outcomes[i]
+	}
+	totalWeight := table.TotalWeight()
+
+	result := &EventSearchResult{
+		Mode:    mode,
+		Matches: []EventMatch{},
+	}
+
+	source, err := e.ForEachEvent(mode, eventsFile, func(lineIndex int, event json.RawMessage) error {
+		outcome, ok := bySimID[lineIndex+table.SimIDOffset]
+		if !ok || !query.inPayoutBand(outcome.Payout) || len(bytes.TrimSpace(event)) == 0 {
+			return nil
+		}
+
+		var book any
+		if err := json.Unmarshal(event, &book); err != nil {
+			return fmt.Errorf("invalid event at line %d: %w", lineIndex, err)
+		}
+		result.Scanned++
+		if !query.Matches(book) {
+			return nil
+		}
+
+		result.TotalMatches++
+		result.MatchWeight += outcome.Weight
+		if len(result.Matches) < query.Limit {
+			result.Matches = append(result.Matches, EventMatch{
+				SimID:       outcome.SimID,
+				Payout:      float64(outcome.Payout) / 100.0,
+				Weight:      outcome.Weight,
+				Probability: float64(outcome.Weight) / float64(totalWeight),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Source = source
+	result.Truncated = result.TotalMatches > len(result.Matches)
+	if totalWeight > 0 {
+		result.Probability = float64(result.MatchWeight) / float64(totalWeight)
+	}
+	return result, nil
+}
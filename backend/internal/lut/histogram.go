@@ -0,0 +1,115 @@
+package lut
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultHistogramBins is the number of bins used when none is requested.
+const DefaultHistogramBins = 20
+
+// RTPDistribution shows how realized RTP spreads across trials after a
+// given number of spins.
+type RTPDistribution struct {
+	SpinCount   int            `json:"spin_count"`
+	Bins        []HistogramBin `json:"bins"`
+	Percentiles Percentiles    `json:"percentiles"`
+	Min         float64        `json:"min"`
+	Max         float64        `json:"max"`
+}
+
+// HistogramBin is one bin of an RTP histogram. Bins cover [RangeStart, RangeEnd);
+// the last bin also includes RangeEnd.
+type HistogramBin struct {
+	RangeStart float64 `json:"range_start"`
+	RangeEnd   float64 `json:"range_end"`
+	Count      int     `json:"count"`
+	Share      float64 `json:"share"` // fraction of trials
+}
+
+// Percentiles of realized RTP across trials.
+type Percentiles struct {
+	P1  float64 `json:"p1"`
+	P5  float64 `json:"p5"`
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// NewRTPDistribution bins per-trial RTPs into equal-width bins between
+// their minimum and maximum and computes percentiles.
+func NewRTPDistribution(spinCount int, rtps []float64, bins int) RTPDistribution {
+	dist := RTPDistribution{SpinCount: spinCount}
+	if len(rtps) == 0 {
+		return dist
+	}
+	if bins <= 0 {
+		bins = DefaultHistogramBins
+	}
+
+	sorted := append([]float64(nil), rtps...)
+	sort.Float64s(sorted)
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	dist.Min = round4(lo)
+	dist.Max = round4(hi)
+
+	if hi == lo {
+		bins = 1 // every trial realized the same RTP
+	}
+	width := (hi - lo) / float64(bins)
+	counts := make([]int, bins)
+	for _, v := range sorted {
+		idx := bins - 1
+		if width > 0 {
+			idx = int((v - lo) / width)
+			if idx >= bins {
+				idx = bins - 1
+			}
+		}
+		counts[idx]++
+	}
+
+	dist.Bins = make([]HistogramBin, bins)
+	for i, c := range counts {
+		dist.Bins[i] = HistogramBin{
+			RangeStart: round4(lo + float64(i)*width),
+			RangeEnd:   round4(lo + float64(i+1)*width),
+			Count:      c,
+			Share:      round4(float64(c) / float64(len(sorted))),
+		}
+	}
+	if bins == 1 {
+		dist.Bins[0].RangeEnd = round4(hi)
+	}
+
+	dist.Percentiles = Percentiles{
+		P1:  round4(percentile(sorted, 0.01)),
+		P5:  round4(percentile(sorted, 0.05)),
+		P10: round4(percentile(sorted, 0.10)),
+		P25: round4(percentile(sorted, 0.25)),
+		P50: round4(percentile(sorted, 0.50)),
+		P75: round4(percentile(sorted, 0.75)),
+		P90: round4(percentile(sorted, 0.90)),
+		P95: round4(percentile(sorted, 0.95)),
+		P99: round4(percentile(sorted, 0.99)),
+	}
+	return dist
+}
+
+// percentile returns the q-quantile of sorted values, interpolating linearly.
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := q * float64(len(sorted)-1)
+	i := int(math.Floor(pos))
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + frac*(sorted[i+1]-sorted[i])
+}
@@ -0,0 +1,78 @@
+package lut
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// DecompressionRatio is the typical zstd ratio for JSONL books (~10-15x).
+	DecompressionRatio = 12.0
+	// StorageOverhead is the extra memory books take once parsed into maps.
+	StorageOverhead = 1.2
+	// LoadBytesPerSecond is the approximate decompressed throughput of book
+	// loading at high priority; low priority takes about twice as long.
+	LoadBytesPerSecond = 100 << 20
+)
+
+// ModeSize is the disk and memory footprint of one mode.
+type ModeSize struct {
+	Mode              string   `json:"mode"`
+	LUTBytes          int64    `json:"lut_bytes"`
+	BooksBytes        int64    `json:"books_bytes"`
+	DecompressedBytes int64    `json:"decompressed_bytes"` // estimated
+	MemoryBytes       int64    `json:"memory_bytes"`       // estimated, books fully loaded
+	LoadSeconds       float64  `json:"load_seconds"`       // estimated at high priority
+	LoadMs            int64    `json:"load_ms,omitempty"`  // measured, once books were loaded
+	Missing           []string `json:"missing,omitempty"`  // files listed in the index but not on disk
+}
+
+// LibrarySize sums the footprint of all modes, so users can tell how much
+// memory and time loading every book will take before starting it.
+type LibrarySize struct {
+	Modes             []ModeSize `json:"modes"`
+	LUTBytes          int64      `json:"lut_bytes"`
+	BooksBytes        int64      `json:"books_bytes"`
+	DecompressedBytes int64      `json:"decompressed_bytes"`
+	MemoryBytes       int64      `json:"memory_bytes"`
+	LoadSeconds       float64    `json:"load_seconds"`
+}
+
+// LibrarySize stats the files of every mode in the index.
+func (l *Loader) LibrarySize() *LibrarySize {
+	index := l.GetIndex()
+	size := &LibrarySize{Modes: []ModeSize{}}
+	if index == nil {
+		return size
+	}
+	baseDir := l.BaseDir()
+
+	for _, mode := range index.Modes {
+		m := ModeSize{Mode: mode.Name}
+		if info, err := os.Stat(filepath.Join(baseDir, mode.Weights)); err == nil {
+			m.LUTBytes = info.Size()
+		} else {
+			m.Missing = append(m.Missing, mode.Weights)
+		}
+		if mode.Events != "" {
+			if info, err := os.Stat(filepath.Join(baseDir, mode.Events)); err == nil {
+				m.BooksBytes = info.Size()
+			} else {
+				m.Missing = append(m.Missing, mode.Events)
+			}
+		}
+
+		m.DecompressedBytes = int64(float64(m.BooksBytes) * DecompressionRatio)
+		m.MemoryBytes = int64(float64(m.DecompressedBytes) * StorageOverhead)
+		m.LoadSeconds = round2(float64(m.DecompressedBytes) / LoadBytesPerSecond)
+
+		size.LUTBytes += m.LUTBytes
+		size.BooksBytes += m.BooksBytes
+		size.DecompressedBytes += m.DecompressedBytes
+		size.MemoryBytes += m.MemoryBytes
+		size.Modes = append(size.Modes, m)
+	}
+	size.LoadSeconds = round2(float64(size.DecompressedBytes) / LoadBytesPerSecond)
+
+	return size
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"stakergs"
@@ -24,6 +27,11 @@ type Loader struct {
 	eventsLoader      *EventsLoader
 	simulator         *Simulator
 	distributionCache *DistributionCache
+	statsCache        *StatsCache
+	samplers          *samplerCache
+	variants          *variantStore
+	syntheticEvents   atomic.Bool  // serve generated books for modes without events
+	mu                sync.RWMutex // guards paths, index and tables, which are swapped together
 }
 
 // NewLoader creates a new LUT loader for the given index file path.
@@ -37,6 +45,9 @@ func NewLoader(indexPath string) *Loader {
 		eventsLoader:      NewEventsLoader(baseDir),
 		simulator:         NewSimulator(),
 		distributionCache: NewDistributionCache(),
+		statsCache:        NewStatsCache(),
+		samplers:          newSamplerCache(),
+		variants:          newVariantStore(),
 	}
 }
 
@@ -54,56 +65,159 @@ func NewLoaderFromLibrary(libraryPath string) *Loader {
 		eventsLoader:      NewEventsLoader(publishFilesDir),
 		simulator:         NewSimulator(),
 		distributionCache: NewDistributionCache(),
+		statsCache:        NewStatsCache(),
+		samplers:          newSamplerCache(),
+		variants:          newVariantStore(),
+	}
+}
+
+// NewEmptyLoader creates a loader with no library open. Use OpenLibrary to
+// load one later.
+func NewEmptyLoader() *Loader {
+	return &Loader{
+		tables:            make(map[string]*stakergs.LookupTable),
+		analyzer:          NewAnalyzer(),
+		eventsLoader:      NewEventsLoader(""),
+		simulator:         NewSimulator(),
+		distributionCache: NewDistributionCache(),
+		statsCache:        NewStatsCache(),
+		samplers:          newSamplerCache(),
+		variants:          newVariantStore(),
 	}
 }
 
 // LibraryDir returns the root library folder path (parent of publish_files).
 // Returns empty string if loader was created with NewLoader (not NewLoaderFromLibrary).
 func (l *Loader) LibraryDir() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.libraryDir
 }
 
+// IsOpen reports whether an index is loaded.
+func (l *Loader) IsOpen() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.index != nil
+}
+
+// OpenLibrary loads another library folder and switches to it. The current
+// library keeps serving until the new one has loaded; on error it stays
+// open. Cached events, distributions and variants of the old library are dropped.
+func (l *Loader) OpenLibrary(libraryPath string) error {
+	absLibrary, err := filepath.Abs(libraryPath)
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	indexPath := filepath.Join(absLibrary, "publish_files", "index.json")
+
+	baseDir, index, tables, err := readLibrary(indexPath)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.indexPath = indexPath
+	l.baseDir = baseDir
+	l.libraryDir = absLibrary
+	l.index = index
+	l.tables = tables
+	l.mu.Unlock()
+
+	l.eventsLoader.UnloadAll()
+	l.eventsLoader.SetBaseDir(baseDir)
+	l.distributionCache.InvalidateAll()
+	l.statsCache.InvalidateAll()
+	l.samplers.invalidateAll()
+	l.variants.clear()
+	return nil
+}
+
+// Close unloads the current library, leaving the loader empty.
+func (l *Loader) Close() {
+	l.mu.Lock()
+	l.indexPath = ""
+	l.libraryDir = ""
+	l.index = nil
+	l.tables = make(map[string]*stakergs.LookupTable)
+	l.mu.Unlock()
+
+	l.eventsLoader.UnloadAll()
+	l.distributionCache.InvalidateAll()
+	l.statsCache.InvalidateAll()
+	l.samplers.invalidateAll()
+	l.variants.clear()
+}
+
 // Simulator returns the LUT simulator.
 func (l *Loader) Simulator() *Simulator {
 	return l.simulator
 }
 
 // Load reads and parses the index.json file and all referenced LUT CSV files.
+// Everything is read into a standby index and table set first; the loaded
+// state is only replaced once all of it parsed, so a failed or slow load
+// never leaves callers without modes.
 func (l *Loader) Load() error {
-	absPath, err := filepath.Abs(l.indexPath)
+	indexPath := l.IndexPath()
+	if indexPath == "" {
+		return fmt.Errorf("no library open")
+	}
+
+	baseDir, index, tables, err := readLibrary(indexPath)
 	if err != nil {
-		return fmt.Errorf("invalid path: %w", err)
+		return err
 	}
-	l.baseDir = filepath.Dir(absPath)
+
+	l.mu.Lock()
+	l.baseDir = baseDir
+	l.index = index
+	l.tables = tables
+	l.mu.Unlock()
+
+	return nil
+}
+
+// readLibrary reads an index file and all LUT CSV files it references.
+func readLibrary(indexPath string) (string, *stakergs.GameIndex, map[string]*stakergs.LookupTable, error) {
+	absPath, err := filepath.Abs(indexPath)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid path: %w", err)
+	}
+	baseDir := filepath.Dir(absPath)
 
 	data, err := os.ReadFile(absPath)
 	if err != nil {
-		return fmt.Errorf("failed to read index file: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to read index file: %w", err)
 	}
 
 	var index stakergs.GameIndex
 	if err := json.Unmarshal(data, &index); err != nil {
-		return fmt.Errorf("failed to parse index file: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to parse index file: %w", err)
 	}
 
-	l.index = &index
-
 	// Load all LUT CSV files
+	tables := make(map[string]*stakergs.LookupTable, len(index.Modes))
 	for _, mode := range index.Modes {
-		table, err := l.loadCSV(mode)
+		table, err := readCSV(baseDir, mode)
 		if err != nil {
-			return fmt.Errorf("failed to load LUT for mode %q: %w", mode.Name, err)
+			return "", nil, nil, fmt.Errorf("failed to load LUT for mode %q: %w", mode.Name, err)
 		}
-		l.tables[mode.Name] = table
+		tables[mode.Name] = table
 	}
 
-	return nil
+	return baseDir, &index, tables, nil
 }
 
-// loadCSV reads a LUT CSV file and returns a LookupTable.
-// CSV format: sim_id,weight,payout (no header)
+// loadCSV reads a LUT CSV file of the open library.
 func (l *Loader) loadCSV(mode stakergs.ModeConfig) (*stakergs.LookupTable, error) {
-	csvPath := filepath.Join(l.baseDir, mode.Weights)
+	return readCSV(l.BaseDir(), mode)
+}
+
+// readCSV reads a LUT CSV file and returns a LookupTable.
+// CSV format: sim_id,weight,payout (no header)
+func readCSV(baseDir string, mode stakergs.ModeConfig) (*stakergs.LookupTable, error) {
+	csvPath := filepath.Join(baseDir, mode.Weights)
 
 	file, err := os.Open(csvPath)
 	if err != nil {
@@ -175,11 +289,16 @@ func (l *Loader) loadCSV(mode stakergs.ModeConfig) (*stakergs.LookupTable, error
 
 // GetIndex returns the loaded game index.
 func (l *Loader) GetIndex() *stakergs.GameIndex {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.index
 }
 
 // GetMode returns a specific mode's lookup table.
 func (l *Loader) GetMode(mode string) (*stakergs.LookupTable, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	if l.index == nil {
 		return nil, fmt.Errorf("index not loaded")
 	}
@@ -197,6 +316,9 @@ func (l *Loader) GetMode(mode string) (*stakergs.LookupTable, error) {
 
 // ListModes returns all available mode names.
 func (l *Loader) ListModes() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	if l.index == nil {
 		return nil
 	}
@@ -210,6 +332,8 @@ func (l *Loader) ListModes() []string {
 
 // IndexPath returns the path to the loaded index file.
 func (l *Loader) IndexPath() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.indexPath
 }
 
@@ -218,6 +342,17 @@ func (l *Loader) Analyzer() *Analyzer {
 	return l.analyzer
 }
 
+// Statistics returns the (cached) statistics of a table. The result is
+// shared and must not be modified.
+func (l *Loader) Statistics(table *stakergs.LookupTable) *Statistics {
+	return l.statsCache.Statistics(table)
+}
+
+// StatsCache returns the statistics cache.
+func (l *Loader) StatsCache() *StatsCache {
+	return l.statsCache
+}
+
 // DistributionCache returns the distribution cache.
 func (l *Loader) DistributionCache() *DistributionCache {
 	return l.distributionCache
@@ -235,6 +370,9 @@ type ModeSummary struct {
 
 // GetModeSummaries returns summaries for all modes.
 func (l *Loader) GetModeSummaries() []ModeSummary {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	if l.index == nil {
 		return nil
 	}
@@ -259,6 +397,9 @@ func (l *Loader) GetModeSummaries() []ModeSummary {
 
 // GetModeConfig returns the configuration for a specific mode.
 func (l *Loader) GetModeConfig(mode string) (*stakergs.ModeConfig, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	if l.index == nil {
 		return nil, fmt.Errorf("index not loaded")
 	}
@@ -318,23 +459,39 @@ func (l *Loader) GetOutcome(mode string, simID int) (*OutcomeStats, error) {
 
 // BaseDir returns the base directory for data files.
 func (l *Loader) BaseDir() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
 	return l.baseDir
 }
 
 // Reload re-reads the index.json and all LUT CSV files from disk.
-// This clears all loaded events and resets the loader state.
+// The current index and tables keep serving while the new ones load and
+// are then switched in one step; on error the old state stays in place.
+// Fully loaded events keep serving until the background loader replaces
+// them; events of modes no longer in the index are dropped.
 func (l *Loader) Reload() error {
-	// Clear events cache first
-	l.eventsLoader.ClearAll()
+	if err := l.Load(); err != nil {
+		return err
+	}
 
-	// Clear distribution cache
 	l.distributionCache.InvalidateAll()
+	l.statsCache.InvalidateAll()
+	l.samplers.invalidateAll()
+	l.eventsLoader.ClearChunks()
+
+	l.mu.RLock()
+	present := make(map[string]bool, len(l.tables))
+	for name := range l.tables {
+		present[strings.ToLower(name)] = true
+	}
+	l.mu.RUnlock()
+	for _, mode := range l.eventsLoader.GetLoadedModes() {
+		if !present[strings.ToLower(mode)] {
+			l.eventsLoader.UnloadMode(mode)
+		}
+	}
 
-	// Clear tables
-	l.tables = make(map[string]*stakergs.LookupTable)
-
-	// Reload index and tables
-	return l.Load()
+	return nil
 }
 
 // ReloadModeTable reloads just the lookup table for a specific mode from disk.
@@ -350,15 +507,168 @@ func (l *Loader) ReloadModeTable(modeName string) error {
 		return fmt.Errorf("failed to reload LUT for mode %q: %w", modeName, err)
 	}
 
+	l.mu.Lock()
 	l.tables[modeName] = table
+	l.mu.Unlock()
 	l.distributionCache.Invalidate(modeName)
+	l.statsCache.Invalidate(modeName)
+	l.samplers.invalidate(modeName)
+
+	return nil
+}
+
+// ReloadIndex re-reads index.json and reconciles the loaded modes with it.
+// Tables are loaded for modes that were added to the index, and tables, events,
+// variants and cached distributions are dropped for modes that were removed.
+// Modes present in both keep their in-memory state. Returns the names of added and removed modes.
+func (l *Loader) ReloadIndex() (added, removed []string, err error) {
+	data, err := os.ReadFile(l.IndexPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read index file: %w", err)
+	}
+
+	var index stakergs.GameIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse index file: %w", err)
+	}
+
+	// Load tables for new modes before touching loader state,
+	// so a broken CSV leaves the previous index in place.
+	newTables := make(map[string]*stakergs.LookupTable)
+	present := make(map[string]bool, len(index.Modes))
+	for _, mode := range index.Modes {
+		present[mode.Name] = true
+		l.mu.RLock()
+		_, ok := l.tables[mode.Name]
+		l.mu.RUnlock()
+		if ok {
+			continue
+		}
+		table, err := l.loadCSV(mode)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load LUT for mode %q: %w", mode.Name, err)
+		}
+		newTables[mode.Name] = table
+		added = append(added, mode.Name)
+	}
+
+	l.mu.Lock()
+	for name := range l.tables {
+		if !present[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	for _, name := range removed {
+		delete(l.tables, name)
+	}
+	for name, table := range newTables {
+		l.tables[name] = table
+	}
+	l.index = &index
+	l.mu.Unlock()
+
+	for _, name := range removed {
+		l.dropModeState(name)
+	}
+
+	return added, removed, nil
+}
+
+// dropModeState clears the events, caches and variants of a mode that was
+// removed from the index.
+func (l *Loader) dropModeState(name string) {
+	l.eventsLoader.ClearMode(name)
+	l.distributionCache.Invalidate(name)
+	l.statsCache.Invalidate(name)
+	l.samplers.invalidate(name)
+	l.variants.clearMode(name)
+}
 
+// AddMode registers a single mode at runtime without reloading the library.
+// Its table is loaded from the mode's weights file, which (like the events
+// file) must be a path inside the library's publish folder. The change is
+// in memory only: index.json is not rewritten.
+func (l *Loader) AddMode(config stakergs.ModeConfig) error {
+	if !l.IsOpen() {
+		return fmt.Errorf("no library open")
+	}
+	config.Name = strings.TrimSpace(config.Name)
+	if config.Name == "" {
+		return fmt.Errorf("mode name is required")
+	}
+	if config.Weights == "" || !filepath.IsLocal(config.Weights) {
+		return fmt.Errorf("weights must be a file inside the library folder")
+	}
+	if config.Events != "" && !filepath.IsLocal(config.Events) {
+		return fmt.Errorf("events must be a file inside the library folder")
+	}
+	if config.Cost <= 0 {
+		config.Cost = 1.0
+	}
+	if _, err := l.GetModeConfig(config.Name); err == nil {
+		return fmt.Errorf("mode %q already exists", config.Name)
+	}
+
+	table, err := l.loadCSV(config)
+	if err != nil {
+		return fmt.Errorf("failed to load LUT for mode %q: %w", config.Name, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.index == nil {
+		return fmt.Errorf("no library open")
+	}
+	for _, mode := range l.index.Modes {
+		if strings.EqualFold(mode.Name, config.Name) {
+			return fmt.Errorf("mode %q already exists", config.Name)
+		}
+	}
+	// Copy the index: readers may hold the previous one
+	index := &stakergs.GameIndex{Modes: append(append([]stakergs.ModeConfig{}, l.index.Modes...), config)}
+	l.index = index
+	l.tables[config.Name] = table
 	return nil
 }
 
+// RemoveMode unregisters a mode at runtime (case-insensitive), dropping its
+// table, events, cached statistics and variants. index.json is not
+// rewritten. Returns the mode's name as registered.
+func (l *Loader) RemoveMode(mode string) (string, error) {
+	l.mu.Lock()
+	if l.index == nil {
+		l.mu.Unlock()
+		return "", fmt.Errorf("no library open")
+	}
+	name := ""
+	modes := make([]stakergs.ModeConfig, 0, len(l.index.Modes))
+	for _, m := range l.index.Modes {
+		if strings.EqualFold(m.Name, mode) {
+			name = m.Name
+			continue
+		}
+		modes = append(modes, m)
+	}
+	if name == "" {
+		l.mu.Unlock()
+		return "", fmt.Errorf("mode %q not found", mode)
+	}
+	l.index = &stakergs.GameIndex{Modes: modes}
+	delete(l.tables, name)
+	l.mu.Unlock()
+
+	l.dropModeState(name)
+	return name, nil
+}
+
 // GetCSVFiles returns a map of CSV weight filenames to mode names.
 // Example: {"lookUpTable_base_0.csv": "base", "lookUpTable_bonus_0.csv": "bonus"}
 func (l *Loader) GetCSVFiles() map[string]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	if l.index == nil {
 		return nil
 	}
@@ -391,7 +701,7 @@ func (l *Loader) SaveWeights(mode string, weights []uint64) error {
 		return fmt.Errorf("mode config not found: %w", err)
 	}
 
-	csvPath := filepath.Join(l.baseDir, config.Weights)
+	csvPath := filepath.Join(l.BaseDir(), config.Weights)
 
 	// Create temp file in same directory for atomic write
 	tmpPath := csvPath + ".tmp"
@@ -436,10 +746,64 @@ func (l *Loader) SaveWeights(mode string, weights []uint64) error {
 
 	// Invalidate distribution cache for this mode
 	l.distributionCache.Invalidate(mode)
+	l.statsCache.Invalidate(mode)
+	l.samplers.invalidate(mode)
 
 	return nil
 }
 
+// SetOutcomeWeight changes the weight of one outcome and returns the updated
+// table with the outcome's previous weight. The edit is made on a copy that
+// replaces the loaded table, so readers of the old table are unaffected and
+// caches keyed by the table miss. Use SaveWeights to write it to disk.
+func (l *Loader) SetOutcomeWeight(mode string, simID int, weight uint64) (*stakergs.LookupTable, uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.index == nil {
+		return nil, 0, fmt.Errorf("index not loaded")
+	}
+	var name string
+	var table *stakergs.LookupTable
+	for n, t := range l.tables {
+		if strings.EqualFold(n, mode) {
+			name, table = n, t
+			break
+		}
+	}
+	if table == nil {
+		return nil, 0, fmt.Errorf("mode %q not found", mode)
+	}
+
+	idx := -1
+	for i, outcome := range table.Outcomes {
+		if outcome.SimID == simID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, 0, fmt.Errorf("sim_id %d not found in mode %q", simID, mode)
+	}
+
+	old := table.Outcomes[idx].Weight
+	if weight == 0 && table.TotalWeight() == old {
+		return nil, 0, fmt.Errorf("cannot set the only weighted outcome of mode %q to zero", mode)
+	}
+
+	updated := *table
+	updated.Outcomes = make([]stakergs.Outcome, len(table.Outcomes))
+	copy(updated.Outcomes, table.Outcomes)
+	updated.Outcomes[idx].Weight = weight
+	l.tables[name] = &updated
+
+	l.distributionCache.Invalidate(name)
+	l.statsCache.Invalidate(name)
+	l.samplers.invalidate(name)
+
+	return &updated, old, nil
+}
+
 // SaveWeightsWithBackup saves new weights and creates a backup of the original file.
 // Returns the path to the backup file.
 func (l *Loader) SaveWeightsWithBackup(mode string, weights []uint64) (string, error) {
@@ -449,7 +813,7 @@ func (l *Loader) SaveWeightsWithBackup(mode string, weights []uint64) (string, e
 		return "", fmt.Errorf("mode config not found: %w", err)
 	}
 
-	csvPath := filepath.Join(l.baseDir, config.Weights)
+	csvPath := filepath.Join(l.BaseDir(), config.Weights)
 
 	// Create backup with timestamp
 	timestamp := time.Now().Format("20060102_150405")
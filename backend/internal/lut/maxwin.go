@@ -0,0 +1,119 @@
+package lut
+
+import (
+	"fmt"
+	"math"
+
+	"lutexplorer/internal/numfmt"
+
+	"stakergs"
+)
+
+// MaxWinPercentiles are the chances of having hit the max win that
+// EstimateMaxWin reports spin counts for.
+var MaxWinPercentiles = []float64{0.10, 0.25, 0.50, 0.75, 0.90, 0.95, 0.99}
+
+// MaxWinPercentile is the number of spins (and turnover) after which the max
+// win has been hit at least once with the given probability.
+type MaxWinPercentile struct {
+	Percentile float64 `json:"percentile"`
+	Spins      int64   `json:"spins"`
+	Turnover   float64 `json:"turnover"`
+}
+
+// MaxWinEstimate answers "how long until a player hits the max win?".
+type MaxWinEstimate struct {
+	Mode        string  `json:"mode"`
+	Cost        float64 `json:"cost"`
+	Bet         float64 `json:"bet"`
+	MaxWin      float64 `json:"max_win"`
+	Threshold   float64 `json:"threshold"` // payouts at or above this multiplier count as a hit
+	Outcomes    int     `json:"outcomes"`  // outcomes at or above the threshold
+	Probability float64 `json:"probability"`
+	Odds        string  `json:"odds"`
+
+	ExpectedSpins    float64 `json:"expected_spins"`
+	ExpectedTurnover float64 `json:"expected_turnover"` // spins × cost × bet
+	// ExpectedLoss is what the player is expected to lose over those spins
+	// at the mode's RTP, before the max win itself is paid.
+	ExpectedLoss float64 `json:"expected_loss"`
+
+	Percentiles []MaxWinPercentile `json:"percentiles"`
+	Summary     string             `json:"summary"`
+}
+
+// EstimateMaxWin computes the spins and turnover needed to hit the max
+// win, or any payout of at least threshold (bet multiples; 0 = max win), at a
+// base bet of bet. Spins until the first hit are geometrically distributed, so
+// the figures are exact rather than sampled.
+func EstimateMaxWin(table *stakergs.LookupTable, bet, threshold float64) (*MaxWinEstimate, error) {
+	if bet <= 0 {
+		bet = 1
+	}
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1
+	}
+	totalWeight := table.TotalWeight()
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("mode %q has zero total weight", table.Mode)
+	}
+
+	maxPayout := table.MaxPayout()
+	minPayout := maxPayout
+	if threshold > 0 {
+		minPayout = uint(math.Round(threshold * 100))
+	}
+
+	result := &MaxWinEstimate{
+		Mode:      table.Mode,
+		Cost:      cost,
+		Bet:       bet,
+		MaxWin:    float64(maxPayout) / 100.0,
+		Threshold: float64(minPayout) / 100.0,
+		Odds:      "-",
+	}
+
+	var hitWeight uint64
+	for _, o := range table.Outcomes {
+		if o.Payout >= minPayout && o.Weight > 0 {
+			result.Outcomes++
+			hitWeight += o.Weight
+		}
+	}
+	if hitWeight == 0 {
+		return nil, fmt.Errorf("no outcome of mode %q pays %.2fx or more with non-zero weight", table.Mode, result.Threshold)
+	}
+
+	p := float64(hitWeight) / float64(totalWeight)
+	spinCost := cost * bet
+	result.Probability = p
+	result.Odds = formatOdds(1 / p)
+	result.ExpectedSpins = round2(1 / p)
+	result.ExpectedTurnover = round2(result.ExpectedSpins * spinCost)
+	mean, _ := SpinRTPStats(table)
+	result.ExpectedLoss = round2(result.ExpectedTurnover * (1 - mean))
+
+	for _, q := range MaxWinPercentiles {
+		spins := int64(1)
+		if p < 1 {
+			spins = int64(math.Ceil(math.Log1p(-q) / math.Log1p(-p)))
+		}
+		result.Percentiles = append(result.Percentiles, MaxWinPercentile{
+			Percentile: q,
+			Spins:      spins,
+			Turnover:   round2(float64(spins) * spinCost),
+		})
+	}
+
+	result.Summary = result.Describe(numfmt.English)
+
+	return result, nil
+}
+
+// Describe summarizes the estimate in one sentence, with numbers formatted
+// for locale.
+func (e *MaxWinEstimate) Describe(locale *numfmt.Locale) string {
+	return fmt.Sprintf("On average ~%s spins / %s turnover at %s per spin to hit %sx once",
+		locale.Compact(e.ExpectedSpins), locale.Compact(e.ExpectedTurnover), locale.Number(e.Cost*e.Bet, 2), locale.Number(e.Threshold, 2))
+}
@@ -0,0 +1,223 @@
+package lut
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+
+	"stakergs"
+)
+
+// Outcome table export formats.
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatJSONL   = "jsonl"
+	ExportFormatParquet = "parquet"
+)
+
+// exportChunk is how many rows are written between flushes.
+const exportChunk = 10000
+
+// OutcomeExportRow is one outcome with its derived columns, as written by
+// WriteOutcomes. Payout is the raw multiplier; RTPContribution is normalized
+// by mode cost so the column sums to the mode RTP.
+type OutcomeExportRow struct {
+	SimID           int64   `parquet:"sim_id" json:"sim_id"`
+	Weight          uint64  `parquet:"weight" json:"weight"`
+	Payout          float64 `parquet:"payout" json:"payout"`
+	Probability     float64 `parquet:"probability" json:"probability"`
+	Odds            float64 `parquet:"odds" json:"odds"` // 1 in N, 0 if never hit
+	RTPContribution float64 `parquet:"rtp_contribution" json:"rtp_contribution"`
+	Bucket          string  `parquet:"bucket,dict" json:"bucket"` // PayoutBands name
+}
+
+var outcomeExportColumns = []string{"sim_id", "weight", "payout", "probability", "odds", "rtp_contribution", "bucket"}
+
+// ExportContentType returns the MIME type and file extension of a format.
+func ExportContentType(format string) (contentType, ext string, err error) {
+	switch strings.ToLower(format) {
+	case ExportFormatCSV:
+		return "text/csv", "csv", nil
+	case ExportFormatJSONL:
+		return "application/x-ndjson", "jsonl", nil
+	case ExportFormatParquet:
+		return "application/vnd.apache.parquet", "parquet", nil
+	}
+	return "", "", fmt.Errorf("unknown export format %q: want csv, jsonl or parquet", format)
+}
+
+// WriteOutcomes streams every outcome of a table in the given format. flush,
+// if non-nil, is called every exportChunk rows; returning an error from it
+// (e.g. a cancelled request) stops the export.
+func WriteOutcomes(w io.Writer, table *stakergs.LookupTable, format string, flush func() error) error {
+	switch strings.ToLower(format) {
+	case ExportFormatCSV:
+		return writeOutcomesCSV(w, table, flush)
+	case ExportFormatJSONL:
+		return writeOutcomesJSONL(w, table, flush)
+	case ExportFormatParquet:
+		return writeOutcomesParquet(w, table, flush)
+	}
+	_, _, err := ExportContentType(format)
+	return err
+}
+
+// outcomeRows calls fn for each outcome's export row.
+func outcomeRows(table *stakergs.LookupTable, fn func(i int, row *OutcomeExportRow) error) error {
+	totalWeight := float64(table.TotalWeight())
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+
+	var row OutcomeExportRow
+	for i, o := range table.Outcomes {
+		row = OutcomeExportRow{
+			SimID:  int64(o.SimID),
+			Weight: o.Weight,
+			Payout: float64(o.Payout) / 100.0,
+		}
+		if totalWeight > 0 {
+			row.Probability = float64(o.Weight) / totalWeight
+			row.RTPContribution = row.Probability * row.Payout / cost
+		}
+		if row.Probability > 0 {
+			row.Odds = 1 / row.Probability
+		}
+		for _, band := range PayoutBands {
+			if band.Contains(o.Payout) {
+				row.Bucket = band.Name
+				break
+			}
+		}
+		if err := fn(i, &row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatExportFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writeOutcomesCSV(w io.Writer, table *stakergs.LookupTable, flush func() error) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(outcomeExportColumns); err != nil {
+		return err
+	}
+	record := make([]string, len(outcomeExportColumns))
+	err := outcomeRows(table, func(i int, row *OutcomeExportRow) error {
+		record[0] = strconv.FormatInt(row.SimID, 10)
+		record[1] = strconv.FormatUint(row.Weight, 10)
+		record[2] = formatExportFloat(row.Payout)
+		record[3] = formatExportFloat(row.Probability)
+		record[4] = formatExportFloat(row.Odds)
+		record[5] = formatExportFloat(row.RTPContribution)
+		record[6] = row.Bucket
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		if (i+1)%exportChunk == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			if flush != nil {
+				return flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeOutcomesJSONL(w io.Writer, table *stakergs.LookupTable, flush func() error) error {
+	writer := bufio.NewWriter(w)
+	buf := make([]byte, 0, 192)
+	err := outcomeRows(table, func(i int, row *OutcomeExportRow) error {
+		buf = append(buf[:0], `{"sim_id":`...)
+		buf = strconv.AppendInt(buf, row.SimID, 10)
+		buf = append(buf, `,"weight":`...)
+		buf = strconv.AppendUint(buf, row.Weight, 10)
+		buf = append(buf, `,"payout":`...)
+		buf = strconv.AppendFloat(buf, row.Payout, 'g', -1, 64)
+		buf = append(buf, `,"probability":`...)
+		buf = strconv.AppendFloat(buf, row.Probability, 'g', -1, 64)
+		buf = append(buf, `,"odds":`...)
+		buf = strconv.AppendFloat(buf, row.Odds, 'g', -1, 64)
+		buf = append(buf, `,"rtp_contribution":`...)
+		buf = strconv.AppendFloat(buf, row.RTPContribution, 'g', -1, 64)
+		buf = append(buf, `,"bucket":`...)
+		buf = strconv.AppendQuote(buf, row.Bucket)
+		buf = append(buf, "}\n"...)
+		if _, err := writer.Write(buf); err != nil {
+			return err
+		}
+		if (i+1)%exportChunk == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if flush != nil {
+				return flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// writeOutcomesParquet writes one row group per exportChunk rows, so memory
+// stays bounded for large tables and readers can skip groups by statistics.
+func writeOutcomesParquet(w io.Writer, table *stakergs.LookupTable, flush func() error) error {
+	writer := parquet.NewGenericWriter[OutcomeExportRow](w,
+		parquet.Compression(&parquet.Snappy),
+		parquet.KeyValueMetadata("mode", table.Mode),
+		parquet.KeyValueMetadata("cost", formatExportFloat(table.Cost)),
+	)
+
+	batch := make([]OutcomeExportRow, 0, exportChunk)
+	writeBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := writer.Write(batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return writer.Flush()
+	}
+
+	err := outcomeRows(table, func(i int, row *OutcomeExportRow) error {
+		batch = append(batch, *row)
+		if len(batch) < exportChunk {
+			return nil
+		}
+		if err := writeBatch(); err != nil {
+			return err
+		}
+		if flush != nil {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeBatch(); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,44 @@
+package lut
+
+import (
+	"fmt"
+	"sort"
+
+	"stakergs"
+)
+
+// OutcomeSortFields are the fields outcomes can be sorted by.
+var OutcomeSortFields = []string{"sim_id", "payout", "weight", "probability"}
+
+// SortOutcomes returns a sorted copy of a table's outcomes. Ties keep simID
+// order, so paging through the result is stable.
+func SortOutcomes(table *stakergs.LookupTable, by string, desc bool) ([]stakergs.Outcome, error) {
+	var less func(a, b *stakergs.Outcome) bool
+	switch by {
+	case "", "sim_id":
+		less = func(a, b *stakergs.Outcome) bool { return a.SimID < b.SimID }
+	case "payout":
+		less = func(a, b *stakergs.Outcome) bool { return a.Payout < b.Payout }
+	case "weight", "probability":
+		less = func(a, b *stakergs.Outcome) bool { return a.Weight < b.Weight }
+	default:
+		return nil, fmt.Errorf("unknown sort field %q: want one of %v", by, OutcomeSortFields)
+	}
+
+	outcomes := make([]stakergs.Outcome, len(table.Outcomes))
+	copy(outcomes, table.Outcomes)
+	sort.Slice(outcomes, func(i, j int) bool {
+		a, b := &outcomes[i], &outcomes[j]
+		if desc {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return outcomes[i].SimID < outcomes[j].SimID
+	})
+	return outcomes, nil
+}
@@ -0,0 +1,157 @@
+package lut
+
+import (
+	"math"
+	"time"
+
+	"stakergs"
+)
+
+// ParSheetSpins are the session lengths tabulated in a PAR sheet's
+// confidence intervals.
+var ParSheetSpins = []int64{1_000, 10_000, 100_000, 1_000_000, 10_000_000, 100_000_000}
+
+// ParSheetConfidences are the confidence levels tabulated per session length.
+var ParSheetConfidences = []float64{0.90, 0.95, 0.99}
+
+// VolatilityIndexConfidence is the confidence used for the volatility index
+// (z × per-spin standard deviation), the convention most labs report.
+const VolatilityIndexConfidence = 0.90
+
+// ParSheet is the theoretical probability accounting report for one mode,
+// in the structure certification labs ask for.
+type ParSheet struct {
+	GameID      string    `json:"game_id"`
+	Mode        string    `json:"mode"`
+	Cost        float64   `json:"cost"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	TotalOutcomes int    `json:"total_outcomes"`
+	TotalWeight   uint64 `json:"total_weight"`
+
+	RTP            float64 `json:"rtp"`
+	HitRate        float64 `json:"hit_rate"`
+	HitFrequency   string  `json:"hit_frequency"` // hit rate as "1 in X"
+	ZeroPayoutRate float64 `json:"zero_payout_rate"`
+	BreakevenRate  float64 `json:"breakeven_rate"`
+	MeanPayout     float64 `json:"mean_payout"`
+	MedianPayout   float64 `json:"median_payout"`
+	StdDev         float64 `json:"std_dev"` // per-spin return, relative to cost
+	Variance       float64 `json:"variance"`
+	// VolatilityIndex is z(90%) × StdDev
+	VolatilityIndex float64 `json:"volatility_index"`
+
+	MaxWin            float64 `json:"max_win"`
+	MaxWinProbability float64 `json:"max_win_probability"`
+	MaxWinOdds        string  `json:"max_win_odds"`
+
+	Bands      []ParSheetBand     `json:"bands"`
+	Intervals  []ParSheetInterval `json:"intervals"`
+	SampleSize SampleSizeEstimate `json:"sample_size"`
+
+	PayoutBuckets []PayoutBucket     `json:"payout_buckets"`
+	Distribution  []DistributionItem `json:"distribution"`
+	TopPayouts    []PayoutInfo       `json:"top_payouts"`
+}
+
+// ParSheetBand holds the hit rate and RTP share of one payout band.
+type ParSheetBand struct {
+	PayoutBand
+	Outcomes        int     `json:"outcomes"`
+	Probability     float64 `json:"probability"`
+	Odds            string  `json:"odds"`
+	RTPContribution float64 `json:"rtp_contribution"`
+}
+
+// ParSheetInterval is the range measured RTP falls in over a session length.
+type ParSheetInterval struct {
+	Spins      int64   `json:"spins"`
+	Confidence float64 `json:"confidence"`
+	Low        float64 `json:"low"`
+	High       float64 `json:"high"`
+}
+
+// BuildParSheet assembles the PAR sheet of a mode from its lookup table.
+func BuildParSheet(table *stakergs.LookupTable) *ParSheet {
+	stats := NewAnalyzer().Analyze(table)
+	mean, stdDev := SpinRTPStats(table)
+
+	sheet := &ParSheet{
+		GameID:          table.GameID,
+		Mode:            table.Mode,
+		Cost:            stats.Cost,
+		GeneratedAt:     time.Now().UTC(),
+		TotalOutcomes:   stats.TotalOutcomes,
+		TotalWeight:     stats.TotalWeight,
+		RTP:             round6(mean),
+		HitRate:         stats.HitRate,
+		HitFrequency:    "-",
+		ZeroPayoutRate:  stats.ZeroPayoutRate,
+		BreakevenRate:   stats.BreakevenRate,
+		MeanPayout:      stats.MeanPayout,
+		MedianPayout:    stats.MedianPayout,
+		StdDev:          round6(stdDev),
+		Variance:        round6(stdDev * stdDev),
+		VolatilityIndex: round4(ZScore(VolatilityIndexConfidence) * stdDev),
+		MaxWin:          stats.MaxPayout,
+		MaxWinOdds:      "-",
+		SampleSize:      EstimateSampleSize(table, 0, 0),
+		PayoutBuckets:   stats.PayoutBuckets,
+		Distribution:    stats.Distribution,
+		TopPayouts:      stats.TopPayouts,
+	}
+	if stats.HitRate > 0 {
+		sheet.HitFrequency = formatOdds(1 / stats.HitRate)
+	}
+
+	totalWeight := stats.TotalWeight
+	if totalWeight == 0 {
+		return sheet
+	}
+
+	maxPayout := table.MaxPayout()
+	var maxWeight uint64
+	for _, o := range table.Outcomes {
+		if o.Payout == maxPayout {
+			maxWeight += o.Weight
+		}
+	}
+	sheet.MaxWinProbability = float64(maxWeight) / float64(totalWeight)
+	if maxWeight > 0 {
+		sheet.MaxWinOdds = formatOdds(1 / sheet.MaxWinProbability)
+	}
+
+	for _, band := range PayoutBands {
+		row := ParSheetBand{PayoutBand: band, Odds: "-"}
+		var weight uint64
+		var contribution float64
+		for _, o := range table.Outcomes {
+			if !band.Contains(o.Payout) {
+				continue
+			}
+			row.Outcomes++
+			weight += o.Weight
+			contribution += float64(o.Weight) * float64(o.Payout) / 100.0
+		}
+		row.Probability = round6(float64(weight) / float64(totalWeight))
+		row.RTPContribution = round6(contribution / float64(totalWeight) / stats.Cost)
+		if weight > 0 {
+			row.Odds = formatOdds(float64(totalWeight) / float64(weight))
+		}
+		sheet.Bands = append(sheet.Bands, row)
+	}
+
+	for _, spins := range ParSheetSpins {
+		for _, confidence := range ParSheetConfidences {
+			margin := ZScore(confidence) * stdDev / math.Sqrt(float64(spins))
+			sheet.Intervals = append(sheet.Intervals, ParSheetInterval{
+				Spins:      spins,
+				Confidence: confidence,
+				Low:        round6(mean - margin),
+				High:       round6(mean + margin),
+			})
+		}
+	}
+
+	return sheet
+}
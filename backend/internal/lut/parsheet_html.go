@@ -0,0 +1,95 @@
+package lut
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+var parSheetFuncs = template.FuncMap{
+	"pct": func(v float64) string { return fmt.Sprintf("%.4f%%", v*100) },
+	"x":   func(v float64) string { return fmt.Sprintf("%.2fx", v) },
+	"num": func(v float64) string { return fmt.Sprintf("%.4f", v) },
+	"bandMax": func(b ParSheetBand) string {
+		if b.Max <= 0 {
+			return "∞"
+		}
+		return fmt.Sprintf("%.2fx", b.Max)
+	},
+}
+
+var parSheetTemplate = template.Must(template.New("parsheet").Funcs(parSheetFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>PAR sheet – {{.GameID}} {{.Mode}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 12px; margin: 24px; color: #111; }
+h1 { font-size: 20px; margin-bottom: 4px; }
+h2 { font-size: 14px; margin-top: 24px; border-bottom: 1px solid #999; }
+table { border-collapse: collapse; margin-top: 8px; }
+th, td { border: 1px solid #ccc; padding: 3px 8px; text-align: right; }
+th { background: #f0f0f0; }
+td.label, th.label { text-align: left; }
+.meta { color: #555; }
+@media print { body { margin: 0; } h2 { page-break-after: avoid; } table { page-break-inside: auto; } }
+</style>
+</head>
+<body>
+<h1>PAR sheet: {{.GameID}} / {{.Mode}}</h1>
+<div class="meta">Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 UTC"}} · {{.TotalOutcomes}} outcomes · total weight {{.TotalWeight}}</div>
+
+<h2>Summary</h2>
+<table>
+<tr><td class="label">Cost</td><td>{{x .Cost}}</td></tr>
+<tr><td class="label">RTP</td><td>{{pct .RTP}}</td></tr>
+<tr><td class="label">Hit rate</td><td>{{pct .HitRate}} ({{.HitFrequency}})</td></tr>
+<tr><td class="label">Zero payout rate</td><td>{{pct .ZeroPayoutRate}}</td></tr>
+<tr><td class="label">Breakeven rate</td><td>{{pct .BreakevenRate}}</td></tr>
+<tr><td class="label">Mean payout</td><td>{{x .MeanPayout}}</td></tr>
+<tr><td class="label">Median payout</td><td>{{x .MedianPayout}}</td></tr>
+<tr><td class="label">Standard deviation</td><td>{{num .StdDev}}</td></tr>
+<tr><td class="label">Variance</td><td>{{num .Variance}}</td></tr>
+<tr><td class="label">Volatility index (90%)</td><td>{{num .VolatilityIndex}}</td></tr>
+<tr><td class="label">Max win</td><td>{{x .MaxWin}}</td></tr>
+<tr><td class="label">Max win odds</td><td>{{.MaxWinOdds}}</td></tr>
+<tr><td class="label">Spins to verify RTP ±{{pct .SampleSize.Tolerance}} at {{pct .SampleSize.Confidence}}</td><td>{{.SampleSize.RequiredSpins}}</td></tr>
+</table>
+
+<h2>Hit rates by payout band</h2>
+<table>
+<tr><th class="label">Band</th><th>From</th><th>To</th><th>Outcomes</th><th>Probability</th><th>Odds</th><th>RTP contribution</th></tr>
+{{range .Bands}}<tr><td class="label">{{.Name}}</td><td>{{x .Min}}</td><td>{{bandMax .}}</td><td>{{.Outcomes}}</td><td>{{pct .Probability}}</td><td>{{.Odds}}</td><td>{{pct .RTPContribution}}</td></tr>
+{{end}}</table>
+
+<h2>RTP confidence intervals</h2>
+<table>
+<tr><th>Spins</th><th>Confidence</th><th>Low</th><th>High</th></tr>
+{{range .Intervals}}<tr><td>{{.Spins}}</td><td>{{pct .Confidence}}</td><td>{{pct .Low}}</td><td>{{pct .High}}</td></tr>
+{{end}}</table>
+
+<h2>Payout distribution</h2>
+<table>
+<tr><th>From</th><th>To</th><th>Outcomes</th><th>Weight</th><th>Probability</th></tr>
+{{range .PayoutBuckets}}<tr><td>{{x .RangeStart}}</td><td>{{x .RangeEnd}}</td><td>{{.Count}}</td><td>{{.Weight}}</td><td>{{pct .Probability}}</td></tr>
+{{end}}</table>
+
+<h2>Top payouts</h2>
+<table>
+<tr><th>Payout</th><th>Sim ID</th><th>Outcomes</th><th>Weight</th><th>Odds</th></tr>
+{{range .TopPayouts}}<tr><td>{{x .Payout}}</td><td>{{.SimID}}</td><td>{{.Count}}</td><td>{{.Weight}}</td><td>{{.Odds}}</td></tr>
+{{end}}</table>
+
+<h2>Payout table</h2>
+<table>
+<tr><th>Payout</th><th>Outcomes</th><th>Weight</th><th>Odds</th></tr>
+{{range .Distribution}}<tr><td>{{x .Payout}}</td><td>{{.Count}}</td><td>{{.Weight}}</td><td>{{.Odds}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// WriteHTML renders the PAR sheet as a printable HTML document.
+func (p *ParSheet) WriteHTML(w io.Writer) error {
+	return parSheetTemplate.Execute(w, p)
+}
@@ -0,0 +1,49 @@
+package lut
+
+import (
+	"math"
+
+	"stakergs"
+)
+
+// DefaultRTPDriftThreshold is the absolute RTP change (0.005 = 0.5 percentage
+// points) on a reload above which the maths is considered to have shifted.
+const DefaultRTPDriftThreshold = 0.005
+
+// ModeDrift compares a mode's table before and after a reload.
+type ModeDrift struct {
+	Mode      string      `json:"mode"`
+	Before    ModeSummary `json:"before"`
+	After     ModeSummary `json:"after"`
+	RTPDelta  float64     `json:"rtp_delta"` // after - before
+	HitDelta  float64     `json:"hit_rate_delta"`
+	Threshold float64     `json:"threshold"`
+	// Exceeded is set when |RTPDelta| is above the threshold
+	Exceeded bool `json:"exceeded"`
+}
+
+func summarize(table *stakergs.LookupTable) ModeSummary {
+	return ModeSummary{
+		Mode:      table.Mode,
+		Cost:      table.Cost,
+		Outcomes:  len(table.Outcomes),
+		RTP:       table.RTP(),
+		HitRate:   table.HitRate(),
+		MaxPayout: float64(table.MaxPayout()) / 100.0,
+	}
+}
+
+// CompareReload diffs the stats of a mode's previous and reloaded tables.
+// A threshold of 0 or less never flags the change.
+func CompareReload(before, after *stakergs.LookupTable, threshold float64) ModeDrift {
+	drift := ModeDrift{
+		Mode:      after.Mode,
+		Before:    summarize(before),
+		After:     summarize(after),
+		Threshold: threshold,
+	}
+	drift.RTPDelta = drift.After.RTP - drift.Before.RTP
+	drift.HitDelta = drift.After.HitRate - drift.Before.HitRate
+	drift.Exceeded = threshold > 0 && math.Abs(drift.RTPDelta) > threshold
+	return drift
+}
@@ -0,0 +1,139 @@
+package lut
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"lutexplorer/internal/common"
+	"stakergs"
+)
+
+const (
+	DefaultSampleCount = 10
+	MaxSampleCount     = 1000
+)
+
+// PayoutBand is a payout range in bet multiples: Min inclusive, Max exclusive
+// (0 = unbounded).
+type PayoutBand struct {
+	Name string  `json:"name,omitempty"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max,omitempty"`
+}
+
+// PayoutBands are the named bands accepted by SampleOutcomes.
+var PayoutBands = []PayoutBand{
+	{Name: "zero", Min: 0, Max: 0.01},
+	{Name: "sub_bet", Min: 0.01, Max: 1},
+	{Name: "win", Min: 1, Max: common.BigWinMultiplier},
+	{Name: "big_win", Min: common.BigWinMultiplier, Max: common.MegaWinMultiplier},
+	{Name: "mega_win", Min: common.MegaWinMultiplier},
+}
+
+// LookupPayoutBand returns the named band (case-insensitive).
+func LookupPayoutBand(name string) (PayoutBand, error) {
+	for _, b := range PayoutBands {
+		if strings.EqualFold(b.Name, name) {
+			return b, nil
+		}
+	}
+	names := make([]string, len(PayoutBands))
+	for i, b := range PayoutBands {
+		names[i] = b.Name
+	}
+	return PayoutBand{}, fmt.Errorf("unknown bucket %q: want one of %s", name, strings.Join(names, ", "))
+}
+
+// Contains reports whether a payout (x100) lies within the band.
+func (b PayoutBand) Contains(payout uint) bool {
+	x := float64(payout) / 100.0
+	return x >= b.Min && (b.Max <= 0 || x < b.Max)
+}
+
+// OutcomeSample holds random outcomes drawn from a payout band.
+type OutcomeSample struct {
+	Mode       string       `json:"mode"`
+	Band       PayoutBand   `json:"band"`
+	Weighted   bool         `json:"weighted"`
+	Seed       int64        `json:"seed"`
+	Candidates int          `json:"candidates"` // outcomes in the band
+	BandWeight uint64       `json:"band_weight"`
+	BandProb   float64      `json:"band_probability"` // chance a spin lands in the band
+	Outcomes   []EventMatch `json:"outcomes"`
+}
+
+// SampleOutcomes draws up to count distinct outcomes from a payout band.
+// Weighted sampling favours outcomes by their LUT weight (what players
+// actually see); uniform sampling treats every book in the band equally,
+// which surfaces rare variants. The same seed gives the same draw.
+func SampleOutcomes(table *stakergs.LookupTable, band PayoutBand, count int, weighted bool, seed int64) *OutcomeSample {
+	totalWeight := table.TotalWeight()
+	result := &OutcomeSample{
+		Mode:     table.Mode,
+		Band:     band,
+		Weighted: weighted,
+		Seed:     seed,
+		Outcomes: []EventMatch{},
+	}
+
+	var candidates []stakergs.Outcome
+	for _, o := range table.Outcomes {
+		if !band.Contains(o.Payout) {
+			continue
+		}
+		if weighted && o.Weight == 0 {
+			continue
+		}
+		candidates = append(candidates, o)
+		result.BandWeight += o.Weight
+	}
+	result.Candidates = len(candidates)
+	if totalWeight > 0 {
+		result.BandProb = float64(result.BandWeight) / float64(totalWeight)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	if weighted {
+		// Weighted sampling without replacement (Efraimidis-Spirakis):
+		// keep the count outcomes with the largest log(u)/weight keys
+		keys := make([]float64, len(candidates))
+		for i, o := range candidates {
+			keys[i] = math.Log(1-rng.Float64()) / float64(o.Weight)
+		}
+		order := make([]int, len(candidates))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool { return keys[order[a]] > keys[order[b]] })
+		reordered := make([]stakergs.Outcome, len(candidates))
+		for i, idx := range order {
+			reordered[i] = candidates[idx]
+		}
+		candidates = reordered
+	} else {
+		rng.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	}
+
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	for _, o := range candidates[:count] {
+		prob := 0.0
+		if totalWeight > 0 {
+			prob = float64(o.Weight) / float64(totalWeight)
+		}
+		result.Outcomes = append(result.Outcomes, EventMatch{
+			SimID:       o.SimID,
+			Payout:      float64(o.Payout) / 100.0,
+			Weight:      o.Weight,
+			Probability: prob,
+		})
+	}
+
+	return result
+}
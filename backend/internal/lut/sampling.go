@@ -0,0 +1,279 @@
+package lut
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"stakergs"
+)
+
+const (
+	// minBinExpected is the smallest expected count of a chi-square bin;
+	// below it the chi-square approximation does not hold.
+	minBinExpected = 5.0
+
+	// SamplingBiasSignificance is the p-value below which draws are flagged
+	// as not following the table weights.
+	SamplingBiasSignificance = 0.01
+)
+
+// ChiSquareResult is a goodness-of-fit test of observed against expected
+// counts.
+type ChiSquareResult struct {
+	Statistic float64 `json:"statistic"`
+	DF        int     `json:"df"`
+	PValue    float64 `json:"p_value"`
+	// Biased is set when PValue is below SamplingBiasSignificance
+	Biased bool `json:"biased"`
+}
+
+// SamplingBin groups outcomes by payout for the chi-square test, so every
+// bin has enough expected draws.
+type SamplingBin struct {
+	Payouts  string  `json:"payouts"` // payout multiplier range, e.g. "2x-5x"
+	Outcomes int     `json:"outcomes"`
+	Expected float64 `json:"expected"`
+	Observed int64   `json:"observed"`
+}
+
+// SimIDDraws is how often a simID was drawn.
+type SimIDDraws struct {
+	SimID    int     `json:"sim_id"`
+	Payout   float64 `json:"payout"`
+	Draws    int64   `json:"draws"`
+	Expected float64 `json:"expected"`
+}
+
+// SamplingReport compares the outcomes a sampler drew with the table
+// weights: how much of the book was covered and whether the draws fit.
+type SamplingReport struct {
+	Mode        string `json:"mode"`
+	Draws       int64  `json:"draws"`
+	Outcomes    int    `json:"outcomes"`
+	OutcomesHit int    `json:"outcomes_hit"`
+	NeverHit    int    `json:"never_hit"`
+	// Coverage is the share of outcomes drawn at least once
+	Coverage float64 `json:"coverage"`
+	// ExpectedCoverage is the coverage an unbiased sampler reaches on
+	// average after the same number of draws
+	ExpectedCoverage float64 `json:"expected_coverage"`
+	// WeightCoverage is the probability mass of the outcomes drawn
+	WeightCoverage float64 `json:"weight_coverage"`
+	// UnknownDraws counts draws of simIDs no longer in the table
+	UnknownDraws int64 `json:"unknown_draws"`
+	// SkippedDraws counts draws left out of the report (set by the caller,
+	// e.g. forced or biased spins)
+	SkippedDraws int64 `json:"skipped_draws"`
+	// ChiSquare is nil until there are draws for at least two bins
+	ChiSquare *ChiSquareResult `json:"chi_square,omitempty"`
+	Bins      []SamplingBin    `json:"bins"`
+	Top       []SimIDDraws     `json:"top"` // most drawn simIDs
+}
+
+// AnalyzeSampling builds a sampling report from per-simID draw counts.
+// top limits the most drawn simIDs listed.
+func AnalyzeSampling(table *stakergs.LookupTable, counts map[int]int64, top int) *SamplingReport {
+	report := &SamplingReport{
+		Mode:     table.Mode,
+		Outcomes: len(table.Outcomes),
+		Bins:     []SamplingBin{},
+		Top:      []SimIDDraws{},
+	}
+	for _, n := range counts {
+		report.Draws += n
+	}
+	totalWeight := float64(table.TotalWeight())
+	if totalWeight == 0 || report.Outcomes == 0 {
+		return report
+	}
+
+	draws := float64(report.Draws)
+	known := make(map[int]bool, len(table.Outcomes))
+	var hitWeight float64
+	for _, o := range table.Outcomes {
+		known[o.SimID] = true
+		p := float64(o.Weight) / totalWeight
+		// P(drawn at least once) = 1 - (1-p)^n
+		report.ExpectedCoverage += -math.Expm1(draws * math.Log1p(-p))
+		if counts[o.SimID] > 0 {
+			report.OutcomesHit++
+			hitWeight += float64(o.Weight)
+		}
+	}
+	for simID, n := range counts {
+		if !known[simID] {
+			report.UnknownDraws += n
+		}
+	}
+	report.NeverHit = report.Outcomes - report.OutcomesHit
+	report.Coverage = float64(report.OutcomesHit) / float64(report.Outcomes)
+	report.ExpectedCoverage /= float64(report.Outcomes)
+	report.WeightCoverage = hitWeight / totalWeight
+
+	report.Bins = samplingBins(table, counts, draws-float64(report.UnknownDraws), totalWeight)
+	if len(report.Bins) >= 2 {
+		observed := make([]float64, len(report.Bins))
+		expected := make([]float64, len(report.Bins))
+		for i, b := range report.Bins {
+			observed[i] = float64(b.Observed)
+			expected[i] = b.Expected
+		}
+		result := ChiSquareTest(observed, expected)
+		report.ChiSquare = &result
+	}
+
+	report.Top = topDraws(table, counts, draws, totalWeight, top)
+	return report
+}
+
+// samplingBins groups outcomes in payout order into bins of at least
+// minBinExpected expected draws.
+func samplingBins(table *stakergs.LookupTable, counts map[int]int64, draws, totalWeight float64) []SamplingBin {
+	outcomes := make([]stakergs.Outcome, len(table.Outcomes))
+	copy(outcomes, table.Outcomes)
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i].Payout < outcomes[j].Payout })
+
+	type bin struct {
+		SamplingBin
+		lo, hi uint
+	}
+	var bins []bin
+	var cur *bin
+	for i, o := range outcomes {
+		if cur == nil {
+			bins = append(bins, bin{lo: o.Payout})
+			cur = &bins[len(bins)-1]
+		}
+		cur.hi = o.Payout
+		cur.Outcomes++
+		cur.Expected += draws * float64(o.Weight) / totalWeight
+		cur.Observed += counts[o.SimID]
+		// Close the bin once it is large enough, but keep equal payouts together
+		if cur.Expected >= minBinExpected && (i+1 == len(outcomes) || outcomes[i+1].Payout != o.Payout) {
+			cur = nil
+		}
+	}
+	// A small trailing bin is merged into the one before it
+	if n := len(bins); n >= 2 && bins[n-1].Expected < minBinExpected {
+		last := bins[n-1]
+		prev := &bins[n-2]
+		prev.hi = last.hi
+		prev.Outcomes += last.Outcomes
+		prev.Expected += last.Expected
+		prev.Observed += last.Observed
+		bins = bins[:n-1]
+	}
+	if len(bins) == 1 && bins[0].Expected < minBinExpected {
+		bins = nil
+	}
+
+	result := make([]SamplingBin, len(bins))
+	for i, b := range bins {
+		b.Payouts = fmt.Sprintf("%gx", float64(b.lo)/100)
+		if b.hi != b.lo {
+			b.Payouts = fmt.Sprintf("%gx-%gx", float64(b.lo)/100, float64(b.hi)/100)
+		}
+		result[i] = b.SamplingBin
+	}
+	return result
+}
+
+func topDraws(table *stakergs.LookupTable, counts map[int]int64, draws, totalWeight float64, top int) []SimIDDraws {
+	result := []SimIDDraws{}
+	if top <= 0 {
+		return result
+	}
+	for _, o := range table.Outcomes {
+		if n := counts[o.SimID]; n > 0 {
+			result = append(result, SimIDDraws{
+				SimID:    o.SimID,
+				Payout:   float64(o.Payout) / 100,
+				Draws:    n,
+				Expected: draws * float64(o.Weight) / totalWeight,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Draws != result[j].Draws {
+			return result[i].Draws > result[j].Draws
+		}
+		return result[i].SimID < result[j].SimID
+	})
+	if len(result) > top {
+		result = result[:top]
+	}
+	return result
+}
+
+// ChiSquareTest runs Pearson's chi-square goodness-of-fit test. Bins with
+// no expected count are skipped.
+func ChiSquareTest(observed, expected []float64) ChiSquareResult {
+	var result ChiSquareResult
+	bins := 0
+	for i := range observed {
+		if expected[i] <= 0 {
+			continue
+		}
+		d := observed[i] - expected[i]
+		result.Statistic += d * d / expected[i]
+		bins++
+	}
+	result.DF = bins - 1
+	if result.DF < 1 {
+		result.PValue = 1
+		return result
+	}
+	result.PValue = gammaQ(float64(result.DF)/2, result.Statistic/2)
+	result.Biased = result.PValue < SamplingBiasSignificance
+	return result
+}
+
+// gammaQ is the regularized upper incomplete gamma function Q(a, x), the
+// chi-square survival function for Q(df/2, stat/2).
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	lgA, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgA)
+
+	if x < a+1 {
+		// Series for P(a, x)
+		sum, term := 1/a, 1/a
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	// Continued fraction for Q(a, x) (modified Lentz)
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 1000; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Min(1, prefix*h)
+}
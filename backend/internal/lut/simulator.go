@@ -1,8 +1,10 @@
 package lut
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
+	"runtime"
 	"sort"
 	"sync"
 	"time"
@@ -23,6 +25,24 @@ func NewSimulator() *Simulator {
 	}
 }
 
+// NewSeed returns a random seed for runs that didn't ask for one.
+func (s *Simulator) NewSeed() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Int63()
+}
+
+// TrialSeed derives the RNG seed for one trial from the run seed. Each trial
+// gets its own stream, so results don't depend on the order trials run in.
+func TrialSeed(seed int64, trial int) int64 {
+	// splitmix64 finalizer over seed + trial * golden ratio
+	z := uint64(seed) + uint64(trial+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return int64(z >> 1)
+}
+
 // SimulationConfig holds parameters for a simulation run.
 type SimulationConfig struct {
 	Spins       int       `json:"spins"`        // Number of spins per trial
@@ -31,12 +51,25 @@ type SimulationConfig struct {
 	TargetRTP   float64   `json:"target_rtp"`   // Target RTP threshold (e.g., 0.97)
 	TestSpins   []int     `json:"test_spins"`   // Spin counts to test RTP at
 	TestWeights []float64 `json:"test_weights"` // Weights for each test spin (for scoring)
+	Seed        int64     `json:"seed"`         // RNG seed; the same seed and table give identical results
+	Workers     int       `json:"workers"`      // Goroutines running trials (0 = one per CPU)
+	// RTPTolerance is the deviation the sample-size estimate must detect (0 = DefaultRTPTolerance)
+	RTPTolerance float64 `json:"rtp_tolerance"`
+	// Confidence is the level for confidence intervals and the sample size (0 = DefaultConfidence)
+	Confidence float64 `json:"confidence"`
+	// HistogramBins is the bin count for per-trial RTP distributions (0 = DefaultHistogramBins)
+	HistogramBins int `json:"histogram_bins"`
+	// BetSchedule varies the bet per spin; nil bets Bet on every spin
+	BetSchedule *BetSchedule `json:"bet_schedule,omitempty"`
 }
 
 // SimulationResult holds the results of a simulation run.
 type SimulationResult struct {
+	ID             string          `json:"id,omitempty"` // set when the result was stored
 	Mode           string          `json:"mode"`
 	Config         SimulationConfig `json:"config"`
+	CustomWeights  bool            `json:"custom_weights,omitempty"` // ran on candidate weights, not the saved table
+	Seed           int64           `json:"seed"`
 	TotalSpins     int             `json:"total_spins"`
 	TotalWagered   float64         `json:"total_wagered"`
 	TotalWon       float64         `json:"total_won"`
@@ -50,6 +83,16 @@ type SimulationResult struct {
 	TrialSummaries []TrialSummary  `json:"trial_summaries,omitempty"`
 	RTPatSpins     []RTPAtSpin     `json:"rtp_at_spins,omitempty"`
 	FinalScore     float64         `json:"final_score,omitempty"`
+	RTPStdError    float64         `json:"rtp_std_error,omitempty"` // of ActualRTP over TotalSpins
+	RTPCILow       float64         `json:"rtp_ci_low,omitempty"`
+	RTPCIHigh      float64         `json:"rtp_ci_high,omitempty"`
+	SampleSize     *SampleSizeEstimate `json:"sample_size,omitempty"`
+	// Spread of realized RTP across trials at each test spin count and at the full session
+	RTPDistributions []RTPDistribution `json:"rtp_distributions,omitempty"`
+	BetLevels        []BetLevelStats   `json:"bet_levels,omitempty"` // only with a bet schedule
+	// Per-trial values in trial order, for comparisons; not serialized
+	TrialRTPs     []float64 `json:"-"`
+	TrialHitRates []float64 `json:"-"`
 	DurationMs     int64           `json:"duration_ms"`
 }
 
@@ -72,11 +115,37 @@ type TrialSummary struct {
 	PassedRTP  bool    `json:"passed_rtp"`
 }
 
-// RTPAtSpin holds RTP success rate at a specific spin count.
+// RTPAtSpin holds RTP success rate at a specific spin count, with the
+// standard error and confidence interval of RTP measured over that many spins.
 type RTPAtSpin struct {
 	SpinCount   int     `json:"spin_count"`
 	SuccessRate float64 `json:"success_rate"`
 	Weight      float64 `json:"weight,omitempty"`
+	MeanRTP     float64 `json:"mean_rtp"`  // average across trials
+	StdError    float64 `json:"std_error"` // per-spin std dev / sqrt(spin count)
+	CILow       float64 `json:"ci_low"`    // MeanRTP -/+ z * StdError
+	CIHigh      float64 `json:"ci_high"`
+}
+
+// WithWeights returns a copy of the table using candidate weights, for
+// simulating optimizer output without saving it. The original is unchanged.
+func WithWeights(lut *stakergs.LookupTable, weights []uint64) (*stakergs.LookupTable, error) {
+	if len(weights) != len(lut.Outcomes) {
+		return nil, fmt.Errorf("weight count mismatch: got %d, expected %d", len(weights), len(lut.Outcomes))
+	}
+
+	copied := *lut
+	copied.Outcomes = make([]stakergs.Outcome, len(lut.Outcomes))
+	var total uint64
+	for i, o := range lut.Outcomes {
+		o.Weight = weights[i]
+		copied.Outcomes[i] = o
+		total += o.Weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("weights must not all be zero")
+	}
+	return &copied, nil
 }
 
 // WeightedSampler provides efficient weighted random sampling.
@@ -193,94 +262,197 @@ func (bws *BiasedWeightedSampler) SampleWithNewRNG() stakergs.Outcome {
 	return bws.outcomes[idx]
 }
 
-// RunSimulation executes a full simulation with multiple trials.
+// trialOutcome holds the raw totals of one trial, merged in trial order.
+type trialOutcome struct {
+	wagered    float64 // in base bets (spins without a bet schedule)
+	won        float64
+	hits       int
+	bigWins    int
+	megaWins   int
+	maxWin     float64
+	rtpAtTest  []float64 // RTP measured at each test spin count
+
+	// Per bet level, with a bet schedule
+	levelSpins   []int
+	levelWagered []float64
+	levelWon     []float64
+}
+
+// runTrial plays one trial of config.Spins spins with its own RNG.
+func runTrial(sampler *WeightedSampler, config SimulationConfig, trial int) trialOutcome {
+	rng := rand.New(rand.NewSource(TrialSeed(config.Seed, trial)))
+	levels := config.BetSchedule.levelCount()
+	out := trialOutcome{
+		rtpAtTest:    make([]float64, len(config.TestSpins)),
+		levelSpins:   make([]int, levels),
+		levelWagered: make([]float64, levels),
+		levelWon:     make([]float64, levels),
+	}
+	bets := betState{schedule: config.BetSchedule}
+
+	for spin := 0; spin < config.Spins; spin++ {
+		level, multiple := bets.next(spin)
+		outcome := sampler.Sample(rng)
+		// Payout thresholds below are bet multiples; winnings scale with the bet
+		payout := float64(outcome.Payout) / 100.0
+		win := payout * multiple
+		bets.record(payout > 0)
+
+		out.wagered += multiple
+		out.won += win
+		out.levelSpins[level]++
+		out.levelWagered[level] += multiple
+		out.levelWon[level] += win
+		if payout > 0 {
+			out.hits++
+		}
+		if payout >= 10.0 {
+			out.bigWins++
+		}
+		if payout >= 50.0 {
+			out.megaWins++
+		}
+		if win > out.maxWin {
+			out.maxWin = win
+		}
+
+		// Check RTP at test spin points
+		for i, testSpin := range config.TestSpins {
+			if testSpin == spin+1 {
+				out.rtpAtTest[i] = out.won / (out.wagered * config.Bet)
+			}
+		}
+	}
+	return out
+}
+
+// RunSimulation executes a full simulation with multiple trials. Trials are
+// spread over config.Workers goroutines; each has its own seeded RNG and the
+// totals are merged in trial order, so the result only depends on the seed.
 func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationConfig) *SimulationResult {
 	start := time.Now()
 
 	sampler := NewWeightedSampler(lut)
 
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > config.Trials {
+		workers = config.Trials
+	}
+	config.Workers = workers
+	if config.Confidence <= 0 || config.Confidence >= 1 {
+		config.Confidence = DefaultConfidence
+	}
+	if config.RTPTolerance <= 0 {
+		config.RTPTolerance = DefaultRTPTolerance
+	}
+	if config.HistogramBins <= 0 {
+		config.HistogramBins = DefaultHistogramBins
+	}
+
 	result := &SimulationResult{
 		Mode:   lut.Mode,
 		Config: config,
+		Seed:   config.Seed,
 	}
 
+	// Run all trials
+	outcomes := make([]trialOutcome, config.Trials)
+	trials := make(chan int, config.Trials)
+	for trial := 0; trial < config.Trials; trial++ {
+		trials <- trial
+	}
+	close(trials)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for trial := range trials {
+				outcomes[trial] = runTrial(sampler, config, trial)
+			}
+		}()
+	}
+	wg.Wait()
+
 	// Initialize test spin success counters
 	testSpinSuccess := make([]int, len(config.TestSpins))
+	testSpinRTPSum := make([]float64, len(config.TestSpins))
 
 	var totalWon float64
+	var totalWageredBets float64
 	var totalHits int
 	var totalBigWins int
 	var totalMegaWins int
 	var maxWin float64
 
 	trialSummaries := make([]TrialSummary, config.Trials)
-
-	// Run all trials
-	for trial := 0; trial < config.Trials; trial++ {
-		s.mu.Lock()
-		trialRNG := rand.New(rand.NewSource(s.rng.Int63()))
-		s.mu.Unlock()
-
-		var trialWon float64
-		var trialHits int
-		var trialMaxWin float64
-		spinPayouts := make([]float64, config.Spins)
-
-		// Run spins for this trial
-		for spin := 0; spin < config.Spins; spin++ {
-			outcome := sampler.Sample(trialRNG)
-			payout := float64(outcome.Payout) / 100.0
-
-			spinPayouts[spin] = payout
-			trialWon += payout
-
-			if payout > 0 {
-				trialHits++
-				totalHits++
-			}
-			if payout >= 10.0 {
-				totalBigWins++
-			}
-			if payout >= 50.0 {
-				totalMegaWins++
-			}
-			if payout > trialMaxWin {
-				trialMaxWin = payout
-			}
-			if payout > maxWin {
-				maxWin = payout
-			}
+	result.TrialRTPs = make([]float64, config.Trials)
+	result.TrialHitRates = make([]float64, config.Trials)
+
+	levels := config.BetSchedule.levelCount()
+	levelSpins := make([]int, levels)
+	levelWagered := make([]float64, levels)
+	levelWon := make([]float64, levels)
+
+	for trial, out := range outcomes {
+		totalWon += out.won
+		totalWageredBets += out.wagered
+		for l := 0; l < levels; l++ {
+			levelSpins[l] += out.levelSpins[l]
+			levelWagered[l] += out.levelWagered[l]
+			levelWon[l] += out.levelWon[l]
 		}
-
-		totalWon += trialWon
-		trialRTP := trialWon / (float64(config.Spins) * config.Bet)
-
-		// Check RTP at test spin points
-		for i, testSpin := range config.TestSpins {
-			if testSpin <= config.Spins {
-				var sumToSpin float64
-				for j := 0; j < testSpin; j++ {
-					sumToSpin += spinPayouts[j]
-				}
-				rtpAtSpin := sumToSpin / (float64(testSpin) * config.Bet)
-				if rtpAtSpin >= config.TargetRTP {
-					testSpinSuccess[i]++
-				}
+		totalHits += out.hits
+		totalBigWins += out.bigWins
+		totalMegaWins += out.megaWins
+		if out.maxWin > maxWin {
+			maxWin = out.maxWin
+		}
+		for i, rtp := range out.rtpAtTest {
+			if config.TestSpins[i] > config.Spins {
+				continue
+			}
+			testSpinRTPSum[i] += rtp
+			if rtp >= config.TargetRTP {
+				testSpinSuccess[i]++
 			}
 		}
 
+		trialRTP := out.won / (out.wagered * config.Bet)
+		result.TrialRTPs[trial] = trialRTP
+		result.TrialHitRates[trial] = float64(out.hits) / float64(config.Spins)
 		trialSummaries[trial] = TrialSummary{
 			Trial:     trial + 1,
-			TotalWon:  round2(trialWon),
+			TotalWon:  round2(out.won),
 			RTP:       round4(trialRTP),
-			HitCount:  trialHits,
-			MaxWin:    round2(trialMaxWin),
+			HitCount:  out.hits,
+			MaxWin:    round2(out.maxWin),
 			PassedRTP: trialRTP >= config.TargetRTP,
 		}
 	}
 
 	totalSpins := config.Spins * config.Trials
-	totalWagered := float64(totalSpins) * config.Bet
+	totalWagered := totalWageredBets * config.Bet
+
+	if config.BetSchedule != nil {
+		for l := 0; l < levels; l++ {
+			stats := BetLevelStats{
+				Level:    l,
+				Multiple: config.BetSchedule.Levels[l],
+				Spins:    levelSpins[l],
+				Wagered:  round2(levelWagered[l] * config.Bet),
+				Won:      round2(levelWon[l]),
+			}
+			if levelWagered[l] > 0 {
+				stats.RTP = round4(levelWon[l] / (levelWagered[l] * config.Bet))
+			}
+			result.BetLevels = append(result.BetLevels, stats)
+		}
+	}
 
 	result.TotalSpins = totalSpins
 	result.TotalWagered = round2(totalWagered)
@@ -297,6 +469,16 @@ func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationCo
 		result.TrialSummaries = trialSummaries
 	}
 
+	// Standard errors use the exact per-spin spread of the table
+	_, spinStdDev := SpinRTPStats(lut)
+	z := ZScore(config.Confidence)
+	rtpStdError := spinStdDev / math.Sqrt(float64(totalSpins))
+	result.RTPStdError = round6(rtpStdError)
+	result.RTPCILow = round4(totalWon/totalWagered - z*rtpStdError)
+	result.RTPCIHigh = round4(totalWon/totalWagered + z*rtpStdError)
+	sampleSize := EstimateSampleSize(lut, config.RTPTolerance, config.Confidence)
+	result.SampleSize = &sampleSize
+
 	// Calculate RTP success rates at test spins
 	rtpAtSpins := make([]RTPAtSpin, len(config.TestSpins))
 	var finalScore float64
@@ -311,25 +493,51 @@ func (s *Simulator) RunSimulation(lut *stakergs.LookupTable, config SimulationCo
 			SuccessRate: round4(successRate),
 			Weight:      weight,
 		}
+		if testSpin > 0 && testSpin <= config.Spins {
+			meanRTP := testSpinRTPSum[i] / float64(config.Trials)
+			stdError := spinStdDev / math.Sqrt(float64(testSpin))
+			rtpAtSpins[i].MeanRTP = round4(meanRTP)
+			rtpAtSpins[i].StdError = round6(stdError)
+			rtpAtSpins[i].CILow = round4(meanRTP - z*stdError)
+			rtpAtSpins[i].CIHigh = round4(meanRTP + z*stdError)
+		}
 		finalScore += successRate * weight
 	}
 	result.RTPatSpins = rtpAtSpins
 	result.FinalScore = round4(finalScore)
 
+	// Distribution of per-trial RTP at each test spin count, then the full session
+	testRTPs := make([]float64, config.Trials)
+	for i, testSpin := range config.TestSpins {
+		if testSpin <= 0 || testSpin >= config.Spins {
+			continue
+		}
+		for trial, out := range outcomes {
+			testRTPs[trial] = out.rtpAtTest[i]
+		}
+		result.RTPDistributions = append(result.RTPDistributions, NewRTPDistribution(testSpin, testRTPs, config.HistogramBins))
+	}
+	result.RTPDistributions = append(result.RTPDistributions, NewRTPDistribution(config.Spins, result.TrialRTPs, config.HistogramBins))
+
 	result.DurationMs = time.Since(start).Milliseconds()
 
 	return result
 }
 
 // RunQuickSimulation runs a simple simulation and returns spin-by-spin results.
-func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet float64) *SimulationResult {
+// The same seed and table give identical spins.
+func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet float64, seed int64) *SimulationResult {
+	return s.RunQuickSimulationStream(lut, spins, bet, seed, 0, nil)
+}
+
+// RunQuickSimulationStream is RunQuickSimulation that also passes every
+// batchSize spins to onBatch as they are generated, for live playback.
+// onBatch returning false stops the run early.
+func (s *Simulator) RunQuickSimulationStream(lut *stakergs.LookupTable, spins int, bet float64, seed int64, batchSize int, onBatch func([]SpinResult) bool) *SimulationResult {
 	start := time.Now()
 
 	sampler := NewWeightedSampler(lut)
-
-	s.mu.Lock()
-	rng := rand.New(rand.NewSource(s.rng.Int63()))
-	s.mu.Unlock()
+	rng := rand.New(rand.NewSource(TrialSeed(seed, 0)))
 
 	spinResults := make([]SpinResult, spins)
 	var totalWon float64
@@ -365,12 +573,21 @@ func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet
 			Balance:    round2(totalWon - wageredSoFar),
 			RunningRTP: round4(totalWon / wageredSoFar),
 		}
+
+		if onBatch != nil && batchSize > 0 && ((i+1)%batchSize == 0 || i == spins-1) {
+			if !onBatch(spinResults[(i/batchSize)*batchSize : i+1]) {
+				spins = i + 1
+				spinResults = spinResults[:spins]
+				break
+			}
+		}
 	}
 
 	totalWagered := float64(spins) * bet
 
 	return &SimulationResult{
 		Mode:         lut.Mode,
+		Seed:         seed,
 		TotalSpins:   spins,
 		TotalWagered: round2(totalWagered),
 		TotalWon:     round2(totalWon),
@@ -385,6 +602,7 @@ func (s *Simulator) RunQuickSimulation(lut *stakergs.LookupTable, spins int, bet
 		Config: SimulationConfig{
 			Spins: spins,
 			Bet:   bet,
+			Seed:  seed,
 		},
 	}
 }
@@ -0,0 +1,116 @@
+package lut
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"stakergs"
+)
+
+// StatsCache caches Analyze results per mode, keyed by a hash of the table
+// contents so in-place weight edits are never served stale statistics.
+type StatsCache struct {
+	analyzer *Analyzer
+
+	mu      sync.Mutex
+	entries map[string]*statsEntry
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type statsEntry struct {
+	hash  uint64
+	stats *Statistics
+}
+
+// StatsCacheMetrics reports how well the cache is doing.
+type StatsCacheMetrics struct {
+	Entries int     `json:"entries"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// NewStatsCache creates an empty statistics cache.
+func NewStatsCache() *StatsCache {
+	return &StatsCache{
+		analyzer: NewAnalyzer(),
+		entries:  make(map[string]*statsEntry),
+	}
+}
+
+// Statistics returns the statistics of a table, computing them only when the
+// table changed since the last call. The result is shared and must not be modified.
+func (c *StatsCache) Statistics(table *stakergs.LookupTable) *Statistics {
+	hash := TableHash(table)
+
+	c.mu.Lock()
+	entry := c.entries[table.Mode]
+	c.mu.Unlock()
+	if entry != nil && entry.hash == hash {
+		c.hits.Add(1)
+		return entry.stats
+	}
+
+	c.misses.Add(1)
+	stats := c.analyzer.Analyze(table)
+
+	c.mu.Lock()
+	c.entries[table.Mode] = &statsEntry{hash: hash, stats: stats}
+	c.mu.Unlock()
+	return stats
+}
+
+// Invalidate drops the cached statistics of a mode.
+func (c *StatsCache) Invalidate(mode string) {
+	c.mu.Lock()
+	delete(c.entries, mode)
+	c.mu.Unlock()
+}
+
+// InvalidateAll clears the entire cache.
+func (c *StatsCache) InvalidateAll() {
+	c.mu.Lock()
+	c.entries = make(map[string]*statsEntry)
+	c.mu.Unlock()
+}
+
+// Metrics returns entry count and hit/miss counters.
+func (c *StatsCache) Metrics() StatsCacheMetrics {
+	c.mu.Lock()
+	m := StatsCacheMetrics{Entries: len(c.entries)}
+	c.mu.Unlock()
+
+	m.Hits = c.hits.Load()
+	m.Misses = c.misses.Load()
+	if total := m.Hits + m.Misses; total > 0 {
+		m.HitRate = round4(float64(m.Hits) / float64(total))
+	}
+	return m
+}
+
+// TableHash fingerprints everything Analyze reads from a table: cost and
+// each outcome's simID, weight and payout.
+func TableHash(table *stakergs.LookupTable) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 8*3*256)
+	n := 0
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(table.Cost))
+	h.Write(buf[:8])
+	for _, o := range table.Outcomes {
+		binary.LittleEndian.PutUint64(buf[n:], uint64(o.SimID))
+		binary.LittleEndian.PutUint64(buf[n+8:], o.Weight)
+		binary.LittleEndian.PutUint64(buf[n+16:], uint64(o.Payout))
+		n += 24
+		if n == len(buf) {
+			h.Write(buf)
+			n = 0
+		}
+	}
+	h.Write(buf[:n])
+	return h.Sum64()
+}
@@ -0,0 +1,57 @@
+package lut
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"stakergs"
+)
+
+// SyntheticEvents returns a minimal events array for an outcome of a mode
+// without books: a payout-only reveal followed by the win totals, enough to
+// drive a game client through a round. Amounts use the LUT payout units
+// (multiplier * 100), like real books.
+func SyntheticEvents(payout uint) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`[`+
+		`{"index":0,"type":"reveal","gameType":"basegame","synthetic":true,"payoutMultiplier":%d},`+
+		`{"index":1,"type":"setTotalWin","amount":%d},`+
+		`{"index":2,"type":"finalWin","amount":%d}]`, payout, payout, payout))
+}
+
+// SyntheticBook wraps SyntheticEvents in a book line as found in events files.
+func SyntheticBook(simID int, payout uint) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"id":%d,"payoutMultiplier":%d,"events":%s}`,
+		simID, payout, SyntheticEvents(payout)))
+}
+
+// SetSyntheticEvents turns synthetic books on or off for modes whose events
+// are missing, so clients can be driven against LUT-only libraries.
+func (l *Loader) SetSyntheticEvents(enabled bool) {
+	l.syntheticEvents.Store(enabled)
+}
+
+// SyntheticEvents reports whether synthetic books are served.
+func (l *Loader) SyntheticEvents() bool {
+	return l.syntheticEvents.Load()
+}
+
+// GetBook returns the book of an outcome, lazily read from the mode's events
+// file. If the mode has no events (no file configured, or the book cannot be
+// read) and synthetic events are on, a synthetic book is returned instead and
+// synthetic is set.
+func (l *Loader) GetBook(mode string, table *stakergs.LookupTable, outcome stakergs.Outcome) (book json.RawMessage, synthetic bool, err error) {
+	config, err := l.GetModeConfig(mode)
+	if err == nil && config.Events == "" {
+		err = fmt.Errorf("no events file configured for mode: %s", mode)
+	}
+	if err == nil {
+		book, err = l.eventsLoader.GetEventLazy(mode, config.Events, outcome.SimID, table.SimIDOffset)
+		if err == nil {
+			return book, false, nil
+		}
+	}
+	if !l.SyntheticEvents() {
+		return nil, false, err
+	}
+	return SyntheticBook(outcome.SimID, outcome.Payout), true, nil
+}
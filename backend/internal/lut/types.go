@@ -2,10 +2,11 @@
 package lut
 
 import (
-	"fmt"
 	"math"
 	"sort"
 
+	"lutexplorer/internal/numfmt"
+
 	"stakergs"
 )
 
@@ -254,19 +255,13 @@ func (a *Analyzer) BuildDistribution(lut *stakergs.LookupTable, totalWeight uint
 
 // formatOdds formats odds as "1 in X" with appropriate suffix
 func formatOdds(odds float64) string {
-	if odds >= 1_000_000_000 {
-		return fmt.Sprintf("1 in %.2fB", odds/1_000_000_000)
-	}
-	if odds >= 1_000_000 {
-		return fmt.Sprintf("1 in %.2fM", odds/1_000_000)
-	}
 	if odds >= 1_000 {
-		return fmt.Sprintf("1 in %.2fK", odds/1_000)
+		return "1 in " + numfmt.English.Compact(odds)
 	}
 	if odds >= 10 {
-		return fmt.Sprintf("1 in %.0f", odds)
+		return "1 in " + numfmt.English.Number(odds, 0)
 	}
-	return fmt.Sprintf("1 in %.2f", odds)
+	return "1 in " + numfmt.English.Number(odds, 2)
 }
 
 // generateBucketBoundaries creates logarithmic bucket boundaries using 1-2-5 pattern
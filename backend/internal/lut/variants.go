@@ -0,0 +1,148 @@
+package lut
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stakergs"
+)
+
+// Variant is an in-memory copy of a mode's table with its own weights, used
+// to compare candidate weights against the originals (e.g. in two LGS
+// sessions side by side) before anything is written to disk. Variants are
+// never persisted and are dropped when the library changes.
+type Variant struct {
+	Name      string
+	Mode      string
+	CreatedAt time.Time
+	Table     *stakergs.LookupTable
+}
+
+// VariantInfo summarizes a variant for listing.
+type VariantInfo struct {
+	Name        string  `json:"name"`
+	Mode        string  `json:"mode"`
+	CreatedAt   int64   `json:"created_at"` // unix ms
+	Outcomes    int     `json:"outcomes"`
+	TotalWeight uint64  `json:"total_weight"`
+	RTP         float64 `json:"rtp"`
+	HitRate     float64 `json:"hit_rate"`
+}
+
+// Info returns the variant summary.
+func (v *Variant) Info() VariantInfo {
+	return VariantInfo{
+		Name:        v.Name,
+		Mode:        v.Mode,
+		CreatedAt:   v.CreatedAt.UnixMilli(),
+		Outcomes:    len(v.Table.Outcomes),
+		TotalWeight: v.Table.TotalWeight(),
+		RTP:         round4(v.Table.RTP()),
+		HitRate:     round4(v.Table.HitRate()),
+	}
+}
+
+// variantStore holds variants per mode (lowercase) and name.
+type variantStore struct {
+	mu     sync.RWMutex
+	byMode map[string]map[string]*Variant
+}
+
+func newVariantStore() *variantStore {
+	return &variantStore{byMode: make(map[string]map[string]*Variant)}
+}
+
+func (s *variantStore) clear() {
+	s.mu.Lock()
+	s.byMode = make(map[string]map[string]*Variant)
+	s.mu.Unlock()
+}
+
+// clearMode drops the variants of one mode.
+func (s *variantStore) clearMode(mode string) {
+	s.mu.Lock()
+	delete(s.byMode, strings.ToLower(mode))
+	s.mu.Unlock()
+}
+
+// CreateVariant snapshots a mode's table under name. With nil weights the
+// current weights are copied, which freezes the original for later
+// comparison; otherwise weights must match the outcome count. An existing
+// variant of the same name is replaced.
+func (l *Loader) CreateVariant(mode, name string, weights []uint64) (*Variant, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("variant name is required")
+	}
+	table, err := l.GetMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	if weights != nil && len(weights) != len(table.Outcomes) {
+		return nil, fmt.Errorf("weight count mismatch: got %d, expected %d", len(weights), len(table.Outcomes))
+	}
+
+	outcomes := make([]stakergs.Outcome, len(table.Outcomes))
+	copy(outcomes, table.Outcomes)
+	if weights != nil {
+		var total uint64
+		for i := range outcomes {
+			outcomes[i].Weight = weights[i]
+			total += weights[i]
+		}
+		if total == 0 {
+			return nil, fmt.Errorf("weights sum to zero")
+		}
+	}
+	clone := *table
+	clone.Outcomes = outcomes
+
+	v := &Variant{Name: name, Mode: table.Mode, CreatedAt: time.Now(), Table: &clone}
+
+	key := strings.ToLower(table.Mode)
+	l.variants.mu.Lock()
+	if l.variants.byMode[key] == nil {
+		l.variants.byMode[key] = make(map[string]*Variant)
+	}
+	l.variants.byMode[key][name] = v
+	l.variants.mu.Unlock()
+	return v, nil
+}
+
+// GetVariant returns a variant's table.
+func (l *Loader) GetVariant(mode, name string) (*stakergs.LookupTable, error) {
+	l.variants.mu.RLock()
+	defer l.variants.mu.RUnlock()
+	v := l.variants.byMode[strings.ToLower(mode)][name]
+	if v == nil {
+		return nil, fmt.Errorf("variant %q of mode %q not found", name, mode)
+	}
+	return v.Table, nil
+}
+
+// ListVariants returns the variants of a mode, oldest first.
+func (l *Loader) ListVariants(mode string) []VariantInfo {
+	l.variants.mu.RLock()
+	defer l.variants.mu.RUnlock()
+	infos := make([]VariantInfo, 0, len(l.variants.byMode[strings.ToLower(mode)]))
+	for _, v := range l.variants.byMode[strings.ToLower(mode)] {
+		infos = append(infos, v.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].CreatedAt < infos[j].CreatedAt })
+	return infos
+}
+
+// DeleteVariant removes a variant.
+func (l *Loader) DeleteVariant(mode, name string) error {
+	key := strings.ToLower(mode)
+	l.variants.mu.Lock()
+	defer l.variants.mu.Unlock()
+	if l.variants.byMode[key][name] == nil {
+		return fmt.Errorf("variant %q of mode %q not found", name, mode)
+	}
+	delete(l.variants.byMode[key], name)
+	return nil
+}
@@ -0,0 +1,148 @@
+package lut
+
+import (
+	"fmt"
+
+	"stakergs"
+)
+
+// WeightEdit sets the weight of a single outcome.
+type WeightEdit struct {
+	SimID  int    `json:"sim_id"`
+	Weight uint64 `json:"weight"`
+}
+
+// WhatIfMetrics are the headline numbers recomputed for a what-if.
+type WhatIfMetrics struct {
+	TotalWeight       uint64  `json:"total_weight"`
+	RTP               float64 `json:"rtp"`
+	HitRate           float64 `json:"hit_rate"`
+	HitFrequency      string  `json:"hit_frequency"`
+	StdDev            float64 `json:"std_dev"`
+	MaxWin            float64 `json:"max_win"`
+	MaxWinProbability float64 `json:"max_win_probability"`
+	MaxWinOdds        string  `json:"max_win_odds"`
+}
+
+// WhatIfOutcome shows how an edited outcome's odds change.
+type WhatIfOutcome struct {
+	SimID          int     `json:"sim_id"`
+	Payout         float64 `json:"payout"`
+	OldWeight      uint64  `json:"old_weight"`
+	NewWeight      uint64  `json:"new_weight"`
+	OldProbability float64 `json:"old_probability"`
+	NewProbability float64 `json:"new_probability"`
+	OldOdds        string  `json:"old_odds"`
+	NewOdds        string  `json:"new_odds"`
+}
+
+// WhatIfResult compares a table before and after weight edits.
+type WhatIfResult struct {
+	Mode         string          `json:"mode"`
+	Before       WhatIfMetrics   `json:"before"`
+	After        WhatIfMetrics   `json:"after"`
+	RTPDelta     float64         `json:"rtp_delta"`
+	HitRateDelta float64         `json:"hit_rate_delta"`
+	Outcomes     []WhatIfOutcome `json:"outcomes"`
+}
+
+// WhatIf applies weight edits to a copy of the table and reports the
+// recomputed metrics. Nothing is saved.
+func WhatIf(table *stakergs.LookupTable, edits []WeightEdit) (*WhatIfResult, error) {
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("at least one edit is required")
+	}
+
+	index := make(map[int]int, len(table.Outcomes))
+	weights := make([]uint64, len(table.Outcomes))
+	for i, o := range table.Outcomes {
+		index[o.SimID] = i
+		weights[i] = o.Weight
+	}
+
+	seen := make(map[int]bool, len(edits))
+	for _, e := range edits {
+		i, ok := index[e.SimID]
+		if !ok {
+			return nil, fmt.Errorf("sim_id %d not found in mode %q", e.SimID, table.Mode)
+		}
+		if seen[e.SimID] {
+			return nil, fmt.Errorf("sim_id %d edited more than once", e.SimID)
+		}
+		seen[e.SimID] = true
+		weights[i] = e.Weight
+	}
+
+	edited, err := WithWeights(table, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	before := whatIfMetrics(table)
+	after := whatIfMetrics(edited)
+	result := &WhatIfResult{
+		Mode:         table.Mode,
+		Before:       before,
+		After:        after,
+		RTPDelta:     round6(after.RTP - before.RTP),
+		HitRateDelta: round6(after.HitRate - before.HitRate),
+		Outcomes:     make([]WhatIfOutcome, 0, len(edits)),
+	}
+
+	for _, e := range edits {
+		o := table.Outcomes[index[e.SimID]]
+		oldProb := float64(o.Weight) / float64(before.TotalWeight)
+		newProb := float64(e.Weight) / float64(after.TotalWeight)
+		result.Outcomes = append(result.Outcomes, WhatIfOutcome{
+			SimID:          o.SimID,
+			Payout:         float64(o.Payout) / 100.0,
+			OldWeight:      o.Weight,
+			NewWeight:      e.Weight,
+			OldProbability: oldProb,
+			NewProbability: newProb,
+			OldOdds:        FormatOdds(oldProb),
+			NewOdds:        FormatOdds(newProb),
+		})
+	}
+
+	return result, nil
+}
+
+func whatIfMetrics(table *stakergs.LookupTable) WhatIfMetrics {
+	mean, stdDev := SpinRTPStats(table)
+	m := WhatIfMetrics{
+		TotalWeight:  table.TotalWeight(),
+		RTP:          round6(mean),
+		HitRate:      round6(table.HitRate()),
+		HitFrequency: "-",
+		StdDev:       round6(stdDev),
+		MaxWinOdds:   "-",
+	}
+	if m.HitRate > 0 {
+		m.HitFrequency = formatOdds(1 / table.HitRate())
+	}
+	if m.TotalWeight == 0 {
+		return m
+	}
+
+	// Max win among outcomes that can still hit
+	var maxPayout uint
+	var maxWeight uint64
+	for _, o := range table.Outcomes {
+		if o.Weight == 0 {
+			continue
+		}
+		if o.Payout > maxPayout {
+			maxPayout, maxWeight = o.Payout, 0
+		}
+		if o.Payout == maxPayout {
+			maxWeight += o.Weight
+		}
+	}
+	m.MaxWin = float64(maxPayout) / 100.0
+	m.MaxWinProbability = float64(maxWeight) / float64(m.TotalWeight)
+	if maxWeight > 0 {
+		m.MaxWinOdds = formatOdds(1 / m.MaxWinProbability)
+	}
+	return m
+}
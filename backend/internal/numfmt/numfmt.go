@@ -0,0 +1,211 @@
+// Package numfmt formats numbers for people: thousands separators, decimal
+// marks, percentages, compact notation (1.23M) and currency amounts, per
+// locale. The locale is picked from an Accept-Language header, so responses
+// with human-readable strings match the reader's language; English is the
+// default.
+package numfmt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Space characters used by locales as separators.
+const (
+	nbsp       = "\u00a0" // no-break space
+	narrowNbsp = "\u202f" // narrow no-break space
+)
+
+// compactUnit abbreviates numbers of at least Value, e.g. {1e6, "M"}.
+type compactUnit struct {
+	Value  float64
+	Suffix string
+}
+
+// Locale holds the formatting conventions of a language.
+type Locale struct {
+	Tag          string
+	Group        string // thousands separator
+	Decimal      string // decimal mark
+	PercentSpace string // between a number and "%"
+	PercentFirst bool   // "%12" instead of "12%"
+	// Units for compact notation, ascending. Numbers below the first are
+	// written in full.
+	Units []compactUnit
+	// CurrencyFirst puts the symbol before the amount ($1.00) instead of
+	// after it (1,00 €)
+	CurrencyFirst bool
+}
+
+var locales = map[string]*Locale{
+	"en": {Tag: "en", Group: ",", Decimal: ".", CurrencyFirst: true,
+		Units: []compactUnit{{1e3, "K"}, {1e6, "M"}, {1e9, "B"}}},
+	"de": {Tag: "de", Group: ".", Decimal: ",", PercentSpace: nbsp,
+		Units: []compactUnit{{1e3, nbsp + "Tsd."}, {1e6, nbsp + "Mio."}, {1e9, nbsp + "Mrd."}}},
+	"el": {Tag: "el", Group: ".", Decimal: ",",
+		Units: []compactUnit{{1e3, nbsp + "χιλ."}, {1e6, nbsp + "εκ."}, {1e9, nbsp + "δισ."}}},
+	"es": {Tag: "es", Group: ".", Decimal: ",", PercentSpace: nbsp,
+		Units: []compactUnit{{1e3, nbsp + "mil"}, {1e6, nbsp + "M"}, {1e9, nbsp + "mil" + nbsp + "M"}}},
+	"fi": {Tag: "fi", Group: nbsp, Decimal: ",", PercentSpace: nbsp,
+		Units: []compactUnit{{1e3, nbsp + "t."}, {1e6, nbsp + "milj."}, {1e9, nbsp + "mrd."}}},
+	"fr": {Tag: "fr", Group: narrowNbsp, Decimal: ",", PercentSpace: narrowNbsp,
+		Units: []compactUnit{{1e3, nbsp + "k"}, {1e6, nbsp + "M"}, {1e9, nbsp + "Md"}}},
+	"it": {Tag: "it", Group: ".", Decimal: ",",
+		Units: []compactUnit{{1e6, nbsp + "Mln"}, {1e9, nbsp + "Mrd"}}},
+	"ko": {Tag: "ko", Group: ",", Decimal: ".", CurrencyFirst: true,
+		Units: []compactUnit{{1e3, "천"}, {1e4, "만"}, {1e8, "억"}, {1e12, "조"}}},
+	"pt": {Tag: "pt", Group: ".", Decimal: ",",
+		Units: []compactUnit{{1e3, nbsp + "mil"}, {1e6, nbsp + "mi"}, {1e9, nbsp + "bi"}}},
+	"ru": {Tag: "ru", Group: nbsp, Decimal: ",", PercentSpace: nbsp,
+		Units: []compactUnit{{1e3, nbsp + "тыс."}, {1e6, nbsp + "млн"}, {1e9, nbsp + "млрд"}}},
+	"th": {Tag: "th", Group: ",", Decimal: ".", CurrencyFirst: true,
+		Units: []compactUnit{{1e3, "K"}, {1e6, "M"}, {1e9, "B"}}},
+	"tr": {Tag: "tr", Group: ".", Decimal: ",", PercentFirst: true, CurrencyFirst: true,
+		Units: []compactUnit{{1e3, nbsp + "B"}, {1e6, nbsp + "Mn"}, {1e9, nbsp + "Mr"}}},
+	"vi": {Tag: "vi", Group: ".", Decimal: ",",
+		Units: []compactUnit{{1e3, nbsp + "N"}, {1e6, nbsp + "Tr"}, {1e9, nbsp + "T"}}},
+	"zh": {Tag: "zh", Group: ",", Decimal: ".", CurrencyFirst: true,
+		Units: []compactUnit{{1e4, "万"}, {1e8, "亿"}, {1e12, "万亿"}}},
+}
+
+// English is the default locale.
+var English = locales["en"]
+
+// Lookup returns the locale of a language tag such as "de" or "pt-BR", or
+// nil if the language is not supported. Regions are ignored.
+func Lookup(tag string) *Locale {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	return locales[lang]
+}
+
+// FromAcceptLanguage picks the supported locale the header prefers most,
+// e.g. "fr-CH, fr;q=0.9, en;q=0.8". It falls back to English.
+func FromAcceptLanguage(header string) *Locale {
+	best, bestQ := English, -1.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if loc := Lookup(tag); loc != nil && q > 0 && q > bestQ {
+			best, bestQ = loc, q
+		}
+	}
+	return best
+}
+
+// Number formats v with the given number of decimals and thousands
+// separators, e.g. 1234567.891 -> "1,234,567.89".
+func (l *Locale) Number(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// Int formats an integer with thousands separators.
+func (l *Locale) Int(v int64) string {
+	return l.Number(float64(v), 0)
+}
+
+// Percent formats a fraction as a percentage, e.g. 0.9650 -> "96.50%".
+func (l *Locale) Percent(fraction float64, decimals int) string {
+	if l.PercentFirst {
+		return "%" + l.PercentSpace + l.Number(fraction*100, decimals)
+	}
+	return l.Number(fraction*100, decimals) + l.PercentSpace + "%"
+}
+
+// Compact abbreviates large numbers with two decimals, e.g. 20000000 ->
+// "20.00M" in English and "20,00 Mio." in German. Numbers below the
+// locale's smallest unit are written in full without decimals.
+func (l *Locale) Compact(v float64) string {
+	abs := math.Abs(v)
+	for i := len(l.Units) - 1; i >= 0; i-- {
+		unit := l.Units[i]
+		if abs >= unit.Value {
+			return l.Number(v/unit.Value, 2) + unit.Suffix
+		}
+	}
+	return l.Number(v, 0)
+}
+
+// currencyDigits lists ISO 4217 currencies whose minor unit is not a
+// hundredth.
+var currencyDigits = map[string]int{
+	"BHD": 3, "CLP": 0, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "KRW": "₩",
+	"INR": "₹", "BRL": "R$", "RUB": "₽", "TRY": "₺", "PLN": "zł",
+}
+
+// MinorUnits returns the number of decimals of a currency's minor unit:
+// 2 for most currencies, 0 for e.g. JPY, 3 for e.g. KWD.
+func MinorUnits(currency string) int {
+	if d, ok := currencyDigits[strings.ToUpper(currency)]; ok {
+		return d
+	}
+	return 2
+}
+
+// Currency formats an amount in major units with the currency's minor unit
+// precision and symbol (or code), e.g. "$1,234.56" or "1.234,56 €".
+func (l *Locale) Currency(amount float64, currency string) string {
+	code := strings.ToUpper(currency)
+	return l.Money(amount, currencySymbols[code], code, MinorUnits(code))
+}
+
+// Money formats an amount in major units with the given precision and
+// symbol, or the code when symbol is empty, for currencies configured at
+// run time.
+func (l *Locale) Money(amount float64, symbol, code string, decimals int) string {
+	number := l.Number(math.Abs(amount), decimals)
+	sign := ""
+	if amount < 0 && strings.Trim(number, "0"+l.Group+l.Decimal) != "" {
+		sign = "-"
+	}
+	if symbol == "" {
+		symbol = code
+		if l.CurrencyFirst {
+			symbol += nbsp // codes need a gap: "CAD 1.00"
+		}
+	}
+	if l.CurrencyFirst {
+		return sign + symbol + number
+	}
+	return sign + number + nbsp + symbol
+}
+
+// Symbol returns the display symbol of a currency, or "" if it has none.
+func Symbol(currency string) string {
+	return currencySymbols[strings.ToUpper(currency)]
+}
@@ -0,0 +1,278 @@
+// Package openapi builds an OpenAPI 3 document from the routes a server
+// registers, with JSON schemas derived from the Go request and response
+// types by reflection.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.0.3"
+
+// Route documents one endpoint. Request and Response are zero values of the
+// JSON body types (nil if there is none or it is not a fixed shape).
+type Route struct {
+	Method   string
+	Path     string // with {param} placeholders
+	Summary  string
+	Tag      string // grouping in the docs; derived from the path if empty
+	Query    []string
+	Request  interface{}
+	Response interface{}
+	// Raw responses are sent as is instead of in the
+	// {"success": ..., "data": ..., "error": ...} envelope
+	Raw bool
+}
+
+// Document is a generated OpenAPI document.
+type Document map[string]interface{}
+
+var pathParamRe = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// Build generates the document for routes. Routes with the same method and
+// path are documented once.
+func Build(title, version string, routes []Route) Document {
+	b := &builder{schemas: map[string]interface{}{}, names: map[reflect.Type]string{}}
+	paths := map[string]map[string]interface{}{}
+
+	for _, route := range routes {
+		method := strings.ToLower(route.Method)
+		path := pathParamRe.ReplaceAllString(route.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		if _, ok := paths[path][method]; ok {
+			continue
+		}
+
+		tag := route.Tag
+		if tag == "" {
+			tag = tagOf(path)
+		}
+		op := map[string]interface{}{
+			"summary":     route.Summary,
+			"tags":        []string{tag},
+			"operationId": operationID(method, path),
+		}
+
+		var params []interface{}
+		for _, m := range pathParamRe.FindAllStringSubmatch(route.Path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": m[1], "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, q := range route.Query {
+			params = append(params, map[string]interface{}{
+				"name": q, "in": "query",
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if route.Request != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": b.schemaOf(reflect.TypeOf(route.Request))},
+				},
+			}
+		}
+
+		var data interface{} = map[string]interface{}{}
+		if route.Response != nil {
+			data = b.schemaOf(reflect.TypeOf(route.Response))
+		}
+		body := data
+		if !route.Raw {
+			body = map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"success": map[string]interface{}{"type": "boolean"},
+					"data":    data,
+					"error":   map[string]interface{}{"type": "string"},
+				},
+			}
+		}
+		op["responses"] = map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "OK",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": body},
+				},
+			},
+		}
+		paths[path][method] = op
+	}
+
+	return Document{
+		"openapi": Version,
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": b.schemas,
+		},
+	}
+}
+
+// tagOf groups a path by its first segment after /api, or its first
+// segment for the LGS (/wallet, /bet, /lgs) and other top-level routes.
+func tagOf(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case parts[0] == "wallet" || parts[0] == "bet" || parts[0] == "lgs":
+		return "lgs"
+	case parts[0] == "api" && len(parts) > 1:
+		return parts[1]
+	}
+	return parts[0]
+}
+
+// operationID turns "get /api/mode/{mode}/stats" into "getApiModeModeStats".
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(method)
+	upper := true
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// builder collects named struct schemas in components.
+type builder struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (b *builder) schemaOf(t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.ref(t)
+	}
+	return map[string]interface{}{}
+}
+
+// ref registers a named struct in components and returns a reference.
+func (b *builder) ref(t reflect.Type) interface{} {
+	name, ok := b.names[t]
+	if !ok {
+		name = componentName(t)
+		for taken := true; taken; {
+			_, taken = b.schemas[name]
+			if taken {
+				name += "_"
+			}
+		}
+		b.names[t] = name
+		b.schemas[name] = nil // reserve the name for recursive types
+		b.schemas[name] = b.structSchema(t)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// componentName is the package and type name, e.g. "lgs.PlayRequest".
+func componentName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	name := t.Name()
+	// Generic instantiations carry their type arguments in the name
+	name = strings.NewReplacer("[", "_", "]", "", "*", "", "/", "_", ",", "_").Replace(name)
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func (b *builder) structSchema(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	b.addFields(t, props)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+// addFields adds the JSON fields of a struct, flattening embedded structs
+// the way encoding/json does. Fields are not marked required: handlers
+// default the ones a request leaves out.
+func (b *builder) addFields(t reflect.Type, props map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(ft, props)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = b.schemaOf(f.Type)
+	}
+}
@@ -1,6 +1,7 @@
 package optimizer
 
 import (
+	"fmt"
 	"math"
 	"time"
 
@@ -104,28 +105,34 @@ func (o *BruteForceOptimizer) OptimizeTable(table *stakergs.LookupTable) (*Brute
 
 	originalRTP := calculateRTPFromWeights(originalWeights, payouts)
 
+	pinned, err := resolvePinnedOutcomes(table, o.config.PinnedOutcomes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create base bucket optimizer for initial assignment
 	baseOptimizer := NewBucketOptimizer(o.config)
 
-	// Assign outcomes to buckets
+	// Assign outcomes to buckets, leaving pinned outcomes out
 	assignments, lossIndices, warnings := baseOptimizer.assignOutcomesToBuckets(payouts)
+	lossIndices = pinned.exclude(assignments, lossIndices)
+
+	// The global max win is weighted in the same pass as the loss weight
+	maxWin, maxWinWarnings := baseOptimizer.resolveGlobalMaxWin(payouts, pinned)
+	lossIndices = maxWin.exclude(assignments, lossIndices)
+	warnings = append(warnings, maxWinWarnings...)
 
 	// Calculate initial target probabilities
-	probWarnings := baseOptimizer.calculateTargetProbabilities(assignments)
+	probWarnings := baseOptimizer.calculateTargetProbabilities(assignments, pinned.rtp(payouts)+maxWin.rtp())
 	warnings = append(warnings, probWarnings...)
 
 	// Calculate initial weights using the base algorithm
-	newWeights, bucketResults, lossResult, weightWarnings := baseOptimizer.calculateWeights(payouts, assignments, lossIndices)
+	newWeights, bucketResults, lossResult, weightWarnings := baseOptimizer.calculateWeights(payouts, assignments, lossIndices, pinned, maxWin)
 	warnings = append(warnings, weightWarnings...)
 
 	// Send initial progress
 	o.sendProgress("init", 0, calculateRTPFromWeights(newWeights, payouts))
 
-	// Handle global max win frequency if specified
-	if o.config.GlobalMaxWinFreq > 0 {
-		o.applyGlobalMaxWinFrequency(newWeights, payouts, assignments, lossIndices)
-	}
-
 	// Handle per-bucket max win frequency
 	for i := range assignments {
 		if assignments[i].config.Type == ConstraintMaxWinFreq && assignments[i].config.MaxWinFrequency > 0 {
@@ -156,9 +163,11 @@ func (o *BruteForceOptimizer) OptimizeTable(table *stakergs.LookupTable) (*Brute
 			break
 		}
 
-		// Apply coordinate descent on loss weight
+		// Apply coordinate descent on loss weight, re-weighting the global
+		// max win for the total it yields
 		if len(lossIndices) > 0 {
-			newWeights = o.refineLossWeight(newWeights, payouts, lossIndices)
+			maxWin.apply(newWeights, payouts, o.config.TargetRTP, o.config.MinWeight)
+			newWeights = o.refineLossWeight(newWeights, payouts, lossIndices, pinned)
 		}
 
 		currentRTP = calculateRTPFromWeights(newWeights, payouts)
@@ -222,7 +231,7 @@ func (o *BruteForceOptimizer) OptimizeTable(table *stakergs.LookupTable) (*Brute
 	}
 
 	// Build outcome details
-	outcomeDetails := baseOptimizer.buildOutcomeDetails(table, payouts, originalWeights, finalWeights, assignments, lossIndices)
+	outcomeDetails := baseOptimizer.buildOutcomeDetails(table, payouts, originalWeights, finalWeights, assignments, lossIndices, pinned, maxWin)
 
 	// Add convergence warning if needed
 	if !finalConverged {
@@ -232,6 +241,14 @@ func (o *BruteForceOptimizer) OptimizeTable(table *stakergs.LookupTable) (*Brute
 		}
 	}
 
+	var pinnedRTP float64
+	if len(pinned) > 0 {
+		pinnedRTP = pinned.contribution(finalWeights, payouts)
+		warnings = append(warnings, fmt.Sprintf(
+			"Pinned %d outcome(s) contributing %.2f%% RTP",
+			len(pinned), pinnedRTP*100))
+	}
+
 	result := &BucketOptimizerResult{
 		OriginalRTP:    originalRTP,
 		FinalRTP:       finalRTP,
@@ -243,6 +260,8 @@ func (o *BruteForceOptimizer) OptimizeTable(table *stakergs.LookupTable) (*Brute
 		TotalWeight:    sumUint64(finalWeights),
 		Warnings:       warnings,
 		OutcomeDetails: outcomeDetails,
+		PinnedRTP:      pinnedRTP,
+		MaxWinFrequency: maxWin.frequency(finalWeights),
 	}
 
 	return &BruteForceResult{
@@ -276,53 +295,6 @@ func (o *BruteForceOptimizer) sendProgress(phase string, iteration int, currentR
 	}
 }
 
-// applyGlobalMaxWinFrequency applies global maximum win frequency constraint
-func (o *BruteForceOptimizer) applyGlobalMaxWinFrequency(weights []uint64, payouts []float64, assignments []bucketAssignment, lossIndices []int) {
-	// Find the global maximum payout outcome
-	maxPayoutIdx := -1
-	maxPayout := 0.0
-	for i, p := range payouts {
-		if p > maxPayout {
-			maxPayout = p
-			maxPayoutIdx = i
-		}
-	}
-
-	if maxPayoutIdx < 0 || o.config.GlobalMaxWinFreq <= 0 {
-		return
-	}
-
-	// Calculate required weight for this outcome
-	// Probability = 1/GlobalMaxWinFreq
-	// Weight = Probability * TotalWeight
-	targetProb := 1.0 / o.config.GlobalMaxWinFreq
-	totalWeight := sumUint64(weights)
-	requiredWeight := uint64(targetProb * float64(totalWeight))
-
-	if requiredWeight < o.config.MinWeight {
-		requiredWeight = o.config.MinWeight
-	}
-
-	// Adjust the max payout outcome's weight
-	oldWeight := weights[maxPayoutIdx]
-	weights[maxPayoutIdx] = requiredWeight
-
-	// Compensate by adjusting loss weights if possible
-	if len(lossIndices) > 0 {
-		// Distribute the weight change across loss outcomes
-		weightDiff := int64(oldWeight) - int64(requiredWeight)
-		adjustmentPerLoss := weightDiff / int64(len(lossIndices))
-
-		for _, idx := range lossIndices {
-			newLossWeight := int64(weights[idx]) + adjustmentPerLoss
-			if newLossWeight < int64(o.config.MinWeight) {
-				newLossWeight = int64(o.config.MinWeight)
-			}
-			weights[idx] = uint64(newLossWeight)
-		}
-	}
-}
-
 // applyBucketMaxWinFrequency applies max win frequency constraint within a bucket
 func (o *BruteForceOptimizer) applyBucketMaxWinFrequency(bucket *bucketAssignment, weights []uint64, payouts []float64) {
 	if len(bucket.outcomeIndices) == 0 {
@@ -352,7 +324,7 @@ func (o *BruteForceOptimizer) applyBucketMaxWinFrequency(bucket *bucketAssignmen
 }
 
 // refineLossWeight uses binary search to find optimal loss weight
-func (o *BruteForceOptimizer) refineLossWeight(weights []uint64, payouts []float64, lossIndices []int) []uint64 {
+func (o *BruteForceOptimizer) refineLossWeight(weights []uint64, payouts []float64, lossIndices []int, pinned pinnedWeights) []uint64 {
 	result := make([]uint64, len(weights))
 	copy(result, weights)
 
@@ -370,7 +342,8 @@ func (o *BruteForceOptimizer) refineLossWeight(weights []uint64, payouts []float
 	// RTP = weightedPayoutSum / (totalWinWeight + totalLossWeight)
 	// targetRTP * (totalWinWeight + totalLossWeight) = weightedPayoutSum
 	// totalLossWeight = weightedPayoutSum / targetRTP - totalWinWeight
-	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight)
+	// (minus the weight of pinned losses)
+	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight) - pinned.lossWeight(payouts)
 	if requiredLossWeight < float64(len(lossIndices)) {
 		requiredLossWeight = float64(len(lossIndices))
 	}
@@ -76,6 +76,7 @@ type BucketOptimizerConfig struct {
 	EnableVoiding       bool             `json:"enable_voiding,omitempty"`        // Enable bucket voiding (default: false) - DEPRECATED, use EnableAutoVoiding
 	VoidedBucketIndices []int            `json:"voided_bucket_indices,omitempty"` // Indices of buckets to void - DEPRECATED
 	EnableAutoVoiding   bool             `json:"enable_auto_voiding,omitempty"`   // Enable automatic outcome voiding to reach target RTP
+	PinnedOutcomes      []PinnedOutcome  `json:"pinned_outcomes,omitempty"`       // Outcomes kept at a fixed weight (still counted in RTP)
 }
 
 // SearchState holds the current state during iterative optimization
@@ -190,6 +191,8 @@ type BucketOptimizerResult struct {
 	VoidedOutcomes []VoidedOutcomeInfo `json:"voided_outcomes,omitempty"` // Auto-voided outcomes
 	TotalVoided    int                 `json:"total_voided,omitempty"`    // Total count of voided outcomes
 	VoidedRTP      float64             `json:"voided_rtp,omitempty"`      // Total RTP removed by voiding
+	PinnedRTP      float64             `json:"pinned_rtp,omitempty"`      // RTP contributed by pinned outcomes
+	MaxWinFrequency float64            `json:"max_win_frequency,omitempty"` // Achieved global max win frequency (1 in N)
 }
 
 // OutcomeDetail shows how each outcome was assigned
@@ -403,6 +406,12 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 
 	originalRTP := calculateRTPFromWeights(originalWeights, payouts)
 
+	pinned, err := resolvePinnedOutcomes(table, o.config.PinnedOutcomes)
+	if err != nil {
+		return nil, err
+	}
+	maxWin, maxWinWarnings := o.resolveGlobalMaxWin(payouts, pinned)
+
 	// Extract simIDs for auto-voiding
 	simIDs := make([]int, n)
 	for i, outcome := range table.Outcomes {
@@ -416,7 +425,22 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 
 	if o.config.EnableAutoVoiding {
 		minRTP := calculateMinAchievableRTP(payouts)
-		autoVoidedIndices, autoVoidedOutcomes = autoSelectOutcomesToVoid(payouts, simIDs, o.config.TargetRTP, minRTP)
+		// Pinned and global max win outcomes keep their weight, so they
+		// are never voided
+		voidable := payouts
+		if len(pinned) > 0 || maxWin != nil {
+			voidable = make([]float64, n)
+			copy(voidable, payouts)
+			for idx := range pinned {
+				voidable[idx] = 0
+			}
+			if maxWin != nil {
+				for _, idx := range maxWin.indices {
+					voidable[idx] = 0
+				}
+			}
+		}
+		autoVoidedIndices, autoVoidedOutcomes = autoSelectOutcomesToVoid(voidable, simIDs, o.config.TargetRTP, minRTP)
 		// Calculate total voided RTP
 		for _, vo := range autoVoidedOutcomes {
 			autoVoidedRTP += vo.RTPLoss
@@ -433,6 +457,9 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 
 	// Assign outcomes to buckets
 	assignments, lossIndices, warnings := o.assignOutcomesToBuckets(payouts)
+	lossIndices = pinned.exclude(assignments, lossIndices)
+	lossIndices = maxWin.exclude(assignments, lossIndices)
+	warnings = append(warnings, maxWinWarnings...)
 
 	// LEGACY: Mark voided buckets and collect voided outcomes info (deprecated)
 	var voidedBuckets []VoidedBucketInfo
@@ -469,11 +496,12 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 	}
 
 	// Calculate target probabilities for each bucket (excluding voided)
-	probWarnings := o.calculateTargetProbabilities(assignments)
+	// Pinned outcomes and the global max win take their share of the RTP first
+	probWarnings := o.calculateTargetProbabilities(assignments, pinned.rtp(payouts)+maxWin.rtp())
 	warnings = append(warnings, probWarnings...)
 
 	// Calculate weights (voided outcomes will have weight 0)
-	newWeights, bucketResults, lossResult := o.calculateWeightsWithVoiding(payouts, assignments, lossIndices, voidedOutcomeIndices)
+	newWeights, bucketResults, lossResult := o.calculateWeightsWithVoiding(payouts, assignments, lossIndices, voidedOutcomeIndices, pinned, maxWin)
 
 	// Calculate final RTP
 	finalRTP := calculateRTPFromWeights(newWeights, payouts)
@@ -481,7 +509,7 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 
 	// Fine-tune if not converged
 	if !converged && len(lossIndices) > 0 {
-		newWeights = o.fineTuneLossWeightWithVoiding(newWeights, payouts, lossIndices, voidedOutcomeIndices)
+		newWeights = o.fineTuneLossWeightWithVoiding(newWeights, payouts, lossIndices, voidedOutcomeIndices, pinned)
 		finalRTP = calculateRTPFromWeights(newWeights, payouts)
 		converged = math.Abs(finalRTP-o.config.TargetRTP) <= o.config.RTPTolerance
 
@@ -521,8 +549,17 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 			len(autoVoidedOutcomes), autoVoidedRTP*100))
 	}
 
+	// Add info about pinned outcomes
+	var pinnedRTP float64
+	if len(pinned) > 0 {
+		pinnedRTP = pinned.contribution(newWeights, payouts)
+		warnings = append(warnings, fmt.Sprintf(
+			"Pinned %d outcome(s) contributing %.2f%% RTP",
+			len(pinned), pinnedRTP*100))
+	}
+
 	// Build outcome details
-	outcomeDetails := o.buildOutcomeDetailsWithVoiding(table, payouts, originalWeights, newWeights, assignments, lossIndices, voidedOutcomeIndices)
+	outcomeDetails := o.buildOutcomeDetailsWithVoiding(table, payouts, originalWeights, newWeights, assignments, lossIndices, voidedOutcomeIndices, pinned, maxWin)
 
 	return &BucketOptimizerResult{
 		OriginalRTP:    originalRTP,
@@ -539,6 +576,8 @@ func (o *BucketOptimizer) OptimizeTable(table *stakergs.LookupTable) (*BucketOpt
 		VoidedOutcomes: autoVoidedOutcomes,
 		TotalVoided:    len(autoVoidedOutcomes),
 		VoidedRTP:      autoVoidedRTP,
+		PinnedRTP:      pinnedRTP,
+		MaxWinFrequency: maxWin.frequency(newWeights),
 	}, nil
 }
 
@@ -621,10 +660,11 @@ func (o *BucketOptimizer) assignOutcomesToBuckets(payouts []float64) ([]bucketAs
 // calculateTargetProbabilities calculates target probability for each bucket
 // For auto buckets, it first calculates non-auto buckets, then distributes remaining RTP
 // Returns warnings if constraints are impossible to satisfy
-func (o *BucketOptimizer) calculateTargetProbabilities(assignments []bucketAssignment) []string {
+// reservedRTP is RTP already taken by outcomes outside the buckets (pinned outcomes)
+func (o *BucketOptimizer) calculateTargetProbabilities(assignments []bucketAssignment, reservedRTP float64) []string {
 	var warnings []string
 	// First pass: calculate probabilities for frequency and rtp_percent buckets
-	var usedRTP float64
+	usedRTP := reservedRTP
 
 	for i := range assignments {
 		bucket := &assignments[i]
@@ -736,7 +776,7 @@ func (o *BucketOptimizer) calculateTargetProbabilities(assignments []bucketAssig
 }
 
 // calculateWeights converts probabilities to weights
-func (o *BucketOptimizer) calculateWeights(payouts []float64, assignments []bucketAssignment, lossIndices []int) ([]uint64, []BucketResult, *BucketResult, []string) {
+func (o *BucketOptimizer) calculateWeights(payouts []float64, assignments []bucketAssignment, lossIndices []int, pinned pinnedWeights, maxWin *globalMaxWin) ([]uint64, []BucketResult, *BucketResult, []string) {
 	n := len(payouts)
 	weights := make([]uint64, n)
 
@@ -847,6 +887,12 @@ func (o *BucketOptimizer) calculateWeights(payouts []float64, assignments []buck
 	// Σ(winWeight * payout) / (winWeight + lossWeight) = targetRTP
 	// weightedPayoutSum / (totalWinWeight + lossWeight) = targetRTP
 	// lossWeight = weightedPayoutSum / targetRTP - totalWinWeight
+	//
+	// Pinned outcomes keep their weight: pinned wins count in the sums,
+	// pinned losses cover part of the loss weight. The global max win is
+	// weighted for the total this loss weight yields.
+	pinned.apply(weights)
+	maxWin.apply(weights, payouts, o.config.TargetRTP, o.config.MinWeight)
 
 	var weightedPayoutSum float64
 	var totalWinWeight uint64
@@ -858,7 +904,7 @@ func (o *BucketOptimizer) calculateWeights(payouts []float64, assignments []buck
 	}
 
 	// Required loss weight
-	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight)
+	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight) - pinned.lossWeight(payouts)
 	if requiredLossWeight < float64(o.config.MinWeight) {
 		requiredLossWeight = float64(o.config.MinWeight)
 	}
@@ -971,7 +1017,7 @@ func (o *BucketOptimizer) calculateLossResult(weights []uint64, payouts []float6
 }
 
 // buildOutcomeDetails creates detailed info for each outcome
-func (o *BucketOptimizer) buildOutcomeDetails(table *stakergs.LookupTable, payouts []float64, oldWeights, newWeights []uint64, assignments []bucketAssignment, lossIndices []int) []OutcomeDetail {
+func (o *BucketOptimizer) buildOutcomeDetails(table *stakergs.LookupTable, payouts []float64, oldWeights, newWeights []uint64, assignments []bucketAssignment, lossIndices []int, pinned pinnedWeights, maxWin *globalMaxWin) []OutcomeDetail {
 	totalWeight := sumUint64(newWeights)
 	details := make([]OutcomeDetail, len(payouts))
 
@@ -985,6 +1031,14 @@ func (o *BucketOptimizer) buildOutcomeDetails(table *stakergs.LookupTable, payou
 	for _, idx := range lossIndices {
 		bucketNames[idx] = "loss"
 	}
+	for idx := range pinned {
+		bucketNames[idx] = "pinned"
+	}
+	if maxWin != nil {
+		for _, idx := range maxWin.indices {
+			bucketNames[idx] = "max_win"
+		}
+	}
 
 	for i := range payouts {
 		details[i] = OutcomeDetail{
@@ -1001,7 +1055,8 @@ func (o *BucketOptimizer) buildOutcomeDetails(table *stakergs.LookupTable, payou
 }
 
 // calculateWeightsWithVoiding converts probabilities to weights, setting voided outcomes to weight 0
-func (o *BucketOptimizer) calculateWeightsWithVoiding(payouts []float64, assignments []bucketAssignment, lossIndices []int, voidedOutcomeIndices []int) ([]uint64, []BucketResult, *BucketResult) {
+// pinned outcomes to their pinned weight and the global max win to its frequency
+func (o *BucketOptimizer) calculateWeightsWithVoiding(payouts []float64, assignments []bucketAssignment, lossIndices []int, voidedOutcomeIndices []int, pinned pinnedWeights, maxWin *globalMaxWin) ([]uint64, []BucketResult, *BucketResult) {
 	n := len(payouts)
 	weights := make([]uint64, n)
 
@@ -1096,6 +1151,8 @@ func (o *BucketOptimizer) calculateWeightsWithVoiding(payouts []float64, assignm
 	for _, idx := range voidedOutcomeIndices {
 		weights[idx] = 0
 	}
+	pinned.apply(weights)
+	maxWin.apply(weights, payouts, o.config.TargetRTP, o.config.MinWeight)
 
 	// Calculate loss weight (same as before)
 	var weightedPayoutSum float64
@@ -1107,8 +1164,8 @@ func (o *BucketOptimizer) calculateWeightsWithVoiding(payouts []float64, assignm
 		}
 	}
 
-	// Required loss weight
-	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight)
+	// Required loss weight (pinned losses already cover part of it)
+	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight) - pinned.lossWeight(payouts)
 	if requiredLossWeight < float64(o.config.MinWeight) {
 		requiredLossWeight = float64(o.config.MinWeight)
 	}
@@ -1159,7 +1216,7 @@ func (o *BucketOptimizer) calculateWeightsWithVoiding(payouts []float64, assignm
 }
 
 // fineTuneLossWeightWithVoiding adjusts loss weight while respecting voided outcomes
-func (o *BucketOptimizer) fineTuneLossWeightWithVoiding(weights []uint64, payouts []float64, lossIndices []int, voidedOutcomeIndices []int) []uint64 {
+func (o *BucketOptimizer) fineTuneLossWeightWithVoiding(weights []uint64, payouts []float64, lossIndices []int, voidedOutcomeIndices []int, pinned pinnedWeights) []uint64 {
 	result := make([]uint64, len(weights))
 	copy(result, weights)
 
@@ -1180,7 +1237,7 @@ func (o *BucketOptimizer) fineTuneLossWeightWithVoiding(weights []uint64, payout
 	}
 
 	// Required loss weight for target RTP
-	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight)
+	requiredLossWeight := weightedPayoutSum/o.config.TargetRTP - float64(totalWinWeight) - pinned.lossWeight(payouts)
 	if requiredLossWeight < float64(len(lossIndices)) {
 		requiredLossWeight = float64(len(lossIndices))
 	}
@@ -1199,7 +1256,7 @@ func (o *BucketOptimizer) fineTuneLossWeightWithVoiding(weights []uint64, payout
 }
 
 // buildOutcomeDetailsWithVoiding creates detailed info including voided outcomes
-func (o *BucketOptimizer) buildOutcomeDetailsWithVoiding(table *stakergs.LookupTable, payouts []float64, oldWeights, newWeights []uint64, assignments []bucketAssignment, lossIndices []int, voidedOutcomeIndices []int) []OutcomeDetail {
+func (o *BucketOptimizer) buildOutcomeDetailsWithVoiding(table *stakergs.LookupTable, payouts []float64, oldWeights, newWeights []uint64, assignments []bucketAssignment, lossIndices []int, voidedOutcomeIndices []int, pinned pinnedWeights, maxWin *globalMaxWin) []OutcomeDetail {
 	totalWeight := sumUint64(newWeights)
 	details := make([]OutcomeDetail, len(payouts))
 
@@ -1223,6 +1280,14 @@ func (o *BucketOptimizer) buildOutcomeDetailsWithVoiding(table *stakergs.LookupT
 	for _, idx := range lossIndices {
 		bucketNames[idx] = "loss"
 	}
+	for idx := range pinned {
+		bucketNames[idx] = "pinned"
+	}
+	if maxWin != nil {
+		for _, idx := range maxWin.indices {
+			bucketNames[idx] = "max_win"
+		}
+	}
 
 	for i := range payouts {
 		prob := 0.0
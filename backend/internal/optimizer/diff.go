@@ -0,0 +1,194 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"stakergs"
+)
+
+// WeightSetStats are the headline metrics of one weight vector. Payouts are
+// normalized by the mode cost.
+type WeightSetStats struct {
+	TotalWeight uint64  `json:"total_weight"`
+	RTP         float64 `json:"rtp"`
+	HitRate     float64 `json:"hit_rate"`
+	Volatility  float64 `json:"volatility"` // std dev of the spin payout in cost units
+	// MaxWinFrequency is 1 in N spins for the highest payout, 0 if it cannot hit
+	MaxWinFrequency float64 `json:"max_win_frequency"`
+}
+
+// OutcomeDiff is how one outcome changes between two weight vectors.
+type OutcomeDiff struct {
+	SimID            int     `json:"sim_id"`
+	Payout           float64 `json:"payout"`
+	OldWeight        uint64  `json:"old_weight"`
+	NewWeight        uint64  `json:"new_weight"`
+	WeightDelta      int64   `json:"weight_delta"`
+	OldProbability   float64 `json:"old_probability"`
+	NewProbability   float64 `json:"new_probability"`
+	ProbabilityDelta float64 `json:"probability_delta"`
+	RTPDelta         float64 `json:"rtp_delta"` // change of the outcome's RTP contribution
+}
+
+// BucketDiff is how the probability and RTP share of a payout range change.
+type BucketDiff struct {
+	Name                 string  `json:"name"`
+	MinPayout            float64 `json:"min_payout"`
+	MaxPayout            float64 `json:"max_payout"`
+	OutcomeCount         int     `json:"outcome_count"`
+	OldProbability       float64 `json:"old_probability"`
+	NewProbability       float64 `json:"new_probability"`
+	ProbabilityDelta     float64 `json:"probability_delta"`
+	OldRTPContribution   float64 `json:"old_rtp_contribution"` // % of RTP
+	NewRTPContribution   float64 `json:"new_rtp_contribution"`
+	RTPContributionDelta float64 `json:"rtp_contribution_delta"`
+}
+
+// WeightDiff compares an old weight vector of a table with a new one.
+// Deltas are new minus old.
+type WeightDiff struct {
+	Old             WeightSetStats `json:"old"`
+	New             WeightSetStats `json:"new"`
+	RTPDelta        float64        `json:"rtp_delta"`
+	HitRateDelta    float64        `json:"hit_rate_delta"`
+	VolatilityDelta float64        `json:"volatility_delta"`
+	// ChangedOutcomes counts the outcomes whose probability moved
+	ChangedOutcomes int `json:"changed_outcomes"`
+	// Outcomes lists the changed outcomes, largest probability shift first,
+	// up to the requested limit
+	Outcomes []OutcomeDiff `json:"outcomes"`
+	Buckets  []BucketDiff  `json:"buckets"`
+	Loss     BucketDiff    `json:"loss"`
+}
+
+// DiffWeights compares two weight vectors of a table, grouping payouts into
+// buckets for the redistribution (SuggestBuckets when none are given).
+// limit caps the listed outcomes; 0 lists them all.
+func DiffWeights(table *stakergs.LookupTable, oldWeights, newWeights []uint64, buckets []BucketConfig, limit int) (*WeightDiff, error) {
+	n := len(table.Outcomes)
+	if len(oldWeights) != n || len(newWeights) != n {
+		return nil, fmt.Errorf("weight count mismatch: table has %d outcomes, got %d and %d weights", n, len(oldWeights), len(newWeights))
+	}
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+	payouts := make([]float64, n)
+	for i, outcome := range table.Outcomes {
+		payouts[i] = float64(outcome.Payout) / 100.0 / cost
+	}
+
+	diff := &WeightDiff{
+		Old: weightSetStats(oldWeights, payouts),
+		New: weightSetStats(newWeights, payouts),
+	}
+	diff.RTPDelta = diff.New.RTP - diff.Old.RTP
+	diff.HitRateDelta = diff.New.HitRate - diff.Old.HitRate
+	diff.VolatilityDelta = diff.New.Volatility - diff.Old.Volatility
+
+	oldProbs := probabilities(oldWeights, diff.Old.TotalWeight)
+	newProbs := probabilities(newWeights, diff.New.TotalWeight)
+
+	outcomes := make([]OutcomeDiff, 0)
+	for i, outcome := range table.Outcomes {
+		shift := newProbs[i] - oldProbs[i]
+		if shift == 0 && oldWeights[i] == newWeights[i] {
+			continue
+		}
+		outcomes = append(outcomes, OutcomeDiff{
+			SimID:            outcome.SimID,
+			Payout:           payouts[i],
+			OldWeight:        oldWeights[i],
+			NewWeight:        newWeights[i],
+			WeightDelta:      int64(newWeights[i]) - int64(oldWeights[i]),
+			OldProbability:   oldProbs[i],
+			NewProbability:   newProbs[i],
+			ProbabilityDelta: shift,
+			RTPDelta:         payouts[i] * shift,
+		})
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool {
+		return math.Abs(outcomes[i].ProbabilityDelta) > math.Abs(outcomes[j].ProbabilityDelta)
+	})
+	diff.ChangedOutcomes = len(outcomes)
+	if limit > 0 && len(outcomes) > limit {
+		outcomes = outcomes[:limit]
+	}
+	diff.Outcomes = outcomes
+
+	if len(buckets) == 0 {
+		buckets = SuggestBuckets(table, diff.New.RTP)
+	}
+	assignments, lossIndices, _ := NewBucketOptimizer(&BucketOptimizerConfig{Buckets: buckets}).assignOutcomesToBuckets(payouts)
+	diff.Buckets = make([]BucketDiff, len(assignments))
+	for i, a := range assignments {
+		diff.Buckets[i] = bucketDiff(a.config.Name, a.config.MinPayout, a.config.MaxPayout, a.outcomeIndices, payouts, oldProbs, newProbs, diff.Old.RTP, diff.New.RTP)
+	}
+	diff.Loss = bucketDiff("loss", 0, 0, lossIndices, payouts, oldProbs, newProbs, diff.Old.RTP, diff.New.RTP)
+	return diff, nil
+}
+
+// weightSetStats computes the metrics of a weight vector.
+func weightSetStats(weights []uint64, payouts []float64) WeightSetStats {
+	stats := WeightSetStats{TotalWeight: sumUint64(weights)}
+	if stats.TotalWeight == 0 {
+		return stats
+	}
+	total := float64(stats.TotalWeight)
+	var s1, s2, hit float64
+	maxPayout, maxWeight := 0.0, uint64(0)
+	for i, w := range weights {
+		p := float64(w) / total
+		s1 += payouts[i] * p
+		s2 += payouts[i] * payouts[i] * p
+		if payouts[i] > 0 {
+			hit += p
+		}
+		switch {
+		case payouts[i] > maxPayout:
+			maxPayout, maxWeight = payouts[i], w
+		case payouts[i] == maxPayout:
+			maxWeight += w
+		}
+	}
+	stats.RTP = s1
+	stats.HitRate = hit
+	stats.Volatility = math.Sqrt(math.Max(s2-s1*s1, 0))
+	if maxPayout > 0 && maxWeight > 0 {
+		stats.MaxWinFrequency = total / float64(maxWeight)
+	}
+	return stats
+}
+
+func probabilities(weights []uint64, total uint64) []float64 {
+	probs := make([]float64, len(weights))
+	if total == 0 {
+		return probs
+	}
+	for i, w := range weights {
+		probs[i] = float64(w) / float64(total)
+	}
+	return probs
+}
+
+func bucketDiff(name string, minPayout, maxPayout float64, indices []int, payouts, oldProbs, newProbs []float64, oldRTP, newRTP float64) BucketDiff {
+	d := BucketDiff{Name: name, MinPayout: minPayout, MaxPayout: maxPayout, OutcomeCount: len(indices)}
+	var oldContribution, newContribution float64
+	for _, i := range indices {
+		d.OldProbability += oldProbs[i]
+		d.NewProbability += newProbs[i]
+		oldContribution += payouts[i] * oldProbs[i]
+		newContribution += payouts[i] * newProbs[i]
+	}
+	d.ProbabilityDelta = d.NewProbability - d.OldProbability
+	if oldRTP > 0 {
+		d.OldRTPContribution = oldContribution / oldRTP * 100
+	}
+	if newRTP > 0 {
+		d.NewRTPContribution = newContribution / newRTP * 100
+	}
+	d.RTPContributionDelta = d.NewRTPContribution - d.OldRTPContribution
+	return d
+}
@@ -0,0 +1,81 @@
+package optimizer
+
+import (
+	"math"
+	"testing"
+
+	"stakergs"
+)
+
+// ============================================================================
+// Weight Diff Tests
+// ============================================================================
+
+func TestDiffWeights(t *testing.T) {
+	table := &stakergs.LookupTable{
+		Mode: "test",
+		Cost: 1.0,
+		Outcomes: []stakergs.Outcome{
+			{SimID: 0, Payout: 0},    // loss
+			{SimID: 1, Payout: 50},   // 0.5x
+			{SimID: 2, Payout: 200},  // 2x
+			{SimID: 3, Payout: 1000}, // 10x
+		},
+	}
+	oldWeights := []uint64{600, 300, 90, 10}
+	newWeights := []uint64{500, 300, 180, 20} // same total, more 2x and 10x
+	buckets := []BucketConfig{
+		{Name: "small", MinPayout: 0, MaxPayout: 5},
+		{Name: "big", MinPayout: 5, MaxPayout: 100},
+	}
+
+	diff, err := DiffWeights(table, oldWeights, newWeights, buckets, 0)
+	if err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+
+	if math.Abs(diff.Old.RTP-0.43) > 1e-9 || math.Abs(diff.New.RTP-0.71) > 1e-9 {
+		t.Errorf("expected RTP 0.43 -> 0.71, got %v -> %v", diff.Old.RTP, diff.New.RTP)
+	}
+	if math.Abs(diff.RTPDelta-0.28) > 1e-9 || math.Abs(diff.HitRateDelta-0.1) > 1e-9 {
+		t.Errorf("unexpected deltas: rtp %v, hit rate %v", diff.RTPDelta, diff.HitRateDelta)
+	}
+	if diff.Old.MaxWinFrequency != 100 || diff.New.MaxWinFrequency != 50 {
+		t.Errorf("expected max win 1 in 100 -> 1 in 50, got %v -> %v", diff.Old.MaxWinFrequency, diff.New.MaxWinFrequency)
+	}
+	if diff.VolatilityDelta <= 0 {
+		t.Errorf("expected volatility to grow, got delta %v", diff.VolatilityDelta)
+	}
+
+	// simID 1 keeps its weight and probability
+	if diff.ChangedOutcomes != 3 || len(diff.Outcomes) != 3 {
+		t.Fatalf("expected 3 changed outcomes, got %d (%d listed)", diff.ChangedOutcomes, len(diff.Outcomes))
+	}
+	if first := diff.Outcomes[0]; first.SimID != 0 || first.WeightDelta != -100 {
+		t.Errorf("expected the largest shift (simID 0) first, got %+v", first)
+	}
+	for _, o := range diff.Outcomes {
+		if o.SimID == 3 && (o.WeightDelta != 10 || math.Abs(o.RTPDelta-0.1) > 1e-9) {
+			t.Errorf("unexpected diff for simID 3: %+v", o)
+		}
+	}
+
+	if len(diff.Buckets) != 2 || diff.Buckets[0].OutcomeCount != 2 || diff.Buckets[1].OutcomeCount != 1 {
+		t.Fatalf("unexpected buckets: %+v", diff.Buckets)
+	}
+	if math.Abs(diff.Loss.ProbabilityDelta+0.1) > 1e-9 {
+		t.Errorf("expected loss probability -0.1, got %v", diff.Loss.ProbabilityDelta)
+	}
+	if math.Abs(diff.Buckets[1].ProbabilityDelta-0.01) > 1e-9 {
+		t.Errorf("expected big bucket +0.01, got %v", diff.Buckets[1].ProbabilityDelta)
+	}
+
+	limited, _ := DiffWeights(table, oldWeights, newWeights, buckets, 1)
+	if limited.ChangedOutcomes != 3 || len(limited.Outcomes) != 1 {
+		t.Errorf("expected 1 of 3 outcomes listed, got %d of %d", len(limited.Outcomes), limited.ChangedOutcomes)
+	}
+
+	if _, err := DiffWeights(table, oldWeights, newWeights[:3], buckets, 0); err == nil {
+		t.Error("expected error for weight count mismatch")
+	}
+}
@@ -2,7 +2,9 @@ package optimizer
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -13,16 +15,36 @@ import (
 
 	"lutexplorer/internal/common"
 	"lutexplorer/internal/lut"
+	"lutexplorer/internal/numfmt"
+	"lutexplorer/internal/openapi"
 	"lutexplorer/internal/ws"
 
 	"github.com/gorilla/websocket"
+
+	"stakergs"
 )
 
 // Handlers provides HTTP handlers for the optimizer API
 type Handlers struct {
-	loader   *lut.Loader
-	wsHub    *ws.Hub
-	analyzer *ModeAnalyzer
+	loader    *lut.Loader
+	wsHub     *ws.Hub
+	analyzer  *ModeAnalyzer
+	recordRun RunRecorder
+}
+
+// RunRecorder stores a finished optimizer run, e.g. in the server's data
+// directory database. kind names the optimizer ("bucket", ...).
+type RunRecorder func(mode, kind string, request, result interface{})
+
+// SetRunRecorder sets the function finished runs are passed to.
+func (h *Handlers) SetRunRecorder(fn RunRecorder) {
+	h.recordRun = fn
+}
+
+func (h *Handlers) record(mode, kind string, request, result interface{}) {
+	if h.recordRun != nil {
+		h.recordRun(mode, kind, request, result)
+	}
 }
 
 // NewHandlers creates new optimizer HTTP handlers
@@ -55,6 +77,11 @@ func (h *Handlers) HandleApply(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Weights      []uint64 `json:"weights"`
 		CreateBackup bool     `json:"create_backup"`
+		// ValidateCompliance checks the weights first and refuses to save
+		// them if a check fails; DryRun only checks them
+		ValidateCompliance bool   `json:"validate_compliance,omitempty"`
+		ComplianceProfile  string `json:"compliance_profile,omitempty"`
+		DryRun             bool   `json:"dry_run,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.WriteError(w, http.StatusBadRequest, "invalid request body")
@@ -66,6 +93,31 @@ func (h *Handlers) HandleApply(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ValidateCompliance || req.DryRun {
+		table, err := h.loader.GetMode(mode)
+		if err != nil {
+			common.WriteError(w, http.StatusNotFound, fmt.Sprintf("mode not found: %s", mode))
+			return
+		}
+		compliance, status, err := checkCandidateCompliance(r, table, req.Weights, req.ComplianceProfile)
+		if err != nil {
+			common.WriteError(w, status, err.Error())
+			return
+		}
+		if req.DryRun || !compliance.Passed {
+			message := "Dry run: weights not applied"
+			if !compliance.Passed {
+				message = "Weights not applied: compliance checks failed"
+			}
+			common.WriteSuccess(w, map[string]interface{}{
+				"saved":      false,
+				"message":    message,
+				"compliance": compliance,
+			})
+			return
+		}
+	}
+
 	var backupPath string
 	var err error
 
@@ -181,12 +233,7 @@ func (h *Handlers) HandleRestore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	backupPath := req.BackupFile
-	if !filepath.IsAbs(backupPath) {
-		backupPath = filepath.Join(h.loader.BaseDir(), req.BackupFile)
-	}
-
-	backupData, err := os.ReadFile(backupPath)
+	backupData, err := os.ReadFile(h.backupPath(req.BackupFile))
 	if err != nil {
 		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("backup file not found: %s", err.Error()))
 		return
@@ -224,6 +271,196 @@ func (h *Handlers) HandleRestore(w http.ResponseWriter, r *http.Request) {
 	common.WriteSuccess(w, response)
 }
 
+// HandleDiff compares a backup's weights with the current weights, or with
+// another weight file, before applying or restoring them
+// GET /api/optimizer/{mode}/diff?backup=<file>[&against=<file>][&limit=N]
+func (h *Handlers) HandleDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.WriteError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+
+	mode := extractMode(r.URL.Path, "diff")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode required")
+		return
+	}
+
+	query := r.URL.Query()
+	backupFile := query.Get("backup")
+	if backupFile == "" {
+		common.WriteError(w, http.StatusBadRequest, "backup required")
+		return
+	}
+	limit := 100
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			common.WriteError(w, http.StatusBadRequest, "limit must be a non-negative integer (0 = all)")
+			return
+		}
+		limit = parsed
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("mode not found: %s", mode))
+		return
+	}
+
+	oldWeights, err := h.readWeightFile(backupFile)
+	if err != nil {
+		common.WriteError(w, weightFileStatus(err), err.Error())
+		return
+	}
+
+	newSource := "current"
+	newWeights := make([]uint64, len(table.Outcomes))
+	for i, outcome := range table.Outcomes {
+		newWeights[i] = outcome.Weight
+	}
+	if against := query.Get("against"); against != "" {
+		if newWeights, err = h.readWeightFile(against); err != nil {
+			common.WriteError(w, weightFileStatus(err), err.Error())
+			return
+		}
+		newSource = against
+	}
+
+	diff, err := DiffWeights(table, oldWeights, newWeights, nil, limit)
+	if err != nil {
+		common.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	common.WriteSuccess(w, map[string]interface{}{
+		"mode":       mode,
+		"old_source": backupFile,
+		"new_source": newSource,
+		"diff":       diff,
+	})
+}
+
+// checkCandidateCompliance runs the compliance checks on a table with
+// candidate weights, without saving them. profile is a preset name or a
+// custom profile as JSON, as for the compliance endpoints. On error it
+// returns the HTTP status to answer with.
+func checkCandidateCompliance(r *http.Request, table *stakergs.LookupTable, weights []uint64, profile string) (*lut.ComplianceResult, int, error) {
+	candidate, err := lut.WithWeights(table, weights)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	p, err := lut.ParseComplianceProfile(profile)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	checker := lut.NewComplianceCheckerWithProfile(p)
+	checker.SetLocale(numfmt.FromAcceptLanguage(r.Header.Get("Accept-Language")))
+	return checker.CheckMode(candidate), http.StatusOK, nil
+}
+
+// backupPath resolves a backup file name against the library directory.
+func (h *Handlers) backupPath(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(h.loader.BaseDir(), name)
+}
+
+// readWeightFile reads the weights of a LUT or backup file.
+func (h *Handlers) readWeightFile(name string) ([]uint64, error) {
+	data, err := os.ReadFile(h.backupPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("weight file not found: %w", err)
+	}
+	weights, err := parseWeightsFromCSV(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", name, err.Error())
+	}
+	return weights, nil
+}
+
+// weightFileStatus is the HTTP status of a readWeightFile error.
+func weightFileStatus(err error) int {
+	if errors.Is(err, fs.ErrNotExist) {
+		return http.StatusNotFound
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// ============================================================================
+// Normalize Endpoint
+// ============================================================================
+
+// NormalizeRequest is the API request for weight normalization
+type NormalizeRequest struct {
+	NormalizeOptions
+	SaveToFile   bool `json:"save_to_file"`  // Save normalized weights to LUT file
+	CreateBackup bool `json:"create_backup"` // Create backup before saving
+}
+
+// HandleNormalize rewrites a mode's current weights as smaller integers
+// without moving the RTP by more than the tolerance
+// POST /api/optimizer/{mode}/normalize
+func (h *Handlers) HandleNormalize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.WriteError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	mode := extractMode(r.URL.Path, "normalize")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode required")
+		return
+	}
+
+	var req NormalizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err.Error()))
+		return
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("mode not found: %s", mode))
+		return
+	}
+
+	weights := make([]uint64, len(table.Outcomes))
+	for i, outcome := range table.Outcomes {
+		weights[i] = outcome.Weight
+	}
+	normalized, err := NormalizeWeights(weights, tablePayouts(table), req.NormalizeOptions)
+	if err != nil {
+		common.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"normalize": normalized,
+		"saved":     false,
+	}
+	if !req.SaveToFile {
+		response["weights"] = normalized.Weights
+		common.WriteSuccess(w, response)
+		return
+	}
+
+	if req.CreateBackup {
+		backupPath, err := h.loader.SaveWeightsWithBackup(mode, normalized.Weights)
+		if err != nil {
+			common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
+			return
+		}
+		response["backup_path"] = backupPath
+	} else if err := h.loader.SaveWeights(mode, normalized.Weights); err != nil {
+		common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
+		return
+	}
+	response["saved"] = true
+	common.WriteSuccess(w, response)
+}
+
 // ============================================================================
 // Utilities
 // ============================================================================
@@ -292,18 +529,22 @@ func getModeNote(cost float64) string {
 
 // BucketOptimizeRequest is the API request for bucket-based optimization
 type BucketOptimizeRequest struct {
-	TargetRTP           float64          `json:"target_rtp"`                      // Target RTP (e.g., 0.97)
-	RTPTolerance        float64          `json:"rtp_tolerance"`                   // Tolerance (e.g., 0.001)
-	Buckets             []BucketConfig   `json:"buckets"`                         // Payout range configurations
-	SaveToFile          bool             `json:"save_to_file"`                    // Save optimized weights to LUT file
-	CreateBackup        bool             `json:"create_backup"`                   // Create backup before saving
-	EnableBruteForce    bool             `json:"enable_brute_force,omitempty"`    // Enable iterative brute force search
-	MaxIterations       int              `json:"max_iterations,omitempty"`        // Max iterations for brute force
-	OptimizationMode    OptimizationMode `json:"optimization_mode,omitempty"`     // "fast"/"balanced"/"precise"
-	GlobalMaxWinFreq    float64          `json:"global_max_win_freq,omitempty"`   // Global max win frequency (1 in N)
-	EnableVoiding       bool             `json:"enable_voiding,omitempty"`        // DEPRECATED: Enable bucket voiding
-	VoidedBucketIndices []int            `json:"voided_bucket_indices,omitempty"` // DEPRECATED: Indices of buckets to void
-	EnableAutoVoiding   bool             `json:"enable_auto_voiding,omitempty"`   // Enable automatic outcome voiding to reach target RTP
+	TargetRTP           float64           `json:"target_rtp"`                      // Target RTP (e.g., 0.97)
+	RTPTolerance        float64           `json:"rtp_tolerance"`                   // Tolerance (e.g., 0.001)
+	Buckets             []BucketConfig    `json:"buckets"`                         // Payout range configurations
+	SaveToFile          bool              `json:"save_to_file"`                    // Save optimized weights to LUT file
+	CreateBackup        bool              `json:"create_backup"`                   // Create backup before saving
+	EnableBruteForce    bool              `json:"enable_brute_force,omitempty"`    // Enable iterative brute force search
+	MaxIterations       int               `json:"max_iterations,omitempty"`        // Max iterations for brute force
+	OptimizationMode    OptimizationMode  `json:"optimization_mode,omitempty"`     // "fast"/"balanced"/"precise"
+	GlobalMaxWinFreq    float64           `json:"global_max_win_freq,omitempty"`   // Global max win frequency (1 in N)
+	EnableVoiding       bool              `json:"enable_voiding,omitempty"`        // DEPRECATED: Enable bucket voiding
+	VoidedBucketIndices []int             `json:"voided_bucket_indices,omitempty"` // DEPRECATED: Indices of buckets to void
+	EnableAutoVoiding   bool              `json:"enable_auto_voiding,omitempty"`   // Enable automatic outcome voiding to reach target RTP
+	ValidateCompliance  bool              `json:"validate_compliance,omitempty"`   // Check the result's compliance; failing weights are not saved
+	ComplianceProfile   string            `json:"compliance_profile,omitempty"`    // Profile name or JSON (default profile if empty)
+	PinnedOutcomes      []PinnedOutcome   `json:"pinned_outcomes,omitempty"`       // Outcomes kept at a fixed weight (still counted in RTP)
+	Normalize           *NormalizeOptions `json:"normalize,omitempty"`             // Rewrite the result as smaller integer weights
 }
 
 // HandleBucketOptimize runs bucket-based optimization on a mode
@@ -343,6 +584,11 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if req.GlobalMaxWinFreq < 0 {
+		common.WriteError(w, http.StatusBadRequest, "global_max_win_freq cannot be negative")
+		return
+	}
+
 	// Load table
 	table, err := h.loader.GetMode(mode)
 	if err != nil {
@@ -350,6 +596,12 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	pinned, err := resolvePinnedOutcomes(table, req.PinnedOutcomes)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid pinned outcomes: %s", err.Error()))
+		return
+	}
+
 	// If no buckets provided, suggest them based on table
 	buckets := req.Buckets
 	if len(buckets) == 0 {
@@ -369,6 +621,7 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		EnableVoiding:       req.EnableVoiding,
 		VoidedBucketIndices: req.VoidedBucketIndices,
 		EnableAutoVoiding:   req.EnableAutoVoiding,
+		PinnedOutcomes:      req.PinnedOutcomes,
 	}
 
 	var result *BucketOptimizerResult
@@ -398,9 +651,44 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	// Normalize the weights before they are checked and saved
+	var normalized *NormalizeResult
+	if req.Normalize != nil && result.NewWeights != nil {
+		payouts := tablePayouts(table)
+		normalized, err = normalizeWeights(result.NewWeights, payouts, pinned, *req.Normalize)
+		if err != nil {
+			common.WriteError(w, http.StatusUnprocessableEntity, fmt.Sprintf("normalize failed: %s", err.Error()))
+			return
+		}
+		result.applyNormalized(normalized)
+		if len(pinned) > 0 {
+			normalized.PinnedOutcomes = pinned.outcomes(table, normalized.Weights)
+			result.PinnedRTP = pinned.contribution(normalized.Weights, payouts)
+			if normalized.Scale != 1 || normalized.GCD > 1 {
+				result.Warnings = append(result.Warnings, "Pinned weights were normalized with the table; see normalize.pinned_outcomes")
+			}
+		}
+	}
+
+	// Check the candidate weights before anything is written
+	var compliance *lut.ComplianceResult
+	if req.ValidateCompliance && result.NewWeights != nil {
+		var status int
+		compliance, status, err = checkCandidateCompliance(r, table, result.NewWeights, req.ComplianceProfile)
+		if err != nil {
+			common.WriteError(w, status, err.Error())
+			return
+		}
+	}
+
 	// Save if requested
 	var saveInfo map[string]interface{}
-	if req.SaveToFile && result.NewWeights != nil {
+	if req.SaveToFile && result.NewWeights != nil && compliance != nil && !compliance.Passed {
+		saveInfo = map[string]interface{}{
+			"saved":  false,
+			"reason": "compliance checks failed",
+		}
+	} else if req.SaveToFile && result.NewWeights != nil {
 		if req.CreateBackup {
 			backupPath, err := h.loader.SaveWeightsWithBackup(mode, result.NewWeights)
 			if err != nil {
@@ -454,11 +742,12 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 			"max_payout":    maxPayout,
 		},
 		"config": map[string]interface{}{
-			"target_rtp":         req.TargetRTP,
-			"buckets":            buckets,
-			"enable_brute_force": req.EnableBruteForce,
-			"optimization_mode":  req.OptimizationMode,
-			"enable_voiding":     req.EnableVoiding,
+			"target_rtp":          req.TargetRTP,
+			"buckets":             buckets,
+			"enable_brute_force":  req.EnableBruteForce,
+			"optimization_mode":   req.OptimizationMode,
+			"enable_voiding":      req.EnableVoiding,
+			"global_max_win_freq": req.GlobalMaxWinFreq,
 		},
 	}
 
@@ -467,6 +756,16 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		response["voided_buckets"] = result.VoidedBuckets
 	}
 
+	if len(req.PinnedOutcomes) > 0 {
+		response["pinned_rtp"] = result.PinnedRTP
+	}
+	if req.GlobalMaxWinFreq > 0 {
+		response["max_win_frequency"] = result.MaxWinFrequency
+	}
+	if normalized != nil {
+		response["normalize"] = normalized
+	}
+
 	// Add brute force specific info if used
 	if bruteForceResult != nil {
 		response["brute_force_info"] = map[string]interface{}{
@@ -476,10 +775,176 @@ func (h *Handlers) HandleBucketOptimize(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if compliance != nil {
+		response["compliance"] = compliance
+	}
+
 	if saveInfo != nil {
 		response["save_result"] = saveInfo
 	}
 
+	h.record(mode, "bucket", req, response)
+	common.WriteSuccess(w, response)
+}
+
+// FitDistributionRequest is the API request for fitting a LUT to a target
+// payout band distribution, given either as CSV text or as bands.
+type FitDistributionRequest struct {
+	CSV          string       `json:"csv,omitempty"`   // see ParseTargetDistributionCSV
+	Bands        []TargetBand `json:"bands,omitempty"` // used when csv is empty
+	SaveToFile   bool         `json:"save_to_file"`
+	CreateBackup bool         `json:"create_backup"`
+}
+
+// HandleFitDistribution fits a mode's weights to an empirical target distribution
+// POST /api/optimizer/{mode}/fit-distribution
+func (h *Handlers) HandleFitDistribution(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.WriteError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	mode := extractMode(r.URL.Path, "fit-distribution")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode required")
+		return
+	}
+
+	var req FitDistributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err.Error()))
+		return
+	}
+
+	bands := req.Bands
+	if req.CSV != "" {
+		var err error
+		bands, err = ParseTargetDistributionCSV([]byte(req.CSV))
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid target CSV: %s", err.Error()))
+			return
+		}
+	}
+	if err := ValidateTargetBands(bands); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid bands: %s", err.Error()))
+		return
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("mode not found: %s", mode))
+		return
+	}
+
+	result, err := FitTargetDistribution(table, bands)
+	if err != nil {
+		common.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"result":    result,
+		"mode_info": map[string]interface{}{"cost": table.Cost, "note": getModeNote(table.Cost)},
+	}
+
+	if req.SaveToFile {
+		if req.CreateBackup {
+			backupPath, err := h.loader.SaveWeightsWithBackup(mode, result.NewWeights)
+			if err != nil {
+				common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
+				return
+			}
+			response["save_result"] = map[string]interface{}{
+				"saved":       true,
+				"backup_path": backupPath,
+			}
+		} else {
+			if err := h.loader.SaveWeights(mode, result.NewWeights); err != nil {
+				common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
+				return
+			}
+			response["save_result"] = map[string]interface{}{"saved": true}
+		}
+	}
+
+	h.record(mode, "fit-distribution", req, response)
+	common.WriteSuccess(w, response)
+}
+
+// MultiObjectiveRequest is the API request for multi-objective optimization
+type MultiObjectiveRequest struct {
+	MultiObjectiveConfig
+	SaveToFile   bool `json:"save_to_file"` // save the best candidate's weights
+	CreateBackup bool `json:"create_backup"`
+}
+
+// HandleMultiObjective searches for weights balancing RTP, hit rate and volatility
+// POST /api/optimizer/{mode}/multi-objective
+func (h *Handlers) HandleMultiObjective(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.WriteError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	mode := extractMode(r.URL.Path, "multi-objective")
+	if mode == "" {
+		common.WriteError(w, http.StatusBadRequest, "mode required")
+		return
+	}
+
+	var req MultiObjectiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err.Error()))
+		return
+	}
+	if err := ValidateMultiObjectiveConfig(&req.MultiObjectiveConfig); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid config: %s", err.Error()))
+		return
+	}
+
+	table, err := h.loader.GetMode(mode)
+	if err != nil {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("mode not found: %s", mode))
+		return
+	}
+
+	config := req.MultiObjectiveConfig
+	result, err := NewMultiObjectiveOptimizer(&config).OptimizeTable(table)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"result": result,
+		"config": config,
+		"mode_info": map[string]interface{}{
+			"cost": table.Cost,
+			"note": getModeNote(table.Cost),
+		},
+	}
+
+	if req.SaveToFile && result.BestWeights != nil {
+		if req.CreateBackup {
+			backupPath, err := h.loader.SaveWeightsWithBackup(mode, result.BestWeights)
+			if err != nil {
+				common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
+				return
+			}
+			response["save_result"] = map[string]interface{}{
+				"saved":       true,
+				"backup_path": backupPath,
+			}
+		} else {
+			if err := h.loader.SaveWeights(mode, result.BestWeights); err != nil {
+				common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("save failed: %s", err.Error()))
+				return
+			}
+			response["save_result"] = map[string]interface{}{"saved": true}
+		}
+	}
+
+	h.record(mode, "multi-objective", req, response)
 	common.WriteSuccess(w, response)
 }
 
@@ -953,6 +1418,7 @@ func (h *Handlers) HandleBruteForceOptimizeWS(w http.ResponseWriter, r *http.Req
 		GlobalMaxWinFreq:    req.GlobalMaxWinFreq,
 		EnableVoiding:       req.EnableVoiding,
 		VoidedBucketIndices: req.VoidedBucketIndices,
+		PinnedOutcomes:      req.PinnedOutcomes,
 	}
 
 	// Validate config
@@ -1150,6 +1616,10 @@ func (h *Handlers) RegisterRoutes(mux *http.ServeMux) {
 			h.HandleBackups(w, r)
 		case strings.HasSuffix(path, "/restore"):
 			h.HandleRestore(w, r)
+		case strings.HasSuffix(path, "/diff"):
+			h.HandleDiff(w, r)
+		case strings.HasSuffix(path, "/normalize"):
+			h.HandleNormalize(w, r)
 
 		// Mode analysis endpoint
 		case strings.HasSuffix(path, "/analyze"):
@@ -1162,6 +1632,10 @@ func (h *Handlers) RegisterRoutes(mux *http.ServeMux) {
 			h.HandleBruteForceOptimizeWS(w, r)
 		case strings.HasSuffix(path, "/suggest-buckets"):
 			h.HandleSuggestBuckets(w, r)
+		case strings.HasSuffix(path, "/fit-distribution"):
+			h.HandleFitDistribution(w, r)
+		case strings.HasSuffix(path, "/multi-objective"):
+			h.HandleMultiObjective(w, r)
 		case path == "/api/optimizer/bucket-presets":
 			h.HandleBucketPresets(w, r)
 
@@ -1180,3 +1654,26 @@ func (h *Handlers) RegisterRoutes(mux *http.ServeMux) {
 		}
 	})
 }
+
+// Routes documents the endpoints RegisterRoutes dispatches, for the OpenAPI
+// document (the subtree handler hides them from the mux).
+func (h *Handlers) Routes() []openapi.Route {
+	return []openapi.Route{
+		{Method: "POST", Path: "/api/optimizer/{mode}/apply", Summary: "Apply weights to the LUT file"},
+		{Method: "GET", Path: "/api/optimizer/{mode}/backups", Summary: "List weight backups of a mode"},
+		{Method: "POST", Path: "/api/optimizer/{mode}/restore", Summary: "Restore weights from a backup file"},
+		{Method: "GET", Path: "/api/optimizer/{mode}/diff", Summary: "Compare a backup's weights with the current ones", Query: []string{"backup", "against", "limit"}, Response: WeightDiff{}},
+		{Method: "POST", Path: "/api/optimizer/{mode}/normalize", Summary: "Scale weights to a target total or reduce them by their GCD", Request: NormalizeRequest{}},
+		{Method: "GET", Path: "/api/optimizer/{mode}/analyze", Summary: "Analyze a mode's RTP boundaries", Query: []string{"target_rtp"}, Response: ModeAnalysis{}},
+		{Method: "POST", Path: "/api/optimizer/{mode}/bucket-optimize", Summary: "Run bucket-based optimization", Request: BucketOptimizeRequest{}},
+		{Method: "GET", Path: "/api/optimizer/{mode}/optimize-stream", Summary: "Brute force optimization with progress (WebSocket)"},
+		{Method: "GET", Path: "/api/optimizer/{mode}/suggest-buckets", Summary: "Suggest a bucket configuration"},
+		{Method: "POST", Path: "/api/optimizer/{mode}/fit-distribution", Summary: "Fit weights to a target distribution", Request: FitDistributionRequest{}},
+		{Method: "POST", Path: "/api/optimizer/{mode}/multi-objective", Summary: "Balance RTP, hit rate and volatility", Request: MultiObjectiveRequest{}},
+		{Method: "GET", Path: "/api/optimizer/{mode}/generate-configs", Summary: "Generate bucket configs from the mode's max payout", Query: []string{"target_rtp"}},
+		{Method: "GET", Path: "/api/optimizer/bucket-presets", Summary: "List bucket presets"},
+		{Method: "GET", Path: "/api/optimizer/generate-configs", Summary: "Generate bucket configs for all profiles", Query: []string{"target_rtp", "max_win"}},
+		{Method: "POST", Path: "/api/optimizer/generate-config", Summary: "Generate a bucket config for a profile", Request: GenerateConfigRequest{}, Response: GeneratedConfig{}},
+		{Method: "GET", Path: "/api/optimizer/profiles", Summary: "List player profiles"},
+	}
+}
@@ -0,0 +1,122 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+)
+
+// globalMaxWin holds the outcomes paying the table's highest payout when
+// GlobalMaxWinFreq is set. They are taken out of the buckets and weighted
+// together with the loss weight, so they hit exactly 1 in freq spins of the
+// final table while the buckets share the rest of the RTP.
+type globalMaxWin struct {
+	indices []int
+	payout  float64
+	freq    float64
+}
+
+// resolveGlobalMaxWin finds the outcomes the GlobalMaxWinFreq constraint
+// applies to. It returns nil when the constraint is off or cannot be met,
+// with a warning in the latter case. Pinned outcomes keep their weight.
+func (o *BucketOptimizer) resolveGlobalMaxWin(payouts []float64, pinned pinnedWeights) (*globalMaxWin, []string) {
+	if o.config.GlobalMaxWinFreq <= 0 {
+		return nil, nil
+	}
+
+	maxPayout := 0.0
+	for _, p := range payouts {
+		if p > maxPayout {
+			maxPayout = p
+		}
+	}
+	if maxPayout <= 0 {
+		return nil, []string{"Global max win frequency ignored: the table has no winning outcomes"}
+	}
+
+	m := &globalMaxWin{payout: maxPayout, freq: o.config.GlobalMaxWinFreq}
+	for i, p := range payouts {
+		if _, ok := pinned[i]; ok || p != maxPayout {
+			continue
+		}
+		m.indices = append(m.indices, i)
+	}
+	if len(m.indices) == 0 {
+		return nil, []string{"Global max win frequency ignored: the max win outcomes are pinned"}
+	}
+	if m.rtp() >= o.config.TargetRTP {
+		return nil, []string{fmt.Sprintf(
+			"Global max win frequency ignored: a %.0fx max win at 1 in %.0f alone returns %.1f%% RTP (target: %.1f%%)",
+			m.payout, m.freq, m.rtp()*100, o.config.TargetRTP*100)}
+	}
+	return m, nil
+}
+
+// contains reports whether an outcome is one of the max win outcomes
+func (m *globalMaxWin) contains(idx int) bool {
+	if m == nil {
+		return false
+	}
+	for _, i := range m.indices {
+		if i == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// exclude removes the max win outcomes from the bucket assignments and
+// returns the loss indices
+func (m *globalMaxWin) exclude(assignments []bucketAssignment, lossIndices []int) []int {
+	if m == nil {
+		return lossIndices
+	}
+	return excludeOutcomes(assignments, lossIndices, m.contains)
+}
+
+// rtp is the RTP the max win returns at its target frequency
+func (m *globalMaxWin) rtp() float64 {
+	if m == nil {
+		return 0
+	}
+	return m.payout / m.freq
+}
+
+// apply weights the max win outcomes once the other wins are weighted.
+// With S the weighted payout sum of those wins and x the max win weight,
+// the final total T must satisfy both targetRTP = (S + x*payout) / T and
+// x = T / freq, so T = S / (targetRTP - payout/freq). The loss weight
+// solved afterwards fills the table up to T.
+func (m *globalMaxWin) apply(weights []uint64, payouts []float64, targetRTP float64, minWeight uint64) {
+	if m == nil {
+		return
+	}
+	var weightedPayoutSum float64
+	for i, w := range weights {
+		if payouts[i] > 0 && !m.contains(i) {
+			weightedPayoutSum += float64(w) * payouts[i]
+		}
+	}
+	total := weightedPayoutSum / (targetRTP - m.rtp())
+	perOutcome := uint64(math.Round(total / m.freq / float64(len(m.indices))))
+	if perOutcome < minWeight {
+		perOutcome = minWeight
+	}
+	for _, idx := range m.indices {
+		weights[idx] = perOutcome
+	}
+}
+
+// frequency is how often the max win hits with the given weights (1 in N)
+func (m *globalMaxWin) frequency(weights []uint64) float64 {
+	if m == nil {
+		return 0
+	}
+	var maxWinWeight uint64
+	for _, idx := range m.indices {
+		maxWinWeight += weights[idx]
+	}
+	if maxWinWeight == 0 {
+		return 0
+	}
+	return float64(sumUint64(weights)) / float64(maxWinWeight)
+}
@@ -0,0 +1,82 @@
+package optimizer
+
+import (
+	"math"
+	"testing"
+
+	"stakergs"
+)
+
+// ============================================================================
+// Global Max Win Frequency Tests
+// ============================================================================
+
+func TestBucketOptimizer_GlobalMaxWinFreq(t *testing.T) {
+	table := &stakergs.LookupTable{
+		Mode: "test",
+		Cost: 1.0,
+		Outcomes: []stakergs.Outcome{
+			{SimID: 0, Weight: 1000, Payout: 0},   // loss
+			{SimID: 1, Weight: 100, Payout: 50},   // 0.5x
+			{SimID: 2, Weight: 100, Payout: 100},  // 1x
+			{SimID: 3, Weight: 50, Payout: 200},   // 2x
+			{SimID: 4, Weight: 20, Payout: 500},   // 5x
+			{SimID: 5, Weight: 5, Payout: 2000},   // 20x
+			{SimID: 6, Weight: 1, Payout: 500000}, // 5000x
+			{SimID: 7, Weight: 1, Payout: 500000}, // 5000x
+		},
+	}
+
+	config := &BucketOptimizerConfig{
+		TargetRTP:    0.96,
+		RTPTolerance: 0.0005,
+		MinWeight:    1,
+		Buckets: []BucketConfig{
+			{Name: "small", MinPayout: 0.01, MaxPayout: 5, Type: ConstraintFrequency, Frequency: 4},
+			{Name: "large", MinPayout: 5, MaxPayout: 5000, Type: ConstraintAuto},
+		},
+		GlobalMaxWinFreq: 1_000_000,
+	}
+
+	for _, bruteForce := range []bool{false, true} {
+		var result *BucketOptimizerResult
+		if bruteForce {
+			bf, err := NewBruteForceOptimizer(config, nil).OptimizeTable(table)
+			if err != nil {
+				t.Fatalf("brute force optimization failed: %v", err)
+			}
+			result = bf.BucketOptimizerResult
+		} else {
+			var err error
+			result, err = NewBucketOptimizer(config).OptimizeTable(table)
+			if err != nil {
+				t.Fatalf("optimization failed: %v", err)
+			}
+		}
+
+		// Both 5000x outcomes together hit 1 in 1M and the rest fills the RTP
+		if math.Abs(result.MaxWinFrequency/config.GlobalMaxWinFreq-1) > 1e-6 {
+			t.Errorf("brute force=%v: max win 1 in %.0f, want 1 in %.0f", bruteForce, result.MaxWinFrequency, config.GlobalMaxWinFreq)
+		}
+		if result.NewWeights[6] != result.NewWeights[7] {
+			t.Errorf("brute force=%v: max win weight not split evenly: %d vs %d", bruteForce, result.NewWeights[6], result.NewWeights[7])
+		}
+		if math.Abs(result.FinalRTP-config.TargetRTP) > config.RTPTolerance {
+			t.Errorf("brute force=%v: final RTP %.5f not within tolerance of %.2f", bruteForce, result.FinalRTP, config.TargetRTP)
+		}
+		if result.OutcomeDetails[6].BucketName != "max_win" {
+			t.Errorf("brute force=%v: expected max_win bucket, got %q", bruteForce, result.OutcomeDetails[6].BucketName)
+		}
+	}
+
+	// A max win returning more than the target RTP on its own is ignored
+	unreachable := *config
+	unreachable.GlobalMaxWinFreq = 1000
+	result, err := NewBucketOptimizer(&unreachable).OptimizeTable(table)
+	if err != nil {
+		t.Fatalf("optimization failed: %v", err)
+	}
+	if result.MaxWinFrequency != 0 || len(result.Warnings) == 0 {
+		t.Errorf("expected the constraint to be ignored with a warning, got 1 in %.0f, warnings %v", result.MaxWinFrequency, result.Warnings)
+	}
+}
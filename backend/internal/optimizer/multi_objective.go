@@ -0,0 +1,493 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"lutexplorer/internal/common"
+
+	"stakergs"
+)
+
+// MultiObjectiveConfig holds targets and per-objective weights for
+// MultiObjectiveOptimizer. A zero objective weight ignores that objective.
+type MultiObjectiveConfig struct {
+	TargetRTP        float64 `json:"target_rtp"`        // e.g. 0.96
+	TargetHitRate    float64 `json:"target_hit_rate"`   // P(payout > 0), e.g. 0.30
+	TargetVolatility float64 `json:"target_volatility"` // std dev of the spin payout in cost units, e.g. 8
+
+	RTPWeight        float64 `json:"rtp_weight"`
+	HitRateWeight    float64 `json:"hit_rate_weight"`
+	VolatilityWeight float64 `json:"volatility_weight"`
+
+	Iterations int   `json:"iterations,omitempty"`  // annealing steps per chain
+	Chains     int   `json:"chains,omitempty"`      // independent chains with varied objective weights
+	ParetoSize int   `json:"pareto_size,omitempty"` // max candidates returned
+	Seed       int64 `json:"seed,omitempty"`        // 0 = random
+
+	// IncludeWeights adds the full weight vector to every Pareto candidate;
+	// otherwise only BestWeights is filled.
+	IncludeWeights bool `json:"include_weights,omitempty"`
+}
+
+// DefaultMultiObjectiveConfig returns a config with RTP weighted highest.
+func DefaultMultiObjectiveConfig() *MultiObjectiveConfig {
+	return &MultiObjectiveConfig{
+		TargetRTP:        0.96,
+		RTPWeight:        10,
+		HitRateWeight:    1,
+		VolatilityWeight: 1,
+		Iterations:       20000,
+		Chains:           8,
+		ParetoSize:       20,
+	}
+}
+
+// ObjectiveMetrics are the three optimized metrics of one weight vector and
+// their relative errors against the targets.
+type ObjectiveMetrics struct {
+	RTP        float64 `json:"rtp"`
+	HitRate    float64 `json:"hit_rate"`
+	Volatility float64 `json:"volatility"`
+
+	RTPError        float64 `json:"rtp_error"`        // |rtp - target| / target
+	HitRateError    float64 `json:"hit_rate_error"`   // |hit_rate - target| / target
+	VolatilityError float64 `json:"volatility_error"` // |volatility - target| / target
+	Score           float64 `json:"score"`            // weighted sum of errors, lower is better
+}
+
+// ParetoCandidate is one non-dominated weight vector.
+type ParetoCandidate struct {
+	ObjectiveMetrics
+	Weights []uint64 `json:"weights,omitempty"`
+
+	groups []float64
+}
+
+// MultiObjectiveResult holds the Pareto set found by MultiObjectiveOptimizer.
+type MultiObjectiveResult struct {
+	Original ObjectiveMetrics  `json:"original"`
+	Pareto   []ParetoCandidate `json:"pareto"` // sorted by score, best first
+	// Ideal is the best error reached for each objective by any candidate;
+	// no single candidate usually reaches all three.
+	Ideal       ObjectiveMetrics `json:"ideal"`
+	BestWeights []uint64         `json:"best_weights"`
+	TotalWeight uint64           `json:"total_weight"`
+	Iterations  int              `json:"iterations"`
+	DurationMs  int64            `json:"duration_ms"`
+	Warnings    []string         `json:"warnings,omitempty"`
+}
+
+// MultiObjectiveOptimizer searches for weight vectors balancing RTP, hit rate
+// and volatility. Outcomes are grouped by payout and the group probabilities
+// are annealed, so outcomes with the same payout keep their relative weights.
+// Several chains run with perturbed objective weights and feed one archive of
+// non-dominated solutions, giving a spread of trade-offs rather than a single
+// answer.
+type MultiObjectiveOptimizer struct {
+	config *MultiObjectiveConfig
+}
+
+// NewMultiObjectiveOptimizer creates an optimizer, filling unset fields with defaults.
+func NewMultiObjectiveOptimizer(config *MultiObjectiveConfig) *MultiObjectiveOptimizer {
+	defaults := DefaultMultiObjectiveConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Iterations <= 0 {
+		config.Iterations = defaults.Iterations
+	}
+	if config.Chains <= 0 {
+		config.Chains = defaults.Chains
+	}
+	if config.ParetoSize <= 0 {
+		config.ParetoSize = defaults.ParetoSize
+	}
+	return &MultiObjectiveOptimizer{config: config}
+}
+
+// ValidateMultiObjectiveConfig checks that at least one objective is active
+// and targets are usable.
+func ValidateMultiObjectiveConfig(config *MultiObjectiveConfig) error {
+	if config.RTPWeight < 0 || config.HitRateWeight < 0 || config.VolatilityWeight < 0 {
+		return fmt.Errorf("objective weights must be non-negative")
+	}
+	active := 0
+	if config.RTPWeight > 0 {
+		if config.TargetRTP <= 0 {
+			return fmt.Errorf("target_rtp must be positive when rtp_weight is set")
+		}
+		active++
+	}
+	if config.HitRateWeight > 0 {
+		if config.TargetHitRate <= 0 || config.TargetHitRate >= 1 {
+			return fmt.Errorf("target_hit_rate must be within (0, 1) when hit_rate_weight is set")
+		}
+		active++
+	}
+	if config.VolatilityWeight > 0 {
+		if config.TargetVolatility <= 0 {
+			return fmt.Errorf("target_volatility must be positive when volatility_weight is set")
+		}
+		active++
+	}
+	if active == 0 {
+		return fmt.Errorf("at least one objective needs a positive weight and target")
+	}
+	if config.Iterations > 1_000_000 {
+		return fmt.Errorf("iterations must be at most 1000000")
+	}
+	if config.Chains > 64 {
+		return fmt.Errorf("chains must be at most 64")
+	}
+	return nil
+}
+
+// objectiveGroup is all outcomes sharing one payout.
+type objectiveGroup struct {
+	payout  float64 // normalized by cost
+	indices []int
+	base    []float64 // relative weights within the group, summing to 1
+}
+
+// objectiveState is the annealing state, one probability mass per payout
+// group. Running sums make evaluating a move O(1).
+type objectiveState struct {
+	mass           []float64
+	total, s1, s2  float64
+	hit            float64
+	groups         []objectiveGroup
+	config         *MultiObjectiveConfig
+	rtpW, hitW, vW float64
+}
+
+func (s *objectiveState) metrics() ObjectiveMetrics {
+	return s.metricsWith(s.rtpW, s.hitW, s.vW)
+}
+
+func (s *objectiveState) metricsWith(rtpW, hitW, vW float64) ObjectiveMetrics {
+	var m ObjectiveMetrics
+	if s.total <= 0 {
+		return m
+	}
+	mean := s.s1 / s.total
+	variance := s.s2/s.total - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	m.RTP = mean
+	m.HitRate = s.hit / s.total
+	m.Volatility = math.Sqrt(variance)
+
+	c := s.config
+	if c.TargetRTP > 0 {
+		m.RTPError = math.Abs(m.RTP-c.TargetRTP) / c.TargetRTP
+	}
+	if c.TargetHitRate > 0 {
+		m.HitRateError = math.Abs(m.HitRate-c.TargetHitRate) / c.TargetHitRate
+	}
+	if c.TargetVolatility > 0 {
+		m.VolatilityError = math.Abs(m.Volatility-c.TargetVolatility) / c.TargetVolatility
+	}
+	m.Score = rtpW*m.RTPError + hitW*m.HitRateError + vW*m.VolatilityError
+	return m
+}
+
+// normalize scales the masses to sum to 1 and recomputes the running sums.
+func (s *objectiveState) normalize() {
+	total := s.total
+	s.total, s.s1, s.s2, s.hit = 0, 0, 0, 0
+	for k, m := range s.mass {
+		s.mass[k] = 0
+		s.set(k, m/total)
+	}
+}
+
+// set changes the mass of group k and updates the running sums.
+func (s *objectiveState) set(k int, mass float64) {
+	old := s.mass[k]
+	x := s.groups[k].payout
+	d := mass - old
+	s.total += d
+	s.s1 += d * x
+	s.s2 += d * x * x
+	if x > 0 {
+		s.hit += d
+	}
+	s.mass[k] = mass
+}
+
+// OptimizeTable runs the search on a table.
+func (o *MultiObjectiveOptimizer) OptimizeTable(table *stakergs.LookupTable) (*MultiObjectiveResult, error) {
+	if err := ValidateMultiObjectiveConfig(o.config); err != nil {
+		return nil, err
+	}
+	n := len(table.Outcomes)
+	if n == 0 {
+		return nil, fmt.Errorf("empty table")
+	}
+	start := time.Now()
+
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+
+	// Group outcomes by payout
+	byPayout := make(map[uint][]int)
+	for i, outcome := range table.Outcomes {
+		byPayout[outcome.Payout] = append(byPayout[outcome.Payout], i)
+	}
+	payoutKeys := make([]uint, 0, len(byPayout))
+	for p := range byPayout {
+		payoutKeys = append(payoutKeys, p)
+	}
+	sort.Slice(payoutKeys, func(i, j int) bool { return payoutKeys[i] < payoutKeys[j] })
+
+	totalWeight := float64(table.TotalWeight())
+	groups := make([]objectiveGroup, len(payoutKeys))
+	initial := make([]float64, len(payoutKeys))
+	for k, p := range payoutKeys {
+		indices := byPayout[p]
+		var groupWeight float64
+		for _, i := range indices {
+			groupWeight += float64(table.Outcomes[i].Weight)
+		}
+		base := make([]float64, len(indices))
+		for j, i := range indices {
+			if groupWeight > 0 {
+				base[j] = float64(table.Outcomes[i].Weight) / groupWeight
+			} else {
+				base[j] = 1 / float64(len(indices))
+			}
+		}
+		groups[k] = objectiveGroup{payout: float64(p) / 100.0 / cost, indices: indices, base: base}
+		if totalWeight > 0 {
+			initial[k] = groupWeight / totalWeight
+		}
+		// Groups without weight still need a foothold to be explored
+		if initial[k] < 1e-12 {
+			initial[k] = 1e-12
+		}
+	}
+
+	seed := o.config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	newState := func(rtpW, hitW, vW float64) *objectiveState {
+		s := &objectiveState{
+			mass:   make([]float64, len(groups)),
+			groups: groups,
+			config: o.config,
+			rtpW:   rtpW,
+			hitW:   hitW,
+			vW:     vW,
+		}
+		for k, m := range initial {
+			s.set(k, m)
+		}
+		return s
+	}
+
+	result := &MultiObjectiveResult{
+		Original: newState(o.config.RTPWeight, o.config.HitRateWeight, o.config.VolatilityWeight).metrics(),
+	}
+	archive := &paretoArchive{limit: o.config.ParetoSize * 4}
+
+	for chain := 0; chain < o.config.Chains; chain++ {
+		rtpW, hitW, vW := o.config.RTPWeight, o.config.HitRateWeight, o.config.VolatilityWeight
+		if chain > 0 {
+			// Perturb the scalarization so chains settle on different trade-offs
+			rtpW *= math.Exp(rng.NormFloat64())
+			hitW *= math.Exp(rng.NormFloat64())
+			vW *= math.Exp(rng.NormFloat64())
+		}
+		o.anneal(newState(rtpW, hitW, vW), rng, archive)
+		result.Iterations += o.config.Iterations
+	}
+
+	// Re-evaluate at the integer weights that will be written, which can
+	// reorder close candidates, then keep the non-dominated best ParetoSize
+	for i := range archive.items {
+		c := &archive.items[i]
+		c.ObjectiveMetrics = o.roundedMetrics(groups, c.groups)
+	}
+	candidates := make([]ParetoCandidate, 0, len(archive.items))
+	for i, c := range archive.items {
+		dominated := false
+		for j, other := range archive.items {
+			if i != j && dominates(other.ObjectiveMetrics, c.ObjectiveMetrics) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			candidates = append(candidates, c)
+		}
+	}
+	archive.items = candidates
+	sort.Slice(archive.items, func(i, j int) bool { return archive.items[i].Score < archive.items[j].Score })
+	if len(archive.items) > o.config.ParetoSize {
+		archive.items = archive.items[:o.config.ParetoSize]
+	}
+
+	result.Ideal = ObjectiveMetrics{RTPError: math.Inf(1), HitRateError: math.Inf(1), VolatilityError: math.Inf(1)}
+	for i := range archive.items {
+		c := &archive.items[i]
+		result.Ideal.RTPError = math.Min(result.Ideal.RTPError, c.RTPError)
+		result.Ideal.HitRateError = math.Min(result.Ideal.HitRateError, c.HitRateError)
+		result.Ideal.VolatilityError = math.Min(result.Ideal.VolatilityError, c.VolatilityError)
+
+		if i == 0 || o.config.IncludeWeights {
+			weights := groupsToWeights(groups, c.groups, n)
+			if i == 0 {
+				result.BestWeights = weights
+				result.TotalWeight = sumUint64(weights)
+			}
+			if o.config.IncludeWeights {
+				c.Weights = weights
+			}
+		}
+	}
+	result.Pareto = archive.items
+
+	if len(result.Pareto) > 0 {
+		best := result.Pareto[0]
+		if o.config.RTPWeight > 0 && best.RTPError > 0.01 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("best candidate misses the RTP target by %.2f%%; the payouts may not support all targets at once", best.RTPError*100))
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// anneal runs one simulated annealing chain, offering every accepted state
+// to the archive.
+func (o *MultiObjectiveOptimizer) anneal(s *objectiveState, rng *rand.Rand, archive *paretoArchive) {
+	const (
+		startTemp = 0.5
+		endTemp   = 1e-5
+		maxMass   = 1e6 // relative to the initial total of 1; keeps sums well conditioned
+		minMass   = 1e-15
+	)
+	iterations := o.config.Iterations
+	cooling := math.Pow(endTemp/startTemp, 1/float64(iterations))
+	temp := startTemp
+	current := s.metrics().Score
+
+	for it := 0; it < iterations; it++ {
+		k := rng.Intn(len(s.mass))
+		old := s.mass[k]
+		step := 0.1 + 1.5*temp/startTemp
+		next := old * math.Exp(rng.NormFloat64()*step)
+		next = math.Max(minMass*s.total, math.Min(next, maxMass))
+
+		s.set(k, next)
+		m := s.metrics()
+		if m.Score <= current || rng.Float64() < math.Exp((current-m.Score)/temp) {
+			current = m.Score
+			// Archive scores always use the requested objective weights
+			archive.offer(s.metricsWith(o.config.RTPWeight, o.config.HitRateWeight, o.config.VolatilityWeight), s.mass)
+		} else {
+			s.set(k, old)
+		}
+
+		// Renormalize now and then so the sums don't drift
+		if it%1000 == 999 {
+			s.normalize()
+		}
+		temp *= cooling
+	}
+}
+
+// roundedMetrics evaluates group masses as groupsToWeights would write them,
+// with the requested objective weights.
+func (o *MultiObjectiveOptimizer) roundedMetrics(groups []objectiveGroup, mass []float64) ObjectiveMetrics {
+	s := &objectiveState{
+		mass:   make([]float64, len(groups)),
+		groups: groups,
+		config: o.config,
+		rtpW:   o.config.RTPWeight,
+		hitW:   o.config.HitRateWeight,
+		vW:     o.config.VolatilityWeight,
+	}
+	total := sumFloat64(mass)
+	for k, g := range groups {
+		var w float64
+		for _, b := range g.base {
+			w += float64(outcomeWeight(mass[k]/total, b))
+		}
+		s.set(k, w)
+	}
+	return s.metrics()
+}
+
+// groupsToWeights spreads group masses over outcomes at common.BaseWeight scale.
+func groupsToWeights(groups []objectiveGroup, mass []float64, n int) []uint64 {
+	total := sumFloat64(mass)
+	weights := make([]uint64, n)
+	for k, g := range groups {
+		for j, i := range g.indices {
+			weights[i] = outcomeWeight(mass[k]/total, g.base[j])
+		}
+	}
+	return weights
+}
+
+// outcomeWeight is the integer weight of an outcome holding the fraction base
+// of a group with probability share. Every outcome keeps a weight of at least 1.
+func outcomeWeight(share, base float64) uint64 {
+	w := uint64(math.Round(share * base * float64(common.BaseWeight)))
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// paretoArchive keeps solutions not dominated on (RTP, hit rate, volatility) error.
+type paretoArchive struct {
+	items []ParetoCandidate
+	limit int
+}
+
+func dominates(a, b ObjectiveMetrics) bool {
+	return a.RTPError <= b.RTPError && a.HitRateError <= b.HitRateError && a.VolatilityError <= b.VolatilityError &&
+		(a.RTPError < b.RTPError || a.HitRateError < b.HitRateError || a.VolatilityError < b.VolatilityError)
+}
+
+// offer adds m unless an archived solution dominates it, dropping the archived
+// solutions m dominates. When full, the candidate with the worst score goes.
+func (a *paretoArchive) offer(m ObjectiveMetrics, mass []float64) {
+	kept := a.items[:0]
+	for _, c := range a.items {
+		if dominates(c.ObjectiveMetrics, m) ||
+			(c.RTPError == m.RTPError && c.HitRateError == m.HitRateError && c.VolatilityError == m.VolatilityError) {
+			return
+		}
+	}
+	for _, c := range a.items {
+		if !dominates(m, c.ObjectiveMetrics) {
+			kept = append(kept, c)
+		}
+	}
+	a.items = append(kept, ParetoCandidate{
+		ObjectiveMetrics: m,
+		groups:           append([]float64(nil), mass...),
+	})
+
+	if len(a.items) > a.limit {
+		worst := 0
+		for i, c := range a.items {
+			if c.Score > a.items[worst].Score {
+				worst = i
+			}
+		}
+		a.items = append(a.items[:worst], a.items[worst+1:]...)
+	}
+}
@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"testing"
+
+	"stakergs"
+)
+
+// ============================================================================
+// Multi-Objective Optimizer Tests
+// ============================================================================
+
+func multiObjectiveTestTable() *stakergs.LookupTable {
+	return &stakergs.LookupTable{
+		Mode: "test",
+		Cost: 1.0,
+		Outcomes: []stakergs.Outcome{
+			{SimID: 0, Weight: 1000, Payout: 0},   // loss
+			{SimID: 1, Weight: 200, Payout: 50},   // 0.5x
+			{SimID: 2, Weight: 150, Payout: 100},  // 1x
+			{SimID: 3, Weight: 100, Payout: 200},  // 2x
+			{SimID: 4, Weight: 40, Payout: 500},   // 5x
+			{SimID: 5, Weight: 10, Payout: 2000},  // 20x
+			{SimID: 6, Weight: 2, Payout: 10000},  // 100x
+			{SimID: 7, Weight: 1, Payout: 100000}, // 1000x
+		},
+	}
+}
+
+func TestMultiObjectiveOptimizer_HitsTargets(t *testing.T) {
+	config := &MultiObjectiveConfig{
+		TargetRTP:        0.96,
+		TargetHitRate:    0.30,
+		TargetVolatility: 10,
+		RTPWeight:        10,
+		HitRateWeight:    2,
+		VolatilityWeight: 1,
+		Iterations:       5000,
+		Chains:           4,
+		Seed:             42,
+	}
+
+	result, err := NewMultiObjectiveOptimizer(config).OptimizeTable(multiObjectiveTestTable())
+	if err != nil {
+		t.Fatalf("optimization failed: %v", err)
+	}
+	if len(result.Pareto) == 0 {
+		t.Fatal("expected at least one Pareto candidate")
+	}
+
+	best := result.Pareto[0]
+	t.Logf("Original: rtp=%.4f hit=%.4f vol=%.2f", result.Original.RTP, result.Original.HitRate, result.Original.Volatility)
+	t.Logf("Best: rtp=%.4f hit=%.4f vol=%.2f score=%.4f (%d candidates)", best.RTP, best.HitRate, best.Volatility, best.Score, len(result.Pareto))
+
+	if best.RTPError > 0.005 {
+		t.Errorf("RTP %.4f too far from target 0.96", best.RTP)
+	}
+	if best.HitRateError > 0.05 {
+		t.Errorf("hit rate %.4f too far from target 0.30", best.HitRate)
+	}
+	if best.VolatilityError > 0.05 {
+		t.Errorf("volatility %.2f too far from target 10", best.Volatility)
+	}
+	if len(result.BestWeights) != 8 {
+		t.Errorf("expected 8 weights, got %d", len(result.BestWeights))
+	}
+
+	// No candidate may dominate another
+	for i, a := range result.Pareto {
+		for j, b := range result.Pareto {
+			if i != j && dominates(a.ObjectiveMetrics, b.ObjectiveMetrics) {
+				t.Errorf("candidate %d dominates candidate %d", i, j)
+			}
+		}
+	}
+}
+
+func TestMultiObjectiveOptimizer_Validation(t *testing.T) {
+	if err := ValidateMultiObjectiveConfig(&MultiObjectiveConfig{}); err == nil {
+		t.Error("expected error without any objective")
+	}
+	if err := ValidateMultiObjectiveConfig(&MultiObjectiveConfig{HitRateWeight: 1, TargetHitRate: 1.5}); err == nil {
+		t.Error("expected error for hit rate target above 1")
+	}
+}
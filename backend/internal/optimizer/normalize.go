@@ -0,0 +1,207 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+
+	"stakergs"
+)
+
+// DefaultNormalizeTolerance is the RTP drift normalization may cause when
+// no tolerance is given (0.01%)
+const DefaultNormalizeTolerance = 0.0001
+
+// NormalizeOptions configures weight normalization. Without a target total
+// the weights are only divided by their GCD.
+type NormalizeOptions struct {
+	TargetTotal  uint64  `json:"target_total,omitempty"`  // Scale weights to sum to about this (e.g. 100000000)
+	ReduceGCD    bool    `json:"reduce_gcd,omitempty"`    // Divide weights by their greatest common divisor
+	RTPTolerance float64 `json:"rtp_tolerance,omitempty"` // Max RTP drift (default 0.0001)
+}
+
+// NormalizeResult describes normalized weights
+type NormalizeResult struct {
+	Weights         []uint64 `json:"-"`
+	OriginalTotal   uint64   `json:"original_total"`
+	NewTotal        uint64   `json:"new_total"`
+	Scale           float64  `json:"scale"`            // Factor the weights were scaled by (1 without a target total)
+	GCD             uint64   `json:"gcd"`              // Divisor of the GCD reduction (1 if none)
+	ClampedOutcomes int      `json:"clamped_outcomes"` // Non-zero weights that scaled below 1 and were kept at 1
+	LossAdjusted    bool     `json:"loss_adjusted"`    // Loss weights were rebalanced to cancel rounding drift
+	OriginalRTP     float64  `json:"original_rtp"`
+	NewRTP          float64  `json:"new_rtp"`
+	RTPDrift        float64  `json:"rtp_drift"` // NewRTP - OriginalRTP
+	RTPTolerance    float64  `json:"rtp_tolerance"`
+	// PinnedOutcomes are the pinned weights after normalization: scaled
+	// with the rest of the table, but left out of the loss rebalancing
+	PinnedOutcomes []PinnedOutcome `json:"pinned_outcomes,omitempty"`
+}
+
+// NormalizeWeights rewrites optimized weights as smaller integers: scaled to
+// a target total and/or divided by their GCD. Outcomes keep a non-zero
+// weight, and the rounding drift is moved onto the loss weights. It fails if
+// the RTP still drifts by more than the tolerance, e.g. when the target
+// total is too small for the rarest outcomes.
+func NormalizeWeights(weights []uint64, payouts []float64, opts NormalizeOptions) (*NormalizeResult, error) {
+	return normalizeWeights(weights, payouts, nil, opts)
+}
+
+// normalizeWeights is NormalizeWeights for optimized weights with pinned
+// outcomes. Scaling keeps their probability, so pinned weights change with
+// the total, but the rounding drift is only moved onto unpinned losses.
+func normalizeWeights(weights []uint64, payouts []float64, pinned pinnedWeights, opts NormalizeOptions) (*NormalizeResult, error) {
+	if len(weights) != len(payouts) {
+		return nil, fmt.Errorf("weight count mismatch: got %d weights for %d outcomes", len(weights), len(payouts))
+	}
+	total := sumUint64(weights)
+	if total == 0 {
+		return nil, fmt.Errorf("weights sum to zero")
+	}
+	if opts.TargetTotal == 0 {
+		opts.ReduceGCD = true
+	}
+	if opts.RTPTolerance <= 0 {
+		opts.RTPTolerance = DefaultNormalizeTolerance
+	}
+
+	result := &NormalizeResult{
+		Weights:       make([]uint64, len(weights)),
+		OriginalTotal: total,
+		Scale:         1,
+		GCD:           1,
+		OriginalRTP:   calculateRTPFromWeights(weights, payouts),
+		RTPTolerance:  opts.RTPTolerance,
+	}
+	copy(result.Weights, weights)
+
+	if opts.TargetTotal > 0 {
+		result.Scale = float64(opts.TargetTotal) / float64(total)
+		for i, w := range weights {
+			if w == 0 {
+				continue
+			}
+			scaled := uint64(math.Round(float64(w) * result.Scale))
+			if scaled == 0 {
+				scaled = 1
+				result.ClampedOutcomes++
+			}
+			result.Weights[i] = scaled
+		}
+		if adjusted := rebalanceLossWeights(result.Weights, payouts, result.OriginalRTP, pinned); adjusted != nil {
+			result.Weights = adjusted
+			result.LossAdjusted = true
+		}
+	}
+
+	if opts.ReduceGCD {
+		var g uint64
+		for _, w := range result.Weights {
+			if w > 0 {
+				g = gcd(g, w)
+			}
+		}
+		if g > 1 {
+			for i := range result.Weights {
+				result.Weights[i] /= g
+			}
+			result.GCD = g
+		}
+	}
+
+	result.NewTotal = sumUint64(result.Weights)
+	result.NewRTP = calculateRTPFromWeights(result.Weights, payouts)
+	result.RTPDrift = result.NewRTP - result.OriginalRTP
+	if math.Abs(result.RTPDrift) > opts.RTPTolerance {
+		return nil, fmt.Errorf("normalized RTP drifts by %.4f%% (tolerance %.4f%%); use a larger target total",
+			result.RTPDrift*100, opts.RTPTolerance*100)
+	}
+	return result, nil
+}
+
+// rebalanceLossWeights scales the unpinned loss weights so the weights
+// return targetRTP, keeping their proportions. It returns nil when there are
+// no loss weights to adjust or the rebalanced weights are not closer.
+func rebalanceLossWeights(weights []uint64, payouts []float64, targetRTP float64, pinned pinnedWeights) []uint64 {
+	if targetRTP <= 0 {
+		return nil
+	}
+	var weightedPayoutSum, fixedWeight, lossWeight float64
+	for i, w := range weights {
+		_, isPinned := pinned[i]
+		if payouts[i] > 0 {
+			weightedPayoutSum += float64(w) * payouts[i]
+		}
+		if payouts[i] > 0 || isPinned {
+			fixedWeight += float64(w)
+		} else {
+			lossWeight += float64(w)
+		}
+	}
+	requiredLossWeight := weightedPayoutSum/targetRTP - fixedWeight
+	if lossWeight == 0 || requiredLossWeight <= 0 {
+		return nil
+	}
+
+	adjusted := make([]uint64, len(weights))
+	copy(adjusted, weights)
+	factor := requiredLossWeight / lossWeight
+	for i, w := range weights {
+		if _, isPinned := pinned[i]; isPinned || payouts[i] > 0 || w == 0 {
+			continue
+		}
+		scaled := uint64(math.Round(float64(w) * factor))
+		if scaled == 0 {
+			scaled = 1
+		}
+		adjusted[i] = scaled
+	}
+
+	before := math.Abs(calculateRTPFromWeights(weights, payouts) - targetRTP)
+	after := math.Abs(calculateRTPFromWeights(adjusted, payouts) - targetRTP)
+	if after >= before {
+		return nil
+	}
+	return adjusted
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// applyNormalized replaces the optimized weights with normalized ones and
+// updates the weight totals of the result
+func (r *BucketOptimizerResult) applyNormalized(n *NormalizeResult) {
+	r.NewWeights = n.Weights
+	r.TotalWeight = n.NewTotal
+	r.FinalRTP = n.NewRTP
+
+	bucketWeights := make(map[string]uint64)
+	for i := range r.OutcomeDetails {
+		d := &r.OutcomeDetails[i]
+		d.NewWeight = n.Weights[i]
+		d.Probability = float64(d.NewWeight) / float64(n.NewTotal)
+		bucketWeights[d.BucketName] += d.NewWeight
+	}
+	for i := range r.BucketResults {
+		r.BucketResults[i].TotalWeight = bucketWeights[r.BucketResults[i].Name]
+	}
+	if r.LossResult != nil {
+		r.LossResult.TotalWeight = bucketWeights["loss"]
+	}
+}
+
+// tablePayouts returns the payouts of a table normalized by its cost
+func tablePayouts(table *stakergs.LookupTable) []float64 {
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+	payouts := make([]float64, len(table.Outcomes))
+	for i, outcome := range table.Outcomes {
+		payouts[i] = float64(outcome.Payout) / 100.0 / cost
+	}
+	return payouts
+}
@@ -0,0 +1,77 @@
+package optimizer
+
+import (
+	"math"
+	"testing"
+)
+
+// ============================================================================
+// Weight Normalization Tests
+// ============================================================================
+
+func TestNormalizeWeights(t *testing.T) {
+	payouts := []float64{0, 0.5, 2, 10, 1000}
+
+	// GCD reduction is exact
+	weights := []uint64{6_000_000, 3_000_000, 900_000, 90_000, 3_000}
+	result, err := NormalizeWeights(weights, payouts, NormalizeOptions{})
+	if err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if result.GCD != 3_000 || result.Weights[4] != 1 || result.Weights[0] != 2_000 {
+		t.Errorf("expected weights divided by 3000, got gcd %d weights %v", result.GCD, result.Weights)
+	}
+	if result.RTPDrift != 0 {
+		t.Errorf("expected no drift from GCD reduction, got %v", result.RTPDrift)
+	}
+
+	// Scaling to a target total keeps the RTP within tolerance
+	weights = []uint64{649_123_456_789, 298_765_432_101, 45_678_901_234, 4_567_890_123, 12_345_678}
+	result, err = NormalizeWeights(weights, payouts, NormalizeOptions{TargetTotal: 100_000_000})
+	if err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if math.Abs(float64(result.NewTotal)-1e8) > 1e8*0.01 {
+		t.Errorf("expected a total of about 1e8, got %d", result.NewTotal)
+	}
+	if math.Abs(result.RTPDrift) > DefaultNormalizeTolerance {
+		t.Errorf("RTP drift %v exceeds the default tolerance", result.RTPDrift)
+	}
+	for i, w := range result.Weights {
+		if w == 0 {
+			t.Errorf("outcome %d lost its weight", i)
+		}
+	}
+
+	// A total too small for the rarest outcome drifts too far
+	if _, err := NormalizeWeights(weights, payouts, NormalizeOptions{TargetTotal: 1_000, RTPTolerance: 1e-6}); err == nil {
+		t.Error("expected error for drift beyond tolerance")
+	}
+	if _, err := NormalizeWeights(weights[:2], payouts, NormalizeOptions{}); err == nil {
+		t.Error("expected error for weight count mismatch")
+	}
+}
+
+func TestNormalizeWeights_Pinned(t *testing.T) {
+	payouts := []float64{0, 0, 2, 10, 1000}
+	weights := []uint64{649_123_456_789, 298_765_432_101, 45_678_901_234, 4_567_890_123, 12_345_678}
+	pinned := pinnedWeights{1: weights[1], 4: weights[4]}
+
+	result, err := normalizeWeights(weights, payouts, pinned, NormalizeOptions{TargetTotal: 100_000_000})
+	if err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if !result.LossAdjusted {
+		t.Fatal("expected the unpinned loss to be rebalanced")
+	}
+	// Pinned outcomes are scaled with the table but not rebalanced
+	for idx := range pinned {
+		expected := uint64(math.Round(float64(weights[idx]) * result.Scale))
+		if result.Weights[idx] != expected {
+			t.Errorf("pinned outcome %d: expected scaled weight %d, got %d", idx, expected, result.Weights[idx])
+		}
+	}
+	if math.Abs(result.RTPDrift) > DefaultNormalizeTolerance {
+		t.Errorf("RTP drift %v exceeds the default tolerance", result.RTPDrift)
+	}
+}
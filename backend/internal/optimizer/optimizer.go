@@ -25,3 +25,12 @@ func sumUint64(values []uint64) uint64 {
 	}
 	return total
 }
+
+// sumFloat64 sums float64 slice
+func sumFloat64(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
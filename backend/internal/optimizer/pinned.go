@@ -0,0 +1,148 @@
+package optimizer
+
+import (
+	"fmt"
+	"sort"
+
+	"lutexplorer/internal/common"
+	"stakergs"
+)
+
+// PinnedOutcome fixes the weight of one outcome during optimization
+type PinnedOutcome struct {
+	SimID  int    `json:"sim_id"`
+	Weight uint64 `json:"weight"`
+}
+
+// pinnedWeights maps outcome indices to their pinned weight
+type pinnedWeights map[int]uint64
+
+// ValidatePinnedOutcomes checks that every pinned outcome exists in the table
+// and is pinned once
+func ValidatePinnedOutcomes(table *stakergs.LookupTable, pinned []PinnedOutcome) error {
+	_, err := resolvePinnedOutcomes(table, pinned)
+	return err
+}
+
+// resolvePinnedOutcomes maps pinned outcomes from simIDs to outcome indices
+func resolvePinnedOutcomes(table *stakergs.LookupTable, pinned []PinnedOutcome) (pinnedWeights, error) {
+	if len(pinned) == 0 {
+		return nil, nil
+	}
+	indexBySimID := make(map[int]int, len(table.Outcomes))
+	for i, outcome := range table.Outcomes {
+		indexBySimID[outcome.SimID] = i
+	}
+	result := make(pinnedWeights, len(pinned))
+	for _, p := range pinned {
+		idx, ok := indexBySimID[p.SimID]
+		if !ok {
+			return nil, fmt.Errorf("pinned outcome %d not found in mode %s", p.SimID, table.Mode)
+		}
+		if _, dup := result[idx]; dup {
+			return nil, fmt.Errorf("outcome %d is pinned more than once", p.SimID)
+		}
+		result[idx] = p.Weight
+	}
+	return result, nil
+}
+
+// exclude removes pinned outcomes from the bucket assignments and loss
+// indices so they are not re-weighted, and returns the remaining loss indices
+func (p pinnedWeights) exclude(assignments []bucketAssignment, lossIndices []int) []int {
+	if len(p) == 0 {
+		return lossIndices
+	}
+	return excludeOutcomes(assignments, lossIndices, func(idx int) bool {
+		_, ok := p[idx]
+		return ok
+	})
+}
+
+// excludeOutcomes removes the outcomes matching skip from the bucket
+// assignments (recomputing their average payout) and from the loss indices,
+// and returns the remaining loss indices
+func excludeOutcomes(assignments []bucketAssignment, lossIndices []int, skip func(idx int) bool) []int {
+	for i := range assignments {
+		bucket := &assignments[i]
+		indices := bucket.outcomeIndices[:0]
+		payouts := bucket.payouts[:0]
+		sum := 0.0
+		for j, idx := range bucket.outcomeIndices {
+			if skip(idx) {
+				continue
+			}
+			indices = append(indices, idx)
+			payouts = append(payouts, bucket.payouts[j])
+			sum += bucket.payouts[j]
+		}
+		bucket.outcomeIndices = indices
+		bucket.payouts = payouts
+		bucket.avgPayout = 0
+		if len(payouts) > 0 {
+			bucket.avgPayout = sum / float64(len(payouts))
+		}
+	}
+	remaining := make([]int, 0, len(lossIndices))
+	for _, idx := range lossIndices {
+		if !skip(idx) {
+			remaining = append(remaining, idx)
+		}
+	}
+	return remaining
+}
+
+// rtp estimates the RTP the pinned outcomes contribute, assuming the
+// optimized table totals about common.BaseWeight like the bucket weights do
+func (p pinnedWeights) rtp(payouts []float64) float64 {
+	var rtp float64
+	for idx, w := range p {
+		rtp += float64(w) / float64(common.BaseWeight) * payouts[idx]
+	}
+	return rtp
+}
+
+// contribution is the RTP the pinned outcomes contribute to a weight vector
+func (p pinnedWeights) contribution(weights []uint64, payouts []float64) float64 {
+	total := sumUint64(weights)
+	if total == 0 {
+		return 0
+	}
+	var sum float64
+	for idx := range p {
+		sum += float64(weights[idx]) * payouts[idx]
+	}
+	return sum / float64(total)
+}
+
+// lossWeight is the total weight of pinned non-paying outcomes, which counts
+// towards the loss weight needed for the target RTP
+func (p pinnedWeights) lossWeight(payouts []float64) float64 {
+	var total float64
+	for idx, w := range p {
+		if payouts[idx] <= 0 {
+			total += float64(w)
+		}
+	}
+	return total
+}
+
+// apply sets the pinned weights
+func (p pinnedWeights) apply(weights []uint64) {
+	for idx, w := range p {
+		weights[idx] = w
+	}
+}
+
+// outcomes lists the pinned outcomes with their weight in weights, by simID
+func (p pinnedWeights) outcomes(table *stakergs.LookupTable, weights []uint64) []PinnedOutcome {
+	if len(p) == 0 {
+		return nil
+	}
+	result := make([]PinnedOutcome, 0, len(p))
+	for idx := range p {
+		result = append(result, PinnedOutcome{SimID: table.Outcomes[idx].SimID, Weight: weights[idx]})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SimID < result[j].SimID })
+	return result
+}
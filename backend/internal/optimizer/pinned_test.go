@@ -0,0 +1,88 @@
+package optimizer
+
+import (
+	"math"
+	"testing"
+
+	"stakergs"
+)
+
+// ============================================================================
+// Pinned Outcome Tests
+// ============================================================================
+
+func TestBucketOptimizer_PinnedOutcomes(t *testing.T) {
+	table := &stakergs.LookupTable{
+		Mode: "test",
+		Cost: 1.0,
+		Outcomes: []stakergs.Outcome{
+			{SimID: 0, Weight: 1000, Payout: 0},  // loss
+			{SimID: 1, Weight: 500, Payout: 0},   // loss (pinned)
+			{SimID: 2, Weight: 100, Payout: 50},  // 0.5x
+			{SimID: 3, Weight: 100, Payout: 100}, // 1x
+			{SimID: 4, Weight: 50, Payout: 200},  // 2x
+			{SimID: 5, Weight: 20, Payout: 500},  // 5x
+			{SimID: 6, Weight: 5, Payout: 2000},  // 20x
+			{SimID: 7, Weight: 1, Payout: 10000}, // 100x (pinned)
+		},
+	}
+	pinned := []PinnedOutcome{
+		{SimID: 1, Weight: 20_000_000_000},
+		{SimID: 7, Weight: 1_000_000},
+	}
+
+	config := &BucketOptimizerConfig{
+		TargetRTP:    0.96,
+		RTPTolerance: 0.001,
+		MinWeight:    1,
+		Buckets: []BucketConfig{
+			{Name: "small", MinPayout: 0.01, MaxPayout: 5, Type: ConstraintFrequency, Frequency: 4},
+			{Name: "large", MinPayout: 5, MaxPayout: 100, Type: ConstraintAuto},
+		},
+		PinnedOutcomes: pinned,
+	}
+
+	for _, bruteForce := range []bool{false, true} {
+		var result *BucketOptimizerResult
+		if bruteForce {
+			bf, err := NewBruteForceOptimizer(config, nil).OptimizeTable(table)
+			if err != nil {
+				t.Fatalf("brute force optimization failed: %v", err)
+			}
+			result = bf.BucketOptimizerResult
+		} else {
+			var err error
+			result, err = NewBucketOptimizer(config).OptimizeTable(table)
+			if err != nil {
+				t.Fatalf("optimization failed: %v", err)
+			}
+		}
+
+		if result.NewWeights[1] != pinned[0].Weight || result.NewWeights[7] != pinned[1].Weight {
+			t.Errorf("brute force=%v: pinned weights changed: %d, %d", bruteForce, result.NewWeights[1], result.NewWeights[7])
+		}
+		if math.Abs(result.FinalRTP-config.TargetRTP) > config.RTPTolerance {
+			t.Errorf("brute force=%v: final RTP %.5f not within tolerance of %.2f", bruteForce, result.FinalRTP, config.TargetRTP)
+		}
+
+		// The pinned max win still counts towards the RTP
+		expectedPinnedRTP := float64(pinned[1].Weight) * 100 / float64(result.TotalWeight)
+		if math.Abs(result.PinnedRTP-expectedPinnedRTP) > 1e-12 || result.PinnedRTP <= 0 {
+			t.Errorf("brute force=%v: expected pinned RTP %v, got %v", bruteForce, expectedPinnedRTP, result.PinnedRTP)
+		}
+		if result.OutcomeDetails[1].BucketName != "pinned" || result.OutcomeDetails[7].BucketName != "pinned" {
+			t.Errorf("brute force=%v: pinned outcomes not marked: %q, %q", bruteForce,
+				result.OutcomeDetails[1].BucketName, result.OutcomeDetails[7].BucketName)
+		}
+		if result.LossResult == nil || result.LossResult.OutcomeCount != 1 {
+			t.Errorf("brute force=%v: expected one re-weighted loss outcome, got %+v", bruteForce, result.LossResult)
+		}
+	}
+
+	if err := ValidatePinnedOutcomes(table, []PinnedOutcome{{SimID: 42, Weight: 1}}); err == nil {
+		t.Error("expected error for unknown simID")
+	}
+	if err := ValidatePinnedOutcomes(table, []PinnedOutcome{{SimID: 7, Weight: 1}, {SimID: 7, Weight: 2}}); err == nil {
+		t.Error("expected error for outcome pinned twice")
+	}
+}
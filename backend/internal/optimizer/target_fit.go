@@ -0,0 +1,317 @@
+package optimizer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"lutexplorer/internal/common"
+
+	"stakergs"
+)
+
+// TargetBand is one row of an empirical target distribution: the probability
+// that a spin pays within [MinPayout, MaxPayout) (bet multiples, normalized by
+// mode cost). MaxPayout 0 means unbounded; MinPayout = MaxPayout = 0 matches
+// losses. As with bucket configs, the last band includes its max.
+type TargetBand struct {
+	Name        string  `json:"name"`
+	MinPayout   float64 `json:"min_payout"`
+	MaxPayout   float64 `json:"max_payout"`
+	Probability float64 `json:"probability"`
+}
+
+// TargetBandFit reports how close the fitted LUT gets to one target band.
+type TargetBandFit struct {
+	TargetBand
+	OutcomeCount        int     `json:"outcome_count"`
+	OriginalProbability float64 `json:"original_probability"`
+	ActualProbability   float64 `json:"actual_probability"`
+	Deviation           float64 `json:"deviation"`          // actual - target
+	RelativeDeviation   float64 `json:"relative_deviation"` // deviation / target, 0 if target is 0
+}
+
+// TargetFitResult is the outcome of fitting a LUT to a target distribution.
+type TargetFitResult struct {
+	OriginalRTP  float64         `json:"original_rtp"`
+	FinalRTP     float64         `json:"final_rtp"`
+	NewWeights   []uint64        `json:"new_weights"`
+	TotalWeight  uint64          `json:"total_weight"`
+	Bands        []TargetBandFit `json:"bands"`
+	MaxDeviation float64         `json:"max_deviation"` // largest |deviation| over all bands
+	// Outcomes matching no band share whatever probability the bands leave
+	UncoveredOutcomes    int      `json:"uncovered_outcomes"`
+	UncoveredProbability float64  `json:"uncovered_probability"`
+	Warnings             []string `json:"warnings,omitempty"`
+}
+
+// ParseTargetDistributionCSV reads a target distribution with a header row
+// naming the columns min_payout, max_payout and probability, plus an optional
+// name column. Column order is free; an empty max_payout means unbounded.
+//
+//	name,min_payout,max_payout,probability
+//	loss,0,0,0.72
+//	small,0.01,5,0.25
+//	big,5,,0.03
+func ParseTargetDistributionCSV(data []byte) ([]TargetBand, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty CSV")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := map[string]int{"name": -1, "min_payout": -1, "max_payout": -1, "probability": -1}
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, ok := columns[key]; ok {
+			columns[key] = i
+		}
+	}
+	for _, required := range []string{"min_payout", "max_payout", "probability"} {
+		if columns[required] < 0 {
+			return nil, fmt.Errorf("missing column %q (header: %s)", required, strings.Join(header, ","))
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i := columns[column]
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var bands []TargetBand
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		var band TargetBand
+		band.Name = field(record, "name")
+		if band.MinPayout, err = strconv.ParseFloat(field(record, "min_payout"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid min_payout: %w", line, err)
+		}
+		if s := field(record, "max_payout"); s != "" {
+			if band.MaxPayout, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid max_payout: %w", line, err)
+			}
+		}
+		if band.Probability, err = strconv.ParseFloat(field(record, "probability"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid probability: %w", line, err)
+		}
+		if band.Name == "" {
+			band.Name = fmt.Sprintf("band_%d", len(bands)+1)
+		}
+		bands = append(bands, band)
+	}
+
+	if err := ValidateTargetBands(bands); err != nil {
+		return nil, err
+	}
+	return bands, nil
+}
+
+// ValidateTargetBands checks ranges and that probabilities sum to at most 1.
+func ValidateTargetBands(bands []TargetBand) error {
+	if len(bands) == 0 {
+		return fmt.Errorf("at least one band is required")
+	}
+	var sum float64
+	for _, b := range bands {
+		if b.MinPayout < 0 {
+			return fmt.Errorf("band '%s': min_payout must be non-negative", b.Name)
+		}
+		if b.MaxPayout != 0 && b.MaxPayout < b.MinPayout {
+			return fmt.Errorf("band '%s': max_payout must be at least min_payout", b.Name)
+		}
+		if b.Probability < 0 || b.Probability > 1 {
+			return fmt.Errorf("band '%s': probability must be within [0, 1]", b.Name)
+		}
+		sum += b.Probability
+	}
+	if sum > 1+1e-6 {
+		return fmt.Errorf("band probabilities sum to %.6f, must be at most 1", sum)
+	}
+	return nil
+}
+
+// contains reports whether a normalized payout falls in the band.
+func (b TargetBand) contains(payout float64, last bool) bool {
+	if b.MinPayout == 0 && b.MaxPayout == 0 {
+		return payout == 0
+	}
+	if payout < b.MinPayout {
+		return false
+	}
+	if b.MaxPayout == 0 {
+		return true
+	}
+	if last {
+		return payout <= b.MaxPayout
+	}
+	return payout < b.MaxPayout
+}
+
+// FitTargetDistribution reweights a table so the probability of each payout
+// band matches the target as closely as possible. Within a band, outcomes
+// keep their current relative weights (uniform if they were all zero), so the
+// shape of the maths is preserved while the band totals move.
+func FitTargetDistribution(table *stakergs.LookupTable, bands []TargetBand) (*TargetFitResult, error) {
+	n := len(table.Outcomes)
+	if n == 0 {
+		return nil, fmt.Errorf("empty table")
+	}
+	if err := ValidateTargetBands(bands); err != nil {
+		return nil, err
+	}
+
+	cost := table.Cost
+	if cost <= 0 {
+		cost = 1.0
+	}
+
+	payouts := make([]float64, n)
+	originalWeights := make([]uint64, n)
+	for i, outcome := range table.Outcomes {
+		payouts[i] = float64(outcome.Payout) / 100.0 / cost
+		originalWeights[i] = outcome.Weight
+	}
+	originalTotal := sumUint64(originalWeights)
+
+	// Assign each outcome to the first matching band
+	members := make([][]int, len(bands))
+	var uncovered []int
+	for i, payout := range payouts {
+		assigned := false
+		for j, b := range bands {
+			if b.contains(payout, j == len(bands)-1) {
+				members[j] = append(members[j], i)
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			uncovered = append(uncovered, i)
+		}
+	}
+
+	result := &TargetFitResult{
+		OriginalRTP:       calculateRTPFromWeights(originalWeights, payouts),
+		UncoveredOutcomes: len(uncovered),
+	}
+
+	// Probability mass each group should receive. Bands without outcomes
+	// cannot be fitted; their mass is spread over the rest by normalization.
+	shares := make([]float64, len(bands))
+	var covered float64
+	for j, b := range bands {
+		if len(members[j]) == 0 {
+			if b.Probability > 0 {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("band '%s' has no matching outcomes", b.Name))
+			}
+			continue
+		}
+		shares[j] = b.Probability
+		covered += b.Probability
+	}
+	uncoveredShare := 0.0
+	if len(uncovered) > 0 {
+		uncoveredShare = 1 - covered
+		if uncoveredShare <= 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%d outcomes match no band but the bands leave no probability for them; they keep the minimum weight", len(uncovered)))
+			uncoveredShare = 0
+		}
+	}
+	total := covered + uncoveredShare
+	if total == 0 {
+		return nil, fmt.Errorf("no outcome falls in a band with non-zero probability")
+	}
+	if math.Abs(total-1) > 1e-9 {
+		if len(uncovered) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("fittable band probabilities sum to %.6f; scaled to 1", total))
+		}
+		for j := range shares {
+			shares[j] /= total
+		}
+		uncoveredShare /= total
+	}
+
+	// Spread each group's share over its outcomes by their original weights
+	newWeights := make([]uint64, n)
+	distribute := func(indices []int, share float64) {
+		var groupWeight uint64
+		for _, i := range indices {
+			groupWeight += originalWeights[i]
+		}
+		for _, i := range indices {
+			rel := 1.0 / float64(len(indices))
+			if groupWeight > 0 {
+				rel = float64(originalWeights[i]) / float64(groupWeight)
+			}
+			w := uint64(math.Round(share * rel * float64(common.BaseWeight)))
+			if w < 1 {
+				w = 1
+			}
+			newWeights[i] = w
+		}
+	}
+	for j := range bands {
+		distribute(members[j], shares[j])
+	}
+	distribute(uncovered, uncoveredShare)
+
+	totalWeight := sumUint64(newWeights)
+	result.NewWeights = newWeights
+	result.TotalWeight = totalWeight
+	result.FinalRTP = calculateRTPFromWeights(newWeights, payouts)
+
+	// Per-band report
+	for j, b := range bands {
+		var origWeight, newWeight uint64
+		for _, i := range members[j] {
+			origWeight += originalWeights[i]
+			newWeight += newWeights[i]
+		}
+		fit := TargetBandFit{
+			TargetBand:        b,
+			OutcomeCount:      len(members[j]),
+			ActualProbability: float64(newWeight) / float64(totalWeight),
+		}
+		if originalTotal > 0 {
+			fit.OriginalProbability = float64(origWeight) / float64(originalTotal)
+		}
+		fit.Deviation = fit.ActualProbability - b.Probability
+		if b.Probability > 0 {
+			fit.RelativeDeviation = fit.Deviation / b.Probability
+		}
+		if d := math.Abs(fit.Deviation); d > result.MaxDeviation {
+			result.MaxDeviation = d
+		}
+		result.Bands = append(result.Bands, fit)
+	}
+	var uncoveredWeight uint64
+	for _, i := range uncovered {
+		uncoveredWeight += newWeights[i]
+	}
+	result.UncoveredProbability = float64(uncoveredWeight) / float64(totalWeight)
+
+	return result, nil
+}
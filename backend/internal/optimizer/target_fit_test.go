@@ -0,0 +1,87 @@
+package optimizer
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"stakergs"
+)
+
+// ============================================================================
+// Target Distribution Fit Tests
+// ============================================================================
+
+func TestParseTargetDistributionCSV(t *testing.T) {
+	data := "probability, min_payout, max_payout, name\n" +
+		"0.7,0,0,loss\n" +
+		"0.25,0.01,5,small\n" +
+		"\n" +
+		"0.05,5,,big\n"
+
+	bands, err := ParseTargetDistributionCSV([]byte(data))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(bands) != 3 {
+		t.Fatalf("expected 3 bands, got %d", len(bands))
+	}
+	if bands[2].Name != "big" || bands[2].MinPayout != 5 || bands[2].MaxPayout != 0 || bands[2].Probability != 0.05 {
+		t.Errorf("unexpected last band: %+v", bands[2])
+	}
+
+	if _, err := ParseTargetDistributionCSV([]byte("min_payout,probability\n0,1\n")); err == nil {
+		t.Error("expected error for missing max_payout column")
+	}
+	if _, err := ParseTargetDistributionCSV([]byte("min_payout,max_payout,probability\n0,0,0.8\n1,,0.3\n")); err == nil || !strings.Contains(err.Error(), "sum") {
+		t.Errorf("expected probability sum error, got %v", err)
+	}
+}
+
+func TestFitTargetDistribution(t *testing.T) {
+	table := &stakergs.LookupTable{
+		Mode: "test",
+		Cost: 1.0,
+		Outcomes: []stakergs.Outcome{
+			{SimID: 0, Weight: 500, Payout: 0},    // loss
+			{SimID: 1, Weight: 300, Payout: 50},   // 0.5x
+			{SimID: 2, Weight: 100, Payout: 200},  // 2x
+			{SimID: 3, Weight: 50, Payout: 1000},  // 10x
+			{SimID: 4, Weight: 50, Payout: 2000},  // 20x
+			{SimID: 5, Weight: 1, Payout: 100000}, // 1000x, matches no band
+		},
+	}
+	bands := []TargetBand{
+		{Name: "loss", MinPayout: 0, MaxPayout: 0, Probability: 0.6},
+		{Name: "small", MinPayout: 0.01, MaxPayout: 5, Probability: 0.3},
+		{Name: "big", MinPayout: 5, MaxPayout: 50, Probability: 0.0999},
+		{Name: "huge", MinPayout: 5000, Probability: 0.0001},
+	}
+
+	result, err := FitTargetDistribution(table, bands)
+	if err != nil {
+		t.Fatalf("fit failed: %v", err)
+	}
+
+	// "huge" has no outcomes: its mass goes to the 1000x outcome
+	if result.UncoveredOutcomes != 1 {
+		t.Errorf("expected 1 uncovered outcome, got %d", result.UncoveredOutcomes)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("expected a warning for the empty band, got %v", result.Warnings)
+	}
+	for _, b := range result.Bands[:3] {
+		if math.Abs(b.Deviation) > 1e-9 {
+			t.Errorf("band %s: target %.4f, got %.6f", b.Name, b.Probability, b.ActualProbability)
+		}
+	}
+	if math.Abs(result.UncoveredProbability-0.0001) > 1e-9 {
+		t.Errorf("expected uncovered probability 0.0001, got %.6f", result.UncoveredProbability)
+	}
+
+	// Relative weights inside a band are preserved (10x and 20x were equal)
+	if result.NewWeights[3] != result.NewWeights[4] {
+		t.Errorf("expected equal weights within band, got %d and %d", result.NewWeights[3], result.NewWeights[4])
+	}
+	t.Logf("RTP %.4f -> %.4f, max deviation %.2e", result.OriginalRTP, result.FinalRTP, result.MaxDeviation)
+}
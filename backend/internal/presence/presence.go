@@ -0,0 +1,236 @@
+// Package presence tracks which clients are using a shared backend and the
+// soft locks they hold, so a team working against one server can see that
+// someone else is optimizing a mode before overwriting their work.
+//
+// Locks are advisory: a conflicting destructive request is refused until
+// the caller forces it, and locks expire unless their holder refreshes them.
+package presence
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a lock lasts without being refreshed.
+const DefaultTTL = 5 * time.Minute
+
+// IdleAfter is how long a client stays listed after its last request.
+const IdleAfter = 5 * time.Minute
+
+// Anonymous names clients that did not identify themselves.
+const Anonymous = "anonymous"
+
+// Lock is a soft lock on a mode, or on the whole library if Mode is empty.
+type Lock struct {
+	Mode      string    `json:"mode,omitempty"`
+	Holder    string    `json:"holder"`
+	Action    string    `json:"action"`
+	Since     time.Time `json:"since"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Message   string    `json:"message"`
+}
+
+// Target describes what the lock covers, e.g. "mode base".
+func (l Lock) Target() string {
+	if l.Mode == "" {
+		return "the library"
+	}
+	return "mode " + l.Mode
+}
+
+// Client is a client seen recently.
+type Client struct {
+	Name     string    `json:"name"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Event reports a lock change.
+type Event struct {
+	Acquired bool // false when released or expired
+	Lock     Lock
+}
+
+// ConflictError is returned when another client holds a lock.
+type ConflictError struct {
+	Lock Lock
+}
+
+func (e *ConflictError) Error() string {
+	return e.Lock.Message
+}
+
+// Tracker holds the presence and lock state of a server.
+type Tracker struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	locks   map[string]*Lock // by mode; "" is the library lock
+	clients map[string]time.Time
+	notify  func(Event)
+}
+
+// NewTracker creates a tracker. notify, if set, is called (without the
+// tracker's lock held) for every lock acquired, released or expired.
+func NewTracker(ttl time.Duration, notify func(Event)) *Tracker {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Tracker{
+		ttl:     ttl,
+		locks:   make(map[string]*Lock),
+		clients: make(map[string]time.Time),
+		notify:  notify,
+	}
+}
+
+// Seen records a request from a client and reports whether the client was
+// not already listed.
+func (t *Tracker) Seen(name string) bool {
+	if name == "" {
+		name = Anonymous
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last, ok := t.clients[name]
+	now := time.Now()
+	t.clients[name] = now
+	return !ok || now.Sub(last) > IdleAfter
+}
+
+// Clients returns the clients seen within IdleAfter, most recent first.
+func (t *Tracker) Clients() []Client {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	clients := make([]Client, 0, len(t.clients))
+	for name, last := range t.clients {
+		if now.Sub(last) > IdleAfter {
+			delete(t.clients, name)
+			continue
+		}
+		clients = append(clients, Client{Name: name, LastSeen: last})
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].LastSeen.After(clients[j].LastSeen) })
+	return clients
+}
+
+// Acquire takes or refreshes the lock on mode ("" for the library) for
+// holder. If another client holds it, a *ConflictError is returned unless
+// force is set, in which case the lock changes hands.
+func (t *Tracker) Acquire(mode, holder, action string, force bool) (Lock, error) {
+	if holder == "" {
+		return Lock{}, fmt.Errorf("a client name is required to take a lock")
+	}
+	if action == "" {
+		action = "editing"
+	}
+
+	t.mu.Lock()
+	events := t.expireLocked()
+	if cur, ok := t.locks[mode]; ok && cur.Holder != holder && !force {
+		conflict := *cur
+		t.mu.Unlock()
+		t.emit(events)
+		return Lock{}, &ConflictError{Lock: conflict}
+	}
+	now := time.Now()
+	lock := &Lock{Mode: mode, Holder: holder, Action: action, Since: now}
+	if cur, ok := t.locks[mode]; ok && cur.Holder == holder {
+		lock.Since = cur.Since // a refresh keeps the original start
+	}
+	lock.ExpiresAt = now.Add(t.ttl)
+	lock.Message = fmt.Sprintf("%s is currently %s %s", holder, action, lock.Target())
+	t.locks[mode] = lock
+	events = append(events, Event{Acquired: true, Lock: *lock})
+	t.mu.Unlock()
+
+	t.emit(events)
+	return *lock, nil
+}
+
+// Release drops the lock on mode. Only its holder may release it unless
+// force is set. Releasing a lock that is not held is not an error.
+func (t *Tracker) Release(mode, holder string, force bool) error {
+	t.mu.Lock()
+	events := t.expireLocked()
+	cur, ok := t.locks[mode]
+	if ok && cur.Holder != holder && !force {
+		conflict := *cur
+		t.mu.Unlock()
+		t.emit(events)
+		return &ConflictError{Lock: conflict}
+	}
+	if ok {
+		delete(t.locks, mode)
+		events = append(events, Event{Lock: *cur})
+	}
+	t.mu.Unlock()
+
+	t.emit(events)
+	return nil
+}
+
+// Check returns a *ConflictError if a client other than holder holds a
+// lock that a destructive action on mode would override: the mode's lock or
+// the library lock. An empty mode means the action affects the whole
+// library, so any lock held by someone else conflicts.
+func (t *Tracker) Check(mode, holder string) error {
+	t.mu.Lock()
+	events := t.expireLocked()
+	var conflict *Lock
+	for m, lock := range t.locks {
+		if lock.Holder == holder {
+			continue
+		}
+		if mode == "" || m == "" || m == mode {
+			conflict = lock
+			break
+		}
+	}
+	var err error
+	if conflict != nil {
+		err = &ConflictError{Lock: *conflict}
+	}
+	t.mu.Unlock()
+
+	t.emit(events)
+	return err
+}
+
+// Locks returns the current locks, library lock first, then by mode.
+func (t *Tracker) Locks() []Lock {
+	t.mu.Lock()
+	events := t.expireLocked()
+	locks := make([]Lock, 0, len(t.locks))
+	for _, lock := range t.locks {
+		locks = append(locks, *lock)
+	}
+	t.mu.Unlock()
+
+	t.emit(events)
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Mode < locks[j].Mode })
+	return locks
+}
+
+// expireLocked drops expired locks. Must be called with t.mu held.
+func (t *Tracker) expireLocked() []Event {
+	var events []Event
+	now := time.Now()
+	for mode, lock := range t.locks {
+		if now.After(lock.ExpiresAt) {
+			delete(t.locks, mode)
+			events = append(events, Event{Lock: *lock})
+		}
+	}
+	return events
+}
+
+func (t *Tracker) emit(events []Event) {
+	if t.notify == nil {
+		return
+	}
+	for _, e := range events {
+		t.notify(e)
+	}
+}
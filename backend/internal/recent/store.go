@@ -0,0 +1,137 @@
+// Package recent remembers recently opened libraries so they can be
+// reopened from a "recent games" list.
+package recent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MaxEntries is how many libraries are remembered.
+const MaxEntries = 10
+
+// Library is a recently opened library.
+type Library struct {
+	Path      string    `json:"path"`
+	IndexPath string    `json:"index_path"`
+	GameID    string    `json:"game_id,omitempty"`
+	Modes     []string  `json:"modes"`
+	OpenedAt  time.Time `json:"opened_at"`
+}
+
+// Store keeps the recent libraries in one JSON file, most recent first.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates a store backed by the given file.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the recent libraries file in the user config folder.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "lutexplorer", "recent_libraries.json")
+}
+
+// List returns the recent libraries, most recent first.
+func (s *Store) List() ([]Library, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked()
+}
+
+// Add moves a library to the top of the list, dropping the oldest entries
+// beyond MaxEntries.
+func (s *Store) Add(lib Library) error {
+	if lib.OpenedAt.IsZero() {
+		lib.OpenedAt = time.Now().UTC()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	result := []Library{lib}
+	for _, l := range all {
+		if samePath(l.Path, lib.Path) {
+			continue
+		}
+		result = append(result, l)
+	}
+	if len(result) > MaxEntries {
+		result = result[:MaxEntries]
+	}
+	return s.writeLocked(result)
+}
+
+// Remove forgets a library. Unknown paths are ignored.
+func (s *Store) Remove(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	result := all[:0]
+	for _, l := range all {
+		if !samePath(l.Path, path) {
+			result = append(result, l)
+		}
+	}
+	return s.writeLocked(result)
+}
+
+// readLocked loads the list; a missing file means none. Caller must hold s.mu.
+func (s *Store) readLocked() ([]Library, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Library{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var all []Library
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("corrupt recent libraries file: %w", err)
+	}
+	return all, nil
+}
+
+// writeLocked replaces the file atomically. Caller must hold s.mu.
+func (s *Store) writeLocked(all []Library) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recent libraries: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config folder: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write recent libraries: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write recent libraries: %w", err)
+	}
+	return nil
+}
+
+func samePath(a, b string) bool {
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,324 @@
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// unitsPerCurrency converts currency amounts to LGS API units.
+const unitsPerCurrency = 1000000
+
+// StepResult is the outcome of one step.
+type StepResult struct {
+	Step       int    `json:"step"` // 1-based
+	Action     string `json:"action"`
+	Summary    string `json:"summary"`
+	Passed     bool   `json:"passed"`
+	Message    string `json:"message"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report is the result of a run.
+type Report struct {
+	Name    string       `json:"name"`
+	Session string       `json:"session"`
+	Passed  bool         `json:"passed"`
+	Steps   []StepResult `json:"steps"`
+	// Skipped counts the steps not run after a step failed to execute
+	Skipped int `json:"skipped"`
+	// Totals over all play steps, in currency units
+	Spins      int     `json:"spins"`
+	Wagered    float64 `json:"wagered"`
+	Won        float64 `json:"won"`
+	RTP        float64 `json:"rtp"`
+	Balance    float64 `json:"balance"`
+	DurationMs int64   `json:"duration_ms"`
+}
+
+// Text renders the report for a terminal, one line per step.
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Scenario %q (session %s)\n", r.Name, r.Session)
+	for _, s := range r.Steps {
+		mark := "PASS"
+		if !s.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&b, "  %s  %2d. %s", mark, s.Step, s.Summary)
+		if s.Message != "" {
+			fmt.Fprintf(&b, ": %s", s.Message)
+		}
+		b.WriteByte('\n')
+	}
+	if r.Skipped > 0 {
+		fmt.Fprintf(&b, "  %d step(s) skipped\n", r.Skipped)
+	}
+	result := "PASSED"
+	if !r.Passed {
+		result = "FAILED"
+	}
+	fmt.Fprintf(&b, "%s: %d spins, RTP %.2f%%, balance %.2f, %dms\n",
+		result, r.Spins, r.RTP*100, r.Balance, r.DurationMs)
+	return b.String()
+}
+
+// Runner executes scripts against an HTTP handler.
+type Runner struct {
+	handler http.Handler
+	// client is sent as X-Client-Name, so soft locks held by the caller do
+	// not block the script's reloads
+	client string
+}
+
+// NewRunner creates a runner sending requests to handler, identified as
+// client (may be empty).
+func NewRunner(handler http.Handler, client string) *Runner {
+	return &Runner{handler: handler, client: client}
+}
+
+// run holds the state of one script execution.
+type run struct {
+	*Runner
+	ctx     context.Context
+	script  *Script
+	wagered int64
+	won     int64
+	spins   int
+	balance int64
+}
+
+// Run executes a script. Steps run in order; a failed assertion is reported
+// and the run continues, while a step that cannot be executed (e.g. an
+// unknown mode or insufficient balance) ends the run. The script's session
+// starts with its scenario cleared.
+func (r *Runner) Run(ctx context.Context, script *Script) *Report {
+	start := time.Now()
+	state := &run{Runner: r, ctx: ctx, script: script}
+	report := &Report{Name: script.Name, Session: script.Session, Passed: true}
+
+	// A scenario left over from an earlier run would skew the plays
+	state.call(http.MethodDelete, "/lgs/scenario?sessionID="+url.QueryEscape(script.Session), nil, nil)
+
+	for i, step := range script.Steps {
+		if err := ctx.Err(); err != nil {
+			report.Passed = false
+			report.Skipped = len(script.Steps) - i
+			break
+		}
+		stepStart := time.Now()
+		message, passed, err := state.execute(step)
+		result := StepResult{
+			Step:       i + 1,
+			Action:     step.action(),
+			Summary:    step.describe(),
+			Passed:     passed && err == nil,
+			Message:    message,
+			DurationMs: time.Since(stepStart).Milliseconds(),
+		}
+		if err != nil {
+			result.Message = err.Error()
+		}
+		report.Steps = append(report.Steps, result)
+		if !result.Passed {
+			report.Passed = false
+		}
+		if err != nil {
+			report.Skipped = len(script.Steps) - i - 1
+			break
+		}
+	}
+
+	report.Spins = state.spins
+	report.Wagered = float64(state.wagered) / unitsPerCurrency
+	report.Won = float64(state.won) / unitsPerCurrency
+	report.RTP = state.rtp()
+	report.Balance = float64(state.balance) / unitsPerCurrency
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report
+}
+
+func (s *run) rtp() float64 {
+	if s.wagered == 0 {
+		return 0
+	}
+	return float64(s.won) / float64(s.wagered)
+}
+
+// execute runs one step. passed is false for a failed assertion; err is set
+// if the step could not be executed.
+func (s *run) execute(step Step) (message string, passed bool, err error) {
+	switch {
+	case step.SetBalance != nil:
+		units := int64(*step.SetBalance * unitsPerCurrency)
+		err = s.call(http.MethodPost, "/lgs/set-balance", map[string]interface{}{
+			"sessionID": s.script.Session,
+			"balance":   units,
+			"currency":  s.script.Currency,
+		}, nil)
+		if err != nil {
+			return "", false, err
+		}
+		s.balance = units
+		return "", true, nil
+
+	case step.Force != nil:
+		steps := make([]map[string]interface{}, len(step.Force))
+		for i, f := range step.Force {
+			steps[i] = map[string]interface{}{"mode": f.Mode, "repeat": f.Repeat}
+			if f.SimID != nil {
+				steps[i]["simID"] = *f.SimID
+			}
+			if f.MinPayout != nil {
+				steps[i]["minPayout"] = *f.MinPayout
+			}
+			if f.MaxPayout != nil {
+				steps[i]["maxPayout"] = *f.MaxPayout
+			}
+		}
+		var resp struct {
+			Spins int `json:"spins"`
+		}
+		err = s.call(http.MethodPost, "/lgs/scenario", map[string]interface{}{
+			"sessionID": s.script.Session,
+			"steps":     steps,
+			"append":    true,
+		}, &resp)
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("%d plays scripted", resp.Spins), true, nil
+
+	case step.Play != nil:
+		return s.play(*step.Play)
+
+	case step.AssertRTP != nil:
+		if s.spins == 0 {
+			return "", false, fmt.Errorf("no spins played yet")
+		}
+		rtp := s.rtp()
+		r := step.AssertRTP
+		passed = (r.Min == nil || rtp >= *r.Min) && (r.Max == nil || rtp <= *r.Max)
+		return fmt.Sprintf("RTP %.2f%% over %d spins", rtp*100, s.spins), passed, nil
+
+	case step.Reload:
+		if err := s.call(http.MethodPost, "/api/reload", nil, nil); err != nil {
+			return "", false, err
+		}
+		return "", true, nil
+	}
+	return "", false, fmt.Errorf("empty step")
+}
+
+// play plays spins one at a time, so forced outcomes apply as in a game.
+func (s *run) play(p PlayStep) (string, bool, error) {
+	amount := int64(p.Bet * unitsPerCurrency)
+	var wagered, won int64
+	var maxWin float64
+	for i := 0; i < p.Spins; i++ {
+		if err := s.ctx.Err(); err != nil {
+			return "", false, err
+		}
+		var resp struct {
+			Balance struct {
+				Amount int64 `json:"amount"`
+			} `json:"balance"`
+			Round struct {
+				Amount           int64   `json:"amount"`
+				Payout           int64   `json:"payout"`
+				PayoutMultiplier float64 `json:"payoutMultiplier"`
+			} `json:"round"`
+		}
+		err := s.call(http.MethodPost, "/wallet/play", map[string]interface{}{
+			"sessionID": s.script.Session,
+			"mode":      p.Mode,
+			"amount":    amount,
+			"currency":  s.script.Currency,
+		}, &resp)
+		if err != nil {
+			return "", false, fmt.Errorf("spin %d: %w", i+1, err)
+		}
+		// End the round like a game does; rounds with a feature are paid then
+		var end struct {
+			Balance struct {
+				Amount int64 `json:"amount"`
+			} `json:"balance"`
+		}
+		err = s.call(http.MethodPost, "/wallet/end-round", map[string]interface{}{
+			"sessionID": s.script.Session,
+		}, &end)
+		if err != nil {
+			return "", false, fmt.Errorf("spin %d: %w", i+1, err)
+		}
+		wagered += resp.Round.Amount
+		won += resp.Round.Payout
+		maxWin = max(maxWin, resp.Round.PayoutMultiplier)
+		s.balance = end.Balance.Amount
+		s.spins++
+		s.wagered += resp.Round.Amount
+		s.won += resp.Round.Payout
+	}
+
+	var rtp float64
+	if wagered > 0 {
+		rtp = float64(won) / float64(wagered)
+	}
+	return fmt.Sprintf("RTP %.2f%%, max win %gx, balance %.2f",
+		rtp*100, maxWin, float64(s.balance)/unitsPerCurrency), true, nil
+}
+
+// call sends a JSON request to the handler and decodes the response into
+// out (if not nil). Non-2xx responses become errors carrying the server's
+// error message.
+func (s *run) call(method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(s.ctx, method, path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.client != "" {
+		req.Header.Set("X-Client-Name", s.client)
+	}
+
+	rec := &responseBuffer{header: make(http.Header), status: http.StatusOK}
+	s.handler.ServeHTTP(rec, req)
+
+	if rec.status < 200 || rec.status > 299 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(rec.body.Bytes(), &e) == nil && e.Error != "" {
+			return fmt.Errorf("%s %s: %s", method, path, e.Error)
+		}
+		return fmt.Errorf("%s %s: status %d", method, path, rec.status)
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.body.Bytes(), out); err != nil {
+			return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// responseBuffer is an in-memory http.ResponseWriter.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseBuffer) Header() http.Header         { return w.header }
+func (w *responseBuffer) WriteHeader(status int)      { w.status = status }
+func (w *responseBuffer) Write(p []byte) (int, error) { return w.body.Write(p) }
@@ -0,0 +1,248 @@
+// Package scenario runs scripted end-to-end flows against the server:
+// set a balance, force outcomes, play spins, assert the RTP and reload the
+// library. Scripts are YAML and run in-process against the server's own
+// HTTP handler, so they exercise the same LGS and API routes a game
+// front-end does and double as integration tests for it.
+//
+// A script looks like:
+//
+//	name: bonus flow
+//	session: qa-demo
+//	mode: base
+//	bet: 1
+//	steps:
+//	  - set_balance: 100
+//	  - force:
+//	      - {max_payout: 0, repeat: 3}
+//	      - {min_payout: 50}
+//	  - play: 4
+//	  - play: {spins: 500, mode: bonus}
+//	  - assert_rtp: {min: 0.5, max: 3}
+//	  - reload: true
+package scenario
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxSpins limits the spins of one play step.
+const MaxSpins = 100000
+
+// Script is a parsed scenario.
+type Script struct {
+	Name string `yaml:"name"`
+	// Session is the LGS session the script plays in (default "scenario")
+	Session string `yaml:"session"`
+	// Mode, Bet and Currency are the defaults of steps that do not set them.
+	// Bet and balances are in currency units, e.g. 1.5 = $1.50.
+	Mode     string  `yaml:"mode"`
+	Bet      float64 `yaml:"bet"`
+	Currency string  `yaml:"currency"`
+	Steps    []Step  `yaml:"steps"`
+}
+
+// Step is one action of a script; exactly one field is set.
+type Step struct {
+	SetBalance *float64     `yaml:"set_balance"`
+	Force      []ForceStep  `yaml:"force"`
+	Play       *PlayStep    `yaml:"play"`
+	AssertRTP  *AssertRange `yaml:"assert_rtp"`
+	Reload     bool         `yaml:"reload"`
+}
+
+// ForceStep scripts the outcome of the next plays in a mode: a fixed simID
+// or a payout multiplier range (see lgs.ScenarioStep).
+type ForceStep struct {
+	Mode      string   `yaml:"mode"`
+	SimID     *int     `yaml:"sim_id"`
+	MinPayout *float64 `yaml:"min_payout"`
+	MaxPayout *float64 `yaml:"max_payout"`
+	Repeat    int      `yaml:"repeat"`
+}
+
+// PlayStep plays spins one by one through /wallet/play. It may be written
+// as a number of spins ("play: 10") or as a mapping.
+type PlayStep struct {
+	Spins int     `yaml:"spins"`
+	Mode  string  `yaml:"mode"`
+	Bet   float64 `yaml:"bet"`
+}
+
+// UnmarshalYAML accepts "play: 10" as well as the mapping form.
+func (p *PlayStep) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&p.Spins)
+	}
+	type plain PlayStep
+	return node.Decode((*plain)(p))
+}
+
+// AssertRange bounds the RTP of all spins the script played so far, as a
+// fraction (0.96 = 96%). A nil bound is open.
+type AssertRange struct {
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+// action names the step's action.
+func (s Step) action() string {
+	switch {
+	case s.SetBalance != nil:
+		return "set_balance"
+	case s.Force != nil:
+		return "force"
+	case s.Play != nil:
+		return "play"
+	case s.AssertRTP != nil:
+		return "assert_rtp"
+	case s.Reload:
+		return "reload"
+	}
+	return ""
+}
+
+// count returns the number of actions set on the step.
+func (s Step) count() int {
+	n := 0
+	for _, set := range []bool{s.SetBalance != nil, s.Force != nil, s.Play != nil, s.AssertRTP != nil, s.Reload} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// Parse reads and validates a YAML script. Unknown keys are errors, so a
+// misspelt action fails instead of being skipped.
+func Parse(data []byte) (*Script, error) {
+	var script Script
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&script); err != nil {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+	if script.Session == "" {
+		script.Session = "scenario"
+	}
+	if script.Bet == 0 {
+		script.Bet = 1
+	}
+	if script.Currency == "" {
+		script.Currency = "USD"
+	}
+	if script.Bet < 0 {
+		return nil, fmt.Errorf("bet must be positive")
+	}
+	if len(script.Steps) == 0 {
+		return nil, fmt.Errorf("scenario has no steps")
+	}
+
+	for i := range script.Steps {
+		if err := script.validateStep(&script.Steps[i]); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return &script, nil
+}
+
+// validateStep checks a step and fills in the script's defaults.
+func (s *Script) validateStep(step *Step) error {
+	if step.count() != 1 {
+		return fmt.Errorf("want exactly one of set_balance, force, play, assert_rtp, reload")
+	}
+	switch {
+	case step.SetBalance != nil:
+		if *step.SetBalance < 0 {
+			return fmt.Errorf("balance must not be negative")
+		}
+	case step.Force != nil:
+		if len(step.Force) == 0 {
+			return fmt.Errorf("force needs at least one outcome")
+		}
+		for i := range step.Force {
+			if step.Force[i].Mode == "" {
+				step.Force[i].Mode = s.Mode
+			}
+			if step.Force[i].Mode == "" {
+				return fmt.Errorf("force %d: mode is required (set it on the outcome or the script)", i+1)
+			}
+		}
+	case step.Play != nil:
+		if step.Play.Mode == "" {
+			step.Play.Mode = s.Mode
+		}
+		if step.Play.Mode == "" {
+			return fmt.Errorf("play: mode is required (set it on the step or the script)")
+		}
+		if step.Play.Bet == 0 {
+			step.Play.Bet = s.Bet
+		}
+		if step.Play.Bet < 0 {
+			return fmt.Errorf("play: bet must be positive")
+		}
+		if step.Play.Spins < 1 || step.Play.Spins > MaxSpins {
+			return fmt.Errorf("play: spins must be between 1 and %d", MaxSpins)
+		}
+	case step.AssertRTP != nil:
+		r := step.AssertRTP
+		if r.Min == nil && r.Max == nil {
+			return fmt.Errorf("assert_rtp needs min, max or both")
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return fmt.Errorf("assert_rtp: min must not exceed max")
+		}
+	}
+	return nil
+}
+
+// describe summarizes a step for reports.
+func (s Step) describe() string {
+	switch {
+	case s.SetBalance != nil:
+		return fmt.Sprintf("set balance to %g", *s.SetBalance)
+	case s.Force != nil:
+		parts := make([]string, len(s.Force))
+		for i, f := range s.Force {
+			var what string
+			switch {
+			case f.SimID != nil:
+				what = fmt.Sprintf("simID %d", *f.SimID)
+			case f.MaxPayout != nil && *f.MaxPayout == 0:
+				what = "loss"
+			case f.MinPayout != nil && f.MaxPayout != nil:
+				what = fmt.Sprintf("%gx-%gx", *f.MinPayout, *f.MaxPayout)
+			case f.MinPayout != nil:
+				what = fmt.Sprintf(">= %gx", *f.MinPayout)
+			default:
+				what = fmt.Sprintf("<= %gx", *f.MaxPayout)
+			}
+			if f.Repeat > 1 {
+				what = fmt.Sprintf("%d x %s", f.Repeat, what)
+			}
+			parts[i] = what + " in " + f.Mode
+		}
+		return "force " + strings.Join(parts, ", ")
+	case s.Play != nil:
+		return fmt.Sprintf("play %d spins in %s at %g", s.Play.Spins, s.Play.Mode, s.Play.Bet)
+	case s.AssertRTP != nil:
+		return "assert RTP " + s.AssertRTP.describe()
+	case s.Reload:
+		return "reload library"
+	}
+	return ""
+}
+
+func (r AssertRange) describe() string {
+	switch {
+	case r.Min != nil && r.Max != nil:
+		return fmt.Sprintf("in [%.2f%%, %.2f%%]", *r.Min*100, *r.Max*100)
+	case r.Min != nil:
+		return fmt.Sprintf(">= %.2f%%", *r.Min*100)
+	default:
+		return fmt.Sprintf("<= %.2f%%", *r.Max*100)
+	}
+}
@@ -0,0 +1,306 @@
+// Package selftest checks that a library is fit to serve before testers or
+// CI rely on it. For every mode it checks that the lookup table loaded,
+// that the sampler draws according to the table weights, and that the
+// books line up with the table: the book read for a simID carries that id
+// and the same payout. A misaligned events offset shows up as books that
+// belong to the neighbouring simID.
+package selftest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"lutexplorer/internal/lut"
+	"stakergs"
+)
+
+// Defaults of Options.
+const (
+	DefaultSpins = 5000
+	DefaultBooks = 20
+	DefaultSeed  = 1
+	MaxSpins     = 1000000
+)
+
+// Check names.
+const (
+	CheckTable    = "table"
+	CheckSampling = "sampling"
+	CheckEvents   = "events"
+)
+
+// Options tune a run; zero values mean the defaults.
+type Options struct {
+	// Spins drawn per mode for the sampling check
+	Spins int
+	// Books read per mode for the events check, including the first and
+	// last simID
+	Books int
+	// Seed of the sampling RNG; runs with the same seed draw the same
+	// outcomes, so CI results are repeatable
+	Seed int64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Spins <= 0 {
+		o.Spins = DefaultSpins
+	}
+	o.Spins = min(o.Spins, MaxSpins)
+	if o.Books <= 0 {
+		o.Books = DefaultBooks
+	}
+	if o.Seed == 0 {
+		o.Seed = DefaultSeed
+	}
+	return o
+}
+
+// Check is the result of one check of a mode.
+type Check struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message"`
+}
+
+// ModeResult is the result of the checks of a mode. A mode passes when
+// none of its checks failed; skipped checks do not fail it.
+type ModeResult struct {
+	Mode       string  `json:"mode"`
+	Passed     bool    `json:"passed"`
+	Checks     []Check `json:"checks"`
+	DurationMs int64   `json:"duration_ms"`
+}
+
+// Report is the result of a self-test.
+type Report struct {
+	Library    string       `json:"library"`
+	Passed     bool         `json:"passed"`
+	Modes      []ModeResult `json:"modes"`
+	Spins      int          `json:"spins"`
+	Seed       int64        `json:"seed"`
+	DurationMs int64        `json:"duration_ms"`
+}
+
+// Run checks every mode of the loader's library.
+func Run(loader *lut.Loader, opts Options) *Report {
+	start := time.Now()
+	opts = opts.withDefaults()
+	report := &Report{
+		Library: loader.BaseDir(),
+		Passed:  true,
+		Modes:   []ModeResult{},
+		Spins:   opts.Spins,
+		Seed:    opts.Seed,
+	}
+	if !loader.IsOpen() {
+		report.Passed = false
+		return report
+	}
+	modes := loader.ListModes()
+	sort.Strings(modes)
+	for _, mode := range modes {
+		result := checkMode(loader, mode, opts)
+		if !result.Passed {
+			report.Passed = false
+		}
+		report.Modes = append(report.Modes, result)
+	}
+	if len(report.Modes) == 0 {
+		report.Passed = false
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report
+}
+
+func checkMode(loader *lut.Loader, mode string, opts Options) ModeResult {
+	start := time.Now()
+	result := ModeResult{Mode: mode, Passed: true}
+	add := func(c Check) {
+		if !c.Passed && !c.Skipped {
+			result.Passed = false
+		}
+		result.Checks = append(result.Checks, c)
+	}
+
+	table, check := checkTable(loader, mode)
+	add(check)
+	if table == nil || !check.Passed {
+		add(Check{Name: CheckSampling, Skipped: true, Message: "table not usable"})
+		add(Check{Name: CheckEvents, Skipped: true, Message: "table not usable"})
+	} else {
+		counts, check := checkSampling(loader, table, opts)
+		add(check)
+		add(checkEvents(loader, mode, table, counts, opts.Books))
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// checkTable checks that the table loaded with outcomes to draw.
+func checkTable(loader *lut.Loader, mode string) (*stakergs.LookupTable, Check) {
+	check := Check{Name: CheckTable}
+	table, err := loader.GetMode(mode)
+	if err != nil {
+		check.Message = err.Error()
+		return nil, check
+	}
+	if len(table.Outcomes) == 0 {
+		check.Message = "lookup table has no outcomes"
+		return table, check
+	}
+	if table.TotalWeight() == 0 {
+		check.Message = "all outcome weights are zero"
+		return table, check
+	}
+	seen := make(map[int]bool, len(table.Outcomes))
+	for _, o := range table.Outcomes {
+		if seen[o.SimID] {
+			check.Message = fmt.Sprintf("simID %d is listed twice", o.SimID)
+			return table, check
+		}
+		seen[o.SimID] = true
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d outcomes, total weight %d", len(table.Outcomes), table.TotalWeight())
+	return table, check
+}
+
+// checkSampling draws from the mode's sampler and tests the draws against
+// the table weights. Returns the draw counts for the events check.
+func checkSampling(loader *lut.Loader, table *stakergs.LookupTable, opts Options) (map[int]int64, Check) {
+	check := Check{Name: CheckSampling}
+	sampler := loader.Sampler(table)
+	rng := rand.New(rand.NewSource(opts.Seed))
+	counts := make(map[int]int64)
+	for i := 0; i < opts.Spins; i++ {
+		counts[sampler.Sample(rng).SimID]++
+	}
+
+	sampling := lut.AnalyzeSampling(table, counts, 0)
+	switch {
+	case sampling.UnknownDraws > 0:
+		check.Message = fmt.Sprintf("%d draws returned simIDs not in the table", sampling.UnknownDraws)
+	case sampling.ChiSquare == nil:
+		check.Passed = true
+		check.Message = fmt.Sprintf("%d draws, too few payout bins for a chi-square test", sampling.Draws)
+	case sampling.ChiSquare.Biased:
+		check.Message = fmt.Sprintf("%d draws do not fit the weights (chi-square p=%.4f)", sampling.Draws, sampling.ChiSquare.PValue)
+	default:
+		check.Passed = true
+		check.Message = fmt.Sprintf("%d draws fit the weights (chi-square p=%.4f), %.1f%% of outcomes hit",
+			sampling.Draws, sampling.ChiSquare.PValue, sampling.Coverage*100)
+	}
+	return counts, check
+}
+
+// bookHeader is the part of a book the events check compares.
+type bookHeader struct {
+	ID               *int  `json:"id"`
+	PayoutMultiplier *uint `json:"payoutMultiplier"`
+}
+
+// checkEvents reads the books of the first and last simIDs and of the most
+// drawn ones and compares them with the table.
+func checkEvents(loader *lut.Loader, mode string, table *stakergs.LookupTable, counts map[int]int64, books int) Check {
+	check := Check{Name: CheckEvents}
+	config, err := loader.GetModeConfig(mode)
+	if err != nil {
+		check.Message = err.Error()
+		return check
+	}
+	if config.Events == "" {
+		check.Skipped = true
+		check.Message = "no events file configured"
+		return check
+	}
+
+	outcomes := bookSample(table, counts, books)
+	for _, o := range outcomes {
+		book, err := loader.EventsLoader().GetEventLazy(mode, config.Events, o.SimID, table.SimIDOffset)
+		if err != nil {
+			check.Message = fmt.Sprintf("simID %d: %v", o.SimID, err)
+			return check
+		}
+		var header bookHeader
+		if err := json.Unmarshal(book, &header); err != nil {
+			check.Message = fmt.Sprintf("simID %d: book is not valid JSON: %v", o.SimID, err)
+			return check
+		}
+		if header.ID != nil && *header.ID != o.SimID {
+			check.Message = fmt.Sprintf("simID %d: book has id %d (events offset %+d)", o.SimID, *header.ID, *header.ID-o.SimID)
+			return check
+		}
+		if header.PayoutMultiplier != nil && *header.PayoutMultiplier != o.Payout {
+			check.Message = fmt.Sprintf("simID %d: book pays %d, lookup table %d", o.SimID, *header.PayoutMultiplier, o.Payout)
+			return check
+		}
+	}
+	check.Passed = true
+	check.Message = fmt.Sprintf("%d books match the lookup table", len(outcomes))
+	return check
+}
+
+// bookSample picks up to n outcomes: the first and last of the table, then
+// the most drawn.
+func bookSample(table *stakergs.LookupTable, counts map[int]int64, n int) []stakergs.Outcome {
+	bySimID := make(map[int]stakergs.Outcome, len(table.Outcomes))
+	for _, o := range table.Outcomes {
+		bySimID[o.SimID] = o
+	}
+	drawn := make([]int, 0, len(counts))
+	for simID := range counts {
+		drawn = append(drawn, simID)
+	}
+	sort.Slice(drawn, func(i, j int) bool {
+		if counts[drawn[i]] != counts[drawn[j]] {
+			return counts[drawn[i]] > counts[drawn[j]]
+		}
+		return drawn[i] < drawn[j]
+	})
+
+	first, last := table.Outcomes[0], table.Outcomes[len(table.Outcomes)-1]
+	picked := make(map[int]bool, n)
+	sample := make([]stakergs.Outcome, 0, n)
+	for _, simID := range append([]int{first.SimID, last.SimID}, drawn...) {
+		if len(sample) == n {
+			break
+		}
+		if o, ok := bySimID[simID]; ok && !picked[simID] {
+			picked[simID] = true
+			sample = append(sample, o)
+		}
+	}
+	return sample
+}
+
+// Text renders the report for a terminal, one line per check.
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Self-test of %s (%d spins per mode, seed %d)\n", r.Library, r.Spins, r.Seed)
+	if len(r.Modes) == 0 {
+		b.WriteString("  FAIL  no modes loaded\n")
+	}
+	for _, m := range r.Modes {
+		fmt.Fprintf(&b, "  %s  mode %s\n", mark(m.Passed, false), m.Mode)
+		for _, c := range m.Checks {
+			fmt.Fprintf(&b, "    %s  %-8s %s\n", mark(c.Passed, c.Skipped), c.Name, c.Message)
+		}
+	}
+	fmt.Fprintf(&b, "%s in %dms\n", mark(r.Passed, false), r.DurationMs)
+	return b.String()
+}
+
+func mark(passed, skipped bool) string {
+	switch {
+	case skipped:
+		return "SKIP"
+	case passed:
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -0,0 +1,92 @@
+package simstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The database methods are called with s.mu held.
+
+func (s *Store) insert(record Record) error {
+	result, err := json.Marshal(record.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	_, err = s.db.Exec(`INSERT INTO simulations (id, library, kind, mode, created_at, request, result)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		record.ID, s.library, record.Kind, record.Mode,
+		record.CreatedAt.Format(time.RFC3339Nano), string(record.Request), string(result))
+	if err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) query(id string) (*Record, error) {
+	record := Record{ID: id}
+	var createdAt, request, result string
+	err := s.db.QueryRow(`SELECT kind, mode, created_at, request, result
+		FROM simulations WHERE id = ? AND library = ?`, id, s.library).
+		Scan(&record.Kind, &record.Mode, &createdAt, &request, &result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	record.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	record.Request = json.RawMessage(request)
+	if err := json.Unmarshal([]byte(result), &record.Result); err != nil {
+		return nil, fmt.Errorf("corrupt result %s: %w", id, err)
+	}
+	return &record, nil
+}
+
+func (s *Store) list(mode string) ([]Summary, error) {
+	rows, err := s.db.Query(`SELECT id FROM simulations
+		WHERE library = ? AND (? = '' OR mode = ?)`, s.library, mode, mode)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]Summary, 0, len(ids))
+	for _, id := range ids {
+		record, err := s.query(id)
+		if err != nil || record.Result == nil {
+			continue
+		}
+		summaries = append(summaries, record.summary())
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ID > summaries[j].ID
+	})
+	return summaries, nil
+}
+
+func (s *Store) remove(id string) error {
+	res, err := s.db.Exec(`DELETE FROM simulations WHERE id = ? AND library = ?`, id, s.library)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
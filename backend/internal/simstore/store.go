@@ -0,0 +1,244 @@
+// Package simstore persists simulation results so previous runs can be
+// reloaded and compared without recomputing them.
+package simstore
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"lutexplorer/internal/lut"
+)
+
+// DirName is the folder inside the library that holds stored results.
+const DirName = ".lutexplorer/simulations"
+
+// ErrNotFound is returned for an unknown simulation ID.
+var ErrNotFound = errors.New("simulation not found")
+
+var idRe = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+// Record is a stored simulation: the request that produced it and its full result.
+type Record struct {
+	ID        string                `json:"id"`
+	Kind      string                `json:"kind"` // "full" or "quick"
+	Mode      string                `json:"mode"`
+	CreatedAt time.Time             `json:"created_at"`
+	Request   json.RawMessage       `json:"request"`
+	Result    *lut.SimulationResult `json:"result"`
+}
+
+// Summary is the list view of a stored simulation.
+type Summary struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Mode       string    `json:"mode"`
+	CreatedAt  time.Time `json:"created_at"`
+	Seed       int64     `json:"seed"`
+	Spins      int       `json:"spins"`
+	Trials     int       `json:"trials"`
+	ActualRTP  float64   `json:"actual_rtp"`
+	FinalScore float64   `json:"final_score,omitempty"`
+}
+
+// Store keeps one JSON file per simulation in a directory, or rows of a
+// database table when one is set.
+type Store struct {
+	dir     string
+	library string
+	db      *sql.DB
+	mu      sync.Mutex
+}
+
+// New creates a store under the given library directory.
+func New(libraryDir string) *Store {
+	return &Store{dir: filepath.Join(libraryDir, filepath.FromSlash(DirName)), library: libraryDir}
+}
+
+// SetLibrary moves the store to another library directory.
+func (s *Store) SetLibrary(libraryDir string) {
+	s.mu.Lock()
+	s.dir = filepath.Join(libraryDir, filepath.FromSlash(DirName))
+	s.library = libraryDir
+	s.mu.Unlock()
+}
+
+// SetDB stores results in the simulations table of db (see datastore)
+// instead of files, keyed by library directory. Files already in the
+// library are no longer listed.
+func (s *Store) SetDB(db *sql.DB) {
+	s.mu.Lock()
+	s.db = db
+	s.mu.Unlock()
+}
+
+// Save stores a result with the request that produced it and returns its ID.
+// The ID is also set on the result.
+func (s *Store) Save(kind string, request interface{}, result *lut.SimulationResult) (string, error) {
+	reqJSON, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	result.ID = id
+
+	record := Record{
+		ID:        id,
+		Kind:      kind,
+		Mode:      result.Mode,
+		CreatedAt: time.Now().UTC(),
+		Request:   reqJSON,
+		Result:    result,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db != nil {
+		if err := s.insert(record); err != nil {
+			result.ID = ""
+			return "", err
+		}
+		return id, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		result.ID = ""
+		return "", fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		result.ID = ""
+		return "", fmt.Errorf("failed to create results folder: %w", err)
+	}
+	path := s.path(id)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		result.ID = ""
+		return "", fmt.Errorf("failed to write result: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		result.ID = ""
+		return "", fmt.Errorf("failed to write result: %w", err)
+	}
+	return id, nil
+}
+
+// Get loads a stored simulation.
+func (s *Store) Get(id string) (*Record, error) {
+	if !idRe.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	s.mu.Lock()
+	if s.db != nil {
+		defer s.mu.Unlock()
+		return s.query(id)
+	}
+	data, err := os.ReadFile(s.path(id))
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("corrupt result %s: %w", id, err)
+	}
+	return &record, nil
+}
+
+// List returns summaries of stored simulations, newest first. An empty mode lists all modes.
+func (s *Store) List(mode string) ([]Summary, error) {
+	s.mu.Lock()
+	if s.db != nil {
+		defer s.mu.Unlock()
+		return s.list(mode)
+	}
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]Summary, 0, len(files))
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".json")
+		record, err := s.Get(id)
+		if err != nil || record.Result == nil {
+			continue
+		}
+		if mode != "" && record.Mode != mode {
+			continue
+		}
+		summaries = append(summaries, record.summary())
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ID > summaries[j].ID
+	})
+	return summaries, nil
+}
+
+// Delete removes a stored simulation.
+func (s *Store) Delete(id string) error {
+	if !idRe.MatchString(id) {
+		return ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db != nil {
+		return s.remove(id)
+	}
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *Record) summary() Summary {
+	return Summary{
+		ID:         r.ID,
+		Kind:       r.Kind,
+		Mode:       r.Mode,
+		CreatedAt:  r.CreatedAt,
+		Seed:       r.Result.Seed,
+		Spins:      r.Result.Config.Spins,
+		Trials:     r.Result.Config.Trials,
+		ActualRTP:  r.Result.ActualRTP,
+		FinalScore: r.Result.FinalScore,
+	}
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// newID returns a sortable ID: UTC timestamp plus random suffix.
+func newID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,285 @@
+// Package statearchive bundles the runtime state of a backend into one zip
+// archive and restores it, so a configured environment (e.g. a demo with
+// sessions, bookmarks and stored simulations) can be cloned to another
+// machine that has the same library.
+package statearchive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"lutexplorer/internal/lgs"
+)
+
+// FormatVersion is the archive layout version written to the manifest.
+const FormatVersion = 1
+
+// StateDir is the per-library folder holding bookmarks, stored
+// simulations, event transforms and LGS cassettes.
+const StateDir = ".lutexplorer"
+
+// Archive entry names. Library files are stored under libraryPrefix with
+// their path relative to the library folder.
+const (
+	manifestEntry = "manifest.json"
+	sessionsEntry = "sessions.json"
+	variantsEntry = "variants.json"
+	libraryPrefix = "library/"
+)
+
+// MaxFileSize caps a single file read from an archive, so a corrupt or
+// hostile archive cannot fill the disk.
+const MaxFileSize = 512 << 20
+
+// Manifest describes an archive.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Library   string    `json:"library"` // folder name of the exporting library
+	Sessions  int       `json:"sessions"`
+	Variants  int       `json:"variants"`
+	Files     []string  `json:"files"` // library-relative, slash separated
+}
+
+// Variant is an in-memory table variant, stored by its weights.
+type Variant struct {
+	Mode    string   `json:"mode"`
+	Name    string   `json:"name"`
+	Weights []uint64 `json:"weights"`
+}
+
+// State is what an archive holds besides library files.
+type State struct {
+	Sessions []*lgs.SessionData `json:"sessions"`
+	Variants []Variant          `json:"variants"`
+}
+
+// LibraryFiles lists the state files of a library: everything under
+// StateDir plus the weight backups the optimizer writes next to the
+// lookup tables (<weights>.<timestamp>.bak). weightFiles are the
+// library-relative CSV paths of the modes.
+func LibraryFiles(libraryDir string, weightFiles []string) ([]string, error) {
+	var files []string
+	root := filepath.Join(libraryDir, StateDir)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(libraryDir, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", root, err)
+	}
+
+	for _, weights := range weightFiles {
+		matches, err := filepath.Glob(filepath.Join(libraryDir, weights+".*.bak"))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			rel, err := filepath.Rel(libraryDir, m)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Export writes state and the given library files to w as a zip archive.
+func Export(w io.Writer, libraryDir string, files []string, state *State) (*Manifest, error) {
+	manifest := &Manifest{
+		Version:   FormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Library:   filepath.Base(libraryDir),
+		Sessions:  len(state.Sessions),
+		Variants:  len(state.Variants),
+		Files:     files,
+	}
+	if manifest.Files == nil {
+		manifest.Files = []string{}
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeJSON(zw, manifestEntry, manifest, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(zw, sessionsEntry, state.Sessions, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(zw, variantsEntry, state.Variants, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		if err := writeFile(zw, libraryDir, name); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}, modified time.Time) error {
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeFile(zw *zip.Writer, libraryDir, name string) error {
+	src, err := os.Open(filepath.Join(libraryDir, filepath.FromSlash(name)))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = libraryPrefix + name
+	header.Method = zip.Deflate
+	dst, err := zw.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Import reads an archive, writes its library files into libraryDir
+// (overwriting files with the same name) and returns the manifest and the
+// state for the caller to apply.
+func Import(r io.ReaderAt, size int64, libraryDir string) (*Manifest, *State, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	var manifest Manifest
+	if err := readJSON(zr, manifestEntry, &manifest); err != nil {
+		return nil, nil, err
+	}
+	if manifest.Version != FormatVersion {
+		return nil, nil, fmt.Errorf("unsupported archive version %d (expected %d)", manifest.Version, FormatVersion)
+	}
+	state := &State{}
+	if err := readJSON(zr, sessionsEntry, &state.Sessions); err != nil {
+		return nil, nil, err
+	}
+	if err := readJSON(zr, variantsEntry, &state.Variants); err != nil {
+		return nil, nil, err
+	}
+
+	// Check every name before writing anything
+	var files []*zip.File
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, libraryPrefix) || f.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.TrimPrefix(f.Name, libraryPrefix)
+		if !isStateFile(name) {
+			return nil, nil, fmt.Errorf("archive entry %q is not a library state file", f.Name)
+		}
+		if f.UncompressedSize64 > MaxFileSize {
+			return nil, nil, fmt.Errorf("archive entry %q is too large", f.Name)
+		}
+		files = append(files, f)
+	}
+
+	manifest.Files = manifest.Files[:0]
+	for _, f := range files {
+		name := strings.TrimPrefix(f.Name, libraryPrefix)
+		if err := extractFile(f, filepath.Join(libraryDir, filepath.FromSlash(name))); err != nil {
+			return nil, nil, err
+		}
+		manifest.Files = append(manifest.Files, name)
+	}
+	return &manifest, state, nil
+}
+
+// isStateFile reports whether a library-relative name is one Export writes:
+// a local path under StateDir or a weight backup.
+func isStateFile(name string) bool {
+	if !filepath.IsLocal(filepath.FromSlash(name)) || path.Clean(name) != name {
+		return false
+	}
+	return strings.HasPrefix(name, StateDir+"/") || strings.HasSuffix(name, ".bak")
+}
+
+func readJSON(zr *zip.Reader, name string, v interface{}) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("archive has no %s: %w", name, err)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(io.LimitReader(f, MaxFileSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, dest string) error {
+	src, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+	tmp := dest + ".tmp"
+	dst, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	_, err = io.Copy(dst, io.LimitReader(src, MaxFileSize))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dest)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build darwin
+
+package watcher
+
+import (
+	"syscall"
+)
+
+// networkFSTypes lists filesystem types on which fsnotify cannot see remote writes.
+var networkFSTypes = map[string]bool{
+	"nfs":     true,
+	"smbfs":   true,
+	"afpfs":   true,
+	"webdav":  true,
+	"osxfuse": true,
+	"macfuse": true,
+}
+
+// IsNetworkMount reports whether path lives on a network filesystem and returns
+// the filesystem type.
+func IsNetworkMount(path string) (bool, string) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false, ""
+	}
+
+	buf := make([]byte, 0, len(stat.Fstypename))
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		buf = append(buf, byte(c))
+	}
+	fsType := string(buf)
+
+	return networkFSTypes[fsType], fsType
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package watcher
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// networkFSTypes lists filesystem types on which fsnotify cannot see remote writes.
+var networkFSTypes = map[string]bool{
+	"nfs":         true,
+	"nfs4":        true,
+	"cifs":        true,
+	"smb3":        true,
+	"smbfs":       true,
+	"afs":         true,
+	"9p":          true,
+	"fuse.sshfs":  true,
+	"fuse.rclone": true,
+	"davfs":       true,
+}
+
+// IsNetworkMount reports whether path lives on a network filesystem and returns
+// the filesystem type. It finds the longest mount point in /proc/mounts that contains path.
+func IsNetworkMount(path string) (bool, string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, ""
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return false, ""
+	}
+	defer file.Close()
+
+	bestMount, bestType := "", ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mountPoint := strings.ReplaceAll(fields[1], `\040`, " ")
+		if !isWithin(absPath, mountPoint) || len(mountPoint) < len(bestMount) {
+			continue
+		}
+		bestMount, bestType = mountPoint, fields[2]
+	}
+
+	return networkFSTypes[bestType], bestType
+}
+
+// isWithin reports whether path equals dir or is nested inside it.
+func isWithin(path, dir string) bool {
+	if dir == "/" || path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+
+package watcher
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsNetworkMount reports whether path lives on a network filesystem.
+// Only UNC paths (\\server\share) are detected on this platform; mapped
+// network drives are reported as local.
+func IsNetworkMount(path string) (bool, string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, ""
+	}
+	if strings.HasPrefix(absPath, `\\`) && !strings.HasPrefix(absPath, `\\?\`) {
+		return true, "smb"
+	}
+	return false, ""
+}
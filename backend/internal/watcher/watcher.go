@@ -3,9 +3,11 @@
 package watcher
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,6 +18,92 @@ import (
 // mode is the game mode name (e.g., "base", "bonus").
 type ReloadFunc func(mode string) error
 
+// IndexChangeFunc is called when the watched index file changes.
+type IndexChangeFunc func() error
+
+// Options configures debounce and file stability detection.
+type Options struct {
+	// Debounce is the quiet period after the last change event before a reload is attempted.
+	Debounce time.Duration
+	// StableFor is how long size and mtime must stay unchanged before the file is read.
+	StableFor time.Duration
+	// SampleInterval is how often the file is sampled while waiting for stability.
+	SampleInterval time.Duration
+	// MaxWait is how long to wait for stability before skipping the reload.
+	MaxWait time.Duration
+	// Ignore lists filepath.Match globs for filenames that never trigger a reload,
+	// such as editor swap files and partial rsync transfers.
+	Ignore []string
+}
+
+// DefaultOptions returns the default watcher settings.
+func DefaultOptions() Options {
+	return Options{
+		Debounce:       2 * time.Second,
+		StableFor:      3 * time.Second,
+		SampleInterval: 500 * time.Millisecond,
+		MaxWait:        10 * time.Minute, // simulation pipelines can write books for minutes
+		Ignore:         DefaultIgnorePatterns(),
+	}
+}
+
+// DefaultIgnorePatterns returns globs for temporary files created by editors,
+// rsync and download tools.
+func DefaultIgnorePatterns() []string {
+	return []string{
+		".*",     // hidden files, including rsync temp files (.name.XXXXXX)
+		"*~",     // editor backups
+		"*.tmp",  // atomic-write temp files
+		"*.swp",  // vim swap files
+		"*.swx",  // vim swap files
+		"*.part", // partial downloads
+		"*.partial",
+		"*.crdownload",
+		"*.bak",
+	}
+}
+
+// isIgnored reports whether filename matches one of the ignore patterns.
+func isIgnored(filename string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := filepath.Match(pattern, filename); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Change detection methods.
+const (
+	MethodFSNotify = "fsnotify"
+	MethodPoll     = "poll"
+)
+
+// DefaultPollingInterval is how often files are checked in polling mode.
+const DefaultPollingInterval = 5 * time.Second
+
+// historyLimit is the number of change events kept for the history API.
+const historyLimit = 200
+
+// Actions recorded in the change history.
+const (
+	ActionReload  = "reload"  // change detected, reload attempted
+	ActionSkipped = "skipped" // change detected, reload not attempted
+	ActionRescan  = "rescan"  // reload forced by a manual rescan
+)
+
+// ChangeEvent records a detected change and what the watcher did about it.
+type ChangeEvent struct {
+	File        string    `json:"file"`
+	Mode        string    `json:"mode,omitempty"` // empty for the index file
+	DetectedAt  time.Time `json:"detected_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Action      string    `json:"action"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+}
+
 // FileWatcher watches files for changes and triggers reloads.
 // It can be enabled/disabled at runtime.
 type FileWatcher struct {
@@ -23,13 +111,22 @@ type FileWatcher struct {
 	baseDir    string
 	files      map[string]string // filename -> mode name
 	onReload   ReloadFunc
-	debounce   time.Duration
+	indexFile  string // index filename, empty if index is not watched
+	onIndex    IndexChangeFunc
+	opts       Options
 	stopCh     chan struct{}
 	wg         sync.WaitGroup
 	mu         sync.Mutex
-	lastChange map[string]time.Time // debounce tracking
-	enabled    bool                 // whether watching is active
-	enabledMu  sync.RWMutex         // protects enabled flag
+	timers     map[string]*time.Timer // pending debounced reloads
+	generation map[string]uint64      // bumped on every change, used to drop superseded reloads
+	firstSeen  map[string]time.Time   // when the first change of a pending burst was seen
+	paused     map[string]bool        // modes with auto-reload disabled
+	polling    bool                   // poll file stats instead of relying on fsnotify
+	scanEvery  time.Duration          // polling interval
+	history    []ChangeEvent          // recent changes, oldest first
+	historyMu  sync.Mutex             // protects history
+	enabled    bool                   // whether watching is active
+	enabledMu  sync.RWMutex           // protects enabled flag
 }
 
 // NewFileWatcher creates a new watcher for files.
@@ -46,9 +143,13 @@ func NewFileWatcher(baseDir string, files map[string]string, onReload ReloadFunc
 		baseDir:    baseDir,
 		files:      files,
 		onReload:   onReload,
-		debounce:   2 * time.Second, // debounce rapid changes
+		opts:       DefaultOptions(),
 		stopCh:     make(chan struct{}),
-		lastChange: make(map[string]time.Time),
+		timers:     make(map[string]*time.Timer),
+		generation: make(map[string]uint64),
+		firstSeen:  make(map[string]time.Time),
+		scanEvery:  DefaultPollingInterval,
+		paused:     make(map[string]bool),
 		enabled:    true, // enabled by default when created
 	}, nil
 }
@@ -73,26 +174,113 @@ func (fw *FileWatcher) SetEnabled(enabled bool) {
 	}
 }
 
+// SetPolling switches the watcher to polling mode, comparing each tracked file's
+// mtime and size every interval instead of relying on fsnotify events.
+// fsnotify does not see changes made by other hosts on NFS/SMB mounts.
+// Must be called before Start.
+func (fw *FileWatcher) SetPolling(enabled bool, interval time.Duration) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	fw.polling = enabled
+	if interval > 0 {
+		fw.scanEvery = interval
+	}
+}
+
+// Method returns how changes are detected: "poll" or "fsnotify".
+func (fw *FileWatcher) Method() string {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if fw.polling {
+		return MethodPoll
+	}
+	return MethodFSNotify
+}
+
+// ModeEnabled returns whether auto-reload is enabled for a mode.
+func (fw *FileWatcher) ModeEnabled(mode string) bool {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return !fw.paused[mode]
+}
+
+// SetModeEnabled enables or disables auto-reload for a single mode while the
+// rest of the watcher keeps running. Pending reloads for the mode are cancelled.
+func (fw *FileWatcher) SetModeEnabled(mode string, enabled bool) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if enabled {
+		delete(fw.paused, mode)
+		log.Printf("[Watcher] Auto-reload enabled for mode: %s", mode)
+		return
+	}
+
+	fw.paused[mode] = true
+	for filename, m := range fw.files {
+		if m != mode {
+			continue
+		}
+		if t, ok := fw.timers[filename]; ok {
+			t.Stop()
+			delete(fw.timers, filename)
+			delete(fw.firstSeen, filename)
+		}
+	}
+	log.Printf("[Watcher] Auto-reload disabled for mode: %s", mode)
+}
+
+// DisabledModes returns the modes with auto-reload disabled, sorted by name.
+func (fw *FileWatcher) DisabledModes() []string {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	modes := make([]string, 0, len(fw.paused))
+	for mode := range fw.paused {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
 // Start begins watching for file changes.
 func (fw *FileWatcher) Start() error {
-	// Watch the base directory
-	if err := fw.watcher.Add(fw.baseDir); err != nil {
-		return err
+	fw.mu.Lock()
+	polling, interval := fw.polling, fw.scanEvery
+	fw.mu.Unlock()
+
+	if polling {
+		log.Printf("[Watcher] Polling directory every %v: %s", interval, fw.baseDir)
+	} else {
+		// Watch the base directory
+		if err := fw.watcher.Add(fw.baseDir); err != nil {
+			return err
+		}
+		log.Printf("[Watcher] Watching directory: %s", fw.baseDir)
 	}
 
-	log.Printf("[Watcher] Watching directory: %s", fw.baseDir)
-	for filename := range fw.files {
+	for filename := range fw.GetFiles() {
 		log.Printf("[Watcher] Tracking file: %s", filename)
 	}
 
 	fw.wg.Add(1)
-	go fw.run()
+	if polling {
+		go fw.poll(interval)
+	} else {
+		go fw.run()
+	}
 
 	return nil
 }
 
 // Stop stops watching for file changes.
 func (fw *FileWatcher) Stop() {
+	fw.mu.Lock()
+	for filename, t := range fw.timers {
+		t.Stop()
+		delete(fw.timers, filename)
+	}
+	fw.mu.Unlock()
+
 	close(fw.stopCh)
 	fw.watcher.Close()
 	fw.wg.Wait()
@@ -122,6 +310,65 @@ func (fw *FileWatcher) run() {
 	}
 }
 
+// fileState is the part of a file's metadata compared in polling mode.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// poll periodically stats the tracked files and feeds any change into the
+// same debounce and stability pipeline as fsnotify events.
+func (fw *FileWatcher) poll(interval time.Duration) {
+	defer fw.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	snapshot := fw.statTracked()
+	for {
+		select {
+		case <-fw.stopCh:
+			return
+		case <-ticker.C:
+			current := fw.statTracked()
+			for filename, state := range current {
+				if prev, ok := snapshot[filename]; ok && prev.size == state.size && prev.modTime.Equal(state.modTime) {
+					continue
+				}
+				fw.handleEvent(fsnotify.Event{
+					Name: filepath.Join(fw.baseDir, filename),
+					Op:   fsnotify.Write,
+				})
+			}
+			snapshot = current
+		}
+	}
+}
+
+// statTracked returns the current size and mtime of the index and tracked files.
+// Files that cannot be stat'ed are omitted, so their reappearance counts as a change.
+func (fw *FileWatcher) statTracked() map[string]fileState {
+	fw.mu.Lock()
+	names := make([]string, 0, len(fw.files)+1)
+	for filename := range fw.files {
+		names = append(names, filename)
+	}
+	if fw.indexFile != "" {
+		names = append(names, fw.indexFile)
+	}
+	fw.mu.Unlock()
+
+	states := make(map[string]fileState, len(names))
+	for _, filename := range names {
+		info, err := os.Stat(filepath.Join(fw.baseDir, filename))
+		if err != nil {
+			continue
+		}
+		states[filename] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+	return states
+}
+
 func (fw *FileWatcher) handleEvent(event fsnotify.Event) {
 	// Check if watcher is enabled
 	if !fw.Enabled() {
@@ -135,93 +382,278 @@ func (fw *FileWatcher) handleEvent(event fsnotify.Event) {
 
 	filename := filepath.Base(event.Name)
 
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if isIgnored(filename, fw.opts.Ignore) {
+		return
+	}
+
+	// Only react to the exact filenames listed in the index
+	if fw.indexFile != "" && filename == fw.indexFile {
+		fw.scheduleLocked(filename, event.Name, "", fw.onIndex)
+		return
+	}
+
 	// Check if this is a file we're tracking
 	mode, ok := fw.files[filename]
 	if !ok {
 		return
 	}
 
-	// Debounce: ignore if last change was too recent
+	if fw.paused[mode] {
+		return
+	}
+
+	fw.scheduleLocked(filename, event.Name, mode, func() error {
+		return fw.onReload(mode)
+	})
+}
+
+// scheduleLocked (re)starts the debounce timer for a file. Each new event pushes
+// the reload back, so it only fires once the file has been quiet for the debounce
+// period. Must be called with fw.mu held.
+// mode is empty for the index file.
+func (fw *FileWatcher) scheduleLocked(filename, fullPath, mode string, reload func() error) {
+	fw.generation[filename]++
+	gen := fw.generation[filename]
+
+	if t, ok := fw.timers[filename]; ok {
+		t.Stop()
+	} else {
+		log.Printf("[Watcher] File changed: %s (%s)", filename, describeTarget(mode))
+		fw.firstSeen[filename] = time.Now()
+	}
+
+	fw.timers[filename] = time.AfterFunc(fw.opts.Debounce, func() {
+		fw.fire(filename, fullPath, mode, gen, reload)
+	})
+}
+
+// describeTarget returns a log label for what a file change reloads.
+func describeTarget(mode string) string {
+	if mode == "" {
+		return "index"
+	}
+	return "mode " + mode
+}
+
+// fire runs after the debounce period. It waits for the file to become stable
+// and triggers the reload unless a newer change superseded this one.
+func (fw *FileWatcher) fire(filename, fullPath, mode string, gen uint64, reload func() error) {
+	select {
+	case <-fw.stopCh:
+		return
+	default:
+	}
+
 	fw.mu.Lock()
-	lastTime, exists := fw.lastChange[filename]
-	now := time.Now()
-	if exists && now.Sub(lastTime) < fw.debounce {
-		fw.mu.Unlock()
+	if fw.generation[filename] == gen {
+		delete(fw.timers, filename)
+	}
+	detectedAt := fw.firstSeen[filename]
+	opts := fw.opts
+	fw.mu.Unlock()
+
+	ev := ChangeEvent{
+		File:       filename,
+		Mode:       mode,
+		DetectedAt: detectedAt,
+	}
+
+	// Wait for file to stabilize (stop being written to)
+	// This is crucial for large files that take time to write
+	if err := fw.waitForFileStable(fullPath, opts); err != nil {
+		log.Printf("[Watcher] File %s not stable, skipping reload: %v", filename, err)
+		ev.Action = ActionSkipped
+		ev.Error = err.Error()
+		fw.record(ev)
 		return
 	}
-	fw.lastChange[filename] = now
+
+	fw.mu.Lock()
+	superseded := fw.generation[filename] != gen
+	if !superseded {
+		delete(fw.firstSeen, filename)
+	}
 	fw.mu.Unlock()
+	if superseded {
+		log.Printf("[Watcher] File %s changed again, deferring reload", filename)
+		return
+	}
+
+	if !fw.Enabled() {
+		ev.Action = ActionSkipped
+		ev.Error = "watcher disabled"
+		fw.record(ev)
+		return
+	}
+	if mode != "" && !fw.ModeEnabled(mode) {
+		ev.Action = ActionSkipped
+		ev.Error = "auto-reload disabled for mode"
+		fw.record(ev)
+		return
+	}
 
-	log.Printf("[Watcher] File changed: %s (mode: %s)", filename, mode)
+	ev.Action = ActionReload
+	fw.runReload(ev, reload)
+}
 
-	// Trigger reload in a goroutine to not block the watcher
-	go func(m string, f string, fullPath string) {
-		// Wait for file to stabilize (stop being written to)
-		// This is crucial for large files that take time to write
-		if err := fw.waitForFileStable(fullPath); err != nil {
-			log.Printf("[Watcher] File %s not stable, skipping reload: %v", f, err)
-			return
-		}
+// runReload invokes reload, logs the outcome and records it in the history.
+func (fw *FileWatcher) runReload(ev ChangeEvent, reload func() error) ChangeEvent {
+	target := describeTarget(ev.Mode)
+	start := time.Now()
 
-		log.Printf("[Watcher] Reloading for mode: %s", m)
-		if err := fw.onReload(m); err != nil {
-			log.Printf("[Watcher] Failed to reload mode %s: %v", m, err)
-		} else {
-			log.Printf("[Watcher] Successfully reloaded mode: %s", m)
-		}
-	}(mode, filename, event.Name)
+	log.Printf("[Watcher] Reloading %s", target)
+	if err := reload(); err != nil {
+		log.Printf("[Watcher] Failed to reload %s: %v", target, err)
+		ev.Error = err.Error()
+	} else {
+		log.Printf("[Watcher] Successfully reloaded %s", target)
+		ev.Success = true
+	}
+
+	ev.DurationMs = time.Since(start).Milliseconds()
+	fw.record(ev)
+	return ev
 }
 
-// waitForFileStable waits until the file size stops changing.
-// This prevents reading a file that is still being written.
-func (fw *FileWatcher) waitForFileStable(path string) error {
-	const (
-		checkInterval  = 200 * time.Millisecond // How often to check file size
-		stableRequired = 3                       // Number of consecutive stable checks required
-		maxWait        = 30 * time.Second        // Maximum wait time
-	)
+// record appends an entry to the change history, dropping the oldest entries
+// once historyLimit is reached.
+func (fw *FileWatcher) record(ev ChangeEvent) {
+	ev.CompletedAt = time.Now()
+	fw.historyMu.Lock()
+	defer fw.historyMu.Unlock()
+	fw.history = append(fw.history, ev)
+	if len(fw.history) > historyLimit {
+		fw.history = fw.history[len(fw.history)-historyLimit:]
+	}
+}
 
+// History returns recent change events, newest first.
+func (fw *FileWatcher) History() []ChangeEvent {
+	fw.historyMu.Lock()
+	defer fw.historyMu.Unlock()
+	result := make([]ChangeEvent, len(fw.history))
+	for i, ev := range fw.history {
+		result[len(fw.history)-1-i] = ev
+	}
+	return result
+}
+
+// Rescan forces a reload of the index (if watched) and every tracked file,
+// regardless of whether a change was detected. Runs synchronously and returns
+// the outcome for each file.
+func (fw *FileWatcher) Rescan() []ChangeEvent {
+	fw.mu.Lock()
+	indexFile, onIndex := fw.indexFile, fw.onIndex
+	fw.mu.Unlock()
+
+	log.Println("[Watcher] Manual rescan requested")
+	now := time.Now()
+	var results []ChangeEvent
+
+	// Index first, so added or removed modes are reflected in the file set below
+	if indexFile != "" && onIndex != nil {
+		results = append(results, fw.runReload(ChangeEvent{
+			File:       indexFile,
+			DetectedAt: now,
+			Action:     ActionRescan,
+		}, onIndex))
+	}
+
+	files := fw.GetFiles()
+	names := make([]string, 0, len(files))
+	for filename := range files {
+		names = append(names, filename)
+	}
+	sort.Strings(names)
+
+	for _, filename := range names {
+		mode := files[filename]
+		results = append(results, fw.runReload(ChangeEvent{
+			File:       filename,
+			Mode:       mode,
+			DetectedAt: now,
+			Action:     ActionRescan,
+		}, func() error {
+			return fw.onReload(mode)
+		}))
+	}
+
+	return results
+}
+
+// waitForFileStable waits until the file size and modification time have not
+// changed for opts.StableFor. This prevents reading a file that is still being written.
+// Returns an error if the file does not settle within opts.MaxWait or the watcher stops.
+func (fw *FileWatcher) waitForFileStable(path string, opts Options) error {
 	startTime := time.Now()
 	var lastSize int64 = -1
-	stableCount := 0
+	var lastModTime time.Time
+	var stableSince time.Time
 
 	for {
-		if time.Since(startTime) > maxWait {
-			log.Printf("[Watcher] File %s: max wait time exceeded, proceeding anyway", filepath.Base(path))
-			return nil // Proceed anyway after max wait
+		if time.Since(startTime) > opts.MaxWait {
+			return fmt.Errorf("still changing after %v", opts.MaxWait)
 		}
 
 		info, err := os.Stat(path)
 		if err != nil {
 			// File might be temporarily unavailable during write
-			time.Sleep(checkInterval)
-			stableCount = 0
 			lastSize = -1
-			continue
+			stableSince = time.Time{}
+		} else if info.Size() != lastSize || !info.ModTime().Equal(lastModTime) || info.Size() == 0 {
+			lastSize = info.Size()
+			lastModTime = info.ModTime()
+			stableSince = time.Now()
+		} else if time.Since(stableSince) >= opts.StableFor {
+			log.Printf("[Watcher] File %s stable at %d bytes after %v",
+				filepath.Base(path), lastSize, time.Since(startTime))
+			return nil
 		}
 
-		currentSize := info.Size()
-
-		if currentSize == lastSize && currentSize > 0 {
-			stableCount++
-			if stableCount >= stableRequired {
-				log.Printf("[Watcher] File %s stable at %d bytes after %v",
-					filepath.Base(path), currentSize, time.Since(startTime))
-				return nil
-			}
-		} else {
-			stableCount = 0
+		select {
+		case <-fw.stopCh:
+			return fmt.Errorf("watcher stopped")
+		case <-time.After(opts.SampleInterval):
 		}
+	}
+}
+
+// Options returns the current debounce and stability settings.
+func (fw *FileWatcher) Options() Options {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	return fw.opts
+}
 
-		lastSize = currentSize
-		time.Sleep(checkInterval)
+// SetOptions updates debounce and stability settings.
+// Zero values keep the current setting.
+func (fw *FileWatcher) SetOptions(opts Options) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if opts.Debounce > 0 {
+		fw.opts.Debounce = opts.Debounce
+	}
+	if opts.StableFor > 0 {
+		fw.opts.StableFor = opts.StableFor
+	}
+	if opts.SampleInterval > 0 {
+		fw.opts.SampleInterval = opts.SampleInterval
+	}
+	if opts.MaxWait > 0 {
+		fw.opts.MaxWait = opts.MaxWait
+	}
+	if opts.Ignore != nil {
+		fw.opts.Ignore = append([]string(nil), opts.Ignore...)
 	}
 }
 
 // SetDebounce sets the debounce duration for file changes.
 func (fw *FileWatcher) SetDebounce(d time.Duration) {
 	fw.mu.Lock()
-	fw.debounce = d
+	fw.opts.Debounce = d
 	fw.mu.Unlock()
 }
 
@@ -233,6 +665,47 @@ func (fw *FileWatcher) AddFile(filename, mode string) {
 	log.Printf("[Watcher] Added file: %s (mode: %s)", filename, mode)
 }
 
+// SetIndexHandler starts tracking the index file (e.g., "index.json") and calls
+// onIndex whenever it changes.
+func (fw *FileWatcher) SetIndexHandler(filename string, onIndex IndexChangeFunc) {
+	fw.mu.Lock()
+	fw.indexFile = filename
+	fw.onIndex = onIndex
+	fw.mu.Unlock()
+	log.Printf("[Watcher] Tracking index: %s", filename)
+}
+
+// RemoveFile stops tracking a file.
+func (fw *FileWatcher) RemoveFile(filename string) {
+	fw.mu.Lock()
+	mode, ok := fw.files[filename]
+	delete(fw.files, filename)
+	if t, ok := fw.timers[filename]; ok {
+		t.Stop()
+		delete(fw.timers, filename)
+	}
+	fw.mu.Unlock()
+	if ok {
+		log.Printf("[Watcher] Removed file: %s (mode: %s)", filename, mode)
+	}
+}
+
+// SetFiles replaces the set of tracked files, adding new entries and removing
+// entries that are no longer present.
+func (fw *FileWatcher) SetFiles(files map[string]string) {
+	for filename := range fw.GetFiles() {
+		if _, ok := files[filename]; !ok {
+			fw.RemoveFile(filename)
+		}
+	}
+	current := fw.GetFiles()
+	for filename, mode := range files {
+		if current[filename] != mode {
+			fw.AddFile(filename, mode)
+		}
+	}
+}
+
 // GetFiles returns the currently watched files.
 func (fw *FileWatcher) GetFiles() map[string]string {
 	fw.mu.Lock()
@@ -27,16 +27,35 @@ const (
 	// LGS session messages
 	MsgLGSSessionUpdate  MessageType = "lgs_session_update"
 	MsgLGSSessionsUpdate MessageType = "lgs_sessions_update"
+	MsgLGSBatchProgress  MessageType = "lgs_batch_progress"
+	MsgLGSBatchComplete  MessageType = "lgs_batch_complete"
+	MsgLGSJackpotHit     MessageType = "lgs_jackpot_hit"
 
 	// LUT watcher messages
 	MsgLUTReloaded     MessageType = "lut_reloaded"
+	MsgIndexReloaded   MessageType = "index_reloaded"
 	MsgWatcherEnabled  MessageType = "watcher_enabled"
 	MsgWatcherDisabled MessageType = "watcher_disabled"
+	MsgLUTDriftWarning MessageType = "lut_drift_warning"
+	MsgTableUpdated    MessageType = "table_updated"
+
+	// Library messages
+	MsgLibrarySwitching MessageType = "library_switching"
+	MsgLibraryChanged   MessageType = "library_changed"
 
 	// Optimizer progress messages
 	MsgOptimizerProgress MessageType = "optimizer_progress"
 	MsgOptimizerComplete MessageType = "optimizer_complete"
 	MsgOptimizerError    MessageType = "optimizer_error"
+
+	// Streamed quick-simulation messages
+	MsgSimulationSpins    MessageType = "simulation_spins"
+	MsgSimulationComplete MessageType = "simulation_complete"
+
+	// Multi-user presence messages
+	MsgLockAcquired    MessageType = "lock_acquired"
+	MsgLockReleased    MessageType = "lock_released"
+	MsgClientConnected MessageType = "client_connected"
 )
 
 // Message represents a WebSocket message sent to clients.
@@ -1,26 +1,68 @@
 // API Client for LUT Explorer Backend
 
+import { getCurrentLocale } from '$lib/i18n';
 import type {
 	ApiResponse,
+	Bookmark,
 	IndexInfo,
+	LibraryInfo,
+	MaxWinEstimate,
+	ModeVariant,
+	RecentLibrary,
 	ModeSummary,
+	ModeRegistration,
 	Statistics,
+	OutcomeWeightResponse,
+	StateImportResult,
 	DistributionItem,
 	Outcome,
 	CompareResponse,
 	EventLoadResult,
 	EventInfo,
+	EventQuery,
+	OutcomeSample,
+	PayoutBucketName,
+	OutcomeExportFormat,
 	LGSAuthResponse,
 	LGSPlayResponse,
 	LGSSessionsResponse,
 	LGSStatsResponse,
 	LGSRound,
 	LGSBatchPlayResponse,
+	LGSBatchStreamStarted,
+	TargetBand,
+	FitDistributionResponse,
+	MultiObjectiveConfig,
+	MultiObjectiveResponse,
+	LGSBiasPreset,
+	LGSEventTransforms,
+	LGSScenario,
+	LGSScenarioStep,
+	LGSSessionRecording,
+	LGSReplayReport,
+	LGSBetLevelConfig,
+	LGSBetLevels,
+	LGSCurrency,
+	LGSCurrencies,
+	LGSRoundProgress,
+	LGSJackpotConfig,
+	LGSFaultRule,
+	LGSFaults,
+	LGSJackpots,
+	ScenarioReport,
+	SelfTestReport,
+	SamplingReport,
+	LGSCassette,
+	LGSCassetteInfo,
+	LGSTapeStatus,
+	LGSStatsPoint,
 	LoaderStatusResponse,
 	LoaderPriorityResponse,
 	LoaderBoostResponse,
 	ComplianceResult,
 	AllModesComplianceResult,
+	ComplianceProfile,
+	ComplianceProfilesResponse,
 	CrowdSimConfig,
 	CrowdSimResult,
 	CrowdSimCompareResult,
@@ -33,21 +75,73 @@ import type {
 	ConvexHealthResponse,
 	ConvexModeInfoResponse,
 	ModeAnalysis,
-	GenerateConfigsAnalysis
+	WeightDiffResponse,
+	NormalizeOptions,
+	NormalizeResponse,
+	GenerateConfigsAnalysis,
+	PresenceInfo,
+	SoftLock,
+	OptimizerRun,
+	ComplianceRun,
+	AuditEntry,
+	LogLevel,
+	LogLine
 } from './types';
 
-const DEFAULT_BASE_URL = 'http://localhost:7754';
+const API_URL_STORAGE_KEY = 'mtools-api-url';
+const CLIENT_NAME_STORAGE_KEY = 'mtools-client-name';
+
+// Name shown to other users of a shared backend (soft locks, presence)
+function getInitialClientName(): string {
+	if (typeof localStorage === 'undefined') return '';
+	return localStorage.getItem(CLIENT_NAME_STORAGE_KEY) ?? '';
+}
+
+/**
+ * Resolve the backend URL from:
+ * 1. URL query parameter (?api=http://localhost:7760), set by the launcher when 7754 was busy
+ * 2. sessionStorage (so client-side navigation keeps it)
+ * 3. Fallback to http://localhost:7754
+ */
+function getInitialBaseUrl(): string {
+	const fallback = 'http://localhost:7754';
+	if (typeof window === 'undefined') return fallback;
+
+	const urlApi = new URLSearchParams(window.location.search).get('api');
+	if (urlApi && /^https?:\/\//.test(urlApi)) {
+		sessionStorage.setItem(API_URL_STORAGE_KEY, urlApi);
+		return urlApi;
+	}
+
+	return sessionStorage.getItem(API_URL_STORAGE_KEY) ?? fallback;
+}
+
+const DEFAULT_BASE_URL = getInitialBaseUrl();
 const DEFAULT_LGS_URL = 'http://localhost:7754';
 
+// Header of the LGS idempotency key, sent only when a key is given
+function requestIdHeader(requestId?: string): Record<string, string> | undefined {
+	return requestId ? { 'X-Request-ID': requestId } : undefined;
+}
+
 class LutApiClient {
 	private baseUrl: string;
+	private clientName: string = getInitialClientName();
 
 	constructor(baseUrl: string = DEFAULT_BASE_URL) {
 		this.baseUrl = baseUrl;
 	}
 
+	// Identifies this client to the backend for presence and soft locks, and
+	// asks for numbers in human-readable strings in the UI language
+	private headers(extra: Record<string, string> = {}): Record<string, string> {
+		const headers: Record<string, string> = { ...extra, 'Accept-Language': getCurrentLocale() };
+		if (this.clientName) headers['X-Client-Name'] = this.clientName;
+		return headers;
+	}
+
 	private async fetch<T>(endpoint: string): Promise<T> {
-		const response = await fetch(`${this.baseUrl}${endpoint}`);
+		const response = await fetch(`${this.baseUrl}${endpoint}`, { headers: this.headers() });
 		const data: ApiResponse<T> = await response.json();
 
 		if (!data.success) {
@@ -69,6 +163,15 @@ class LutApiClient {
 		return this.fetch('/api/modes');
 	}
 
+	// Register a mode at runtime; paths are relative to the library's publish_files
+	async addMode(config: ModeRegistration): Promise<ModeSummary> {
+		return this.postJson('/api/modes', config);
+	}
+
+	async removeMode(mode: string): Promise<{ removed: string }> {
+		return this.sendJson('DELETE', `/api/mode/${encodeURIComponent(mode)}`);
+	}
+
 	async getMode(mode: string): Promise<ModeSummary> {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}`);
 	}
@@ -77,6 +180,11 @@ class LutApiClient {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/stats`);
 	}
 
+	// Edits one outcome's weight in memory (and on disk with save) and returns the new stats
+	async setOutcomeWeight(mode: string, simId: number, weight: number, save = false): Promise<OutcomeWeightResponse> {
+		return this.sendJson('PATCH', `/api/mode/${encodeURIComponent(mode)}/outcome/${simId}`, { weight, save });
+	}
+
 	async getModeDistribution(mode: string): Promise<DistributionItem[]> {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/distribution`);
 	}
@@ -101,6 +209,52 @@ class LutApiClient {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/outcomes`);
 	}
 
+	// Streams outcomes as NDJSON, sorted server-side. onRows is called for each
+	// received batch so a virtualized table can render before the stream ends.
+	// Resolves with the total number of outcomes in the mode.
+	async streamModeOutcomes(
+		mode: string,
+		onRows: (rows: Outcome[], total: number) => void,
+		options: {
+			sort?: 'sim_id' | 'payout' | 'weight' | 'probability';
+			order?: 'asc' | 'desc';
+			offset?: number;
+			limit?: number;
+			signal?: AbortSignal;
+		} = {}
+	): Promise<number> {
+		const { signal, ...query } = options;
+		const params = new URLSearchParams();
+		for (const [key, value] of Object.entries(query)) {
+			if (value !== undefined) params.set(key, String(value));
+		}
+		const qs = params.toString();
+		const response = await fetch(
+			`${this.baseUrl}/api/mode/${encodeURIComponent(mode)}/outcomes/stream${qs ? `?${qs}` : ''}`,
+			{ signal }
+		);
+		if (!response.ok || !response.body) {
+			const data: ApiResponse<unknown> = await response.json();
+			throw new Error(data.error || 'Unknown error');
+		}
+
+		const total = Number(response.headers.get('X-Total-Count') ?? 0);
+		const reader = response.body.getReader();
+		const decoder = new TextDecoder();
+		let pending = '';
+		for (;;) {
+			const { done, value } = await reader.read();
+			if (done) break;
+			pending += decoder.decode(value, { stream: true });
+			const lines = pending.split('\n');
+			pending = lines.pop() ?? '';
+			const rows = lines.filter((line) => line !== '').map((line) => JSON.parse(line) as Outcome);
+			if (rows.length > 0) onRows(rows, total);
+		}
+		if (pending.trim() !== '') onRows([JSON.parse(pending) as Outcome], total);
+		return total;
+	}
+
 	async compare(modes?: string[]): Promise<CompareResponse> {
 		const params = modes?.map((m) => `mode=${encodeURIComponent(m)}`).join('&');
 		const endpoint = params ? `/api/compare?${params}` : '/api/compare';
@@ -115,9 +269,38 @@ class LutApiClient {
 		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/event/${simId}`);
 	}
 
+	// Synthetic events: generated payout-only books for modes without an events file
+	async getSyntheticEvents(): Promise<{ enabled: boolean }> {
+		return this.fetch('/api/events/synthetic');
+	}
+
+	async setSyntheticEvents(enabled: boolean): Promise<{ enabled: boolean; message: string }> {
+		return enabled ? this.post('/api/events/synthetic') : this.sendJson('DELETE', '/api/events/synthetic');
+	}
+
+	async sampleOutcomes(
+		mode: string,
+		options: {
+			bucket?: PayoutBucketName;
+			min?: number;
+			max?: number;
+			count?: number;
+			weighting?: 'weighted' | 'uniform';
+			seed?: number;
+		} = {}
+	): Promise<OutcomeSample> {
+		const params = new URLSearchParams();
+		for (const [key, value] of Object.entries(options)) {
+			if (value !== undefined) params.set(key, String(value));
+		}
+		const query = params.toString();
+		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/sample-outcomes${query ? `?${query}` : ''}`);
+	}
+
 	private async post<T>(endpoint: string): Promise<T> {
 		const response = await fetch(`${this.baseUrl}${endpoint}`, {
-			method: 'POST'
+			method: 'POST',
+			headers: this.headers()
 		});
 		const data: ApiResponse<T> = await response.json();
 
@@ -131,9 +314,9 @@ class LutApiClient {
 	private async postJson<T>(endpoint: string, body: unknown): Promise<T> {
 		const response = await fetch(`${this.baseUrl}${endpoint}`, {
 			method: 'POST',
-			headers: {
+			headers: this.headers({
 				'Content-Type': 'application/json'
-			},
+			}),
 			body: JSON.stringify(body)
 		});
 		const data: ApiResponse<T> = await response.json();
@@ -145,6 +328,129 @@ class LutApiClient {
 		return data.data as T;
 	}
 
+	private async sendJson<T>(method: 'PUT' | 'PATCH' | 'DELETE', endpoint: string, body?: unknown): Promise<T> {
+		const response = await fetch(`${this.baseUrl}${endpoint}`, {
+			method,
+			headers: this.headers(body === undefined ? {} : { 'Content-Type': 'application/json' }),
+			body: body === undefined ? undefined : JSON.stringify(body)
+		});
+		const data: ApiResponse<T> = await response.json();
+
+		if (!data.success) {
+			throw new Error(data.error || 'Unknown error');
+		}
+
+		return data.data as T;
+	}
+
+	// ============ Bookmarks ============
+
+	async listBookmarks(filter: { mode?: string; tag?: string } = {}): Promise<Bookmark[]> {
+		const params = new URLSearchParams();
+		if (filter.mode) params.set('mode', filter.mode);
+		if (filter.tag) params.set('tag', filter.tag);
+		const query = params.toString();
+		const data = await this.fetch<{ bookmarks: Bookmark[] }>(`/api/bookmarks${query ? `?${query}` : ''}`);
+		return data.bookmarks;
+	}
+
+	async addBookmark(mode: string, simId: number, note = '', tags: string[] = []): Promise<Bookmark> {
+		return this.postJson('/api/bookmarks', { mode, sim_id: simId, note, tags });
+	}
+
+	async updateBookmark(id: string, changes: { note?: string; tags?: string[] }): Promise<Bookmark> {
+		return this.sendJson('PUT', `/api/bookmarks/${encodeURIComponent(id)}`, changes);
+	}
+
+	async deleteBookmark(id: string): Promise<{ deleted: string }> {
+		return this.sendJson('DELETE', `/api/bookmarks/${encodeURIComponent(id)}`);
+	}
+
+	async getParSheet(mode: string): Promise<unknown> {
+		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/par-sheet`);
+	}
+
+	// Printable HTML version of the PAR sheet, for opening in a new tab
+	getParSheetUrl(mode: string): string {
+		return `${this.baseUrl}/api/mode/${encodeURIComponent(mode)}/par-sheet?format=html`;
+	}
+
+	async getMaxWinEstimate(mode: string, bet?: number, threshold?: number): Promise<MaxWinEstimate> {
+		const params = new URLSearchParams();
+		if (bet !== undefined) params.set('bet', String(bet));
+		if (threshold !== undefined) params.set('threshold', String(threshold));
+		const query = params.toString();
+		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/max-win${query ? `?${query}` : ''}`);
+	}
+
+	async getSampling(mode: string, top?: number): Promise<SamplingReport> {
+		const qs = top !== undefined ? `?top=${top}` : '';
+		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/sampling${qs}`);
+	}
+
+	async resetSampling(mode: string): Promise<{ status: string; mode: string }> {
+		return this.sendJson('DELETE', `/api/mode/${encodeURIComponent(mode)}/sampling`);
+	}
+
+	async listVariants(mode: string): Promise<{ mode: string; variants: ModeVariant[] }> {
+		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/variants`);
+	}
+
+	/** Creates an in-memory variant; without weights the current weights are snapshotted. */
+	async createVariant(mode: string, name: string, weights?: number[]): Promise<ModeVariant> {
+		return this.postJson(`/api/mode/${encodeURIComponent(mode)}/variants`, { name, weights });
+	}
+
+	async deleteVariant(mode: string, name: string): Promise<{ deleted: string }> {
+		return this.sendJson('DELETE', `/api/mode/${encodeURIComponent(mode)}/variants/${encodeURIComponent(name)}`);
+	}
+
+	getModeExportUrl(mode: string, format?: OutcomeExportFormat): string {
+		const qs = format ? `?format=${format}` : '';
+		return `${this.baseUrl}/api/mode/${encodeURIComponent(mode)}/export${qs}`;
+	}
+
+	// Zip of all runtime state (sessions, variants, bookmarks, simulations, weight backups)
+	getStateExportUrl(): string {
+		return `${this.baseUrl}/api/state/export`;
+	}
+
+	async importState(archive: Blob): Promise<StateImportResult> {
+		const response = await fetch(`${this.baseUrl}/api/state/import`, {
+			method: 'POST',
+			headers: this.headers({ 'Content-Type': 'application/zip' }),
+			body: archive
+		});
+		const data: ApiResponse<StateImportResult> = await response.json();
+
+		if (!data.success) {
+			throw new Error(data.error || 'Unknown error');
+		}
+
+		return data.data as StateImportResult;
+	}
+
+	// Runs a YAML scenario script against the server; failed assertions are in the report
+	async runScenario(script: string): Promise<ScenarioReport> {
+		const response = await fetch(`${this.baseUrl}/api/scenarios/run`, {
+			method: 'POST',
+			headers: this.headers({ 'Content-Type': 'application/yaml' }),
+			body: script
+		});
+		const data: ApiResponse<ScenarioReport> = await response.json();
+
+		if (!data.success) {
+			throw new Error(data.error || 'Unknown error');
+		}
+
+		return data.data as ScenarioReport;
+	}
+
+	// Checks the tables, sampler and events offsets of every mode of the open library
+	async runSelfTest(spins?: number): Promise<SelfTestReport> {
+		return this.fetch<SelfTestReport>(`/api/selftest${spins ? `?spins=${spins}` : ''}`);
+	}
+
 	setBaseUrl(url: string) {
 		this.baseUrl = url;
 	}
@@ -153,14 +459,87 @@ class LutApiClient {
 		return this.baseUrl;
 	}
 
+	setClientName(name: string) {
+		this.clientName = name.trim();
+		if (typeof localStorage !== 'undefined') {
+			localStorage.setItem(CLIENT_NAME_STORAGE_KEY, this.clientName);
+		}
+	}
+
+	getClientName(): string {
+		return this.clientName;
+	}
+
+	// ============ Presence & Soft Locks ============
+
+	async getPresence(): Promise<PresenceInfo> {
+		return this.fetch('/api/presence');
+	}
+
+	// Take or refresh a lock (re-post before it expires to keep it); omit mode to lock the library
+	async acquireLock(mode?: string, action?: string, force?: boolean): Promise<SoftLock> {
+		return this.postJson(`/api/locks${force ? '?force=true' : ''}`, { mode, action });
+	}
+
+	async releaseLock(mode?: string, force?: boolean): Promise<{ released: boolean }> {
+		const params = new URLSearchParams();
+		if (mode) params.set('mode', mode);
+		if (force) params.set('force', 'true');
+		const qs = params.toString();
+		return this.sendJson('DELETE', `/api/locks${qs ? `?${qs}` : ''}`);
+	}
+
+	// ============ Backend Logs ============
+
+	// Buffered lines newer than `since`, at least as severe as `level`
+	async getLogs(since = 0, level?: LogLevel, limit?: number): Promise<{ lines: LogLine[]; last: number }> {
+		const params = new URLSearchParams({ since: String(since) });
+		if (level) params.set('level', level);
+		if (limit) params.set('limit', String(limit));
+		return this.fetch(`/api/logs?${params.toString()}`);
+	}
+
+	// Follows the backend log (buffered lines newer than `since` first); close the returned source to stop
+	streamLogs(onLine: (line: LogLine) => void, since = 0, level?: LogLevel): EventSource {
+		const params = new URLSearchParams({ since: String(since) });
+		if (level) params.set('level', level);
+		const source = new EventSource(`${this.baseUrl}/api/logs/stream?${params.toString()}`);
+		source.onmessage = (event) => onLine(JSON.parse(event.data) as LogLine);
+		return source;
+	}
+
+	// ============ History (backend started with -data-dir) ============
+
+	private historyQuery(params: Record<string, string | number | undefined>): string {
+		const qs = new URLSearchParams();
+		for (const [key, value] of Object.entries(params)) {
+			if (value !== undefined && value !== '') qs.set(key, String(value));
+		}
+		const s = qs.toString();
+		return s ? `?${s}` : '';
+	}
+
+	async getOptimizerHistory(mode?: string, limit?: number): Promise<{ runs: OptimizerRun[] }> {
+		return this.fetch(`/api/history/optimizer${this.historyQuery({ mode, limit })}`);
+	}
+
+	async getComplianceHistory(mode?: string, limit?: number): Promise<{ runs: ComplianceRun[] }> {
+		return this.fetch(`/api/history/compliance${this.historyQuery({ mode, limit })}`);
+	}
+
+	async getAuditLog(client?: string, limit?: number): Promise<{ entries: AuditEntry[] }> {
+		return this.fetch(`/api/audit${this.historyQuery({ client, limit })}`);
+	}
+
 	// ============ LGS (Local Game Server) Methods ============
 
 	// LGS responses don't use the ApiResponse wrapper
-	private async lgsPost<T>(endpoint: string, body?: unknown): Promise<T> {
+	private async lgsPost<T>(endpoint: string, body?: unknown, headers?: Record<string, string>): Promise<T> {
 		const response = await fetch(`${this.baseUrl}${endpoint}`, {
 			method: 'POST',
 			headers: {
-				'Content-Type': 'application/json'
+				'Content-Type': 'application/json',
+				...headers
 			},
 			body: body ? JSON.stringify(body) : undefined
 		});
@@ -189,17 +568,25 @@ class LutApiClient {
 		mode: string;
 		amount: number;
 		currency?: string;
+		// Idempotency key: repeating a play with the same key returns the
+		// original response instead of deducting the bet again
+		requestId?: string;
 	}): Promise<LGSPlayResponse> {
 		return this.lgsPost('/wallet/play', {
 			sessionID: options.sessionID,
 			mode: options.mode,
 			amount: options.amount,
 			currency: options.currency || 'USD'
-		});
+		}, requestIdHeader(options.requestId));
 	}
 
-	async lgsEndRound(sessionID: string): Promise<{ balance: { amount: number; currency: string }; round: LGSRound | null }> {
-		return this.lgsPost('/wallet/end-round', { sessionID });
+	async lgsEndRound(sessionID: string, requestId?: string): Promise<{ balance: { amount: number; currency: string }; round: LGSRound | null }> {
+		return this.lgsPost('/wallet/end-round', { sessionID }, requestIdHeader(requestId));
+	}
+
+	// Reports the event a multi-stage round reached; its progress is returned
+	async lgsEvent(sessionID: string, event: number): Promise<{ event: string; round?: LGSRoundProgress }> {
+		return this.lgsPost('/bet/event', { sessionID, event: String(event) });
 	}
 
 	// LGS utility endpoints
@@ -207,8 +594,11 @@ class LutApiClient {
 		return this.lgsGet('/lgs/sessions');
 	}
 
-	async lgsStats(sessionID: string): Promise<LGSStatsResponse> {
-		return this.lgsGet(`/lgs/stats?sessionID=${encodeURIComponent(sessionID)}`);
+	async lgsStats(sessionID: string, options?: { confidence?: number; tolerance?: number }): Promise<LGSStatsResponse> {
+		const params = new URLSearchParams({ sessionID });
+		if (options?.confidence) params.set('confidence', String(options.confidence));
+		if (options?.tolerance) params.set('tolerance', String(options.tolerance));
+		return this.lgsGet(`/lgs/stats?${params}`);
 	}
 
 	async lgsHistory(sessionID: string, limit: number = 50): Promise<{ rounds: LGSRound[]; balance: { amount: number; currency: string } }> {
@@ -247,6 +637,30 @@ class LutApiClient {
 		});
 	}
 
+	/** Starts a batch play in the background; progress arrives as 'lgs_batch_progress' WebSocket messages. */
+	async lgsBatchPlayStream(options: {
+		sessionID: string;
+		mode: string;
+		amount: number;
+		spins: number;
+		currency?: string;
+		progressEvery?: number;
+	}): Promise<LGSBatchStreamStarted> {
+		return this.lgsPost('/lgs/batchplay', {
+			sessionID: options.sessionID,
+			mode: options.mode,
+			amount: options.amount,
+			spins: options.spins,
+			currency: options.currency || 'USD',
+			stream: true,
+			progressEvery: options.progressEvery ?? 0
+		});
+	}
+
+	async lgsCancelBatchPlay(batchID: string): Promise<{ success: boolean; batchID: string }> {
+		return this.lgsPost('/lgs/batchplay/cancel', { batchID });
+	}
+
 	async lgsForceOutcome(sessionID: string, mode: string, simID: number): Promise<{
 		success: boolean;
 		message: string;
@@ -257,6 +671,23 @@ class LutApiClient {
 		return this.lgsPost('/lgs/force-outcome', { sessionID, mode, simID });
 	}
 
+	async lgsForceOutcomeByCriteria(
+		sessionID: string,
+		mode: string,
+		query: EventQuery,
+		pick: 'random' | 'weighted' | 'first' = 'random'
+	): Promise<{
+		success: boolean;
+		message: string;
+		mode: string;
+		simID: number;
+		payout: number;
+		totalMatches: number;
+		probability: number;
+	}> {
+		return this.lgsPost('/lgs/force-outcome/by-criteria', { sessionID, mode, pick, ...query });
+	}
+
 	async lgsGetForcedOutcomes(sessionID: string): Promise<{
 		sessionID: string;
 		forcedOutcomes: Record<string, number>;
@@ -270,6 +701,97 @@ class LutApiClient {
 		return this.lgsDelete(`/lgs/force-outcome?${params.toString()}`);
 	}
 
+	// Steps are consumed in order by plays in the step's mode
+	async lgsSetScenario(sessionID: string, steps: LGSScenarioStep[], append = false): Promise<LGSScenario> {
+		return this.lgsPost('/lgs/scenario', { sessionID, steps, append });
+	}
+
+	async lgsGetScenario(sessionID: string): Promise<LGSScenario> {
+		return this.lgsGet(`/lgs/scenario?sessionID=${encodeURIComponent(sessionID)}`);
+	}
+
+	async lgsClearScenario(sessionID: string): Promise<{ success: boolean; message: string }> {
+		return this.lgsDelete(`/lgs/scenario?sessionID=${encodeURIComponent(sessionID)}`);
+	}
+
+	async lgsStartRecording(sessionID: string): Promise<{
+		success: boolean;
+		sessionID: string;
+		startBalance: number;
+		startedAt: string;
+	}> {
+		return this.lgsPost('/lgs/record/start', { sessionID });
+	}
+
+	// Returns the recording; save it as a file to replay it later
+	async lgsStopRecording(sessionID: string): Promise<LGSSessionRecording> {
+		return this.lgsPost('/lgs/record/stop', { sessionID });
+	}
+
+	async lgsReplayRecording(recording: LGSSessionRecording): Promise<LGSReplayReport> {
+		return this.lgsPost('/lgs/record/replay', recording);
+	}
+
+	// Plays in a configured mode reject bets outside its levels
+	async lgsSetBetLevels(modes: Record<string, Partial<LGSBetLevelConfig>>, replace = false): Promise<LGSBetLevels> {
+		return this.lgsPost('/lgs/config/bet-levels', { modes, replace });
+	}
+
+	async lgsGetBetLevels(): Promise<LGSBetLevels> {
+		return this.lgsGet('/lgs/config/bet-levels');
+	}
+
+	async lgsClearBetLevels(mode?: string): Promise<{ success: boolean; message: string }> {
+		const query = mode ? `?mode=${encodeURIComponent(mode)}` : '';
+		return this.lgsDelete(`/lgs/config/bet-levels${query}`);
+	}
+
+	// Sessions play in one currency; balances convert until the first bet
+	async lgsGetCurrencies(): Promise<LGSCurrencies> {
+		return this.lgsGet('/lgs/config/currencies');
+	}
+
+	async lgsSetCurrencies(currencies: LGSCurrency[], replace = false): Promise<LGSCurrencies> {
+		return this.lgsPost('/lgs/config/currencies', { currencies, replace });
+	}
+
+	async lgsResetCurrencies(): Promise<{ success: boolean; message: string }> {
+		return this.lgsDelete('/lgs/config/currencies');
+	}
+
+	// Latency, errors, dropped requests and duplicate bet IDs on /wallet and /bet
+	async lgsGetFaults(): Promise<LGSFaults> {
+		return this.lgsGet('/lgs/faults');
+	}
+
+	async lgsSetFaults(rules: LGSFaultRule[], replace = false): Promise<LGSFaults> {
+		return this.lgsPost('/lgs/faults', { rules, replace });
+	}
+
+	async lgsClearFaults(endpoint?: string): Promise<{ success: boolean; message: string }> {
+		const query = endpoint ? `?endpoint=${encodeURIComponent(endpoint)}` : '';
+		return this.lgsDelete(`/lgs/faults${query}`);
+	}
+
+	async lgsGetJackpots(): Promise<LGSJackpots> {
+		return this.lgsGet('/lgs/config/jackpots');
+	}
+
+	// Setting a pool seeds its value
+	async lgsSetJackpots(pools: LGSJackpotConfig[], replace = false): Promise<LGSJackpots> {
+		return this.lgsPost('/lgs/config/jackpots', { pools, replace });
+	}
+
+	async lgsResetJackpots(name?: string): Promise<LGSJackpots> {
+		const query = name ? `?name=${encodeURIComponent(name)}` : '';
+		return this.lgsPost(`/lgs/jackpots/reset${query}`);
+	}
+
+	async lgsClearJackpots(name?: string): Promise<{ success: boolean; message: string }> {
+		const query = name ? `?name=${encodeURIComponent(name)}` : '';
+		return this.lgsDelete(`/lgs/config/jackpots${query}`);
+	}
+
 	async lgsSetRTPBias(sessionID: string, bias: number): Promise<{
 		success: boolean;
 		message: string;
@@ -286,6 +808,88 @@ class LutApiClient {
 		return this.lgsGet(`/lgs/rtp-bias?sessionID=${encodeURIComponent(sessionID)}`);
 	}
 
+	/** Selects the table variant a session plays in a mode; '' plays the table on disk. */
+	async lgsSetVariant(sessionID: string, mode: string, variant: string): Promise<{
+		success: boolean;
+		sessionID: string;
+		mode: string;
+		variant: string;
+	}> {
+		return this.lgsPost('/lgs/variant', { sessionID, mode, variant });
+	}
+
+	async lgsGetVariants(sessionID: string): Promise<{
+		sessionID: string;
+		variants: Record<string, string>;
+	}> {
+		return this.lgsGet(`/lgs/variant?sessionID=${encodeURIComponent(sessionID)}`);
+	}
+
+	// Aggregate bets/wins/RTP across all sessions per interval, oldest first
+	async lgsStatsTimeseries(since?: number): Promise<{ intervalMs: number; points: LGSStatsPoint[] }> {
+		return this.lgsGet(`/lgs/stats/timeseries${since !== undefined ? `?since=${since}` : ''}`);
+	}
+
+	async lgsGetPresets(): Promise<{ presets: LGSBiasPreset[] }> {
+		return this.lgsGet('/lgs/presets');
+	}
+
+	// Applies bias, balance and forced outcomes of a preset in one call.
+	// mode is required when the preset forces outcomes.
+	async lgsApplyPreset(sessionID: string, preset: string, mode?: string): Promise<{
+		success: boolean;
+		message: string;
+		sessionID: string;
+		preset: string;
+		bias: number;
+		balance: { amount: number; currency: string };
+		forcedSimIDs: number[] | null;
+	}> {
+		return this.lgsPost('/lgs/presets/apply', { sessionID, preset, mode });
+	}
+
+	// Event transforms of the open library; pick one per request with ?transform=
+	async lgsGetTransforms(): Promise<LGSEventTransforms> {
+		return this.lgsGet('/lgs/transforms');
+	}
+
+	async lgsSetTransforms(config: LGSEventTransforms): Promise<LGSEventTransforms> {
+		const response = await fetch(`${this.baseUrl}/lgs/transforms`, {
+			method: 'PUT',
+			headers: {
+				'Content-Type': 'application/json'
+			},
+			body: JSON.stringify(config)
+		});
+		return response.json();
+	}
+
+	// Record /wallet and /bet traffic into a cassette, or serve one back verbatim
+	async lgsCassettes(): Promise<{ cassettes: LGSCassetteInfo[]; tape: LGSTapeStatus }> {
+		return this.lgsGet('/lgs/cassettes');
+	}
+
+	async lgsRecordCassette(name: string): Promise<LGSTapeStatus> {
+		return this.lgsPost('/lgs/cassettes/record', { name });
+	}
+
+	async lgsReplayCassette(name: string): Promise<LGSTapeStatus> {
+		return this.lgsPost('/lgs/cassettes/replay', { name });
+	}
+
+	// Saves the cassette when recording; returns the state before stopping
+	async lgsStopCassette(): Promise<LGSTapeStatus> {
+		return this.lgsPost('/lgs/cassettes/stop');
+	}
+
+	async lgsGetCassette(name: string): Promise<LGSCassette> {
+		return this.lgsGet(`/lgs/cassettes/${encodeURIComponent(name)}`);
+	}
+
+	async lgsDeleteCassette(name: string): Promise<{ success: boolean; deleted: string }> {
+		return this.lgsDelete(`/lgs/cassettes/${encodeURIComponent(name)}`);
+	}
+
 	// ============ Background Loader Methods ============
 
 	async loaderStatus(): Promise<LoaderStatusResponse> {
@@ -319,6 +923,31 @@ class LutApiClient {
 		return this.post('/api/reload');
 	}
 
+	// Library API
+	async getLibrary(): Promise<LibraryInfo> {
+		return this.fetch('/api/library');
+	}
+
+	async openLibrary(path: string): Promise<LibraryInfo> {
+		return this.postJson('/api/library/open', { path });
+	}
+
+	async closeLibrary(): Promise<LibraryInfo> {
+		return this.post('/api/library/close');
+	}
+
+	async switchLibrary(path: string): Promise<LibraryInfo> {
+		return this.postJson('/api/library/switch', { path });
+	}
+
+	async getRecentLibraries(): Promise<RecentLibrary[]> {
+		return this.fetch('/api/library/recent');
+	}
+
+	async forgetRecentLibrary(path: string): Promise<RecentLibrary[]> {
+		return this.sendJson('DELETE', `/api/library/recent?path=${encodeURIComponent(path)}`);
+	}
+
 	// WebSocket URL
 	getWebSocketUrl(): string {
 		const url = new URL(this.baseUrl);
@@ -328,12 +957,28 @@ class LutApiClient {
 
 	// ============ Compliance Methods ============
 
-	async getModeCompliance(mode: string): Promise<ComplianceResult> {
-		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/compliance`);
+	// profile is a preset name or a (partial) custom profile
+	async getModeCompliance(mode: string, profile?: string | Partial<ComplianceProfile>): Promise<ComplianceResult> {
+		return this.fetch(`/api/mode/${encodeURIComponent(mode)}/compliance${this.complianceQuery(profile)}`);
+	}
+
+	async getAllCompliance(profile?: string | Partial<ComplianceProfile>): Promise<AllModesComplianceResult> {
+		return this.fetch(`/api/compliance${this.complianceQuery(profile)}`);
+	}
+
+	getComplianceReportUrl(mode: string, format: 'pdf' | 'html' = 'pdf', profile?: string | Partial<ComplianceProfile>): string {
+		const qs = this.complianceQuery(profile);
+		return `${this.baseUrl}/api/mode/${encodeURIComponent(mode)}/compliance/report${qs ? `${qs}&` : '?'}format=${format}`;
+	}
+
+	async getComplianceProfiles(): Promise<ComplianceProfilesResponse> {
+		return this.fetch('/api/compliance/profiles');
 	}
 
-	async getAllCompliance(): Promise<AllModesComplianceResult> {
-		return this.fetch('/api/compliance');
+	private complianceQuery(profile?: string | Partial<ComplianceProfile>): string {
+		if (!profile) return '';
+		const value = typeof profile === 'string' ? profile : JSON.stringify(profile);
+		return `?profile=${encodeURIComponent(value)}`;
 	}
 
 	// ============ CrowdSim Methods ============
@@ -400,10 +1045,18 @@ class LutApiClient {
 	/**
 	 * Apply weights to a mode
 	 */
-	async optimizerApply(mode: string, weights: number[], createBackup?: boolean): Promise<{ saved: boolean; backup_path?: string }> {
+	async optimizerApply(
+		mode: string,
+		weights: number[],
+		createBackup?: boolean,
+		options?: { validateCompliance?: boolean; complianceProfile?: string; dryRun?: boolean }
+	): Promise<{ saved: boolean; backup_path?: string; message?: string; compliance?: ComplianceResult }> {
 		return this.postJson(`/api/optimizer/${encodeURIComponent(mode)}/apply`, {
 			weights,
-			create_backup: createBackup ?? true
+			create_backup: createBackup ?? true,
+			validate_compliance: options?.validateCompliance,
+			compliance_profile: options?.complianceProfile,
+			dry_run: options?.dryRun
 		});
 	}
 
@@ -424,6 +1077,34 @@ class LutApiClient {
 		});
 	}
 
+	/**
+	 * Compare a backup's weights with the current weights, or with another
+	 * weight file given as against
+	 */
+	async optimizerDiff(mode: string, backupFile: string, options?: { against?: string; limit?: number }): Promise<WeightDiffResponse> {
+		const params = new URLSearchParams({ backup: backupFile });
+		if (options?.against) params.set('against', options.against);
+		if (options?.limit !== undefined) params.set('limit', String(options.limit));
+		return this.fetch(`/api/optimizer/${encodeURIComponent(mode)}/diff?${params}`);
+	}
+
+	/**
+	 * Rewrite a mode's weights as smaller integers within an RTP tolerance;
+	 * saved to the LUT file when saveToFile is set
+	 */
+	async optimizerNormalize(
+		mode: string,
+		options: NormalizeOptions,
+		saveToFile?: boolean,
+		createBackup?: boolean
+	): Promise<NormalizeResponse> {
+		return this.postJson(`/api/optimizer/${encodeURIComponent(mode)}/normalize`, {
+			...options,
+			save_to_file: saveToFile ?? false,
+			create_backup: createBackup ?? true
+		});
+	}
+
 	// ============ Mode Analysis Methods ============
 
 	/**
@@ -498,6 +1179,38 @@ class LutApiClient {
 		return this.postJson(`/api/optimizer/${encodeURIComponent(mode)}/bucket-optimize`, config);
 	}
 
+	/**
+	 * Fit a mode's weights to a target payout band distribution.
+	 * csv needs the columns min_payout, max_payout, probability (name optional).
+	 */
+	async fitDistribution(
+		mode: string,
+		target: { csv?: string; bands?: TargetBand[] },
+		options?: { saveToFile?: boolean; createBackup?: boolean }
+	): Promise<FitDistributionResponse> {
+		return this.postJson(`/api/optimizer/${encodeURIComponent(mode)}/fit-distribution`, {
+			csv: target.csv,
+			bands: target.bands,
+			save_to_file: options?.saveToFile ?? false,
+			create_backup: options?.createBackup ?? true
+		});
+	}
+
+	/**
+	 * Search for weights balancing RTP, hit rate and volatility; returns a Pareto set.
+	 */
+	async multiObjectiveOptimize(
+		mode: string,
+		config: MultiObjectiveConfig,
+		options?: { saveToFile?: boolean; createBackup?: boolean }
+	): Promise<MultiObjectiveResponse> {
+		return this.postJson(`/api/optimizer/${encodeURIComponent(mode)}/multi-objective`, {
+			...config,
+			save_to_file: options?.saveToFile ?? false,
+			create_backup: options?.createBackup ?? true
+		});
+	}
+
 	/**
 	 * Get suggested bucket configuration for a mode
 	 */
@@ -15,8 +15,52 @@ export interface ModeSummary {
 	max_payout: number;
 }
 
+// Mode entry as in index.json, for POST /api/modes
+export interface ModeRegistration {
+	name: string;
+	cost?: number;
+	weights: string;
+	events?: string;
+}
+
+export interface ModeSize {
+	mode: string;
+	lut_bytes: number;
+	books_bytes: number;
+	decompressed_bytes: number; // estimated
+	memory_bytes: number; // estimated, books fully loaded
+	load_seconds: number; // estimated at high priority
+	load_ms?: number; // measured, once books were loaded
+	missing?: string[];
+}
+
+export interface LibrarySize {
+	modes: ModeSize[];
+	lut_bytes: number;
+	books_bytes: number;
+	decompressed_bytes: number;
+	memory_bytes: number;
+	load_seconds: number;
+}
+
 export interface IndexInfo {
 	modes: ModeSummary[];
+	size: LibrarySize;
+}
+
+export interface LibraryInfo {
+	open: boolean;
+	library?: string;
+	index_path?: string;
+	modes: ModeSummary[];
+}
+
+export interface RecentLibrary {
+	path: string;
+	index_path: string;
+	game_id?: string;
+	modes: string[];
+	opened_at: string;
 }
 
 export interface PayoutBucket {
@@ -108,13 +152,60 @@ export interface EventInfo {
 	event_missing?: boolean;
 	lazy_load?: boolean;      // true if event was loaded on-demand (not from full cache)
 	no_events_file?: boolean; // true if mode has no events file configured
+	synthetic?: boolean;      // true if event is a generated payout-only book
 	error?: string;           // error message if lazy loading failed
 }
 
+// Bookmarked outcome with a free-text note and tags
+export interface Bookmark {
+	id: string;
+	mode: string;
+	sim_id: number;
+	note: string;
+	tags: string[];
+	created_at: string;
+	updated_at: string;
+}
+
+// Event search predicate: path is a JSON-path subset like "events[*].type"
+export interface EventPredicate {
+	path: string;
+	op: 'eq' | 'ne' | 'gt' | 'gte' | 'lt' | 'lte' | 'contains' | 'exists' | 'not_exists'
+		| 'count_eq' | 'count_gt' | 'count_gte' | 'count_lt' | 'count_lte';
+	value?: unknown;
+	where?: unknown; // only count values equal to this (count ops)
+}
+
+export type PayoutBucketName = 'zero' | 'sub_bet' | 'win' | 'big_win' | 'mega_win';
+
+// Outcome table download formats for /api/mode/{mode}/export (omit for the JSON bundle)
+export type OutcomeExportFormat = 'csv' | 'jsonl' | 'parquet';
+
+// Random outcomes drawn from a payout band (min inclusive, max exclusive)
+export interface OutcomeSample {
+	mode: string;
+	band: { name?: string; min: number; max?: number };
+	weighted: boolean;
+	seed: number;
+	candidates: number;
+	band_weight: number;
+	band_probability: number;
+	outcomes: { sim_id: number; payout: number; weight: number; probability: number }[];
+}
+
+export interface EventQuery {
+	predicates: EventPredicate[];
+	match?: 'all' | 'any';
+	min_payout?: number;
+	max_payout?: number;
+	limit?: number;
+}
+
 // LGS (Local Game Server) types
 export interface LGSBalance {
 	amount: number;
 	currency: string;
+	formatted?: string; // e.g. "€12.50", for the request's Accept-Language
 }
 
 export interface LGSConfig {
@@ -144,6 +235,18 @@ export interface LGSRound {
 	active: boolean;
 	mode: string;
 	event?: unknown;
+	progress?: LGSRoundProgress; // rounds with a feature, paid on end-round
+}
+
+// Progress of a multi-stage round through the events of its book
+export interface LGSRoundProgress {
+	phase: 'base' | 'pending_feature' | 'feature' | 'settled';
+	eventIndex: number; // -1 before the first /bet/event
+	events: number;
+	triggerIndex: number;
+	featureSpinIndexes: number[];
+	featureSpins: number;
+	featureSpinsPlayed: number;
 }
 
 export interface LGSAuthResponse {
@@ -156,6 +259,8 @@ export interface LGSAuthResponse {
 export interface LGSPlayResponse {
 	balance: LGSBalance;
 	round: LGSRound;
+	jackpots?: LGSJackpotValue[]; // pool values after the play
+	jackpotWins?: LGSJackpotWin[]; // included in round.payout
 }
 
 export interface LGSSessionSummary {
@@ -174,6 +279,8 @@ export interface LGSSessionSummary {
 	lastActivity: string;
 	forcedOutcomes: Record<string, number>;
 	rtpBias: number;
+	variants?: Record<string, string>; // mode -> table variant
+	convergence?: LGSConvergence[];
 }
 
 export interface LGSAggregateStats {
@@ -184,6 +291,12 @@ export interface LGSAggregateStats {
 	overallRTP: number;
 	overallHitRate: number;
 	totalProfit: number;
+	// Totals normalized to the base currency; overallRTP is taken from these
+	baseCurrency: string;
+	totalWageredBase: number;
+	totalWonBase: number;
+	totalProfitBase: number;
+	unconvertedSessions?: number;
 }
 
 export interface LGSSessionsResponse {
@@ -191,6 +304,7 @@ export interface LGSSessionsResponse {
 	totalSessions: number;
 	totalCreated: number;
 	aggregate: LGSAggregateStats;
+	jackpots?: LGSJackpotValue[];
 }
 
 export interface LGSStatsResponse {
@@ -202,6 +316,31 @@ export interface LGSStatsResponse {
 	rtp: number;
 	balance: number;
 	currency: string;
+	convergence: LGSConvergence[] | null;
+}
+
+// Observed vs theoretical RTP of a session in a mode; forced and biased
+// plays are left out
+export interface LGSConvergence {
+	mode: string;
+	spins: number;
+	observedRTP: number;
+	theoreticalRTP: number;
+	stdDev: number;
+	standardError: number;
+	confidence: number;
+	rtpLow: number;
+	rtpHigh: number;
+	zScore: number;
+	significant: boolean; // deviation unlikely to be chance
+	observedHitRate: number;
+	theoreticalHitRate: number;
+	hitRateLow: number; // Wilson interval
+	hitRateHigh: number;
+	tolerance: number;
+	spinsToConverge: number;
+	remainingSpins: number;
+	converged: boolean;
 }
 
 // Batch Play types
@@ -227,6 +366,42 @@ export interface LGSBatchPlayResponse {
 	balance: LGSBalance;
 	rounds?: LGSBatchPlayRound[];
 	durationMs: number;
+	jackpots?: LGSJackpotValue[]; // pool values after the batch
+	jackpotWins?: LGSJackpotWin[]; // included in totalWon
+}
+
+export interface LGSBatchStreamStarted {
+	success: boolean;
+	batchID: string;
+	sessionID: string;
+	mode: string;
+	spins: number;
+	progressEvery: number;
+}
+
+// Payload of 'lgs_batch_progress' WebSocket messages
+export interface LGSBatchProgress {
+	batchID: string;
+	sessionID: string;
+	mode: string;
+	spinsDone: number;
+	spins: number;
+	totalWagered: number;
+	totalWon: number;
+	hitCount: number;
+	hitRate: number;
+	rtp: number;
+	maxWin: number;
+	balance: number;
+	elapsedMs: number;
+}
+
+// Payload of 'lgs_batch_complete' WebSocket messages
+export interface LGSBatchComplete {
+	batchID: string;
+	cancelled: boolean;
+	outOfBalance: boolean; // stopped when the balance could not cover a spin
+	result: LGSBatchPlayResponse;
 }
 
 // Background Loader types
@@ -242,6 +417,7 @@ export interface LoaderModeStatus {
 	error?: string;
 	started_at?: number;
 	completed_at?: number;
+	reloading?: boolean; // previous events keep serving until the new file loads
 }
 
 export interface MemoryEstimate {
@@ -263,6 +439,7 @@ export interface LoaderStatusResponse {
 export interface LoaderPriorityResponse {
 	priority: 'low' | 'high';
 	description: string;
+	workers: number; // modes decoded in parallel in high priority
 }
 
 export interface LoaderBoostResponse {
@@ -270,6 +447,47 @@ export interface LoaderBoostResponse {
 	message: string;
 }
 
+// Payload of 'lut_drift_warning': a watcher reload moved RTP past the threshold
+export interface ModeDrift {
+	mode: string;
+	before: ModeSummary;
+	after: ModeSummary;
+	rtp_delta: number;
+	hit_rate_delta: number;
+	threshold: number;
+	exceeded: boolean;
+}
+
+// Payload of 'table_updated': one outcome's weight was edited in place
+export interface TableUpdate {
+	sim_id: number;
+	weight: number;
+	old_weight: number;
+	saved: boolean;
+	total_weight: number;
+	rtp: number;
+	hit_rate: number;
+	volatility: number;
+}
+
+export interface OutcomeWeightResponse {
+	sim_id: number;
+	weight: number;
+	old_weight: number;
+	saved: boolean;
+	stats: Statistics;
+}
+
+// Result of importing a state archive
+export interface StateImportResult {
+	library: string; // folder name of the exporting library
+	exported_at: string;
+	sessions: number;
+	variants: number;
+	files: string[];
+	skipped: string[];
+}
+
 // WebSocket message types
 export type WSMessageType =
 	| 'loading_started'
@@ -280,9 +498,73 @@ export type WSMessageType =
 	| 'reload_started'
 	| 'lgs_session_update'
 	| 'lgs_sessions_update'
+	| 'lgs_batch_progress'
+	| 'lgs_batch_complete'
+	| 'lgs_jackpot_hit'
+	| 'lut_drift_warning'
+	| 'table_updated'
+	| 'lock_acquired'
+	| 'lock_released'
+	| 'client_connected'
 	| 'crowdsim_progress'
 	| 'optimizer_progress';
 
+// Advisory lock on a mode (or the whole library when mode is absent)
+export interface SoftLock {
+	mode?: string;
+	holder: string;
+	action: string;
+	since: string;
+	expires_at: string;
+	message: string; // e.g. "Alice is currently optimizing mode base"
+}
+
+export interface PresenceInfo {
+	clients: { name: string; last_seen: string }[];
+	locks: SoftLock[];
+}
+
+// Backend log line from /api/logs; the level is inferred from the message
+export type LogLevel = 'debug' | 'info' | 'warn' | 'error';
+
+export interface LogLine {
+	seq: number;
+	time: string;
+	level: LogLevel;
+	message: string;
+}
+
+// History kept by a backend started with -data-dir
+export interface OptimizerRun {
+	id: number;
+	library: string;
+	mode: string;
+	kind: 'bucket' | 'fit-distribution' | 'multi-objective';
+	created_at: string;
+	request: unknown;
+	result: unknown;
+}
+
+export interface ComplianceRun {
+	id: number;
+	library: string;
+	mode: string;
+	profile: string;
+	passed: boolean;
+	created_at: string;
+	result: unknown;
+}
+
+export interface AuditEntry {
+	id: number;
+	at: string;
+	client: string;
+	method: string;
+	path: string;
+	status: number;
+	duration_ms: number;
+}
+
 export interface WSMessage {
 	type: WSMessageType;
 	mode?: string;
@@ -368,6 +650,7 @@ export interface ComplianceSummary {
 
 export interface ComplianceResult {
 	mode: string;
+	profile: string;
 	passed: boolean;
 	passed_count: number;
 	failed_count: number;
@@ -378,10 +661,35 @@ export interface ComplianceResult {
 
 export interface AllModesComplianceResult {
 	all_passed: boolean;
+	profile: string;
 	mode_results: Record<string, ComplianceResult>;
 	global_checks: ComplianceCheck[];
 }
 
+// Jurisdiction thresholds for compliance checks. Custom profiles are passed as
+// JSON and fall back to the preset named by `extends` for unset fields.
+export interface ComplianceProfile {
+	name: string;
+	description?: string;
+	extends?: string;
+	min_rtp: number;
+	max_rtp: number;
+	max_rtp_variation: number;
+	max_win_odds: number; // cost-1 modes; divided by cost for bonus modes
+	min_hit_rate: number;
+	max_hit_rate: number;
+	min_unique_payouts: number;
+	max_single_outcome_probability: number;
+	max_zero_payout_rate: number;
+	max_volatility: number;
+	severities?: Partial<Record<ComplianceCheckID, ComplianceSeverity | 'off'>>;
+}
+
+export interface ComplianceProfilesResponse {
+	profiles: ComplianceProfile[];
+	default: string;
+}
+
 // Mode info for cost-aware display
 export interface ModeInfo {
 	cost: number;
@@ -404,6 +712,8 @@ export interface CrowdSimConfig {
 	use_crypto_rng: boolean;
 	streaming_mode: boolean;
 	parallel_workers: number;
+	workers?: number; // alias of parallel_workers
+	seed?: number; // omit for a random seed; echoed in the result config
 }
 
 export interface CrowdSimBalanceBucket {
@@ -476,6 +786,13 @@ export interface CrowdSimPlayerSummary {
 	actual_rtp: number;
 }
 
+export interface CrowdSimNarrativeLine {
+	key: string;
+	text: string;
+	value: number;
+	severity: 'info' | 'warning';
+}
+
 export type CrowdSimVolatilityProfile = 'low' | 'medium' | 'high';
 
 export interface CrowdSimBalanceCurvePoint {
@@ -514,6 +831,9 @@ export interface CrowdSimResult {
 	volatility_profile: CrowdSimVolatilityProfile;
 	composite_score: number;
 
+	// Plain-language summary of the player experience
+	narrative?: CrowdSimNarrativeLine[];
+
 	// Detailed Data
 	player_summaries?: CrowdSimPlayerSummary[];
 }
@@ -606,6 +926,88 @@ export interface OptimizerBackupInfo {
 	path: string;
 }
 
+// Weight diff between a backup and the current weights (deltas are new - old)
+export interface WeightSetStats {
+	total_weight: number;
+	rtp: number;
+	hit_rate: number;
+	volatility: number;
+	max_win_frequency: number; // 1 in N, 0 if the max win cannot hit
+}
+
+export interface OutcomeDiff {
+	sim_id: number;
+	payout: number;
+	old_weight: number;
+	new_weight: number;
+	weight_delta: number;
+	old_probability: number;
+	new_probability: number;
+	probability_delta: number;
+	rtp_delta: number;
+}
+
+export interface BucketDiff {
+	name: string;
+	min_payout: number;
+	max_payout: number;
+	outcome_count: number;
+	old_probability: number;
+	new_probability: number;
+	probability_delta: number;
+	old_rtp_contribution: number; // % of RTP
+	new_rtp_contribution: number;
+	rtp_contribution_delta: number;
+}
+
+export interface WeightDiff {
+	old: WeightSetStats;
+	new: WeightSetStats;
+	rtp_delta: number;
+	hit_rate_delta: number;
+	volatility_delta: number;
+	changed_outcomes: number;
+	outcomes: OutcomeDiff[]; // largest probability shift first
+	buckets: BucketDiff[];
+	loss: BucketDiff;
+}
+
+export interface WeightDiffResponse {
+	mode: string;
+	old_source: string;
+	new_source: string; // "current" or the compared file
+	diff: WeightDiff;
+}
+
+// Weight normalization: scale to a target total and/or divide by the GCD
+// (GCD only when no target total is given)
+export interface NormalizeOptions {
+	target_total?: number;  // e.g. 100000000
+	reduce_gcd?: boolean;
+	rtp_tolerance?: number; // Max RTP drift (default 0.0001)
+}
+
+export interface NormalizeResult {
+	original_total: number;
+	new_total: number;
+	scale: number;            // 1 without a target total
+	gcd: number;              // 1 if no GCD reduction
+	clamped_outcomes: number; // Non-zero weights kept at 1 instead of rounding to 0
+	loss_adjusted: boolean;   // Loss weights rebalanced to cancel rounding drift
+	original_rtp: number;
+	new_rtp: number;
+	rtp_drift: number;
+	rtp_tolerance: number;
+	pinned_outcomes?: PinnedOutcome[]; // Pinned weights after normalization (bucket optimize only)
+}
+
+export interface NormalizeResponse {
+	normalize: NormalizeResult;
+	saved: boolean;
+	backup_path?: string;
+	weights?: number[]; // when not saved
+}
+
 // ============================================================================
 // Bucket Optimizer Types
 // ============================================================================
@@ -648,6 +1050,17 @@ export interface BucketOptimizeRequest {
 	voided_bucket_indices?: number[]; // DEPRECATED: Indices of buckets to void
 	// Auto-voiding (recommended)
 	enable_auto_voiding?: boolean;  // Enable automatic outcome voiding to reach target RTP
+	// Compliance re-check of the result; failing weights are not saved
+	validate_compliance?: boolean;
+	compliance_profile?: string;    // Preset name or JSON profile
+	pinned_outcomes?: PinnedOutcome[]; // Outcomes kept at a fixed weight (still counted in RTP)
+	normalize?: NormalizeOptions;   // Rewrite the result as smaller integer weights
+}
+
+// Outcome whose weight the optimizer must not change
+export interface PinnedOutcome {
+	sim_id: number;
+	weight: number;
 }
 
 // Result for a single bucket after optimization
@@ -708,13 +1121,18 @@ export interface BucketOptimizeResult {
 	voided_outcomes?: VoidedOutcomeInfo[]; // Individual outcomes that were auto-voided
 	total_voided?: number;                 // Total count of voided outcomes
 	voided_rtp?: number;                   // Total RTP removed by voiding
+	pinned_rtp?: number;                   // RTP contributed by pinned outcomes
+	max_win_frequency?: number;            // Achieved global max win frequency (1 in N)
+	normalize?: NormalizeResult;           // when normalize was set
 	config: {
 		target_rtp: number;
 		buckets: BucketConfig[];
 	};
+	compliance?: ComplianceResult; // when validate_compliance was set
 	save_result?: {
 		saved: boolean;
 		backup_path?: string;
+		reason?: string;
 	};
 }
 
@@ -738,6 +1156,87 @@ export interface BucketPresetsResponse {
 	aggressive: BucketConfig[];
 }
 
+// Target distribution fit types (POST /api/optimizer/{mode}/fit-distribution)
+export interface TargetBand {
+	name: string;
+	min_payout: number;
+	max_payout: number; // 0 = unbounded
+	probability: number;
+}
+
+export interface TargetBandFit extends TargetBand {
+	outcome_count: number;
+	original_probability: number;
+	actual_probability: number;
+	deviation: number;
+	relative_deviation: number;
+}
+
+export interface TargetFitResult {
+	original_rtp: number;
+	final_rtp: number;
+	new_weights: number[];
+	total_weight: number;
+	bands: TargetBandFit[];
+	max_deviation: number;
+	uncovered_outcomes: number;
+	uncovered_probability: number;
+	warnings?: string[];
+}
+
+export interface FitDistributionResponse {
+	result: TargetFitResult;
+	mode_info: { cost: number; note: string };
+	save_result?: { saved: boolean; backup_path?: string };
+}
+
+// Multi-objective optimizer types (POST /api/optimizer/{mode}/multi-objective)
+export interface MultiObjectiveConfig {
+	target_rtp: number;
+	target_hit_rate: number;
+	target_volatility: number;
+	rtp_weight: number;
+	hit_rate_weight: number;
+	volatility_weight: number;
+	iterations?: number;
+	chains?: number;
+	pareto_size?: number;
+	seed?: number;
+	include_weights?: boolean;
+}
+
+export interface ObjectiveMetrics {
+	rtp: number;
+	hit_rate: number;
+	volatility: number;
+	rtp_error: number;
+	hit_rate_error: number;
+	volatility_error: number;
+	score: number;
+}
+
+export interface ParetoCandidate extends ObjectiveMetrics {
+	weights?: number[];
+}
+
+export interface MultiObjectiveResult {
+	original: ObjectiveMetrics;
+	pareto: ParetoCandidate[]; // best first
+	ideal: ObjectiveMetrics;
+	best_weights: number[];
+	total_weight: number;
+	iterations: number;
+	duration_ms: number;
+	warnings?: string[];
+}
+
+export interface MultiObjectiveResponse {
+	result: MultiObjectiveResult;
+	config: MultiObjectiveConfig;
+	mode_info: { cost: number; note: string };
+	save_result?: { saved: boolean; backup_path?: string };
+}
+
 // Bucket Distribution API types
 export interface BucketDistributionResponse {
 	range_start: number;
@@ -992,3 +1491,360 @@ export interface GenerateConfigsAnalysis {
 	is_bonus_mode: boolean;
 	suggested_void_buckets?: VoidSuggestion[]; // Suggestions for voiding when RTP unreachable
 }
+
+// LGS draw coverage of a mode's book (see /api/mode/{mode}/sampling)
+export interface ChiSquareResult {
+	statistic: number;
+	df: number;
+	p_value: number;
+	biased: boolean; // p_value below 0.01
+}
+
+export interface SamplingBin {
+	payouts: string; // e.g. "2x-5x"
+	outcomes: number;
+	expected: number;
+	observed: number;
+}
+
+export interface SimIDDraws {
+	sim_id: number;
+	payout: number;
+	draws: number;
+	expected: number;
+}
+
+export interface SamplingReport {
+	mode: string;
+	draws: number;
+	outcomes: number;
+	outcomes_hit: number;
+	never_hit: number;
+	coverage: number; // share of outcomes drawn at least once
+	expected_coverage: number; // same for an unbiased sampler
+	weight_coverage: number;
+	unknown_draws: number;
+	skipped_draws: number; // forced, biased and variant spins
+	chi_square?: ChiSquareResult;
+	bins: SamplingBin[];
+	top: SimIDDraws[];
+}
+
+export interface MaxWinPercentile {
+	percentile: number;
+	spins: number;
+	turnover: number;
+}
+
+// In-memory table variant of a mode (A/B comparison in LGS sessions)
+export interface ModeVariant {
+	name: string;
+	mode: string;
+	created_at: number; // unix ms
+	outcomes: number;
+	total_weight: number;
+	rtp: number;
+	hit_rate: number;
+}
+
+export interface MaxWinEstimate {
+	mode: string;
+	cost: number;
+	bet: number;
+	max_win: number;
+	threshold: number;
+	outcomes: number;
+	probability: number;
+	odds: string;
+	expected_spins: number;
+	expected_turnover: number;
+	expected_loss: number;
+	percentiles: MaxWinPercentile[];
+	summary: string;
+}
+
+export interface LGSBiasPreset {
+	name: string;
+	description: string;
+	bias: number;
+	balance?: number; // API units (1000000 = $1); omitted keeps the balance
+	resetStats: boolean;
+	force?: PayoutBucketName[];
+}
+
+// Reshapes round state for a game client generation (see /lgs/transforms)
+export interface LGSEventTransform {
+	name: string;
+	description?: string;
+	versions?: string[]; // client version globs used on replay, e.g. "1.*"
+	keyCase?: 'camel' | 'snake';
+	rename?: Record<string, string>; // book field -> client field, at any depth
+	wrap?: unknown; // JSON template; "$events" and "$book" are substituted
+}
+
+export interface LGSEventTransforms {
+	default?: string;
+	transforms: LGSEventTransform[];
+}
+
+// Scripted outcomes of the next plays (see /lgs/scenario). A step sets
+// either simID or a payout multiplier range; maxPayout 0 forces a loss.
+export interface LGSScenarioStep {
+	mode: string;
+	simID?: number;
+	minPayout?: number;
+	maxPayout?: number;
+	repeat?: number;
+}
+
+export interface LGSScenario {
+	sessionID: string;
+	steps: LGSScenarioStep[];
+	spins: number;
+	message?: string;
+}
+
+// Plays of a session captured by /lgs/record/start and /stop; amounts in API units
+export interface LGSRecordedPlay {
+	seq: number;
+	mode: string;
+	variant?: string;
+	simID: number;
+	amount: number;
+	bet: number;
+	payout: number;
+	payoutMultiplier: number;
+	probability: number;
+	balance: number;
+	forced?: boolean;
+	batch?: boolean;
+}
+
+export interface LGSSessionRecording {
+	version: number;
+	sessionID: string;
+	currency: string;
+	startBalance: number;
+	startedAt: string;
+	stoppedAt?: string;
+	truncated?: boolean;
+	plays: LGSRecordedPlay[];
+}
+
+export type LGSDivergenceKind =
+	| 'mode_missing'
+	| 'sim_missing'
+	| 'payout_changed'
+	| 'cost_changed'
+	| 'unreachable'
+	| 'weight_changed'
+	| 'balance_changed';
+
+export interface LGSPlayDivergence {
+	seq: number;
+	mode: string;
+	simID: number;
+	kind: LGSDivergenceKind;
+	message: string;
+	recorded: number;
+	current: number;
+}
+
+export interface LGSReplayReport {
+	sessionID: string;
+	plays: number;
+	matched: number;
+	diverged: number;
+	kinds: Partial<Record<LGSDivergenceKind, number>>;
+	divergences: LGSPlayDivergence[];
+	recordedBalance: number;
+	replayedBalance: number;
+	recordedRTP: number;
+	replayedRTP: number;
+	likelihoodRatio: number;
+}
+
+// Bet configuration of a mode; amounts are per play before the mode cost,
+// in API units (1000000 = 1 currency unit)
+export interface LGSBetLevelConfig {
+	minBet: number;
+	maxBet: number;
+	stepBet: number;
+	defaultBetLevel: number;
+	betLevels?: number[];
+}
+
+export interface LGSBetLevels {
+	modes: Record<string, LGSBetLevelConfig>;
+	message?: string;
+}
+
+// A currency sessions can play in; rate is the value of one unit in the
+// base currency
+export interface LGSCurrency {
+	code: string;
+	decimals: number;
+	symbol?: string;
+	rate: number;
+}
+
+// Progressive jackpot pool; amounts are in API units of the base currency
+export interface LGSJackpotConfig {
+	name: string;
+	mode?: string; // empty: every mode
+	contributionPercent: number;
+	seed: number;
+	triggerSimIDs?: number[];
+	triggerMultiplier?: number;
+}
+
+export interface LGSJackpotPool extends LGSJackpotConfig {
+	value: number;
+	hits: number;
+	lastHit?: LGSJackpotWin;
+}
+
+export interface LGSJackpotValue {
+	name: string;
+	value: number;
+	currency: string;
+}
+
+// Payload of 'lgs_jackpot_hit' WebSocket messages; amount is in the
+// session's currency
+export interface LGSJackpotWin {
+	name: string;
+	sessionID: string;
+	mode: string;
+	simID: number;
+	amount: number;
+	currency: string;
+	at: string;
+}
+
+export interface LGSJackpots {
+	baseCurrency: string;
+	pools: LGSJackpotPool[];
+	message?: string;
+}
+
+// Fault injection rule for a wallet or bet endpoint ('*' for all);
+// probabilities are 0-1
+export interface LGSFaultRule {
+	endpoint: string;
+	delayMs?: number;
+	delayJitterMs?: number;
+	delayProbability?: number;
+	errorProbability?: number;
+	errorStatus?: number;
+	dropProbability?: number;
+	duplicateRoundProbability?: number;
+}
+
+export interface LGSFaultStatus extends LGSFaultRule {
+	injected: { delayed: number; errors: number; dropped: number; duplicated: number };
+}
+
+export interface LGSFaults {
+	rules: LGSFaultStatus[];
+	message?: string;
+}
+
+export interface LGSCurrencies {
+	baseCurrency: string;
+	currencies: LGSCurrency[];
+	message?: string;
+}
+
+// Result of POST /api/scenarios/run; amounts are in currency units
+export interface ScenarioStepResult {
+	step: number;
+	action: 'set_balance' | 'force' | 'play' | 'assert_rtp' | 'reload';
+	summary: string;
+	passed: boolean;
+	message: string;
+	duration_ms: number;
+}
+
+export interface ScenarioReport {
+	name: string;
+	session: string;
+	passed: boolean;
+	steps: ScenarioStepResult[];
+	skipped: number;
+	spins: number;
+	wagered: number;
+	won: number;
+	rtp: number;
+	balance: number;
+	duration_ms: number;
+}
+
+// Result of GET /api/selftest
+export interface SelfTestCheck {
+	name: 'table' | 'sampling' | 'events';
+	passed: boolean;
+	skipped?: boolean;
+	message: string;
+}
+
+export interface SelfTestMode {
+	mode: string;
+	passed: boolean;
+	checks: SelfTestCheck[];
+	duration_ms: number;
+}
+
+export interface SelfTestReport {
+	library: string;
+	passed: boolean;
+	modes: SelfTestMode[];
+	spins: number;
+	seed: number;
+	duration_ms: number;
+}
+
+// Recorded /wallet and /bet traffic (see /lgs/cassettes)
+export interface LGSCassetteInfo {
+	name: string;
+	recordedAt: string;
+	interactions: number;
+	size: number;
+}
+
+export interface LGSTapeStatus {
+	mode: 'off' | 'record' | 'replay';
+	cassette?: string;
+	interactions: number;
+	played: number;
+	misses: number; // replay requests without a recorded response
+}
+
+export interface LGSInteraction {
+	method: string;
+	path: string;
+	request?: unknown;
+	status: number;
+	contentType?: string;
+	response: unknown;
+}
+
+export interface LGSCassette {
+	name: string;
+	recordedAt: string;
+	interactions: LGSInteraction[];
+}
+
+export interface LGSStatsPoint {
+	time: number; // bucket start, unix ms
+	bets: number;
+	wins: number;
+	wagered: number;
+	won: number;
+	rtp: number;
+	hitRate: number;
+	cumulativeBets: number;
+	cumulativeWagered: number;
+	cumulativeWon: number;
+	cumulativeRTP: number;
+}
@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -37,14 +38,20 @@ type Status struct {
 	LibraryPath    string        `json:"libraryPath"`
 	FrontendPort   string        `json:"frontendPort"`
 	BackendPort    string        `json:"backendPort"`
+	HTTPSPort      string        `json:"httpsPort"`
 	IsProduction   bool          `json:"isProduction"`
-}
-
-type Config struct {
-	LibraryPath   string `json:"libraryPath"`
-	FrontendPort  string `json:"frontendPort"`
-	AutoLoadBooks bool   `json:"autoLoadBooks"`
-	Language      string `json:"language"`
+	// BackendRestarts is the number of automatic restarts since the last stable run
+	BackendRestarts int `json:"backendRestarts"`
+	// BackendHealth is the last polled health state (see health.go)
+	BackendHealth string `json:"backendHealth"`
+	// RemoteMode means the launcher connects to RemoteURL instead of spawning a backend
+	RemoteMode bool   `json:"remoteMode"`
+	RemoteURL  string `json:"remoteUrl,omitempty"`
+	// FrontendMode is how the frontend is served (dev, preview or static)
+	FrontendMode string `json:"frontendMode"`
+	// Live CPU/memory of the child processes, nil when not running
+	BackendResources  *ProcessResources `json:"backendResources"`
+	FrontendResources *ProcessResources `json:"frontendResources"`
 }
 
 // WatcherStatus represents the backend watcher status
@@ -54,14 +61,6 @@ type WatcherStatus struct {
 	Files     map[string]string `json:"files,omitempty"`
 }
 
-// PortStatus represents information about a port
-type PortStatus struct {
-	Port   string `json:"port"`
-	InUse  bool   `json:"inUse"`
-	PID    int    `json:"pid,omitempty"`
-	Killed bool   `json:"killed,omitempty"`
-}
-
 type App struct {
 	ctx          context.Context
 	mu           sync.Mutex
@@ -72,6 +71,28 @@ type App struct {
 	backendLogs  []string
 	frontendLogs []string
 	logMu        sync.Mutex
+	logFiles     map[string]*rotatingLog // persisted logs per source
+	logFollow    *LogQuery               // active FollowLogs subscription
+
+	// Backend health polling
+	health     BackendHealth
+	indexError string // fatal index load error seen in backend output
+
+	// System tray
+	tray     *trayMenu
+	quitting bool // set when quitting from the tray, bypasses minimize-to-tray
+
+	// Ports actually in use (may differ from defaults when those were busy)
+	backendPort  string
+	httpsPort    string
+	frontendPort string
+
+	// Backend crash recovery
+	backendStartedAt  time.Time
+	backendRestarts   int           // consecutive automatic restarts
+	backendRestarting bool          // waiting for backoff before a restart
+	backendFailed     bool          // gave up after MaxRestarts
+	restartCancel     chan struct{} // closed to abort a pending restart
 
 	// Production mode
 	dataDir          string       // Directory for extracted files
@@ -79,22 +100,45 @@ type App struct {
 	frontendDir      string       // Path to frontend static files
 	frontendServer   *http.Server // Static file server for frontend
 	frontendListener net.Listener
+	backendUpdate    *backendUpdate // downloaded backend replacing the bundled one
+
+	startedAtLogin bool // launched by the OS login item (window starts hidden)
+
+	// Child process resource usage (see resources.go)
+	backendResources  *ProcessResources
+	frontendResources *ProcessResources
+
+	// Scheduled tasks (see scheduler.go)
+	sched *scheduler
+
+	// Remote backend mode (see remote.go)
+	remoteActive bool
+	remoteCancel context.CancelFunc
+
+	// Additional backend instances (see instances.go)
+	instances    map[string]*backendInstance
+	instanceSeq  int
+	instanceLogs map[string][]string // keyed by log source, guarded by logMu
 }
 
 const (
 	DefaultBackendPort  = "7754"
 	DefaultFrontendPort = "7750"
 	MaxLogEntries       = 200 // Maximum log entries to keep in memory per source
+
+	// Auto-restart backoff: 1s, 2s, 4s ... capped at RestartMaxDelay
+	RestartBaseDelay  = 1 * time.Second
+	RestartMaxDelay   = 60 * time.Second
+	RestartStableTime = 2 * time.Minute // uptime after which the restart counter resets
 )
 
 func NewApp() *App {
 	return &App{
-		config: Config{
-			FrontendPort:  DefaultFrontendPort,
-			AutoLoadBooks: false, // Default: don't auto-load books to prevent high CPU usage
-		},
+		config:       defaultConfig(),
 		backendLogs:  make([]string, 0, MaxLogEntries),
 		frontendLogs: make([]string, 0, MaxLogEntries),
+		instances:    make(map[string]*backendInstance),
+		instanceLogs: make(map[string][]string),
 	}
 }
 
@@ -105,15 +149,27 @@ func (a *App) startup(ctx context.Context) {
 		a.setupProduction()
 	} else {
 		a.projectRoot = a.findProjectRoot()
-		a.loadConfig()
 	}
+	a.loadConfig()
+	a.initLogFiles()
+	a.startHealthPolling()
+	a.startResourcePolling()
+	a.startScheduler()
+
+	if a.config.TrayEnabled {
+		a.startTray()
+	}
+	a.autoStart()
 }
 
 func (a *App) shutdown(ctx context.Context) {
 	a.StopAll()
-	// Cleanup extracted files in production
+	a.stopTray()
+	a.closeLogFiles()
+	// Cleanup extracted files in production (config.json in the data dir is kept)
 	if isProduction && a.dataDir != "" {
-		os.RemoveAll(a.dataDir)
+		os.Remove(a.backendPath)
+		os.RemoveAll(a.frontendDir)
 	}
 }
 
@@ -136,6 +192,7 @@ func (a *App) setupProduction() {
 	if err := a.extractBackend(); err != nil {
 		return
 	}
+	a.loadBackendUpdate()
 
 	// Extract frontend files
 	a.frontendDir = filepath.Join(a.dataDir, "frontend")
@@ -258,56 +315,6 @@ func (a *App) isValidProjectRoot(dir string) bool {
 	return errFE == nil && errBE == nil
 }
 
-func (a *App) loadConfig() {
-	// TODO: Enable config persistence when needed
-	// // Try to load saved config first
-	// configPath := a.getConfigPath()
-	// if data, err := os.ReadFile(configPath); err == nil {
-	// 	var savedConfig Config
-	// 	if err := json.Unmarshal(data, &savedConfig); err == nil {
-	// 		a.config = savedConfig
-	// 		// Ensure defaults for new fields
-	// 		if a.config.FrontendPort == "" {
-	// 			a.config.FrontendPort = DefaultFrontendPort
-	// 		}
-	// 		return
-	// 	}
-	// }
-
-	// Fallback: Try to find default library in testdata
-	defaultLibrary := filepath.Join(a.projectRoot, "backend", "testdata", "library")
-	if _, err := os.Stat(defaultLibrary); err == nil {
-		a.config.LibraryPath = defaultLibrary
-	}
-}
-
-func (a *App) getConfigPath() string {
-	if isProduction {
-		return filepath.Join(a.dataDir, "config.json")
-	}
-	return filepath.Join(a.projectRoot, "launcher", "config.json")
-}
-
-func (a *App) saveConfigToFile() error {
-	// TODO: Enable config persistence when needed
-	return nil
-
-	// configPath := a.getConfigPath()
-	//
-	// // Ensure directory exists
-	// dir := filepath.Dir(configPath)
-	// if err := os.MkdirAll(dir, 0755); err != nil {
-	// 	return err
-	// }
-	//
-	// data, err := json.MarshalIndent(a.config, "", "  ")
-	// if err != nil {
-	// 	return err
-	// }
-	//
-	// return os.WriteFile(configPath, data, 0644)
-}
-
 // GetStatus returns current status of all processes
 func (a *App) GetStatus() Status {
 	a.mu.Lock()
@@ -319,6 +326,7 @@ func (a *App) GetStatus() Status {
 		LibraryPath:  a.config.LibraryPath,
 		FrontendPort: a.config.FrontendPort,
 		BackendPort:  DefaultBackendPort,
+		HTTPSPort:    DefaultHTTPSPort,
 		IsProduction: isProduction,
 	}
 
@@ -328,20 +336,47 @@ func (a *App) GetStatus() Status {
 		status.MToolsExists = a.isValidProjectRoot(a.projectRoot)
 	}
 
+	if a.backendPort != "" {
+		status.BackendPort = a.backendPort
+		status.HTTPSPort = a.httpsPort
+	}
+	if a.frontendPort != "" {
+		status.FrontendPort = a.frontendPort
+	}
+
 	if a.backendCmd != nil && a.backendCmd.Process != nil {
 		status.Backend = StatusRunning
 		status.BackendPID = a.backendCmd.Process.Pid
-	}
-
+	} else if a.remoteActive {
+		status.Backend = StatusRunning
+	} else if a.backendRestarting {
+		status.Backend = StatusStarting
+	} else if a.backendFailed {
+		status.Backend = StatusError
+	}
+	status.BackendRestarts = a.backendRestarts
+	status.BackendResources = a.backendResources
+	status.FrontendResources = a.frontendResources
+	status.RemoteMode = a.config.RemoteMode
+	if a.config.RemoteMode {
+		status.RemoteURL = a.config.RemoteURL
+	}
+	status.BackendHealth = a.health.State
+	if status.BackendHealth == "" {
+		status.BackendHealth = HealthStopped
+	}
+
+	if a.frontendServer != nil {
+		status.Frontend = StatusRunning
+	} else if a.frontendCmd != nil && a.frontendCmd.Process != nil {
+		status.Frontend = StatusRunning
+		status.FrontendPID = a.frontendCmd.Process.Pid
+	}
+	status.FrontendMode = a.config.FrontendMode
 	if isProduction {
-		if a.frontendServer != nil {
-			status.Frontend = StatusRunning
-		}
-	} else {
-		if a.frontendCmd != nil && a.frontendCmd.Process != nil {
-			status.Frontend = StatusRunning
-			status.FrontendPID = a.frontendCmd.Process.Pid
-		}
+		status.FrontendMode = FrontendModeStatic
+	} else if status.FrontendMode == "" {
+		status.FrontendMode = FrontendModeDev
 	}
 
 	return status
@@ -359,20 +394,14 @@ func (a *App) GetLanguage() string {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.config.Language == "" {
-		return "en"
+		return DefaultLanguage
 	}
 	return a.config.Language
 }
 
 // SetLanguage sets the UI language and saves config
 func (a *App) SetLanguage(lang string) error {
-	// Supported languages
-	supported := map[string]bool{
-		"en": true, "ru": true, "es": true, "zh": true, "fr": true,
-		"it": true, "de": true, "ko": true, "pt": true, "el": true,
-		"tr": true, "vi": true, "th": true, "fi": true,
-	}
-	if !supported[lang] {
+	if !a.isSupportedLanguage(lang) {
 		return fmt.Errorf("unsupported language: %s", lang)
 	}
 	a.mu.Lock()
@@ -383,6 +412,36 @@ func (a *App) SetLanguage(lang string) error {
 
 // SetConfig updates configuration and saves to file
 func (a *App) SetConfig(config Config) error {
+	config.Version = ConfigVersion
+	if config.FrontendPort == "" {
+		config.FrontendPort = DefaultFrontendPort
+	}
+	if config.MaxRestarts <= 0 {
+		config.MaxRestarts = DefaultMaxRestarts
+	}
+	if config.MemoryWarningMB < 0 {
+		config.MemoryWarningMB = 0
+	}
+	if !validFrontendMode(config.FrontendMode) {
+		return fmt.Errorf("unknown frontend mode %q", config.FrontendMode)
+	}
+	if config.StopGraceSeconds < 0 {
+		config.StopGraceSeconds = 0
+	}
+	for _, task := range config.Schedules {
+		if err := validateTask(task); err != nil {
+			return fmt.Errorf("schedule %q: %w", task.Name, err)
+		}
+	}
+	config.BackendEnv, config.BackendFlags = normalizeBackendOptions(config.BackendEnv, config.BackendFlags)
+	if err := validateBackendOptions(config.BackendEnv, config.BackendFlags); err != nil {
+		return err
+	}
+	if config.RemoteMode {
+		if err := validateRemoteURL(config.RemoteURL); err != nil {
+			return err
+		}
+	}
 	a.mu.Lock()
 	a.config = config
 	a.mu.Unlock()
@@ -415,6 +474,9 @@ func (a *App) SelectLibraryFolder() (string, error) {
 		a.mu.Lock()
 		a.config.LibraryPath = path
 		a.mu.Unlock()
+		if err := a.saveConfigToFile(); err != nil {
+			return path, err
+		}
 	}
 	return path, nil
 }
@@ -427,30 +489,65 @@ func (a *App) StartBackend() error {
 		return fmt.Errorf("backend is already running")
 	}
 
+	if a.config.RemoteMode {
+		return a.startRemoteBackendLocked()
+	}
+
 	if a.config.LibraryPath == "" {
 		a.mu.Unlock()
 		return fmt.Errorf("library path is not set")
 	}
 
-	var cmd *exec.Cmd
+	// Catch bad libraries here instead of as a backend log line after startup
+	report := validateLibrary(a.config.LibraryPath)
+	for _, w := range report.Warnings {
+		a.emitLog("backend", "Library warning: "+w)
+	}
+	if !report.Valid {
+		a.mu.Unlock()
+		for _, e := range report.Errors {
+			a.emitLog("backend", "Library error: "+e)
+		}
+		return fmt.Errorf("library is invalid: %s", report.Errors[0])
+	}
+	a.emitLog("backend", fmt.Sprintf("Library OK: %d modes, ~%s for lookup tables, ~%s with books",
+		len(report.Modes), formatBytes(report.EstimatedMemory), formatBytes(report.EstimatedBooksMemory)))
+
+	// Pick free ports instead of failing when the defaults are taken;
+	// -port/-https-port from the flag editor replace the defaults
+	preferredPort := DefaultBackendPort
+	if p := backendFlagValue(a.config.BackendFlags, "port"); p != "" {
+		preferredPort = p
+	}
+	preferredHTTPS := DefaultHTTPSPort
+	if p := backendFlagValue(a.config.BackendFlags, "https-port"); p != "" {
+		preferredHTTPS = p
+	}
+	backendPort, err := a.resolvePort("backend", "backend", preferredPort, a.frontendPort)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	httpsPort := "0" // -https-port=0 disables HTTPS
+	if preferredHTTPS != "0" {
+		httpsPort, err = a.resolvePort("backend", "HTTPS", preferredHTTPS, backendPort, a.frontendPort)
+		if err != nil {
+			a.mu.Unlock()
+			return err
+		}
+	}
 
 	// Build args list
-	args := []string{"-library", a.config.LibraryPath}
+	args := []string{"-library", a.config.LibraryPath, "-port", backendPort, "-https-port", httpsPort}
 	if a.config.AutoLoadBooks {
 		args = append(args, "-autoload-books")
 	}
+	args = append(args, backendFlagArgs(a.config.BackendFlags)...)
 
+	cmd := a.backendCommand(args)
 	if isProduction {
-		// Production: use extracted binary
-		cmd = exec.Command(a.backendPath, args...)
-		cmd.Dir = a.dataDir // Ensure certs are created in app data dir
 		a.emitLog("backend", "Starting backend (production mode)...")
 	} else {
-		// Development: use go run
-		backendDir := filepath.Join(a.projectRoot, "backend")
-		goArgs := append([]string{"run", "./cmd"}, args...)
-		cmd = exec.Command("go", goArgs...)
-		cmd.Dir = backendDir
 		a.emitLog("backend", "Starting backend (development mode)...")
 	}
 
@@ -458,8 +555,6 @@ func (a *App) StartBackend() error {
 		a.emitLog("backend", "Auto-load books disabled (use frontend to start loading)")
 	}
 
-	setupProcessGroup(cmd)
-
 	stdout, _ := cmd.StdoutPipe()
 	stderr, _ := cmd.StderrPipe()
 
@@ -469,6 +564,11 @@ func (a *App) StartBackend() error {
 	}
 
 	a.backendCmd = cmd
+	a.backendPort = backendPort
+	a.httpsPort = httpsPort
+	a.backendStartedAt = time.Now()
+	a.backendFailed = false
+	a.indexError = ""
 	a.mu.Unlock()
 
 	a.emitLog("backend", fmt.Sprintf("Backend started (PID: %d)", cmd.Process.Pid))
@@ -480,111 +580,68 @@ func (a *App) StartBackend() error {
 	return nil
 }
 
+// backendCommand builds the backend process for args: the extracted binary in
+// production, `go run` in development. Callers must hold a.mu.
+func (a *App) backendCommand(args []string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if isProduction {
+		// Production: use extracted binary
+		cmd = exec.Command(a.activeBackendBinary(), args...)
+		cmd.Dir = a.dataDir // Ensure certs are created in app data dir
+	} else {
+		// Development: use go run
+		goArgs := append([]string{"run", "./cmd"}, args...)
+		cmd = exec.Command("go", goArgs...)
+		cmd.Dir = filepath.Join(a.projectRoot, "backend")
+	}
+	cmd.Env = backendEnviron(a.config.BackendEnv)
+	setupProcessGroup(cmd)
+	return cmd
+}
+
 // StartFrontend starts the Svelte frontend
 func (a *App) StartFrontend() error {
 	a.mu.Lock()
 
+	if a.frontendServer != nil || (a.frontendCmd != nil && a.frontendCmd.Process != nil) {
+		a.mu.Unlock()
+		return fmt.Errorf("frontend is already running")
+	}
+
 	if isProduction {
 		// Production: start static file server
-		if a.frontendServer != nil {
-			a.mu.Unlock()
-			return fmt.Errorf("frontend is already running")
-		}
-
 		a.emitLog("frontend", "Starting frontend (production mode)...")
+		return a.startStaticFrontendLocked(a.frontendDir)
+	}
 
-		// Create server with SPA fallback
-		mux := http.NewServeMux()
-		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			// Clean URL path
-			urlPath := r.URL.Path
-			if urlPath == "/" {
-				urlPath = "/index.html"
-			}
-
-			// Try to serve the file
-			filePath := filepath.Join(a.frontendDir, filepath.Clean(urlPath))
-
-			// Check if file exists
-			info, err := os.Stat(filePath)
-			if err == nil && !info.IsDir() {
-				// File exists, serve it with proper content type
-				http.ServeFile(w, r, filePath)
-				return
-			}
-
-			// Check if it's a directory with index.html
-			if err == nil && info.IsDir() {
-				indexPath := filepath.Join(filePath, "index.html")
-				if _, err := os.Stat(indexPath); err == nil {
-					http.ServeFile(w, r, indexPath)
-					return
-				}
-			}
-
-			// SPA fallback: only for routes (no extension = likely a route)
-			ext := filepath.Ext(urlPath)
-			if ext == "" || ext == ".html" {
-				http.ServeFile(w, r, filepath.Join(a.frontendDir, "index.html"))
-				return
-			}
-
-			// Asset not found - return 404
-			http.NotFound(w, r)
-		})
-
-		listener, err := net.Listen("tcp", ":"+a.config.FrontendPort)
-		if err != nil {
+	frontendDir := filepath.Join(a.projectRoot, "frontend")
+	switch a.config.FrontendMode {
+	case FrontendModeStatic:
+		dir := a.staticFrontendDir()
+		if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
 			a.mu.Unlock()
-			return fmt.Errorf("failed to listen on port %s: %w", a.config.FrontendPort, err)
+			return fmt.Errorf("no index.html in %s, build the frontend first", dir)
 		}
-
-		a.frontendListener = listener
-		a.frontendServer = &http.Server{Handler: mux}
-		a.mu.Unlock()
-
-		a.emitLog("frontend", fmt.Sprintf("Frontend serving at http://localhost:%s", a.config.FrontendPort))
-
-		// Start server in goroutine
-		go func() {
-			if err := a.frontendServer.Serve(listener); err != nil && err != http.ErrServerClosed {
-				a.emitLog("frontend", fmt.Sprintf("Frontend server error: %v", err))
-			}
-			a.emitLog("frontend", "Frontend stopped")
-			wailsRuntime.EventsEmit(a.ctx, "statusChange", a.GetStatus())
-		}()
-
-		return nil
+		a.emitLog("frontend", fmt.Sprintf("Serving static frontend from %s", dir))
+		return a.startStaticFrontendLocked(dir)
+	case FrontendModePreview:
+		return a.startFrontendPreviewLocked(frontendDir)
 	}
 
-	// Development mode
-	if a.frontendCmd != nil && a.frontendCmd.Process != nil {
-		a.mu.Unlock()
-		return fmt.Errorf("frontend is already running")
+	// Development mode: pnpm dev with hot reload
+	a.mu.Unlock()
+	if err := a.ensureFrontendDeps(frontendDir); err != nil {
+		return err
 	}
+	a.mu.Lock()
 
-	frontendDir := filepath.Join(a.projectRoot, "frontend")
-
-	// Check for node_modules
-	nodeModules := filepath.Join(frontendDir, "node_modules")
-	if _, err := os.Stat(nodeModules); os.IsNotExist(err) {
+	port, err := a.resolvePort("frontend", "frontend", a.config.FrontendPort, a.backendPort, a.httpsPort)
+	if err != nil {
 		a.mu.Unlock()
-		a.emitLog("frontend", "Installing dependencies...")
-
-		installCmd := exec.Command("pnpm", "install")
-		installCmd.Dir = frontendDir
-
-		output, err := installCmd.CombinedOutput()
-		a.emitLog("frontend", string(output))
-
-		if err != nil {
-			return fmt.Errorf("failed to install dependencies: %w", err)
-		}
-		a.emitLog("frontend", "Dependencies installed")
-		a.mu.Lock()
+		return err
 	}
 
-	cmd := exec.Command("pnpm", "run", "dev", "--port", a.config.FrontendPort)
+	cmd := exec.Command("pnpm", "run", "dev", "--port", port, "--strictPort")
 	cmd.Dir = frontendDir
 	setupProcessGroup(cmd)
 
@@ -597,6 +654,7 @@ func (a *App) StartFrontend() error {
 	}
 
 	a.frontendCmd = cmd
+	a.frontendPort = port
 	a.mu.Unlock()
 
 	a.emitLog("frontend", fmt.Sprintf("Frontend started (PID: %d)", cmd.Process.Pid))
@@ -608,6 +666,81 @@ func (a *App) StartFrontend() error {
 	return nil
 }
 
+// startStaticFrontendLocked serves dir with an SPA fallback to index.html.
+// Callers must hold a.mu; it is released before returning.
+func (a *App) startStaticFrontendLocked(dir string) error {
+	// Create server with SPA fallback
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// Clean URL path
+		urlPath := r.URL.Path
+		if urlPath == "/" {
+			urlPath = "/index.html"
+		}
+
+		// Try to serve the file
+		filePath := filepath.Join(dir, filepath.Clean(urlPath))
+
+		// Check if file exists
+		info, err := os.Stat(filePath)
+		if err == nil && !info.IsDir() {
+			// File exists, serve it with proper content type
+			http.ServeFile(w, r, filePath)
+			return
+		}
+
+		// Check if it's a directory with index.html
+		if err == nil && info.IsDir() {
+			indexPath := filepath.Join(filePath, "index.html")
+			if _, err := os.Stat(indexPath); err == nil {
+				http.ServeFile(w, r, indexPath)
+				return
+			}
+		}
+
+		// SPA fallback: only for routes (no extension = likely a route)
+		ext := filepath.Ext(urlPath)
+		if ext == "" || ext == ".html" {
+			http.ServeFile(w, r, filepath.Join(dir, "index.html"))
+			return
+		}
+
+		// Asset not found - return 404
+		http.NotFound(w, r)
+	})
+
+	port, err := a.resolvePort("frontend", "frontend", a.config.FrontendPort, a.backendPort, a.httpsPort)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		a.mu.Unlock()
+		return fmt.Errorf("failed to listen on port %s: %w", port, err)
+	}
+
+	server := &http.Server{Handler: mux}
+	a.frontendListener = listener
+	a.frontendServer = server
+	a.frontendPort = port
+	a.mu.Unlock()
+
+	a.emitLog("frontend", fmt.Sprintf("Frontend serving at http://localhost:%s", port))
+
+	// Start server in goroutine
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			a.emitLog("frontend", fmt.Sprintf("Frontend server error: %v", err))
+		}
+		a.emitLog("frontend", "Frontend stopped")
+		a.emitStatus()
+	}()
+
+	return nil
+}
+
 // StartAll starts both backend and frontend
 func (a *App) StartAll() error {
 	if err := a.StartBackend(); err != nil {
@@ -620,29 +753,37 @@ func (a *App) StartAll() error {
 
 // StopBackend stops the backend process
 func (a *App) StopBackend() error {
+	grace := a.stopGrace()
+
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	// A user-initiated stop also cancels any pending automatic restart
+	a.cancelRestartLocked()
+	a.backendRestarts = 0
+	a.backendFailed = false
+	a.stopRemoteBackendLocked()
+
+	cmd := a.backendCmd
+	// Clearing backendCmd first marks the exit as expected for waitForProcess
+	a.backendCmd = nil
+	a.mu.Unlock()
 
-	if a.backendCmd == nil || a.backendCmd.Process == nil {
+	if cmd == nil || cmd.Process == nil {
 		return nil
 	}
 
 	a.emitLog("backend", "Stopping backend...")
-	killProcessGroup(a.backendCmd.Process.Pid)
-	a.backendCmd = nil
+	a.stopProcessGroup("backend", cmd.Process.Pid, grace)
 	a.emitLog("backend", "Backend stopped")
 	return nil
 }
 
 // StopFrontend stops the frontend process/server
 func (a *App) StopFrontend() error {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	grace := a.stopGrace()
 
-	if isProduction {
-		if a.frontendServer == nil {
-			return nil
-		}
+	a.mu.Lock()
+	if a.frontendServer != nil {
+		defer a.mu.Unlock()
 		a.emitLog("frontend", "Stopping frontend...")
 		a.frontendServer.Close()
 		if a.frontendListener != nil {
@@ -654,13 +795,16 @@ func (a *App) StopFrontend() error {
 		return nil
 	}
 
-	if a.frontendCmd == nil || a.frontendCmd.Process == nil {
+	cmd := a.frontendCmd
+	a.frontendCmd = nil
+	a.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
 		return nil
 	}
 
 	a.emitLog("frontend", "Stopping frontend...")
-	killProcessGroup(a.frontendCmd.Process.Pid)
-	a.frontendCmd = nil
+	a.stopProcessGroup("frontend", cmd.Process.Pid, grace)
 	a.emitLog("frontend", "Frontend stopped")
 	return nil
 }
@@ -669,6 +813,7 @@ func (a *App) StopFrontend() error {
 func (a *App) StopAll() error {
 	a.StopBackend()
 	a.StopFrontend()
+	a.stopAllInstances()
 	return nil
 }
 
@@ -691,6 +836,10 @@ func (a *App) GetLogs(source string, limit int) []string {
 	case "frontend":
 		logs = a.frontendLogs
 	default:
+		if instLogs, ok := a.instanceLogs[source]; ok {
+			logs = instLogs
+			break
+		}
 		// Merge both logs (simplified - just concat)
 		logs = append(a.backendLogs, a.frontendLogs...)
 	}
@@ -719,21 +868,23 @@ func (a *App) ClearLogs(source string) {
 
 // OpenMTools opens the mtools frontend in default browser
 func (a *App) OpenMTools() error {
-	lang := a.GetLanguage()
-	url := fmt.Sprintf("http://localhost:%s/?lang=%s", a.config.FrontendPort, lang)
-	return openURL(url)
+	return openURL(a.GetFrontendURL())
 }
 
 // OpenMToolsAPI opens the backend API in default browser
 func (a *App) OpenMToolsAPI() error {
-	url := fmt.Sprintf("http://localhost:%s", DefaultBackendPort)
-	return openURL(url)
+	return openURL(a.backendURL())
 }
 
-// GetFrontendURL returns the frontend URL for embedding (with language parameter)
+// GetFrontendURL returns the frontend URL for embedding (with language parameter).
+// When the backend is not on its default port, the api parameter tells the frontend where to find it.
 func (a *App) GetFrontendURL() string {
 	lang := a.GetLanguage()
-	return fmt.Sprintf("http://localhost:%s/?lang=%s", a.config.FrontendPort, lang)
+	url := fmt.Sprintf("http://localhost:%s/?lang=%s", a.activeFrontendPort(), lang)
+	if api := a.backendURL(); api != "http://localhost:"+DefaultBackendPort {
+		url += "&api=" + neturl.QueryEscape(api)
+	}
+	return url
 }
 
 // CheckDependencies checks for required dependencies
@@ -774,13 +925,19 @@ func (a *App) streamOutput(pipe io.ReadCloser, source string) {
 	scanner := bufio.NewScanner(pipe)
 	for scanner.Scan() {
 		line := scanner.Text()
+		if source == "backend" {
+			a.noteBackendOutput(line)
+		}
 		a.emitLog(source, line)
 	}
 }
 
 func (a *App) emitLog(source, message string) {
+	now := time.Now()
+	a.persistLog(source, message, now)
+
 	a.logMu.Lock()
-	timestamp := time.Now().Format("15:04:05")
+	timestamp := now.Format("15:04:05")
 	logLine := fmt.Sprintf("[%s] %s", timestamp, message)
 
 	switch source {
@@ -794,97 +951,146 @@ func (a *App) emitLog(source, message string) {
 		if len(a.frontendLogs) > MaxLogEntries {
 			a.frontendLogs = a.frontendLogs[len(a.frontendLogs)-MaxLogEntries:]
 		}
+	default:
+		if logs, ok := a.instanceLogs[source]; ok {
+			logs = append(logs, logLine)
+			if len(logs) > MaxLogEntries {
+				logs = logs[len(logs)-MaxLogEntries:]
+			}
+			a.instanceLogs[source] = logs
+		}
 	}
+	follow := a.logFollow
 	a.logMu.Unlock()
 
+	level := detectLevel(message)
+
 	// Emit event to frontend
 	wailsRuntime.EventsEmit(a.ctx, "log", map[string]string{
 		"source":  source,
+		"level":   level,
 		"message": logLine,
 	})
+
+	if follow != nil {
+		line := LogLine{
+			Source:  source,
+			Level:   level,
+			Time:    now.Format("2006-01-02 15:04:05.000"),
+			Message: message,
+		}
+		if follow.matches(line) {
+			wailsRuntime.EventsEmit(a.ctx, "logFollow", line)
+		}
+	}
 }
 
 func (a *App) waitForProcess(cmd *exec.Cmd, name string) {
-	cmd.Wait()
+	waitErr := cmd.Wait()
+
+	// If the command is no longer the current one, it was stopped by the user
+	// (Stop* clears it before killing the process)
+	unexpected := false
+	var uptime time.Duration
 	a.mu.Lock()
 	switch name {
 	case "backend":
-		a.backendCmd = nil
+		if a.backendCmd == cmd {
+			a.backendCmd = nil
+			unexpected = true
+			uptime = time.Since(a.backendStartedAt)
+		}
 	case "frontend":
-		a.frontendCmd = nil
+		if a.frontendCmd == cmd {
+			a.frontendCmd = nil
+			unexpected = true
+		}
 	}
 	a.mu.Unlock()
-	a.emitLog(name, fmt.Sprintf("%s process exited", name))
+
+	if waitErr != nil {
+		a.emitLog(name, fmt.Sprintf("%s process exited: %v", name, waitErr))
+	} else {
+		a.emitLog(name, fmt.Sprintf("%s process exited", name))
+	}
+
+	if name == "backend" && unexpected && waitErr != nil {
+		a.scheduleBackendRestart(uptime)
+	}
 
 	// Emit status change
-	wailsRuntime.EventsEmit(a.ctx, "statusChange", a.GetStatus())
+	a.emitStatus()
 }
 
-// CheckPortInUse checks if a port is in use and returns the PID if possible
-func (a *App) CheckPortInUse(port string) PortStatus {
-	status := PortStatus{Port: port, InUse: false}
+// scheduleBackendRestart restarts a crashed backend after an exponential backoff,
+// giving up after Config.MaxRestarts consecutive crashes
+func (a *App) scheduleBackendRestart(uptime time.Duration) {
+	a.mu.Lock()
+	if !a.config.AutoRestart {
+		a.backendFailed = true
+		a.mu.Unlock()
+		return
+	}
 
-	// Try to listen on the port to check if it's in use
-	listener, err := net.Listen("tcp", ":"+port)
-	if err != nil {
-		status.InUse = true
-		// Try to get PID using lsof (macOS/Linux)
-		if runtime.GOOS != "windows" {
-			cmd := exec.Command("lsof", "-ti", ":"+port)
-			output, err := cmd.Output()
-			if err == nil && len(output) > 0 {
-				var pid int
-				fmt.Sscanf(string(output), "%d", &pid)
-				status.PID = pid
-			}
-		}
-	} else {
-		listener.Close()
+	// A backend that ran for a while before crashing starts a fresh retry budget
+	if uptime >= RestartStableTime {
+		a.backendRestarts = 0
 	}
 
-	return status
-}
+	if a.backendRestarts >= a.config.MaxRestarts {
+		a.backendFailed = true
+		attempts := a.backendRestarts
+		a.mu.Unlock()
+		a.emitLog("backend", fmt.Sprintf("Backend crashed %d times in a row, giving up on automatic restart", attempts))
+		return
+	}
 
-// KillProcessOnPort kills any process using the specified port
-func (a *App) KillProcessOnPort(port string) PortStatus {
-	status := a.CheckPortInUse(port)
-	if !status.InUse {
-		return status
+	delay := RestartBaseDelay << a.backendRestarts
+	if delay > RestartMaxDelay || delay <= 0 {
+		delay = RestartMaxDelay
 	}
+	a.backendRestarts++
+	attempt, maxAttempts := a.backendRestarts, a.config.MaxRestarts
+	a.backendRestarting = true
+	cancel := make(chan struct{})
+	a.restartCancel = cancel
+	a.mu.Unlock()
 
-	a.emitLog("backend", fmt.Sprintf("Killing process on port %s...", port))
+	a.emitLog("backend", fmt.Sprintf("Backend crashed, restarting in %v (attempt %d/%d)...", delay, attempt, maxAttempts))
 
-	if runtime.GOOS == "windows" {
-		// Windows: use netstat to find PID and taskkill
-		cmd := exec.Command("cmd", "/c", fmt.Sprintf("for /f \"tokens=5\" %%a in ('netstat -aon ^| findstr :%s') do taskkill /F /PID %%a", port))
-		cmd.Run()
-	} else {
-		// macOS/Linux: use lsof and kill
-		cmd := exec.Command("sh", "-c", fmt.Sprintf("lsof -ti :%s | xargs kill -9 2>/dev/null", port))
-		cmd.Run()
-	}
+	go func() {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(delay):
+		}
 
-	// Wait a bit and check again
-	time.Sleep(500 * time.Millisecond)
-	newStatus := a.CheckPortInUse(port)
-	newStatus.Killed = !newStatus.InUse
-	return newStatus
-}
+		a.mu.Lock()
+		if a.restartCancel != cancel {
+			a.mu.Unlock()
+			return
+		}
+		a.restartCancel = nil
+		a.backendRestarting = false
+		a.mu.Unlock()
 
-// GetPortsStatus returns the status of both backend and frontend ports
-func (a *App) GetPortsStatus() []PortStatus {
-	return []PortStatus{
-		a.CheckPortInUse(DefaultBackendPort),
-		a.CheckPortInUse(a.config.FrontendPort),
-	}
+		if err := a.StartBackend(); err != nil {
+			a.emitLog("backend", fmt.Sprintf("Automatic restart failed: %v", err))
+			a.mu.Lock()
+			a.backendFailed = true
+			a.mu.Unlock()
+		}
+		a.emitStatus()
+	}()
 }
 
-// KillPortProcesses kills processes on both backend and frontend ports
-func (a *App) KillPortProcesses() []PortStatus {
-	return []PortStatus{
-		a.KillProcessOnPort(DefaultBackendPort),
-		a.KillProcessOnPort(a.config.FrontendPort),
+// cancelRestartLocked aborts a pending automatic restart. Must be called with a.mu held.
+func (a *App) cancelRestartLocked() {
+	if a.restartCancel != nil {
+		close(a.restartCancel)
+		a.restartCancel = nil
 	}
+	a.backendRestarting = false
 }
 
 // GetWatcherStatus gets the watcher status from the backend API
@@ -892,11 +1098,11 @@ func (a *App) GetWatcherStatus() (WatcherStatus, error) {
 	status := WatcherStatus{Available: false, Enabled: false}
 
 	// Check if backend is running
-	if a.backendCmd == nil || a.backendCmd.Process == nil {
+	if !a.backendAvailable() {
 		return status, nil
 	}
 
-	resp, err := http.Get(fmt.Sprintf("http://localhost:%s/api/watcher/status", DefaultBackendPort))
+	resp, err := http.Get(a.backendURL() + "/api/watcher/status")
 	if err != nil {
 		return status, err
 	}
@@ -915,11 +1121,11 @@ func (a *App) GetWatcherStatus() (WatcherStatus, error) {
 // SetWatcherEnabled enables or disables the watcher via backend API
 func (a *App) SetWatcherEnabled(enabled bool) error {
 	// Check if backend is running
-	if a.backendCmd == nil || a.backendCmd.Process == nil {
+	if !a.backendAvailable() {
 		return fmt.Errorf("backend is not running")
 	}
 
-	url := fmt.Sprintf("http://localhost:%s/api/watcher/enable", DefaultBackendPort)
+	url := a.backendURL() + "/api/watcher/enable"
 	var req *http.Request
 	var err error
 
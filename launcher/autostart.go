@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// AutostartArg is passed to the launcher when the OS starts it at login
+const AutostartArg = "--autostart"
+
+// LoginItemName identifies the launcher's login item on every OS
+const LoginItemName = "mtools-launcher"
+
+// launchedAtLogin reports whether the process was started by the login item
+func launchedAtLogin() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == AutostartArg {
+			return true
+		}
+	}
+	return false
+}
+
+// launcherExecutable returns the resolved path of the running launcher
+func launcherExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
+
+// GetLaunchAtLogin reports whether the launcher is registered to start at login
+func (a *App) GetLaunchAtLogin() bool {
+	return loginItemEnabled()
+}
+
+// SetLaunchAtLogin registers or removes the OS login item
+func (a *App) SetLaunchAtLogin(enabled bool) error {
+	if !enabled {
+		if err := disableLoginItem(); err != nil {
+			return fmt.Errorf("failed to remove login item: %w", err)
+		}
+		return nil
+	}
+	if !isProduction {
+		return fmt.Errorf("launch at login is only available in production builds")
+	}
+	exe, err := launcherExecutable()
+	if err != nil {
+		return err
+	}
+	if err := enableLoginItem(exe); err != nil {
+		return fmt.Errorf("failed to register login item: %w", err)
+	}
+	return nil
+}
+
+// SetAutoStartServices toggles starting backend+frontend when the launcher opens
+func (a *App) SetAutoStartServices(enabled bool) error {
+	a.mu.Lock()
+	a.config.AutoStartServices = enabled
+	a.mu.Unlock()
+	return a.saveConfigToFile()
+}
+
+// autoStart runs at startup: shows the window if a login launch has no tray
+// to restore it from, and starts services when configured
+func (a *App) autoStart() {
+	a.mu.Lock()
+	cfg := a.config
+	a.mu.Unlock()
+
+	if a.startedAtLogin && !cfg.TrayEnabled {
+		wailsRuntime.WindowShow(a.ctx)
+	}
+
+	if !cfg.AutoStartServices {
+		return
+	}
+	if cfg.LibraryPath == "" && !cfg.RemoteMode {
+		a.emitLog("backend", "Auto-start skipped: library path is not set")
+		return
+	}
+	go func() {
+		// Let the UI subscribe to log and status events first
+		time.Sleep(time.Second)
+		if err := a.StartAll(); err != nil {
+			a.emitLog("backend", fmt.Sprintf("Auto-start failed: %v", err))
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+//go:build !windows && !darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// autostartDesktopPath returns the XDG autostart entry path
+func autostartDesktopPath() string {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, _ := os.UserHomeDir()
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "autostart", LoginItemName+".desktop")
+}
+
+func enableLoginItem(exe string) error {
+	// Quote per the Desktop Entry spec so paths with spaces work
+	quoted := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(exe) + `"`
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Mnemoo Tools
+Exec=%s %s
+Terminal=false
+X-GNOME-Autostart-enabled=true
+`, quoted, AutostartArg)
+
+	path := autostartDesktopPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(entry), 0644)
+}
+
+func disableLoginItem() error {
+	if err := os.Remove(autostartDesktopPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func loginItemEnabled() bool {
+	_, err := os.Stat(autostartDesktopPath())
+	return err == nil
+}
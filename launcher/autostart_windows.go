@@ -0,0 +1,38 @@
+package main
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+const runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+func enableLoginItem(exe string) error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	return key.SetStringValue(LoginItemName, `"`+exe+`" `+AutostartArg)
+}
+
+func disableLoginItem() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	if err := key.DeleteValue(LoginItemName); err != nil && err != registry.ErrNotExist {
+		return err
+	}
+	return nil
+}
+
+func loginItemEnabled() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+	_, _, err = key.GetStringValue(LoginItemName)
+	return err == nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// BackendOption is one entry of the backend env/flag editor
+type BackendOption struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
+
+var (
+	envKeyPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	flagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// reservedFlags are always set by the launcher and cannot be overridden
+var reservedFlags = map[string]bool{
+	"library": true,
+}
+
+// portFlags are not passed through directly; their values become the
+// preferred ports handed to resolvePort
+var portFlags = map[string]bool{
+	"port":       true,
+	"https-port": true,
+}
+
+// normalizeBackendOptions trims keys and strips leading dashes from flag names
+func normalizeBackendOptions(env, flags []BackendOption) ([]BackendOption, []BackendOption) {
+	outEnv := make([]BackendOption, 0, len(env))
+	for _, o := range env {
+		o.Key = strings.TrimSpace(o.Key)
+		if o.Key != "" {
+			outEnv = append(outEnv, o)
+		}
+	}
+	outFlags := make([]BackendOption, 0, len(flags))
+	for _, o := range flags {
+		o.Key = strings.TrimLeft(strings.TrimSpace(o.Key), "-")
+		if o.Key != "" {
+			outFlags = append(outFlags, o)
+		}
+	}
+	return outEnv, outFlags
+}
+
+// validateBackendOptions rejects malformed, duplicate and reserved entries
+func validateBackendOptions(env, flags []BackendOption) error {
+	seen := make(map[string]bool)
+	for _, o := range env {
+		if !envKeyPattern.MatchString(o.Key) {
+			return fmt.Errorf("invalid environment variable name %q", o.Key)
+		}
+		if seen[o.Key] {
+			return fmt.Errorf("duplicate environment variable %q", o.Key)
+		}
+		seen[o.Key] = true
+	}
+
+	seen = make(map[string]bool)
+	for _, o := range flags {
+		if !flagKeyPattern.MatchString(o.Key) {
+			return fmt.Errorf("invalid flag name %q", o.Key)
+		}
+		if reservedFlags[o.Key] {
+			return fmt.Errorf("flag -%s is managed by the launcher", o.Key)
+		}
+		if seen[o.Key] {
+			return fmt.Errorf("duplicate flag -%s", o.Key)
+		}
+		seen[o.Key] = true
+	}
+	return nil
+}
+
+// backendFlagValue returns the value of an enabled flag entry, or ""
+func backendFlagValue(flags []BackendOption, name string) string {
+	for _, o := range flags {
+		if o.Enabled && o.Key == name {
+			return strings.TrimSpace(o.Value)
+		}
+	}
+	return ""
+}
+
+// backendFlagArgs converts enabled flag entries to command-line arguments.
+// An empty value is passed as a bare boolean flag.
+func backendFlagArgs(flags []BackendOption) []string {
+	var args []string
+	for _, o := range flags {
+		if !o.Enabled || portFlags[o.Key] {
+			continue
+		}
+		if o.Value == "" {
+			args = append(args, "-"+o.Key)
+		} else {
+			args = append(args, "-"+o.Key+"="+o.Value)
+		}
+	}
+	return args
+}
+
+// backendEnviron returns the launcher's environment plus enabled env entries
+func backendEnviron(env []BackendOption) []string {
+	environ := os.Environ()
+	for _, o := range env {
+		if o.Enabled {
+			environ = append(environ, o.Key+"="+o.Value)
+		}
+	}
+	return environ
+}
+
+// SetBackendOptions validates and saves the backend env vars and extra flags.
+// Changes apply the next time the backend starts.
+func (a *App) SetBackendOptions(env, flags []BackendOption) error {
+	env, flags = normalizeBackendOptions(env, flags)
+	if err := validateBackendOptions(env, flags); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.config.BackendEnv = env
+	a.config.BackendFlags = flags
+	a.mu.Unlock()
+
+	return a.saveConfigToFile()
+}
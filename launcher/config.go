@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigVersion is the current version of the persisted config format.
+// Bump it and add a step to migrateConfig when the format changes.
+const ConfigVersion = 1
+
+// DefaultMaxRestarts is how many consecutive backend crashes are auto-restarted
+const DefaultMaxRestarts = 5
+
+type Config struct {
+	Version        int    `json:"version"`
+	LibraryPath    string `json:"libraryPath"`
+	FrontendPort   string `json:"frontendPort"`
+	AutoLoadBooks  bool   `json:"autoLoadBooks"`
+	Language       string `json:"language"`
+	AutoRestart    bool   `json:"autoRestart"`    // Restart the backend when it crashes
+	MaxRestarts    int    `json:"maxRestarts"`    // Consecutive automatic restarts before giving up
+	TrayEnabled    bool   `json:"trayEnabled"`    // Show a system tray icon (applies on next launch)
+	MinimizeToTray bool   `json:"minimizeToTray"` // Closing the window hides it to the tray
+	// Start backend+frontend when the launcher opens
+	AutoStartServices bool `json:"autoStartServices"`
+	// Warn when backend memory exceeds this many MB (0 disables)
+	MemoryWarningMB int `json:"memoryWarningMB"`
+	// Seconds a stopped process gets to exit cleanly before it is killed (0 kills immediately)
+	StopGraceSeconds int `json:"stopGraceSeconds"`
+
+	// Connect to a backend at RemoteURL (e.g. a team server) instead of spawning one
+	RemoteMode bool   `json:"remoteMode"`
+	RemoteURL  string `json:"remoteUrl"`
+
+	// How the frontend is served in development: dev, preview or static
+	FrontendMode      string `json:"frontendMode"`
+	StaticFrontendDir string `json:"staticFrontendDir"` // static mode folder, default frontend/build
+
+	// Cron-like tasks run by the launcher (see scheduler.go)
+	Schedules []ScheduledTask `json:"schedules"`
+
+	// Extra environment variables and flags passed to the backend process
+	BackendEnv   []BackendOption `json:"backendEnv"`
+	BackendFlags []BackendOption `json:"backendFlags"`
+}
+
+// defaultConfig returns the configuration used on first launch and after a reset
+func defaultConfig() Config {
+	return Config{
+		Version:          ConfigVersion,
+		FrontendPort:     DefaultFrontendPort,
+		AutoLoadBooks:    false, // Default: don't auto-load books to prevent high CPU usage
+		AutoRestart:      true,
+		MaxRestarts:      DefaultMaxRestarts,
+		TrayEnabled:      true,
+		MemoryWarningMB:  DefaultMemoryWarningMB,
+		StopGraceSeconds: DefaultStopGraceSeconds,
+	}
+}
+
+// loadConfig loads the saved config, falling back to defaults if none exists
+func (a *App) loadConfig() {
+	configPath, err := a.getConfigPath()
+	if err == nil {
+		if data, err := os.ReadFile(configPath); err == nil {
+			if cfg, err := parseConfig(data); err == nil {
+				a.config = cfg
+				return
+			}
+		}
+	}
+
+	// Fallback: Try to find default library in testdata
+	if !isProduction {
+		defaultLibrary := filepath.Join(a.projectRoot, "backend", "testdata", "library")
+		if _, err := os.Stat(defaultLibrary); err == nil {
+			a.config.LibraryPath = defaultLibrary
+		}
+	}
+}
+
+// parseConfig decodes a saved config and migrates it to the current version
+func parseConfig(data []byte) (Config, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Config{}, err
+	}
+
+	version := 0
+	if v, ok := raw["version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return Config{}, fmt.Errorf("invalid config version: %w", err)
+		}
+	}
+	if version > ConfigVersion {
+		return Config{}, fmt.Errorf("config version %d is newer than supported version %d", version, ConfigVersion)
+	}
+
+	if err := migrateConfig(raw, version); err != nil {
+		return Config{}, err
+	}
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := defaultConfig()
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
+		return Config{}, err
+	}
+	cfg.Version = ConfigVersion
+
+	// Ensure defaults for fields that were saved empty
+	if cfg.FrontendPort == "" {
+		cfg.FrontendPort = DefaultFrontendPort
+	}
+	if cfg.MaxRestarts <= 0 {
+		cfg.MaxRestarts = DefaultMaxRestarts
+	}
+
+	return cfg, nil
+}
+
+// migrateConfig upgrades a raw config from the given version to ConfigVersion in place
+func migrateConfig(raw map[string]json.RawMessage, from int) error {
+	for v := from; v < ConfigVersion; v++ {
+		switch v {
+		case 0:
+			// v0 (unversioned) configs were written by early builds that had no
+			// "version" field; the field layout is otherwise identical.
+		default:
+			return fmt.Errorf("no migration from config version %d", v)
+		}
+	}
+	raw["version"] = json.RawMessage(fmt.Sprint(ConfigVersion))
+	return nil
+}
+
+// getConfigPath returns the config file location in the app data dir
+func (a *App) getConfigPath() (string, error) {
+	dir := a.dataDir
+	if dir == "" {
+		var err error
+		if dir, err = a.getAppDataDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// saveConfigToFile writes the current config atomically
+func (a *App) saveConfigToFile() error {
+	configPath, err := a.getConfigPath()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	cfg := a.config
+	a.mu.Unlock()
+	cfg.Version = ConfigVersion
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename so a crash never leaves a truncated config
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, configPath)
+}
+
+// ResetConfig restores the default configuration and saves it
+func (a *App) ResetConfig() (Config, error) {
+	a.mu.Lock()
+	a.config = defaultConfig()
+	cfg := a.config
+	a.mu.Unlock()
+
+	if err := a.saveConfigToFile(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// InstallPlan describes how a missing dev dependency would be installed
+type InstallPlan struct {
+	Dependency string   `json:"dependency"`
+	Manager    string   `json:"manager"` // package manager used, empty if none found
+	Command    []string `json:"command"`
+	NeedsAdmin bool     `json:"needsAdmin"`
+	// DownloadURL is the manual fallback when no package manager is available
+	DownloadURL string `json:"downloadUrl"`
+}
+
+var dependencyDownloadURLs = map[string]string{
+	"go":   "https://go.dev/dl/",
+	"node": "https://nodejs.org/en/download",
+	"pnpm": "https://pnpm.io/installation",
+}
+
+// packageNames maps package manager -> dependency -> package name
+var packageNames = map[string]map[string]string{
+	"brew":    {"go": "go", "node": "node", "pnpm": "pnpm"},
+	"winget":  {"go": "GoLang.Go", "node": "OpenJS.NodeJS.LTS", "pnpm": "pnpm.pnpm"},
+	"choco":   {"go": "golang", "node": "nodejs-lts", "pnpm": "pnpm"},
+	"apt-get": {"go": "golang-go", "node": "nodejs"},
+	"dnf":     {"go": "golang", "node": "nodejs"},
+	"pacman":  {"go": "go", "node": "nodejs", "pnpm": "pnpm"},
+}
+
+// packageManagers lists supported managers per OS in order of preference
+var packageManagers = map[string][]string{
+	"darwin":  {"brew"},
+	"windows": {"winget", "choco"},
+	"linux":   {"apt-get", "dnf", "pacman"},
+}
+
+var installMu sync.Mutex
+
+// detectPackageManager returns the first supported package manager on PATH
+func detectPackageManager() string {
+	for _, pm := range packageManagers[runtime.GOOS] {
+		if _, err := exec.LookPath(pm); err == nil {
+			return pm
+		}
+	}
+	return ""
+}
+
+// GetInstallPlan returns how dep would be installed on this machine
+func (a *App) GetInstallPlan(dep string) (InstallPlan, error) {
+	url, ok := dependencyDownloadURLs[dep]
+	if !ok {
+		return InstallPlan{}, fmt.Errorf("unknown dependency %q", dep)
+	}
+	plan := InstallPlan{Dependency: dep, DownloadURL: url}
+
+	// pnpm ships with node via corepack, which avoids distro packages that
+	// often don't exist
+	if dep == "pnpm" {
+		if _, err := exec.LookPath("corepack"); err == nil {
+			plan.Manager = "corepack"
+			plan.Command = []string{"corepack", "enable", "pnpm"}
+			plan.NeedsAdmin = runtime.GOOS == "linux"
+			if plan.NeedsAdmin {
+				plan.Command = withElevation(plan.Command)
+			}
+			return plan, nil
+		}
+		if _, err := exec.LookPath("npm"); err == nil {
+			plan.Manager = "npm"
+			plan.Command = []string{"npm", "install", "-g", "pnpm"}
+			return plan, nil
+		}
+	}
+
+	pm := detectPackageManager()
+	pkg := packageNames[pm][dep]
+	if pm == "" || pkg == "" {
+		return plan, nil
+	}
+	plan.Manager = pm
+
+	switch pm {
+	case "brew":
+		plan.Command = []string{"brew", "install", pkg}
+	case "winget":
+		plan.Command = []string{"winget", "install", "--id", pkg, "-e", "--silent",
+			"--accept-package-agreements", "--accept-source-agreements"}
+	case "choco":
+		plan.Command = []string{"choco", "install", pkg, "-y"}
+		plan.NeedsAdmin = true
+	case "apt-get":
+		plan.Command = withElevation([]string{"apt-get", "install", "-y", pkg})
+		plan.NeedsAdmin = true
+	case "dnf":
+		plan.Command = withElevation([]string{"dnf", "install", "-y", pkg})
+		plan.NeedsAdmin = true
+	case "pacman":
+		plan.Command = withElevation([]string{"pacman", "-S", "--noconfirm", pkg})
+		plan.NeedsAdmin = true
+	}
+	return plan, nil
+}
+
+// withElevation prefixes a Linux command with pkexec, which shows a
+// graphical password prompt (sudo would block on a terminal we don't have)
+func withElevation(cmd []string) []string {
+	if _, err := exec.LookPath("pkexec"); err == nil {
+		return append([]string{"pkexec"}, cmd...)
+	}
+	return cmd
+}
+
+// InstallDependency installs a missing dev dependency, streaming the installer
+// output to the log pane. Emits "dependencyInstall" with the outcome.
+func (a *App) InstallDependency(dep string) error {
+	if isProduction {
+		return fmt.Errorf("production builds have no external dependencies")
+	}
+	if !installMu.TryLock() {
+		return fmt.Errorf("another installation is already running")
+	}
+	defer installMu.Unlock()
+
+	plan, err := a.GetInstallPlan(dep)
+	if err != nil {
+		return err
+	}
+	if len(plan.Command) == 0 {
+		return fmt.Errorf("no supported package manager found, install %s from %s", dep, plan.DownloadURL)
+	}
+
+	// go output belongs with the backend, node/pnpm with the frontend
+	source := "frontend"
+	if dep == "go" {
+		source = "backend"
+	}
+
+	emit := func(status, message string) {
+		wailsRuntime.EventsEmit(a.ctx, "dependencyInstall", map[string]string{
+			"dependency": dep,
+			"status":     status,
+			"message":    message,
+		})
+	}
+
+	a.emitLog(source, fmt.Sprintf("Installing %s: %s", dep, strings.Join(plan.Command, " ")))
+	emit("running", "")
+
+	cmd := exec.Command(plan.Command[0], plan.Command[1:]...)
+	stdout, _ := cmd.StdoutPipe()
+	stderr, _ := cmd.StderrPipe()
+	if err := cmd.Start(); err != nil {
+		emit("failed", err.Error())
+		return fmt.Errorf("failed to start installer: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); a.streamOutput(stdout, source) }()
+	go func() { defer wg.Done(); a.streamOutput(stderr, source) }()
+	wg.Wait()
+
+	if err := cmd.Wait(); err != nil {
+		a.emitLog(source, fmt.Sprintf("Installing %s failed: %v", dep, err))
+		emit("failed", err.Error())
+		return fmt.Errorf("installing %s failed: %w", dep, err)
+	}
+
+	if _, err := exec.LookPath(dep); err != nil {
+		// Installers like winget update PATH for new processes only
+		msg := fmt.Sprintf("%s was installed but is not on PATH yet, restart the launcher", dep)
+		a.emitLog(source, msg)
+		emit("restart", msg)
+		return nil
+	}
+
+	a.emitLog(source, fmt.Sprintf("%s installed", dep))
+	emit("done", "")
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// diagnosticsEndpoints are backend API responses included in a diagnostics bundle
+var diagnosticsEndpoints = map[string]string{
+	"backend/system.json":         "/api/system",
+	"backend/loader-status.json":  "/api/loader/status",
+	"backend/watcher-status.json": "/api/watcher/status",
+	"backend/index.json":          "/api/index",
+}
+
+// dependencyVersionCommands are run to record toolchain versions
+var dependencyVersionCommands = [][]string{
+	{"go", "version"},
+	{"node", "--version"},
+	{"pnpm", "--version"},
+}
+
+// CollectDiagnostics zips launcher config, logs, launcher and backend status,
+// backend system info and dependency versions into a user-chosen file for
+// support requests. Returns the zip path, or "" if the dialog was cancelled.
+func (a *App) CollectDiagnostics() (string, error) {
+	homeDir, _ := os.UserHomeDir()
+	path, err := wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+		Title:            "Collect Diagnostics",
+		DefaultDirectory: homeDir,
+		DefaultFilename:  fmt.Sprintf("mtools-diagnostics-%s.zip", time.Now().Format("20060102-150405")),
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "Zip archive (*.zip)", Pattern: "*.zip"},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	if err := a.writeDiagnosticsZip(path); err != nil {
+		return "", fmt.Errorf("failed to collect diagnostics: %w", err)
+	}
+
+	a.emitLog("backend", fmt.Sprintf("Diagnostics saved to %s", path))
+	return path, nil
+}
+
+func (a *App) writeDiagnosticsZip(path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+
+	if logsDir, err := a.getLogsDir(); err == nil {
+		if err := a.addLogsToZip(zw, logsDir); err != nil {
+			return err
+		}
+	}
+	if err := a.addConfigToZip(zw); err != nil {
+		return err
+	}
+	if err := addSystemInfoToZip(zw); err != nil {
+		return err
+	}
+
+	launcherState := map[string]interface{}{
+		"status":    a.GetStatus(),
+		"health":    a.GetBackendHealth(),
+		"tls":       a.GetTLSInfo(),
+		"instances": a.ListInstances(),
+		"ports":     a.GetPortsStatus(),
+	}
+	if err := addJSONToZip(zw, "launcher/state.json", launcherState); err != nil {
+		return err
+	}
+
+	// In-memory log tail covers sources without log files (instances, remote)
+	if err := addJSONToZip(zw, "launcher/recent-logs.json", map[string][]string{
+		"backend":  a.GetLogs("backend", 0),
+		"frontend": a.GetLogs("frontend", 0),
+	}); err != nil {
+		return err
+	}
+
+	if a.backendAvailable() {
+		client := &http.Client{Timeout: 5 * time.Second}
+		base := a.backendURL()
+		for name, endpoint := range diagnosticsEndpoints {
+			if err := addURLToZip(zw, client, name, base+endpoint); err != nil {
+				return err
+			}
+		}
+	}
+
+	deps, err := zw.Create("dependencies.txt")
+	if err != nil {
+		return err
+	}
+	for _, args := range dependencyVersionCommands {
+		fmt.Fprintf(deps, "$ %s\n%s\n", strings.Join(args, " "), commandOutput(args))
+	}
+
+	return zw.Close()
+}
+
+func addJSONToZip(zw *zip.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(data)
+	return err
+}
+
+// addURLToZip stores a backend response; request failures are recorded in
+// the file rather than aborting the bundle
+func addURLToZip(zw *zip.Writer, client *http.Client, name, url string) error {
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Fprintf(dst, `{"error": %q}`, err.Error())
+		return nil
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(dst, resp.Body)
+	return err
+}
+
+// commandOutput runs a version command, returning its trimmed output or the error
+func commandOutput(args []string) string {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return "not found"
+	}
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Frontend serving modes for development builds. Production always serves
+// the embedded build.
+const (
+	FrontendModeDev     = "dev"     // pnpm dev with hot reload
+	FrontendModePreview = "preview" // pnpm build, then vite preview
+	FrontendModeStatic  = "static"  // serve a pre-built folder from the launcher
+)
+
+var buildMu sync.Mutex
+
+func validFrontendMode(mode string) bool {
+	switch mode {
+	case "", FrontendModeDev, FrontendModePreview, FrontendModeStatic:
+		return true
+	}
+	return false
+}
+
+// staticFrontendDir returns the folder served in static mode, defaulting to
+// the SvelteKit build output. Callers must hold a.mu.
+func (a *App) staticFrontendDir() string {
+	if a.config.StaticFrontendDir != "" {
+		return a.config.StaticFrontendDir
+	}
+	return filepath.Join(a.projectRoot, "frontend", "build")
+}
+
+// SetFrontendMode selects how the frontend is served on the next start
+func (a *App) SetFrontendMode(mode, staticDir string) error {
+	if !validFrontendMode(mode) {
+		return fmt.Errorf("unknown frontend mode %q", mode)
+	}
+	a.mu.Lock()
+	a.config.FrontendMode = mode
+	a.config.StaticFrontendDir = staticDir
+	a.mu.Unlock()
+	return a.saveConfigToFile()
+}
+
+// SelectStaticFrontendFolder opens a folder dialog for static mode
+func (a *App) SelectStaticFrontendFolder() (string, error) {
+	dir, err := wailsRuntime.OpenDirectoryDialog(a.ctx, wailsRuntime.OpenDialogOptions{
+		Title: "Select Built Frontend Folder",
+	})
+	if err != nil || dir == "" {
+		return dir, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
+		return "", fmt.Errorf("%s has no index.html", dir)
+	}
+	return dir, nil
+}
+
+// BuildFrontend runs `pnpm run build` in the frontend project, streaming the
+// output to the frontend log. Emits "frontendBuild" with the outcome.
+func (a *App) BuildFrontend() error {
+	if isProduction {
+		return fmt.Errorf("production builds ship a pre-built frontend")
+	}
+	if !buildMu.TryLock() {
+		return fmt.Errorf("a frontend build is already running")
+	}
+	defer buildMu.Unlock()
+
+	frontendDir := filepath.Join(a.projectRoot, "frontend")
+	wailsRuntime.EventsEmit(a.ctx, "frontendBuild", map[string]string{"status": "running"})
+
+	err := a.ensureFrontendDeps(frontendDir)
+	if err == nil {
+		err = a.runStreamed("frontend", frontendDir, "pnpm", "run", "build")
+	}
+
+	if err != nil {
+		a.emitLog("frontend", fmt.Sprintf("Frontend build failed: %v", err))
+		wailsRuntime.EventsEmit(a.ctx, "frontendBuild", map[string]string{"status": "failed", "error": err.Error()})
+		return fmt.Errorf("frontend build failed: %w", err)
+	}
+	a.emitLog("frontend", "Frontend build finished")
+	wailsRuntime.EventsEmit(a.ctx, "frontendBuild", map[string]string{"status": "done"})
+	return nil
+}
+
+// ensureFrontendDeps runs `pnpm install` if node_modules is missing
+func (a *App) ensureFrontendDeps(frontendDir string) error {
+	if _, err := os.Stat(filepath.Join(frontendDir, "node_modules")); err == nil {
+		return nil
+	}
+	a.emitLog("frontend", "Installing dependencies...")
+	if err := a.runStreamed("frontend", frontendDir, "pnpm", "install"); err != nil {
+		return fmt.Errorf("failed to install dependencies: %w", err)
+	}
+	a.emitLog("frontend", "Dependencies installed")
+	return nil
+}
+
+// runStreamed runs a command to completion, streaming its output to source
+func (a *App) runStreamed(source, dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	stdout, _ := cmd.StdoutPipe()
+	stderr, _ := cmd.StderrPipe()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); a.streamOutput(stdout, source) }()
+	go func() { defer wg.Done(); a.streamOutput(stderr, source) }()
+	wg.Wait()
+	return cmd.Wait()
+}
+
+// startFrontendPreviewLocked builds the frontend if needed and starts
+// `vite preview`. Callers must hold a.mu; it is released before returning.
+func (a *App) startFrontendPreviewLocked(frontendDir string) error {
+	port, err := a.resolvePort("frontend", "frontend", a.config.FrontendPort, a.backendPort, a.httpsPort)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	a.mu.Unlock()
+
+	if _, err := os.Stat(filepath.Join(frontendDir, "build", "index.html")); err != nil {
+		a.emitLog("frontend", "No production build found, building first...")
+		if err := a.BuildFrontend(); err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.Command("pnpm", "run", "preview", "--port", port, "--strictPort")
+	cmd.Dir = frontendDir
+	setupProcessGroup(cmd)
+
+	stdout, _ := cmd.StdoutPipe()
+	stderr, _ := cmd.StderrPipe()
+
+	a.mu.Lock()
+	if a.frontendCmd != nil || a.frontendServer != nil {
+		a.mu.Unlock()
+		return fmt.Errorf("frontend is already running")
+	}
+	if err := cmd.Start(); err != nil {
+		a.mu.Unlock()
+		return fmt.Errorf("failed to start frontend preview: %w", err)
+	}
+	a.frontendCmd = cmd
+	a.frontendPort = port
+	a.mu.Unlock()
+
+	a.emitLog("frontend", fmt.Sprintf("Frontend preview started (PID: %d)", cmd.Process.Pid))
+
+	go a.streamOutput(stdout, "frontend")
+	go a.streamOutput(stderr, "frontend")
+	go a.waitForProcess(cmd, "frontend")
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Backend health states, from worst to best
+const (
+	HealthStopped      = "stopped"      // process not running
+	HealthIndexError   = "index_error"  // backend could not load the library index
+	HealthUnresponsive = "unresponsive" // process up but HTTP not answering after the grace period
+	HealthStarting     = "starting"     // process up, HTTP not answering yet
+	HealthLoading      = "loading"      // HTTP up, event books still loading
+	HealthReady        = "ready"        // HTTP up, nothing loading
+)
+
+const (
+	HealthPollInterval = 2 * time.Second
+	HealthStartupGrace = 30 * time.Second // `go run` compiles before listening in dev mode
+	HealthHTTPTimeout  = 2 * time.Second
+)
+
+// BackendHealth is the result of polling the backend HTTP API
+type BackendHealth struct {
+	State          string  `json:"state"`
+	HTTPOK         bool    `json:"httpOk"`
+	IndexLoaded    bool    `json:"indexLoaded"`
+	ModeCount      int     `json:"modeCount"`
+	LoadingModes   int     `json:"loadingModes"`
+	LoadingPercent float64 `json:"loadingPercent"` // weighted by book size, 0-100
+	ResponseMs     int64   `json:"responseMs"`
+	Error          string  `json:"error,omitempty"`
+	CheckedAt      int64   `json:"checkedAt"` // unix ms
+}
+
+// startHealthPolling polls the backend until the app context is done
+func (a *App) startHealthPolling() {
+	go func() {
+		ticker := time.NewTicker(HealthPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+				a.pollHealth()
+			}
+		}
+	}()
+}
+
+// pollHealth checks the backend once and emits "backendHealth" if anything changed
+func (a *App) pollHealth() {
+	health := a.checkHealth()
+
+	a.mu.Lock()
+	prev := a.health
+	a.health = health
+	a.mu.Unlock()
+
+	if health.State != prev.State || health.LoadingPercent != prev.LoadingPercent ||
+		health.LoadingModes != prev.LoadingModes || health.Error != prev.Error {
+		wailsRuntime.EventsEmit(a.ctx, "backendHealth", health)
+	}
+	if health.State != prev.State {
+		a.emitStatus()
+	}
+}
+
+// checkHealth determines the backend state from the process and its HTTP API
+func (a *App) checkHealth() BackendHealth {
+	health := BackendHealth{CheckedAt: time.Now().UnixMilli()}
+
+	a.mu.Lock()
+	running := a.remoteActive || (a.backendCmd != nil && a.backendCmd.Process != nil)
+	startedAt := a.backendStartedAt
+	indexError := a.indexError
+	a.mu.Unlock()
+
+	if !running {
+		health.State = HealthStopped
+		if indexError != "" {
+			health.State = HealthIndexError
+			health.Error = indexError
+		}
+		return health
+	}
+
+	base := a.backendURL()
+	client := &http.Client{Timeout: HealthHTTPTimeout}
+
+	start := time.Now()
+	var ping struct {
+		Success bool `json:"success"`
+	}
+	if err := getJSON(client, base+"/api/health", &ping); err != nil || !ping.Success {
+		health.State = HealthStarting
+		if time.Since(startedAt) > HealthStartupGrace {
+			health.State = HealthUnresponsive
+		}
+		if err != nil {
+			health.Error = err.Error()
+		}
+		return health
+	}
+	health.HTTPOK = true
+	health.ResponseMs = time.Since(start).Milliseconds()
+
+	var index struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+		Data    struct {
+			Modes []json.RawMessage `json:"modes"`
+		} `json:"data"`
+	}
+	if err := getJSON(client, base+"/api/index", &index); err != nil || !index.Success {
+		health.State = HealthIndexError
+		health.Error = index.Error
+		if err != nil {
+			health.Error = err.Error()
+		}
+		return health
+	}
+	health.IndexLoaded = true
+	health.ModeCount = len(index.Data.Modes)
+
+	var loader struct {
+		Data struct {
+			Started bool `json:"started"`
+			Modes   map[string]struct {
+				Status     string `json:"status"`
+				BytesRead  int64  `json:"bytes_read"`
+				TotalBytes int64  `json:"total_bytes"`
+			} `json:"modes"`
+		} `json:"data"`
+	}
+	health.State = HealthReady
+	if err := getJSON(client, base+"/api/loader/status", &loader); err == nil && loader.Data.Started {
+		var read, total int64
+		for _, m := range loader.Data.Modes {
+			total += m.TotalBytes
+			switch m.Status {
+			case "loading", "pending":
+				health.LoadingModes++
+				read += m.BytesRead
+			default:
+				read += m.TotalBytes
+			}
+		}
+		if health.LoadingModes > 0 {
+			health.State = HealthLoading
+			if total > 0 {
+				health.LoadingPercent = float64(int(float64(read)/float64(total)*1000)) / 10
+			}
+		}
+	}
+
+	return health
+}
+
+// GetBackendHealth returns the most recent health check result
+func (a *App) GetBackendHealth() BackendHealth {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.health.State == "" {
+		return BackendHealth{State: HealthStopped}
+	}
+	return a.health
+}
+
+// noteBackendOutput records fatal startup errors from backend output
+func (a *App) noteBackendOutput(line string) {
+	if strings.Contains(line, "Failed to load index") {
+		a.mu.Lock()
+		a.indexError = line
+		a.mu.Unlock()
+	}
+}
+
+// getJSON fetches url and decodes the JSON body into v
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the catalog every other language falls back to
+const DefaultLanguage = "en"
+
+// The UI's own locale files are the built-in catalog
+//
+//go:embed ui/src/lib/i18n/locales/*.json
+var embeddedLocales embed.FS
+
+var languageCodeRe = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+// LanguageInfo describes one language available to the UI
+type LanguageInfo struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`   // name of the language in itself
+	Custom bool   `json:"custom"` // loaded from the locales folder rather than built in
+}
+
+// getLocalesDir returns the folder extra <code>.json catalogs are read from.
+// Dropping a file there adds (or overrides) a language without a rebuild.
+func (a *App) getLocalesDir() (string, error) {
+	baseDir, err := a.getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, "locales"), nil
+}
+
+// loadCatalogs reads the built-in catalogs and any from the locales folder.
+// The second result marks codes that came from (or were overridden on) disk.
+func (a *App) loadCatalogs() (map[string]map[string]any, map[string]bool) {
+	catalogs := map[string]map[string]any{}
+	custom := map[string]bool{}
+
+	entries, _ := embeddedLocales.ReadDir("ui/src/lib/i18n/locales")
+	for _, e := range entries {
+		data, err := embeddedLocales.ReadFile(path.Join("ui/src/lib/i18n/locales", e.Name()))
+		if err != nil {
+			continue
+		}
+		var messages map[string]any
+		if json.Unmarshal(data, &messages) == nil {
+			catalogs[strings.TrimSuffix(e.Name(), ".json")] = messages
+		}
+	}
+
+	dir, err := a.getLocalesDir()
+	if err != nil {
+		return catalogs, custom
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, file := range files {
+		code := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !languageCodeRe.MatchString(code) {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var messages map[string]any
+		if err := json.Unmarshal(data, &messages); err != nil {
+			a.emitLog("backend", fmt.Sprintf("Skipping locale %s: %v", filepath.Base(file), err))
+			continue
+		}
+		// A file for a built-in language only needs the keys it changes
+		catalogs[code] = mergeMessages(catalogs[code], messages)
+		custom[code] = true
+	}
+	return catalogs, custom
+}
+
+// mergeMessages returns base with override's keys layered on top, recursively
+func mergeMessages(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if sub, ok := v.(map[string]any); ok {
+			if baseSub, ok := merged[k].(map[string]any); ok {
+				merged[k] = mergeMessages(baseSub, sub)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// GetLanguages lists the built-in and drop-in languages, English first
+func (a *App) GetLanguages() []LanguageInfo {
+	catalogs, custom := a.loadCatalogs()
+
+	languages := make([]LanguageInfo, 0, len(catalogs))
+	for code, messages := range catalogs {
+		name := code
+		if names, ok := messages["languages"].(map[string]any); ok {
+			if n, ok := names[code].(string); ok && n != "" {
+				name = n
+			}
+		}
+		languages = append(languages, LanguageInfo{Code: code, Name: name, Custom: custom[code]})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if (languages[i].Code == DefaultLanguage) != (languages[j].Code == DefaultLanguage) {
+			return languages[i].Code == DefaultLanguage
+		}
+		return languages[i].Code < languages[j].Code
+	})
+	return languages
+}
+
+// GetTranslations returns the strings for lang, with missing keys filled in from English
+func (a *App) GetTranslations(lang string) (map[string]any, error) {
+	catalogs, _ := a.loadCatalogs()
+	messages, ok := catalogs[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language: %s", lang)
+	}
+	if lang == DefaultLanguage {
+		return messages, nil
+	}
+	return mergeMessages(catalogs[DefaultLanguage], messages), nil
+}
+
+// isSupportedLanguage reports whether lang has a catalog
+func (a *App) isSupportedLanguage(lang string) bool {
+	catalogs, _ := a.loadCatalogs()
+	_, ok := catalogs[lang]
+	return ok
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"fmt"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// instanceLogPrefix prefixes the log source of additional backend instances
+const instanceLogPrefix = "instance:"
+
+// backendInstance is an additional backend started next to the main one,
+// e.g. to compare two game builds side by side
+type backendInstance struct {
+	id          string
+	name        string
+	libraryPath string
+	port        string
+	httpsPort   string
+	cmd         *exec.Cmd
+	startedAt   time.Time
+	lastError   string
+}
+
+// InstanceStatus is the UI view of an additional backend instance
+type InstanceStatus struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	LibraryPath string        `json:"libraryPath"`
+	Port        string        `json:"port"`
+	HTTPSPort   string        `json:"httpsPort"`
+	Status      ProcessStatus `json:"status"`
+	PID         int           `json:"pid"`
+	StartedAt   int64         `json:"startedAt"` // unix ms, 0 when stopped
+	LogSource   string        `json:"logSource"` // source for GetLogs / "log" events
+	Error       string        `json:"error,omitempty"`
+}
+
+func (inst *backendInstance) logSource() string {
+	return instanceLogPrefix + inst.id
+}
+
+// statusLocked builds the instance status. Callers must hold a.mu.
+func (inst *backendInstance) statusLocked() InstanceStatus {
+	st := InstanceStatus{
+		ID:          inst.id,
+		Name:        inst.name,
+		LibraryPath: inst.libraryPath,
+		Port:        inst.port,
+		HTTPSPort:   inst.httpsPort,
+		Status:      StatusStopped,
+		LogSource:   inst.logSource(),
+		Error:       inst.lastError,
+	}
+	if inst.cmd != nil && inst.cmd.Process != nil {
+		st.Status = StatusRunning
+		st.PID = inst.cmd.Process.Pid
+		st.StartedAt = inst.startedAt.UnixMilli()
+	} else if inst.lastError != "" {
+		st.Status = StatusError
+	}
+	return st
+}
+
+// AddInstance registers an additional backend instance for libraryPath and
+// starts it on the next free ports
+func (a *App) AddInstance(name, libraryPath string) (InstanceStatus, error) {
+	if libraryPath == "" {
+		return InstanceStatus{}, fmt.Errorf("library path is not set")
+	}
+	if info, err := os.Stat(libraryPath); err != nil || !info.IsDir() {
+		return InstanceStatus{}, fmt.Errorf("library folder not found: %s", libraryPath)
+	}
+
+	a.mu.Lock()
+	a.instanceSeq++
+	inst := &backendInstance{
+		id:          fmt.Sprintf("%d", a.instanceSeq),
+		name:        strings.TrimSpace(name),
+		libraryPath: libraryPath,
+	}
+	if inst.name == "" {
+		inst.name = filepath.Base(libraryPath)
+	}
+	a.instances[inst.id] = inst
+	a.mu.Unlock()
+
+	a.logMu.Lock()
+	a.instanceLogs[inst.logSource()] = make([]string, 0, MaxLogEntries)
+	a.logMu.Unlock()
+
+	if err := a.StartInstance(inst.id); err != nil {
+		return a.instanceStatus(inst.id), err
+	}
+	return a.instanceStatus(inst.id), nil
+}
+
+// StartInstance starts a stopped instance
+func (a *App) StartInstance(id string) error {
+	a.mu.Lock()
+	inst, ok := a.instances[id]
+	if !ok {
+		a.mu.Unlock()
+		return fmt.Errorf("unknown instance %q", id)
+	}
+	if inst.cmd != nil && inst.cmd.Process != nil {
+		a.mu.Unlock()
+		return fmt.Errorf("instance %q is already running", inst.name)
+	}
+
+	// Never reuse a port held by the main backend, the frontend or another instance
+	exclude := []string{a.backendPort, a.httpsPort, a.frontendPort}
+	for _, other := range a.instances {
+		if other != inst && other.cmd != nil {
+			exclude = append(exclude, other.port, other.httpsPort)
+		}
+	}
+	source := inst.logSource()
+	port, err := a.resolvePort(source, "backend", DefaultBackendPort, exclude...)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	httpsPort, err := a.resolvePort(source, "HTTPS", DefaultHTTPSPort, append(exclude, port)...)
+	if err != nil {
+		a.mu.Unlock()
+		return err
+	}
+
+	args := []string{"-library", inst.libraryPath, "-port", port, "-https-port", httpsPort}
+	if a.config.AutoLoadBooks {
+		args = append(args, "-autoload-books")
+	}
+	args = append(args, backendFlagArgs(a.config.BackendFlags)...)
+	cmd := a.backendCommand(args)
+
+	stdout, _ := cmd.StdoutPipe()
+	stderr, _ := cmd.StderrPipe()
+
+	a.emitLog(source, fmt.Sprintf("Starting instance %q on port %s (library: %s)", inst.name, port, inst.libraryPath))
+	if err := cmd.Start(); err != nil {
+		inst.lastError = err.Error()
+		a.mu.Unlock()
+		a.emitInstanceStatus(id)
+		return fmt.Errorf("failed to start instance: %w", err)
+	}
+
+	inst.cmd = cmd
+	inst.port = port
+	inst.httpsPort = httpsPort
+	inst.startedAt = time.Now()
+	inst.lastError = ""
+	a.mu.Unlock()
+
+	a.emitLog(source, fmt.Sprintf("Instance started (PID: %d)", cmd.Process.Pid))
+
+	go a.streamOutput(stdout, source)
+	go a.streamOutput(stderr, source)
+	go a.waitForInstance(inst, cmd)
+
+	a.emitInstanceStatus(id)
+	return nil
+}
+
+// StopInstance kills an instance's process group
+func (a *App) StopInstance(id string) error {
+	a.mu.Lock()
+	inst, ok := a.instances[id]
+	if !ok {
+		a.mu.Unlock()
+		return fmt.Errorf("unknown instance %q", id)
+	}
+	cmd := inst.cmd
+	inst.cmd = nil
+	a.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	a.emitLog(inst.logSource(), "Stopping instance...")
+	a.stopProcessGroup(inst.logSource(), cmd.Process.Pid, a.stopGrace())
+	a.emitLog(inst.logSource(), "Instance stopped")
+	a.emitInstanceStatus(id)
+	return nil
+}
+
+// RemoveInstance stops an instance and forgets it and its logs
+func (a *App) RemoveInstance(id string) error {
+	if err := a.StopInstance(id); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	inst := a.instances[id]
+	delete(a.instances, id)
+	a.mu.Unlock()
+
+	if inst != nil {
+		a.logMu.Lock()
+		delete(a.instanceLogs, inst.logSource())
+		a.logMu.Unlock()
+	}
+	wailsRuntime.EventsEmit(a.ctx, "instanceRemoved", id)
+	return nil
+}
+
+// ListInstances returns all additional instances ordered by creation
+func (a *App) ListInstances() []InstanceStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	list := make([]InstanceStatus, 0, len(a.instances))
+	for _, inst := range a.instances {
+		list = append(list, inst.statusLocked())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return instanceOrder(list[i].ID) < instanceOrder(list[j].ID)
+	})
+	return list
+}
+
+// OpenInstance opens the frontend pointed at an instance's API
+func (a *App) OpenInstance(id string) error {
+	status := a.instanceStatus(id)
+	if status.Status != StatusRunning {
+		return fmt.Errorf("instance is not running")
+	}
+	url := fmt.Sprintf("http://localhost:%s/?lang=%s&api=%s", a.activeFrontendPort(), a.GetLanguage(),
+		neturl.QueryEscape("http://localhost:"+status.Port))
+	return openURL(url)
+}
+
+func (a *App) instanceStatus(id string) InstanceStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if inst, ok := a.instances[id]; ok {
+		return inst.statusLocked()
+	}
+	return InstanceStatus{ID: id, Status: StatusStopped}
+}
+
+func (a *App) emitInstanceStatus(id string) {
+	wailsRuntime.EventsEmit(a.ctx, "instanceStatus", a.instanceStatus(id))
+}
+
+// waitForInstance records the exit of an instance that was not stopped by the user
+func (a *App) waitForInstance(inst *backendInstance, cmd *exec.Cmd) {
+	waitErr := cmd.Wait()
+
+	a.mu.Lock()
+	unexpected := inst.cmd == cmd
+	if unexpected {
+		inst.cmd = nil
+		if waitErr != nil {
+			inst.lastError = waitErr.Error()
+		}
+	}
+	a.mu.Unlock()
+
+	if waitErr != nil {
+		a.emitLog(inst.logSource(), fmt.Sprintf("instance process exited: %v", waitErr))
+	} else {
+		a.emitLog(inst.logSource(), "instance process exited")
+	}
+	a.emitInstanceStatus(inst.id)
+}
+
+// stopAllInstances stops every additional instance (they stay registered)
+func (a *App) stopAllInstances() {
+	a.mu.Lock()
+	ids := make([]string, 0, len(a.instances))
+	for id := range a.instances {
+		ids = append(ids, id)
+	}
+	a.mu.Unlock()
+
+	for _, id := range ids {
+		a.StopInstance(id)
+	}
+}
+
+func instanceOrder(id string) int {
+	var n int
+	fmt.Sscanf(id, "%d", &n)
+	return n
+}
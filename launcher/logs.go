@@ -0,0 +1,463 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	LogFileMaxSize  = 10 * 1024 * 1024 // Rotate log files at 10MB
+	LogFileMaxFiles = 5                // Rotated files kept per source (backend.log.1 ... .5)
+)
+
+// rotatingLog appends lines to a file and rotates it when it grows past maxSize
+type rotatingLog struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	size     int64
+	maxSize  int64
+	maxFiles int
+}
+
+func newRotatingLog(path string) (*rotatingLog, error) {
+	r := &rotatingLog{
+		path:     path,
+		maxSize:  LogFileMaxSize,
+		maxFiles: LogFileMaxFiles,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingLog) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// WriteLine appends a line, rotating first if the file is full
+func (r *rotatingLog) WriteLine(line string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return fmt.Errorf("log file closed")
+	}
+
+	if r.size+int64(len(line))+1 > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := io.WriteString(r.file, line+"\n")
+	r.size += int64(n)
+	return err
+}
+
+// rotate shifts name.N-1 -> name.N ... name -> name.1 and opens a fresh file
+func (r *rotatingLog) rotate() error {
+	r.file.Close()
+	r.file = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxFiles))
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	os.Rename(r.path, r.path+".1")
+
+	return r.open()
+}
+
+// Close closes the underlying file
+func (r *rotatingLog) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// getLogsDir returns the directory holding persisted log files
+func (a *App) getLogsDir() (string, error) {
+	dir := a.dataDir
+	if dir == "" {
+		var err error
+		if dir, err = a.getAppDataDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "logs"), nil
+}
+
+// initLogFiles opens the rotating log files for each process source
+func (a *App) initLogFiles() {
+	logsDir, err := a.getLogsDir()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return
+	}
+
+	files := make(map[string]*rotatingLog)
+	for _, source := range []string{"backend", "frontend"} {
+		if f, err := newRotatingLog(filepath.Join(logsDir, source+".log")); err == nil {
+			files[source] = f
+		}
+	}
+
+	a.logMu.Lock()
+	a.logFiles = files
+	a.logMu.Unlock()
+}
+
+// closeLogFiles flushes and closes the log files
+func (a *App) closeLogFiles() {
+	a.logMu.Lock()
+	defer a.logMu.Unlock()
+	for _, f := range a.logFiles {
+		f.Close()
+	}
+	a.logFiles = nil
+}
+
+// persistLog writes a log line to the source's log file with a full timestamp
+func (a *App) persistLog(source, message string, t time.Time) {
+	a.logMu.Lock()
+	f := a.logFiles[source]
+	a.logMu.Unlock()
+	if f != nil {
+		f.WriteLine(fmt.Sprintf("[%s] %s", t.Format("2006-01-02 15:04:05.000"), message))
+	}
+}
+
+// ExportLogs zips all log files (including rotated ones) and the launcher config
+// into a user-chosen file for bug reports. Returns the path of the zip, or an empty
+// string if the user cancelled the dialog.
+func (a *App) ExportLogs() (string, error) {
+	logsDir, err := a.getLogsDir()
+	if err != nil {
+		return "", err
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	path, err := wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+		Title:            "Export Logs",
+		DefaultDirectory: homeDir,
+		DefaultFilename:  fmt.Sprintf("mtools-logs-%s.zip", time.Now().Format("20060102-150405")),
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "Zip archive (*.zip)", Pattern: "*.zip"},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	if err := a.writeLogsZip(path, logsDir); err != nil {
+		return "", fmt.Errorf("failed to export logs: %w", err)
+	}
+
+	a.emitLog("backend", fmt.Sprintf("Logs exported to %s", path))
+	return path, nil
+}
+
+// writeLogsZip writes every file in logsDir plus config.json into a zip at path
+func (a *App) writeLogsZip(path, logsDir string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	if err := a.addLogsToZip(zw, logsDir); err != nil {
+		return err
+	}
+	if err := a.addConfigToZip(zw); err != nil {
+		return err
+	}
+	if err := addSystemInfoToZip(zw); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// addLogsToZip adds every file in logsDir (including rotated ones) under logs/
+func (a *App) addLogsToZip(zw *zip.Writer, logsDir string) error {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := addFileToZip(zw, filepath.Join(logsDir, entry.Name()), "logs/"+entry.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addConfigToZip adds the launcher config with backend env values masked,
+// since they may hold tokens
+func (a *App) addConfigToZip(zw *zip.Writer) error {
+	cfg := a.GetConfig()
+	env := make([]BackendOption, len(cfg.BackendEnv))
+	for i, o := range cfg.BackendEnv {
+		if o.Value != "" {
+			o.Value = "<redacted>"
+		}
+		env[i] = o
+	}
+	cfg.BackendEnv = env
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	dst, err := zw.Create("config.json")
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(data)
+	return err
+}
+
+// addSystemInfoToZip adds basic environment info that helps triage
+// platform-specific issues
+func addSystemInfoToZip(zw *zip.Writer) error {
+	info, err := zw.Create("system.txt")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(info, "os: %s\narch: %s\ngo: %s\nlauncher: %s\nproduction: %v\nexported: %s\n",
+		runtime.GOOS, runtime.GOARCH, runtime.Version(), Version, isProduction, time.Now().Format(time.RFC3339))
+	return nil
+}
+
+func addFileToZip(zw *zip.Writer, srcPath, name string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// RevealLogsFolder opens the logs folder in the system file manager
+func (a *App) RevealLogsFolder() error {
+	logsDir, err := a.getLogsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return exec.Command("explorer", logsDir).Start()
+	}
+	return openURL(logsDir)
+}
+
+// Log levels detected from log lines
+const (
+	LevelError = "error"
+	LevelWarn  = "warn"
+	LevelInfo  = "info"
+	LevelDebug = "debug"
+)
+
+const DefaultLogQueryLimit = 500
+
+// LogLine is a single parsed log line
+type LogLine struct {
+	Source  string `json:"source"`
+	Level   string `json:"level"`
+	Time    string `json:"time"`
+	Message string `json:"message"`
+}
+
+// LogQuery filters log lines. Empty fields match everything.
+type LogQuery struct {
+	Source        string   `json:"source"` // "backend", "frontend" or "" for both
+	Levels        []string `json:"levels"` // e.g. ["error", "warn"]
+	Search        string   `json:"search"` // substring to look for
+	CaseSensitive bool     `json:"caseSensitive"`
+	Limit         int      `json:"limit"` // most recent N matches, default 500
+}
+
+// detectLevel guesses the severity of a log line from its content
+func detectLevel(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "panic"),
+		strings.Contains(lower, "fatal"),
+		strings.Contains(lower, "error"),
+		strings.Contains(lower, "failed"),
+		strings.HasPrefix(lower, "goroutine "):
+		return LevelError
+	case strings.Contains(lower, "warn"):
+		return LevelWarn
+	case strings.Contains(lower, "debug"):
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+// matches reports whether a line passes the query filters
+func (q LogQuery) matches(line LogLine) bool {
+	if q.Source != "" && q.Source != "all" && line.Source != q.Source {
+		return false
+	}
+	if len(q.Levels) > 0 {
+		found := false
+		for _, level := range q.Levels {
+			if level == line.Level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Search != "" {
+		if q.CaseSensitive {
+			return strings.Contains(line.Message, q.Search)
+		}
+		return strings.Contains(strings.ToLower(line.Message), strings.ToLower(q.Search))
+	}
+	return true
+}
+
+// parseLogFileLine splits a persisted "[2006-01-02 15:04:05.000] message" line
+func parseLogFileLine(source, raw string) LogLine {
+	line := LogLine{Source: source, Message: raw}
+	if strings.HasPrefix(raw, "[") {
+		if end := strings.Index(raw, "] "); end > 0 {
+			line.Time = raw[1:end]
+			line.Message = raw[end+2:]
+		}
+	}
+	line.Level = detectLevel(line.Message)
+	return line
+}
+
+// QueryLogs searches the full persisted log history (including rotated files),
+// returning the most recent matches in chronological order.
+func (a *App) QueryLogs(query LogQuery) ([]LogLine, error) {
+	if query.Limit <= 0 {
+		query.Limit = DefaultLogQueryLimit
+	}
+
+	logsDir, err := a.getLogsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []string{"backend", "frontend"}
+	if query.Source == "backend" || query.Source == "frontend" {
+		sources = []string{query.Source}
+	}
+
+	var matches []LogLine
+	for _, source := range sources {
+		base := filepath.Join(logsDir, source+".log")
+
+		// Oldest rotated file first so results stay chronological
+		paths := make([]string, 0, LogFileMaxFiles+1)
+		for i := LogFileMaxFiles; i >= 1; i-- {
+			paths = append(paths, fmt.Sprintf("%s.%d", base, i))
+		}
+		paths = append(paths, base)
+
+		for _, path := range paths {
+			if err := scanLogFile(path, source, query, &matches); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Interleave sources by timestamp
+	if len(sources) > 1 {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Time < matches[j].Time })
+	}
+
+	if len(matches) > query.Limit {
+		matches = matches[len(matches)-query.Limit:]
+	}
+	return matches, nil
+}
+
+// scanLogFile appends lines from path that match query
+func scanLogFile(path, source string, query LogQuery, matches *[]LogLine) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := parseLogFileLine(source, scanner.Text())
+		if query.matches(line) {
+			*matches = append(*matches, line)
+		}
+	}
+	return scanner.Err()
+}
+
+// FollowLogs subscribes to new log lines matching query. Each match is emitted
+// as a "logFollow" event until UnfollowLogs is called or FollowLogs is called again.
+func (a *App) FollowLogs(query LogQuery) {
+	a.logMu.Lock()
+	a.logFollow = &query
+	a.logMu.Unlock()
+}
+
+// UnfollowLogs cancels the FollowLogs subscription
+func (a *App) UnfollowLogs() {
+	a.logMu.Lock()
+	a.logFollow = nil
+	a.logMu.Unlock()
+}
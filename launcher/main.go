@@ -19,6 +19,7 @@ var appIcon []byte
 
 func main() {
 	app := NewApp()
+	app.startedAtLogin = launchedAtLogin()
 
 	err := wails.Run(&options.App{
 		Title:     "Mnemoo Tools",
@@ -29,9 +30,11 @@ func main() {
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
+		StartHidden:      app.startedAtLogin,
 		BackgroundColour: &options.RGBA{R: 18, G: 18, B: 18, A: 1},
 		OnStartup:        app.startup,
 		OnShutdown:       app.shutdown,
+		OnBeforeClose:    app.beforeClose,
 		SingleInstanceLock: &options.SingleInstanceLock{
 			UniqueId: "mtools-launcher-c9c8fd93-6758-4144-87d1-34bdb0a8bd60",
 		},
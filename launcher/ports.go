@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// MaxPortProbe is how many ports above the preferred one are tried
+const MaxPortProbe = 100
+
+// DefaultHTTPSPort is the backend's HTTPS (LGS) port
+const DefaultHTTPSPort = "7755"
+
+// isPortFree reports whether a TCP port can be bound on all interfaces
+func isPortFree(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// findFreePort returns preferred if it is free, otherwise the next free port
+// above it. Ports listed in exclude are skipped even if free, so the backend
+// HTTP and HTTPS ports never collide.
+func findFreePort(preferred string, exclude ...string) (string, error) {
+	start, err := strconv.Atoi(preferred)
+	if err != nil || start <= 0 || start > 65535 {
+		return "", fmt.Errorf("invalid port: %q", preferred)
+	}
+
+	skip := make(map[int]bool, len(exclude))
+	for _, p := range exclude {
+		if n, err := strconv.Atoi(p); err == nil {
+			skip[n] = true
+		}
+	}
+
+	for port := start; port < start+MaxPortProbe && port <= 65535; port++ {
+		if skip[port] {
+			continue
+		}
+		if isPortFree(port) {
+			return strconv.Itoa(port), nil
+		}
+	}
+	return "", fmt.Errorf("no free port in range %d-%d", start, start+MaxPortProbe-1)
+}
+
+// resolvePort finds a free port for source, logging when it differs from the preferred one
+func (a *App) resolvePort(source, name, preferred string, exclude ...string) (string, error) {
+	port, err := findFreePort(preferred, exclude...)
+	if err != nil {
+		return "", fmt.Errorf("%s port: %w", name, err)
+	}
+	if port != preferred {
+		a.emitLog(source, fmt.Sprintf("Port %s is busy, using %s port %s instead", preferred, name, port))
+	}
+	return port, nil
+}
+
+// activeBackendPort returns the HTTP port the backend was started on
+func (a *App) activeBackendPort() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.backendPort == "" {
+		return DefaultBackendPort
+	}
+	return a.backendPort
+}
+
+// activeFrontendPort returns the port the frontend was started on
+func (a *App) activeFrontendPort() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.frontendPort == "" {
+		return a.config.FrontendPort
+	}
+	return a.frontendPort
+}
+
+// PortStatus represents information about a port
+type PortStatus struct {
+	Port    string `json:"port"`
+	InUse   bool   `json:"inUse"`
+	PID     int    `json:"pid,omitempty"`
+	Process string `json:"process,omitempty"` // executable name of the listener
+	Command string `json:"command,omitempty"` // full command line of the listener
+	// Owner names the mtools process holding the port (backend, frontend,
+	// instance:<id> or launcher); empty means something else owns it
+	Owner string `json:"owner,omitempty"`
+	// NeedsConfirmation is set when a kill was refused because the port
+	// isn't held by an mtools process; retry with confirmed=true
+	NeedsConfirmation bool `json:"needsConfirmation,omitempty"`
+	Killed            bool `json:"killed,omitempty"`
+}
+
+// CheckPortInUse checks if a port is in use and reports which process holds it
+func (a *App) CheckPortInUse(port string) PortStatus {
+	status := PortStatus{Port: port, InUse: false}
+
+	// Try to listen on the port to check if it's in use
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		status.InUse = true
+		if pid := portListenerPID(port); pid > 0 {
+			status.PID = pid
+			if p, err := process.NewProcess(int32(pid)); err == nil {
+				status.Process, _ = p.Name()
+				status.Command, _ = p.Cmdline()
+			}
+			status.Owner = a.processOwner(pid)
+		}
+	} else {
+		listener.Close()
+	}
+
+	return status
+}
+
+// KillProcessOnPort stops the process listening on the specified port. It is
+// asked to exit first and only force-killed after the stop grace period.
+// Processes that weren't started by mtools are left alone unless confirmed.
+func (a *App) KillProcessOnPort(port string, confirmed bool) PortStatus {
+	status := a.CheckPortInUse(port)
+	if !status.InUse {
+		return status
+	}
+
+	if status.PID == 0 {
+		a.emitLog("backend", fmt.Sprintf("Port %s is in use but its process could not be determined", port))
+		return status
+	}
+	if status.Owner == "launcher" {
+		a.emitLog("backend", fmt.Sprintf("Port %s is held by the launcher itself; stop the frontend instead", port))
+		return status
+	}
+	if status.Owner == "" && !confirmed {
+		status.NeedsConfirmation = true
+		return status
+	}
+
+	a.emitLog("backend", fmt.Sprintf("Stopping %s (PID %d) on port %s...", describePortProcess(status), status.PID, port))
+
+	if grace := a.stopGrace(); grace > 0 {
+		if err := terminateProcess(status.PID); err != nil {
+			a.emitLog("backend", fmt.Sprintf("Graceful stop failed (%v), killing process", err))
+		} else if !waitForProcessExit(status.PID, grace) {
+			a.emitLog("backend", fmt.Sprintf("Process did not exit within %s, killing", grace))
+		}
+	}
+	if exists, _ := process.PidExists(int32(status.PID)); exists {
+		killProcess(status.PID)
+	}
+
+	// Wait a bit and check again
+	time.Sleep(500 * time.Millisecond)
+	newStatus := a.CheckPortInUse(port)
+	newStatus.Killed = !newStatus.InUse
+	return newStatus
+}
+
+// GetPortsStatus returns the status of both backend and frontend ports
+func (a *App) GetPortsStatus() []PortStatus {
+	return []PortStatus{
+		a.CheckPortInUse(DefaultBackendPort),
+		a.CheckPortInUse(a.config.FrontendPort),
+	}
+}
+
+// KillPortProcesses stops processes on both backend and frontend ports
+func (a *App) KillPortProcesses(confirmed bool) []PortStatus {
+	return []PortStatus{
+		a.KillProcessOnPort(DefaultBackendPort, confirmed),
+		a.KillProcessOnPort(a.config.FrontendPort, confirmed),
+	}
+}
+
+// portListenerPID returns the PID listening on a TCP port, or 0 if unknown
+func portListenerPID(port string) int {
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return 0
+	}
+	conns, err := psnet.Connections("tcp")
+	if err != nil {
+		return 0
+	}
+	for _, c := range conns {
+		if c.Status == "LISTEN" && c.Laddr.Port == uint32(n) && c.Pid > 0 {
+			return int(c.Pid)
+		}
+	}
+	return 0
+}
+
+// processOwner names the mtools process pid belongs to, or "" if none.
+// Walks up the parent chain and also checks process groups, since `go run`
+// and pnpm leave the actual server a few levels below the child we started.
+func (a *App) processOwner(pid int) string {
+	if pid == os.Getpid() {
+		return "launcher"
+	}
+
+	roots := map[int]string{}
+	a.mu.Lock()
+	if a.backendCmd != nil && a.backendCmd.Process != nil {
+		roots[a.backendCmd.Process.Pid] = "backend"
+	}
+	if a.frontendCmd != nil && a.frontendCmd.Process != nil {
+		roots[a.frontendCmd.Process.Pid] = "frontend"
+	}
+	for id, inst := range a.instances {
+		if inst.cmd != nil && inst.cmd.Process != nil {
+			roots[inst.cmd.Process.Pid] = "instance:" + id
+		}
+	}
+	a.mu.Unlock()
+
+	if owner, ok := roots[processGroupOf(pid)]; ok {
+		return owner
+	}
+	current := int32(pid)
+	for i := 0; i < 16 && current > 1; i++ {
+		if owner, ok := roots[int(current)]; ok {
+			return owner
+		}
+		p, err := process.NewProcess(current)
+		if err != nil {
+			break
+		}
+		parent, err := p.Ppid()
+		if err != nil || parent == current {
+			break
+		}
+		current = parent
+	}
+	return ""
+}
+
+// waitForProcessExit polls until pid is gone or timeout passes
+func waitForProcessExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if exists, err := process.PidExists(int32(pid)); err == nil && !exists {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func describePortProcess(status PortStatus) string {
+	name := status.Process
+	if name == "" {
+		name = "process"
+	}
+	if status.Owner != "" {
+		return fmt.Sprintf("%s [%s]", name, status.Owner)
+	}
+	return name
+}
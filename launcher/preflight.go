@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Rough memory estimates for the pre-flight summary. Outcomes are three
+// numbers in a struct (~32 bytes each once loaded); zstd-compressed books
+// typically expand ~12x when decompressed into memory.
+const (
+	bytesPerOutcome     = 32
+	zstdExpansionFactor = 12
+	eventsOverheadPct   = 20
+)
+
+// indexMode mirrors the fields of a mode entry in publish_files/index.json
+type indexMode struct {
+	Name    string  `json:"name"`
+	Cost    float64 `json:"cost"`
+	Events  string  `json:"events"`
+	Weights string  `json:"weights"`
+}
+
+// ModeReport is the pre-flight result for a single mode
+type ModeReport struct {
+	Name            string  `json:"name"`
+	Cost            float64 `json:"cost"`
+	WeightsFile     string  `json:"weightsFile"`
+	WeightsExists   bool    `json:"weightsExists"`
+	WeightsBytes    int64   `json:"weightsBytes"`
+	Outcomes        int64   `json:"outcomes"`
+	EventsFile      string  `json:"eventsFile"`
+	EventsExists    bool    `json:"eventsExists"`
+	EventsBytes     int64   `json:"eventsBytes"`
+	EstimatedMemory int64   `json:"estimatedMemory"` // bytes with books loaded
+}
+
+// LibraryReport summarises a library before the backend is started
+type LibraryReport struct {
+	LibraryPath string       `json:"libraryPath"`
+	IndexPath   string       `json:"indexPath"`
+	Valid       bool         `json:"valid"` // false if the backend would fail to start
+	Modes       []ModeReport `json:"modes"`
+	Errors      []string     `json:"errors"`
+	Warnings    []string     `json:"warnings"`
+	// EstimatedMemory covers LUTs only; EstimatedBooksMemory adds event books
+	EstimatedMemory      int64 `json:"estimatedMemory"`
+	EstimatedBooksMemory int64 `json:"estimatedBooksMemory"`
+}
+
+func (r *LibraryReport) errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *LibraryReport) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// ValidateLibrary checks that a library folder has a parseable index.json,
+// that every referenced file exists, and estimates memory use
+func (a *App) ValidateLibrary(libraryPath string) LibraryReport {
+	return validateLibrary(libraryPath)
+}
+
+func validateLibrary(libraryPath string) LibraryReport {
+	publishDir := filepath.Join(libraryPath, "publish_files")
+	report := LibraryReport{
+		LibraryPath: libraryPath,
+		IndexPath:   filepath.Join(publishDir, "index.json"),
+		Modes:       []ModeReport{},
+		Errors:      []string{},
+		Warnings:    []string{},
+	}
+
+	if info, err := os.Stat(libraryPath); err != nil || !info.IsDir() {
+		report.errorf("library folder not found: %s", libraryPath)
+		return report
+	}
+
+	data, err := os.ReadFile(report.IndexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			report.errorf("publish_files/index.json not found, is this a library folder?")
+		} else {
+			report.errorf("cannot read index.json: %v", err)
+		}
+		return report
+	}
+
+	var index struct {
+		Modes []indexMode `json:"modes"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		report.errorf("index.json is not valid JSON: %v", err)
+		return report
+	}
+	if len(index.Modes) == 0 {
+		report.errorf("index.json defines no modes")
+		return report
+	}
+
+	seen := make(map[string]bool)
+	for _, mode := range index.Modes {
+		mr := ModeReport{
+			Name:        mode.Name,
+			Cost:        mode.Cost,
+			WeightsFile: mode.Weights,
+			EventsFile:  mode.Events,
+		}
+
+		switch key := strings.ToLower(mode.Name); {
+		case mode.Name == "":
+			report.errorf("a mode has no name")
+		case seen[key]:
+			report.errorf("duplicate mode %q", mode.Name)
+		default:
+			seen[key] = true
+		}
+		if mode.Cost <= 0 {
+			report.warnf("mode %q has cost %v", mode.Name, mode.Cost)
+		}
+
+		if mode.Weights == "" {
+			report.errorf("mode %q has no weights file", mode.Name)
+		} else if info, err := os.Stat(filepath.Join(publishDir, mode.Weights)); err != nil {
+			report.errorf("mode %q: weights file %s not found", mode.Name, mode.Weights)
+		} else {
+			mr.WeightsExists = true
+			mr.WeightsBytes = info.Size()
+			lines, err := checkWeightsFile(filepath.Join(publishDir, mode.Weights))
+			if err != nil {
+				report.errorf("mode %q: %s: %v", mode.Name, mode.Weights, err)
+			}
+			mr.Outcomes = lines
+		}
+
+		// Books are only needed for event playback, so a missing file is not fatal
+		if mode.Events == "" {
+			report.warnf("mode %q has no events file", mode.Name)
+		} else if info, err := os.Stat(filepath.Join(publishDir, mode.Events)); err != nil {
+			report.warnf("mode %q: events file %s not found, books will be unavailable", mode.Name, mode.Events)
+		} else {
+			mr.EventsExists = true
+			mr.EventsBytes = info.Size()
+		}
+
+		lutMemory := mr.Outcomes * bytesPerOutcome
+		booksMemory := mr.EventsBytes
+		if strings.HasSuffix(mode.Events, ".zst") {
+			booksMemory *= zstdExpansionFactor
+		}
+		booksMemory += booksMemory * eventsOverheadPct / 100
+		mr.EstimatedMemory = lutMemory + booksMemory
+
+		report.EstimatedMemory += lutMemory
+		report.EstimatedBooksMemory += mr.EstimatedMemory
+		report.Modes = append(report.Modes, mr)
+	}
+
+	report.Valid = len(report.Errors) == 0
+	return report
+}
+
+// checkWeightsFile validates the first line of a LUT CSV (sim_id,weight,payout)
+// and counts its lines
+func checkWeightsFile(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, 1<<20)
+	first, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	first = strings.TrimSpace(first)
+	if first == "" {
+		return 0, fmt.Errorf("file is empty")
+	}
+	parts := strings.Split(first, ",")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("expected sim_id,weight,payout, got %d fields", len(parts))
+	}
+	for _, p := range parts {
+		if _, err := strconv.ParseUint(strings.TrimSpace(p), 10, 64); err != nil {
+			return 0, fmt.Errorf("first line is not numeric: %q", first)
+		}
+	}
+
+	lines := int64(1)
+	buf := make([]byte, 1<<20)
+	for {
+		n, err := r.Read(buf)
+		lines += int64(bytes.Count(buf[:n], []byte{'\n'}))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+	return lines, nil
+}
+
+// formatBytes renders a byte count for log lines
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
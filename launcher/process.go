@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultStopGraceSeconds is how long a child gets to exit cleanly before it is killed
+const DefaultStopGraceSeconds = 10
+
+// stopGrace returns the configured graceful-stop period
+func (a *App) stopGrace() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return time.Duration(a.config.StopGraceSeconds) * time.Second
+}
+
+// stopProcessGroup asks a child process group to shut down (SIGTERM, or
+// CTRL_C on Windows) so the backend can flush pending writes, waits up to
+// grace for it to exit, and only then kills it. A zero grace kills immediately.
+func (a *App) stopProcessGroup(source string, pid int, grace time.Duration) {
+	if grace > 0 {
+		if err := terminateProcessGroup(pid); err != nil {
+			a.emitLog(source, fmt.Sprintf("Graceful stop failed (%v), killing process", err))
+		} else if waitForGroupExit(pid, grace) {
+			return
+		} else {
+			a.emitLog(source, fmt.Sprintf("Process did not exit within %s, killing", grace))
+		}
+	}
+	killProcessGroup(pid)
+}
+
+// waitForGroupExit polls until the process group is gone or timeout passes
+func waitForGroupExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processGroupAlive(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
@@ -11,10 +11,37 @@ func setupProcessGroup(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
 
+// terminateProcessGroup asks every process in the group to shut down cleanly
+func terminateProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+// killProcessGroup force-kills every process in the group
 func killProcessGroup(pid int) {
-	// First try graceful shutdown
-	syscall.Kill(-pid, syscall.SIGTERM)
+	syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// processGroupAlive reports whether any process in the group still exists.
+// `go run` exits before the server it spawned, so the leader alone is not enough.
+func processGroupAlive(pid int) bool {
+	return syscall.Kill(-pid, 0) == nil
+}
+
+// terminateProcess asks a single process to shut down cleanly
+func terminateProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
+
+// killProcess force-kills a single process
+func killProcess(pid int) {
+	syscall.Kill(pid, syscall.SIGKILL)
+}
 
-	// Note: In production, you might want to wait and then SIGKILL
-	// For now, we'll let the process handle SIGTERM
+// processGroupOf returns the process group of pid, or 0 if unknown
+func processGroupOf(pid int) int {
+	pgid, err := syscall.Getpgid(pid)
+	if err != nil {
+		return 0
+	}
+	return pgid
 }
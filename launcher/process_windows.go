@@ -5,14 +5,74 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32                  = windows.NewLazySystemDLL("kernel32.dll")
+	procAttachConsole         = kernel32.NewProc("AttachConsole")
+	procFreeConsole           = kernel32.NewProc("FreeConsole")
+	procSetConsoleCtrlHandler = kernel32.NewProc("SetConsoleCtrlHandler")
+
+	// consoleMu serializes terminateProcessGroup: a process has at most one
+	// console attached at a time
+	consoleMu sync.Mutex
 )
 
 func setupProcessGroup(cmd *exec.Cmd) {
-	// Windows handles process trees differently
-	// taskkill /T will kill child processes
+	// The launcher is a GUI process without a console, so it cannot send
+	// console control events to a child sharing its own. The child gets a
+	// hidden console of its own that terminateProcessGroup attaches to;
+	// taskkill /T covers the tree
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.CREATE_NO_WINDOW}
+}
+
+// terminateProcessGroup attaches to the child's console and sends CTRL_C to
+// every process on it, which Go programs receive as os.Interrupt
+func terminateProcessGroup(pid int) error {
+	consoleMu.Lock()
+	defer consoleMu.Unlock()
+
+	if r, _, err := procAttachConsole.Call(uintptr(pid)); r == 0 {
+		return fmt.Errorf("attach console: %w", err)
+	}
+	// Ignore the event in the launcher, which shares the console now, until
+	// it has been delivered and the console is detached again
+	procSetConsoleCtrlHandler.Call(0, 1)
+	err := windows.GenerateConsoleCtrlEvent(windows.CTRL_C_EVENT, 0)
+	time.Sleep(50 * time.Millisecond)
+	procFreeConsole.Call()
+	procSetConsoleCtrlHandler.Call(0, 0)
+	return err
 }
 
 func killProcessGroup(pid int) {
 	// Use taskkill with /T to kill process tree
 	exec.Command("taskkill", "/F", "/T", "/PID", fmt.Sprint(pid)).Run()
 }
+
+// processGroupAlive reports whether the group leader still exists
+func processGroupAlive(pid int) bool {
+	exists, err := process.PidExists(int32(pid))
+	return err != nil || exists
+}
+
+// terminateProcess asks a process to close without /F; console programs may
+// ignore this, in which case the caller falls back to killProcess
+func terminateProcess(pid int) error {
+	return exec.Command("taskkill", "/PID", fmt.Sprint(pid)).Run()
+}
+
+func killProcess(pid int) {
+	exec.Command("taskkill", "/F", "/PID", fmt.Sprint(pid)).Run()
+}
+
+// processGroupOf is not tracked on Windows; ownership uses the parent chain
+func processGroupOf(pid int) int {
+	return 0
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// RemoteReconnectDelay is how long to wait before reconnecting the remote log stream
+const RemoteReconnectDelay = 5 * time.Second
+
+// remoteLogLine mirrors the backend's logbuf.Line
+type remoteLogLine struct {
+	Seq     uint64 `json:"seq"`
+	Message string `json:"message"`
+}
+
+// backendURL returns the base URL of the backend the launcher talks to:
+// the configured remote URL in remote mode, the local backend otherwise
+func (a *App) backendURL() string {
+	a.mu.Lock()
+	remote, remoteURL := a.config.RemoteMode, a.config.RemoteURL
+	a.mu.Unlock()
+	if remote && remoteURL != "" {
+		return strings.TrimRight(remoteURL, "/")
+	}
+	return "http://localhost:" + a.activeBackendPort()
+}
+
+// backendAvailable reports whether a local backend is running or a remote
+// backend is connected
+func (a *App) backendAvailable() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.remoteActive || (a.backendCmd != nil && a.backendCmd.Process != nil)
+}
+
+// validateRemoteURL checks that a remote backend URL is an absolute http(s) URL
+func validateRemoteURL(raw string) error {
+	u, err := neturl.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("remote backend URL must look like http://host:7754")
+	}
+	return nil
+}
+
+// SetRemoteBackend switches between spawning a local backend and connecting
+// to a remote one. The backend must be stopped first.
+func (a *App) SetRemoteBackend(enabled bool, remoteURL string) error {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if enabled {
+		if err := validateRemoteURL(remoteURL); err != nil {
+			return err
+		}
+	}
+	if a.backendAvailable() {
+		return fmt.Errorf("stop the backend before switching backend mode")
+	}
+
+	a.mu.Lock()
+	a.config.RemoteMode = enabled
+	if remoteURL != "" {
+		a.config.RemoteURL = remoteURL
+	}
+	a.mu.Unlock()
+
+	a.emitStatus()
+	return a.saveConfigToFile()
+}
+
+// startRemoteBackendLocked connects to the remote backend instead of spawning
+// one: logs are streamed from /api/logs/stream and health polling uses the
+// remote URL. Callers must hold a.mu; it is released before returning.
+func (a *App) startRemoteBackendLocked() error {
+	if a.remoteActive {
+		a.mu.Unlock()
+		return fmt.Errorf("remote backend is already connected")
+	}
+	remoteURL := strings.TrimRight(a.config.RemoteURL, "/")
+	if err := validateRemoteURL(remoteURL); err != nil {
+		a.mu.Unlock()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.remoteActive = true
+	a.remoteCancel = cancel
+	a.backendStartedAt = time.Now()
+	a.backendFailed = false
+	a.indexError = ""
+	a.mu.Unlock()
+
+	a.emitLog("backend", fmt.Sprintf("Connecting to remote backend %s", remoteURL))
+	go a.streamRemoteLogs(ctx, remoteURL)
+	a.emitStatus()
+	return nil
+}
+
+// stopRemoteBackendLocked disconnects from the remote backend. Callers must hold a.mu.
+func (a *App) stopRemoteBackendLocked() {
+	if !a.remoteActive {
+		return
+	}
+	a.remoteCancel()
+	a.remoteCancel = nil
+	a.remoteActive = false
+	a.emitLog("backend", "Disconnected from remote backend")
+}
+
+// streamRemoteLogs follows the remote log stream until ctx is cancelled,
+// reconnecting after failures and resuming from the last seen line
+func (a *App) streamRemoteLogs(ctx context.Context, remoteURL string) {
+	var lastSeq uint64
+	failing := false // only the first failure of a series is logged
+
+	for {
+		err := a.followRemoteLogs(ctx, remoteURL, &lastSeq, func() {
+			a.emitLog("backend", "Remote log stream connected")
+			failing = false
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if !failing {
+			a.emitLog("backend", fmt.Sprintf("Remote log stream unavailable: %v (retrying every %s)", err, RemoteReconnectDelay))
+			failing = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(RemoteReconnectDelay):
+		}
+	}
+}
+
+// followRemoteLogs reads one SSE connection, emitting lines as backend logs
+func (a *App) followRemoteLogs(ctx context.Context, remoteURL string, lastSeq *uint64, onConnect func()) error {
+	url := fmt.Sprintf("%s/api/logs/stream?since=%d", remoteURL, *lastSeq)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	onConnect()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var line remoteLogLine
+		if err := json.Unmarshal([]byte(data), &line); err != nil {
+			continue
+		}
+		*lastSeq = line.Seq
+		a.noteBackendOutput(line.Message)
+		a.emitLog("backend", line.Message)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("stream closed")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	ResourcePollInterval = 2 * time.Second
+	// DefaultMemoryWarningMB is the backend memory use that triggers a warning
+	DefaultMemoryWarningMB = 8192
+)
+
+// ProcessResources is the CPU and memory use of a child process and its
+// descendants (`go run` and `pnpm dev` run the real server as a grandchild)
+type ProcessResources struct {
+	PID         int     `json:"pid"`
+	CPUPercent  float64 `json:"cpuPercent"` // may exceed 100 on multi-core
+	MemoryBytes uint64  `json:"memoryBytes"`
+	Processes   int     `json:"processes"`
+}
+
+// resourceMonitor keeps gopsutil handles between polls; CPU percent is
+// measured relative to the previous call on the same handle
+type resourceMonitor struct {
+	procs       map[int32]*process.Process
+	memoryAlert bool // warning emitted, waiting for usage to drop
+}
+
+// startResourcePolling samples child processes until the app context is done
+func (a *App) startResourcePolling() {
+	mon := &resourceMonitor{procs: make(map[int32]*process.Process)}
+	go func() {
+		ticker := time.NewTicker(ResourcePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+				a.pollResources(mon)
+			}
+		}
+	}()
+}
+
+func (a *App) pollResources(mon *resourceMonitor) {
+	a.mu.Lock()
+	backendPID, frontendPID := 0, 0
+	if a.backendCmd != nil && a.backendCmd.Process != nil {
+		backendPID = a.backendCmd.Process.Pid
+	}
+	if a.frontendCmd != nil && a.frontendCmd.Process != nil {
+		frontendPID = a.frontendCmd.Process.Pid
+	}
+	threshold := a.config.MemoryWarningMB
+	a.mu.Unlock()
+
+	seen := make(map[int32]bool)
+	var backend, frontend *ProcessResources
+	if backendPID != 0 {
+		backend = mon.sample(int32(backendPID), seen)
+	}
+	if frontendPID != 0 {
+		frontend = mon.sample(int32(frontendPID), seen)
+	} else if a.frontendServer != nil {
+		// Static mode serves the frontend from the launcher process itself
+		frontend = mon.sample(int32(os.Getpid()), seen)
+	}
+	for pid := range mon.procs {
+		if !seen[pid] {
+			delete(mon.procs, pid)
+		}
+	}
+
+	a.mu.Lock()
+	a.backendResources = backend
+	a.frontendResources = frontend
+	a.mu.Unlock()
+
+	wailsRuntime.EventsEmit(a.ctx, "resources", map[string]*ProcessResources{
+		"backend":  backend,
+		"frontend": frontend,
+	})
+
+	a.checkMemoryThreshold(mon, backend, threshold)
+}
+
+// checkMemoryThreshold emits "memoryWarning" once when the backend crosses
+// the threshold and re-arms after usage drops below 90% of it
+func (a *App) checkMemoryThreshold(mon *resourceMonitor, backend *ProcessResources, thresholdMB int) {
+	if backend == nil || thresholdMB <= 0 {
+		mon.memoryAlert = false
+		return
+	}
+	limit := uint64(thresholdMB) * 1024 * 1024
+	if mon.memoryAlert {
+		if backend.MemoryBytes < limit/10*9 {
+			mon.memoryAlert = false
+		}
+		return
+	}
+	if backend.MemoryBytes < limit {
+		return
+	}
+	mon.memoryAlert = true
+
+	msg := fmt.Sprintf("Backend memory %s exceeds warning threshold %d MB", formatBytes(int64(backend.MemoryBytes)), thresholdMB)
+	if health := a.GetBackendHealth(); health.State == HealthLoading {
+		msg += fmt.Sprintf(" while loading books (%.1f%%); consider loading fewer modes", health.LoadingPercent)
+	}
+	a.emitLog("backend", "Warning: "+msg)
+	wailsRuntime.EventsEmit(a.ctx, "memoryWarning", map[string]interface{}{
+		"memoryBytes": backend.MemoryBytes,
+		"thresholdMB": thresholdMB,
+		"message":     msg,
+	})
+}
+
+// sample sums CPU and RSS over pid and all its descendants
+func (mon *resourceMonitor) sample(pid int32, seen map[int32]bool) *ProcessResources {
+	res := &ProcessResources{PID: int(pid)}
+	queue := []int32{pid}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		proc, ok := mon.procs[p]
+		if !ok {
+			var err error
+			if proc, err = process.NewProcess(p); err != nil {
+				continue
+			}
+			mon.procs[p] = proc
+		}
+
+		if cpu, err := proc.Percent(0); err == nil {
+			res.CPUPercent += cpu
+		}
+		if mem, err := proc.MemoryInfo(); err == nil {
+			res.MemoryBytes += mem.RSS
+		}
+		res.Processes++
+
+		if children, err := proc.Children(); err == nil {
+			for _, c := range children {
+				queue = append(queue, c.Pid)
+			}
+		}
+	}
+	return res
+}
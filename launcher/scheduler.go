@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Scheduled task actions
+const (
+	ActionStartBackend = "start_backend"
+	ActionStopBackend  = "stop_backend"
+	ActionLoaderStart  = "loader_start"
+	ActionLoaderBoost  = "loader_boost"
+	ActionCrowdSim     = "crowdsim"
+	ActionCompliance   = "compliance"
+	ActionSimulate     = "simulate"
+)
+
+const (
+	maxScheduleRuns = 100
+	// scheduledRequestTimeout bounds API calls; crowdsim runs can take a while
+	scheduledRequestTimeout = 2 * time.Hour
+)
+
+// ScheduledTask runs an action whenever its cron expression matches
+type ScheduledTask struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Cron   string `json:"cron"` // "minute hour day-of-month month day-of-week"
+	Action string `json:"action"`
+	Mode   string `json:"mode,omitempty"` // game mode for crowdsim/compliance/simulate
+	// Body is the JSON request body for crowdsim and simulate
+	Body    json.RawMessage `json:"body,omitempty"`
+	Enabled bool            `json:"enabled"`
+}
+
+// TaskRun records one execution of a scheduled task
+type TaskRun struct {
+	TaskID     string `json:"taskId"`
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+	Manual     bool   `json:"manual"`
+	StartedAt  string `json:"startedAt"`
+	FinishedAt string `json:"finishedAt"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	ResultFile string `json:"resultFile,omitempty"` // saved API response
+}
+
+// scheduler holds runtime state; task definitions live in Config.Schedules
+type scheduler struct {
+	mu      sync.Mutex
+	running map[string]bool
+	runs    []TaskRun
+}
+
+// startScheduler checks schedules at the top of every minute until the app context is done
+func (a *App) startScheduler() {
+	a.sched = &scheduler{running: make(map[string]bool)}
+	go func() {
+		for {
+			now := time.Now()
+			next := now.Truncate(time.Minute).Add(time.Minute)
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-time.After(next.Sub(now)):
+			}
+
+			a.mu.Lock()
+			tasks := append([]ScheduledTask(nil), a.config.Schedules...)
+			a.mu.Unlock()
+
+			for _, task := range tasks {
+				if !task.Enabled {
+					continue
+				}
+				spec, err := parseCron(task.Cron)
+				if err != nil || !spec.matches(next) {
+					continue
+				}
+				go a.runTask(task, false)
+			}
+		}
+	}()
+}
+
+// GetSchedules returns all scheduled tasks
+func (a *App) GetSchedules() []ScheduledTask {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ScheduledTask{}, a.config.Schedules...)
+}
+
+// SaveSchedule validates and adds or updates a scheduled task (matched by ID)
+func (a *App) SaveSchedule(task ScheduledTask) (ScheduledTask, error) {
+	task.Name = strings.TrimSpace(task.Name)
+	task.Cron = strings.TrimSpace(task.Cron)
+	if err := validateTask(task); err != nil {
+		return task, err
+	}
+
+	a.mu.Lock()
+	if task.ID == "" {
+		task.ID = strconv.FormatInt(time.Now().UnixNano(), 36)
+		a.config.Schedules = append(a.config.Schedules, task)
+	} else {
+		found := false
+		for i := range a.config.Schedules {
+			if a.config.Schedules[i].ID == task.ID {
+				a.config.Schedules[i] = task
+				found = true
+				break
+			}
+		}
+		if !found {
+			a.mu.Unlock()
+			return task, fmt.Errorf("unknown schedule %q", task.ID)
+		}
+	}
+	a.mu.Unlock()
+
+	return task, a.saveConfigToFile()
+}
+
+// DeleteSchedule removes a scheduled task
+func (a *App) DeleteSchedule(id string) error {
+	a.mu.Lock()
+	tasks := a.config.Schedules[:0]
+	for _, t := range a.config.Schedules {
+		if t.ID != id {
+			tasks = append(tasks, t)
+		}
+	}
+	a.config.Schedules = tasks
+	a.mu.Unlock()
+	return a.saveConfigToFile()
+}
+
+// RunScheduleNow runs a task immediately and returns its run record
+func (a *App) RunScheduleNow(id string) (TaskRun, error) {
+	for _, task := range a.GetSchedules() {
+		if task.ID == id {
+			return a.runTask(task, true), nil
+		}
+	}
+	return TaskRun{}, fmt.Errorf("unknown schedule %q", id)
+}
+
+// GetScheduleRuns returns recent task runs, newest first
+func (a *App) GetScheduleRuns() []TaskRun {
+	if a.sched == nil {
+		return []TaskRun{}
+	}
+	a.sched.mu.Lock()
+	defer a.sched.mu.Unlock()
+	runs := make([]TaskRun, len(a.sched.runs))
+	for i, r := range a.sched.runs {
+		runs[len(runs)-1-i] = r
+	}
+	return runs
+}
+
+// NextScheduleRuns returns the next n times a cron expression matches
+func (a *App) NextScheduleRuns(expr string, n int) ([]string, error) {
+	spec, err := parseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 || n > 20 {
+		n = 5
+	}
+	times := []string{}
+	t := time.Now().Truncate(time.Minute)
+	// A year of minutes bounds the search for expressions like "0 0 31 2 *"
+	for i := 0; i < 366*24*60 && len(times) < n; i++ {
+		t = t.Add(time.Minute)
+		if spec.matches(t) {
+			times = append(times, t.Format(time.RFC3339))
+		}
+	}
+	return times, nil
+}
+
+func validateTask(task ScheduledTask) error {
+	if task.Name == "" {
+		return fmt.Errorf("schedule name is required")
+	}
+	if _, err := parseCron(task.Cron); err != nil {
+		return err
+	}
+	switch task.Action {
+	case ActionStartBackend, ActionStopBackend, ActionLoaderStart, ActionLoaderBoost:
+	case ActionCrowdSim, ActionSimulate:
+		if task.Mode == "" {
+			return fmt.Errorf("%s needs a mode", task.Action)
+		}
+		if len(task.Body) > 0 && !json.Valid(task.Body) {
+			return fmt.Errorf("request body is not valid JSON")
+		}
+	case ActionCompliance:
+		// Mode is optional: empty checks all modes
+	default:
+		return fmt.Errorf("unknown action %q", task.Action)
+	}
+	return nil
+}
+
+// runTask executes a task, skipping it if the previous run is still going
+func (a *App) runTask(task ScheduledTask, manual bool) TaskRun {
+	run := TaskRun{
+		TaskID:    task.ID,
+		Name:      task.Name,
+		Action:    task.Action,
+		Manual:    manual,
+		StartedAt: time.Now().Format(time.RFC3339),
+	}
+
+	a.sched.mu.Lock()
+	if a.sched.running[task.ID] {
+		a.sched.mu.Unlock()
+		run.FinishedAt = run.StartedAt
+		run.Error = "previous run still in progress"
+		return run
+	}
+	a.sched.running[task.ID] = true
+	a.sched.mu.Unlock()
+
+	a.emitLog("backend", fmt.Sprintf("Scheduled task %q started (%s)", task.Name, task.Action))
+	result, err := a.executeTask(task)
+	if err == nil && result != nil {
+		run.ResultFile, err = a.saveTaskResult(task, result)
+	}
+
+	run.FinishedAt = time.Now().Format(time.RFC3339)
+	run.Success = err == nil
+	if err != nil {
+		run.Error = err.Error()
+		a.emitLog("backend", fmt.Sprintf("Scheduled task %q failed: %v", task.Name, err))
+	} else {
+		a.emitLog("backend", fmt.Sprintf("Scheduled task %q finished", task.Name))
+	}
+
+	a.sched.mu.Lock()
+	delete(a.sched.running, task.ID)
+	a.sched.runs = append(a.sched.runs, run)
+	if len(a.sched.runs) > maxScheduleRuns {
+		a.sched.runs = a.sched.runs[len(a.sched.runs)-maxScheduleRuns:]
+	}
+	a.sched.mu.Unlock()
+
+	wailsRuntime.EventsEmit(a.ctx, "scheduleRun", run)
+	return run
+}
+
+// executeTask performs the action, returning the API response body to store (if any)
+func (a *App) executeTask(task ScheduledTask) ([]byte, error) {
+	switch task.Action {
+	case ActionStartBackend:
+		if a.backendAvailable() {
+			return nil, nil
+		}
+		return nil, a.StartBackend()
+	case ActionStopBackend:
+		return nil, a.StopBackend()
+	}
+
+	if !a.backendAvailable() {
+		return nil, fmt.Errorf("backend is not running")
+	}
+
+	mode := neturl.PathEscape(task.Mode)
+	switch task.Action {
+	case ActionLoaderStart:
+		return a.callBackend(http.MethodPost, "/api/loader/start", nil)
+	case ActionLoaderBoost:
+		return a.callBackend(http.MethodPost, "/api/loader/boost", nil)
+	case ActionCrowdSim:
+		return a.callBackend(http.MethodPost, "/api/crowdsim/"+mode+"/simulate", task.Body)
+	case ActionSimulate:
+		return a.callBackend(http.MethodPost, "/api/mode/"+mode+"/simulate", task.Body)
+	case ActionCompliance:
+		if task.Mode == "" {
+			return a.callBackend(http.MethodGet, "/api/compliance", nil)
+		}
+		return a.callBackend(http.MethodGet, "/api/mode/"+mode+"/compliance", nil)
+	}
+	return nil, fmt.Errorf("unknown action %q", task.Action)
+}
+
+// callBackend performs a request against the backend API and returns the body
+func (a *App) callBackend(method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, a.backendURL()+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: scheduledRequestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return data, fmt.Errorf("%s %s: status %d", method, path, resp.StatusCode)
+	}
+	return data, nil
+}
+
+// saveTaskResult writes an API response to the results folder
+func (a *App) saveTaskResult(task ScheduledTask, data []byte) (string, error) {
+	dir, err := a.getResultsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s-%s.json", time.Now().Format("20060102-150405"), task.Action, task.ID)
+	path := filepath.Join(dir, name)
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// getResultsDir returns the folder scheduled task results are stored in
+func (a *App) getResultsDir() (string, error) {
+	baseDir, err := a.getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, "results"), nil
+}
+
+// cronField is the set of allowed values for one cron field
+type cronField struct {
+	any    bool
+	values map[int]bool
+}
+
+// cronSpec is a parsed 5-field cron expression
+type cronSpec struct {
+	minute, hour, dom, month, dow cronField
+}
+
+// parseCron parses "minute hour day-of-month month day-of-week". Each field
+// supports *, numbers, lists (1,5), ranges (1-5) and steps (*/15, 0-30/5).
+// Day of week is 0-6 with 0 = Sunday (7 is accepted as Sunday too).
+func parseCron(expr string) (*cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression needs 5 fields (minute hour day month weekday), got %d", len(fields))
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	names := [5]string{"minute", "hour", "day of month", "month", "day of week"}
+
+	var parsed [5]cronField
+	for i, f := range fields {
+		field, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", names[i], err)
+		}
+		parsed[i] = field
+	}
+	if parsed[4].values[7] {
+		parsed[4].values[0] = true
+	}
+	return &cronSpec{parsed[0], parsed[1], parsed[2], parsed[3], parsed[4]}, nil
+}
+
+func parseCronField(s string, lo, hi int) (cronField, error) {
+	if s == "*" {
+		return cronField{any: true}, nil
+	}
+	field := cronField{values: make(map[int]bool)}
+	for _, part := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return field, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(a); err != nil {
+				return field, fmt.Errorf("invalid value %q", a)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(b); err != nil {
+					return field, fmt.Errorf("invalid value %q", b)
+				}
+			} else if hasStep {
+				end = hi // "5/10" means every 10 starting at 5
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return field, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			field.values[v] = true
+		}
+	}
+	return field, nil
+}
+
+func (f cronField) has(v int) bool {
+	return f.any || f.values[v]
+}
+
+// matches follows cron semantics: when both day fields are restricted, a
+// time matches if either one does
+func (c *cronSpec) matches(t time.Time) bool {
+	if !c.minute.has(t.Minute()) || !c.hour.has(t.Hour()) || !c.month.has(int(t.Month())) {
+		return false
+	}
+	domOK := c.dom.has(t.Day())
+	dowOK := c.dow.has(int(t.Weekday()))
+	if !c.dom.any && !c.dow.any {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SelfTestTimeout bounds GET /api/selftest; sampling and reading books of a
+// large library takes a while
+const SelfTestTimeout = 2 * time.Minute
+
+// SelfTestCheck mirrors a check of the backend's self-test report
+type SelfTestCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message"`
+}
+
+// SelfTestMode mirrors the checks of one mode
+type SelfTestMode struct {
+	Mode   string          `json:"mode"`
+	Passed bool            `json:"passed"`
+	Checks []SelfTestCheck `json:"checks"`
+}
+
+// SelfTestReport mirrors the backend's GET /api/selftest report
+type SelfTestReport struct {
+	Library    string         `json:"library"`
+	Passed     bool           `json:"passed"`
+	Modes      []SelfTestMode `json:"modes"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+// RunSelfTest asks the running backend to check the tables, sampler and
+// events offsets of every mode of its library
+func (a *App) RunSelfTest() (SelfTestReport, error) {
+	client := &http.Client{Timeout: SelfTestTimeout}
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    SelfTestReport `json:"data"`
+		Error   string         `json:"error"`
+	}
+	if err := getJSON(client, a.backendURL()+"/api/selftest", &resp); err != nil {
+		return SelfTestReport{}, fmt.Errorf("self-test request failed: %w", err)
+	}
+	if !resp.Success {
+		return SelfTestReport{}, fmt.Errorf("self-test failed to run: %s", resp.Error)
+	}
+	return resp.Data, nil
+}
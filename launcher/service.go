@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// ServiceName identifies the backend service on every OS
+const ServiceName = "mtools-backend"
+
+// ServiceStatus describes the installed background backend service
+type ServiceStatus struct {
+	Supported   bool   `json:"supported"` // production builds only
+	Installed   bool   `json:"installed"`
+	Running     bool   `json:"running"` // /api/health answered on Port
+	Method      string `json:"method"`  // launchd, systemd or task scheduler
+	Port        string `json:"port"`
+	LibraryPath string `json:"libraryPath,omitempty"`
+	LogFile     string `json:"logFile,omitempty"`
+}
+
+// serviceSpec is what the OS-specific installers register
+type serviceSpec struct {
+	Binary  string
+	Args    []string
+	Env     []BackendOption // enabled entries only
+	WorkDir string
+	LogFile string
+}
+
+// serviceDir holds the service's own copy of the backend, its certificate and logs.
+// The extracted backend is removed when the launcher exits, so it can't be used.
+func (a *App) serviceDir() string {
+	return filepath.Join(a.dataDir, "service")
+}
+
+// servicePort returns the port the service listens on
+func (a *App) servicePort() string {
+	if p := backendFlagValue(a.GetConfig().BackendFlags, "port"); p != "" {
+		return p
+	}
+	return DefaultBackendPort
+}
+
+// InstallBackendService installs the backend as a background service that
+// starts at login/boot and logs to a file, so the LUT explorer stays available
+// without the launcher open. Uses the current library, flags and env vars.
+func (a *App) InstallBackendService() (ServiceStatus, error) {
+	if !isProduction || a.dataDir == "" {
+		return a.GetServiceStatus(), fmt.Errorf("the backend service can only be installed from a production build")
+	}
+
+	cfg := a.GetConfig()
+	report := validateLibrary(cfg.LibraryPath)
+	if !report.Valid {
+		return a.GetServiceStatus(), fmt.Errorf("library is invalid: %s", report.Errors[0])
+	}
+
+	dir := a.serviceDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return a.GetServiceStatus(), err
+	}
+
+	// Replace a previous install cleanly before overwriting its binary
+	if serviceInstalled() {
+		uninstallService()
+	}
+
+	binary := filepath.Join(dir, ServiceName)
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	if err := copyFile(a.activeBackendBinary(), binary, 0755); err != nil {
+		return a.GetServiceStatus(), fmt.Errorf("failed to copy backend binary: %w", err)
+	}
+
+	httpsPort := DefaultHTTPSPort
+	if p := backendFlagValue(cfg.BackendFlags, "https-port"); p != "" {
+		httpsPort = p
+	}
+	logFile := filepath.Join(dir, "backend.log")
+	args := []string{"-library", cfg.LibraryPath, "-port", a.servicePort(), "-https-port", httpsPort, "-log-file", logFile}
+	if cfg.AutoLoadBooks {
+		args = append(args, "-autoload-books")
+	}
+	args = append(args, backendFlagArgs(cfg.BackendFlags)...)
+
+	var env []BackendOption
+	for _, o := range cfg.BackendEnv {
+		if o.Enabled {
+			env = append(env, o)
+		}
+	}
+
+	spec := serviceSpec{Binary: binary, Args: args, Env: env, WorkDir: dir, LogFile: logFile}
+	if err := installService(spec); err != nil {
+		return a.GetServiceStatus(), fmt.Errorf("failed to install service: %w", err)
+	}
+
+	a.emitLog("backend", fmt.Sprintf("Backend service installed (%s), listening on port %s", serviceMethod, a.servicePort()))
+	return a.GetServiceStatus(), nil
+}
+
+// UninstallBackendService stops and removes the backend service
+func (a *App) UninstallBackendService() error {
+	if err := uninstallService(); err != nil {
+		return fmt.Errorf("failed to uninstall service: %w", err)
+	}
+	if a.dataDir != "" {
+		os.RemoveAll(a.serviceDir())
+	}
+	a.emitLog("backend", "Backend service uninstalled")
+	return nil
+}
+
+// GetServiceStatus reports whether the service is installed and answering
+func (a *App) GetServiceStatus() ServiceStatus {
+	status := ServiceStatus{
+		Supported: isProduction,
+		Installed: serviceInstalled(),
+		Method:    serviceMethod,
+		Port:      a.servicePort(),
+	}
+	if !status.Installed {
+		return status
+	}
+	status.LibraryPath = a.GetConfig().LibraryPath
+	if a.dataDir != "" {
+		status.LogFile = filepath.Join(a.serviceDir(), "backend.log")
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	if resp, err := client.Get("http://localhost:" + status.Port + "/api/health"); err == nil {
+		resp.Body.Close()
+		status.Running = resp.StatusCode == http.StatusOK
+	}
+	return status
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const serviceMethod = "launchd"
+
+const serviceLabel = "com.mnemoo." + ServiceName
+
+func servicePlistPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "LaunchAgents", serviceLabel+".plist")
+}
+
+func installService(spec serviceSpec) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + serviceLabel + `</string>
+	<key>ProgramArguments</key>
+	<array>
+`)
+	for _, arg := range append([]string{spec.Binary}, spec.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", html.EscapeString(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if len(spec.Env) > 0 {
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, o := range spec.Env {
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", html.EscapeString(o.Key), html.EscapeString(o.Value))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	fmt.Fprintf(&b, `	<key>WorkingDirectory</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+</dict>
+</plist>
+`, html.EscapeString(spec.WorkDir), html.EscapeString(filepath.Join(spec.WorkDir, "stderr.log")))
+
+	path := servicePlistPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	if out, err := exec.Command("launchctl", "load", "-w", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl load: %v: %s", err, out)
+	}
+	return nil
+}
+
+func uninstallService() error {
+	path := servicePlistPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	exec.Command("launchctl", "unload", "-w", path).Run()
+	return os.Remove(path)
+}
+
+func serviceInstalled() bool {
+	_, err := os.Stat(servicePlistPath())
+	return err == nil
+}
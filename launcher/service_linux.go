@@ -0,0 +1,89 @@
+//go:build !windows && !darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const serviceMethod = "systemd"
+
+// serviceUnitPath returns the systemd user unit path
+func serviceUnitPath() string {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, _ := os.UserHomeDir()
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "systemd", "user", ServiceName+".service")
+}
+
+// systemdQuote quotes a word for ExecStart/Environment lines
+func systemdQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(s)
+	return `"` + s + `"`
+}
+
+func installService(spec serviceSpec) error {
+	words := []string{systemdQuote(spec.Binary)}
+	for _, arg := range spec.Args {
+		words = append(words, systemdQuote(arg))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `[Unit]
+Description=Mnemoo Tools LUT Explorer backend
+After=network.target
+
+[Service]
+ExecStart=%s
+WorkingDirectory=%s
+Restart=on-failure
+RestartSec=5
+`, strings.Join(words, " "), systemdQuote(spec.WorkDir))
+	for _, o := range spec.Env {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(o.Key+"="+o.Value))
+	}
+	b.WriteString("\n[Install]\nWantedBy=default.target\n")
+
+	path := serviceUnitPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+
+	if out, err := exec.Command("systemctl", "--user", "daemon-reload").CombinedOutput(); err != nil {
+		return fmt.Errorf("systemctl daemon-reload: %v: %s", err, out)
+	}
+	if out, err := exec.Command("systemctl", "--user", "enable", "--now", ServiceName+".service").CombinedOutput(); err != nil {
+		return fmt.Errorf("systemctl enable: %v: %s", err, out)
+	}
+	// User services stop at logout unless lingering is enabled; best effort
+	// since it may need a polkit prompt
+	exec.Command("loginctl", "enable-linger").Run()
+	return nil
+}
+
+func uninstallService() error {
+	path := serviceUnitPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	exec.Command("systemctl", "--user", "disable", "--now", ServiceName+".service").Run()
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	exec.Command("systemctl", "--user", "daemon-reload").Run()
+	return nil
+}
+
+func serviceInstalled() bool {
+	_, err := os.Stat(serviceUnitPath())
+	return err == nil
+}
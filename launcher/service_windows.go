@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// The backend doesn't speak the service control protocol, so on Windows it
+// runs as a Task Scheduler task started at logon instead of an SCM service.
+const serviceMethod = "task scheduler"
+
+const serviceTaskName = "MTools Backend"
+
+// serviceScript returns the launcher script the task runs; it sets the
+// environment variables, which scheduled tasks can't carry themselves
+func serviceScript(spec serviceSpec) string {
+	return filepath.Join(spec.WorkDir, "run-backend.cmd")
+}
+
+func cmdQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func installService(spec serviceSpec) error {
+	var b strings.Builder
+	b.WriteString("@echo off\r\n")
+	fmt.Fprintf(&b, "cd /d %s\r\n", cmdQuote(spec.WorkDir))
+	for _, o := range spec.Env {
+		fmt.Fprintf(&b, "set %s\r\n", cmdQuote(o.Key+"="+o.Value))
+	}
+	words := []string{cmdQuote(spec.Binary)}
+	for _, arg := range spec.Args {
+		words = append(words, cmdQuote(arg))
+	}
+	fmt.Fprintf(&b, "%s >> %s 2>&1\r\n", strings.Join(words, " "), cmdQuote(filepath.Join(spec.WorkDir, "stderr.log")))
+
+	script := serviceScript(spec)
+	if err := os.WriteFile(script, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+
+	if out, err := schtasks("/Create", "/F", "/SC", "ONLOGON", "/RL", "LIMITED", "/TN", serviceTaskName, "/TR", cmdQuote(script)); err != nil {
+		return fmt.Errorf("schtasks create: %v: %s", err, out)
+	}
+	if out, err := schtasks("/Run", "/TN", serviceTaskName); err != nil {
+		return fmt.Errorf("schtasks run: %v: %s", err, out)
+	}
+	return nil
+}
+
+func uninstallService() error {
+	if !serviceInstalled() {
+		return nil
+	}
+	schtasks("/End", "/TN", serviceTaskName)
+	if out, err := schtasks("/Delete", "/F", "/TN", serviceTaskName); err != nil {
+		return fmt.Errorf("schtasks delete: %v: %s", err, out)
+	}
+	return nil
+}
+
+func serviceInstalled() bool {
+	_, err := schtasks("/Query", "/TN", serviceTaskName)
+	return err == nil
+}
+
+func schtasks(args ...string) ([]byte, error) {
+	cmd := exec.Command("schtasks", args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Certificate files written by the backend into its working directory
+const (
+	backendCertFile = "lutexplorer.crt"
+	backendKeyFile  = "lutexplorer.key"
+)
+
+// CertExpiryWarning is how close to expiry a certificate is flagged
+const CertExpiryWarning = 14 * 24 * time.Hour
+
+// TLSInfo describes the backend's HTTPS listener and certificate
+type TLSInfo struct {
+	HTTPSPort   string `json:"httpsPort"`
+	Enabled     bool   `json:"enabled"`   // HTTPS port is not 0
+	Listening   bool   `json:"listening"` // TLS handshake on the port succeeded
+	CertPath    string `json:"certPath"`
+	KeyPath     string `json:"keyPath"`
+	CertExists  bool   `json:"certExists"`
+	Subject     string `json:"subject,omitempty"`
+	NotBefore   string `json:"notBefore,omitempty"`
+	NotAfter    string `json:"notAfter,omitempty"`
+	Expired     bool   `json:"expired"`
+	ExpiresSoon bool   `json:"expiresSoon"`
+	Fingerprint string `json:"fingerprint,omitempty"` // SHA-256 of the DER certificate
+	Trusted     bool   `json:"trusted"`               // verifies against the system trust store
+	Error       string `json:"error,omitempty"`
+}
+
+// certDir returns the directory the backend writes its certificate to
+// (its working directory)
+func (a *App) certDir() string {
+	if isProduction {
+		return a.dataDir
+	}
+	return filepath.Join(a.projectRoot, "backend")
+}
+
+// GetTLSInfo reports the HTTPS port, certificate details and whether the
+// certificate is trusted, so HTTPS failures in game clients can be diagnosed
+func (a *App) GetTLSInfo() TLSInfo {
+	a.mu.Lock()
+	port := a.httpsPort
+	a.mu.Unlock()
+	if port == "" {
+		port = DefaultHTTPSPort
+		if p := backendFlagValue(a.GetConfig().BackendFlags, "https-port"); p != "" {
+			port = p
+		}
+	}
+
+	dir := a.certDir()
+	info := TLSInfo{
+		HTTPSPort: port,
+		Enabled:   port != "0",
+		CertPath:  filepath.Join(dir, backendCertFile),
+		KeyPath:   filepath.Join(dir, backendKeyFile),
+	}
+
+	if info.Enabled {
+		info.Listening = probeTLS(port)
+	}
+
+	cert, err := readCertificate(info.CertPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			info.Error = err.Error()
+		}
+		return info
+	}
+	info.CertExists = true
+	info.Subject = cert.Subject.String()
+	info.NotBefore = cert.NotBefore.Format(time.RFC3339)
+	info.NotAfter = cert.NotAfter.Format(time.RFC3339)
+	info.Expired = time.Now().After(cert.NotAfter)
+	info.ExpiresSoon = !info.Expired && time.Until(cert.NotAfter) < CertExpiryWarning
+	sum := sha256.Sum256(cert.Raw)
+	info.Fingerprint = hex.EncodeToString(sum[:])
+
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "localhost"}); err == nil {
+		info.Trusted = true
+	}
+	return info
+}
+
+// RegenerateCertificate deletes the backend certificate and key. The backend
+// generates a fresh pair on start, so it is restarted if running.
+func (a *App) RegenerateCertificate() (TLSInfo, error) {
+	dir := a.certDir()
+	for _, name := range []string{backendCertFile, backendKeyFile} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return a.GetTLSInfo(), fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	a.emitLog("backend", "TLS certificate removed, a new one is generated on next start")
+
+	a.mu.Lock()
+	running := a.backendCmd != nil && a.backendCmd.Process != nil
+	a.mu.Unlock()
+	if running {
+		a.StopBackend()
+		if err := a.StartBackend(); err != nil {
+			return a.GetTLSInfo(), err
+		}
+	}
+	return a.GetTLSInfo(), nil
+}
+
+// TrustCertificate adds the backend certificate to the current user's trust
+// store. Linux has no per-user store shared by browsers, so the command to
+// run is returned in the error instead.
+func (a *App) TrustCertificate() error {
+	certPath := filepath.Join(a.certDir(), backendCertFile)
+	if _, err := readCertificate(certPath); err != nil {
+		return fmt.Errorf("no certificate to trust yet, start the backend with HTTPS enabled first: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		keychain := filepath.Join(home, "Library", "Keychains", "login.keychain-db")
+		cmd = exec.Command("security", "add-trusted-cert", "-r", "trustRoot", "-p", "ssl", "-k", keychain, certPath)
+	case "windows":
+		cmd = exec.Command("certutil", "-user", "-f", "-addstore", "Root", certPath)
+	default:
+		return fmt.Errorf("automatic trust is not supported on %s; run: sudo cp %q /usr/local/share/ca-certificates/lutexplorer.crt && sudo update-ca-certificates", runtime.GOOS, certPath)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to trust certificate: %v: %s", err, out)
+	}
+	a.emitLog("backend", "TLS certificate added to the user trust store")
+	return nil
+}
+
+// RevealCertificate opens the folder containing the backend certificate
+func (a *App) RevealCertificate() error {
+	dir := a.certDir()
+	if runtime.GOOS == "windows" {
+		return exec.Command("explorer", dir).Start()
+	}
+	return openURL(dir)
+}
+
+func readCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s is not a PEM certificate", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// probeTLS reports whether a TLS handshake succeeds on localhost:port
+func probeTLS(port string) bool {
+	dialer := &net.Dialer{Timeout: time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort("localhost", port), &tls.Config{
+		InsecureSkipVerify: true, // only checking that HTTPS is served
+	})
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
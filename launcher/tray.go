@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"runtime"
+
+	"fyne.io/systray"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// trayMenu holds the tray menu items that change with process status
+type trayMenu struct {
+	status *systray.MenuItem
+	start  *systray.MenuItem
+	stop   *systray.MenuItem
+}
+
+// startTray creates the system tray icon and menu.
+// Wails v2 has no tray support, so systray runs alongside the Wails event loop.
+func (a *App) startTray() {
+	start, _ := systray.RunWithExternalLoop(a.onTrayReady, nil)
+	start()
+}
+
+// stopTray removes the tray icon
+func (a *App) stopTray() {
+	a.mu.Lock()
+	running := a.tray != nil
+	a.tray = nil
+	a.mu.Unlock()
+	if running {
+		systray.Quit()
+	}
+}
+
+func (a *App) onTrayReady() {
+	systray.SetIcon(trayIcon(appIcon))
+	systray.SetTooltip("Mnemoo Tools")
+
+	open := systray.AddMenuItem("Show Launcher", "Show the launcher window")
+	systray.AddSeparator()
+	status := systray.AddMenuItem("Stopped", "Service status")
+	status.Disable()
+	start := systray.AddMenuItem("Start", "Start backend and frontend")
+	stop := systray.AddMenuItem("Stop", "Stop backend and frontend")
+	browser := systray.AddMenuItem("Open in Browser", "Open Mnemoo Tools in the default browser")
+	systray.AddSeparator()
+	quit := systray.AddMenuItem("Quit", "Stop services and quit")
+
+	a.mu.Lock()
+	a.tray = &trayMenu{status: status, start: start, stop: stop}
+	a.mu.Unlock()
+	a.updateTray(a.GetStatus())
+
+	go func() {
+		for {
+			select {
+			case <-open.ClickedCh:
+				a.ShowWindow()
+			case <-start.ClickedCh:
+				go func() {
+					if err := a.StartAll(); err != nil {
+						a.emitLog("backend", fmt.Sprintf("Start from tray failed: %v", err))
+					}
+					a.emitStatus()
+				}()
+			case <-stop.ClickedCh:
+				a.StopAll()
+				a.emitStatus()
+			case <-browser.ClickedCh:
+				a.OpenMTools()
+			case <-quit.ClickedCh:
+				a.mu.Lock()
+				a.quitting = true
+				a.mu.Unlock()
+				wailsRuntime.Quit(a.ctx)
+				return
+			}
+		}
+	}()
+}
+
+// updateTray reflects process status in the tray menu
+func (a *App) updateTray(status Status) {
+	a.mu.Lock()
+	tray := a.tray
+	a.mu.Unlock()
+	if tray == nil {
+		return
+	}
+
+	running := status.Backend == StatusRunning || status.Frontend == StatusRunning
+	title := fmt.Sprintf("Backend: %s · Frontend: %s", status.Backend, status.Frontend)
+	tray.status.SetTitle(title)
+	systray.SetTooltip("Mnemoo Tools — " + title)
+
+	if running {
+		tray.start.Disable()
+		tray.stop.Enable()
+	} else {
+		tray.start.Enable()
+		tray.stop.Disable()
+	}
+}
+
+// emitStatus notifies the UI and the tray of a status change
+func (a *App) emitStatus() {
+	status := a.GetStatus()
+	wailsRuntime.EventsEmit(a.ctx, "statusChange", status)
+	a.updateTray(status)
+}
+
+// ShowWindow restores the launcher window from the tray
+func (a *App) ShowWindow() {
+	wailsRuntime.WindowShow(a.ctx)
+	wailsRuntime.WindowUnminimise(a.ctx)
+}
+
+// beforeClose hides the window instead of quitting when minimize-to-tray is on.
+// Returning true prevents the window from closing.
+func (a *App) beforeClose(ctx context.Context) bool {
+	a.mu.Lock()
+	hide := a.config.MinimizeToTray && a.tray != nil && !a.quitting
+	a.mu.Unlock()
+
+	if hide {
+		wailsRuntime.WindowHide(ctx)
+		a.emitLog("backend", "Launcher minimized to tray (services keep running)")
+	}
+	return hide
+}
+
+// trayIcon converts the PNG app icon into the format the platform tray expects.
+// Windows needs an ICO container; PNG-compressed ICO entries are supported since Vista.
+func trayIcon(png []byte) []byte {
+	if runtime.GOOS != "windows" || len(png) < 24 {
+		return png
+	}
+
+	// Width and height from the PNG IHDR chunk; 0 means 256 in an ICO entry
+	width := binary.BigEndian.Uint32(png[16:20])
+	height := binary.BigEndian.Uint32(png[20:24])
+	dim := func(v uint32) byte {
+		if v >= 256 {
+			return 0
+		}
+		return byte(v)
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, []uint16{0, 1, 1}) // reserved, type=icon, count
+	buf.Write([]byte{dim(width), dim(height), 0, 0})           // width, height, colors, reserved
+	binary.Write(&buf, binary.LittleEndian, []uint16{1, 32})   // planes, bits per pixel
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(png)), 6 + 16})
+	buf.Write(png)
+	return buf.Bytes()
+}
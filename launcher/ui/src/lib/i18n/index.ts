@@ -46,9 +46,15 @@ export function initI18n(initialLocale: SupportedLocale = 'en') {
     locale.set(initialLocale);
 }
 
-export function setLocale(newLocale: SupportedLocale) {
+// Codes may also be drop-in languages served by the launcher's catalog
+export function setLocale(newLocale: string) {
     locale.set(newLocale);
 }
 
+// Register a catalog loaded at runtime through GetTranslations
+export function registerLocale(code: string, messages: Record<string, any>) {
+    addMessages(code, messages);
+}
+
 export { _, locale };
 export const isLoading = derived(locale, ($locale) => !$locale);
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Version is the launcher release version, set at build time with
+// -ldflags "-X main.Version=v1.2.3". Development builds report "dev".
+var Version = "dev"
+
+const (
+	// ReleasesURL returns the latest GitHub release in the API format
+	ReleasesURL = "https://api.github.com/repos/mnemoo/tools/releases/latest"
+	// ChecksumsAsset lists "<sha256>  <file>" for every release asset
+	ChecksumsAsset = "SHA256SUMS"
+
+	updateCheckTimeout    = 15 * time.Second
+	updateDownloadTimeout = 10 * time.Minute
+	backendUpdateManifest = "backend-update.json"
+)
+
+// UpdateInfo describes the latest available release
+type UpdateInfo struct {
+	CurrentVersion  string `json:"currentVersion"`
+	LatestVersion   string `json:"latestVersion"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	Changelog       string `json:"changelog"`
+	ReleaseURL      string `json:"releaseUrl"`
+	PublishedAt     string `json:"publishedAt"`
+	LauncherAsset   string `json:"launcherAsset,omitempty"` // download URL for this platform
+	BackendAsset    string `json:"backendAsset,omitempty"`  // download URL for this platform
+	// CanUpdateBackend is true when the bundled backend can be swapped in place
+	CanUpdateBackend bool `json:"canUpdateBackend"`
+	// BackendVersion is the version of an installed backend update, if any
+	BackendVersion string `json:"backendVersion,omitempty"`
+}
+
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	Assets      []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// backendUpdate is persisted next to a downloaded backend binary
+type backendUpdate struct {
+	Version         string `json:"version"`
+	SHA256          string `json:"sha256"`
+	File            string `json:"file"`
+	LauncherVersion string `json:"launcherVersion"` // launcher that installed it
+}
+
+// CheckForUpdates queries the releases endpoint for a newer version
+func (a *App) CheckForUpdates() (UpdateInfo, error) {
+	info := UpdateInfo{CurrentVersion: Version}
+
+	a.mu.Lock()
+	if a.backendUpdate != nil {
+		info.BackendVersion = a.backendUpdate.Version
+	}
+	a.mu.Unlock()
+
+	rel, err := fetchLatestRelease()
+	if err != nil {
+		return info, err
+	}
+
+	info.LatestVersion = rel.TagName
+	info.Changelog = rel.Body
+	info.ReleaseURL = rel.HTMLURL
+	info.PublishedAt = rel.PublishedAt
+
+	installed := Version
+	if info.BackendVersion != "" && compareVersions(info.BackendVersion, installed) > 0 {
+		installed = info.BackendVersion
+	}
+	info.UpdateAvailable = compareVersions(rel.TagName, Version) > 0
+
+	launcherName, backendName := releaseAssetNames()
+	for _, asset := range rel.Assets {
+		switch asset.Name {
+		case launcherName:
+			info.LauncherAsset = asset.URL
+		case backendName:
+			info.BackendAsset = asset.URL
+		}
+	}
+	info.CanUpdateBackend = isProduction && info.BackendAsset != "" &&
+		compareVersions(rel.TagName, installed) > 0
+
+	return info, nil
+}
+
+// InstallBackendUpdate downloads the latest backend for this platform,
+// verifies it against the release checksums and swaps it in. The backend
+// is restarted if it was running.
+func (a *App) InstallBackendUpdate() error {
+	if !isProduction {
+		return fmt.Errorf("backend updates are only available in production builds")
+	}
+	if a.dataDir == "" {
+		return fmt.Errorf("app data directory is not available")
+	}
+
+	rel, err := fetchLatestRelease()
+	if err != nil {
+		return err
+	}
+
+	_, backendName := releaseAssetNames()
+	var binURL, sumsURL string
+	for _, asset := range rel.Assets {
+		switch asset.Name {
+		case backendName:
+			binURL = asset.URL
+		case ChecksumsAsset:
+			sumsURL = asset.URL
+		}
+	}
+	if binURL == "" {
+		return fmt.Errorf("release %s has no backend for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	if sumsURL == "" {
+		return fmt.Errorf("release %s has no %s, refusing unverified download", rel.TagName, ChecksumsAsset)
+	}
+
+	expected, err := fetchChecksum(sumsURL, backendName)
+	if err != nil {
+		return err
+	}
+
+	updatesDir := filepath.Join(a.dataDir, "updates")
+	if err := os.MkdirAll(updatesDir, 0755); err != nil {
+		return err
+	}
+
+	a.emitLog("backend", fmt.Sprintf("Downloading backend %s...", rel.TagName))
+	tmp, err := os.CreateTemp(updatesDir, "backend-*.download")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	sum, err := downloadTo(tmp, binURL, func(done, total int64) {
+		wailsRuntime.EventsEmit(a.ctx, "updateProgress", map[string]int64{"done": done, "total": total})
+	})
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	if !strings.EqualFold(sum, expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", backendName, expected, sum)
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	wasRunning := a.backendCmd != nil && a.backendCmd.Process != nil
+	a.mu.Unlock()
+	if wasRunning {
+		a.StopBackend()
+	}
+
+	dest := filepath.Join(updatesDir, filepath.Base(a.backendPath))
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return fmt.Errorf("failed to install backend: %w", err)
+	}
+
+	update := &backendUpdate{
+		Version:         rel.TagName,
+		SHA256:          sum,
+		File:            dest,
+		LauncherVersion: Version,
+	}
+	data, err := json.MarshalIndent(update, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(updatesDir, backendUpdateManifest), data, 0644); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.backendUpdate = update
+	a.mu.Unlock()
+	a.emitLog("backend", fmt.Sprintf("Backend updated to %s (sha256 %s)", rel.TagName, sum[:12]))
+
+	if wasRunning {
+		return a.StartBackend()
+	}
+	return nil
+}
+
+// RevertBackendUpdate removes an installed backend update so the bundled
+// backend is used again
+func (a *App) RevertBackendUpdate() error {
+	a.mu.Lock()
+	update := a.backendUpdate
+	a.backendUpdate = nil
+	a.mu.Unlock()
+	if update == nil || a.dataDir == "" {
+		return nil
+	}
+	os.Remove(update.File)
+	return os.Remove(filepath.Join(a.dataDir, "updates", backendUpdateManifest))
+}
+
+// loadBackendUpdate picks up a previously installed backend update. Updates
+// installed by an older launcher are discarded since the launcher bundles a
+// backend at least as new as the one it was released with.
+func (a *App) loadBackendUpdate() {
+	updatesDir := filepath.Join(a.dataDir, "updates")
+	manifest := filepath.Join(updatesDir, backendUpdateManifest)
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return
+	}
+
+	var update backendUpdate
+	if err := json.Unmarshal(data, &update); err != nil || update.LauncherVersion != Version {
+		os.RemoveAll(updatesDir)
+		return
+	}
+
+	// Re-verify so a truncated or tampered binary is never executed
+	f, err := os.Open(update.File)
+	if err != nil {
+		os.RemoveAll(updatesDir)
+		return
+	}
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	f.Close()
+	if err != nil || hex.EncodeToString(h.Sum(nil)) != update.SHA256 {
+		os.RemoveAll(updatesDir)
+		return
+	}
+
+	a.backendUpdate = &update
+}
+
+// activeBackendBinary returns the backend binary to run in production
+func (a *App) activeBackendBinary() string {
+	if a.backendUpdate != nil {
+		return a.backendUpdate.File
+	}
+	return a.backendPath
+}
+
+// releaseAssetNames returns the launcher and backend asset names for this platform
+func releaseAssetNames() (launcher, backend string) {
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	switch runtime.GOOS {
+	case "windows":
+		return "mtools-launcher-" + platform + ".exe", "mtools-backend-" + platform + ".exe"
+	case "darwin":
+		return "mtools-launcher-" + platform + ".app.zip", "mtools-backend-" + platform
+	default:
+		return "mtools-launcher-" + platform, "mtools-backend-" + platform
+	}
+}
+
+func fetchLatestRelease() (*githubRelease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ReleasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "mtools-launcher/"+Version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for updates: %s", resp.Status)
+	}
+
+	var rel githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("invalid release response: %w", err)
+	}
+	return &rel, nil
+}
+
+// fetchChecksum downloads a SHA256SUMS file and returns the hash for name
+func fetchChecksum(url, name string) (string, error) {
+	client := &http.Client{Timeout: updateCheckTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch checksums: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch checksums: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+// downloadTo streams url into w and returns the hex SHA-256 of the body
+func downloadTo(w io.Writer, url string, progress func(done, total int64)) (string, error) {
+	client := &http.Client{Timeout: updateDownloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s", resp.Status)
+	}
+
+	h := sha256.New()
+	var done int64
+	buf := make([]byte, 256*1024)
+	for {
+		n, rerr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return "", err
+			}
+			h.Write(buf[:n])
+			done += int64(n)
+			progress(done, resp.ContentLength)
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return "", rerr
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// compareVersions compares dotted versions like "v1.2.3", returning -1, 0 or 1.
+// "dev" sorts below every release so development builds always see updates.
+func compareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "dev" {
+		return -1
+	}
+	if b == "dev" {
+		return 1
+	}
+	pa := versionParts(a)
+	pb := versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i] // ignore pre-release and build metadata
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(p)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// GetVersion returns the launcher version
+func (a *App) GetVersion() string {
+	return Version
+}
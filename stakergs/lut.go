@@ -13,6 +13,15 @@ type ModeConfig struct {
 	Cost    float64 `json:"cost"`    // Cost per spin in base units
 	Events  string  `json:"events"`  // Path to events file (e.g., "books_base.jsonl.zst")
 	Weights string  `json:"weights"` // Path to LUT CSV file (e.g., "lookUpTable_base_0.csv")
+	// Triggers lists outcomes of this mode that start a feature mode (optional,
+	// not part of the Stake Engine format; used for composite simulation)
+	Triggers []FeatureTrigger `json:"triggers,omitempty"`
+}
+
+// FeatureTrigger maps outcomes of a mode to the feature mode they start.
+type FeatureTrigger struct {
+	Mode   string `json:"mode"`    // Feature mode played when triggered (e.g., "bonus")
+	SimIDs []int  `json:"sim_ids"` // Outcomes that trigger it
 }
 
 // LookupTable represents the complete set of game outcomes for a specific mode.